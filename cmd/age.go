@@ -0,0 +1,98 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// ageWindow selects issues by the time since they were created. Zero bounds
+// don't limit.
+type ageWindow struct {
+	// Min is the age issues must have reached
+	Min time.Duration
+	// Max is the age issues must not have exceeded
+	Max time.Duration
+}
+
+// ageWindowFlags reads the window from the --min-age and --max-age flags,
+// given as days (30d), weeks (2w) or a duration (72h).
+func ageWindowFlags(cmd *cobra.Command) (ageWindow, error) {
+	var window ageWindow
+	for _, flag := range []struct {
+		name string
+		into *time.Duration
+	}{{"min-age", &window.Min}, {"max-age", &window.Max}} {
+		value, err := cmd.Flags().GetString(flag.name)
+		if err != nil {
+			return ageWindow{}, err
+		}
+		if value == "" {
+			continue
+		}
+		if *flag.into, err = parseSince(value); err != nil {
+			return ageWindow{}, fmt.Errorf("invalid --%s: %v", flag.name, err)
+		}
+	}
+	if window.Min > 0 && window.Max > 0 && window.Min > window.Max {
+		return ageWindow{}, fmt.Errorf("--min-age must not be greater than --max-age")
+	}
+	return window, nil
+}
+
+// contains reports whether an issue created at created is inside the window
+// at now. Issues without a creation time are.
+func (w ageWindow) contains(created, now time.Time) bool {
+	if created.IsZero() {
+		return true
+	}
+	age := now.Sub(created)
+	if w.Min > 0 && age < w.Min {
+		return false
+	}
+	return w.Max <= 0 || age <= w.Max
+}
+
+// skips reports whether the sync leaves an issue out at now because it has no
+// JIRA ticket and was created outside the window.
+func (w ageWindow) skips(issue models.GitHubIssue, now time.Time) bool {
+	return marker.GitHub.Key(issue.Title) == "" && !w.contains(issue.CreatedAt, now)
+}
+
+// skipIssuesByAge leaves out the issues without a JIRA ticket that were
+// created outside the window, so they get none. Issues with a ticket are
+// kept, so their tickets and links are still maintained.
+func skipIssuesByAge(issuesByBoard map[string][]models.GitHubIssue, window ageWindow, now time.Time) map[string][]models.GitHubIssue {
+	if window == (ageWindow{}) {
+		return issuesByBoard
+	}
+
+	result := make(map[string][]models.GitHubIssue, len(issuesByBoard))
+	for board, issues := range issuesByBoard {
+		kept := make([]models.GitHubIssue, 0, len(issues))
+		for _, issue := range issues {
+			if window.skips(issue, now) {
+				logging.Debug("skipping github issue outside the age window",
+					"issue_number", issue.Number,
+					"board", board,
+					"created_at", issue.CreatedAt)
+				continue
+			}
+			kept = append(kept, issue)
+		}
+		if skipped := len(issues) - len(kept); skipped > 0 {
+			logging.Info("skipped issues outside the age window",
+				"board", board,
+				"skipped_count", skipped,
+				"min_age", window.Min,
+				"max_age", window.Max)
+		}
+		result[board] = kept
+	}
+	return result
+}
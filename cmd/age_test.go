@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgeWindowFlags(t *testing.T) {
+	parse := func(args ...string) (ageWindow, error) {
+		cmd := &cobra.Command{}
+		cmd.Flags().String("min-age", "", "")
+		cmd.Flags().String("max-age", "", "")
+		require.NoError(t, cmd.ParseFlags(args))
+		return ageWindowFlags(cmd)
+	}
+
+	window, err := parse()
+	require.NoError(t, err)
+	assert.Equal(t, ageWindow{}, window)
+
+	window, err = parse("--min-age", "2w", "--max-age", "365d")
+	require.NoError(t, err)
+	assert.Equal(t, ageWindow{Min: 14 * 24 * time.Hour, Max: 365 * 24 * time.Hour}, window)
+
+	_, err = parse("--max-age", "a year")
+	assert.ErrorContains(t, err, "invalid --max-age")
+
+	_, err = parse("--min-age", "30d", "--max-age", "7d")
+	assert.ErrorContains(t, err, "--min-age must not be greater than --max-age")
+}
+
+func TestSkipIssuesByAge(t *testing.T) {
+	now := time.Date(2024, 6, 1, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(days int) time.Time { return now.Add(-time.Duration(days) * 24 * time.Hour) }
+	issuesByBoard := map[string][]models.GitHubIssue{
+		"PROJ": {
+			{Number: 1, CreatedAt: daysAgo(2)},
+			{Number: 2, CreatedAt: daysAgo(40)},
+			{Number: 3, CreatedAt: daysAgo(800)},
+			{Number: 4, Title: "[PROJ-4] Synced long ago", CreatedAt: daysAgo(800)},
+			{Number: 5},
+		},
+	}
+
+	numbers := func(window ageWindow) []int {
+		var got []int
+		for _, issue := range skipIssuesByAge(issuesByBoard, window, now)["PROJ"] {
+			got = append(got, issue.Number)
+		}
+		return got
+	}
+
+	assert.Equal(t, []int{1, 2, 3, 4, 5}, numbers(ageWindow{}))
+	assert.Equal(t, []int{1, 2, 4, 5}, numbers(ageWindow{Max: 365 * 24 * time.Hour}))
+	assert.Equal(t, []int{2, 3, 4, 5}, numbers(ageWindow{Min: 30 * 24 * time.Hour}))
+	assert.Equal(t, []int{2, 4, 5}, numbers(ageWindow{Min: 30 * 24 * time.Hour, Max: 365 * 24 * time.Hour}))
+}
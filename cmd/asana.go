@@ -0,0 +1,258 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/asana"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/issueline"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// asanaCmd creates Asana tasks for labeled GitHub issues.
+var asanaCmd = &cobra.Command{
+	Use:   "asana",
+	Short: "Create Asana tasks for labeled GitHub issues",
+	Long: `Create a task in an Asana project for every open GitHub issue with the
+--label label that has none yet, and complete the tasks of issues that were
+closed.
+
+The first line of a task's notes links its issue, which is how the issue and
+the task find each other on later runs; GitHub issues aren't changed. Tasks
+are created in the section asana.sections in the config file maps the first
+of the issue's labels to, or in no section.
+
+The project is given by name or by the ID in its Asana address. The token is
+read from ASANA_TOKEN or asana.token in the config file.
+
+Example:
+  glue asana -r owner/repo --project "Platform Roadmap"
+  glue asana -r owner/repo --project 1204567890123456 --label roadmap --dry-run`,
+	PreRunE: validateFlags(flagRules{Repository: true, Required: []string{"project"}}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		projectName, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		label, err := cmd.Flags().GetString("label")
+		if err != nil {
+			return err
+		}
+
+		complete, err := cmd.Flags().GetBool("complete")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		noLock, err := cmd.Flags().GetBool("no-lock")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		asanaClient, err := asana.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize asana client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := lockRepository(repository, noLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := checkSafety(cmd, cfg.Safety, repository, nil); err != nil {
+			return err
+		}
+
+		project, err := asanaClient.FindProject(ctx, projectName)
+		if err != nil {
+			return err
+		}
+
+		sections, err := asanaClient.Sections(ctx, project.GID)
+		if err != nil {
+			return err
+		}
+
+		tasks, err := asanaClient.Tasks(ctx, project.GID)
+		if err != nil {
+			return err
+		}
+
+		open, err := githubClient.GetIssuesWithLabel(ctx, repository, label)
+		if err != nil {
+			return fmt.Errorf("failed to get issues with label %s: %v", label, err)
+		}
+
+		var closed []models.GitHubIssue
+		if complete {
+			closed, err = githubClient.GetClosedIssuesWithLabels(ctx, repository, []string{label})
+			if err != nil {
+				return fmt.Errorf("failed to get closed issues with label %s: %v", label, err)
+			}
+		}
+
+		tracker := &asanaTracker{
+			client:     asanaClient,
+			cfg:        cfg.Asana,
+			domain:     cfg.GitHub.Domain,
+			repository: repository,
+			project:    project,
+			sections:   sections,
+			byIssue:    tasksByIssue(tasks),
+		}
+		out := cmd.OutOrStdout()
+		counts := syncTracker(ctx, tracker, open, closed, trackerSync{out: out, dryRun: dryRun})
+
+		fmt.Fprintf(out, "\n%d open issues labeled %s, %d tasks created, %d completed in %s\n",
+			len(open), label, counts.Created, counts.Closed, project.Name)
+		if counts.Failed > 0 {
+			return fmt.Errorf("failed to update tasks of %d issues", counts.Failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(asanaCmd)
+	asanaCmd.Flags().String("project", "", "Name or ID of the Asana project to create the tasks in")
+	asanaCmd.Flags().StringP("label", "l", "asana", "Label of the GitHub issues that get a task")
+	asanaCmd.Flags().Bool("complete", true, "Complete the tasks of closed issues")
+	asanaCmd.Flags().Bool("dry-run", false, "Print the tasks that would be created or completed without changing them")
+	asanaCmd.Flags().Bool("no-lock", false, "Skip the per-repository lock that prevents concurrent runs")
+	asanaCmd.Flags().Bool("force", false, "Run even if the safety config doesn't permit changing the repository")
+}
+
+// asanaTracker is the Tracker of the tasks in an Asana project. Completing a
+// task is its Close.
+type asanaTracker struct {
+	client     *asana.Client
+	cfg        config.AsanaConfig
+	domain     string
+	repository string
+	project    asana.Project
+	sections   []asana.Section
+	// byIssue are the project's tasks by the lower-cased address of their
+	// issue
+	byIssue map[string]asana.Task
+	// sectionOf holds the sections looked up by section, by issue number
+	sectionOf map[int]asana.Section
+}
+
+// Noun implements Tracker.
+func (t *asanaTracker) Noun() string {
+	return "asana task"
+}
+
+// Item implements Tracker.
+func (t *asanaTracker) Item(issue models.GitHubIssue) (trackerItem, bool) {
+	task, ok := t.byIssue[strings.ToLower(issueline.URL(t.domain, t.repository, issue.Number))]
+	return trackerItem{ID: task.GID, Closed: task.Completed}, ok
+}
+
+// Where implements Tracker.
+func (t *asanaTracker) Where(issue models.GitHubIssue) string {
+	name, _ := t.section(issue)
+	return inSection(name)
+}
+
+// Create implements Tracker.
+func (t *asanaTracker) Create(ctx context.Context, issue models.GitHubIssue) (trackerItem, error) {
+	_, sectionGID := t.section(issue)
+	url := issueline.URL(t.domain, t.repository, issue.Number)
+	task, err := t.client.CreateTask(ctx, t.project.GID, sectionGID, issue.Title, issueline.Description(url, issue.Description))
+	if err != nil {
+		return trackerItem{}, err
+	}
+	return trackerItem{ID: task.GID}, nil
+}
+
+// Close implements Tracker.
+func (t *asanaTracker) Close(ctx context.Context, issue models.GitHubIssue, item trackerItem) (bool, error) {
+	return true, t.client.CompleteTask(ctx, item.ID)
+}
+
+// section returns the name and ID of the section of an issue's task, looked
+// up once per issue so a missing section is only logged once.
+func (t *asanaTracker) section(issue models.GitHubIssue) (string, string) {
+	section, ok := t.sectionOf[issue.Number]
+	if !ok {
+		section.Name, section.GID = asanaSection(t.cfg, issue.Labels, t.sections)
+		if t.sectionOf == nil {
+			t.sectionOf = make(map[int]asana.Section)
+		}
+		t.sectionOf[issue.Number] = section
+	}
+	return section.Name, section.GID
+}
+
+// tasksByIssue returns the tasks created by glue by the lower-cased address
+// of their issue. Tasks created otherwise are left out.
+func tasksByIssue(tasks []asana.Task) map[string]asana.Task {
+	byIssue := make(map[string]asana.Task)
+	for _, task := range tasks {
+		if url := issueline.IssueURL(task.Notes); url != "" {
+			byIssue[strings.ToLower(url)] = task
+		}
+	}
+	return byIssue
+}
+
+// asanaSection returns the name and ID of the section the task of an issue
+// with labels is created in, or empty strings for none. A configured section
+// missing from the project is logged and not used.
+func asanaSection(cfg config.AsanaConfig, labels []string, sections []asana.Section) (string, string) {
+	name := cfg.Section(labels)
+	if name == "" {
+		return "", ""
+	}
+	for _, section := range sections {
+		if strings.EqualFold(section.Name, name) {
+			return section.Name, section.GID
+		}
+	}
+	logging.Warn("asana section not found in project, creating task without section",
+		"section", name)
+	return "", ""
+}
+
+// inSection describes the section of a task for the output, if any.
+func inSection(name string) string {
+	if name == "" {
+		return ""
+	}
+	return fmt.Sprintf(" in %s", name)
+}
@@ -0,0 +1,40 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/asana"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/issueline"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTasksByIssue(t *testing.T) {
+	url := issueline.URL("github.com", "Owner/Repo", 7)
+
+	tasks := []asana.Task{
+		{GID: "1", Notes: issueline.Description(url, "Body")},
+		{GID: "2", Notes: "Planned by hand"},
+	}
+	assert.Equal(t, map[string]asana.Task{
+		"https://github.com/owner/repo/issues/7": tasks[0],
+	}, tasksByIssue(tasks))
+}
+
+func TestAsanaSection(t *testing.T) {
+	cfg := config.AsanaConfig{Sections: map[string]string{"bug": "Bugs", "idea": "Someday"}}
+	sections := []asana.Section{{GID: "101", Name: "Bugs"}, {GID: "102", Name: "Ideas"}}
+
+	name, gid := asanaSection(cfg, []string{"asana", "Bug"}, sections)
+	assert.Equal(t, "Bugs", name)
+	assert.Equal(t, "101", gid)
+
+	// Sections missing from the project aren't used
+	name, gid = asanaSection(cfg, []string{"idea"}, sections)
+	assert.Empty(t, name)
+	assert.Empty(t, gid)
+
+	name, gid = asanaSection(cfg, []string{"asana"}, sections)
+	assert.Empty(t, name)
+	assert.Empty(t, gid)
+}
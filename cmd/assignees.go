@@ -0,0 +1,73 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+
+	"github.com/danielolaszy/glue/internal/identity"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// syncJiraAssignees assigns unassigned JIRA tickets to the JIRA user of the
+// first GitHub assignee of their issue that resolves to one. Tickets already
+// assigned in JIRA are left alone. Issues already present in seen are skipped.
+// Returns the number of assigned tickets.
+func syncJiraAssignees(ctx context.Context, issues []models.GitHubIssue, seen map[int]bool, jiraClient *jira.Client, resolver *identity.Resolver) int {
+	assignCount := 0
+	for _, issue := range issues {
+		if stopStarting(ctx, jiraClient) {
+			break
+		}
+		jiraID := marker.GitHub.Key(issue.Title)
+		if jiraID == "" || seen[issue.Number] || len(issue.Assignees) == 0 {
+			continue
+		}
+		seen[issue.Number] = true
+
+		log := logging.FromContext(logging.WithTraceID(ctx, "issue_number", issue.Number, "jira_ticket", jiraID))
+		issueJira := jiraClient.WithLogger(log)
+
+		ticket, err := issueJira.GetTicket(jiraID)
+		if err != nil {
+			log.Error("failed to get jira ticket", "error", err)
+			continue
+		}
+		if ticket.Assignee != nil {
+			continue
+		}
+
+		user, login := resolveAssignee(issue.Assignees, func(login string) (*models.JiraUser, error) {
+			user, err := resolver.JiraUser(ctx, login)
+			if err != nil {
+				log.Warn("failed to resolve github user", "login", login, "error", err)
+			}
+			return user, err
+		})
+		if user == nil {
+			log.Debug("no github assignee resolves to a jira user", "assignees", issue.Assignees)
+			continue
+		}
+
+		if err := issueJira.AssignTicket(jiraID, *user); err != nil {
+			log.Error("failed to assign jira ticket", "login", login, "error", err)
+			continue
+		}
+		assignCount++
+	}
+	return assignCount
+}
+
+// resolveAssignee returns the JIRA user of the first login that resolves to
+// one, and that login. Logins failing to resolve are skipped.
+func resolveAssignee(logins []string, resolve func(string) (*models.JiraUser, error)) (*models.JiraUser, string) {
+	for _, login := range logins {
+		user, err := resolve(login)
+		if err == nil && user != nil {
+			return user, login
+		}
+	}
+	return nil, ""
+}
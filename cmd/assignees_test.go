@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolveAssignee(t *testing.T) {
+	jane := &models.JiraUser{AccountID: "acc-jane"}
+	resolve := func(login string) (*models.JiraUser, error) {
+		switch login {
+		case "broken":
+			return nil, errors.New("lookup failed")
+		case "janedoe":
+			return jane, nil
+		default:
+			return nil, nil
+		}
+	}
+
+	user, login := resolveAssignee([]string{"broken", "unknown", "janedoe"}, resolve)
+	assert.Equal(t, jane, user)
+	assert.Equal(t, "janedoe", login)
+
+	user, login = resolveAssignee([]string{"unknown"}, resolve)
+	assert.Nil(t, user)
+	assert.Empty(t, login)
+}
@@ -0,0 +1,424 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/checkpoint"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// backfillOperation names backfill checkpoints.
+const backfillOperation = "backfill"
+
+// runBudget limits how much work a single run may do. Zero values mean no limit.
+type runBudget struct {
+	deadline   time.Time
+	maxTickets int
+	created    int
+}
+
+// exhausted reports whether the run must stop starting new work.
+func (b *runBudget) exhausted(now time.Time) bool {
+	if !b.deadline.IsZero() && !now.Before(b.deadline) {
+		return true
+	}
+	return b.maxTickets > 0 && b.created >= b.maxTickets
+}
+
+// remainingTickets returns how many more tickets may be created, or -1 if unlimited.
+func (b *runBudget) remainingTickets() int {
+	if b.maxTickets <= 0 {
+		return -1
+	}
+	return b.maxTickets - b.created
+}
+
+// jiraBackfillCmd creates JIRA tickets for a large number of existing GitHub
+// issues in resumable batches.
+var jiraBackfillCmd = &cobra.Command{
+	Use:   "backfill",
+	Short: "Create JIRA tickets for existing GitHub issues in resumable batches",
+	Long: `Create JIRA tickets for a large number of existing GitHub issues, as in a
+first-time migration.
+
+Issues are processed in ascending order in batches of --batch-size. After each
+batch, progress is saved to a checkpoint file, so an interrupted or budget-limited
+run resumes where it stopped. Issues that failed are retried by the next run.
+
+Closed issues are left out unless --include-closed is set. Their tickets are
+transitioned to Done right away, with the time the issue was closed recorded in
+a comment and, with --closed-at-field, in a datetime custom field.
+
+--min-age and --max-age limit the backfill to issues created at least or at
+most that long ago, e.g. --min-age 180d to backfill only the old backlog.
+
+Each run can be limited with --max-duration and --max-tickets; the run stops
+starting new batches once a limit is reached. When no issues are left, a final
+reconciliation pass establishes the parent-child links of all features and the
+checkpoint is removed.
+
+Example:
+  glue jira backfill -r owner/repo -b PROJ --batch-size 100 --max-duration 30m`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true, Requires: map[string][]string{"closed-at-field": {"include-closed"}}}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		board, err := cmd.Flags().GetString("board")
+		if err != nil {
+			return err
+		}
+
+		batchSize, err := cmd.Flags().GetInt("batch-size")
+		if err != nil {
+			return err
+		}
+		if batchSize <= 0 {
+			return fmt.Errorf("batch size must be positive")
+		}
+
+		maxDuration, err := cmd.Flags().GetDuration("max-duration")
+		if err != nil {
+			return err
+		}
+
+		maxTickets, err := cmd.Flags().GetInt("max-tickets")
+		if err != nil {
+			return err
+		}
+
+		window, err := ageWindowFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		reset, err := cmd.Flags().GetBool("reset")
+		if err != nil {
+			return err
+		}
+
+		noLock, err := cmd.Flags().GetBool("no-lock")
+		if err != nil {
+			return err
+		}
+
+		includeClosed, err := cmd.Flags().GetBool("include-closed")
+		if err != nil {
+			return err
+		}
+
+		noSyncState, err := cmd.Flags().GetBool("no-sync-state")
+		if err != nil {
+			return err
+		}
+
+		closedAtField, err := cmd.Flags().GetString("closed-at-field")
+		if err != nil {
+			return err
+		}
+
+		budget := &runBudget{maxTickets: maxTickets}
+		if maxDuration > 0 {
+			budget.deadline = time.Now().Add(maxDuration)
+		}
+
+		ctx := context.Background()
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+		if err := configureRequiredFieldPrompt(cmd, jiraClient); err != nil {
+			return err
+		}
+		if err := configureReporter(ctx, cmd, cfg.Users, githubClient, jiraClient); err != nil {
+			return err
+		}
+
+		boards, err := resolveBoards(jiraClient, []string{board})
+		if err != nil {
+			return err
+		}
+		board = boards[0]
+
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := lockRepository(repository, noLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := checkSafety(cmd, cfg.Safety, repository, boards); err != nil {
+			return err
+		}
+
+		engine, err := loadRules(cfg.Rules)
+		if err != nil {
+			return err
+		}
+
+		cp, err := checkpoint.Load(checkpoint.DefaultDir(), backfillOperation, repository, board)
+		if err != nil {
+			return err
+		}
+		if reset {
+			if err := cp.Remove(); err != nil {
+				return err
+			}
+			cp, err = checkpoint.Load(checkpoint.DefaultDir(), backfillOperation, repository, board)
+			if err != nil {
+				return err
+			}
+		}
+		cp.Runs++
+
+		logging.Info("starting backfill",
+			"repository", repository,
+			"board", board,
+			"resume_after", cp.LastIssue,
+			"retrying", len(cp.Failed))
+
+		var syncState *state.Store
+		if !noSyncState {
+			syncState = trackSyncState(githubClient, repository)
+			defer saveSyncState(syncState)
+		}
+
+		issuesByBoard, err := fetchIssuesByBoard(ctx, githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+		issuesByBoard = skipLockedIssues(issuesByBoard)
+		issuesByBoard = skipIssuesByAge(issuesByBoard, window, time.Now())
+		frontMatter := parseFrontMatter(issuesByBoard)
+		decisions := applyFrontMatter(evaluateRules(engine, issuesByBoard), frontMatter, boards)
+		issuesByBoard = routeByRules(issuesByBoard, boards, decisions, cfg.Routes, cfg.BoardAliases)
+
+		featureTypeID, storyTypeID, err := issueTypeIDs(jiraClient, board)
+		if err != nil {
+			return err
+		}
+
+		hooks := &syncHooks{repository: repository, config: cfg.Hooks, syncState: syncState}
+		out := cmd.OutOrStdout()
+
+		candidates := backfillCandidates(issuesByBoard[board], cp, decisions, includeClosed)
+		fmt.Fprintf(out, "%d issues to backfill into %s\n", len(candidates), board)
+
+		for len(candidates) > 0 && !budget.exhausted(time.Now()) && jiraClient.CircuitOpen() == nil {
+			batch := nextBatch(candidates, batchSize, budget.remainingTickets())
+			candidates = candidates[len(batch):]
+
+			var features, stories []models.GitHubIssue
+			for _, issue := range batch {
+				if issueTypeFor(issue, decisions) == "feature" {
+					features = append(features, issue)
+				} else {
+					stories = append(stories, issue)
+				}
+			}
+
+			closedAt := make(map[int]*time.Time)
+			for _, issue := range batch {
+				if issue.State == "closed" {
+					closedAt[issue.Number] = issue.ClosedAt
+				}
+			}
+
+			created := make(map[int]bool)
+			for _, group := range []struct {
+				issues []models.GitHubIssue
+				typeID string
+			}{{features, featureTypeID}, {stories, storyTypeID}} {
+				updated, _, _ := processIssueGroup(ctx, group.issues, group.typeID, board, repository, githubClient, jiraClient, hooks, decisions)
+				for _, issue := range updated {
+					created[issue.Number] = true
+					if closed, ok := closedAt[issue.Number]; ok {
+						closeImportedTicket(ctx, jiraClient, issue, closed, closedAtField)
+					}
+				}
+			}
+
+			recordBatch(cp, batch, created)
+			budget.created += len(created)
+			if err := cp.Save(); err != nil {
+				return err
+			}
+
+			fmt.Fprintf(out, "batch up to #%d: %d created, %d failed, %d remaining\n",
+				cp.LastIssue, len(created), len(batch)-len(created), len(candidates))
+		}
+
+		if err := jiraClient.CircuitOpen(); err != nil {
+			return fmt.Errorf("backfill aborted with %d issues remaining; run again to resume (checkpoint %s): %v",
+				len(candidates), cp.Path(), err)
+		}
+
+		if len(candidates) > 0 {
+			fmt.Fprintf(out, "budget reached with %d issues remaining; run again to resume (checkpoint %s)\n",
+				len(candidates), cp.Path())
+			return nil
+		}
+
+		// Final reconciliation pass over the whole board
+		issuesByBoard, err = fetchIssuesByBoard(ctx, githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+		issuesByBoard = routeByRules(issuesByBoard, boards, decisions, cfg.Routes, cfg.BoardAliases)
+		if err := establishHierarchies(ctx, githubClient, jiraClient, repository, board, issuesByBoard[board], syncState, decisions, newLinkBudget(cfg.Jira)); err != nil {
+			logging.Error("failed to establish hierarchies during reconciliation",
+				"board", board,
+				"error", err)
+		}
+
+		if len(cp.Failed) > 0 {
+			fmt.Fprintf(out, "backfill finished with %d failed issues %v; run again to retry (checkpoint %s)\n",
+				len(cp.Failed), cp.Failed, cp.Path())
+			return nil
+		}
+
+		if err := cp.Remove(); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "backfill complete: %d tickets created in %d runs\n", cp.Created, cp.Runs)
+		return nil
+	},
+}
+
+func init() {
+	jiraBackfillCmd.Flags().StringP("board", "b", "", "JIRA project board to backfill")
+	jiraBackfillCmd.Flags().Int("batch-size", 100, "Number of issues processed between checkpoints")
+	jiraBackfillCmd.Flags().Duration("max-duration", 0, "Stop starting new batches after this long (e.g. 30m, 0 for no limit)")
+	jiraBackfillCmd.Flags().Int("max-tickets", 0, "Stop after creating this many tickets (0 for no limit)")
+	jiraBackfillCmd.Flags().Bool("reset", false, "Discard the saved checkpoint and start from the beginning")
+	jiraBackfillCmd.Flags().Bool("include-closed", false, "Also create tickets for closed issues, transitioned to Done")
+	jiraBackfillCmd.Flags().String("closed-at-field", "", "JIRA datetime custom field (e.g. customfield_10050) to record when an imported issue was closed")
+	jiraBackfillCmd.Flags().Bool("no-lock", false, "Skip the per-repository lock that prevents concurrent runs")
+	jiraBackfillCmd.Flags().Bool("no-sync-state", false, "Rely on issue title prefixes alone, ignoring the sync state recorded by earlier runs")
+}
+
+// backfillCandidates returns the issues still to backfill, in ascending order:
+// unsynced features and stories after the checkpoint, plus previously failed
+// issues. Issues skipped by rules, and closed issues unless includeClosed is
+// set, are left out.
+func backfillCandidates(issues []models.GitHubIssue, cp *checkpoint.Checkpoint, decisions map[int]rules.Decision, includeClosed bool) []models.GitHubIssue {
+	failed := make(map[int]bool, len(cp.Failed))
+	for _, number := range cp.Failed {
+		failed[number] = true
+	}
+
+	seen := make(map[int]bool)
+	var candidates []models.GitHubIssue
+	for _, issue := range issues {
+		if seen[issue.Number] || marker.GitHub.Marked(issue.Title, issue.Labels) || decisions[issue.Number].Skip {
+			continue
+		}
+		if issue.State == "closed" && !includeClosed {
+			continue
+		}
+		if issue.Number <= cp.LastIssue && !failed[issue.Number] {
+			continue
+		}
+		if issueTypeFor(issue, decisions) == "" {
+			continue
+		}
+		seen[issue.Number] = true
+		candidates = append(candidates, issue)
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].Number < candidates[j].Number
+	})
+	return candidates
+}
+
+// nextBatch returns the next batch of candidates, bounded by the batch size and
+// the remaining ticket budget (-1 for unlimited).
+func nextBatch(candidates []models.GitHubIssue, batchSize, remaining int) []models.GitHubIssue {
+	size := batchSize
+	if remaining >= 0 && remaining < size {
+		size = remaining
+	}
+	if size > len(candidates) {
+		size = len(candidates)
+	}
+	return candidates[:size]
+}
+
+// recordBatch updates the checkpoint after a batch: it advances LastIssue,
+// counts the created tickets and tracks the issues that failed.
+func recordBatch(cp *checkpoint.Checkpoint, batch []models.GitHubIssue, created map[int]bool) {
+	inBatch := make(map[int]bool, len(batch))
+	for _, issue := range batch {
+		inBatch[issue.Number] = true
+		if issue.Number > cp.LastIssue {
+			cp.LastIssue = issue.Number
+		}
+	}
+
+	var failed []int
+	for _, number := range cp.Failed {
+		if !inBatch[number] {
+			failed = append(failed, number)
+		}
+	}
+	for _, issue := range batch {
+		if !created[issue.Number] {
+			failed = append(failed, issue.Number)
+		}
+	}
+	sort.Ints(failed)
+
+	cp.Failed = failed
+	cp.Created += len(created)
+}
+
+// closeImportedTicket transitions the ticket just created for a closed issue to
+// Done, recording when the issue was closed. Failures are logged; the ticket is
+// closed by the next sync in any case.
+func closeImportedTicket(ctx context.Context, jiraClient *jira.Client, issue models.GitHubIssue, closedAt *time.Time, closedAtField string) {
+	jiraKey := marker.GitHub.Key(issue.Title)
+	log := logging.FromContext(logging.WithTraceID(ctx, "issue_number", issue.Number, "jira_ticket", jiraKey))
+
+	if closedAt == nil {
+		closedAt = issue.ClosedAt
+	}
+	if jiraKey == "" || closedAt == nil {
+		log.Warn("cannot close imported ticket, close time or ticket key unknown")
+		return
+	}
+
+	if err := jiraClient.WithLogger(log).CloseHistoricalTicket(jiraKey, *closedAt, closedAtField); err != nil {
+		log.Error("failed to close imported ticket",
+			"error", err)
+	}
+}
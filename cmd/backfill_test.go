@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/checkpoint"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func issueNumbers(issues []models.GitHubIssue) []int {
+	numbers := make([]int, 0, len(issues))
+	for _, issue := range issues {
+		numbers = append(numbers, issue.Number)
+	}
+	return numbers
+}
+
+func TestBackfillCandidates(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{Number: 9, Title: "Later story", Labels: []string{"story"}},
+		{Number: 3, Title: "Failed before", Labels: []string{"feature"}},
+		{Number: 2, Title: "Done before", Labels: []string{"story"}},
+		{Number: 7, Title: "[PROJ-1] Synced", Labels: []string{"story"}},
+		{Number: 8, Title: "No type", Labels: []string{"bug"}},
+		{Number: 10, Title: "Skipped", Labels: []string{"story"}},
+		{Number: 11, Title: "Typed by rules", Labels: []string{"bug"}},
+		{Number: 12, Title: "Closed story", State: "closed", Labels: []string{"story"}},
+		{Number: 9, Title: "Later story", Labels: []string{"story"}},
+	}
+	cp := &checkpoint.Checkpoint{LastIssue: 5, Failed: []int{3}}
+	decisions := map[int]rules.Decision{
+		10: {Skip: true},
+		11: {Type: "story"},
+	}
+
+	candidates := backfillCandidates(issues, cp, decisions, false)
+	assert.Equal(t, []int{3, 9, 11}, issueNumbers(candidates))
+
+	candidates = backfillCandidates(issues, cp, decisions, true)
+	assert.Equal(t, []int{3, 9, 11, 12}, issueNumbers(candidates))
+}
+
+func TestNextBatch(t *testing.T) {
+	candidates := []models.GitHubIssue{{Number: 1}, {Number: 2}, {Number: 3}, {Number: 4}}
+
+	tests := []struct {
+		name      string
+		batchSize int
+		remaining int
+		want      []int
+	}{
+		{"batch size", 3, -1, []int{1, 2, 3}},
+		{"fewer candidates", 10, -1, []int{1, 2, 3, 4}},
+		{"ticket budget", 3, 2, []int{1, 2}},
+		{"budget spent", 3, 0, []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, issueNumbers(nextBatch(candidates, tt.batchSize, tt.remaining)))
+		})
+	}
+}
+
+func TestRecordBatch(t *testing.T) {
+	cp := &checkpoint.Checkpoint{LastIssue: 5, Created: 4, Failed: []int{1, 3}}
+	batch := []models.GitHubIssue{{Number: 3}, {Number: 6}, {Number: 8}}
+
+	recordBatch(cp, batch, map[int]bool{3: true, 8: true})
+
+	assert.Equal(t, 8, cp.LastIssue)
+	assert.Equal(t, 6, cp.Created)
+	assert.Equal(t, []int{1, 6}, cp.Failed)
+}
+
+func TestRunBudget(t *testing.T) {
+	now := time.Now()
+
+	unlimited := &runBudget{}
+	assert.False(t, unlimited.exhausted(now))
+	assert.Equal(t, -1, unlimited.remainingTickets())
+
+	tickets := &runBudget{maxTickets: 5, created: 3}
+	assert.False(t, tickets.exhausted(now))
+	assert.Equal(t, 2, tickets.remainingTickets())
+	tickets.created = 5
+	assert.True(t, tickets.exhausted(now))
+
+	deadline := &runBudget{deadline: now.Add(time.Minute)}
+	assert.False(t, deadline.exhausted(now))
+	assert.True(t, deadline.exhausted(now.Add(time.Minute)))
+}
@@ -0,0 +1,361 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// Problems 'glue report backlinks' finds with a mapping.
+const (
+	// backlinkJiraOnly is a glue-created ticket no GitHub issue carries the key of
+	backlinkJiraOnly = "jira-only"
+	// backlinkGitHubOnly is an issue carrying the key of a deleted or moved ticket
+	backlinkGitHubOnly = "github-only"
+	// backlinkMissing is a ticket whose description lacks the configured backlink
+	backlinkMissing = "missing-backlink"
+	// backlinkUnresolvable is a backlink to no issue of the repository
+	backlinkUnresolvable = "unresolvable-backlink"
+	// backlinkMismatch is a backlink to an issue carrying another key, or a
+	// key carried by several issues
+	backlinkMismatch = "mismatch"
+)
+
+// backlinkProblem is a mapping between a JIRA ticket and a GitHub issue that
+// doesn't hold both ways, as reported by 'glue report backlinks'.
+type backlinkProblem struct {
+	// Key is the key of the JIRA ticket
+	Key string `json:"key"`
+	// Issue is the number of the GitHub issue involved, 0 if there is none
+	Issue int `json:"issue,omitempty"`
+	// Problem is one of the backlink* problems
+	Problem string `json:"problem"`
+	// Detail describes the problem
+	Detail string `json:"detail"`
+	// Remedy tells how to repair the mapping
+	Remedy string `json:"remedy"`
+}
+
+// reportBacklinksCmd checks that glue-created tickets and their GitHub issues
+// point at each other.
+var reportBacklinksCmd = &cobra.Command{
+	Use:   "backlinks",
+	Short: "Check that JIRA tickets and GitHub issues point at each other",
+	Long: `Check every JIRA ticket created by glue against the GitHub issues of the
+repository, and every synced GitHub issue against its ticket, and list the
+mappings that only hold one way:
+- jira-only: no issue carries the ticket's key in its title
+- github-only: the ticket in an issue title was deleted or moved
+- missing-backlink: the description lacks the link to the issue, although the
+  metadata or footer section is configured (see 'description.sections')
+- unresolvable-backlink: the description links to no issue of the repository
+- mismatch: the description links to an issue carrying another key, or several
+  issues carry the key
+
+Nothing is changed.
+
+Example:
+  glue report backlinks -r owner/repo -b PROJ --format json`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "table" && format != "json" {
+			return fmt.Errorf("invalid format %q, expected table or json", format)
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		openIssues, err := githubClient.GetAllIssues(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch github issues: %v", err)
+		}
+		closedIssues, err := githubClient.GetClosedIssues(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch closed github issues: %v", err)
+		}
+
+		tickets, err := jiraClient.SearchTickets(backlinksJQL(boards))
+		if err != nil {
+			return fmt.Errorf("failed to search jira tickets: %v", err)
+		}
+
+		check := backlinkCheck{
+			Repository:      repository,
+			GitHubDomain:    cfg.GitHub.Domain,
+			ExpectBacklinks: describesBacklink(cfg.Description.Sections),
+			Boards:          boards,
+		}
+		problems, err := check.run(append(openIssues, closedIssues...), tickets, jiraClient.GetTicket)
+		if err != nil {
+			return err
+		}
+
+		return writeBacklinkProblems(cmd.OutOrStdout(), repository, problems, format)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportBacklinksCmd)
+	reportBacklinksCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to check (can be specified multiple times)")
+	reportBacklinksCmd.Flags().String("format", "table", "Output format: table or json")
+}
+
+// backlinksJQL selects the tickets glue created in the boards.
+func backlinksJQL(boards []string) string {
+	quoted := make([]string, 0, len(boards))
+	for _, board := range boards {
+		quoted = append(quoted, fmt.Sprintf("'%s'", board))
+	}
+	return fmt.Sprintf("project in (%s) AND %s ORDER BY key ASC", strings.Join(quoted, ", "), marker.Jira.JQL())
+}
+
+// describesBacklink reports whether descriptions composed of sections link
+// to their GitHub issue.
+func describesBacklink(sections []string) bool {
+	return containsString(sections, config.DescriptionMetadata) || containsString(sections, config.DescriptionFooter)
+}
+
+// backlinkCheck checks the mappings between the GitHub issues of a
+// repository and the glue-created tickets of its boards.
+type backlinkCheck struct {
+	// Repository is the repository in owner/repo form
+	Repository string
+	// GitHubDomain is the domain of backlink URLs
+	GitHubDomain string
+	// ExpectBacklinks is set if descriptions are composed with a link to
+	// their issue
+	ExpectBacklinks bool
+	// Boards are the keys of the checked JIRA projects
+	Boards []string
+}
+
+// run returns the problems of the mappings between issues and tickets, by
+// key and issue. getTicket fetches the tickets of issue keys of the boards
+// that aren't among tickets, to tell tickets glue didn't create from deleted
+// or moved ones.
+func (c backlinkCheck) run(issues []models.GitHubIssue, tickets []models.JiraTicket, getTicket func(key string) (models.JiraTicket, error)) ([]backlinkProblem, error) {
+	issueByNumber := make(map[int]models.GitHubIssue)
+	carriers := make(map[string][]int)
+	for _, issue := range issues {
+		if _, seen := issueByNumber[issue.Number]; seen {
+			continue
+		}
+		issueByNumber[issue.Number] = issue
+		if key := marker.GitHub.Key(issue.Title); key != "" {
+			carriers[key] = append(carriers[key], issue.Number)
+		}
+	}
+
+	var problems []backlinkProblem
+	ticketKeys := make(map[string]bool)
+	for _, ticket := range tickets {
+		if !marker.Jira.Marked(ticket.Title, ticket.Labels) || hasLabel(ticket.Labels, jira.SecurityLabel) {
+			continue
+		}
+		ticketKeys[ticket.Key] = true
+		if problem, ok := c.checkTicket(ticket, carriers[ticket.Key], issueByNumber); ok {
+			problems = append(problems, problem)
+		}
+	}
+
+	for key, numbers := range carriers {
+		if ticketKeys[key] || !containsString(c.Boards, projectOfKey(key)) {
+			continue
+		}
+		ticket, err := getTicket(key)
+		if broken, ok := brokenMappingOf(key, ticket, err); ok {
+			for _, number := range numbers {
+				problems = append(problems, backlinkProblem{
+					Key:     key,
+					Issue:   number,
+					Problem: backlinkGitHubOnly,
+					Detail:  fmt.Sprintf("#%d carries %s, which was %s", number, key, brokenStatus(broken)),
+					Remedy:  brokenRemedy(broken, number),
+				})
+			}
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to get jira ticket %s: %v", key, err)
+		}
+		// A ticket glue didn't create, mapped by hand
+		if len(numbers) > 1 {
+			problems = append(problems, duplicateCarriers(key, numbers))
+		}
+	}
+
+	sort.Slice(problems, func(i, j int) bool {
+		if problems[i].Key != problems[j].Key {
+			return problems[i].Key < problems[j].Key
+		}
+		return problems[i].Issue < problems[j].Issue
+	})
+	return problems, nil
+}
+
+// checkTicket checks a glue-created ticket against the issues carrying its
+// key and the issue its description links to.
+func (c backlinkCheck) checkTicket(ticket models.JiraTicket, carriers []int, issueByNumber map[int]models.GitHubIssue) (backlinkProblem, bool) {
+	backlink, linked := jira.DescriptionBacklink(ticket.Description)
+	if linked {
+		issue, ok := issueByNumber[backlink.Number]
+		if !ok || backlink.URL != "" && !strings.EqualFold(backlink.URL, c.issueURL(issue, backlink.Number)) {
+			target := backlink.URL
+			if target == "" {
+				target = fmt.Sprintf("#%d", backlink.Number)
+			}
+			return backlinkProblem{
+				Key:     ticket.Key,
+				Issue:   singleCarrier(carriers),
+				Problem: backlinkUnresolvable,
+				Detail:  fmt.Sprintf("%s links to %s, which is no issue of %s", ticket.Key, target, c.Repository),
+				Remedy:  c.rewriteRemedy(ticket.Key, carriers),
+			}, true
+		}
+		if key := marker.GitHub.Key(issue.Title); key != ticket.Key {
+			carried := "no key"
+			if key != "" {
+				carried = key
+			}
+			return backlinkProblem{
+				Key:     ticket.Key,
+				Issue:   issue.Number,
+				Problem: backlinkMismatch,
+				Detail:  fmt.Sprintf("%s links to #%d, which carries %s", ticket.Key, issue.Number, carried),
+				Remedy:  c.rewriteRemedy(ticket.Key, carriers),
+			}, true
+		}
+	}
+
+	switch {
+	case len(carriers) == 0:
+		return backlinkProblem{
+			Key:     ticket.Key,
+			Problem: backlinkJiraOnly,
+			Detail:  fmt.Sprintf("no issue of %s carries %s", c.Repository, ticket.Key),
+			Remedy:  fmt.Sprintf("prefix the title of its issue with [%s], or close %s if the issue is gone", ticket.Key, ticket.Key),
+		}, true
+	case len(carriers) > 1:
+		return duplicateCarriers(ticket.Key, carriers), true
+	case !linked && c.ExpectBacklinks:
+		return backlinkProblem{
+			Key:     ticket.Key,
+			Issue:   carriers[0],
+			Problem: backlinkMissing,
+			Detail:  fmt.Sprintf("the description of %s doesn't link to #%d", ticket.Key, carriers[0]),
+			Remedy:  c.rewriteRemedy(ticket.Key, carriers),
+		}, true
+	}
+	return backlinkProblem{}, false
+}
+
+// issueURL returns the web page of the issue with number, as backlinks to it
+// are written.
+func (c backlinkCheck) issueURL(issue models.GitHubIssue, number int) string {
+	if issue.URL != "" {
+		return issue.URL
+	}
+	return fmt.Sprintf("https://%s/%s/issues/%d", c.GitHubDomain, c.Repository, number)
+}
+
+// rewriteRemedy tells how to have a ticket's description link to its issue.
+func (c backlinkCheck) rewriteRemedy(key string, carriers []int) string {
+	if len(carriers) == 1 {
+		return fmt.Sprintf("run 'glue jira -r %s -b %s' to rewrite the description of %s from #%d", c.Repository, projectOfKey(key), key, carriers[0])
+	}
+	return fmt.Sprintf("prefix the title of the issue of %s with [%s] and run 'glue jira' to rewrite its description", key, key)
+}
+
+// duplicateCarriers is the problem of a key carried by several issues.
+func duplicateCarriers(key string, numbers []int) backlinkProblem {
+	refs := make([]string, 0, len(numbers))
+	for _, number := range numbers {
+		refs = append(refs, fmt.Sprintf("#%d", number))
+	}
+	return backlinkProblem{
+		Key:     key,
+		Issue:   numbers[0],
+		Problem: backlinkMismatch,
+		Detail:  fmt.Sprintf("%s is carried by %s", key, strings.Join(refs, ", ")),
+		Remedy:  fmt.Sprintf("remove the [%s] prefix from all but one of %s", key, strings.Join(refs, ", ")),
+	}
+}
+
+// singleCarrier returns the issue carrying a key, or 0 if none or several do.
+func singleCarrier(carriers []int) int {
+	if len(carriers) == 1 {
+		return carriers[0]
+	}
+	return 0
+}
+
+// projectOfKey returns the project of a ticket key, e.g. "PROJ" for "PROJ-12".
+func projectOfKey(key string) string {
+	if i := strings.LastIndex(key, "-"); i != -1 {
+		return key[:i]
+	}
+	return key
+}
+
+// writeBacklinkProblems writes the problems as a list or as JSON.
+func writeBacklinkProblems(w io.Writer, repository string, problems []backlinkProblem, format string) error {
+	if format == "json" {
+		if problems == nil {
+			problems = []backlinkProblem{}
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(problems)
+	}
+
+	if len(problems) == 0 {
+		_, err := fmt.Fprintf(w, "All JIRA tickets and GitHub issues of %s point at each other.\n", repository)
+		return err
+	}
+	for _, p := range problems {
+		fmt.Fprintf(w, "%s: %s\n  %s\n", p.Problem, p.Detail, p.Remedy)
+	}
+	_, err := fmt.Fprintf(w, "\nMappings holding only one way: %d\n", len(problems))
+	return err
+}
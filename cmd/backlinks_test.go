@@ -0,0 +1,157 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBacklinkCheck(t *testing.T) {
+	issue := func(number int, title string) models.GitHubIssue {
+		return models.GitHubIssue{Number: number, Title: title}
+	}
+	footer := func(number int, url string) string {
+		return jira.ComposeDescription(models.GitHubIssue{Number: number, URL: url}, "Body", []string{config.DescriptionBody, config.DescriptionFooter})
+	}
+	ticket := func(key, description string, labels ...string) models.JiraTicket {
+		return models.JiraTicket{Key: key, Description: description, Labels: append([]string{"glue"}, labels...)}
+	}
+
+	issues := []models.GitHubIssue{
+		issue(1, "[PROJ-1] Login"),
+		issue(3, "Logout"),
+		issue(4, "[PROJ-4] Signup"),
+		issue(5, "[PROJ-5] Profile"),
+		issue(6, "[PROJ-6] Search"),
+		issue(7, "[PROJ-6] Search again"),
+		issue(10, "[PROJ-10] Deleted"),
+		issue(11, "[OTHER-1] Elsewhere"),
+		issue(12, "[PROJ-12] Created by hand"),
+		issue(30, "[PROJ-3] Logout"),
+	}
+	issues[0].URL = "https://github.com/owner/repo/issues/1"
+	// Closed issues are fetched separately and may repeat open ones
+	issues = append(issues, issues[0])
+
+	tickets := []models.JiraTicket{
+		ticket("PROJ-1", footer(1, "https://github.com/owner/repo/issues/1")),
+		ticket("PROJ-2", "Body"),
+		ticket("PROJ-3", footer(3, "")),
+		ticket("PROJ-4", footer(4, "https://github.com/owner/old-repo/issues/4")),
+		ticket("PROJ-5", "Body"),
+		ticket("PROJ-6", footer(6, "")),
+		ticket("PROJ-8", "Alert", jira.SecurityLabel),
+	}
+
+	getTicket := func(key string) (models.JiraTicket, error) {
+		switch key {
+		case "PROJ-10":
+			return models.JiraTicket{}, jira.ErrNotFound
+		case "PROJ-12":
+			return models.JiraTicket{Key: key}, nil
+		}
+		t.Fatalf("unexpected lookup of %s", key)
+		return models.JiraTicket{}, nil
+	}
+
+	check := backlinkCheck{Repository: "owner/repo", GitHubDomain: "github.com", ExpectBacklinks: true, Boards: []string{"PROJ"}}
+	problems, err := check.run(issues, tickets, getTicket)
+	require.NoError(t, err)
+
+	got := make(map[string]backlinkProblem)
+	for _, p := range problems {
+		got[p.Key] = p
+	}
+	require.Len(t, problems, 6, "%+v", problems)
+	assert.Equal(t, []string{"PROJ-10", "PROJ-2", "PROJ-3", "PROJ-4", "PROJ-5", "PROJ-6"},
+		[]string{problems[0].Key, problems[1].Key, problems[2].Key, problems[3].Key, problems[4].Key, problems[5].Key})
+
+	assert.Equal(t, backlinkJiraOnly, got["PROJ-2"].Problem)
+	assert.Equal(t, "no issue of owner/repo carries PROJ-2", got["PROJ-2"].Detail)
+
+	assert.Equal(t, backlinkMismatch, got["PROJ-3"].Problem)
+	assert.Equal(t, 3, got["PROJ-3"].Issue)
+	assert.Equal(t, "PROJ-3 links to #3, which carries no key", got["PROJ-3"].Detail)
+	assert.Contains(t, got["PROJ-3"].Remedy, "rewrite the description of PROJ-3 from #30")
+
+	assert.Equal(t, backlinkUnresolvable, got["PROJ-4"].Problem)
+	assert.Equal(t, "PROJ-4 links to https://github.com/owner/old-repo/issues/4, which is no issue of owner/repo", got["PROJ-4"].Detail)
+
+	assert.Equal(t, backlinkMissing, got["PROJ-5"].Problem)
+	assert.Equal(t, 5, got["PROJ-5"].Issue)
+
+	assert.Equal(t, backlinkMismatch, got["PROJ-6"].Problem)
+	assert.Equal(t, "PROJ-6 is carried by #6, #7", got["PROJ-6"].Detail)
+
+	assert.Equal(t, backlinkGitHubOnly, got["PROJ-10"].Problem)
+	assert.Equal(t, "#10 carries PROJ-10, which was deleted", got["PROJ-10"].Detail)
+	assert.Contains(t, got["PROJ-10"].Remedy, "restore PROJ-10 in JIRA")
+}
+
+func TestBacklinkCheckWithoutConfiguredBacklinks(t *testing.T) {
+	issues := []models.GitHubIssue{{Number: 5, Title: "[PROJ-5] Profile"}}
+	tickets := []models.JiraTicket{{Key: "PROJ-5", Description: "Body", Labels: []string{"glue"}}}
+
+	check := backlinkCheck{Repository: "owner/repo", GitHubDomain: "github.com", Boards: []string{"PROJ"}}
+	problems, err := check.run(issues, tickets, nil)
+	require.NoError(t, err)
+	assert.Empty(t, problems)
+}
+
+func TestBacklinkCheckLookupError(t *testing.T) {
+	issues := []models.GitHubIssue{{Number: 5, Title: "[PROJ-5] Profile"}}
+	getTicket := func(key string) (models.JiraTicket, error) {
+		return models.JiraTicket{}, errors.New("connection reset")
+	}
+
+	check := backlinkCheck{Repository: "owner/repo", Boards: []string{"PROJ"}}
+	_, err := check.run(issues, nil, getTicket)
+	assert.EqualError(t, err, "failed to get jira ticket PROJ-5: connection reset")
+}
+
+func TestDescribesBacklink(t *testing.T) {
+	assert.False(t, describesBacklink(nil))
+	assert.False(t, describesBacklink([]string{config.DescriptionBody, config.DescriptionChildren}))
+	assert.True(t, describesBacklink([]string{config.DescriptionBody, config.DescriptionFooter}))
+	assert.True(t, describesBacklink([]string{config.DescriptionMetadata}))
+}
+
+func TestBacklinksJQL(t *testing.T) {
+	assert.Equal(t, `project in ('PROJ', 'OPS') AND labels = "glue" ORDER BY key ASC`, backlinksJQL([]string{"PROJ", "OPS"}))
+}
+
+func TestWriteBacklinkProblems(t *testing.T) {
+	problems := []backlinkProblem{{
+		Key:     "PROJ-2",
+		Problem: backlinkJiraOnly,
+		Detail:  "no issue of owner/repo carries PROJ-2",
+		Remedy:  "prefix the title of its issue with [PROJ-2], or close PROJ-2 if the issue is gone",
+	}}
+
+	var table bytes.Buffer
+	require.NoError(t, writeBacklinkProblems(&table, "owner/repo", problems, "table"))
+	assert.Equal(t, "jira-only: no issue of owner/repo carries PROJ-2\n  prefix the title of its issue with [PROJ-2], or close PROJ-2 if the issue is gone\n\nMappings holding only one way: 1\n", table.String())
+
+	var out bytes.Buffer
+	require.NoError(t, writeBacklinkProblems(&out, "owner/repo", problems, "json"))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 1)
+	assert.Equal(t, "jira-only", decoded[0]["problem"])
+	assert.NotContains(t, decoded[0], "issue")
+
+	var empty bytes.Buffer
+	require.NoError(t, writeBacklinkProblems(&empty, "owner/repo", nil, "json"))
+	assert.Equal(t, "[]\n", empty.String())
+
+	var healthy bytes.Buffer
+	require.NoError(t, writeBacklinkProblems(&healthy, "owner/repo", nil, "table"))
+	assert.Equal(t, "All JIRA tickets and GitHub issues of owner/repo point at each other.\n", healthy.String())
+}
@@ -0,0 +1,290 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// boardLabelPrefix is the prefix of labels that route an issue to a JIRA project,
+// e.g. "jira-project: PROJ". A plain label equal to the project key also routes.
+const boardLabelPrefix = "jira-project:"
+
+// boardLabelPattern matches "jira-project: KEY" labels and captures the key,
+// or the board alias, e.g. "jira-project: platform-eng".
+var boardLabelPattern = regexp.MustCompile(`(?i)^jira-project:\s*([A-Za-z][A-Za-z0-9_-]*)\s*$`)
+
+// parseBoardLabels returns the sorted, de-duplicated JIRA project keys found
+// in labels of the form "jira-project: KEY". Aliases are resolved and keys
+// are upper-cased.
+func parseBoardLabels(labels []string, aliases config.BoardAliases) []string {
+	seen := make(map[string]bool)
+	var keys []string
+	for _, label := range labels {
+		matches := boardLabelPattern.FindStringSubmatch(label)
+		if len(matches) < 2 {
+			continue
+		}
+		key := strings.ToUpper(aliases.Resolve(matches[1]))
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// hasBoardLabel reports whether an issue is routed to board, either by a label
+// equal to the project key or one of its aliases, or by a "jira-project: KEY"
+// label.
+func hasBoardLabel(labels []string, board string, aliases config.BoardAliases) bool {
+	if hasLabel(labels, board) {
+		return true
+	}
+	for _, alias := range aliases.Of(board) {
+		if hasLabel(labels, alias) {
+			return true
+		}
+	}
+	for _, key := range parseBoardLabels(labels, aliases) {
+		if strings.EqualFold(key, board) {
+			return true
+		}
+	}
+	return false
+}
+
+// boardLabels returns every label form that routes issues to the given
+// boards, by their keys and aliases.
+func boardLabels(boards []string, aliases config.BoardAliases) []string {
+	labels := make([]string, 0, len(boards)*2)
+	for _, board := range boards {
+		for _, name := range append([]string{board}, aliases.Of(board)...) {
+			labels = append(labels, name, fmt.Sprintf("%s %s", boardLabelPrefix, name))
+		}
+	}
+	return labels
+}
+
+// fetchClosedIssuesForBoards retrieves the closed GitHub issues with the
+// board labels of any of the boards or the labels of routes to them. The
+// search ANDs labels, so each routing label is queried separately and the
+// results are de-duplicated. Failed queries are logged and skipped.
+func fetchClosedIssuesForBoards(ctx context.Context, githubClient *github.Client, repository string, boards []string, routes []config.Route, aliases config.BoardAliases) []models.GitHubIssue {
+	var issues []models.GitHubIssue
+	seen := make(map[int]bool)
+	for _, label := range append(boardLabels(boards, aliases), routeLabels(routes, boards)...) {
+		closed, err := githubClient.GetClosedIssuesWithLabels(ctx, repository, []string{label})
+		if err != nil {
+			logging.Warn("failed to fetch closed github issues",
+				"label", label,
+				"error", err)
+			continue
+		}
+		for _, issue := range closed {
+			if !seen[issue.Number] {
+				seen[issue.Number] = true
+				issues = append(issues, issue)
+			}
+		}
+	}
+	return issues
+}
+
+// discoverBoards derives the boards to sync from the repository's
+// "jira-project: KEY" labels, which may name boards by their aliases. Keys
+// that don't exist in JIRA are skipped with a warning. It returns an error if
+// the labels or projects cannot be listed.
+func discoverBoards(ctx context.Context, githubClient *github.Client, jiraClient *jira.Client, repository string, aliases config.BoardAliases) ([]string, error) {
+	labels, err := githubClient.ListLabels(ctx, repository)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list repository labels: %v", err)
+	}
+
+	candidates := parseBoardLabels(labels, aliases)
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	projectKeys, err := jiraClient.ListProjectKeys()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list jira projects: %v", err)
+	}
+
+	existing := make(map[string]bool, len(projectKeys))
+	for _, key := range projectKeys {
+		existing[strings.ToUpper(key)] = true
+	}
+
+	var boards []string
+	for _, key := range candidates {
+		if !existing[key] {
+			logging.Warn("skipping discovered board that does not exist in jira",
+				"board", key,
+				"label", fmt.Sprintf("%s %s", boardLabelPrefix, key))
+			continue
+		}
+		boards = append(boards, key)
+	}
+
+	logging.Info("discovered boards from repository labels",
+		"repository", repository,
+		"boards", boards)
+
+	return boards, nil
+}
+
+// resolveBoardFlag replaces the board aliases given with --board by the
+// project keys they stand for, before the flags are validated and any API is
+// called. Commands without --board are left alone.
+func resolveBoardFlag(cmd *cobra.Command, aliases config.BoardAliases) error {
+	flag := cmd.Flags().Lookup("board")
+	if flag == nil || len(aliases) == 0 {
+		return nil
+	}
+
+	if slice, ok := flag.Value.(pflag.SliceValue); ok {
+		boards := slice.GetSlice()
+		resolved := make([]string, len(boards))
+		for i, board := range boards {
+			resolved[i] = aliases.Resolve(board)
+		}
+		logging.Debug("resolved board aliases", "boards", boards, "resolved", resolved)
+		return slice.Replace(resolved)
+	}
+	if board := flag.Value.String(); board != "" {
+		if key := aliases.Resolve(board); key != board {
+			logging.Debug("resolved board alias", "alias", board, "board", key)
+			return flag.Value.Set(key)
+		}
+	}
+	return nil
+}
+
+// resolveBoards normalizes the boards given with --board and validates them
+// against the JIRA projects visible to the configured user, so typos fail fast
+// with a suggestion instead of deep inside issue type lookups. If the projects
+// cannot be listed, the normalized boards are returned unvalidated.
+func resolveBoards(jiraClient *jira.Client, boards []string) ([]string, error) {
+	projectKeys, err := jiraClient.ListProjectKeys()
+	if err != nil {
+		logging.Warn("failed to list jira projects, skipping board validation", "error", err)
+		return normalizeBoards(boards), nil
+	}
+	return validateBoards(boards, projectKeys)
+}
+
+// normalizeBoards trims and upper-cases board keys and drops empty values and
+// duplicates, preserving the order they were given in.
+func normalizeBoards(boards []string) []string {
+	seen := make(map[string]bool)
+	var normalized []string
+	for _, board := range boards {
+		key := strings.ToUpper(strings.TrimSpace(board))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, key)
+	}
+	return normalized
+}
+
+// validateBoards normalizes boards and checks them against the existing
+// project keys. It returns the boards using the keys' JIRA spelling, or an
+// error listing every unknown board together with close matches.
+func validateBoards(boards []string, projectKeys []string) ([]string, error) {
+	existing := make(map[string]string, len(projectKeys))
+	for _, key := range projectKeys {
+		existing[strings.ToUpper(key)] = key
+	}
+
+	var valid, problems []string
+	for _, board := range normalizeBoards(boards) {
+		if key, ok := existing[board]; ok {
+			valid = append(valid, key)
+			continue
+		}
+
+		problem := fmt.Sprintf("'%s'", board)
+		if suggestions := suggestProjectKeys(board, projectKeys); len(suggestions) > 0 {
+			problem += fmt.Sprintf(" (did you mean %s?)", strings.Join(suggestions, ", "))
+		}
+		problems = append(problems, problem)
+	}
+
+	if len(problems) > 0 {
+		return nil, fmt.Errorf("unknown jira project(s): %s", strings.Join(problems, "; "))
+	}
+	if len(valid) == 0 {
+		return nil, fmt.Errorf("no valid jira boards specified")
+	}
+	return valid, nil
+}
+
+// maxSuggestionDistance is the largest edit distance at which a project key is
+// suggested for a mistyped board.
+const maxSuggestionDistance = 2
+
+// suggestProjectKeys returns up to three project keys close to key, closest first.
+func suggestProjectKeys(key string, projectKeys []string) []string {
+	type candidate struct {
+		key      string
+		distance int
+	}
+
+	var candidates []candidate
+	for _, projectKey := range projectKeys {
+		distance := editDistance(strings.ToUpper(key), strings.ToUpper(projectKey))
+		if distance <= maxSuggestionDistance {
+			candidates = append(candidates, candidate{projectKey, distance})
+		}
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].key < candidates[j].key
+	})
+
+	var suggestions []string
+	for i := 0; i < len(candidates) && i < 3; i++ {
+		suggestions = append(suggestions, candidates[i].key)
+	}
+	return suggestions
+}
+
+// editDistance returns the Levenshtein distance between a and b.
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAliases name the PLTENG board platform and platform-eng.
+var testAliases = config.BoardAliases{"platform": "PLTENG", "platform-eng": "PLTENG"}
+
+func TestParseBoardLabels(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		expected []string
+	}{
+		{
+			name:     "no board labels",
+			labels:   []string{"feature", "PROJ"},
+			expected: nil,
+		},
+		{
+			name:     "single board label",
+			labels:   []string{"story", "jira-project: PROJ"},
+			expected: []string{"PROJ"},
+		},
+		{
+			name:     "case insensitive prefix and key",
+			labels:   []string{"Jira-Project:proj", "JIRA-PROJECT:  Ops "},
+			expected: []string{"OPS", "PROJ"},
+		},
+		{
+			name:     "duplicates and sorting",
+			labels:   []string{"jira-project: ZED", "jira-project: ABC", "jira-project: zed"},
+			expected: []string{"ABC", "ZED"},
+		},
+		{
+			name:     "invalid keys are ignored",
+			labels:   []string{"jira-project:", "jira-project: two words", "jira-project: 1ABC"},
+			expected: nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, parseBoardLabels(tt.labels, nil))
+		})
+	}
+
+	assert.Equal(t, []string{"OPS", "PLTENG"}, parseBoardLabels([]string{"jira-project: Platform-Eng", "jira-project: ops", "jira-project: PLTENG"}, testAliases))
+	assert.Equal(t, []string{"PLATFORM-ENG"}, parseBoardLabels([]string{"jira-project: platform-eng"}, nil))
+}
+
+func TestHasBoardLabel(t *testing.T) {
+	tests := []struct {
+		name     string
+		labels   []string
+		board    string
+		expected bool
+	}{
+		{"plain key label", []string{"feature", "PROJ"}, "PROJ", true},
+		{"prefixed label", []string{"jira-project: PROJ"}, "PROJ", true},
+		{"prefixed label different case", []string{"jira-project: proj"}, "PROJ", true},
+		{"other board", []string{"jira-project: OPS", "OPS"}, "PROJ", false},
+		{"no labels", nil, "PROJ", false},
+		{"alias label", []string{"Platform"}, "PLTENG", true},
+		{"prefixed alias label", []string{"jira-project: platform-eng"}, "PLTENG", true},
+		{"alias of other board", []string{"platform"}, "PROJ", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, hasBoardLabel(tt.labels, tt.board, testAliases))
+		})
+	}
+}
+
+func TestBoardLabels(t *testing.T) {
+	assert.Equal(t,
+		[]string{"PROJ", "jira-project: PROJ", "OPS", "jira-project: OPS"},
+		boardLabels([]string{"PROJ", "OPS"}, nil))
+	assert.Equal(t,
+		[]string{"PLTENG", "jira-project: PLTENG", "platform", "jira-project: platform", "platform-eng", "jira-project: platform-eng"},
+		boardLabels([]string{"PLTENG"}, testAliases))
+}
+
+func TestResolveBoardFlag(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArrayP("board", "b", []string{}, "")
+	require.NoError(t, cmd.Flags().Set("board", "platform"))
+	require.NoError(t, cmd.Flags().Set("board", "PROJ"))
+	require.NoError(t, resolveBoardFlag(cmd, testAliases))
+	boards, _ := cmd.Flags().GetStringArray("board")
+	assert.Equal(t, []string{"PLTENG", "PROJ"}, boards)
+
+	cmd = &cobra.Command{}
+	cmd.Flags().StringP("board", "b", "", "")
+	require.NoError(t, cmd.Flags().Set("board", "Platform-Eng"))
+	require.NoError(t, resolveBoardFlag(cmd, testAliases))
+	board, _ := cmd.Flags().GetString("board")
+	assert.Equal(t, "PLTENG", board)
+
+	// Commands without --board are left alone
+	assert.NoError(t, resolveBoardFlag(&cobra.Command{}, testAliases))
+}
+
+func TestValidateBoards(t *testing.T) {
+	projectKeys := []string{"PROJ", "OPS", "DATA", "PRJ2"}
+
+	tests := []struct {
+		name        string
+		boards      []string
+		expected    []string
+		errContains []string
+	}{
+		{
+			name:     "exact keys",
+			boards:   []string{"PROJ", "OPS"},
+			expected: []string{"PROJ", "OPS"},
+		},
+		{
+			name:     "case and whitespace are normalized",
+			boards:   []string{" proj", "Ops "},
+			expected: []string{"PROJ", "OPS"},
+		},
+		{
+			name:     "duplicates are dropped",
+			boards:   []string{"PROJ", "proj"},
+			expected: []string{"PROJ"},
+		},
+		{
+			name:        "typo suggests close matches",
+			boards:      []string{"PORJ"},
+			errContains: []string{"'PORJ'", "did you mean", "PROJ?"},
+		},
+		{
+			name:        "unknown key without suggestions",
+			boards:      []string{"PROJ", "MARKETING"},
+			errContains: []string{"'MARKETING'"},
+		},
+		{
+			name:        "only empty values",
+			boards:      []string{" "},
+			errContains: []string{"no valid jira boards"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			boards, err := validateBoards(tt.boards, projectKeys)
+			if len(tt.errContains) > 0 {
+				assert.Error(t, err)
+				for _, s := range tt.errContains {
+					assert.Contains(t, err.Error(), s)
+				}
+				assert.NotContains(t, err.Error(), "did you mean DATA")
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, boards)
+		})
+	}
+}
+
+func TestSuggestProjectKeys(t *testing.T) {
+	projectKeys := []string{"PROJ", "PRJ", "OPS", "PROJX"}
+
+	assert.Equal(t, []string{"PRJ", "PROJ", "PROJX"}, suggestProjectKeys("PRJO", projectKeys))
+	assert.Equal(t, []string{"OPS"}, suggestProjectKeys("ops1", projectKeys))
+	assert.Empty(t, suggestProjectKeys("FINANCE", projectKeys))
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("PROJ", "PROJ"))
+	assert.Equal(t, 1, editDistance("PROJ", "PRJ"))
+	assert.Equal(t, 2, editDistance("PORJ", "PROJ"))
+	assert.Equal(t, 4, editDistance("", "PROJ"))
+}
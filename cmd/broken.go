@@ -0,0 +1,166 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/mappings"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/spf13/cobra"
+)
+
+// brokenMapping is a broken mapping with the GitHub issue carrying its key,
+// as reported by 'glue report broken'.
+type brokenMapping struct {
+	mappings.Broken
+	// Issue is the number of the GitHub issue whose title carries the key
+	Issue int `json:"issue"`
+	// Remedy tells how to repair the mapping
+	Remedy string `json:"remedy"`
+}
+
+// reportBrokenCmd lists the mappings to deleted or moved JIRA tickets.
+var reportBrokenCmd = &cobra.Command{
+	Use:   "broken",
+	Short: "Show GitHub issues mapped to deleted or moved JIRA tickets",
+	Long: `List the GitHub issues whose JIRA ticket was deleted or moved to another
+project, with how to repair each mapping.
+
+'glue jira' records a mapping as broken when JIRA answers 404 Not Found for
+its ticket, or answers under another key because the ticket was moved. The
+record is dropped once the ticket answers under the key in the issue title
+again, or no issue carries the key anymore.
+
+Example:
+  glue report broken -r owner/repo --format json`,
+	PreRunE: validateFlags(flagRules{Repository: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "table" && format != "json" {
+			return fmt.Errorf("invalid format %q, expected table or json", format)
+		}
+
+		store, err := mappings.Load(mappings.DefaultDir(), repository)
+		if err != nil {
+			return err
+		}
+
+		issueByKey := make(map[string]int)
+		if len(store.List()) > 0 {
+			githubClient, err := github.NewClient()
+			if err != nil {
+				return fmt.Errorf("failed to initialize github client: %v", err)
+			}
+			openIssues, err := githubClient.GetAllIssues(cmd.Context(), repository)
+			if err != nil {
+				return fmt.Errorf("failed to fetch github issues: %v", err)
+			}
+			closedIssues, err := githubClient.GetClosedIssues(cmd.Context(), repository)
+			if err != nil {
+				return fmt.Errorf("failed to fetch closed github issues: %v", err)
+			}
+			for _, issue := range append(openIssues, closedIssues...) {
+				if key := marker.GitHub.Key(issue.Title); key != "" {
+					issueByKey[key] = issue.Number
+				}
+			}
+		}
+
+		return writeBrokenMappings(cmd.OutOrStdout(), repository, brokenMappings(store.List(), issueByKey), format)
+	},
+}
+
+func init() {
+	reportCmd.AddCommand(reportBrokenCmd)
+	reportBrokenCmd.Flags().String("format", "table", "Output format: table or json")
+}
+
+// trackBrokenMappings records the tickets the JIRA client finds deleted or
+// moved in the repository's mapping store, and repairs the records of those
+// found intact. It returns the store to save at the end of the run.
+func trackBrokenMappings(jiraClient *jira.Client, repository string) *mappings.Store {
+	store, err := mappings.Load(mappings.DefaultDir(), repository)
+	if err != nil {
+		logging.Warn("replacing unreadable mapping store", "error", err)
+		store = mappings.New(mappings.DefaultDir(), repository)
+	}
+	jiraClient.SetTicketFunc(func(key, current string) {
+		store.Observe(key, current, time.Now())
+	})
+	return store
+}
+
+// saveBrokenMappings saves the mapping store and points out broken mappings.
+func saveBrokenMappings(cmd *cobra.Command, store *mappings.Store) {
+	if err := store.Save(); err != nil {
+		logging.Warn("failed to save mapping store", "error", err)
+	}
+	if broken := len(store.List()); broken > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), "%d JIRA tickets mapped to GitHub issues were deleted or moved; run 'glue report broken -r %s' for how to repair them\n",
+			broken, store.Repository)
+	}
+}
+
+// brokenMappings pairs broken mappings with the issue carrying their key and
+// a remedy. Mappings no issue carries anymore were repaired and are left out.
+func brokenMappings(list []mappings.Broken, issueByKey map[string]int) []brokenMapping {
+	result := make([]brokenMapping, 0, len(list))
+	for _, broken := range list {
+		issue, ok := issueByKey[broken.Key]
+		if !ok {
+			continue
+		}
+		result = append(result, brokenMapping{Broken: broken, Issue: issue, Remedy: brokenRemedy(broken, issue)})
+	}
+	return result
+}
+
+// brokenRemedy tells how to repair a broken mapping of an issue.
+func brokenRemedy(broken mappings.Broken, issue int) string {
+	if broken.Reason == mappings.Moved {
+		return fmt.Sprintf("change the [%s] prefix in the title of #%d to [%s]", broken.Key, issue, broken.MovedTo)
+	}
+	return fmt.Sprintf("restore %s in JIRA, or remove the [%s] prefix from the title of #%d for the next sync to create a new ticket",
+		broken.Key, broken.Key, issue)
+}
+
+// writeBrokenMappings writes the broken mappings as a list or as JSON.
+func writeBrokenMappings(w io.Writer, repository string, broken []brokenMapping, format string) error {
+	if format == "json" {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(broken)
+	}
+
+	if len(broken) == 0 {
+		_, err := fmt.Fprintf(w, "No broken mappings recorded for %s.\n", repository)
+		return err
+	}
+	for _, b := range broken {
+		fmt.Fprintf(w, "#%d -> %s: %s (detected %s)\n  %s\n", b.Issue, b.Key, brokenStatus(b.Broken), b.DetectedAt.Format("2006-01-02"), b.Remedy)
+	}
+	return nil
+}
+
+// brokenStatus describes what happened to the ticket of a broken mapping,
+// e.g. "deleted" or "moved to NEW-7".
+func brokenStatus(broken mappings.Broken) string {
+	if broken.Reason == mappings.Moved {
+		return "moved to " + broken.MovedTo
+	}
+	return string(broken.Reason)
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/mappings"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBrokenMappings(t *testing.T) {
+	detected := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	list := []mappings.Broken{
+		{Key: "OLD-1", Reason: mappings.Moved, MovedTo: "NEW-7", DetectedAt: detected},
+		{Key: "PROJ-2", Reason: mappings.Deleted, DetectedAt: detected},
+		{Key: "PROJ-3", Reason: mappings.Deleted, DetectedAt: detected},
+	}
+
+	// PROJ-3 is carried by no issue anymore, so it was repaired
+	broken := brokenMappings(list, map[string]int{"OLD-1": 10, "PROJ-2": 20})
+	require.Len(t, broken, 2)
+	assert.Equal(t, 10, broken[0].Issue)
+	assert.Equal(t, "change the [OLD-1] prefix in the title of #10 to [NEW-7]", broken[0].Remedy)
+	assert.Equal(t, 20, broken[1].Issue)
+	assert.Contains(t, broken[1].Remedy, "remove the [PROJ-2] prefix from the title of #20")
+
+	var table bytes.Buffer
+	require.NoError(t, writeBrokenMappings(&table, "owner/repo", broken, "table"))
+	assert.Contains(t, table.String(), "#10 -> OLD-1: moved to NEW-7 (detected 2024-05-01)")
+	assert.Contains(t, table.String(), "#20 -> PROJ-2: deleted (detected 2024-05-01)")
+
+	var out bytes.Buffer
+	require.NoError(t, writeBrokenMappings(&out, "owner/repo", broken, "json"))
+	var decoded []map[string]any
+	require.NoError(t, json.Unmarshal(out.Bytes(), &decoded))
+	require.Len(t, decoded, 2)
+	assert.Equal(t, "NEW-7", decoded[0]["moved_to"])
+	assert.Equal(t, float64(20), decoded[1]["issue"])
+
+	var empty bytes.Buffer
+	require.NoError(t, writeBrokenMappings(&empty, "owner/repo", nil, "table"))
+	assert.Equal(t, "No broken mappings recorded for owner/repo.\n", empty.String())
+}
+
+func TestBrokenMappingOf(t *testing.T) {
+	tests := []struct {
+		name       string
+		ticket     models.JiraTicket
+		err        error
+		wantOK     bool
+		wantReason mappings.Reason
+		wantMoved  string
+	}{
+		{"intact", models.JiraTicket{Key: "PROJ-1"}, nil, false, "", ""},
+		{"deleted", models.JiraTicket{}, fmt.Errorf("failed to get ticket: %w", jira.ErrNotFound), true, mappings.Deleted, ""},
+		{"moved", models.JiraTicket{Key: "NEW-9"}, nil, true, mappings.Moved, "NEW-9"},
+		{"other error", models.JiraTicket{}, errors.New("timeout"), false, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broken, ok := brokenMappingOf("PROJ-1", tt.ticket, tt.err)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.wantReason, broken.Reason)
+			assert.Equal(t, tt.wantMoved, broken.MovedTo)
+		})
+	}
+}
@@ -0,0 +1,164 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// jiraBulkTransitionCmd moves the tickets matching a JQL query to a status.
+var jiraBulkTransitionCmd = &cobra.Command{
+	Use:   "bulk-transition",
+	Short: "Move the JIRA tickets matching a JQL query to another status",
+	Long: `Move every JIRA ticket matching a JQL query through the workflow, e.g. to
+clean up the tickets glue created for a repository after a migration.
+
+--to names a transition, by name or ID, or the status to move to. Each ticket
+uses the transition its current status offers; list them with
+'glue jira transitions'. Tickets already in the status are skipped, as are
+tickets glue didn't create unless --all is set. The projects of the tickets
+must be permitted by the safety config.
+
+Run with --dry-run first to see which transition each ticket would take.
+
+Example:
+  glue jira bulk-transition --jql 'project = PROJ AND status = "To Do"' --to Done --dry-run
+  glue jira bulk-transition --jql 'project = PROJ AND created < -365d' --to "Won't Do"`,
+	PreRunE: validateFlags(flagRules{Required: []string{"jql", "to"}}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jql, err := cmd.Flags().GetString("jql")
+		if err != nil {
+			return err
+		}
+
+		to, err := cmd.Flags().GetString("to")
+		if err != nil {
+			return err
+		}
+
+		all, err := cmd.Flags().GetBool("all")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		tickets, err := jiraClient.SearchTickets(jql)
+		if err != nil {
+			return err
+		}
+
+		plan := planBulkTransition(tickets, to, all)
+		if err := checkSafety(cmd, cfg.Safety, "", ticketProjects(plan.Move)); err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		moved, failed := 0, 0
+		for _, ticket := range plan.Move {
+			transitions, err := jiraClient.GetTransitions(ticket.Key)
+			if err != nil {
+				logging.Error("failed to get transitions", "ticket", ticket.Key, "error", err)
+				failed++
+				continue
+			}
+
+			transition, ok := jira.FindTransition(transitions, to)
+			if !ok {
+				fmt.Fprintf(out, "%s: no transition to %s from %s\n", ticket.Key, to, ticket.Status)
+				failed++
+				continue
+			}
+
+			if dryRun {
+				fmt.Fprintf(out, "%s: would move from %s to %s via '%s'\n", ticket.Key, ticket.Status, transition.ToStatus, transition.Name)
+				continue
+			}
+
+			if err := jiraClient.DoTransition(ticket.Key, transition.ID); err != nil {
+				logging.Error("failed to transition ticket", "ticket", ticket.Key, "error", err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(out, "%s: moved from %s to %s\n", ticket.Key, ticket.Status, transition.ToStatus)
+			moved++
+		}
+
+		fmt.Fprintf(out, "\n%d tickets matched: %d moved, %d already %s, %d not created by glue, %d failed\n",
+			len(tickets), moved, plan.Already, to, plan.NotGlue, failed)
+		if failed > 0 {
+			return fmt.Errorf("failed to move %d tickets", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraBulkTransitionCmd)
+	jiraBulkTransitionCmd.Flags().String("jql", "", "JQL query selecting the tickets to move")
+	jiraBulkTransitionCmd.Flags().String("to", "", "Transition name or ID, or status, to move the tickets to")
+	jiraBulkTransitionCmd.Flags().Bool("all", false, "Also move tickets glue didn't create")
+	jiraBulkTransitionCmd.Flags().Bool("dry-run", false, "Print the transition each ticket would take without moving it")
+}
+
+// bulkTransitionPlan sorts the tickets matched by a bulk transition.
+type bulkTransitionPlan struct {
+	// Move are the tickets to move
+	Move []models.JiraTicket
+	// Already counts the tickets already in the target status
+	Already int
+	// NotGlue counts the tickets skipped because glue didn't create them
+	NotGlue int
+}
+
+// planBulkTransition selects the tickets to move to target: those not yet in
+// a status named target and, unless all is set, created by glue.
+func planBulkTransition(tickets []models.JiraTicket, target string, all bool) bulkTransitionPlan {
+	var plan bulkTransitionPlan
+	for _, ticket := range tickets {
+		switch {
+		case strings.EqualFold(ticket.Status, target):
+			plan.Already++
+		case !all && !ticket.CreatedByGlue:
+			plan.NotGlue++
+		default:
+			plan.Move = append(plan.Move, ticket)
+		}
+	}
+	return plan
+}
+
+// ticketProjects returns the sorted, de-duplicated project keys of tickets.
+func ticketProjects(tickets []models.JiraTicket) []string {
+	seen := make(map[string]bool)
+	var projects []string
+	for _, ticket := range tickets {
+		project, _, _ := strings.Cut(ticket.Key, "-")
+		if !seen[project] {
+			seen[project] = true
+			projects = append(projects, project)
+		}
+	}
+	sort.Strings(projects)
+	return projects
+}
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanBulkTransition(t *testing.T) {
+	tickets := []models.JiraTicket{
+		{Key: "PROJ-1", Status: "To Do", CreatedByGlue: true},
+		{Key: "PROJ-2", Status: "done", CreatedByGlue: true},
+		{Key: "OPS-3", Status: "In Progress"},
+		{Key: "OPS-4", Status: "To Do", CreatedByGlue: true},
+	}
+
+	plan := planBulkTransition(tickets, "Done", false)
+	assert.Equal(t, []models.JiraTicket{tickets[0], tickets[3]}, plan.Move)
+	assert.Equal(t, 1, plan.Already)
+	assert.Equal(t, 1, plan.NotGlue)
+	assert.Equal(t, []string{"OPS", "PROJ"}, ticketProjects(plan.Move))
+
+	plan = planBulkTransition(tickets, "Done", true)
+	assert.Equal(t, []models.JiraTicket{tickets[0], tickets[2], tickets[3]}, plan.Move)
+	assert.Zero(t, plan.NotGlue)
+}
@@ -0,0 +1,360 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/clickup"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/issueline"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// clickUpTask is the task of an issue with the list it is in.
+type clickUpTask struct {
+	Task clickup.Task
+	List string
+}
+
+// clickUpLink makes the existing task of an issue a subtask of the task of
+// its feature.
+type clickUpLink struct {
+	Issue models.GitHubIssue
+	// Parent is the number of the issue whose task becomes the parent
+	Parent int
+}
+
+// clickupCmd creates ClickUp tasks for labeled GitHub issues.
+var clickupCmd = &cobra.Command{
+	Use:   "clickup",
+	Short: "Synchronize labeled GitHub issues with ClickUp tasks",
+	Long: `Create a ClickUp task for every open GitHub issue with the --label label that
+has none yet, keep the task status in line with the issue and link the tasks
+of stories as subtasks of their feature's task.
+
+Tasks are created in the list clickup.lists in the config file maps the first
+of the issue's labels to, or in the --list list. Features are the issues
+glue creates JIRA features for, and their stories are the issues linked in
+their '## Issues' section; a story's task becomes a subtask of its feature's
+task when both are in the same list.
+
+The first line of a task's description links its issue, which is how the
+issue and the task find each other on later runs; GitHub issues aren't
+changed. Tasks of closed issues move to clickup.closed_status (default
+"complete"), and closed tasks of reopened issues to clickup.open_status
+(default "to do").
+
+The token is read from CLICKUP_TOKEN or clickup.token in the config file.
+
+Example:
+  glue clickup -r owner/repo --list 901234567
+  glue clickup -r owner/repo --label roadmap --dry-run`,
+	PreRunE: validateFlags(flagRules{Repository: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		defaultList, err := cmd.Flags().GetString("list")
+		if err != nil {
+			return err
+		}
+
+		label, err := cmd.Flags().GetString("label")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		noLock, err := cmd.Flags().GetBool("no-lock")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+		if defaultList == "" && len(cfg.ClickUp.Lists) == 0 {
+			return fmt.Errorf("no clickup list, use --list or set clickup.lists in the config file")
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		clickupClient, err := clickup.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize clickup client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := lockRepository(repository, noLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := checkSafety(cmd, cfg.Safety, repository, nil); err != nil {
+			return err
+		}
+
+		open, err := githubClient.GetIssuesWithLabel(ctx, repository, label)
+		if err != nil {
+			return fmt.Errorf("failed to get issues with label %s: %v", label, err)
+		}
+		closed, err := githubClient.GetClosedIssuesWithLabels(ctx, repository, []string{label})
+		if err != nil {
+			return fmt.Errorf("failed to get closed issues with label %s: %v", label, err)
+		}
+		all := append(append([]models.GitHubIssue{}, open...), closed...)
+		applyIssueTypes(ctx, githubClient, repository, all)
+
+		lists := clickUpLists(cfg.ClickUp, all, defaultList)
+
+		// Find the tasks of the issues in the lists they are routed to
+		byURL := make(map[string]clickUpTask)
+		for _, list := range distinctValues(lists) {
+			tasks, err := clickupClient.Tasks(ctx, list)
+			if err != nil {
+				return err
+			}
+			for _, task := range tasks {
+				if url := issueline.IssueURL(task.Description); url != "" {
+					byURL[strings.ToLower(url)] = clickUpTask{Task: task, List: list}
+				}
+			}
+		}
+		tasks := make(map[int]clickUpTask)
+		for _, issue := range all {
+			if task, ok := byURL[strings.ToLower(issueline.URL(cfg.GitHub.Domain, repository, issue.Number))]; ok {
+				tasks[issue.Number] = task
+			}
+		}
+
+		tracker := &clickUpTracker{
+			client:     clickupClient,
+			cfg:        cfg.ClickUp,
+			domain:     cfg.GitHub.Domain,
+			repository: repository,
+			lists:      lists,
+			tasks:      tasks,
+			parents:    clickUpParents(all, cfg.GitHub.Domain),
+		}
+		out := cmd.OutOrStdout()
+		run := trackerSync{out: out, dryRun: dryRun}
+		counts := syncTracker(ctx, tracker, routedIssues(open, lists), closed, run)
+		linked, failed := tracker.link(ctx, planClickUpLinks(open, tasks, tracker.parents), run)
+
+		fmt.Fprintf(out, "\n%d open and %d closed issues labeled %s, %d task changes\n",
+			len(open), len(closed), label, counts.Created+counts.Reopened+counts.Closed+linked)
+		if failed += counts.Failed; failed > 0 {
+			return fmt.Errorf("failed to update tasks of %d issues", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(clickupCmd)
+	clickupCmd.Flags().String("list", "", "ID of the ClickUp list for issues without a label in clickup.lists")
+	clickupCmd.Flags().StringP("label", "l", "clickup", "Label of the GitHub issues that get a task")
+	clickupCmd.Flags().Bool("dry-run", false, "Print the task changes without making them")
+	clickupCmd.Flags().Bool("no-lock", false, "Skip the per-repository lock that prevents concurrent runs")
+	clickupCmd.Flags().Bool("force", false, "Run even if the safety config doesn't permit changing the repository")
+}
+
+// clickUpLists returns the list each issue's task is created in, by issue
+// number. Issues without a list are logged and left out.
+func clickUpLists(cfg config.ClickUpConfig, issues []models.GitHubIssue, defaultList string) map[int]string {
+	lists := make(map[int]string, len(issues))
+	for _, issue := range issues {
+		list := cfg.List(issue.Labels)
+		if list == "" {
+			list = defaultList
+		}
+		if list == "" {
+			logging.Warn("no clickup list for issue, skipping it",
+				"issue_number", issue.Number)
+			continue
+		}
+		lists[issue.Number] = list
+	}
+	return lists
+}
+
+// clickUpParents maps the numbers of stories to the number of the feature
+// listing them, the lowest if several do.
+func clickUpParents(issues []models.GitHubIssue, gitHubDomain string) map[int]int {
+	parents := make(map[int]int)
+	for _, issue := range issues {
+		if issueTypeOf(issue) != "feature" {
+			continue
+		}
+		for _, child := range parseChildIssues(issue.Description, gitHubDomain) {
+			if current, ok := parents[child]; !ok || issue.Number < current {
+				parents[child] = issue.Number
+			}
+		}
+	}
+	return parents
+}
+
+// routedIssues returns the issues with a list.
+func routedIssues(issues []models.GitHubIssue, lists map[int]string) []models.GitHubIssue {
+	var routed []models.GitHubIssue
+	for _, issue := range issues {
+		if _, ok := lists[issue.Number]; ok {
+			routed = append(routed, issue)
+		}
+	}
+	return routed
+}
+
+// planClickUpLinks returns the parent links missing from the existing tasks
+// of open issues.
+func planClickUpLinks(open []models.GitHubIssue, tasks map[int]clickUpTask, parents map[int]int) []clickUpLink {
+	var links []clickUpLink
+	for _, issue := range open {
+		task, exists := tasks[issue.Number]
+		if parent, ok := tasks[parents[issue.Number]]; exists && ok && task.Task.Parent != parent.Task.ID {
+			links = append(links, clickUpLink{Issue: issue, Parent: parents[issue.Number]})
+		}
+	}
+	return links
+}
+
+// clickUpTracker is the Tracker of the tasks in ClickUp lists. Tasks are
+// created in the list of their issue, as subtasks of the task of their
+// feature if it is in the same list, and closing and reopening a task sets
+// its status.
+type clickUpTracker struct {
+	client     *clickup.Client
+	cfg        config.ClickUpConfig
+	domain     string
+	repository string
+	// lists are the lists of the issues' tasks, by issue number
+	lists map[int]string
+	// tasks are the existing tasks, by issue number; created tasks are added
+	tasks map[int]clickUpTask
+	// parents are the numbers of the features of stories, by story number
+	parents map[int]int
+}
+
+// Noun implements Tracker.
+func (t *clickUpTracker) Noun() string {
+	return "clickup task"
+}
+
+// Item implements Tracker.
+func (t *clickUpTracker) Item(issue models.GitHubIssue) (trackerItem, bool) {
+	task, ok := t.tasks[issue.Number]
+	return trackerItem{ID: task.Task.ID, Closed: task.Task.Closed()}, ok
+}
+
+// Where implements Tracker.
+func (t *clickUpTracker) Where(issue models.GitHubIssue) string {
+	return ""
+}
+
+// Create implements Tracker.
+func (t *clickUpTracker) Create(ctx context.Context, issue models.GitHubIssue) (trackerItem, error) {
+	list := t.lists[issue.Number]
+	task, err := t.client.CreateTask(ctx, list, clickup.NewTask{
+		Name:        issue.Title,
+		Description: issueline.Description(issueline.URL(t.domain, t.repository, issue.Number), issue.Description),
+		Parent:      t.parentID(issue, list),
+	})
+	if err != nil {
+		return trackerItem{}, err
+	}
+	t.tasks[issue.Number] = clickUpTask{Task: task, List: list}
+	return trackerItem{ID: task.ID}, nil
+}
+
+// Close implements Tracker.
+func (t *clickUpTracker) Close(ctx context.Context, issue models.GitHubIssue, item trackerItem) (bool, error) {
+	return true, t.client.SetStatus(ctx, item.ID, t.cfg.ClosedStatus)
+}
+
+// Reopen implements trackerReopener.
+func (t *clickUpTracker) Reopen(ctx context.Context, issue models.GitHubIssue, item trackerItem) error {
+	return t.client.SetStatus(ctx, item.ID, t.cfg.OpenStatus)
+}
+
+// parentID returns the ID of the task of an issue's feature, if it is in
+// list, or "" otherwise.
+func (t *clickUpTracker) parentID(issue models.GitHubIssue, list string) string {
+	parent, ok := t.tasks[t.parents[issue.Number]]
+	switch {
+	case ok && parent.List == list:
+		return parent.Task.ID
+	case ok:
+		logging.Warn("parent task is in another list, not linking the subtask",
+			"issue_number", issue.Number,
+			"parent_issue", t.parents[issue.Number])
+	}
+	return ""
+}
+
+// link makes existing tasks subtasks of the tasks of their features, and
+// returns the number linked and failed. Tasks in another list than their
+// feature's aren't linked.
+func (t *clickUpTracker) link(ctx context.Context, links []clickUpLink, run trackerSync) (int, int) {
+	out := outOrDiscard(run.out)
+	linked, failed := 0, 0
+	for _, link := range links {
+		issue := link.Issue
+		task := t.tasks[issue.Number]
+		parentID := t.parentID(issue, task.List)
+		if parentID == "" {
+			continue
+		}
+		if run.dryRun {
+			fmt.Fprintf(out, "would link %s %s of #%d %s\n", t.Noun(), task.Task.ID, issue.Number, issue.Title)
+			continue
+		}
+		if err := t.client.SetParent(ctx, task.Task.ID, parentID); err != nil {
+			logging.Error("failed to link clickup task",
+				"issue_number", issue.Number,
+				"tracker_item", task.Task.ID,
+				"error", err)
+			failed++
+			continue
+		}
+		fmt.Fprintf(out, "linked %s %s of #%d %s\n", t.Noun(), task.Task.ID, issue.Number, issue.Title)
+		linked++
+	}
+	return linked, failed
+}
+
+// distinctValues returns the distinct values of m, sorted.
+func distinctValues(m map[int]string) []string {
+	seen := make(map[string]bool)
+	var values []string
+	for _, value := range m {
+		if !seen[value] {
+			seen[value] = true
+			values = append(values, value)
+		}
+	}
+	sort.Strings(values)
+	return values
+}
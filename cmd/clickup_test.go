@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/clickup"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClickUpLists(t *testing.T) {
+	cfg := config.ClickUpConfig{Lists: map[string]string{"bug": "901"}}
+	issues := []models.GitHubIssue{
+		{Number: 1, Labels: []string{"clickup", "Bug"}},
+		{Number: 2, Labels: []string{"clickup"}},
+	}
+
+	assert.Equal(t, map[int]string{1: "901", 2: "900"}, clickUpLists(cfg, issues, "900"))
+	// Issues without a list are left out
+	assert.Equal(t, map[int]string{1: "901"}, clickUpLists(cfg, issues, ""))
+}
+
+func TestClickUpParents(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{Number: 5, Type: "Feature", Description: "## Issues\n- https://github.com/owner/repo/issues/7\n- https://github.com/owner/repo/issues/8"},
+		{Number: 3, Type: "Feature", Description: "## Issues\n- https://github.com/owner/repo/issues/8"},
+		{Number: 7, Description: "## Issues\n- https://github.com/owner/repo/issues/9"},
+	}
+
+	// Only features have stories, and the lowest feature listing a story wins
+	assert.Equal(t, map[int]int{7: 5, 8: 3}, clickUpParents(issues, "github.com"))
+}
+
+func TestPlanClickUpActions(t *testing.T) {
+	open := []models.GitHubIssue{
+		{Number: 2, Title: "Story"},
+		{Number: 1, Title: "Feature", Type: "Feature"},
+		{Number: 3, Title: "Reopened"},
+		{Number: 4, Title: "Unlinked story"},
+		{Number: 6, Title: "Unrouted"},
+	}
+	closed := []models.GitHubIssue{
+		{Number: 5, Title: "Done"},
+		{Number: 8, Title: "Already closed"},
+	}
+	lists := map[int]string{1: "901", 2: "901", 3: "901", 4: "901", 5: "901", 8: "901"}
+	done := clickup.TaskStatus{Status: "complete", Type: "closed"}
+	tasks := map[int]clickUpTask{
+		3: {Task: clickup.Task{ID: "c", Status: done}, List: "901"},
+		4: {Task: clickup.Task{ID: "d"}, List: "901"},
+		5: {Task: clickup.Task{ID: "e"}, List: "901"},
+		8: {Task: clickup.Task{ID: "h", Status: done}, List: "901"},
+		9: {Task: clickup.Task{ID: "i"}, List: "901"},
+	}
+	parents := map[int]int{2: 1, 4: 9}
+	tracker := &clickUpTracker{lists: lists, tasks: tasks, parents: parents}
+
+	actions := planTrackerActions(tracker, routedIssues(open, lists), closed)
+
+	var kinds []string
+	var numbers []int
+	for _, action := range actions {
+		kinds = append(kinds, action.Kind)
+		numbers = append(numbers, action.Issue.Number)
+	}
+	// Features are created before their stories
+	assert.Equal(t, []string{"create", "create", "reopen", "close"}, kinds)
+	assert.Equal(t, []int{1, 2, 3, 5}, numbers)
+	assert.Equal(t, "c", actions[2].Item.ID)
+
+	links := planClickUpLinks(open, tasks, parents)
+	assert.Equal(t, []clickUpLink{{Issue: open[3], Parent: 9}}, links)
+	assert.Equal(t, "i", tracker.parentID(open[3], "901"))
+	assert.Equal(t, "", tracker.parentID(open[3], "902"))
+
+	// Tasks already linked aren't linked again
+	tasks[4] = clickUpTask{Task: clickup.Task{ID: "d", Parent: "i"}, List: "901"}
+	assert.Empty(t, planClickUpLinks(open, tasks, parents))
+}
+
+func TestDistinctValues(t *testing.T) {
+	assert.Equal(t, []string{"901", "902"}, distinctValues(map[int]string{1: "902", 2: "901", 3: "902"}))
+	assert.Empty(t, distinctValues(nil))
+}
@@ -0,0 +1,51 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// configCmd groups the commands about the config file.
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "Work with the glue config file",
+}
+
+// configSchemaCmd prints the JSON Schema of the config file.
+var configSchemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print a JSON Schema of the config file",
+	Long: `Print a JSON Schema describing glue.yaml, generated from the settings glue
+reads. Editors use it to validate the config file and complete its keys, and
+unknown keys such as misspelled settings are reported as errors.
+
+Examples:
+  glue config schema > glue.schema.json
+
+  # With the YAML language server, e.g. in VS Code, start glue.yaml with
+  # yaml-language-server: $schema=./glue.schema.json`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return writeSchema(cmd.OutOrStdout(), config.Schema())
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configSchemaCmd)
+}
+
+// writeSchema writes schema as indented JSON.
+func writeSchema(w io.Writer, schema map[string]interface{}) error {
+	data, err := json.MarshalIndent(schema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode schema: %v", err)
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
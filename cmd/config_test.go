@@ -0,0 +1,23 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteSchema(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, writeSchema(&out, config.Schema()))
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal(out.Bytes(), &schema))
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Equal(t, false, schema["additionalProperties"])
+	assert.Contains(t, schema["properties"], "jira")
+	assert.Contains(t, out.String(), "\n  \"properties\": {\n")
+}
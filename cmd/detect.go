@@ -0,0 +1,95 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+var (
+	// branchBoardPattern matches branch names starting with a ticket key, such
+	// as "PROJ-123-fix-login" or "feature/PROJ-123", and captures the project
+	// key. Keys must be upper case, so "release-2024" isn't taken for one.
+	branchBoardPattern = regexp.MustCompile(`^([A-Z][A-Z0-9_]*)-[0-9]+(?:[-_.].*)?$`)
+	// topicBoardPattern matches repository topics such as "jira-proj" and
+	// captures the project key.
+	topicBoardPattern = regexp.MustCompile(`^jira-([a-z][a-z0-9]*)$`)
+)
+
+// detectBoards sets --board, if none was given, from the ticket key the
+// current git branch starts with, or else from the repository's "jira-KEY"
+// topics. It only runs with --detect-board, and prints what it inferred.
+func detectBoards(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	flag := flags.Lookup("board")
+	if enabled, _ := flags.GetBool("detect-board"); flag == nil || !enabled {
+		return nil
+	}
+	if value := flag.Value.String(); value != "" && value != "[]" {
+		return nil
+	}
+
+	var boards []string
+	var source string
+	if branch, err := gitOutput("rev-parse", "--abbrev-ref", "HEAD"); err != nil {
+		logging.Debug("not detecting board from git branch", "error", err)
+	} else if board := branchBoard(branch); board != "" {
+		boards, source = []string{board}, fmt.Sprintf("git branch %s", branch)
+	}
+
+	repository, _ := flags.GetString("repository")
+	if len(boards) == 0 && repository != "" {
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+		topics, err := githubClient.ListTopics(cmd.Context(), repository)
+		if err != nil {
+			logging.Warn("not detecting board from repository topics", "error", err)
+		}
+		boards, source = topicBoards(topics), fmt.Sprintf("topics of %s", repository)
+	}
+
+	if len(boards) == 0 {
+		return nil
+	}
+	if flag.Value.Type() != "stringArray" && len(boards) > 1 {
+		logging.Warn("not detecting board, several found", "boards", boards, "source", source)
+		return nil
+	}
+
+	for _, board := range boards {
+		if err := flags.Set("board", board); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Using board %s from %s (set --board to override)\n", strings.Join(boards, ", "), source)
+	return nil
+}
+
+// branchBoard returns the project key of the ticket key the last path element
+// of branch starts with, or an empty string.
+func branchBoard(branch string) string {
+	name := branch[strings.LastIndex(branch, "/")+1:]
+	matches := branchBoardPattern.FindStringSubmatch(name)
+	if matches == nil {
+		return ""
+	}
+	return matches[1]
+}
+
+// topicBoards returns the upper-cased project keys of "jira-KEY" topics.
+func topicBoards(topics []string) []string {
+	var boards []string
+	for _, topic := range topics {
+		if matches := topicBoardPattern.FindStringSubmatch(topic); matches != nil {
+			boards = append(boards, strings.ToUpper(matches[1]))
+		}
+	}
+	return boards
+}
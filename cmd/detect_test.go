@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBranchBoard(t *testing.T) {
+	tests := []struct {
+		branch string
+		want   string
+	}{
+		{"PROJ-123-fix-login", "PROJ"},
+		{"proj-123", ""},
+		{"feature/OPS_2-7_cleanup", "OPS_2"},
+		{"user/jdoe/PROJ-1.hotfix", "PROJ"},
+		{"main", ""},
+		{"HEAD", ""},
+		{"release-2024", ""},
+		{"PROJ-123abc", ""},
+		{"PROJ-123/notes", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.branch, func(t *testing.T) {
+			assert.Equal(t, tt.want, branchBoard(tt.branch))
+		})
+	}
+}
+
+func TestTopicBoards(t *testing.T) {
+	assert.Equal(t, []string{"PROJ", "OPS2"}, topicBoards([]string{"go", "jira-proj", "jira", "jira-ops2", "jira-my-proj"}))
+	assert.Empty(t, topicBoards(nil))
+}
@@ -0,0 +1,299 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/mappings"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// fieldDiff describes a single difference between a GitHub issue and its JIRA ticket.
+type fieldDiff struct {
+	// Field is the compared field (e.g., "title", "status")
+	Field string
+	// GitHub is the value on the GitHub side
+	GitHub string
+	// Jira is the value on the JIRA side
+	Jira string
+	// Fixable indicates whether the next 'glue jira' run resolves the difference
+	Fixable bool
+	// Note explains what the next sync will do, or why it won't
+	Note string
+}
+
+// diffCmd prints the drift between GitHub issues and their mapped JIRA tickets.
+var diffCmd = &cobra.Command{
+	Use:   "diff",
+	Short: "Show differences between GitHub issues and their JIRA tickets",
+	Long: `Compare each GitHub issue with its mapped JIRA ticket without changing anything.
+
+For every synced issue (one with a '[KEY-123]' title prefix), the following fields are compared:
+- title: the GitHub title without the JIRA prefix against the JIRA summary
+- status: closed GitHub issues against JIRA tickets that are not 'Done'
+- description: a hash of the GitHub body against a hash of the JIRA description
+- links: child issues in a feature's '## Issues' section against the JIRA links
+
+Differences the next 'glue jira' run would fix are highlighted. Description
+differences are fixed by the next 'glue jira --sync-descriptions' run.
+
+Example:
+  glue diff -r owner/repo -b PROJ`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		issuesByBoard, err := fetchIssuesByBoard(cmd.Context(), githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		inSync, drifted, fixable := 0, 0, 0
+		seen := make(map[int]bool)
+
+		for _, board := range boards {
+			issues := issuesByBoard[board]
+			githubToJira := buildGitHubToJiraMap(issues, nil)
+
+			for _, issue := range issues {
+				jiraKey := marker.GitHub.Key(issue.Title)
+				if jiraKey == "" || seen[issue.Number] {
+					continue
+				}
+				seen[issue.Number] = true
+
+				ticket, err := jiraClient.GetTicket(jiraKey)
+				if broken, ok := brokenMappingOf(jiraKey, ticket, err); ok {
+					fmt.Fprintf(out, "#%d -> %s\n  broken: %s\n", issue.Number, jiraKey, brokenRemedy(broken, issue.Number))
+					drifted++
+					continue
+				}
+				if err != nil {
+					logging.Error("failed to get jira ticket",
+						"issue_number", issue.Number,
+						"jira_ticket", jiraKey,
+						"error", err)
+					fmt.Fprintf(out, "#%d -> %s\n  error: %v\n", issue.Number, jiraKey, err)
+					drifted++
+					continue
+				}
+
+				var expected []string
+				var existing map[string]bool
+				if issueTypeOf(issue) == "feature" {
+					expected = expectedChildKeys(issue, githubToJira, cfg.GitHub.Domain)
+					if expected != nil {
+						existing, err = jiraClient.GetIssueLinks(jiraKey)
+						if err != nil {
+							logging.Warn("failed to get jira links",
+								"jira_ticket", jiraKey,
+								"error", err)
+						}
+					}
+				}
+
+				// Compare the description glue would write, without mapped form sections
+				issue.Description = jiraClient.DescriptionFor(issue)
+				diffs := diffIssue(issue, ticket, expected, existing)
+				if len(diffs) == 0 {
+					inSync++
+					continue
+				}
+
+				drifted++
+				fmt.Fprintf(out, "#%d %s -> %s\n", issue.Number, stripJiraPrefix(issue.Title), jiraKey)
+				for _, d := range diffs {
+					marker := " "
+					if d.Fixable {
+						marker = "*"
+						fixable++
+					}
+					fmt.Fprintf(out, "  %s %s: github=%q jira=%q (%s)\n", marker, d.Field, d.GitHub, d.Jira, d.Note)
+				}
+			}
+		}
+
+		fmt.Fprintf(out, "\n%d in sync, %d drifted, %d differences fixable by the next sync (marked *)\n",
+			inSync, drifted, fixable)
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to compare (can be specified multiple times)")
+}
+
+// brokenMappingOf returns the broken mapping to the ticket with key, if
+// fetching it failed because it was deleted or answered under another key
+// because it was moved.
+func brokenMappingOf(key string, ticket models.JiraTicket, err error) (mappings.Broken, bool) {
+	switch {
+	case errors.Is(err, jira.ErrNotFound):
+		return mappings.Broken{Key: key, Reason: mappings.Deleted}, true
+	case err == nil && ticket.Key != "" && !strings.EqualFold(ticket.Key, key):
+		return mappings.Broken{Key: key, Reason: mappings.Moved, MovedTo: ticket.Key}, true
+	}
+	return mappings.Broken{}, false
+}
+
+// expectedChildKeys returns the JIRA keys of the synced child issues listed in
+// a feature's "## Issues" section. It returns nil if the feature lists no
+// children, in which case the sync leaves the feature's links untouched.
+func expectedChildKeys(feature models.GitHubIssue, githubToJira map[int]string, gitHubDomain string) []string {
+	childNums := parseChildIssues(feature.Description, gitHubDomain)
+	if len(childNums) == 0 {
+		return nil
+	}
+
+	keys := []string{}
+	for _, num := range childNums {
+		if key, ok := githubToJira[num]; ok {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
+
+// diffIssue compares a GitHub issue with its JIRA ticket. Links are only
+// compared when expectedChildren is non-nil, mirroring the sync which leaves
+// links alone for features without listed children.
+func diffIssue(issue models.GitHubIssue, ticket models.JiraTicket, expectedChildren []string, existingLinks map[string]bool) []fieldDiff {
+	var diffs []fieldDiff
+
+	title := stripJiraPrefix(issue.Title)
+	if strings.TrimSpace(title) != strings.TrimSpace(ticket.Title) {
+		diffs = append(diffs, fieldDiff{
+			Field:  "title",
+			GitHub: title,
+			Jira:   ticket.Title,
+			Note:   "titles are not synced after creation",
+		})
+	}
+
+	if issue.State == "closed" && ticket.StatusCategory != jira.StatusCategoryDone {
+		diffs = append(diffs, fieldDiff{
+			Field:   "status",
+			GitHub:  issue.State,
+			Jira:    ticket.Status,
+			Fixable: true,
+			Note:    "next sync closes the ticket",
+		})
+	} else if issue.State == "open" && ticket.StatusCategory == jira.StatusCategoryDone {
+		diffs = append(diffs, fieldDiff{
+			Field:  "status",
+			GitHub: issue.State,
+			Jira:   ticket.Status,
+			Note:   "tickets are not reopened by glue",
+		})
+	}
+
+	// JIRA notes sections are maintained in JIRA and don't count as drift
+	githubHash, jiraHash := contentHash(jira.SyncedDescription(issue.Description)), contentHash(jira.SyncedDescription(ticket.Description))
+	if githubHash != jiraHash {
+		diffs = append(diffs, fieldDiff{
+			Field:  "description",
+			GitHub: githubHash,
+			Jira:   jiraHash,
+			Note:   "next 'glue jira --sync-descriptions' run updates the description",
+		})
+	}
+
+	if expectedChildren != nil {
+		want := make(map[string]bool)
+		for _, key := range expectedChildren {
+			want[key] = true
+		}
+
+		var missing, extra []string
+		for key := range want {
+			if !existingLinks[key] {
+				missing = append(missing, key)
+			}
+		}
+		for key := range existingLinks {
+			if !want[key] {
+				extra = append(extra, key)
+			}
+		}
+		sort.Strings(missing)
+		sort.Strings(extra)
+
+		if len(missing) > 0 {
+			diffs = append(diffs, fieldDiff{
+				Field:   "links",
+				GitHub:  strings.Join(missing, ","),
+				Fixable: true,
+				Note:    "next sync creates the missing links",
+			})
+		}
+		if len(extra) > 0 {
+			diffs = append(diffs, fieldDiff{
+				Field:   "links",
+				Jira:    strings.Join(extra, ","),
+				Fixable: true,
+				Note:    "next sync removes links not listed in '## Issues'",
+			})
+		}
+	}
+
+	return diffs
+}
+
+// stripJiraPrefix removes a leading "[KEY-123] " prefix from a GitHub issue title.
+func stripJiraPrefix(title string) string {
+	key := marker.GitHub.Key(title)
+	if key == "" {
+		return title
+	}
+	return strings.TrimSpace(strings.TrimPrefix(title, "["+key+"]"))
+}
+
+// contentHash returns a short hash of text with line endings and surrounding
+// whitespace normalized, so cosmetic differences don't count as drift.
+func contentHash(text string) string {
+	normalized := strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n"))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])[:12]
+}
@@ -0,0 +1,97 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiffIssue(t *testing.T) {
+	tests := []struct {
+		name       string
+		issue      models.GitHubIssue
+		ticket     models.JiraTicket
+		expected   []string
+		existing   map[string]bool
+		wantFields []string
+		wantFix    []bool
+	}{
+		{
+			name:   "In sync",
+			issue:  models.GitHubIssue{Title: "[PROJ-1] Title", Description: "Body\r\n", State: "open"},
+			ticket: models.JiraTicket{Title: "Title", Description: "Body", Status: "To Do"},
+		},
+		{
+			name:       "Closed issue with open ticket",
+			issue:      models.GitHubIssue{Title: "[PROJ-1] Title", Description: "Body", State: "closed"},
+			ticket:     models.JiraTicket{Title: "Title", Description: "Body", Status: "In Progress"},
+			wantFields: []string{"status"},
+			wantFix:    []bool{true},
+		},
+		{
+			name:   "Closed issue with ticket in a renamed done status",
+			issue:  models.GitHubIssue{Title: "[PROJ-1] Title", Description: "Body", State: "closed"},
+			ticket: models.JiraTicket{Title: "Title", Description: "Body", Status: "Released", StatusCategory: "done"},
+		},
+		{
+			name:       "Open issue with done ticket",
+			issue:      models.GitHubIssue{Title: "[PROJ-1] Title", Description: "Body", State: "open"},
+			ticket:     models.JiraTicket{Title: "Title", Description: "Body", Status: "Closed", StatusCategory: "done"},
+			wantFields: []string{"status"},
+			wantFix:    []bool{false},
+		},
+		{
+			name:       "Edited title and body",
+			issue:      models.GitHubIssue{Title: "[PROJ-1] New title", Description: "New body", State: "open"},
+			ticket:     models.JiraTicket{Title: "Title", Description: "Body", Status: "To Do"},
+			wantFields: []string{"title", "description"},
+			wantFix:    []bool{false, false},
+		},
+		{
+			name:       "Missing and extra links",
+			issue:      models.GitHubIssue{Title: "[PROJ-1] Feature", State: "open"},
+			ticket:     models.JiraTicket{Title: "Feature", Status: "To Do"},
+			expected:   []string{"PROJ-2", "PROJ-3"},
+			existing:   map[string]bool{"PROJ-2": true, "PROJ-9": true},
+			wantFields: []string{"links", "links"},
+			wantFix:    []bool{true, true},
+		},
+		{
+			name:       "Listed children not synced yet",
+			issue:      models.GitHubIssue{Title: "[PROJ-1] Feature", State: "open"},
+			ticket:     models.JiraTicket{Title: "Feature", Status: "To Do"},
+			expected:   []string{},
+			existing:   map[string]bool{"PROJ-9": true},
+			wantFields: []string{"links"},
+			wantFix:    []bool{true},
+		},
+	}
+
+	t.Run("description note names --sync-descriptions", func(t *testing.T) {
+		diffs := diffIssue(models.GitHubIssue{Title: "[PROJ-1] Title", Description: "New body", State: "open"},
+			models.JiraTicket{Title: "Title", Description: "Body", Status: "To Do"}, nil, nil)
+		require.Len(t, diffs, 1)
+		assert.Contains(t, diffs[0].Note, "--sync-descriptions")
+	})
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diffs := diffIssue(tt.issue, tt.ticket, tt.expected, tt.existing)
+			var fields []string
+			var fix []bool
+			for _, d := range diffs {
+				fields = append(fields, d.Field)
+				fix = append(fix, d.Fixable)
+			}
+			assert.Equal(t, tt.wantFields, fields)
+			assert.Equal(t, tt.wantFix, fix)
+		})
+	}
+}
+
+func TestStripJiraPrefix(t *testing.T) {
+	assert.Equal(t, "Title", stripJiraPrefix("[PROJ-12] Title"))
+	assert.Equal(t, "Title", stripJiraPrefix("Title"))
+}
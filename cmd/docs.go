@@ -0,0 +1,226 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// docsCmd groups the documentation commands.
+var docsCmd = &cobra.Command{
+	Use:   "docs",
+	Short: "Generate documentation for glue",
+}
+
+// docsGenerateCmd writes man pages, a Markdown reference and shell completions.
+var docsGenerateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Write man pages, a Markdown CLI reference and shell completions",
+	Long: `Write the documentation shipped with glue packages into a directory, generated
+from the commands and flags glue actually has:
+
+  man/          a man page per command (glue.1, glue-jira.1, ...)
+  markdown/     a Markdown reference page per command (glue.md, glue_jira.md, ...)
+  completions/  completion scripts for bash, zsh, fish and PowerShell
+
+The output doesn't depend on the date or machine, so packaging (e.g. a Homebrew
+formula or Scoop manifest) can regenerate it on every release and only see
+changes when the CLI changed.
+
+Example:
+  glue docs generate --dir dist/docs`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dir, err := cmd.Flags().GetString("dir")
+		if err != nil {
+			return err
+		}
+
+		files, err := generateDocs(cmd.Root(), dir)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.OutOrStdout(), "wrote %d files to %s\n", files, dir)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(docsCmd)
+	docsCmd.AddCommand(docsGenerateCmd)
+	docsGenerateCmd.Flags().String("dir", "docs", "Directory to write the documentation to")
+}
+
+// generateDocs writes the documentation for root and its commands into dir
+// and returns the number of files written.
+func generateDocs(root *cobra.Command, dir string) (int, error) {
+	files := make(map[string][]byte)
+
+	for _, c := range documentedCommands(root) {
+		base := strings.ReplaceAll(c.CommandPath(), " ", "-")
+		files[filepath.Join("man", base+".1")] = manPage(c)
+		files[filepath.Join("markdown", strings.ReplaceAll(c.CommandPath(), " ", "_")+".md")] = markdownPage(c)
+	}
+
+	completions := []struct {
+		name string
+		gen  func(*bytes.Buffer) error
+	}{
+		{"glue.bash", func(b *bytes.Buffer) error { return root.GenBashCompletionV2(b, true) }},
+		{"_glue", func(b *bytes.Buffer) error { return root.GenZshCompletion(b) }},
+		{"glue.fish", func(b *bytes.Buffer) error { return root.GenFishCompletion(b, true) }},
+		{"glue.ps1", func(b *bytes.Buffer) error { return root.GenPowerShellCompletionWithDesc(b) }},
+	}
+	for _, completion := range completions {
+		var b bytes.Buffer
+		if err := completion.gen(&b); err != nil {
+			return 0, fmt.Errorf("failed to generate %s: %v", completion.name, err)
+		}
+		files[filepath.Join("completions", completion.name)] = b.Bytes()
+	}
+
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return 0, fmt.Errorf("failed to create directory: %v", err)
+		}
+		if err := os.WriteFile(path, content, 0o644); err != nil {
+			return 0, fmt.Errorf("failed to write %s: %v", path, err)
+		}
+	}
+	return len(files), nil
+}
+
+// documentedCommands returns root and all its available subcommands, sorted
+// by command path.
+func documentedCommands(root *cobra.Command) []*cobra.Command {
+	commands := []*cobra.Command{root}
+	for _, c := range root.Commands() {
+		if !c.IsAvailableCommand() || c.IsAdditionalHelpTopicCommand() {
+			continue
+		}
+		commands = append(commands, documentedCommands(c)...)
+	}
+	sort.Slice(commands, func(i, j int) bool {
+		return commands[i].CommandPath() < commands[j].CommandPath()
+	})
+	return commands
+}
+
+// description returns the long description of a command, or the short one.
+func description(c *cobra.Command) string {
+	if c.Long != "" {
+		return c.Long
+	}
+	return c.Short
+}
+
+// seeAlso returns the parent and the available subcommands of c.
+func seeAlso(c *cobra.Command) []*cobra.Command {
+	var related []*cobra.Command
+	if c.HasParent() {
+		related = append(related, c.Parent())
+	}
+	for _, sub := range c.Commands() {
+		if sub.IsAvailableCommand() && !sub.IsAdditionalHelpTopicCommand() {
+			related = append(related, sub)
+		}
+	}
+	return related
+}
+
+// markdownPage renders the Markdown reference page of a command.
+func markdownPage(c *cobra.Command) []byte {
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "## %s\n\n%s\n\n", c.CommandPath(), c.Short)
+	fmt.Fprintf(&b, "### Synopsis\n\n%s\n\n", description(c))
+	if c.Runnable() {
+		fmt.Fprintf(&b, "```\n%s\n```\n\n", c.UseLine())
+	}
+	if c.Example != "" {
+		fmt.Fprintf(&b, "### Examples\n\n```\n%s\n```\n\n", c.Example)
+	}
+	if flags := c.NonInheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&b, "### Options\n\n```\n%s```\n\n", flags.FlagUsages())
+	}
+	if flags := c.InheritedFlags(); flags.HasAvailableFlags() {
+		fmt.Fprintf(&b, "### Options inherited from parent commands\n\n```\n%s```\n\n", flags.FlagUsages())
+	}
+	if related := seeAlso(c); len(related) > 0 {
+		b.WriteString("### SEE ALSO\n\n")
+		for _, r := range related {
+			fmt.Fprintf(&b, "* [%s](%s.md)\t - %s\n", r.CommandPath(), strings.ReplaceAll(r.CommandPath(), " ", "_"), r.Short)
+		}
+	}
+	return b.Bytes()
+}
+
+// manPage renders the man page of a command in roff.
+func manPage(c *cobra.Command) []byte {
+	name := strings.ReplaceAll(c.CommandPath(), " ", "-")
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, ".TH \"%s\" \"1\" \"\" \"glue\" \"Glue Manual\"\n", strings.ToUpper(name))
+	fmt.Fprintf(&b, ".SH NAME\n%s \\- %s\n", name, roffEscape(c.Short))
+	fmt.Fprintf(&b, ".SH SYNOPSIS\n\\fB%s\\fP\n", roffEscape(c.UseLine()))
+	fmt.Fprintf(&b, ".SH DESCRIPTION\n.nf\n%s\n.fi\n", roffText(description(c)))
+	if c.Example != "" {
+		fmt.Fprintf(&b, ".SH EXAMPLES\n.nf\n%s\n.fi\n", roffText(c.Example))
+	}
+	writeManFlags(&b, "OPTIONS", c.NonInheritedFlags())
+	writeManFlags(&b, "OPTIONS INHERITED FROM PARENT COMMANDS", c.InheritedFlags())
+	if related := seeAlso(c); len(related) > 0 {
+		names := make([]string, 0, len(related))
+		for _, r := range related {
+			names = append(names, fmt.Sprintf("\\fB%s\\fP(1)", strings.ReplaceAll(r.CommandPath(), " ", "-")))
+		}
+		fmt.Fprintf(&b, ".SH SEE ALSO\n%s\n", strings.Join(names, ", "))
+	}
+	return b.Bytes()
+}
+
+// writeManFlags renders a man page section listing flags.
+func writeManFlags(b *bytes.Buffer, section string, flags *pflag.FlagSet) {
+	if !flags.HasAvailableFlags() {
+		return
+	}
+	fmt.Fprintf(b, ".SH %s\n", section)
+	flags.VisitAll(func(f *pflag.Flag) {
+		if f.Hidden {
+			return
+		}
+		var names string
+		if f.Shorthand != "" {
+			names = fmt.Sprintf("\\fB\\-%s\\fP, ", f.Shorthand)
+		}
+		names += fmt.Sprintf("\\fB\\-\\-%s\\fP", roffEscape(f.Name))
+		if f.Value.Type() != "bool" {
+			names += fmt.Sprintf("=%s", roffEscape(f.DefValue))
+		}
+		fmt.Fprintf(b, ".TP\n%s\n%s\n", names, roffText(f.Usage))
+	})
+}
+
+// roffEscape escapes backslashes and dashes for roff.
+func roffEscape(s string) string {
+	return strings.NewReplacer(`\`, `\e`, "-", `\-`).Replace(s)
+}
+
+// roffText escapes multi-line text for roff, protecting lines that would
+// otherwise be read as requests.
+func roffText(s string) string {
+	lines := strings.Split(roffEscape(s), "\n")
+	for i, line := range lines {
+		if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+			lines[i] = `\&` + line
+		}
+	}
+	return strings.Join(lines, "\n")
+}
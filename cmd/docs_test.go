@@ -0,0 +1,62 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newDocsTestCommand() *cobra.Command {
+	root := &cobra.Command{Use: "glue", Short: "Glue synchronizes issues"}
+	root.PersistentFlags().StringP("repository", "r", "", "GitHub repository")
+	sync := &cobra.Command{
+		Use:   "jira",
+		Short: "Sync with JIRA",
+		Long:  "Sync issues.\n.starts with a dot",
+		RunE:  func(cmd *cobra.Command, args []string) error { return nil },
+	}
+	sync.Flags().StringArrayP("board", "b", []string{}, "JIRA board")
+	sync.Flags().Bool("sync-descriptions", false, "Update descriptions")
+	hidden := &cobra.Command{Use: "secret", Hidden: true, RunE: sync.RunE}
+	root.AddCommand(sync, hidden)
+	return root
+}
+
+func TestGenerateDocs(t *testing.T) {
+	dir := t.TempDir()
+
+	files, err := generateDocs(newDocsTestCommand(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, 8, files)
+
+	for _, name := range []string{"man/glue.1", "markdown/glue.md", "completions/glue.bash", "completions/_glue", "completions/glue.fish", "completions/glue.ps1"} {
+		assert.FileExists(t, filepath.Join(dir, name))
+	}
+	assert.NoFileExists(t, filepath.Join(dir, "man", "glue-secret.1"))
+
+	markdown, err := os.ReadFile(filepath.Join(dir, "markdown", "glue_jira.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(markdown), "## glue jira\n\nSync with JIRA\n")
+	assert.Contains(t, string(markdown), "```\nglue jira [flags]\n```")
+	assert.Contains(t, string(markdown), "-b, --board stringArray")
+	assert.Contains(t, string(markdown), "### Options inherited from parent commands")
+	assert.Contains(t, string(markdown), "* [glue](glue.md)\t - Glue synchronizes issues")
+
+	man, err := os.ReadFile(filepath.Join(dir, "man", "glue-jira.1"))
+	require.NoError(t, err)
+	assert.Contains(t, string(man), `.TH "GLUE-JIRA" "1" "" "glue" "Glue Manual"`)
+	assert.Contains(t, string(man), "\\&.starts with a dot")
+	assert.Contains(t, string(man), ".TP\n\\fB\\-b\\fP, \\fB\\-\\-board\\fP=[]\nJIRA board\n")
+	assert.Contains(t, string(man), ".TP\n\\fB\\-\\-sync\\-descriptions\\fP\nUpdate descriptions\n")
+
+	// Regenerating gives the same output
+	_, err = generateDocs(newDocsTestCommand(), dir)
+	require.NoError(t, err)
+	again, err := os.ReadFile(filepath.Join(dir, "man", "glue-jira.1"))
+	require.NoError(t, err)
+	assert.Equal(t, man, again)
+}
@@ -0,0 +1,375 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/frontmatter"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// projectKeyPattern matches labels that look like JIRA project keys (e.g. "PROJ").
+var projectKeyPattern = regexp.MustCompile(`^[A-Z][A-Z0-9]+$`)
+
+// explainCmd groups commands that explain how glue treats GitHub objects.
+var explainCmd = &cobra.Command{
+	Use:   "explain",
+	Short: "Explain how glue treats GitHub issues",
+	Long:  `Explain the decisions glue makes for GitHub issues without changing anything.`,
+}
+
+// explainIssueCmd prints why a single GitHub issue was or wasn't synchronized.
+var explainIssueCmd = &cobra.Command{
+	Use:   "issue <number>",
+	Short: "Explain why a GitHub issue was or wasn't synced to JIRA",
+	Long: `Explain why a GitHub issue was or wasn't synchronized with JIRA.
+
+The command evaluates the issue the same way 'glue jira' does and prints:
+- label evaluation and board routing
+- the detected JIRA key
+- the issue type mapping decision
+- hierarchy membership (parent features and child issues)
+- the current state of the mapped JIRA ticket
+
+The verdict applies the same skips as the sync: locked issues, issues
+created outside --min-age and --max-age, and issues the rules script or the
+issue's front matter skip.
+
+If no boards are given, labels that look like JIRA project keys and
+'jira-project: KEY' labels are used.
+
+Example:
+  glue explain issue 42 -r owner/repo -b PROJ`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateFlags(flagRules{Repository: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		number, err := strconv.Atoi(strings.TrimPrefix(args[0], "#"))
+		if err != nil {
+			return fmt.Errorf("invalid issue number: %s", args[0])
+		}
+
+		window, err := ageWindowFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		engine, err := loadRules(cfg.Rules)
+		if err != nil {
+			return err
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		issue, err := githubClient.GetIssue(cmd.Context(), repository, number)
+		if err != nil {
+			return fmt.Errorf("failed to fetch github issue: %v", err)
+		}
+
+		inferred := false
+		if len(boards) == 0 {
+			boards = inferBoards(issue.Labels, cfg.BoardAliases)
+			if routed := routedBoard(issue, cfg.Routes); routed != "" && !hasLabel(boards, routed) {
+				boards = append(boards, routed)
+			}
+			inferred = true
+		}
+
+		// Collect the other issues on the same boards to evaluate hierarchy membership
+		var related []models.GitHubIssue
+		if len(boards) > 0 {
+			open, err := githubClient.GetIssuesWithLabels(cmd.Context(), repository, append(boardLabels(boards, cfg.BoardAliases), routeLabels(cfg.Routes, boards)...))
+			if err != nil {
+				logging.Warn("failed to fetch open issues for hierarchy evaluation", "error", err)
+			}
+			related = append(related, open...)
+			related = append(related, fetchClosedIssuesForBoards(cmd.Context(), githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)...)
+		}
+		typed := append([]models.GitHubIssue{issue}, related...)
+		applyIssueTypes(cmd.Context(), githubClient, repository, typed)
+
+		// Decide as the sync does, with the front matter removed from the bodies
+		byBoard := map[string][]models.GitHubIssue{"": typed}
+		frontMatter := parseFrontMatter(byBoard)
+		skips := issueSkips{
+			decisions:   applyFrontMatter(evaluateRules(engine, byBoard), frontMatter, boards),
+			frontMatter: frontMatter,
+			window:      window,
+			now:         time.Now(),
+		}
+		issue, related = typed[0], typed[1:]
+
+		out := cmd.OutOrStdout()
+		for _, line := range explainIssue(issue, boards, inferred, related, cfg.GitHub.Domain, cfg.Routes, cfg.BoardAliases, cfg.Jira.MultipleParents, skips) {
+			fmt.Fprintln(out, line)
+		}
+
+		// The JIRA ticket state is best effort; credentials may not be configured
+		if jiraKey := marker.GitHub.Key(issue.Title); jiraKey != "" {
+			fmt.Fprintln(out, "")
+			jiraClient, err := jira.NewClient()
+			if err != nil {
+				fmt.Fprintf(out, "JIRA ticket: %s (state unavailable: %v)\n", jiraKey, err)
+				return nil
+			}
+			status, err := jiraClient.GetTicketStatus(jiraKey)
+			if err != nil {
+				fmt.Fprintf(out, "JIRA ticket: %s (state unavailable: %v)\n", jiraKey, err)
+				return nil
+			}
+			fmt.Fprintf(out, "JIRA ticket: %s is %q\n", jiraKey, status)
+		}
+
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(explainCmd)
+	explainCmd.AddCommand(explainIssueCmd)
+	explainIssueCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to evaluate routing against (can be specified multiple times)")
+	explainIssueCmd.Flags().String("min-age", "", "Evaluate as a sync with this --min-age, e.g. 30d")
+	explainIssueCmd.Flags().String("max-age", "", "Evaluate as a sync with this --max-age, e.g. 365d")
+}
+
+// issueSkips holds what the sync decides to skip issues by, besides their
+// boards and types.
+type issueSkips struct {
+	// decisions are the rules decisions with the front matter applied
+	decisions map[int]rules.Decision
+	// frontMatter are the front matter overrides of the issues that have it
+	frontMatter map[int]frontmatter.Overrides
+	// window is the age window of the sync
+	window ageWindow
+	// now is when the sync runs
+	now time.Time
+}
+
+// reason returns why the sync creates no ticket for an issue, in the order
+// the sync checks, or an empty string if nothing skips it. Issues with a
+// ticket are only skipped while locked.
+func (s issueSkips) reason(issue models.GitHubIssue) string {
+	switch {
+	case skippedAsLocked(issue):
+		return "the issue is locked; unlock it to sync"
+	case marker.GitHub.Key(issue.Title) != "":
+		return ""
+	case s.window.skips(issue, s.now):
+		return "created outside the age window of --min-age and --max-age"
+	case s.frontMatter[issue.Number].Skip:
+		return "its front matter skips it, or is invalid"
+	case s.decisions[issue.Number].Skip:
+		return "the rules script skips it, or failed to evaluate"
+	}
+	return ""
+}
+
+// inferBoards returns the issue labels that look like JIRA project keys or
+// are board aliases, including keys from "jira-project: KEY" labels. Aliases
+// are resolved.
+func inferBoards(labels []string, aliases config.BoardAliases) []string {
+	var boards []string
+	for _, label := range labels {
+		if key := aliases.Resolve(label); key != label {
+			if !hasLabel(boards, key) {
+				boards = append(boards, key)
+			}
+		} else if projectKeyPattern.MatchString(label) {
+			boards = append(boards, label)
+		}
+	}
+	for _, key := range parseBoardLabels(labels, aliases) {
+		if !hasLabel(boards, key) {
+			boards = append(boards, key)
+		}
+	}
+	return boards
+}
+
+// explainIssue builds a human-readable explanation of how the sync treats an
+// issue. The related issues are used to determine hierarchy membership.
+func explainIssue(issue models.GitHubIssue, boards []string, boardsInferred bool, related []models.GitHubIssue, gitHubDomain string, routes []config.Route, aliases config.BoardAliases, multipleParents string, skips issueSkips) []string {
+	var lines []string
+	add := func(format string, args ...any) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	}
+
+	add("Issue #%d: %s", issue.Number, issue.Title)
+	if issue.State != "" {
+		add("State: %s", issue.State)
+	}
+
+	// Label evaluation
+	if len(issue.Labels) == 0 {
+		add("Labels: (none)")
+	} else {
+		add("Labels: %s", strings.Join(issue.Labels, ", "))
+	}
+
+	// Board routing
+	var matchedBoards []string
+	switch {
+	case len(boards) == 0:
+		add("Board routing: no boards given and no labels look like JIRA project keys")
+	default:
+		source := "given"
+		if boardsInferred {
+			source = "inferred from labels"
+		}
+		add("Board routing (%s):", source)
+		routed := routedBoard(issue, routes)
+		decided := skips.decisions[issue.Number].Boards
+		for _, board := range boards {
+			switch {
+			case decided != nil && hasLabel(decided, board):
+				matchedBoards = append(matchedBoards, board)
+				add("  %s: matched by %s", board, decisionSource(skips, issue.Number, "board"))
+			case decided != nil:
+				add("  %s: %s sends the issue to %s", board, decisionSource(skips, issue.Number, "board"), strings.Join(decided, ", "))
+			case routed != "" && strings.EqualFold(routed, board):
+				matchedBoards = append(matchedBoards, board)
+				add("  %s: matched by route", board)
+			case routed != "":
+				add("  %s: a route sends the issue to %s", board, routed)
+			case hasBoardLabel(issue.Labels, board, aliases):
+				matchedBoards = append(matchedBoards, board)
+				add("  %s: matched by label", board)
+			default:
+				add("  %s: no '%s' or '%s %s' label", board, board, boardLabelPrefix, board)
+			}
+		}
+	}
+
+	// Detected JIRA key
+	jiraKey := marker.GitHub.Key(issue.Title)
+	if jiraKey != "" {
+		add("JIRA key: %s (from title prefix)", jiraKey)
+	} else {
+		add("JIRA key: none (title has no '[KEY-123]' prefix)")
+	}
+
+	// Type mapping decision
+	issueType := issueTypeFor(issue, skips.decisions)
+	switch {
+	case skips.decisions[issue.Number].Type != "" && issueType == "feature":
+		add("Type mapping: %s -> JIRA Feature", decisionSource(skips, issue.Number, "type"))
+	case skips.decisions[issue.Number].Type != "":
+		add("Type mapping: %s -> JIRA Story", decisionSource(skips, issue.Number, "type"))
+	case issue.Type != "" && issueType == "feature":
+		add("Type mapping: native type '%s' -> JIRA Feature", issue.Type)
+	case issue.Type != "":
+		add("Type mapping: native type '%s' -> JIRA Story", issue.Type)
+	case issueType == "feature":
+		add("Type mapping: 'feature' label -> JIRA Feature")
+	case issueType == "story":
+		add("Type mapping: 'story' label -> JIRA Story")
+	default:
+		add("Type mapping: none (no 'feature' or 'story' label)")
+	}
+
+	// Hierarchy membership
+	githubToJira := buildGitHubToJiraMap(append([]models.GitHubIssue{issue}, related...), nil)
+	describe := func(number int) string {
+		if key, ok := githubToJira[number]; ok {
+			return fmt.Sprintf("#%d (%s)", number, key)
+		}
+		return fmt.Sprintf("#%d (not synced)", number)
+	}
+
+	var parents []string
+	var parentNumbers []int
+	seen := make(map[int]bool)
+	for _, other := range related {
+		if other.Number == issue.Number || seen[other.Number] || issueTypeOf(other) != "feature" {
+			continue
+		}
+		seen[other.Number] = true
+		for _, child := range parseChildIssues(other.Description, gitHubDomain) {
+			if child == issue.Number {
+				parents = append(parents, describe(other.Number))
+				parentNumbers = append(parentNumbers, other.Number)
+				break
+			}
+		}
+	}
+	sort.Strings(parents)
+	sort.Ints(parentNumbers)
+
+	var children []string
+	if issueType == "feature" {
+		for _, child := range parseChildIssues(issue.Description, gitHubDomain) {
+			children = append(children, describe(child))
+		}
+	}
+
+	if len(parents) == 0 && len(children) == 0 {
+		add("Hierarchy: not part of any feature hierarchy")
+	} else {
+		add("Hierarchy:")
+		if len(parents) > 0 {
+			add("  child of: %s", strings.Join(parents, ", "))
+		}
+		if len(parentNumbers) > 1 {
+			add("  multiple parents: %s", describeMultipleParents(parentNumbers, multipleParents))
+		}
+		if len(children) > 0 {
+			add("  parent of: %s", strings.Join(children, ", "))
+		}
+	}
+
+	// Verdict
+	reason := skips.reason(issue)
+	switch {
+	case reason != "":
+		add("Verdict: skipped, %s", reason)
+	case jiraKey != "":
+		add("Verdict: already synced as %s; only links and status are maintained", jiraKey)
+	case len(matchedBoards) == 0:
+		add("Verdict: skipped, no board label matched")
+	case issueType == "":
+		add("Verdict: skipped, needs a 'feature' or 'story' label")
+	default:
+		add("Verdict: will be created in %s on the next sync", strings.Join(matchedBoards, ", "))
+	}
+
+	return lines
+}
+
+// decisionSource names what decided the board or type of an issue: its
+// front matter or the rules script.
+func decisionSource(skips issueSkips, number int, field string) string {
+	o := skips.frontMatter[number]
+	if field == "board" && o.Board != "" || field == "type" && o.Type != "" {
+		return "front matter"
+	}
+	return "rules script"
+}
@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/frontmatter"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplainIssue(t *testing.T) {
+	feature := models.GitHubIssue{
+		Number:      1,
+		Title:       "[PROJ-10] Parent feature",
+		Description: "## Issues\n- https://github.com/org/repo/issues/2\n- https://github.com/org/repo/issues/3",
+		Labels:      []string{"feature", "PROJ"},
+	}
+	syncedStory := models.GitHubIssue{
+		Number: 3,
+		Title:  "[PROJ-11] Synced story",
+		Labels: []string{"story", "PROJ"},
+	}
+
+	tests := []struct {
+		name     string
+		issue    models.GitHubIssue
+		boards   []string
+		routes   []config.Route
+		related  []models.GitHubIssue
+		skips    issueSkips
+		contains []string
+	}{
+		{
+			name:    "Unsynced story with parent",
+			issue:   models.GitHubIssue{Number: 2, Title: "New story", State: "open", Labels: []string{"story", "PROJ"}},
+			boards:  []string{"PROJ", "OTHER"},
+			related: []models.GitHubIssue{feature, syncedStory},
+			contains: []string{
+				"PROJ: matched by label",
+				"OTHER: no 'OTHER' or 'jira-project: OTHER' label",
+				"JIRA key: none",
+				"'story' label -> JIRA Story",
+				"child of: #1 (PROJ-10)",
+				"Verdict: will be created in PROJ",
+			},
+		},
+		{
+			name:   "Story listed by two features",
+			issue:  syncedStory,
+			boards: []string{"PROJ"},
+			related: []models.GitHubIssue{feature, {
+				Number:      8,
+				Title:       "[PROJ-12] Other feature",
+				Description: "## Issues\n- https://github.com/org/repo/issues/3",
+				Labels:      []string{"feature", "PROJ"},
+			}},
+			contains: []string{
+				"child of: #1 (PROJ-10), #8 (PROJ-12)",
+				"multiple parents: linked to #1 only (first-wins)",
+			},
+		},
+		{
+			name:    "Synced feature lists children",
+			issue:   feature,
+			boards:  []string{"PROJ"},
+			related: []models.GitHubIssue{syncedStory},
+			contains: []string{
+				"JIRA key: PROJ-10 (from title prefix)",
+				"parent of: #2 (not synced), #3 (PROJ-11)",
+				"Verdict: already synced as PROJ-10",
+			},
+		},
+		{
+			name:   "Missing type label",
+			issue:  models.GitHubIssue{Number: 4, Title: "Untyped", Labels: []string{"PROJ"}},
+			boards: []string{"PROJ"},
+			contains: []string{
+				"Type mapping: none",
+				"Hierarchy: not part of any feature hierarchy",
+				"Verdict: skipped, needs a 'feature' or 'story' label",
+			},
+		},
+		{
+			name:   "Native type wins over labels",
+			issue:  models.GitHubIssue{Number: 6, Title: "Crash", Labels: []string{"PROJ", "feature"}, Type: "Bug"},
+			boards: []string{"PROJ"},
+			contains: []string{
+				"Type mapping: native type 'Bug' -> JIRA Story",
+				"Verdict: will be created in PROJ",
+			},
+		},
+		{
+			name:   "Route wins over labels",
+			issue:  models.GitHubIssue{Number: 7, Title: "Refund fails", Labels: []string{"story", "PROJ", "area:payments"}},
+			boards: []string{"PROJ", "PAY"},
+			routes: []config.Route{{Board: "PAY", Labels: []string{"area:payments"}}},
+			contains: []string{
+				"PROJ: a route sends the issue to PAY",
+				"PAY: matched by route",
+				"Verdict: will be created in PAY",
+			},
+		},
+		{
+			name:   "No board label",
+			issue:  models.GitHubIssue{Number: 5, Title: "Elsewhere", Labels: []string{"story"}},
+			boards: []string{"PROJ"},
+			contains: []string{
+				"Verdict: skipped, no board label matched",
+			},
+		},
+		{
+			name:   "Locked issue",
+			issue:  models.GitHubIssue{Number: 6, Title: "Locked story", State: "open", Locked: true, Labels: []string{"story", "PROJ"}},
+			boards: []string{"PROJ"},
+			contains: []string{
+				"PROJ: matched by label",
+				"Verdict: skipped, the issue is locked",
+			},
+		},
+		{
+			name:   "Outside the age window",
+			issue:  models.GitHubIssue{Number: 7, Title: "Old story", CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC), Labels: []string{"story", "PROJ"}},
+			boards: []string{"PROJ"},
+			skips:  issueSkips{window: ageWindow{Max: 30 * 24 * time.Hour}, now: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)},
+			contains: []string{
+				"Verdict: skipped, created outside the age window",
+			},
+		},
+		{
+			name:   "Skipped by front matter",
+			issue:  models.GitHubIssue{Number: 8, Title: "Draft story", Labels: []string{"story", "PROJ"}},
+			boards: []string{"PROJ"},
+			skips: issueSkips{
+				decisions:   map[int]rules.Decision{8: {Skip: true}},
+				frontMatter: map[int]frontmatter.Overrides{8: {Skip: true}},
+			},
+			contains: []string{
+				"Verdict: skipped, its front matter skips it",
+			},
+		},
+		{
+			name:   "Skipped by rules",
+			issue:  models.GitHubIssue{Number: 9, Title: "Internal story", Labels: []string{"story", "PROJ"}},
+			boards: []string{"PROJ"},
+			skips:  issueSkips{decisions: map[int]rules.Decision{9: {Skip: true}}},
+			contains: []string{
+				"Verdict: skipped, the rules script skips it",
+			},
+		},
+		{
+			name:   "Routed and typed by rules",
+			issue:  models.GitHubIssue{Number: 10, Title: "Payments work", Labels: []string{"PROJ"}},
+			boards: []string{"PROJ", "PAY"},
+			skips:  issueSkips{decisions: map[int]rules.Decision{10: {Boards: []string{"PAY"}, Type: "feature"}}},
+			contains: []string{
+				"PROJ: rules script sends the issue to PAY",
+				"PAY: matched by rules script",
+				"Type mapping: rules script -> JIRA Feature",
+				"Verdict: will be created in PAY",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			output := strings.Join(explainIssue(tt.issue, tt.boards, false, tt.related, "github.com", tt.routes, nil, config.MultipleParentsFirstWins, tt.skips), "\n")
+			for _, want := range tt.contains {
+				assert.Contains(t, output, want)
+			}
+		})
+	}
+}
+
+func TestInferBoards(t *testing.T) {
+	assert.Equal(t, []string{"PROJ", "OPS2"}, inferBoards([]string{"feature", "PROJ", "good first issue", "OPS2"}, nil))
+	assert.Empty(t, inferBoards([]string{"story"}, nil))
+	assert.Equal(t, []string{"PROJ", "OPS"}, inferBoards([]string{"PROJ", "jira-project: proj", "jira-project: OPS"}, nil))
+	assert.Equal(t, []string{"PLTENG"}, inferBoards([]string{"platform", "jira-project: platform-eng"}, testAliases))
+}
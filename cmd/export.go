@@ -0,0 +1,245 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/xlsx"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// exportHeader lists the columns of the mapping export.
+var exportHeader = []string{
+	"github_number", "github_title", "github_state", "jira_key", "type",
+	"jira_status", "parent_github_number", "parent_jira_key", "jira_updated",
+}
+
+// exportCmd writes the GitHub to JIRA mapping table as CSV or Excel.
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export the GitHub to JIRA mapping table as CSV or Excel",
+	Long: `Export one row per GitHub issue on the given boards with its JIRA mapping.
+
+Each row contains:
+- the GitHub issue number, title and state
+- the JIRA key, issue type and status
+- the parent feature on GitHub and its JIRA key
+- the time the JIRA ticket was last updated
+
+Glue doesn't record when it last synced an issue, so the JIRA update time is
+the closest available indication.
+
+CSV is written to standard output unless --output is given. Excel output
+requires --output.
+
+Example:
+  glue export -r owner/repo -b PROJ -o mapping.csv
+  glue export -r owner/repo -b PROJ --format xlsx -o mapping.xlsx`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "csv" && format != "xlsx" {
+			return fmt.Errorf("invalid format %q, expected csv or xlsx", format)
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		if format == "xlsx" && output == "" {
+			return fmt.Errorf("--output is required for xlsx exports")
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		issuesByBoard, err := fetchIssuesByBoard(cmd.Context(), githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+
+		var issues []models.GitHubIssue
+		for _, board := range boards {
+			issues = append(issues, issuesByBoard[board]...)
+		}
+
+		tickets := ticketsOf(jiraClient, issues)
+
+		rows := buildExportRows(issues, tickets, cfg.GitHub.Domain)
+
+		w, closeOutput, err := outputWriter(cmd, output)
+		if err != nil {
+			return err
+		}
+
+		if format == "xlsx" {
+			err = xlsx.Write(w, "Mapping", append([][]string{exportHeader}, rows...))
+		} else {
+			err = writeExportCSV(w, rows)
+		}
+		if closeErr := closeOutput(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write export: %v", err)
+		}
+
+		if output != "" {
+			logging.Info("exported mapping table", "rows", len(rows), "output", output)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to export (can be specified multiple times)")
+	exportCmd.Flags().String("format", "csv", "Output format: csv or xlsx")
+	exportCmd.Flags().StringP("output", "o", "", "File to write the export to")
+}
+
+// ticketsOf returns the tickets of the issues prefixed with a JIRA key, by
+// key. Tickets that can't be fetched are logged and left out.
+func ticketsOf(jiraClient *jira.Client, issues []models.GitHubIssue) map[string]models.JiraTicket {
+	tickets := make(map[string]models.JiraTicket)
+	for _, issue := range issues {
+		jiraKey := marker.GitHub.Key(issue.Title)
+		if jiraKey == "" {
+			continue
+		}
+		if _, ok := tickets[jiraKey]; ok {
+			continue
+		}
+		ticket, err := jiraClient.GetTicket(jiraKey)
+		if err != nil {
+			logging.Warn("failed to get jira ticket, going on without jira details",
+				"issue_number", issue.Number,
+				"jira_ticket", jiraKey,
+				"error", err)
+			continue
+		}
+		tickets[jiraKey] = ticket
+	}
+	return tickets
+}
+
+// buildExportRows returns one row per GitHub issue, ordered by issue number,
+// in the column order of exportHeader. Tickets are keyed by JIRA key; issues
+// whose ticket is missing are exported without JIRA status and update time.
+func buildExportRows(issues []models.GitHubIssue, tickets map[string]models.JiraTicket, gitHubDomain string) [][]string {
+	byNumber := make(map[int]models.GitHubIssue, len(issues))
+	for _, issue := range issues {
+		byNumber[issue.Number] = issue
+	}
+
+	// Map each child issue to the feature listing it
+	parents := make(map[int]models.GitHubIssue)
+	for _, issue := range byNumber {
+		if issueTypeOf(issue) != "feature" {
+			continue
+		}
+		for _, child := range parseChildIssues(issue.Description, gitHubDomain) {
+			if current, ok := parents[child]; !ok || issue.Number < current.Number {
+				parents[child] = issue
+			}
+		}
+	}
+
+	numbers := make([]int, 0, len(byNumber))
+	for number := range byNumber {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+
+	rows := make([][]string, 0, len(numbers))
+	for _, number := range numbers {
+		issue := byNumber[number]
+		jiraKey := marker.GitHub.Key(issue.Title)
+		ticket, hasTicket := tickets[jiraKey]
+
+		issueType := issueTypeOf(issue)
+		if hasTicket && ticket.Type != "" {
+			issueType = ticket.Type
+		}
+
+		var jiraStatus, jiraUpdated string
+		if hasTicket {
+			jiraStatus = ticket.Status
+			if !ticket.UpdatedAt.IsZero() {
+				jiraUpdated = ticket.UpdatedAt.UTC().Format(time.RFC3339)
+			}
+		}
+
+		var parentNumber, parentKey string
+		if parent, ok := parents[number]; ok {
+			parentNumber = strconv.Itoa(parent.Number)
+			parentKey = marker.GitHub.Key(parent.Title)
+		}
+
+		rows = append(rows, []string{
+			strconv.Itoa(number),
+			stripJiraPrefix(issue.Title),
+			issue.State,
+			jiraKey,
+			issueType,
+			jiraStatus,
+			parentNumber,
+			parentKey,
+			jiraUpdated,
+		})
+	}
+	return rows
+}
+
+// writeExportCSV writes the export rows as CSV with a header line.
+func writeExportCSV(w io.Writer, rows [][]string) error {
+	writer := csv.NewWriter(w)
+	if err := writer.Write(exportHeader); err != nil {
+		return err
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return err
+	}
+	return writer.Error()
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildExportRows(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{
+			Number: 3,
+			Title:  "Unsynced story",
+			State:  "open",
+			Labels: []string{"story", "PROJ"},
+		},
+		{
+			Number:      1,
+			Title:       "[PROJ-10] Parent feature",
+			Description: "## Issues\n- https://github.com/org/repo/issues/2\n- https://github.com/org/repo/issues/3",
+			State:       "open",
+			Labels:      []string{"feature", "PROJ"},
+		},
+		{
+			Number: 2,
+			Title:  "[PROJ-11] Synced story",
+			State:  "closed",
+			Labels: []string{"story", "PROJ"},
+		},
+		{
+			Number: 4,
+			Title:  "[PROJ-12] Ticket unavailable",
+			State:  "open",
+			Labels: []string{"story", "PROJ"},
+		},
+	}
+	tickets := map[string]models.JiraTicket{
+		"PROJ-10": {Key: "PROJ-10", Type: "Feature", Status: "In Progress", UpdatedAt: time.Date(2025, 5, 1, 8, 30, 0, 0, time.UTC)},
+		"PROJ-11": {Key: "PROJ-11", Type: "Story", Status: "Done"},
+	}
+
+	rows := buildExportRows(issues, tickets, "github.com")
+
+	assert.Equal(t, [][]string{
+		{"1", "Parent feature", "open", "PROJ-10", "Feature", "In Progress", "", "", "2025-05-01T08:30:00Z"},
+		{"2", "Synced story", "closed", "PROJ-11", "Story", "Done", "1", "PROJ-10", ""},
+		{"3", "Unsynced story", "open", "", "story", "", "1", "PROJ-10", ""},
+		{"4", "Ticket unavailable", "open", "PROJ-12", "story", "", "", "", ""},
+	}, rows)
+}
+
+func TestWriteExportCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeExportCSV(&buf, [][]string{
+		{"1", "Title, with comma", "open", "PROJ-1", "Story", "To Do", "", "", ""},
+	}))
+
+	assert.Equal(t,
+		"github_number,github_title,github_state,jira_key,type,jira_status,parent_github_number,parent_jira_key,jira_updated\n"+
+			"1,\"Title, with comma\",open,PROJ-1,Story,To Do,,,\n",
+		buf.String())
+}
@@ -0,0 +1,192 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// findResult pairs a GitHub issue with its JIRA ticket. Either side may be
+// missing: an issue that isn't synced, or a ticket without a GitHub issue.
+type findResult struct {
+	Issue *models.GitHubIssue
+	// Key is the JIRA key of the pair, empty for unsynced issues
+	Key    string
+	Ticket *models.JiraTicket
+}
+
+// findCmd searches GitHub issues and JIRA tickets at once.
+var findCmd = &cobra.Command{
+	Use:   "find <text>",
+	Short: "Search GitHub issues and their JIRA tickets",
+	Long: `Search the GitHub issues of a repository and the JIRA tickets of the given
+boards for text, showing each match paired with its counterpart.
+
+GitHub issues, open and closed, match if their title or body contains the text,
+ignoring case. JIRA tickets match a JQL text search ('text ~ "..."').
+
+Example:
+  glue find -r owner/repo -b PROJ "login timeout"`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		query := strings.TrimSpace(args[0])
+		if query == "" {
+			return fmt.Errorf("search text is required")
+		}
+
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		openIssues, err := githubClient.GetAllIssues(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch github issues: %v", err)
+		}
+		closedIssues, err := githubClient.GetClosedIssues(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch closed github issues: %v", err)
+		}
+
+		tickets, err := jiraClient.SearchTickets(findJQL(boards, query))
+		if err != nil {
+			return fmt.Errorf("failed to search jira tickets: %v", err)
+		}
+
+		results := matchFindResults(query, append(openIssues, closedIssues...), tickets)
+
+		// Fetch the tickets of matching issues the text search didn't return
+		for i, result := range results {
+			if result.Ticket != nil || result.Key == "" {
+				continue
+			}
+			ticket, err := jiraClient.GetTicket(result.Key)
+			if err != nil {
+				logging.Warn("failed to get jira ticket",
+					"jira_ticket", result.Key,
+					"error", err)
+				continue
+			}
+			results[i].Ticket = &ticket
+		}
+
+		return writeFindResults(cmd.OutOrStdout(), results)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(findCmd)
+	findCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to search (can be specified multiple times)")
+}
+
+// findJQL builds the JQL text search of the given boards.
+func findJQL(boards []string, query string) string {
+	quoted := make([]string, 0, len(boards))
+	for _, board := range boards {
+		quoted = append(quoted, fmt.Sprintf("'%s'", board))
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(query)
+	return fmt.Sprintf("project in (%s) AND text ~ \"%s\" ORDER BY key ASC", strings.Join(quoted, ", "), escaped)
+}
+
+// matchFindResults returns the issues whose title or body contains query,
+// ignoring case, and the matching tickets, paired by JIRA key. Issues come
+// first by number, followed by tickets without a matching issue by key.
+func matchFindResults(query string, issues []models.GitHubIssue, tickets []models.JiraTicket) []findResult {
+	needle := strings.ToLower(query)
+
+	ticketsByKey := make(map[string]*models.JiraTicket)
+	for i := range tickets {
+		ticketsByKey[tickets[i].Key] = &tickets[i]
+	}
+	issuesByKey := make(map[string]*models.GitHubIssue)
+	for i := range issues {
+		if key := marker.GitHub.Key(issues[i].Title); key != "" {
+			issuesByKey[key] = &issues[i]
+		}
+	}
+
+	var results []findResult
+	paired := make(map[string]bool)
+	for i := range issues {
+		issue := &issues[i]
+		key := marker.GitHub.Key(issue.Title)
+		matched := strings.Contains(strings.ToLower(issue.Title), needle) ||
+			strings.Contains(strings.ToLower(issue.Description), needle)
+		if !matched && ticketsByKey[key] == nil {
+			continue
+		}
+		results = append(results, findResult{Issue: issue, Key: key, Ticket: ticketsByKey[key]})
+		if key != "" {
+			paired[key] = true
+		}
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Issue.Number < results[j].Issue.Number })
+
+	for i := range tickets {
+		if paired[tickets[i].Key] {
+			continue
+		}
+		results = append(results, findResult{Key: tickets[i].Key, Ticket: &tickets[i]})
+	}
+	return results
+}
+
+// writeFindResults prints the results as a table with one pair per row.
+func writeFindResults(w io.Writer, results []findResult) error {
+	if len(results) == 0 {
+		_, err := fmt.Fprintln(w, "no matches")
+		return err
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "GITHUB\tSTATE\tJIRA\tSTATUS\tTITLE")
+	for _, r := range results {
+		number, state, key, status, title := "-", "-", "-", "-", ""
+		if r.Issue != nil {
+			number, state, title = fmt.Sprintf("#%d", r.Issue.Number), r.Issue.State, stripJiraPrefix(r.Issue.Title)
+		}
+		if r.Key != "" {
+			key = r.Key
+		}
+		if r.Ticket != nil {
+			status = r.Ticket.Status
+			if title == "" {
+				title = r.Ticket.Title
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", number, state, key, status, title)
+	}
+	return tw.Flush()
+}
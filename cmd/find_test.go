@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFindJQL(t *testing.T) {
+	assert.Equal(t, `project in ('PROJ') AND text ~ "say \"hi\"" ORDER BY key ASC`, findJQL([]string{"PROJ"}, `say "hi"`))
+}
+
+func TestMatchFindResults(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{Number: 3, Title: "[PROJ-3] Login timeout", State: "open"},
+		{Number: 1, Title: "[PROJ-1] Session handling", Description: "Users hit a LOGIN timeout", State: "closed"},
+		{Number: 2, Title: "[PROJ-2] Unrelated", State: "open"},
+		{Number: 4, Title: "Login timeout on mobile", State: "open"},
+	}
+	tickets := []models.JiraTicket{
+		{Key: "PROJ-2", Title: "Unrelated", Status: "To Do"},
+		{Key: "PROJ-3", Title: "Login timeout", Status: "In Progress"},
+		{Key: "PROJ-9", Title: "Login timeout in JIRA only", Status: "Done"},
+	}
+
+	results := matchFindResults("login timeout", issues, tickets)
+
+	require.Len(t, results, 5)
+	assert.Equal(t, 1, results[0].Issue.Number)
+	assert.Nil(t, results[0].Ticket, "ticket is fetched separately")
+	assert.Equal(t, "PROJ-2", results[1].Ticket.Key, "matched in JIRA only")
+	assert.Equal(t, "PROJ-3", results[2].Ticket.Key)
+	assert.Equal(t, "", results[3].Key)
+	assert.Nil(t, results[4].Issue)
+	assert.Equal(t, "PROJ-9", results[4].Key)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeFindResults(&buf, results))
+	assert.Equal(t, `GITHUB  STATE   JIRA    STATUS       TITLE
+#1      closed  PROJ-1  -            Session handling
+#2      open    PROJ-2  To Do        Unrelated
+#3      open    PROJ-3  In Progress  Login timeout
+#4      open    -       -            Login timeout on mobile
+-       -       PROJ-9  Done         Login timeout in JIRA only
+`, buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeFindResults(&buf, nil))
+	assert.Equal(t, "no matches\n", buf.String())
+}
@@ -0,0 +1,131 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	// repositoryPattern matches GitHub repositories in owner/repo form.
+	repositoryPattern = regexp.MustCompile(`^[\w.-]+/[\w.-]+$`)
+	// boardPattern matches JIRA project keys, compared case-insensitively.
+	boardPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+)
+
+// globalExclusiveFlags are the root flags that can't be combined on any command.
+var globalExclusiveFlags = [][]string{{"record", "replay"}}
+
+// flagRules describes the flag values and combinations a command accepts.
+type flagRules struct {
+	// Repository requires --repository, inferred from the git remote origin
+	// when not given
+	Repository bool
+	// Boards requires at least one --board
+	Boards bool
+	// Required lists other flags that must be given a value
+	Required []string
+	// Exclusive lists groups of flags of which at most one may be set
+	Exclusive [][]string
+	// Requires maps flags to other flags that must be set with them
+	Requires map[string][]string
+}
+
+// validateFlags returns a PreRunE function checking the command's flags
+// against rules, after config defaults are applied but before the command
+// creates any client. A missing --repository or --board is inferred first,
+// where possible, and their values are always checked for their format when
+// given. All problems are reported in one error.
+func validateFlags(rules flagRules) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		if rules.Repository {
+			if err := inferRepository(cmd); err != nil {
+				return err
+			}
+		}
+		if err := detectBoards(cmd); err != nil {
+			return err
+		}
+
+		problems := flagProblems(cmd, rules)
+		if len(problems) == 0 {
+			return nil
+		}
+		return fmt.Errorf("invalid flags:\n  %s", strings.Join(problems, "\n  "))
+	}
+}
+
+// flagProblems returns a description of every way the command's flags break
+// rules.
+func flagProblems(cmd *cobra.Command, rules flagRules) []string {
+	var problems []string
+	flags := cmd.Flags()
+
+	if flags.Lookup("repository") != nil {
+		repository, _ := flags.GetString("repository")
+		switch {
+		case repository == "" && rules.Repository:
+			problems = append(problems, "--repository is required, e.g. --repository owner/repo")
+		case repository != "" && !repositoryPattern.MatchString(repository):
+			problems = append(problems, fmt.Sprintf("--repository %q is not in owner/repo form", repository))
+		}
+	}
+
+	if flag := flags.Lookup("board"); flag != nil {
+		var boards []string
+		if flag.Value.Type() == "stringArray" {
+			boards, _ = flags.GetStringArray("board")
+		} else if board, _ := flags.GetString("board"); board != "" {
+			boards = []string{board}
+		}
+
+		if len(boards) == 0 && rules.Boards {
+			problems = append(problems, "--board is required, e.g. --board PROJ")
+		}
+		for _, board := range boards {
+			if !boardPattern.MatchString(strings.TrimSpace(board)) {
+				problems = append(problems, fmt.Sprintf("--board %q is not a JIRA project key, e.g. PROJ", board))
+			}
+		}
+	}
+
+	for _, name := range rules.Required {
+		if flag := flags.Lookup(name); flag != nil && strings.TrimSpace(flag.Value.String()) == "" {
+			problems = append(problems, fmt.Sprintf("--%s is required", name))
+		}
+	}
+
+	for _, group := range append(append([][]string{}, globalExclusiveFlags...), rules.Exclusive...) {
+		var set []string
+		for _, name := range group {
+			if flags.Changed(name) {
+				set = append(set, "--"+name)
+			}
+		}
+		if len(set) > 1 {
+			problems = append(problems, fmt.Sprintf("%s can't be used together", strings.Join(set, " and ")))
+		}
+	}
+
+	names := make([]string, 0, len(rules.Requires))
+	for name := range rules.Requires {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if !flags.Changed(name) {
+			continue
+		}
+		for _, required := range rules.Requires[name] {
+			if !flags.Changed(required) {
+				problems = append(problems, fmt.Sprintf("--%s requires --%s", name, required))
+			}
+		}
+	}
+
+	return problems
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newFlagsTestCommand(singleBoard bool) *cobra.Command {
+	cmd := &cobra.Command{Use: "test"}
+	cmd.Flags().StringP("repository", "r", "", "")
+	cmd.Flags().String("record", "", "")
+	cmd.Flags().String("replay", "", "")
+	if singleBoard {
+		cmd.Flags().StringP("board", "b", "", "")
+	} else {
+		cmd.Flags().StringArrayP("board", "b", []string{}, "")
+	}
+	cmd.Flags().String("fix-version", "", "")
+	cmd.Flags().Bool("include-closed", false, "")
+	cmd.Flags().String("closed-at-field", "", "")
+	return cmd
+}
+
+func TestFlagProblems(t *testing.T) {
+	rules := flagRules{
+		Repository: true,
+		Boards:     true,
+		Required:   []string{"fix-version"},
+		Requires:   map[string][]string{"closed-at-field": {"include-closed"}},
+	}
+
+	tests := []struct {
+		name        string
+		singleBoard bool
+		args        []string
+		rules       flagRules
+		want        []string
+	}{
+		{
+			name:  "valid",
+			args:  []string{"-r", "owner/repo", "-b", "PROJ", "-b", " ops_2 ", "--fix-version", "1.0"},
+			rules: rules,
+		},
+		{
+			name:  "everything missing",
+			rules: rules,
+			want: []string{
+				"--repository is required, e.g. --repository owner/repo",
+				"--board is required, e.g. --board PROJ",
+				"--fix-version is required",
+			},
+		},
+		{
+			name: "malformed values are checked even when optional",
+			args: []string{"-r", "owner", "-b", "MY PROJ", "-b", "1PROJ"},
+			want: []string{
+				`--repository "owner" is not in owner/repo form`,
+				`--board "MY PROJ" is not a JIRA project key, e.g. PROJ`,
+				`--board "1PROJ" is not a JIRA project key, e.g. PROJ`,
+			},
+		},
+		{
+			name:        "single board flag",
+			singleBoard: true,
+			args:        []string{"-r", "owner/repo", "-b", "PROJ-1"},
+			rules:       flagRules{Boards: true},
+			want:        []string{`--board "PROJ-1" is not a JIRA project key, e.g. PROJ`},
+		},
+		{
+			name: "exclusive flags",
+			args: []string{"--record", "rec", "--replay", "rec"},
+			want: []string{"--record and --replay can't be used together"},
+		},
+		{
+			name:  "flag requiring another",
+			args:  []string{"-r", "owner/repo", "-b", "PROJ", "--fix-version", "1.0", "--closed-at-field", "customfield_10050"},
+			rules: rules,
+			want:  []string{"--closed-at-field requires --include-closed"},
+		},
+		{
+			name:  "required flag present",
+			args:  []string{"-r", "owner/repo", "-b", "PROJ", "--fix-version", "1.0", "--closed-at-field", "customfield_10050", "--include-closed"},
+			rules: rules,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cmd := newFlagsTestCommand(tt.singleBoard)
+			require.NoError(t, cmd.ParseFlags(tt.args))
+			assert.Equal(t, tt.want, flagProblems(cmd, tt.rules))
+		})
+	}
+}
+
+func TestValidateFlags(t *testing.T) {
+	cmd := newFlagsTestCommand(false)
+	require.NoError(t, cmd.ParseFlags([]string{"-r", "owner", "-b", "MY PROJ"}))
+
+	err := validateFlags(flagRules{Repository: true})(cmd, nil)
+	require.Error(t, err)
+	assert.Equal(t, "invalid flags:\n  --repository \"owner\" is not in owner/repo form\n  --board \"MY PROJ\" is not a JIRA project key, e.g. PROJ", err.Error())
+
+	cmd = newFlagsTestCommand(false)
+	require.NoError(t, cmd.ParseFlags([]string{"-r", "owner/repo"}))
+	assert.NoError(t, validateFlags(flagRules{Repository: true})(cmd, nil))
+}
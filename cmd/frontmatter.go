@@ -0,0 +1,78 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"github.com/danielolaszy/glue/internal/frontmatter"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// parseFrontMatter removes the glue front matter from the bodies of issues,
+// so it reaches neither the rules script nor the tickets, and returns the
+// overrides of the issues that have it. An issue with invalid front matter is
+// skipped.
+func parseFrontMatter(issuesByBoard map[string][]models.GitHubIssue) map[int]frontmatter.Overrides {
+	found := make(map[int]frontmatter.Overrides)
+	for _, issues := range issuesByBoard {
+		for i, issue := range issues {
+			overrides, body, ok, err := frontmatter.Parse(issue.Description)
+			if !ok {
+				continue
+			}
+			issues[i].Description = body
+
+			if _, seen := found[issue.Number]; seen {
+				continue
+			}
+			if err != nil {
+				logging.Error("skipping issue with invalid front matter",
+					"issue_number", issue.Number,
+					"error", err)
+				overrides = frontmatter.Overrides{Skip: true}
+			}
+			found[issue.Number] = overrides
+		}
+	}
+	return found
+}
+
+// applyFrontMatter merges the front matter overrides into the rules
+// decisions, taking precedence over the rules script. The returned decisions
+// are nil only if decisions is nil and there are no overrides.
+func applyFrontMatter(decisions map[int]rules.Decision, overrides map[int]frontmatter.Overrides, boards []string) map[int]rules.Decision {
+	for number, o := range overrides {
+		if decisions == nil {
+			decisions = make(map[int]rules.Decision)
+		}
+		if o.Board != "" && !hasLabel(boards, o.Board) {
+			logging.Warn("front matter board is not part of this run, issue is not synced",
+				"issue_number", number,
+				"board", o.Board)
+		}
+		decisions[number] = mergeOverrides(decisions[number], o)
+	}
+	return decisions
+}
+
+// mergeOverrides returns decision with the front matter overrides applied.
+func mergeOverrides(decision rules.Decision, overrides frontmatter.Overrides) rules.Decision {
+	if overrides.Skip {
+		decision.Skip = true
+	}
+	if overrides.Board != "" {
+		decision.Boards = []string{overrides.Board}
+	}
+	if overrides.Type != "" {
+		decision.Type = overrides.Type
+	}
+	if overrides.FixVersion != "" {
+		fields := make(map[string]interface{}, len(decision.Fields)+1)
+		for id, value := range decision.Fields {
+			fields[id] = value
+		}
+		fields["fixVersions"] = []map[string]interface{}{{"name": overrides.FixVersion}}
+		decision.Fields = fields
+	}
+	return decision
+}
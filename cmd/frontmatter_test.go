@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/frontmatter"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseFrontMatter(t *testing.T) {
+	issuesByBoard := map[string][]models.GitHubIssue{
+		"PROJ": {
+			{Number: 1, Description: "```yaml\nglue:\n  board: OPS\n```\nBody"},
+			{Number: 2, Description: "No front matter"},
+			{Number: 3, Description: "```yaml\nglue:\n  type: epic\n```\nBody"},
+		},
+		"OPS": {
+			{Number: 1, Description: "```yaml\nglue:\n  board: OPS\n```\nBody"},
+		},
+	}
+
+	overrides := parseFrontMatter(issuesByBoard)
+	assert.Equal(t, map[int]frontmatter.Overrides{
+		1: {Board: "OPS"},
+		3: {Skip: true},
+	}, overrides)
+	assert.Equal(t, "Body", issuesByBoard["PROJ"][0].Description)
+	assert.Equal(t, "No front matter", issuesByBoard["PROJ"][1].Description)
+	// Invalid front matter is left in place
+	assert.Equal(t, "```yaml\nglue:\n  type: epic\n```\nBody", issuesByBoard["PROJ"][2].Description)
+	assert.Equal(t, "Body", issuesByBoard["OPS"][0].Description)
+}
+
+func TestApplyFrontMatter(t *testing.T) {
+	assert.Nil(t, applyFrontMatter(nil, map[int]frontmatter.Overrides{}, []string{"PROJ"}))
+
+	decisions := applyFrontMatter(map[int]rules.Decision{
+		1: {Boards: []string{"PROJ"}, Type: "feature", Fields: map[string]interface{}{"priority": "High"}},
+		2: {Skip: true},
+	}, map[int]frontmatter.Overrides{
+		1: {Board: "OPS", FixVersion: "PI 24.3"},
+		3: {Type: "story"},
+	}, []string{"PROJ", "OPS"})
+
+	assert.Equal(t, map[int]rules.Decision{
+		1: {
+			Boards: []string{"OPS"},
+			Type:   "feature",
+			Fields: map[string]interface{}{
+				"priority":    "High",
+				"fixVersions": []map[string]interface{}{{"name": "PI 24.3"}},
+			},
+		},
+		2: {Skip: true},
+		3: {Type: "story"},
+	}, decisions)
+}
@@ -0,0 +1,293 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// Kinds of graph edges.
+const (
+	edgeChild  = "child"
+	edgeBlocks = "blocks"
+)
+
+// graphNode is an issue or ticket in the dependency graph.
+type graphNode struct {
+	// ID identifies the node in the rendered graph
+	ID string
+	// Key is the JIRA key, empty for issues without a ticket
+	Key string
+	// Number is the GitHub issue number, 0 for tickets without an issue
+	Number int
+	// Title is the issue title without its key prefix
+	Title string
+	// Feature marks feature issues
+	Feature bool
+}
+
+// graphEdge connects two nodes by ID.
+type graphEdge struct {
+	From string
+	To   string
+	// Kind is edgeChild (feature to child issue) or edgeBlocks
+	Kind string
+}
+
+// issueGraph is the feature/story hierarchy and blocking relationships of a board.
+type issueGraph struct {
+	Nodes []graphNode
+	Edges []graphEdge
+}
+
+// graphCmd exports the issue hierarchy as a Graphviz or Mermaid graph.
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export the issue hierarchy as a Graphviz or Mermaid graph",
+	Long: `Export the feature/story hierarchy of a board and the blocking relationships
+between its JIRA tickets as a Graphviz (dot) or Mermaid graph, for docs and
+planning reviews.
+
+Hierarchy edges come from the '## Issues' section of feature issues. Blocking
+edges come from the "Blocks" links of the synced JIRA tickets and are drawn
+dashed; blocked tickets without a GitHub issue on the board are included by key.
+
+Example:
+  glue graph -r owner/repo -b PROJ --format mermaid
+  glue graph -r owner/repo -b PROJ | dot -Tsvg > hierarchy.svg
+  glue graph -r owner/repo -b PROJ -o hierarchy.dot`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "dot" && format != "mermaid" {
+			return fmt.Errorf("invalid format %q, expected dot or mermaid", format)
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		issuesByBoard, err := fetchIssuesByBoard(cmd.Context(), githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+
+		var issues []models.GitHubIssue
+		seen := make(map[int]bool)
+		for _, board := range boards {
+			for _, issue := range issuesByBoard[board] {
+				if !seen[issue.Number] {
+					seen[issue.Number] = true
+					issues = append(issues, issue)
+				}
+			}
+		}
+
+		blocks := make(map[string][]string)
+		for _, issue := range issues {
+			key := marker.GitHub.Key(issue.Title)
+			if key == "" {
+				continue
+			}
+			blocked, err := jiraClient.GetBlockedTickets(key)
+			if err != nil {
+				logging.Error("failed to get blocking links",
+					"jira_ticket", key,
+					"error", err)
+				continue
+			}
+			blocks[key] = blocked
+		}
+
+		graph := buildIssueGraph(issues, cfg.GitHub.Domain, blocks)
+		w, closeOutput, err := outputWriter(cmd, output)
+		if err != nil {
+			return err
+		}
+		if format == "mermaid" {
+			err = writeMermaid(w, graph)
+		} else {
+			err = writeDOT(w, graph)
+		}
+		if closeErr := closeOutput(); err == nil {
+			err = closeErr
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+	graphCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to graph (can be specified multiple times)")
+	graphCmd.Flags().String("format", "dot", "Output format: dot or mermaid")
+	graphCmd.Flags().StringP("output", "o", "", "File to write the graph to instead of stdout")
+}
+
+// buildIssueGraph builds the graph of issues, with edges from features to the
+// child issues listed in their descriptions and from tickets to the tickets
+// they block. blocks maps JIRA keys to the keys they block.
+func buildIssueGraph(issues []models.GitHubIssue, gitHubDomain string, blocks map[string][]string) issueGraph {
+	var graph issueGraph
+	byNumber := make(map[int]string)
+	byKey := make(map[string]string)
+
+	for _, issue := range issues {
+		node := graphNode{
+			ID:      fmt.Sprintf("i%d", issue.Number),
+			Key:     marker.GitHub.Key(issue.Title),
+			Number:  issue.Number,
+			Title:   stripJiraPrefix(issue.Title),
+			Feature: issueTypeOf(issue) == "feature",
+		}
+		graph.Nodes = append(graph.Nodes, node)
+		byNumber[issue.Number] = node.ID
+		if node.Key != "" {
+			byKey[node.Key] = node.ID
+		}
+	}
+
+	for _, issue := range issues {
+		if issueTypeOf(issue) != "feature" {
+			continue
+		}
+		for _, child := range parseChildIssues(issue.Description, gitHubDomain) {
+			if to, ok := byNumber[child]; ok {
+				graph.Edges = append(graph.Edges, graphEdge{From: byNumber[issue.Number], To: to, Kind: edgeChild})
+			}
+		}
+	}
+
+	keys := make([]string, 0, len(blocks))
+	for key := range blocks {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		from, ok := byKey[key]
+		if !ok {
+			continue
+		}
+		for _, blocked := range blocks[key] {
+			to, ok := byKey[blocked]
+			if !ok {
+				to = "t" + strings.ReplaceAll(blocked, "-", "_")
+				byKey[blocked] = to
+				graph.Nodes = append(graph.Nodes, graphNode{ID: to, Key: blocked})
+			}
+			graph.Edges = append(graph.Edges, graphEdge{From: from, To: to, Kind: edgeBlocks})
+		}
+	}
+
+	return graph
+}
+
+// nodeLabel returns the text shown for a node, e.g. "PROJ-1 #12: Add login".
+func nodeLabel(node graphNode) string {
+	var ids []string
+	if node.Key != "" {
+		ids = append(ids, node.Key)
+	}
+	if node.Number != 0 {
+		ids = append(ids, fmt.Sprintf("#%d", node.Number))
+	}
+	label := strings.Join(ids, " ")
+	if node.Title != "" {
+		label += ": " + node.Title
+	}
+	return label
+}
+
+// writeDOT renders a graph in the Graphviz dot language. Features are drawn
+// bold and blocking edges dashed.
+func writeDOT(w io.Writer, graph issueGraph) error {
+	var b strings.Builder
+	b.WriteString("digraph glue {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, node := range graph.Nodes {
+		attrs := fmt.Sprintf("label=%q", nodeLabel(node))
+		if node.Feature {
+			attrs += " style=bold"
+		}
+		fmt.Fprintf(&b, "  %s [%s];\n", node.ID, attrs)
+	}
+	for _, edge := range graph.Edges {
+		if edge.Kind == edgeBlocks {
+			fmt.Fprintf(&b, "  %s -> %s [style=dashed label=\"blocks\"];\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %s -> %s;\n", edge.From, edge.To)
+		}
+	}
+	b.WriteString("}\n")
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// writeMermaid renders a graph as a Mermaid flowchart. Features get a double
+// border and blocking edges are dotted.
+func writeMermaid(w io.Writer, graph issueGraph) error {
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, node := range graph.Nodes {
+		// Mermaid uses # for entity codes, so both # and quotes are escaped
+		label := strings.NewReplacer("#", "#35;", `"`, "#quot;").Replace(nodeLabel(node))
+		if node.Feature {
+			fmt.Fprintf(&b, "  %s[[\"%s\"]]\n", node.ID, label)
+		} else {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", node.ID, label)
+		}
+	}
+	for _, edge := range graph.Edges {
+		if edge.Kind == edgeBlocks {
+			fmt.Fprintf(&b, "  %s -. blocks .-> %s\n", edge.From, edge.To)
+		} else {
+			fmt.Fprintf(&b, "  %s --> %s\n", edge.From, edge.To)
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildIssueGraph(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{
+			Number:      1,
+			Title:       "[PROJ-1] SSO",
+			Labels:      []string{"feature", "PROJ"},
+			Description: "## Issues\n- https://github.com/o/r/issues/2\n- https://github.com/o/r/issues/99",
+		},
+		{Number: 2, Title: "[PROJ-2] Login \"form\"", Labels: []string{"story", "PROJ"}},
+		{Number: 3, Title: "Unsynced story", Labels: []string{"story", "PROJ"}},
+	}
+	blocks := map[string][]string{"PROJ-2": {"PROJ-1", "OPS-7"}}
+
+	graph := buildIssueGraph(issues, "github.com", blocks)
+
+	require.Len(t, graph.Nodes, 4)
+	assert.Equal(t, graphNode{ID: "tOPS_7", Key: "OPS-7"}, graph.Nodes[3])
+	assert.Equal(t, []graphEdge{
+		{From: "i1", To: "i2", Kind: edgeChild},
+		{From: "i2", To: "i1", Kind: edgeBlocks},
+		{From: "i2", To: "tOPS_7", Kind: edgeBlocks},
+	}, graph.Edges)
+
+	var dot bytes.Buffer
+	require.NoError(t, writeDOT(&dot, graph))
+	assert.Equal(t, `digraph glue {
+  rankdir=LR;
+  node [shape=box];
+  i1 [label="PROJ-1 #1: SSO" style=bold];
+  i2 [label="PROJ-2 #2: Login \"form\""];
+  i3 [label="#3: Unsynced story"];
+  tOPS_7 [label="OPS-7"];
+  i1 -> i2;
+  i2 -> i1 [style=dashed label="blocks"];
+  i2 -> tOPS_7 [style=dashed label="blocks"];
+}
+`, dot.String())
+
+	var mermaid bytes.Buffer
+	require.NoError(t, writeMermaid(&mermaid, graph))
+	assert.Equal(t, `graph LR
+  i1[["PROJ-1 #35;1: SSO"]]
+  i2["PROJ-2 #35;2: Login #quot;form#quot;"]
+  i3["#35;3: Unsynced story"]
+  tOPS_7["OPS-7"]
+  i1 --> i2
+  i2 -. blocks .-> i1
+  i2 -. blocks .-> tOPS_7
+`, mermaid.String())
+}
@@ -0,0 +1,217 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/hooks"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// Lifecycle hook event names, passed to hook commands as GLUE_HOOK_EVENT.
+const (
+	eventPreSync   = "pre_sync"
+	eventPostIssue = "post_issue"
+	eventPostRun   = "post_run"
+)
+
+// preSyncPayload is sent to pre_sync hooks.
+type preSyncPayload struct {
+	Event      string   `json:"event"`
+	Repository string   `json:"repository"`
+	Boards     []string `json:"boards"`
+}
+
+// postIssuePayload is sent to post_issue hooks.
+type postIssuePayload struct {
+	Event       string `json:"event"`
+	Repository  string `json:"repository"`
+	Board       string `json:"board"`
+	IssueNumber int    `json:"issue_number"`
+	Title       string `json:"title"`
+	JiraKey     string `json:"jira_key,omitempty"`
+	Status      string `json:"status"`
+	Error       string `json:"error,omitempty"`
+}
+
+// postRunPayload is sent to post_run hooks.
+type postRunPayload struct {
+	Event        string   `json:"event"`
+	Repository   string   `json:"repository"`
+	Boards       []string `json:"boards"`
+	Synchronized int      `json:"synchronized"`
+	Closed       int      `json:"closed"`
+}
+
+// syncHooks runs the configured hooks of a synchronization run. A nil
+// *syncHooks runs nothing.
+type syncHooks struct {
+	repository string
+	config     config.HooksConfig
+	// failed collects the issues reported as failed, for the run summary;
+	// nil collects nothing
+	failed map[int]bool
+	// syncState records the tickets created; nil records nothing
+	syncState *state.Store
+}
+
+// preSync runs the pre_sync hooks. It returns the first failure, which aborts the run.
+func (h *syncHooks) preSync(ctx context.Context, boards []string) error {
+	if h == nil {
+		return nil
+	}
+	payload := preSyncPayload{Event: eventPreSync, Repository: h.repository, Boards: boards}
+	for i, hook := range h.config.PreSync {
+		if err := runHook(ctx, hook, eventPreSync, payload); err != nil {
+			return fmt.Errorf("pre_sync hook %s failed: %v", hookName(hook.Name, eventPreSync, i), err)
+		}
+	}
+	return nil
+}
+
+// postIssue runs the post_issue hooks for an issue a ticket creation was
+// attempted for. A non-nil err reports the issue as failed.
+func (h *syncHooks) postIssue(ctx context.Context, board string, issue models.GitHubIssue, jiraKey string, err error) {
+	if h == nil {
+		return
+	}
+	if err != nil && h.failed != nil {
+		h.failed[issue.Number] = true
+	}
+	if err == nil && h.syncState != nil && jiraKey != "" {
+		h.syncState.MarkSynced(issue.Number, jiraKey, time.Now())
+	}
+	if len(h.config.PostIssue) == 0 {
+		return
+	}
+	payload := postIssuePayload{
+		Event:       eventPostIssue,
+		Repository:  h.repository,
+		Board:       board,
+		IssueNumber: issue.Number,
+		Title:       issue.Title,
+		JiraKey:     jiraKey,
+		Status:      "created",
+	}
+	if err != nil {
+		payload.Status = "failed"
+		payload.Error = err.Error()
+	}
+	h.run(ctx, h.config.PostIssue, eventPostIssue, payload)
+}
+
+// postRun runs the post_run hooks with the run's summary.
+func (h *syncHooks) postRun(ctx context.Context, boards []string, synchronized, closed int) {
+	if h == nil || len(h.config.PostRun) == 0 {
+		return
+	}
+	h.run(ctx, h.config.PostRun, eventPostRun, postRunPayload{
+		Event:        eventPostRun,
+		Repository:   h.repository,
+		Boards:       boards,
+		Synchronized: synchronized,
+		Closed:       closed,
+	})
+}
+
+// run executes hooks in order, logging failures without stopping.
+// Hooks ignore the cancellation of ctx, as it may carry the run's time budget.
+func (h *syncHooks) run(ctx context.Context, execHooks []config.ExecHook, event string, payload interface{}) {
+	ctx = context.WithoutCancel(ctx)
+	log := logging.FromContext(ctx)
+	for i, hook := range execHooks {
+		name := hookName(hook.Name, event, i)
+		if err := runHook(ctx, hook, event, payload); err != nil {
+			log.Error("hook failed", "hook", name, "event", event, "error", err)
+			continue
+		}
+		log.Debug("hook succeeded", "hook", name, "event", event)
+	}
+}
+
+// runHook runs a hook's command, or posts the payload to its URL.
+func runHook(ctx context.Context, hook config.ExecHook, event string, payload interface{}) error {
+	if hook.URL != "" {
+		return hooks.Post(ctx, hook.URL, hook.Secret, hook.Timeout, event, payload)
+	}
+	return hooks.Run(ctx, hook.Command, hook.Timeout, event, payload)
+}
+
+// postCreate applies the post_create hooks to the tickets created on a board.
+func (h *syncHooks) postCreate(jiraClient *jira.Client, createdKeys []string) {
+	if h == nil {
+		return
+	}
+	runPostCreateHooks(jiraClient, createdKeys, h.config.PostCreate)
+}
+
+// hookName returns the configured name of a hook, or its position in the config.
+func hookName(name, event string, index int) string {
+	if name != "" {
+		return name
+	}
+	return fmt.Sprintf("%s[%d]", event, index)
+}
+
+// runPostCreateHooks applies the configured post-create hooks to the tickets
+// created in this run. Each hook selects the new tickets matching its JQL and
+// sets its fields on them. Failures are logged and don't stop other hooks.
+// Returns the number of ticket updates made.
+func runPostCreateHooks(jiraClient *jira.Client, createdKeys []string, hooks []config.PostCreateHook) int {
+	if len(createdKeys) == 0 {
+		return 0
+	}
+
+	updateCount := 0
+	for i, hook := range hooks {
+		name := hookName(hook.Name, "post_create", i)
+
+		if len(hook.Fields) == 0 {
+			logging.Warn("skipping post-create hook without fields", "hook", name)
+			continue
+		}
+
+		jql := postCreateJQL(createdKeys, hook.JQL)
+		keys, err := jiraClient.SearchKeys(jql)
+		if err != nil {
+			logging.Error("failed to run post-create hook query",
+				"hook", name,
+				"jql", jql,
+				"error", err)
+			continue
+		}
+
+		for _, key := range keys {
+			if err := jiraClient.UpdateFields(key, hook.Fields); err != nil {
+				logging.Error("failed to apply post-create hook",
+					"hook", name,
+					"jira_ticket", key,
+					"error", err)
+				continue
+			}
+			updateCount++
+		}
+
+		logging.Info("applied post-create hook",
+			"hook", name,
+			"matched", len(keys))
+	}
+	return updateCount
+}
+
+// postCreateJQL builds the query selecting the created tickets that match a
+// hook's JQL. An empty hook JQL selects all created tickets.
+func postCreateJQL(createdKeys []string, hookJQL string) string {
+	jql := fmt.Sprintf("key in (%s)", strings.Join(createdKeys, ", "))
+	if hookJQL = strings.TrimSpace(hookJQL); hookJQL != "" {
+		jql += fmt.Sprintf(" AND (%s)", hookJQL)
+	}
+	return jql
+}
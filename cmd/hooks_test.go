@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os/exec"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/hooks"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostCreateJQL(t *testing.T) {
+	keys := []string{"PROJ-1", "PROJ-2"}
+
+	assert.Equal(t, "key in (PROJ-1, PROJ-2)", postCreateJQL(keys, ""))
+	assert.Equal(t, "key in (PROJ-1, PROJ-2)", postCreateJQL(keys, "  "))
+	assert.Equal(t,
+		"key in (PROJ-1, PROJ-2) AND (labels = backend OR component = API)",
+		postCreateJQL(keys, "labels = backend OR component = API"))
+}
+
+func TestRunPostCreateHooksWithoutCreatedTickets(t *testing.T) {
+	// Nothing was created, so no query is made and the nil client is never used
+	assert.Equal(t, 0, runPostCreateHooks(nil, nil, nil))
+}
+
+func TestSyncHooksNilSafe(t *testing.T) {
+	var h *syncHooks
+	ctx := context.Background()
+
+	assert.NoError(t, h.preSync(ctx, []string{"PROJ"}))
+	h.postIssue(ctx, "PROJ", models.GitHubIssue{Number: 1}, "PROJ-1", nil)
+	h.postRun(ctx, []string{"PROJ"}, 1, 0)
+	h.postCreate(nil, []string{"PROJ-1"})
+}
+
+func TestSyncHooksCollectsFailures(t *testing.T) {
+	h := &syncHooks{repository: "owner/repo", failed: make(map[int]bool)}
+	ctx := context.Background()
+
+	h.postIssue(ctx, "PROJ", models.GitHubIssue{Number: 1}, "PROJ-1", nil)
+	h.postIssue(ctx, "PROJ", models.GitHubIssue{Number: 2}, "", errors.New("field required"))
+	assert.Equal(t, map[int]bool{2: true}, h.failed)
+}
+
+func TestSyncHooksPreSyncFailureAborts(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	h := &syncHooks{
+		repository: "owner/repo",
+		config: config.HooksConfig{PreSync: []config.ExecHook{
+			{Name: "freeze window", Command: []string{"sh", "-c", "echo frozen; exit 1"}},
+		}},
+	}
+
+	err := h.preSync(context.Background(), []string{"PROJ"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "freeze window")
+	assert.Contains(t, err.Error(), "frozen")
+}
+
+func TestSyncHooksPostToURL(t *testing.T) {
+	var payload postRunPayload
+	var signature string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(hooks.SignatureHeader)
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+	}))
+	defer server.Close()
+
+	h := &syncHooks{
+		repository: "owner/repo",
+		config: config.HooksConfig{PostRun: []config.ExecHook{
+			{Name: "notify", URL: server.URL, Secret: "s3cret"},
+		}},
+	}
+
+	h.postRun(context.Background(), []string{"PROJ"}, 3, 1)
+	assert.Equal(t, postRunPayload{Event: eventPostRun, Repository: "owner/repo", Boards: []string{"PROJ"}, Synchronized: 3, Closed: 1}, payload)
+	assert.NotEmpty(t, signature)
+}
+
+func TestHookName(t *testing.T) {
+	assert.Equal(t, "notify", hookName("notify", eventPostRun, 0))
+	assert.Equal(t, "post_run[2]", hookName("", eventPostRun, 2))
+}
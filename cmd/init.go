@@ -0,0 +1,303 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+// initAnswers are the settings collected by 'glue init'.
+type initAnswers struct {
+	GitHubDomain string
+	GitHubToken  string
+	JiraURL      string
+	JiraUsername string
+	JiraToken    string
+	Repository   string
+	Boards       []string
+	// StoreTokens writes the tokens to the config file instead of leaving
+	// them to the environment
+	StoreTokens bool
+	// Preset adds the settings of a preset, if set
+	Preset *initPreset
+}
+
+// wizard asks questions on the terminal.
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// errInputEnded is returned when the input ends before all questions are answered.
+var errInputEnded = errors.New("input ended before setup was complete")
+
+// ask prints question and returns the trimmed answer, or def if the answer is
+// empty. A non-empty def is shown in brackets, unless hide is set.
+func (w *wizard) ask(question, def string, hide bool) (string, error) {
+	switch {
+	case def != "" && hide:
+		fmt.Fprintf(w.out, "%s [keep current]: ", question)
+	case def != "":
+		fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+	default:
+		fmt.Fprintf(w.out, "%s: ", question)
+	}
+
+	line, err := w.in.ReadString('\n')
+	answer := strings.TrimSpace(line)
+	if answer == "" {
+		if err != nil && def == "" {
+			return "", errInputEnded
+		}
+		return def, nil
+	}
+	return answer, nil
+}
+
+// require asks question until it gets a non-empty answer.
+func (w *wizard) require(question, def string, hide bool) (string, error) {
+	for {
+		answer, err := w.ask(question, def, hide)
+		if err != nil || answer != "" {
+			return answer, err
+		}
+		fmt.Fprintln(w.out, "A value is required.")
+	}
+}
+
+// confirm asks a yes/no question, answered no by default.
+func (w *wizard) confirm(question string) (bool, error) {
+	answer, err := w.ask(question+" (y/N)", "n", false)
+	if err != nil {
+		return false, err
+	}
+	return strings.EqualFold(answer, "y") || strings.EqualFold(answer, "yes"), nil
+}
+
+// initCmd writes a starter config file from interactively collected settings.
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Create a config file interactively",
+	Long: `Set up glue for a repository by answering a few questions.
+
+The GitHub and JIRA credentials are checked against the live APIs, the JIRA
+projects are listed, and the chosen projects are checked for the 'Feature' and
+'Story' issue types glue creates. The result is written as a starter config
+file mapping the repository to its boards, so later runs only need
+'glue jira'.
+
+Tokens are only written to the file if you agree; otherwise keep them in the
+GITHUB_TOKEN and JIRA_TOKEN environment variables. Answers are shown as you
+type them.
+
+--preset adds the flag defaults, rules script and label settings of a common
+way of working, to adjust as needed:` + presetHelp() + `
+
+Example:
+  glue init
+  glue init --preset safe
+  glue init --output ~/.config/glue/glue.yaml`,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+		overwrite, err := cmd.Flags().GetBool("overwrite")
+		if err != nil {
+			return err
+		}
+		if _, err := os.Stat(output); err == nil && !overwrite {
+			return fmt.Errorf("%s already exists; use --overwrite to replace it", output)
+		}
+
+		presetName, err := cmd.Flags().GetString("preset")
+		if err != nil {
+			return err
+		}
+		var preset *initPreset
+		if presetName != "" {
+			p, err := lookupPreset(presetName)
+			if err != nil {
+				return err
+			}
+			preset = &p
+		}
+
+		w := &wizard{in: bufio.NewReader(cmd.InOrStdin()), out: cmd.OutOrStdout()}
+		answers, err := runInitWizard(cmd.Context(), w)
+		if err != nil {
+			return err
+		}
+		answers.Preset = preset
+
+		if err := os.WriteFile(output, []byte(renderInitConfig(answers)), 0o600); err != nil {
+			return fmt.Errorf("failed to write config file: %v", err)
+		}
+
+		fmt.Fprintf(w.out, "\nWrote %s.\n", output)
+		if !answers.StoreTokens {
+			fmt.Fprintln(w.out, "Set GITHUB_TOKEN and JIRA_TOKEN in the environment before running glue.")
+		}
+		if output != config.ConfigFileName {
+			fmt.Fprintf(w.out, "Point GLUE_CONFIG at it unless it is in a default location.\n")
+		}
+		fmt.Fprintln(w.out, "Try it out without changing anything: glue diff")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(initCmd)
+	initCmd.Flags().String("output", config.ConfigFileName, "Path of the config file to write")
+	initCmd.Flags().Bool("overwrite", false, "Replace the config file if it exists")
+	initCmd.Flags().String("preset", "", "Start from the settings of a preset: "+strings.Join(presetNames(), ", "))
+}
+
+// runInitWizard collects and checks the settings for a config file. The
+// credentials are put into the process environment so the regular clients
+// validate them exactly as later runs will.
+func runInitWizard(ctx context.Context, w *wizard) (initAnswers, error) {
+	var answers initAnswers
+	var err error
+
+	fmt.Fprintln(w.out, "GitHub")
+	if answers.GitHubDomain, err = w.require("  Domain", envOr("GITHUB_DOMAIN", "github.com"), false); err != nil {
+		return answers, err
+	}
+	var githubClient *github.Client
+	for githubClient == nil {
+		if answers.GitHubToken, err = w.require("  Personal access token", os.Getenv("GITHUB_TOKEN"), true); err != nil {
+			return answers, err
+		}
+		os.Setenv("GITHUB_DOMAIN", answers.GitHubDomain)
+		os.Setenv("GITHUB_TOKEN", answers.GitHubToken)
+		if githubClient, err = github.NewClient(); err != nil {
+			fmt.Fprintf(w.out, "  GitHub rejected the token: %v\n", err)
+			os.Unsetenv("GITHUB_TOKEN")
+		}
+	}
+	fmt.Fprintln(w.out, "  Authenticated.")
+
+	fmt.Fprintln(w.out, "JIRA")
+	var jiraClient *jira.Client
+	for jiraClient == nil {
+		if answers.JiraURL, err = w.require("  URL (e.g. https://your-domain.atlassian.net)", os.Getenv("JIRA_URL"), false); err != nil {
+			return answers, err
+		}
+		if answers.JiraUsername, err = w.require("  Username or email", os.Getenv("JIRA_USERNAME"), false); err != nil {
+			return answers, err
+		}
+		if answers.JiraToken, err = w.require("  API token", os.Getenv("JIRA_TOKEN"), true); err != nil {
+			return answers, err
+		}
+		os.Setenv("JIRA_URL", answers.JiraURL)
+		os.Setenv("JIRA_USERNAME", answers.JiraUsername)
+		os.Setenv("JIRA_TOKEN", answers.JiraToken)
+		if jiraClient, err = jira.NewClient(); err != nil {
+			fmt.Fprintf(w.out, "  JIRA rejected the credentials: %v\n", err)
+			os.Unsetenv("JIRA_TOKEN")
+		}
+	}
+	fmt.Fprintln(w.out, "  Authenticated.")
+
+	projectKeys, err := jiraClient.ListProjectKeys()
+	if err != nil {
+		return answers, err
+	}
+	fmt.Fprintf(w.out, "  Projects: %s\n", strings.Join(projectKeys, ", "))
+
+	fmt.Fprintln(w.out, "Repository")
+	var suggested []string
+	for {
+		if answers.Repository, err = w.require("  GitHub repository (owner/repo)", "", false); err != nil {
+			return answers, err
+		}
+		labels, err := githubClient.ListLabels(ctx, answers.Repository)
+		if err == nil {
+			suggested = parseBoardLabels(labels, nil)
+			break
+		}
+		fmt.Fprintf(w.out, "  Can't read %s: %v\n", answers.Repository, err)
+	}
+
+	for {
+		boards, err := w.require("  JIRA project key(s), comma-separated", strings.Join(suggested, ","), false)
+		if err != nil {
+			return answers, err
+		}
+		answers.Boards, err = validateBoards(strings.Split(boards, ","), projectKeys)
+		if err == nil {
+			break
+		}
+		fmt.Fprintf(w.out, "  %v\n", err)
+	}
+
+	for _, board := range answers.Boards {
+		for _, typeName := range []string{"Feature", "Story"} {
+			exists, _, err := jiraClient.IssueTypeExists(board, typeName)
+			switch {
+			case err != nil:
+				fmt.Fprintf(w.out, "  %s: can't check issue types: %v\n", board, err)
+			case !exists && typeName == "Feature":
+				fmt.Fprintf(w.out, "  %s: no 'Feature' issue type; glue can't create tickets until it is added\n", board)
+			case !exists:
+				fmt.Fprintf(w.out, "  %s: no 'Story' issue type; stories will be created as features\n", board)
+			}
+		}
+	}
+
+	if answers.StoreTokens, err = w.confirm("Write the tokens to the config file?"); err != nil {
+		return answers, err
+	}
+	return answers, nil
+}
+
+// envOr returns the environment variable name, or def if it is unset or empty.
+func envOr(name, def string) string {
+	if value := os.Getenv(name); value != "" {
+		return value
+	}
+	return def
+}
+
+// renderInitConfig returns the config file written by 'glue init'.
+func renderInitConfig(answers initAnswers) string {
+	var b strings.Builder
+	b.WriteString("# Written by 'glue init'. See the README for all settings.\n")
+	fmt.Fprintf(&b, "github:\n  domain: %s\n", answers.GitHubDomain)
+	if answers.StoreTokens {
+		fmt.Fprintf(&b, "  token: %q\n", answers.GitHubToken)
+	}
+	fmt.Fprintf(&b, "jira:\n  baseurl: %s\n  username: %q\n", answers.JiraURL, answers.JiraUsername)
+	if answers.StoreTokens {
+		fmt.Fprintf(&b, "  token: %q\n", answers.JiraToken)
+	}
+
+	b.WriteString("\n# Used when no --board or --repository is given\n")
+	fmt.Fprintf(&b, "boards: [%s]\n", strings.Join(answers.Boards, ", "))
+	fmt.Fprintf(&b, "flags:\n  repository: %s\n", answers.Repository)
+	if answers.Preset != nil {
+		for _, flag := range answers.Preset.Flags {
+			fmt.Fprintf(&b, "  %s\n", flag)
+		}
+		fmt.Fprintf(&b, "\n%s", answers.Preset.Settings)
+	}
+
+	b.WriteString(`
+# Map JIRA users to GitHub logins, e.g. for --sync-assignees
+# users:
+#   - jira: jane.doe@example.com
+#     github: janedoe
+`)
+	return b.String()
+}
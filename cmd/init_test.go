@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"bufio"
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWizard(t *testing.T) {
+	var out bytes.Buffer
+	w := &wizard{in: bufio.NewReader(strings.NewReader("\n\nvalue\ny\n")), out: &out}
+
+	answer, err := w.ask("Domain", "github.com", false)
+	require.NoError(t, err)
+	assert.Equal(t, "github.com", answer)
+
+	// Empty answers are asked again
+	answer, err = w.require("Repository", "", false)
+	require.NoError(t, err)
+	assert.Equal(t, "value", answer)
+	assert.Contains(t, out.String(), "A value is required.")
+
+	yes, err := w.confirm("Write tokens?")
+	require.NoError(t, err)
+	assert.True(t, yes)
+
+	out.Reset()
+	_, err = w.ask("Token", "secret", true)
+	require.NoError(t, err)
+	assert.NotContains(t, out.String(), "secret")
+
+	_, err = w.require("Repository", "", false)
+	assert.ErrorIs(t, err, errInputEnded)
+}
+
+func TestRenderInitConfig(t *testing.T) {
+	answers := initAnswers{
+		GitHubDomain: "github.com",
+		GitHubToken:  "ghp_token",
+		JiraURL:      "https://example.atlassian.net",
+		JiraUsername: "bot@example.com",
+		JiraToken:    "jira-token",
+		Repository:   "owner/repo",
+		Boards:       []string{"PROJ", "OPS"},
+	}
+
+	rendered := renderInitConfig(answers)
+	assert.NotContains(t, rendered, "ghp_token")
+	assert.NotContains(t, rendered, "jira-token")
+
+	// The written file loads as the intended config
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	answers.StoreTokens = true
+	require.NoError(t, os.WriteFile(path, []byte(renderInitConfig(answers)), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+	for _, env := range []string{"GITHUB_DOMAIN", "GITHUB_TOKEN", "JIRA_URL", "JIRA_USERNAME", "JIRA_TOKEN", "GLUE_PROFILE"} {
+		t.Setenv(env, "")
+	}
+
+	cfg, err := config.LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "github.com", cfg.GitHub.Domain)
+	assert.Equal(t, "ghp_token", cfg.GitHub.Token)
+	assert.Equal(t, "https://example.atlassian.net", cfg.Jira.BaseURL)
+	assert.Equal(t, "bot@example.com", cfg.Jira.Username)
+	assert.Equal(t, "jira-token", cfg.Jira.Token)
+	assert.Equal(t, []string{"PROJ", "OPS"}, cfg.Boards)
+	assert.Equal(t, map[string]interface{}{"repository": "owner/repo"}, cfg.Flags)
+}
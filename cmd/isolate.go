@@ -0,0 +1,30 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+// errIssuePanicked marks the error returned when processing an issue panicked.
+var errIssuePanicked = errors.New("panic while processing issue")
+
+// isolateIssue runs fn for a single issue and converts a panic into an error
+// wrapping errIssuePanicked, so one malformed issue can't abort the whole run.
+// The panic is logged with its stack trace.
+func isolateIssue(issueCtx context.Context, issueNumber int, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w #%d: %v", errIssuePanicked, issueNumber, r)
+			logging.FromContext(issueCtx).Error("recovered from panic while processing issue",
+				"issue_number", issueNumber,
+				"panic", fmt.Sprint(r),
+				"stack", string(debug.Stack()))
+		}
+	}()
+	return fn()
+}
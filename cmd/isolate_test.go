@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsolateIssue(t *testing.T) {
+	ctx := context.Background()
+
+	assert.NoError(t, isolateIssue(ctx, 1, func() error { return nil }))
+
+	failure := errors.New("jira unavailable")
+	assert.Equal(t, failure, isolateIssue(ctx, 2, func() error { return failure }))
+
+	err := isolateIssue(ctx, 3, func() error {
+		var issue *models.GitHubIssue
+		_ = issue.Title // nil dereference
+		return nil
+	})
+	assert.ErrorIs(t, err, errIssuePanicked)
+	assert.Contains(t, err.Error(), "#3")
+	assert.Contains(t, err.Error(), "nil pointer dereference")
+}
@@ -3,15 +3,24 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/danielolaszy/glue/internal/config"
 	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/identity"
 	"github.com/danielolaszy/glue/internal/jira"
 	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/internal/runs"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/internal/statuscache"
 	"github.com/danielolaszy/glue/pkg/models"
 	"github.com/spf13/cobra"
 )
@@ -35,6 +44,15 @@ You can specify multiple boards using -b/--board flag multiple times.
 Example:
   glue jira -r owner/repo -b PROJ1 -b PROJ2
 
+Board auto-discovery:
+- Without -b flags, boards are derived from repository labels of the form 'jira-project: KEY'
+- Each discovered key is validated against the JIRA projects before processing
+- Issues are routed to a board by a 'KEY' or 'jira-project: KEY' label
+
+Board validation:
+- Boards given with -b are case-insensitive and checked against the JIRA projects before syncing
+- Unknown boards fail fast with suggestions for similarly named projects
+
 Issues are categorized and processed based on their labels:
 - GitHub issues with a 'feature' label are created as 'Feature' type in JIRA
 - GitHub issues with a 'story' label are created as 'Story' type in JIRA
@@ -46,7 +64,100 @@ Parent-child relationships:
 - If an issue reference is removed, the corresponding JIRA link will be deleted
 
 Closed issue synchronization:
-- When a GitHub issue is closed, its corresponding JIRA ticket will be transitioned to 'Done'`,
+- When a GitHub issue is closed, its corresponding JIRA ticket will be transitioned to 'Done'
+- With --close-grace-period (e.g. 15m), tickets are only closed once their issue has stayed closed
+  that long, so an issue closed and reopened in quick succession doesn't close its ticket
+
+JIRA outages:
+- Each JIRA request times out after 30s (JIRA_TIMEOUT)
+- After 5 consecutive failed requests (JIRA_MAX_CONSECUTIVE_FAILURES) the circuit breaker trips:
+  no new work is started and the run fails with a summary of what was done
+
+Time budget:
+- Use --max-duration (e.g. 10m) to stop starting new work after that long, for CI jobs with hard timeouts
+- Operations in flight are finished and the run exits successfully; progress is kept in the GitHub
+  issue titles, so the next run picks up the remaining issues
+
+Issue age:
+- Use --max-age (e.g. 365d) to leave issues created longer ago than that out of ticket creation,
+  or --min-age (e.g. 30d) to leave out the newer ones; both accept days, weeks (2w) and durations
+- Issues that already have a ticket are kept in sync whatever their age
+
+Maintenance windows:
+- No changes are made to JIRA during the maintenance_windows in the config file, e.g. JIRA upgrades
+- A run starting in a window exits successfully without syncing, leaving the work for the next run;
+  use --wait-for-maintenance to wait for the window to end instead
+- A window starting mid-run stops new work like --max-duration does
+
+Sync state:
+- The ticket each issue is synced to, when it was last synced and a hash of the description written
+  to it are kept in glue's cache directory
+- An issue whose title lost its [KEY] prefix keeps its ticket instead of getting a duplicate; a
+  different key in the title is warned about and the recorded one is used
+- With --sync-descriptions, tickets whose issue body is unchanged since it was last written aren't
+  fetched again; edits made in JIRA since then are kept
+- Use --no-sync-state to rely on title prefixes alone and compare every description
+
+Run summaries:
+- Each run's counts of created and closed tickets, link changes and failed issues are kept in glue's
+  cache directory, and the run ends by printing how it differs from the previous run, e.g.
+  "+12 tickets created, -3 links created, 2 new failures (#4, #9)"
+
+Concurrent runs:
+- A lock file per repository prevents two glue runs from syncing the same repository at once
+- Locks older than two hours, or held by a process that no longer exists, are treated as stale
+- Use --no-lock to skip locking
+
+Safety config:
+- Repositories and JIRA projects can be allowlisted or denylisted under safety in the config file
+- A run against anything the safety config doesn't permit is refused; use --force to run anyway
+
+Rules script:
+- A Starlark script configured under rules in the config file can decide the boards, issue type,
+  custom fields and skipping of each issue instead of the label heuristics
+
+Lifecycle hooks:
+- Commands configured under hooks.pre_sync, hooks.post_issue and hooks.post_run in the config file
+  receive a JSON payload describing the event on stdin
+- A failing pre_sync hook aborts the run; other hook failures are logged
+
+Acceptance criteria:
+- With acceptance_criteria configured, the checklist under an issue's '### Acceptance Criteria' heading
+  is mirrored to a JIRA field on every run, including which items are ticked
+
+Assignees:
+- Use --sync-assignees to assign unassigned JIRA tickets to the JIRA user of their GitHub assignee
+- GitHub logins are resolved through the users mappings in the config file, then by searching JIRA
+  users for the GitHub user's public email address and name; ambiguous matches are skipped
+
+Reporter attribution:
+- Use --attribute-reporter to report new tickets as the JIRA user of their GitHub issue's author,
+  resolved like assignees
+- Where the author has no JIRA user, or the service account may not set reporters, the service
+  account reports the ticket and a JIRA notes section names the author
+
+Required fields:
+- When JIRA rejects a ticket because the project requires fields glue doesn't set, creation is retried
+  once with the defaults under required_fields in the config file
+- With --prompt-required-fields, values for fields without a default are asked for interactively and
+  reused for later issues on the same board
+
+Pull requests:
+- With --link-pull-requests, merged pull requests are added as remote links, with a comment, to the
+  tickets whose key is in their title or branch, or whose GitHub issue they close ("Fixes #42")
+
+Reverse sync:
+- Use --mirror-jira-labels to copy triage decisions made in JIRA back to GitHub
+- A listed name found among a ticket's labels or components is added as a label to the mapped GitHub issue
+- Labels are only added, never removed
+- When users are mapped in the config file, a JIRA assignee with a GitHub mapping is assigned on GitHub
+- With --hierarchy-from-jira, the '## Issues' section of synced features lists the GitHub issues of the
+  tickets linked to the feature's ticket, between <!-- glue:issues:start --> and <!-- glue:issues:end -->
+  markers, and links are no longer created or removed from the section
+
+Example:
+  glue jira -r owner/repo -b PROJ --mirror-jira-labels needs-design --mirror-jira-labels blocked`,
+	PreRunE: validateFlags(flagRules{Repository: true}),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		repository, err := cmd.Flags().GetString("repository")
 		if err != nil {
@@ -58,18 +169,41 @@ Closed issue synchronization:
 			return err
 		}
 
-		if repository == "" {
-			return fmt.Errorf("repository flag is required")
-		}
-
-		if len(boards) == 0 {
-			return fmt.Errorf("at least one JIRA board must be specified using --board")
+		noLock, err := cmd.Flags().GetBool("no-lock")
+		if err != nil {
+			return err
 		}
 
 		logging.Info("starting synchronization",
 			"repository", repository,
 			"boards", boards)
 
+		ctx := context.Background()
+		startedAt := time.Now()
+
+		maxDuration, err := cmd.Flags().GetDuration("max-duration")
+		if err != nil {
+			return err
+		}
+
+		window, err := ageWindowFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		// workCtx carries the time budget; hooks run on ctx so they aren't cut short
+		workCtx := ctx
+		if maxDuration > 0 {
+			var cancel context.CancelFunc
+			workCtx, cancel = context.WithTimeout(ctx, maxDuration)
+			defer cancel()
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
 		// Initialize clients
 		githubClient, err := github.NewClient()
 		if err != nil {
@@ -80,48 +214,97 @@ Closed issue synchronization:
 		if err != nil {
 			return fmt.Errorf("failed to initialize jira client: %v", err)
 		}
+		if err := configureRequiredFieldPrompt(cmd, jiraClient); err != nil {
+			return err
+		}
+		if err := configureReporter(ctx, cmd, cfg.Users, githubClient, jiraClient); err != nil {
+			return err
+		}
 
-		// Get all issues for all boards in a single query
-		issues, err := githubClient.GetIssuesWithLabels(repository, boards)
+		waitForMaintenance, err := cmd.Flags().GetBool("wait-for-maintenance")
 		if err != nil {
-			return fmt.Errorf("failed to fetch github issues: %v", err)
+			return err
+		}
+		if !awaitMaintenance(workCtx, jiraClient, waitForMaintenance) {
+			return nil
 		}
 
-		// Also get closed issues for relationship mapping
-		closedIssues, err := githubClient.GetClosedIssuesWithLabels(repository, boards)
-		if err != nil {
-			logging.Warn("failed to fetch closed github issues for relationships",
-				"error", err)
+		if len(boards) == 0 {
+			boards, err = discoverBoards(ctx, githubClient, jiraClient, repository, cfg.BoardAliases)
+			if err != nil {
+				return err
+			}
+			if len(boards) == 0 {
+				return fmt.Errorf("no boards specified with --board and no '%s KEY' labels found in %s", boardLabelPrefix, repository)
+			}
 		} else {
-			// Combine open and closed issues for processing
-			issues = append(issues, closedIssues...)
-			logging.Debug("combined issues for processing",
-				"open_count", len(issues)-len(closedIssues),
-				"closed_count", len(closedIssues),
-				"total_count", len(issues))
+			boards, err = resolveBoards(jiraClient, boards)
+			if err != nil {
+				return err
+			}
 		}
 
-		logging.Info("found github issues",
-			"total_count", len(issues),
-			"boards", boards)
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
 
-		// Group issues by board
-		issuesByBoard := make(map[string][]models.GitHubIssue)
-		for _, issue := range issues {
-			for _, board := range boards {
-				if hasLabel(issue.Labels, board) {
-					issuesByBoard[board] = append(issuesByBoard[board], issue)
-					logging.Debug("assigned issue to board",
-						"issue", issue.Number,
-						"board", board,
-						"title", issue.Title)
-				}
-			}
+		// Lock under the current name, so runs given the old and the new name
+		// of a renamed repository exclude each other
+		unlock, err := lockRepository(repository, noLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := checkSafety(cmd, cfg.Safety, repository, boards); err != nil {
+			return err
+		}
+		reportUnsupported(cmd, githubClient, github.FeatureCommitPullRequests)
+
+		brokenStore := trackBrokenMappings(jiraClient, repository)
+		defer saveBrokenMappings(cmd, brokenStore)
+
+		engine, err := loadRules(cfg.Rules)
+		if err != nil {
+			return err
 		}
 
+		noSyncState, err := cmd.Flags().GetBool("no-sync-state")
+		if err != nil {
+			return err
+		}
+
+		var syncState *state.Store
+		if !noSyncState {
+			syncState = trackSyncState(githubClient, repository)
+			defer saveSyncState(syncState)
+		}
+
+		hooks := &syncHooks{repository: repository, config: cfg.Hooks, failed: make(map[int]bool), syncState: syncState}
+		if err := hooks.preSync(ctx, boards); err != nil {
+			return err
+		}
+
+		issuesByBoard, err := fetchIssuesByBoard(ctx, githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+		issuesByBoard = skipLockedIssues(issuesByBoard)
+		issuesByBoard = skipIssuesByAge(issuesByBoard, window, time.Now())
+
+		frontMatter := parseFrontMatter(issuesByBoard)
+		decisions := applyFrontMatter(evaluateRules(engine, issuesByBoard), frontMatter, boards)
+		issuesByBoard = routeByRules(issuesByBoard, boards, decisions, cfg.Routes, cfg.BoardAliases)
+
 		// Process each board with its pre-filtered issues
 		totalSynced := 0
+		budget := newLinkBudget(cfg.Jira)
 		for _, board := range boards {
+			if stopStarting(workCtx, jiraClient) {
+				break
+			}
+
 			boardIssues := issuesByBoard[board]
 			logging.Info("processing board",
 				"board", board,
@@ -132,7 +315,7 @@ Closed issue synchronization:
 				continue
 			}
 
-			syncCount, err := processBoard(repository, board, boardIssues, githubClient, jiraClient)
+			syncCount, err := processBoard(workCtx, repository, board, boardIssues, githubClient, jiraClient, hooks, syncState, decisions, budget)
 			if err != nil {
 				logging.Error("error processing board",
 					"board", board,
@@ -143,20 +326,133 @@ Closed issue synchronization:
 			totalSynced += syncCount
 		}
 
+		hierarchyFromJira, err := cmd.Flags().GetBool("hierarchy-from-jira")
+		if err != nil {
+			return err
+		}
+
 		// After all boards are processed, check and update hierarchies
-		logging.Info("checking issue hierarchies")
-		for _, board := range boards {
-			err := establishHierarchies(context.Background(), githubClient, jiraClient, repository, board, issuesByBoard[board])
+		if hierarchyFromJira {
+			logging.Info("updating issues sections from jira")
+			known := fetchClosedIssuesForBoards(ctx, githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+			for _, board := range boards {
+				known = append(known, issuesByBoard[board]...)
+			}
+			sectionCount := 0
+			seen := make(map[int]bool)
+			for _, board := range boards {
+				sectionCount += syncIssuesSectionsFromJira(workCtx, repository, cfg.GitHub.Domain, issuesByBoard[board], known, seen, syncState, decisions, githubClient, jiraClient)
+			}
+			logging.Info("updated github issues sections from jira", "count", sectionCount)
+		} else {
+			logging.Info("checking issue hierarchies")
+			for _, board := range boards {
+				err := establishHierarchies(workCtx, githubClient, jiraClient, repository, board, issuesByBoard[board], syncState, decisions, budget)
+				if err != nil {
+					logging.Error("failed to establish hierarchies for board",
+						"board", board,
+						"error", err)
+					continue
+				}
+			}
+		}
+
+		syncDescriptions, err := cmd.Flags().GetBool("sync-descriptions")
+		if err != nil {
+			return err
+		}
+
+		if syncDescriptions {
+			updateCount := 0
+			seen := make(map[int]bool)
+			for _, board := range boards {
+				updateCount += syncTicketDescriptions(workCtx, issuesByBoard[board], seen, jiraClient, syncState)
+			}
+			logging.Info("updated jira descriptions", "count", updateCount)
+		}
+
+		if cfg.AcceptanceCriteria.Field != "" {
+			checklistCount := 0
+			seen := make(map[int]bool)
+			for _, board := range boards {
+				checklistCount += syncAcceptanceCriteria(workCtx, issuesByBoard[board], seen, jiraClient)
+			}
+			logging.Info("updated jira acceptance criteria", "count", checklistCount)
+		}
+
+		syncAssignees, err := cmd.Flags().GetBool("sync-assignees")
+		if err != nil {
+			return err
+		}
+
+		if syncAssignees {
+			resolver := identity.NewResolver(cfg.Users, githubClient, jiraClient)
+			assignCount := 0
+			seen := make(map[int]bool)
+			for _, board := range boards {
+				assignCount += syncJiraAssignees(workCtx, issuesByBoard[board], seen, jiraClient, resolver)
+			}
+			logging.Info("assigned jira tickets from github", "count", assignCount)
+		}
+
+		mirrorLabels, err := cmd.Flags().GetStringArray("mirror-jira-labels")
+		if err != nil {
+			return err
+		}
+
+		opts := reverseSyncOptions{MirrorLabels: mirrorLabels, Users: cfg.Users}
+		if opts.enabled() {
+			reverseCount := 0
+			seen := make(map[int]bool)
+			for _, board := range boards {
+				reverseCount += reverseSync(workCtx, repository, issuesByBoard[board], seen, githubClient, jiraClient, opts)
+			}
+			logging.Info("updated github issues from jira", "count", reverseCount)
+		}
+
+		linkPulls, err := cmd.Flags().GetBool("link-pull-requests")
+		if err != nil {
+			return err
+		}
+
+		if linkPulls {
+			var issues []models.GitHubIssue
+			for _, board := range boards {
+				issues = append(issues, issuesByBoard[board]...)
+			}
+			closed, err := githubClient.GetClosedIssues(ctx, repository)
 			if err != nil {
-				logging.Error("failed to establish hierarchies for board",
-					"board", board,
-					"error", err)
-				continue
+				logging.Warn("failed to fetch closed issues, only linking pull requests to open issues' tickets", "error", err)
+			}
+			linkCount, err := linkPullRequests(workCtx, repository, append(issues, closed...), githubClient, jiraClient)
+			if err != nil {
+				logging.Error("failed to link pull requests", "error", err)
+			} else {
+				logging.Info("linked pull requests to jira tickets", "count", linkCount)
+			}
+		}
+
+		closeGracePeriod, err := cmd.Flags().GetDuration("close-grace-period")
+		if err != nil {
+			return err
+		}
+
+		noStatusCache, err := cmd.Flags().GetBool("no-status-cache")
+		if err != nil {
+			return err
+		}
+
+		var cache *statuscache.Cache
+		if !noStatusCache {
+			cache, err = statuscache.Load(statuscache.DefaultDir(), repository)
+			if err != nil {
+				logging.Warn("replacing unreadable status cache", "error", err)
+				cache = statuscache.New(statuscache.DefaultDir(), repository)
 			}
 		}
 
 		// Process all closed issues once
-		closeCount, err := syncClosedIssues(repository, githubClient, jiraClient)
+		closeCount, err := syncClosedIssues(workCtx, repository, githubClient, jiraClient, syncState, closeGracePeriod, cache)
 		if err != nil {
 			logging.Error("failed to sync closed issues",
 				"error", err)
@@ -165,9 +461,44 @@ Closed issue synchronization:
 				"count", closeCount)
 		}
 
+		summary := runs.Summary{
+			StartedAt:    startedAt.UTC(),
+			FinishedAt:   time.Now().UTC(),
+			Boards:       boards,
+			Created:      totalSynced,
+			Closed:       closeCount,
+			LinksCreated: budget.created,
+			LinksRemoved: budget.removed,
+			Failed:       failedIssues(hooks.failed),
+		}
+
+		if err := jiraClient.CircuitOpen(); err != nil {
+			summary.Aborted = true
+			recordRun(cmd.OutOrStdout(), runs.DefaultDir(), repository, summary)
+			logging.Error("synchronization aborted, jira is unavailable",
+				"total_synchronized", totalSynced,
+				"closed", closeCount,
+				"error", err)
+			hooks.postRun(ctx, boards, totalSynced, closeCount)
+			return fmt.Errorf("synchronization aborted after %d tickets created and %d closed: %v", totalSynced, closeCount, err)
+		}
+
+		if workCtx.Err() != nil {
+			logging.Warn("time budget reached, remaining work is left for the next run",
+				"max_duration", maxDuration)
+		}
+		if window, until, ok := jiraClient.Maintenance(time.Now()); ok {
+			logging.Warn("maintenance window started, remaining work is left for the next run",
+				"window", window.Name,
+				"until", until)
+		}
+
 		logging.Info("synchronization complete",
 			"total_synchronized", totalSynced,
 			"boards_processed", len(boards))
+		recordRun(cmd.OutOrStdout(), runs.DefaultDir(), repository, summary)
+
+		hooks.postRun(ctx, boards, totalSynced, closeCount)
 
 		return nil
 	},
@@ -176,21 +507,132 @@ Closed issue synchronization:
 func init() {
 	rootCmd.AddCommand(jiraCmd)
 	jiraCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to sync with (can be specified multiple times)")
+	jiraCmd.Flags().Bool("no-lock", false, "Skip the per-repository lock that prevents concurrent runs")
+	jiraCmd.Flags().Duration("max-duration", 0, "Stop starting new work after this long (e.g. 10m, 0 for no limit)")
+	jiraCmd.Flags().Bool("wait-for-maintenance", false, "Wait for a JIRA maintenance window to end instead of leaving the sync to the next run")
+	jiraCmd.Flags().Bool("sync-descriptions", false, "Update JIRA descriptions from GitHub, saving the previous description as a comment")
+	jiraCmd.Flags().Bool("sync-assignees", false, "Assign unassigned JIRA tickets to the JIRA user of their GitHub assignee")
+	jiraCmd.Flags().Duration("close-grace-period", 0, "Only close JIRA tickets of issues closed at least this long ago (e.g. 15m), so issues reopened quickly don't close their tickets")
+	jiraCmd.Flags().Bool("no-status-cache", false, "Check the JIRA status of every closed issue's ticket, including those recorded as done by earlier runs")
+	jiraCmd.Flags().Bool("no-sync-state", false, "Rely on issue title prefixes alone and compare every description with JIRA, ignoring the sync state recorded by earlier runs")
+	jiraCmd.Flags().Bool("hierarchy-from-jira", false, "Regenerate the '## Issues' section of feature issues from the links of their JIRA tickets instead of linking tickets from the section")
+	jiraCmd.Flags().Bool("link-pull-requests", false, "Link merged pull requests to the JIRA tickets whose key is in their title or branch, or whose issue they close")
+	jiraCmd.Flags().StringArray("mirror-jira-labels", []string{}, "JIRA label or component to mirror back as a GitHub label (can be specified multiple times)")
+
+	jiraCmd.PersistentFlags().Bool("prompt-required-fields", false, "Ask for values of fields JIRA requires on creation that have no default under required_fields in the config file")
+	jiraCmd.PersistentFlags().Bool("attribute-reporter", false, "Report new JIRA tickets as the JIRA user of their GitHub issue's author, where permissions allow")
+	jiraCmd.PersistentFlags().String("min-age", "", "Only create tickets for issues created at least this long ago, e.g. 30d, 2w or 72h")
+	jiraCmd.PersistentFlags().String("max-age", "", "Only create tickets for issues created at most this long ago, e.g. 365d")
+	jiraCmd.PersistentFlags().Bool("force", false, "Run even if the safety config doesn't permit changing the repository or board")
+	jiraCmd.AddCommand(jiraRollbackCmd)
+	jiraCmd.AddCommand(jiraBackfillCmd)
 }
 
-// processBoard handles all operations for a single board
-func processBoard(repository string, board string, issues []models.GitHubIssue, githubClient *github.Client, jiraClient *jira.Client) (int, error) {
-	// Get issue type IDs once for this board
-	featureTypeID, err := jiraClient.GetIssueTypeID(board, "feature")
+// checkRepository returns the current name of the repository, following a
+// rename, or an error if the repository is archived, since the title, label
+// and assignee updates glue makes to its issues would all fail.
+func checkRepository(ctx context.Context, cmd *cobra.Command, githubClient *github.Client, repository string) (string, error) {
+	repo, err := githubClient.GetRepository(ctx, repository)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get 'feature' type ID: %v", err)
+		return "", err
 	}
+	if repo.Archived {
+		return "", fmt.Errorf("repository %s is archived and its issues can't be updated; unarchive it to sync", repository)
+	}
+	return followRename(cmd, repository, repo.FullName), nil
+}
 
-	storyTypeID, err := jiraClient.GetIssueTypeID(board, "story")
+// reportUnsupported tells the user which of the features the command uses
+// their GitHub Enterprise Server release lacks, so that the missing output
+// isn't a surprise. Those features are skipped rather than failing the run.
+func reportUnsupported(cmd *cobra.Command, githubClient *github.Client, features ...github.Feature) {
+	for _, feature := range features {
+		if err := githubClient.Require(feature); err != nil {
+			fmt.Fprintf(cmd.ErrOrStderr(), "Skipping %v\n", err)
+		}
+	}
+}
+
+// skipLockedIssues leaves out the open issues whose conversation is locked,
+// since glue's updates to them would fail. Locked closed issues are kept so
+// their tickets still get closed.
+func skipLockedIssues(issuesByBoard map[string][]models.GitHubIssue) map[string][]models.GitHubIssue {
+	result := make(map[string][]models.GitHubIssue, len(issuesByBoard))
+	for board, issues := range issuesByBoard {
+		kept := make([]models.GitHubIssue, 0, len(issues))
+		for _, issue := range issues {
+			if skippedAsLocked(issue) {
+				logging.Warn("skipping locked github issue, unlock it to sync",
+					"issue_number", issue.Number,
+					"board", board)
+				continue
+			}
+			kept = append(kept, issue)
+		}
+		result[board] = kept
+	}
+	return result
+}
+
+// skippedAsLocked reports whether the sync leaves an issue out because it is
+// locked. Closed issues are kept, so their tickets are still closed.
+func skippedAsLocked(issue models.GitHubIssue) bool {
+	return issue.Locked && issue.State != "closed"
+}
+
+// fetchIssuesByBoard retrieves the open and closed GitHub issues routed to any
+// of the boards, by routes or board labels, and groups them by board. An
+// issue routed to several boards appears in each of their groups.
+func fetchIssuesByBoard(ctx context.Context, githubClient *github.Client, repository string, boards []string, routes []config.Route, aliases config.BoardAliases) (map[string][]models.GitHubIssue, error) {
+	// Get all issues for all boards in a single query
+	issues, err := githubClient.GetIssuesWithLabels(ctx, repository, append(boardLabels(boards, aliases), routeLabels(routes, boards)...))
 	if err != nil {
-		logging.Warn("failed to get 'story' type ID, using feature type",
-			"board", board)
-		storyTypeID = featureTypeID
+		return nil, fmt.Errorf("failed to fetch github issues: %v", err)
+	}
+
+	// Also get closed issues for relationship mapping
+	closedIssues := fetchClosedIssuesForBoards(ctx, githubClient, repository, boards, routes, aliases)
+	issues = append(issues, closedIssues...)
+	applyIssueTypes(ctx, githubClient, repository, issues)
+	logging.Debug("combined issues for processing",
+		"open_count", len(issues)-len(closedIssues),
+		"closed_count", len(closedIssues),
+		"total_count", len(issues))
+
+	logging.Info("found github issues",
+		"total_count", len(issues),
+		"boards", boards)
+
+	return groupIssuesByBoard(issues, boards, routes, aliases), nil
+}
+
+// groupIssuesByBoard returns the issues routed to each of the boards, by
+// board. An issue routed to several boards is listed under each.
+func groupIssuesByBoard(issues []models.GitHubIssue, boards []string, routes []config.Route, aliases config.BoardAliases) map[string][]models.GitHubIssue {
+	issuesByBoard := make(map[string][]models.GitHubIssue)
+	for _, issue := range issues {
+		for _, board := range boards {
+			if routesTo(issue, board, routes, aliases) {
+				issuesByBoard[board] = append(issuesByBoard[board], issue)
+				logging.Debug("assigned issue to board",
+					"issue", issue.Number,
+					"board", board,
+					"title", issue.Title)
+			}
+		}
+	}
+	return issuesByBoard
+}
+
+// processBoard handles all operations for a single board.
+// Rules decisions, keyed by issue number, take precedence over labels.
+// Issues with a key in syncState, which may be nil, or their title are
+// synced already.
+func processBoard(ctx context.Context, repository string, board string, issues []models.GitHubIssue, githubClient *github.Client, jiraClient *jira.Client, hooks *syncHooks, syncState *state.Store, decisions map[int]rules.Decision, budget *linkBudget) (int, error) {
+	// Get issue type IDs once for this board
+	featureTypeID, storyTypeID, err := issueTypeIDs(jiraClient, board)
+	if err != nil {
+		return 0, err
 	}
 
 	// Group issues by type
@@ -198,15 +640,23 @@ func processBoard(repository string, board string, issues []models.GitHubIssue,
 	skippedCount := 0
 
 	for _, issue := range issues {
-		if hasJiraIDPrefix(issue.Title) {
+		if issueSynced(syncState, issue) {
 			continue // Skip already synced issues
 		}
 
-		if hasLabel(issue.Labels, "feature") {
+		if decisions[issue.Number].Skip {
+			logging.Info("skipping issue by rules decision",
+				"issue_number", issue.Number,
+				"title", issue.Title)
+			continue
+		}
+
+		switch issueTypeFor(issue, decisions) {
+		case "feature":
 			features = append(features, issue)
-		} else if hasLabel(issue.Labels, "story") {
+		case "story":
 			stories = append(stories, issue)
-		} else {
+		default:
 			// Skip issues without feature or story labels
 			skippedCount++
 			logging.Warn("skipping issue without feature or story label",
@@ -225,7 +675,7 @@ func processBoard(repository string, board string, issues []models.GitHubIssue,
 	var allUpdatedIssues []models.GitHubIssue
 
 	// Process features
-	updatedFeatures, syncCount, err := processIssueGroup(features, featureTypeID, board, repository, githubClient, jiraClient)
+	updatedFeatures, syncCount, err := processIssueGroup(ctx, features, featureTypeID, board, repository, githubClient, jiraClient, hooks, decisions)
 	if err != nil {
 		logging.Error("error processing features", "error", err)
 	} else {
@@ -234,7 +684,7 @@ func processBoard(repository string, board string, issues []models.GitHubIssue,
 	}
 
 	// Process stories only (removed 'others' group)
-	updatedStories, syncCount, err := processIssueGroup(stories, storyTypeID, board, repository, githubClient, jiraClient)
+	updatedStories, syncCount, err := processIssueGroup(ctx, stories, storyTypeID, board, repository, githubClient, jiraClient, hooks, decisions)
 	if err != nil {
 		logging.Error("error processing stories", "error", err)
 	} else {
@@ -244,19 +694,109 @@ func processBoard(repository string, board string, issues []models.GitHubIssue,
 
 	// Process hierarchies
 	if len(allUpdatedIssues) > 0 {
-		if err := establishHierarchies(context.Background(), githubClient, jiraClient, repository, board, allUpdatedIssues); err != nil {
+		if err := establishHierarchies(ctx, githubClient, jiraClient, repository, board, allUpdatedIssues, syncState, decisions, budget); err != nil {
 			logging.Error("error establishing hierarchies",
 				"board", board,
 				"error", err)
 		}
 	}
 
+	// Apply org-specific conventions to the new tickets
+	var createdKeys []string
+	for _, issue := range allUpdatedIssues {
+		if key := marker.GitHub.Key(issue.Title); key != "" {
+			createdKeys = append(createdKeys, key)
+		}
+	}
+	hooks.postCreate(jiraClient, createdKeys)
+
 	return totalSyncCount, nil
 }
 
+// issueTypeIDs returns the JIRA type IDs used for features and stories on a
+// board. Boards without a Story type use the Feature type for stories. Boards
+// creating service management requests have no type IDs, their request type
+// sets the type.
+func issueTypeIDs(jiraClient *jira.Client, board string) (string, string, error) {
+	if jiraClient.UsesRequestType(board) {
+		return "", "", nil
+	}
+
+	featureTypeID, err := jiraClient.GetIssueTypeID(board, "feature")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to get 'feature' type ID: %v", err)
+	}
+
+	storyTypeID, err := jiraClient.GetIssueTypeID(board, "story")
+	if err != nil {
+		logging.Warn("failed to get 'story' type ID, using feature type",
+			"board", board)
+		storyTypeID = featureTypeID
+	}
+
+	return featureTypeID, storyTypeID, nil
+}
+
 // Helper functions
-func hasJiraIDPrefix(title string) bool {
-	return regexp.MustCompile(`^\[[A-Z]+-\d+\]`).MatchString(title)
+
+// stopStarting reports whether no new work should be started, because the
+// run's time budget carried by ctx is spent, the JIRA circuit breaker is open
+// or a maintenance window has started.
+func stopStarting(ctx context.Context, jiraClient *jira.Client) bool {
+	if ctx.Err() != nil || jiraClient.CircuitOpen() != nil {
+		return true
+	}
+	_, _, inMaintenance := jiraClient.Maintenance(time.Now())
+	return inMaintenance
+}
+
+// issueTypeOf returns the JIRA issue type ("feature" or "story") that glue
+// creates for an issue, or an empty string if the issue is skipped. A native
+// GitHub issue type takes precedence over the labels: "Feature" maps to a
+// feature and any other type, like "Bug" or "Task", to a story.
+func issueTypeOf(issue models.GitHubIssue) string {
+	if issue.Type != "" {
+		if strings.EqualFold(issue.Type, "feature") {
+			return "feature"
+		}
+		return "story"
+	}
+	return issueTypeForLabels(issue.Labels)
+}
+
+// applyIssueTypes sets the native GitHub issue types of issues. Where the
+// types can't be read, e.g. on GitHub Enterprise Server releases without
+// them, the issues are left untyped and their labels decide.
+func applyIssueTypes(ctx context.Context, githubClient *github.Client, repository string, issues []models.GitHubIssue) {
+	if len(issues) == 0 {
+		return
+	}
+	numbers := make([]int, len(issues))
+	for i, issue := range issues {
+		numbers[i] = issue.Number
+	}
+
+	types, err := githubClient.GetIssueTypes(ctx, repository, numbers)
+	if err != nil {
+		logging.Debug("native issue types unavailable, using labels", "error", err)
+		return
+	}
+	for i := range issues {
+		issues[i].Type = types[issues[i].Number]
+	}
+}
+
+// issueTypeForLabels returns the JIRA issue type ("feature" or "story") that
+// glue creates for an issue with the given labels, or an empty string if the
+// issue is skipped. The 'feature' label takes precedence over 'story'.
+func issueTypeForLabels(labels []string) string {
+	if hasLabel(labels, "feature") {
+		return "feature"
+	}
+	if hasLabel(labels, "story") {
+		return "story"
+	}
+	return ""
 }
 
 func hasLabel(labels []string, targetLabel string) bool {
@@ -268,18 +808,6 @@ func hasLabel(labels []string, targetLabel string) bool {
 	return false
 }
 
-// parseJiraIDFromTitle extracts a JIRA ticket ID from a GitHub issue title.
-// It looks for a pattern like "[PROJ-123] Issue title" and returns "PROJ-123".
-// If no JIRA ID is found, it returns an empty string.
-func parseJiraIDFromTitle(title string) string {
-	re := regexp.MustCompile(`^\[([\w\-]+)\]`)
-	matches := re.FindStringSubmatch(title)
-	if len(matches) > 1 {
-		return matches[1]
-	}
-	return ""
-}
-
 // findIssuesSection extracts the "## Issues" section from an issue description.
 // It returns the content between "## Issues" and the next section header (if any).
 // If no "## Issues" section is found, it returns an empty string.
@@ -333,50 +861,145 @@ func parseChildIssues(description string, gitHubDomain string) []int {
 // It creates tickets in the specified JIRA board with the given type ID,
 // updates the GitHub issue titles to include the JIRA ticket ID, and returns
 // the updated issues along with a count of successfully synchronized issues.
-func processIssueGroup(issues []models.GitHubIssue, typeID string, board string, repository string, githubClient *github.Client, jiraClient *jira.Client) ([]models.GitHubIssue, int, error) {
-	var updatedIssues []models.GitHubIssue
-	syncCount := 0
+// Each issue is processed with its own trace ID so its log lines can be correlated,
+// and the post_issue hooks are run with the outcome. Fields from rules decisions
+// are set on the new tickets. A panic while processing an issue is reported as
+// a failure of that issue and doesn't stop the others.
+func processIssueGroup(ctx context.Context, issues []models.GitHubIssue, typeID string, board string, repository string, githubClient *github.Client, jiraClient *jira.Client, hooks *syncHooks, decisions map[int]rules.Decision) ([]models.GitHubIssue, int, error) {
+	tracker := &jiraTracker{
+		repository:   repository,
+		board:        board,
+		typeID:       typeID,
+		githubClient: githubClient,
+		jiraClient:   jiraClient,
+		hooks:        hooks,
+		decisions:    decisions,
+	}
+	counts := syncTracker(ctx, tracker, issues, nil, trackerSync{})
+	return tracker.created, counts.Created, nil
+}
 
-	for _, issue := range issues {
-		ticketID, err := jiraClient.CreateTicketWithTypeID(board, issue, typeID)
-		if err != nil {
-			logging.Error("failed to create ticket",
-				"issue_number", issue.Number,
-				"error", err)
-			continue
-		}
+// jiraTracker is the Tracker of the JIRA tickets of a repository's issues.
+// It creates tickets on board with the type typeID, and closes them with a
+// comment telling how their issue was closed.
+type jiraTracker struct {
+	repository   string
+	board        string
+	typeID       string
+	githubClient *github.Client
+	jiraClient   *jira.Client
+	hooks        *syncHooks
+	decisions    map[int]rules.Decision
+	// syncState, which may be nil, is consulted for the keys of the tickets
+	syncState *state.Store
+	// cache, which may be nil, records the tickets closed
+	cache *statuscache.Cache
+	// created collects the issues tickets were created for, with the keys in
+	// their titles
+	created []models.GitHubIssue
+}
 
-		newTitle := fmt.Sprintf("[%s] %s", ticketID, issue.Title)
-		err = githubClient.UpdateIssueTitle(repository, issue.Number, newTitle)
-		if err != nil {
-			logging.Error("failed to update github issue title",
-				"issue_number", issue.Number,
-				"error", err)
-			continue
-		}
+// Noun implements Tracker.
+func (t *jiraTracker) Noun() string {
+	return "jira ticket"
+}
 
-		updatedIssue, err := githubClient.GetIssue(repository, issue.Number)
-		if err != nil {
-			logging.Error("failed to fetch updated issue",
-				"issue_number", issue.Number,
+// Item implements Tracker. Whether a ticket is done is only checked when it
+// is closed.
+func (t *jiraTracker) Item(issue models.GitHubIssue) (trackerItem, bool) {
+	key := issueKey(t.syncState, issue)
+	return trackerItem{ID: key}, key != ""
+}
+
+// Where implements Tracker.
+func (t *jiraTracker) Where(issue models.GitHubIssue) string {
+	return fmt.Sprintf(" in %s", t.board)
+}
+
+// Create implements Tracker, and runs the post_issue hooks with the outcome.
+func (t *jiraTracker) Create(ctx context.Context, issue models.GitHubIssue) (trackerItem, error) {
+	var updated models.GitHubIssue
+	err := isolateIssue(ctx, issue.Number, func() error {
+		var err error
+		updated, err = createTicketForIssue(ctx, issue, t.typeID, t.board, t.repository, t.githubClient, t.jiraClient, t.hooks, t.decisions)
+		return err
+	})
+	if errors.Is(err, errIssuePanicked) {
+		t.hooks.postIssue(ctx, t.board, issue, "", err)
+	}
+	if err != nil {
+		return trackerItem{}, err
+	}
+	t.created = append(t.created, updated)
+	return trackerItem{ID: marker.GitHub.Key(updated.Title)}, nil
+}
+
+// Close implements Tracker.
+func (t *jiraTracker) Close(ctx context.Context, issue models.GitHubIssue, item trackerItem) (bool, error) {
+	closed, err := closeIssueTicket(ctx, t.repository, issue, item.ID, t.githubClient, t.jiraClient)
+	if err == nil && t.cache != nil {
+		t.cache.MarkDone(item.ID, time.Now())
+	}
+	return closed, err
+}
+
+// Stopped implements trackerStopper: no issue is started once JIRA is
+// unavailable.
+func (t *jiraTracker) Stopped(ctx context.Context) bool {
+	return stopStarting(ctx, t.jiraClient)
+}
+
+// createTicketForIssue creates the JIRA ticket of a single issue, prefixes the
+// issue title with the ticket key, applies the synced_labels rule of the
+// repository and returns the updated issue. Failures are reported to the
+// post_issue hooks before they are returned.
+func createTicketForIssue(issueCtx context.Context, issue models.GitHubIssue, typeID string, board string, repository string, githubClient *github.Client, jiraClient *jira.Client, hooks *syncHooks, decisions map[int]rules.Decision) (models.GitHubIssue, error) {
+	log := logging.FromContext(issueCtx)
+	issueJira := jiraClient.WithLogger(log)
+	issueGitHub := githubClient.WithLogger(log)
+
+	ticketID, err := issueJira.CreateTicketWithTypeID(board, issue, typeID)
+	if err != nil {
+		hooks.postIssue(issueCtx, board, issue, "", err)
+		return models.GitHubIssue{}, err
+	}
+
+	if fields := decisions[issue.Number].Fields; len(fields) > 0 {
+		if err := issueJira.UpdateFields(ticketID, fields); err != nil {
+			log.Error("failed to set fields from rules decision",
+				"jira_ticket", ticketID,
 				"error", err)
-			continue
 		}
+	}
 
-		updatedIssues = append(updatedIssues, updatedIssue)
-		syncCount++
+	// Once the ticket exists its key must reach the issue title even if the
+	// time budget runs out meanwhile, or the next run would create it again
+	apiCtx := context.WithoutCancel(issueCtx)
+
+	err = issueGitHub.MarkSynced(apiCtx, repository, issue, ticketID, board)
+	if err != nil {
+		err = fmt.Errorf("failed to mark github issue as synced with %s: %v", ticketID, err)
+		hooks.postIssue(issueCtx, board, issue, ticketID, err)
+		return models.GitHubIssue{}, err
 	}
+	hooks.postIssue(issueCtx, board, issue, ticketID, nil)
 
-	return updatedIssues, syncCount, nil
+	updatedIssue, err := issueGitHub.GetIssue(apiCtx, repository, issue.Number)
+	if err != nil {
+		return models.GitHubIssue{}, fmt.Errorf("failed to fetch updated issue: %v", err)
+	}
+
+	return updatedIssue, nil
 }
 
 // buildGitHubToJiraMap creates a mapping of GitHub issue numbers to JIRA ticket IDs.
-// It extracts JIRA IDs from GitHub issue titles and returns a map where the key
-// is the GitHub issue number and the value is the corresponding JIRA ticket ID.
-func buildGitHubToJiraMap(issues []models.GitHubIssue) map[int]string {
+// It looks the JIRA IDs up in syncState, which may be nil, falling back to the
+// GitHub issue titles, and returns a map where the key is the GitHub issue
+// number and the value is the corresponding JIRA ticket ID.
+func buildGitHubToJiraMap(issues []models.GitHubIssue, syncState *state.Store) map[int]string {
 	githubToJira := make(map[int]string)
 	for _, issue := range issues {
-		if jiraID := parseJiraIDFromTitle(issue.Title); jiraID != "" {
+		if jiraID := issueKey(syncState, issue); jiraID != "" {
 			githubToJira[issue.Number] = jiraID
 			logging.Debug("mapped github issue to jira",
 				"github_number", issue.Number,
@@ -387,71 +1010,64 @@ func buildGitHubToJiraMap(issues []models.GitHubIssue) map[int]string {
 }
 
 // processFeatureLinks handles the creation and maintenance of parent-child relationships
-// between JIRA tickets. It processes a GitHub feature issue, extracts child issue references,
-// creates links to child tickets in JIRA, and removes obsolete links.
-// Returns the count of links created and removed, along with any error encountered.
-func processFeatureLinks(feature models.GitHubIssue, githubToJira map[int]string, jiraClient *jira.Client, gitHubDomain string) (int, int, error) {
-	linksCreated := 0
-	linksRemoved := 0
-
-	parentJiraID := parseJiraIDFromTitle(feature.Title)
+// between JIRA tickets. It links the ticket of a GitHub feature issue to the tickets of
+// childNums, the issues it is linked to under the multiple-parents policy, and removes
+// obsolete links, in batches paced and capped by budget. Returns the count of links
+// created and removed, along with any error encountered.
+func processFeatureLinks(ctx context.Context, feature models.GitHubIssue, childNums []int, githubToJira map[int]string, jiraClient *jira.Client, budget *linkBudget) (int, int, error) {
+	log := logging.FromContext(ctx)
+	jiraClient = jiraClient.WithLogger(log)
+
+	parentJiraID := marker.GitHub.Key(feature.Title)
 	if parentJiraID == "" {
 		return 0, 0, nil
 	}
 
-	childNums := parseChildIssues(feature.Description, gitHubDomain)
-	if len(childNums) == 0 {
-		return 0, 0, nil
-	}
-
-	logging.Debug("found child issues in feature description",
+	log.Debug("found child issues in feature description",
 		"parent_jira", parentJiraID,
-		"child_count", len(childNums),
-		"github_domain", gitHubDomain)
+		"child_count", len(childNums))
 
 	existingLinks, err := jiraClient.GetIssueLinks(parentJiraID)
 	if err != nil {
 		return 0, 0, fmt.Errorf("failed to get existing links: %v", err)
 	}
 
+	var changes []linkChange
 	validChildren := make(map[string]bool)
 	for _, num := range childNums {
 		childJiraID, exists := githubToJira[num]
 		if !exists {
-			logging.Debug("no JIRA ID found for GitHub issue",
+			log.Debug("no JIRA ID found for GitHub issue",
 				"github_number", num)
 			continue
 		}
 
-		validChildren[childJiraID] = true
-
-		if !existingLinks[childJiraID] {
-			err := jiraClient.CreateParentChildLink(parentJiraID, childJiraID)
-			if err != nil {
-				logging.Error("failed to create parent-child link",
-					"error", err,
-					"parent", parentJiraID,
-					"child", childJiraID)
-			} else {
-				linksCreated++
-			}
+		if !existingLinks[childJiraID] && !validChildren[childJiraID] {
+			changes = append(changes, linkChange{Child: childJiraID})
 		}
+		validChildren[childJiraID] = true
 	}
 
 	// Remove invalid links
+	var obsolete []string
 	for childID := range existingLinks {
 		if !validChildren[childID] {
-			err := jiraClient.DeleteIssueLink(parentJiraID, childID)
-			if err != nil {
-				logging.Error("failed to remove parent-child link",
-					"error", err,
-					"parent", parentJiraID,
-					"child", childID)
-			} else {
-				linksRemoved++
-			}
+			obsolete = append(obsolete, childID)
 		}
 	}
+	sort.Strings(obsolete)
+	for _, childID := range obsolete {
+		changes = append(changes, linkChange{Child: childID, Remove: true})
+	}
+
+	linksCreated, linksRemoved := applyLinkChanges(parentJiraID, changes, budget,
+		func() bool { return stopStarting(ctx, jiraClient) },
+		func(change linkChange) error {
+			if change.Remove {
+				return jiraClient.DeleteIssueLink(parentJiraID, change.Child)
+			}
+			return jiraClient.CreateParentChildLink(parentJiraID, change.Child)
+		})
 
 	return linksCreated, linksRemoved, nil
 }
@@ -460,7 +1076,7 @@ func processFeatureLinks(feature models.GitHubIssue, githubToJira map[int]string
 // in both GitHub and JIRA. It builds a mapping between GitHub issues and their
 // corresponding JIRA tickets, then processes feature issues to establish
 // hierarchical relationships based on the "## Issues" section in their descriptions.
-func establishHierarchies(ctx context.Context, ghClient *github.Client, jiraClient *jira.Client, repository string, board string, issues []models.GitHubIssue) error {
+func establishHierarchies(ctx context.Context, ghClient *github.Client, jiraClient *jira.Client, repository string, board string, issues []models.GitHubIssue, syncState *state.Store, decisions map[int]rules.Decision, budget *linkBudget) error {
 	// Get config for GitHub domain
 	cfg, err := config.LoadConfig()
 	if err != nil {
@@ -471,28 +1087,48 @@ func establishHierarchies(ctx context.Context, ghClient *github.Client, jiraClie
 	allIssues := make([]models.GitHubIssue, len(issues))
 	copy(allIssues, issues)
 
-	closedIssues, err := ghClient.GetClosedIssuesWithLabels(repository, []string{board})
-	if err != nil {
-		logging.Warn("failed to fetch closed issues for hierarchy mapping",
-			"error", err,
-			"board", board)
-	} else {
-		allIssues = append(allIssues, closedIssues...)
-	}
+	allIssues = append(allIssues, fetchClosedIssuesForBoards(ctx, ghClient, repository, []string{board}, cfg.Routes, cfg.BoardAliases)...)
 
 	// Build GitHub to JIRA mapping
-	githubToJira := buildGitHubToJiraMap(allIssues)
+	githubToJira := buildGitHubToJiraMap(allIssues, syncState)
 
+	linkFeatures(ctx, jiraClient, cfg, board, issues, githubToJira, decisions, budget)
+	return nil
+}
+
+// linkFeatures links the tickets of the features among issues to the tickets
+// of their children, as listed in their '## Issues' section and mapped to
+// tickets by githubToJira. Failures are logged per feature.
+func linkFeatures(ctx context.Context, jiraClient *jira.Client, cfg *config.Config, board string, issues []models.GitHubIssue, githubToJira map[int]string, decisions map[int]rules.Decision, budget *linkBudget) {
 	totalLinksCreated := 0
 	totalLinksRemoved := 0
 
-	// Process each feature
+	var features []models.GitHubIssue
 	for _, issue := range issues {
-		if !hasLabel(issue.Labels, "feature") {
+		if issueTypeFor(issue, decisions) == "feature" {
+			features = append(features, issue)
+		}
+	}
+	linked, multiple := featureChildren(features, cfg.GitHub.Domain, cfg.Jira.MultipleParents)
+	reportMultipleParents(board, multiple, cfg.Jira.MultipleParents)
+
+	// Process each feature
+	for _, issue := range features {
+		if stopStarting(ctx, jiraClient) {
+			break
+		}
+		children, ok := linked[issue.Number]
+		if !ok {
 			continue
 		}
 
-		created, removed, err := processFeatureLinks(issue, githubToJira, jiraClient, cfg.GitHub.Domain)
+		featureCtx := logging.WithTraceID(ctx, "issue_number", issue.Number)
+		var created, removed int
+		err := isolateIssue(featureCtx, issue.Number, func() error {
+			var err error
+			created, removed, err = processFeatureLinks(featureCtx, issue, children, githubToJira, jiraClient, budget)
+			return err
+		})
 		if err != nil {
 			logging.Error("error processing feature links",
 				"error", err,
@@ -507,54 +1143,187 @@ func establishHierarchies(ctx context.Context, ghClient *github.Client, jiraClie
 	logging.Info("parent-child relationship synchronization complete",
 		"board", board,
 		"relationships_created", totalLinksCreated,
-		"relationships_removed", totalLinksRemoved)
-
-	return nil
+		"relationships_removed", totalLinksRemoved,
+		"multiple_parent_stories", len(multiple))
 }
 
-// syncClosedIssues handles synchronization of closed GitHub issues to JIRA.
-// It identifies GitHub issues that have been closed but their corresponding
-// JIRA tickets are still open, and closes those JIRA tickets.
-// Returns the count of JIRA tickets that were closed and any error encountered.
-func syncClosedIssues(repository string, githubClient *github.Client, jiraClient *jira.Client) (int, error) {
-	logging.Info("checking for closed github issues", "repository", repository)
+// syncTicketDescriptions updates the descriptions of JIRA tickets whose GitHub
+// issue body has changed. The previous description is saved as a comment on the
+// ticket so it can be restored with 'glue jira rollback'. Issues already present
+// in seen are skipped, and so are those whose description is recorded in
+// syncState as synced already; a nil syncState checks every ticket. Returns
+// the number of updated tickets.
+func syncTicketDescriptions(ctx context.Context, issues []models.GitHubIssue, seen map[int]bool, jiraClient *jira.Client, syncState *state.Store) int {
+	updateCount := 0
+	for _, issue := range issues {
+		if stopStarting(ctx, jiraClient) {
+			break
+		}
+		jiraID := issueKey(syncState, issue)
+		if jiraID == "" || seen[issue.Number] {
+			continue
+		}
+		seen[issue.Number] = true
 
-	closedIssues, err := githubClient.GetClosedIssues(repository)
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch closed GitHub issues: %v", err)
-	}
+		log := logging.FromContext(logging.WithTraceID(ctx, "issue_number", issue.Number, "jira_ticket", jiraID))
+		issueJira := jiraClient.WithLogger(log)
 
-	closeCount := 0
-	for _, issue := range closedIssues {
-		jiraID := parseJiraIDFromTitle(issue.Title)
-		if jiraID == "" {
+		hash := contentHash(issueJira.DescriptionFor(issue))
+		if syncState != nil && syncState.DescriptionSynced(issue.Number, jiraID, hash) {
 			continue
 		}
 
-		status, err := jiraClient.GetTicketStatus(jiraID)
+		ticket, err := issueJira.GetTicket(jiraID)
 		if err != nil {
-			logging.Error("failed to get jira ticket status",
-				"issue_number", issue.Number,
-				"jira_ticket", jiraID,
-				"error", err)
+			log.Error("failed to get jira ticket", "error", err)
 			continue
 		}
 
-		if status == "Done" {
+		// Only the synced part counts; JIRA notes are kept on update
+		if contentHash(jira.SyncedDescription(ticket.Description)) != hash {
+			if err := issueJira.UpdateTicketDescription(jiraID, issue); err != nil {
+				log.Error("failed to update jira description", "error", err)
+				continue
+			}
+			updateCount++
+		}
+		if syncState != nil {
+			syncState.MarkDescriptionSynced(issue.Number, jiraID, hash, time.Now())
+		}
+	}
+	return updateCount
+}
+
+// syncAcceptanceCriteria mirrors the acceptance criteria checklists of synced
+// GitHub issues to their JIRA tickets, so completion state ticked off on GitHub
+// shows up in JIRA. Issues already present in seen are skipped. Returns the
+// number of updated tickets.
+func syncAcceptanceCriteria(ctx context.Context, issues []models.GitHubIssue, seen map[int]bool, jiraClient *jira.Client) int {
+	updateCount := 0
+	for _, issue := range issues {
+		if stopStarting(ctx, jiraClient) {
+			break
+		}
+		jiraID := marker.GitHub.Key(issue.Title)
+		if jiraID == "" || seen[issue.Number] {
 			continue
 		}
+		seen[issue.Number] = true
 
-		err = jiraClient.CloseTicket(jiraID)
+		log := logging.FromContext(logging.WithTraceID(ctx, "issue_number", issue.Number, "jira_ticket", jiraID))
+
+		updated, err := jiraClient.WithLogger(log).SyncAcceptanceCriteria(jiraID, issue.Description)
 		if err != nil {
-			logging.Error("failed to close jira ticket",
-				"issue_number", issue.Number,
-				"jira_ticket", jiraID,
-				"error", err)
+			log.Error("failed to sync acceptance criteria", "error", err)
 			continue
 		}
+		if updated {
+			updateCount++
+		}
+	}
+	return updateCount
+}
+
+// syncClosedIssues handles synchronization of closed GitHub issues to JIRA.
+// It identifies GitHub issues that have been closed but their corresponding
+// JIRA tickets are still open, and closes those JIRA tickets with a comment
+// saying who closed the issue, why, and with which pull request or commit.
+// Issues closed less than gracePeriod ago are left for a later run, so an
+// issue closed and reopened in quick succession doesn't close its ticket.
+// Tickets recorded as done in cache aren't checked again; cache may be nil.
+// Ticket keys are looked up in syncState, which may be nil, before titles.
+// Returns the count of JIRA tickets that were closed and any error encountered.
+func syncClosedIssues(ctx context.Context, repository string, githubClient *github.Client, jiraClient *jira.Client, syncState *state.Store, gracePeriod time.Duration, cache *statuscache.Cache) (int, error) {
+	logging.Info("checking for closed github issues", "repository", repository)
+
+	closedIssues, err := githubClient.GetClosedIssues(ctx, repository)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch closed GitHub issues: %v", err)
+	}
+
+	if cache != nil {
+		// Reopened issues drop out, so their tickets are checked once closed again
+		var keys []string
+		for _, issue := range closedIssues {
+			if key := issueKey(syncState, issue); key != "" {
+				keys = append(keys, key)
+			}
+		}
+		cache.Retain(keys)
+		defer func() {
+			if err := cache.Save(); err != nil {
+				logging.Warn("failed to save status cache", "error", err)
+			}
+		}()
+	}
+
+	now := time.Now()
+	var pending []models.GitHubIssue
+	deferred, cached := 0, 0
+	for _, issue := range closedIssues {
+		jiraID := issueKey(syncState, issue)
+		switch {
+		case jiraID == "":
+		case inCloseGracePeriod(issue, gracePeriod, now):
+			deferred++
+		case cache != nil && cache.IsDone(jiraID):
+			cached++
+		default:
+			pending = append(pending, issue)
+		}
+	}
 
-		closeCount++
+	tracker := &jiraTracker{
+		repository:   repository,
+		githubClient: githubClient,
+		jiraClient:   jiraClient,
+		syncState:    syncState,
+		cache:        cache,
 	}
+	closeCount := syncTracker(ctx, tracker, nil, pending, trackerSync{}).Closed
 
+	if cached > 0 {
+		logging.Debug("skipped tickets recorded as done", "count", cached)
+	}
+	if deferred > 0 {
+		logging.Info("left recently closed issues for a later run",
+			"count", deferred,
+			"grace_period", gracePeriod)
+	}
 	return closeCount, nil
 }
+
+// closeIssueTicket closes the ticket key of a closed issue, with a comment
+// telling how the issue was closed. It returns false if the ticket is done
+// already.
+func closeIssueTicket(ctx context.Context, repository string, issue models.GitHubIssue, key string, githubClient *github.Client, jiraClient *jira.Client) (bool, error) {
+	log := logging.FromContext(ctx)
+	issueJira := jiraClient.WithLogger(log)
+
+	done, err := issueJira.IsTicketDone(key)
+	if err != nil {
+		return false, fmt.Errorf("failed to get jira ticket status: %v", err)
+	}
+	if done {
+		return false, nil
+	}
+
+	closure, err := githubClient.GetIssueClosure(ctx, repository, issue.Number)
+	if err != nil {
+		log.Warn("failed to find out how the github issue was closed", "error", err)
+	}
+	if closure.ClosedAt.IsZero() && issue.ClosedAt != nil {
+		closure.ClosedAt = *issue.ClosedAt
+	}
+
+	if err := issueJira.CloseTicketForIssue(key, issue.Number, closure); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// inCloseGracePeriod reports whether an issue was closed less than
+// gracePeriod before now. Issues with an unknown close time are not.
+func inCloseGracePeriod(issue models.GitHubIssue, gracePeriod time.Duration, now time.Time) bool {
+	return gracePeriod > 0 && issue.ClosedAt != nil && now.Sub(*issue.ClosedAt) < gracePeriod
+}
@@ -2,9 +2,13 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"testing"
+	"time"
 
+	"github.com/danielolaszy/glue/pkg/models"
 	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
 )
 
 // setupJiraCommandTest creates a command with output capture for testing
@@ -71,3 +75,86 @@ func TestParseChildIssuesAlt(t *testing.T) {
 		})
 	}
 }
+
+// TestProcessIssueGroupBudgetExhausted tests that no issue is started once the time budget is spent
+func TestProcessIssueGroupBudgetExhausted(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	issues := []models.GitHubIssue{{Number: 1, Title: "Story", Labels: []string{"story"}}}
+
+	// The clients are nil, so any attempt to process the issue would panic
+	updated, syncCount, err := processIssueGroup(ctx, issues, "10001", "PROJ", "owner/repo", nil, nil, nil, nil)
+	assert.NoError(t, err)
+	assert.Empty(t, updated)
+	assert.Equal(t, 0, syncCount)
+}
+
+func TestInCloseGracePeriod(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	closedAt := func(ago time.Duration) *time.Time {
+		t := now.Add(-ago)
+		return &t
+	}
+
+	tests := []struct {
+		name        string
+		closedAt    *time.Time
+		gracePeriod time.Duration
+		want        bool
+	}{
+		{"no grace period", closedAt(time.Minute), 0, false},
+		{"closed recently", closedAt(5 * time.Minute), 15 * time.Minute, true},
+		{"closed long enough ago", closedAt(15 * time.Minute), 15 * time.Minute, false},
+		{"unknown close time", nil, 15 * time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issue := models.GitHubIssue{Number: 1, State: "closed", ClosedAt: tt.closedAt}
+			assert.Equal(t, tt.want, inCloseGracePeriod(issue, tt.gracePeriod, now))
+		})
+	}
+}
+
+func TestSkipLockedIssues(t *testing.T) {
+	issuesByBoard := map[string][]models.GitHubIssue{
+		"PROJ": {
+			{Number: 1, State: "open"},
+			{Number: 2, State: "open", Locked: true},
+			{Number: 3, State: "closed", Locked: true},
+		},
+		"OTHER": {
+			{Number: 4, State: "open", Locked: true},
+		},
+	}
+
+	got := skipLockedIssues(issuesByBoard)
+
+	var numbers []int
+	for _, issue := range got["PROJ"] {
+		numbers = append(numbers, issue.Number)
+	}
+	assert.Equal(t, []int{1, 3}, numbers)
+	assert.Empty(t, got["OTHER"])
+}
+
+func TestIssueTypeOf(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue models.GitHubIssue
+		want  string
+	}{
+		{"feature label", models.GitHubIssue{Labels: []string{"feature"}}, "feature"},
+		{"no type", models.GitHubIssue{Labels: []string{"bug"}}, ""},
+		{"native feature", models.GitHubIssue{Labels: []string{"story"}, Type: "Feature"}, "feature"},
+		{"native bug", models.GitHubIssue{Labels: []string{"feature"}, Type: "Bug"}, "story"},
+		{"native task", models.GitHubIssue{Type: "Task"}, "story"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, issueTypeOf(tt.issue))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+// linkBudget paces and caps the parent-child link changes of a run, so that
+// features with hundreds of children don't overwhelm JIRA's indexing. One
+// budget is shared by all boards of a run.
+type linkBudget struct {
+	// batchSize is the number of changes between progress reports and pauses
+	batchSize int
+	// pause is waited between batches
+	pause time.Duration
+	// max caps the changes of the run; zero means no limit
+	max int
+	// changes counts the changes made so far
+	changes int
+	// created and removed count the links created and removed so far
+	created, removed int
+	// capped is set once a change was refused, so the cap is reported once
+	capped bool
+	// sleep waits between batches
+	sleep func(time.Duration)
+}
+
+// newLinkBudget returns the link budget for a run with the given JIRA config.
+func newLinkBudget(cfg config.JiraConfig) *linkBudget {
+	batchSize := cfg.LinkBatchSize
+	if batchSize <= 0 {
+		batchSize = config.DefaultLinkBatchSize
+	}
+	return &linkBudget{batchSize: batchSize, pause: cfg.LinkBatchPause, max: cfg.MaxLinkChanges, sleep: time.Sleep}
+}
+
+// allow reports whether another change may be made, logging a warning the
+// first time the cap is reached.
+func (b *linkBudget) allow() bool {
+	if b.max <= 0 || b.changes < b.max {
+		return true
+	}
+	if !b.capped {
+		b.capped = true
+		logging.Warn("reached the maximum number of link changes, remaining changes are left for the next run",
+			"max_link_changes", b.max)
+	}
+	return false
+}
+
+// linkChange is a parent-child link to create or remove.
+type linkChange struct {
+	// Child is the key of the child ticket
+	Child string
+	// Remove is set for links to remove
+	Remove bool
+}
+
+// applyLinkChanges makes the link changes of a feature's ticket in batches.
+// Progress is logged after each batch of a feature with more than one batch
+// of changes, and the budget's pause is waited between batches. Changes stop
+// when the budget is used up or stop reports true; the rest is left for the
+// next run. Failed changes are logged and don't count against the budget.
+// Returns the number of links created and removed.
+func applyLinkChanges(parent string, changes []linkChange, budget *linkBudget, stop func() bool, apply func(linkChange) error) (int, int) {
+	created, removed := 0, 0
+	for start := 0; start < len(changes); start += budget.batchSize {
+		if start > 0 {
+			if stop() {
+				break
+			}
+			if budget.pause > 0 {
+				budget.sleep(budget.pause)
+			}
+		}
+
+		end := start + budget.batchSize
+		if end > len(changes) {
+			end = len(changes)
+		}
+		for _, change := range changes[start:end] {
+			if !budget.allow() {
+				return created, removed
+			}
+			if err := apply(change); err != nil {
+				logging.Error("failed to change parent-child link",
+					"error", err,
+					"parent", parent,
+					"child", change.Child,
+					"remove", change.Remove)
+				continue
+			}
+			budget.changes++
+			if change.Remove {
+				removed++
+				budget.removed++
+			} else {
+				created++
+				budget.created++
+			}
+		}
+
+		if len(changes) > budget.batchSize {
+			logging.Info("feature link progress",
+				"parent", parent,
+				"done", end,
+				"total", len(changes))
+		}
+	}
+	return created, removed
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewLinkBudget(t *testing.T) {
+	budget := newLinkBudget(config.JiraConfig{})
+	assert.Equal(t, config.DefaultLinkBatchSize, budget.batchSize)
+	assert.Zero(t, budget.max)
+
+	budget = newLinkBudget(config.JiraConfig{LinkBatchSize: 10, LinkBatchPause: time.Second, MaxLinkChanges: 100})
+	assert.Equal(t, 10, budget.batchSize)
+	assert.Equal(t, time.Second, budget.pause)
+	assert.Equal(t, 100, budget.max)
+}
+
+func TestApplyLinkChanges(t *testing.T) {
+	changes := []linkChange{{Child: "P-1"}, {Child: "P-2"}, {Child: "P-3"}, {Child: "P-4"}, {Child: "P-5", Remove: true}}
+	never := func() bool { return false }
+
+	t.Run("batches with pauses", func(t *testing.T) {
+		var pauses []time.Duration
+		budget := &linkBudget{batchSize: 2, pause: time.Second, sleep: func(d time.Duration) { pauses = append(pauses, d) }}
+		var applied []string
+		created, removed := applyLinkChanges("P-0", changes, budget, never, func(c linkChange) error {
+			applied = append(applied, c.Child)
+			return nil
+		})
+		assert.Equal(t, 4, created)
+		assert.Equal(t, 1, removed)
+		assert.Equal(t, 4, budget.created)
+		assert.Equal(t, 1, budget.removed)
+		assert.Equal(t, []string{"P-1", "P-2", "P-3", "P-4", "P-5"}, applied)
+		assert.Equal(t, []time.Duration{time.Second, time.Second}, pauses)
+	})
+
+	t.Run("cap shared across features", func(t *testing.T) {
+		budget := &linkBudget{batchSize: 2, max: 3}
+		created, removed := applyLinkChanges("P-0", changes[:2], budget, never, func(linkChange) error { return nil })
+		assert.Equal(t, 2, created)
+		assert.Zero(t, removed)
+
+		created, _ = applyLinkChanges("P-9", changes, budget, never, func(linkChange) error { return nil })
+		assert.Equal(t, 1, created)
+		assert.True(t, budget.capped)
+		assert.False(t, budget.allow())
+	})
+
+	t.Run("failures don't count", func(t *testing.T) {
+		budget := &linkBudget{batchSize: 10, max: 2}
+		created, _ := applyLinkChanges("P-0", changes, budget, never, func(c linkChange) error {
+			if c.Child == "P-1" {
+				return errors.New("rejected")
+			}
+			return nil
+		})
+		assert.Equal(t, 2, created)
+	})
+
+	t.Run("stops between batches", func(t *testing.T) {
+		budget := &linkBudget{batchSize: 2}
+		created, _ := applyLinkChanges("P-0", changes, budget, func() bool { return true }, func(linkChange) error { return nil })
+		assert.Equal(t, 2, created)
+	})
+}
@@ -0,0 +1,47 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+// awaitMaintenance reports whether the run may go ahead. If JIRA is in a
+// maintenance window, the run is deferred to the next one unless wait is set,
+// in which case it waits for the window to end. It doesn't wait past the time
+// budget carried by ctx.
+func awaitMaintenance(ctx context.Context, jiraClient *jira.Client, wait bool) bool {
+	for {
+		window, until, ok := jiraClient.Maintenance(time.Now())
+		if !ok {
+			return true
+		}
+
+		if !wait {
+			logging.Warn("jira is in a maintenance window, leaving the synchronization to the next run",
+				"window", window.Name,
+				"until", until)
+			return false
+		}
+		if deadline, ok := ctx.Deadline(); ok && deadline.Before(until) {
+			logging.Warn("maintenance window ends after the time budget, leaving the synchronization to the next run",
+				"window", window.Name,
+				"until", until)
+			return false
+		}
+
+		logging.Info("waiting for maintenance window to end",
+			"window", window.Name,
+			"until", until)
+		timer := time.NewTimer(time.Until(until))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return false
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,326 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/mappings"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/internal/statuscache"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// keyMigration is the change of an issue's ticket key and board labels from
+// one JIRA project key to another.
+type keyMigration struct {
+	// Issue is the GitHub issue number
+	Issue int
+	// OldKey and NewKey are the ticket keys before and after; both are empty
+	// if only labels change
+	OldKey, NewKey string
+	// Title is the issue title with the new key, or "" if it stays
+	Title string
+	// RemoveLabels and AddLabels are the board labels to replace
+	RemoveLabels, AddLabels []string
+}
+
+// jiraMigrateCmd rewrites the ticket keys and board labels of issues after a
+// JIRA project was re-keyed.
+var jiraMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move issues to a re-keyed JIRA project",
+	Long: `Rewrite the ticket key prefixes and board labels of a repository's issues
+after a JIRA project was given a new key, so they stay synced to their tickets.
+
+Title prefixes are changed from [OLD-x] to [NEW-x], keeping the ticket number
+as JIRA does when a project is re-keyed. If tickets were renumbered, e.g.
+because they were moved to another project, pass --keys with a CSV file
+of old and new keys, one ticket per line: OLD-12,NEW-40. Issues whose key is
+missing from the file keep their title. Labels equal to OLD and
+'jira-project: OLD' labels are replaced by their NEW equivalents.
+
+The ticket status cache, broken mapping records and sync state are moved to
+the new keys.
+Use --dry-run to print the changes without making them.
+
+Example:
+  glue jira migrate -r owner/repo --from OLD --to NEW
+  glue jira migrate -r owner/repo --from OLD --to NEW --keys keys.csv`,
+	PreRunE: validateFlags(flagRules{Repository: true, Required: []string{"from", "to"}}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		from, err := migrationProjectFlag(cmd, "from")
+		if err != nil {
+			return err
+		}
+		to, err := migrationProjectFlag(cmd, "to")
+		if err != nil {
+			return err
+		}
+		if from == to {
+			return fmt.Errorf("--from and --to are both %s", from)
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		var keys map[string]string
+		if keysFile, _ := cmd.Flags().GetString("keys"); keysFile != "" {
+			f, err := os.Open(keysFile)
+			if err != nil {
+				return fmt.Errorf("failed to open key mapping: %v", err)
+			}
+			keys, err = parseKeyMapping(f, from)
+			f.Close()
+			if err != nil {
+				return err
+			}
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		if err := checkSafety(cmd, cfg.Safety, repository, []string{from, to}); err != nil {
+			return err
+		}
+
+		openIssues, err := githubClient.GetAllIssues(ctx, repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch github issues: %v", err)
+		}
+		closedIssues, err := githubClient.GetClosedIssues(ctx, repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch closed github issues: %v", err)
+		}
+
+		statuses, err := statuscache.Load(statuscache.DefaultDir(), repository)
+		if err != nil {
+			return err
+		}
+		broken, err := mappings.Load(mappings.DefaultDir(), repository)
+		if err != nil {
+			return err
+		}
+		syncState, err := state.Load(state.DefaultDir(), repository)
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		migrated, failed := 0, 0
+		for _, issue := range append(openIssues, closedIssues...) {
+			migration, ok := planMigration(issue, from, to, keys)
+			if !ok {
+				continue
+			}
+
+			if dryRun {
+				fmt.Fprintf(out, "#%d: %s (dry run)\n", issue.Number, describeMigration(migration))
+				migrated++
+				continue
+			}
+			if err := applyMigration(ctx, githubClient, repository, migration); err != nil {
+				logging.Error("failed to migrate issue",
+					"issue_number", issue.Number,
+					"error", err)
+				fmt.Fprintf(out, "#%d: %v\n", issue.Number, err)
+				failed++
+				continue
+			}
+
+			if migration.OldKey != "" {
+				statuses.Rename(migration.OldKey, migration.NewKey)
+				broken.Forget(migration.OldKey)
+				syncState.Rename(migration.OldKey, migration.NewKey)
+			}
+			fmt.Fprintf(out, "#%d: %s\n", issue.Number, describeMigration(migration))
+			migrated++
+		}
+
+		if dryRun {
+			fmt.Fprintf(out, "\nwould migrate %d issues from %s to %s\n", migrated, from, to)
+			return nil
+		}
+
+		if err := statuses.Save(); err != nil {
+			logging.Warn("failed to save status cache", "error", err)
+		}
+		if err := broken.Save(); err != nil {
+			logging.Warn("failed to save mapping store", "error", err)
+		}
+		saveSyncState(syncState)
+
+		fmt.Fprintf(out, "\nmigrated %d issues from %s to %s\n", migrated, from, to)
+		if failed > 0 {
+			return fmt.Errorf("failed to migrate %d issues", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraMigrateCmd)
+	jiraMigrateCmd.Flags().String("from", "", "JIRA project key the issues are synced to")
+	jiraMigrateCmd.Flags().String("to", "", "New key of the JIRA project")
+	jiraMigrateCmd.Flags().String("keys", "", "CSV file mapping old ticket keys to new ones, for renumbered tickets")
+	jiraMigrateCmd.Flags().Bool("dry-run", false, "Print the changes without making them")
+}
+
+// migrationProjectFlag returns the upper-cased project key given with a flag.
+func migrationProjectFlag(cmd *cobra.Command, name string) (string, error) {
+	key, err := cmd.Flags().GetString(name)
+	if err != nil {
+		return "", err
+	}
+	if !boardPattern.MatchString(key) {
+		return "", fmt.Errorf("--%s %q is not a JIRA project key", name, key)
+	}
+	return strings.ToUpper(key), nil
+}
+
+// parseKeyMapping reads the old and new ticket keys from the first two columns
+// of a CSV file, keeping those of tickets in project from. Rows whose first
+// column isn't a ticket key, like a header, are skipped.
+func parseKeyMapping(r io.Reader, from string) (map[string]string, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	keys := make(map[string]string)
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read key mapping: %v", err)
+		}
+		if len(record) < 2 || !ticketKeyArgPattern.MatchString(strings.TrimSpace(record[0])) {
+			continue
+		}
+
+		oldKey := strings.ToUpper(strings.TrimSpace(record[0]))
+		newKey := strings.ToUpper(strings.TrimSpace(record[1]))
+		if project, _, _ := strings.Cut(oldKey, "-"); project != from {
+			continue
+		}
+		if !ticketKeyArgPattern.MatchString(newKey) {
+			return nil, fmt.Errorf("invalid new key %q for %s in key mapping", record[1], oldKey)
+		}
+		keys[oldKey] = newKey
+	}
+
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("key mapping has no keys of project %s", from)
+	}
+	return keys, nil
+}
+
+// migrateKey returns the new key of a ticket in project from: the one in keys
+// if given, or the key in project to with the same number otherwise. It
+// returns false if the ticket isn't in project from, or is missing from keys.
+func migrateKey(key, from, to string, keys map[string]string) (string, bool) {
+	project, number, ok := strings.Cut(key, "-")
+	if !ok || !strings.EqualFold(project, from) {
+		return "", false
+	}
+	if keys != nil {
+		newKey, ok := keys[strings.ToUpper(key)]
+		return newKey, ok
+	}
+	return to + "-" + number, true
+}
+
+// planMigration returns the changes moving an issue from project from to
+// project to, and false if there are none.
+func planMigration(issue models.GitHubIssue, from, to string, keys map[string]string) (keyMigration, bool) {
+	migration := keyMigration{Issue: issue.Number}
+
+	if key := marker.GitHub.Key(issue.Title); key != "" {
+		if newKey, ok := migrateKey(key, from, to, keys); ok {
+			migration.OldKey, migration.NewKey = key, newKey
+			migration.Title = "[" + newKey + "]" + strings.TrimPrefix(issue.Title, "["+key+"]")
+		} else if keys != nil && strings.HasPrefix(key, from+"-") {
+			logging.Warn("ticket missing from key mapping, keeping title",
+				"issue_number", issue.Number,
+				"jira_ticket", key)
+		}
+	}
+
+	for _, label := range issue.Labels {
+		matches := boardLabelPattern.FindStringSubmatch(label)
+		switch {
+		case strings.EqualFold(label, from):
+			migration.RemoveLabels = append(migration.RemoveLabels, label)
+			migration.AddLabels = append(migration.AddLabels, to)
+		case len(matches) == 2 && strings.EqualFold(matches[1], from):
+			migration.RemoveLabels = append(migration.RemoveLabels, label)
+			migration.AddLabels = append(migration.AddLabels, fmt.Sprintf("%s %s", boardLabelPrefix, to))
+		}
+	}
+
+	return migration, migration.Title != "" || len(migration.RemoveLabels) > 0
+}
+
+// applyMigration changes an issue's title and labels. Labels are added before
+// the old ones are removed, so an interrupted migration leaves the issue
+// routed to a board.
+func applyMigration(ctx context.Context, githubClient *github.Client, repository string, migration keyMigration) error {
+	if migration.Title != "" {
+		if err := githubClient.UpdateIssueTitle(ctx, repository, migration.Issue, migration.Title); err != nil {
+			return err
+		}
+	}
+	if len(migration.AddLabels) > 0 {
+		if err := githubClient.AddLabels(ctx, repository, migration.Issue, migration.AddLabels...); err != nil {
+			return err
+		}
+	}
+	for _, label := range migration.RemoveLabels {
+		if err := githubClient.RemoveLabel(ctx, repository, migration.Issue, label); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// describeMigration summarizes the changes of a migration.
+func describeMigration(migration keyMigration) string {
+	var changes []string
+	if migration.Title != "" {
+		changes = append(changes, fmt.Sprintf("%s -> %s", migration.OldKey, migration.NewKey))
+	}
+	for i, label := range migration.RemoveLabels {
+		changes = append(changes, fmt.Sprintf("label %q -> %q", label, migration.AddLabels[i]))
+	}
+	return strings.Join(changes, ", ")
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyMapping(t *testing.T) {
+	input := `Old key,New key
+OLD-1, NEW-40
+old-2,new-41
+OTHER-3,NEW-42
+`
+	keys, err := parseKeyMapping(strings.NewReader(input), "OLD")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"OLD-1": "NEW-40", "OLD-2": "NEW-41"}, keys)
+
+	_, err = parseKeyMapping(strings.NewReader("OLD-1,garbage\n"), "OLD")
+	assert.Error(t, err)
+
+	_, err = parseKeyMapping(strings.NewReader("OTHER-1,NEW-1\n"), "OLD")
+	assert.Error(t, err)
+}
+
+func TestPlanMigration(t *testing.T) {
+	tests := []struct {
+		name   string
+		issue  models.GitHubIssue
+		keys   map[string]string
+		wantOK bool
+		want   keyMigration
+	}{
+		{
+			name:   "re-keyed project keeps numbers",
+			issue:  models.GitHubIssue{Number: 1, Title: "[OLD-12] Login", Labels: []string{"OLD", "story"}},
+			wantOK: true,
+			want: keyMigration{Issue: 1, OldKey: "OLD-12", NewKey: "NEW-12", Title: "[NEW-12] Login",
+				RemoveLabels: []string{"OLD"}, AddLabels: []string{"NEW"}},
+		},
+		{
+			name:   "key mapping",
+			issue:  models.GitHubIssue{Number: 2, Title: "[OLD-12] Login", Labels: []string{"jira-project: old"}},
+			keys:   map[string]string{"OLD-12": "NEW-40"},
+			wantOK: true,
+			want: keyMigration{Issue: 2, OldKey: "OLD-12", NewKey: "NEW-40", Title: "[NEW-40] Login",
+				RemoveLabels: []string{"jira-project: old"}, AddLabels: []string{"jira-project: NEW"}},
+		},
+		{
+			name:   "missing from key mapping relabels only",
+			issue:  models.GitHubIssue{Number: 3, Title: "[OLD-13] Logout", Labels: []string{"OLD"}},
+			keys:   map[string]string{"OLD-12": "NEW-40"},
+			wantOK: true,
+			want:   keyMigration{Issue: 3, RemoveLabels: []string{"OLD"}, AddLabels: []string{"NEW"}},
+		},
+		{
+			name:  "other project",
+			issue: models.GitHubIssue{Number: 4, Title: "[OLDER-1] Other", Labels: []string{"OLDER"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			migration, ok := planMigration(tt.issue, "OLD", "NEW", tt.keys)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Equal(t, tt.want, migration)
+			}
+		})
+	}
+}
+
+func TestDescribeMigration(t *testing.T) {
+	migration := keyMigration{OldKey: "OLD-1", NewKey: "NEW-1", Title: "[NEW-1] Login",
+		RemoveLabels: []string{"OLD"}, AddLabels: []string{"NEW"}}
+	assert.Equal(t, `OLD-1 -> NEW-1, label "OLD" -> "NEW"`, describeMigration(migration))
+}
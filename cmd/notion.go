@@ -0,0 +1,261 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/notion"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// notionRow is the row of a GitHub issue in the Notion database.
+type notionRow struct {
+	Number int
+	Title  string
+	Status string
+	URL    string
+	Key    string
+}
+
+// notionCmd mirrors the GitHub issues of boards to a Notion database.
+var notionCmd = &cobra.Command{
+	Use:   "notion",
+	Short: "Mirror GitHub issues and their JIRA tickets to a Notion database",
+	Long: `Write one row per GitHub issue on the given boards to a Notion database, so
+dashboards built in Notion stay current without copying.
+
+Each row holds the issue title without its ticket key, the JIRA status (or
+Open or Closed for issues without a ticket), the address of the issue and the
+JIRA key. Rows are matched to issues by the address, so existing rows are
+updated in place and rows added by hand are left alone. Rows whose values
+haven't changed aren't written.
+
+The database must be shared with the Notion integration whose secret is in
+NOTION_TOKEN, and have these properties, which notion.properties in the config
+file can rename:
+- its title property, for the title
+- Status, a select, status or text property
+- GitHub, a URL or text property
+- JIRA Key, a text property
+
+Example:
+  glue notion -r owner/repo -b PROJ --database 8a3c9e6b1f2d4c5e9a7b0c1d2e3f4a5b
+  glue notion -r owner/repo -b PROJ -b OPS --dry-run`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		database, err := cmd.Flags().GetString("database")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+		if database == "" {
+			database = cfg.Notion.Database
+		}
+		if database == "" {
+			return fmt.Errorf("no notion database, use --database or set notion.database in the config file")
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		notionClient, err := notion.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize notion client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		types, err := notionClient.PropertyTypes(ctx, database)
+		if err != nil {
+			return err
+		}
+		columns := notionColumns(cfg.Notion.Properties, types)
+
+		issuesByBoard, err := fetchIssuesByBoard(ctx, githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+		var issues []models.GitHubIssue
+		for _, board := range boards {
+			issues = append(issues, issuesByBoard[board]...)
+		}
+		rows := buildNotionRows(issues, ticketsOf(jiraClient, issues), cfg.GitHub.Domain, repository)
+
+		pages, err := notionClient.Query(ctx, database)
+		if err != nil {
+			return err
+		}
+		byURL := make(map[string]notion.Page, len(pages))
+		for _, page := range pages {
+			if url := page.Value(columns.URL); url != "" {
+				byURL[strings.ToLower(url)] = page
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		created, updated, unchanged, failed := 0, 0, 0, 0
+		for _, row := range rows {
+			values := row.values(columns)
+			properties, err := notion.Properties(types, values)
+			if err != nil {
+				return err
+			}
+
+			page, exists := byURL[strings.ToLower(row.URL)]
+			switch {
+			case exists && pageHasValues(page, values):
+				unchanged++
+			case dryRun && exists:
+				fmt.Fprintf(out, "would update row of #%d %s\n", row.Number, row.Title)
+			case dryRun:
+				fmt.Fprintf(out, "would create row for #%d %s\n", row.Number, row.Title)
+			case exists:
+				if err := notionClient.UpdatePage(ctx, page.ID, properties); err != nil {
+					logging.Error("failed to update notion row",
+						"issue_number", row.Number,
+						"error", err)
+					failed++
+					continue
+				}
+				fmt.Fprintf(out, "updated row of #%d %s\n", row.Number, row.Title)
+				updated++
+			default:
+				if _, err := notionClient.CreatePage(ctx, database, properties); err != nil {
+					logging.Error("failed to create notion row",
+						"issue_number", row.Number,
+						"error", err)
+					failed++
+					continue
+				}
+				fmt.Fprintf(out, "created row for #%d %s\n", row.Number, row.Title)
+				created++
+			}
+		}
+
+		fmt.Fprintf(out, "\n%d issues, %d rows created, %d updated, %d unchanged\n",
+			len(rows), created, updated, unchanged)
+		if failed > 0 {
+			return fmt.Errorf("failed to write rows of %d issues", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(notionCmd)
+	notionCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) whose issues are written (can be specified multiple times)")
+	notionCmd.Flags().String("database", "", "ID of the Notion database to write to (overrides notion.database)")
+	notionCmd.Flags().Bool("dry-run", false, "Print the rows that would be created or updated without writing them")
+}
+
+// notionColumns returns the database properties the values are written to,
+// with the defaults for those the config doesn't name.
+func notionColumns(properties config.NotionProperties, types map[string]string) config.NotionProperties {
+	if properties.Title == "" {
+		properties.Title = notion.TitleProperty(types)
+	}
+	if properties.Status == "" {
+		properties.Status = "Status"
+	}
+	if properties.URL == "" {
+		properties.URL = "GitHub"
+	}
+	if properties.Key == "" {
+		properties.Key = "JIRA Key"
+	}
+	return properties
+}
+
+// buildNotionRows returns one row per GitHub issue, ordered by issue number.
+// Tickets are keyed by JIRA key; issues without a ticket get their GitHub
+// state as status.
+func buildNotionRows(issues []models.GitHubIssue, tickets map[string]models.JiraTicket, gitHubDomain, repository string) []notionRow {
+	byNumber := make(map[int]models.GitHubIssue, len(issues))
+	for _, issue := range issues {
+		byNumber[issue.Number] = issue
+	}
+
+	rows := make([]notionRow, 0, len(byNumber))
+	for _, issue := range byNumber {
+		key := marker.GitHub.Key(issue.Title)
+		status := "Open"
+		if strings.EqualFold(issue.State, "closed") {
+			status = "Closed"
+		}
+		if ticket, ok := tickets[key]; ok && ticket.Status != "" {
+			status = ticket.Status
+		}
+		rows = append(rows, notionRow{
+			Number: issue.Number,
+			Title:  marker.GitHub.Strip(issue.Title),
+			Status: status,
+			URL:    fmt.Sprintf("https://%s/%s/issues/%d", gitHubDomain, repository, issue.Number),
+			Key:    key,
+		})
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Number < rows[j].Number })
+	return rows
+}
+
+// values returns the values of the row by property name.
+func (r notionRow) values(columns config.NotionProperties) map[string]string {
+	return map[string]string{
+		columns.Title:  r.Title,
+		columns.Status: r.Status,
+		columns.URL:    r.URL,
+		columns.Key:    r.Key,
+	}
+}
+
+// pageHasValues reports whether a row already holds the values.
+func pageHasValues(page notion.Page, values map[string]string) bool {
+	for name, value := range values {
+		if page.Value(name) != value {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/notion"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotionColumns(t *testing.T) {
+	types := map[string]string{"Issue": "title"}
+
+	assert.Equal(t, config.NotionProperties{Title: "Issue", Status: "Status", URL: "GitHub", Key: "JIRA Key"},
+		notionColumns(config.NotionProperties{}, types))
+	assert.Equal(t, config.NotionProperties{Title: "Summary", Status: "Stage", URL: "GitHub", Key: "Ticket"},
+		notionColumns(config.NotionProperties{Title: "Summary", Status: "Stage", Key: "Ticket"}, types))
+}
+
+func TestBuildNotionRows(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{Number: 3, Title: "Unsynced idea", State: "closed"},
+		{Number: 1, Title: "[PROJ-1] Add login", State: "open"},
+		{Number: 2, Title: "[PROJ-2] Missing ticket", State: "open"},
+		{Number: 1, Title: "[PROJ-1] Add login", State: "open"},
+	}
+	tickets := map[string]models.JiraTicket{"PROJ-1": {Key: "PROJ-1", Status: "In Progress"}}
+
+	assert.Equal(t, []notionRow{
+		{Number: 1, Title: "Add login", Status: "In Progress", URL: "https://github.com/owner/repo/issues/1", Key: "PROJ-1"},
+		{Number: 2, Title: "Missing ticket", Status: "Open", URL: "https://github.com/owner/repo/issues/2", Key: "PROJ-2"},
+		{Number: 3, Title: "Unsynced idea", Status: "Closed", URL: "https://github.com/owner/repo/issues/3"},
+	}, buildNotionRows(issues, tickets, "github.com", "owner/repo"))
+}
+
+func TestPageHasValues(t *testing.T) {
+	var page notion.Page
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"p1","properties":{
+		"Name":{"type":"title","title":[{"plain_text":"Add login"}]},
+		"Status":{"type":"select","select":{"name":"To Do"}}
+	}}`), &page))
+
+	assert.True(t, pageHasValues(page, map[string]string{"Name": "Add login", "Status": "To Do"}))
+	assert.False(t, pageHasValues(page, map[string]string{"Name": "Add login", "Status": "Done"}))
+}
@@ -0,0 +1,156 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os/exec"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/spf13/cobra"
+)
+
+// ticketKeyArgPattern matches a JIRA key given on the command line.
+var ticketKeyArgPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*-\d+$`)
+
+// openBrowser opens a URL in the default browser without waiting for it.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
+
+// openCmd opens a GitHub issue and its JIRA ticket in the browser.
+var openCmd = &cobra.Command{
+	Use:   "open <issue-number|ticket-key>",
+	Short: "Open a GitHub issue and its JIRA ticket in the browser",
+	Long: `Open a GitHub issue and the JIRA ticket it is synced to in the browser.
+
+The argument is either a GitHub issue number or a JIRA key. The counterpart is
+found through the key prefix in the GitHub issue title; if there is none, only
+the given side is opened.
+
+Example:
+  glue open -r owner/repo 123
+  glue open -r owner/repo PROJ-456 --print`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateFlags(flagRules{Repository: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, key, err := parseOpenTarget(args[0])
+		if err != nil {
+			return err
+		}
+
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		printOnly, err := cmd.Flags().GetBool("print")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		if number != 0 {
+			issue, err := githubClient.GetIssue(cmd.Context(), repository, number)
+			if err != nil {
+				return fmt.Errorf("failed to get github issue #%d: %v", number, err)
+			}
+			key = marker.GitHub.Key(issue.Title)
+		} else {
+			number, err = findIssueForKey(cmd.Context(), githubClient, repository, key)
+			if err != nil {
+				return err
+			}
+		}
+
+		urls := mappingURLs(cfg, repository, number, key)
+		if len(urls) < 2 {
+			fmt.Fprintf(cmd.ErrOrStderr(), "no mapping found for %s\n", args[0])
+		}
+		for _, url := range urls {
+			fmt.Fprintln(cmd.OutOrStdout(), url)
+			if printOnly {
+				continue
+			}
+			if err := openBrowser(url); err != nil {
+				return fmt.Errorf("failed to open %s: %v", url, err)
+			}
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(openCmd)
+	openCmd.Flags().Bool("print", false, "Print the URLs instead of opening them")
+}
+
+// parseOpenTarget parses the argument of glue open into a GitHub issue number
+// or an upper-cased JIRA key.
+func parseOpenTarget(arg string) (int, string, error) {
+	arg = strings.TrimPrefix(strings.TrimSpace(arg), "#")
+	if number, err := strconv.Atoi(arg); err == nil && number > 0 {
+		return number, "", nil
+	}
+	if ticketKeyArgPattern.MatchString(arg) {
+		return 0, strings.ToUpper(arg), nil
+	}
+	return 0, "", fmt.Errorf("invalid argument %q: expected a GitHub issue number or a JIRA key like PROJ-123", arg)
+}
+
+// findIssueForKey returns the number of the GitHub issue, open or closed,
+// synced to a JIRA key, or 0 if there is none.
+func findIssueForKey(ctx context.Context, githubClient *github.Client, repository, key string) (int, error) {
+	openIssues, err := githubClient.GetAllIssues(ctx, repository)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch github issues: %v", err)
+	}
+	closedIssues, err := githubClient.GetClosedIssues(ctx, repository)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch closed github issues: %v", err)
+	}
+
+	for _, issue := range append(openIssues, closedIssues...) {
+		if marker.GitHub.Key(issue.Title) == key {
+			return issue.Number, nil
+		}
+	}
+	return 0, nil
+}
+
+// mappingURLs returns the web URLs of a GitHub issue and a JIRA ticket,
+// leaving out a side that is unknown (number 0 or empty key).
+func mappingURLs(cfg *config.Config, repository string, number int, key string) []string {
+	var urls []string
+	if number != 0 {
+		urls = append(urls, fmt.Sprintf("https://%s/%s/issues/%d", cfg.GitHub.Domain, repository, number))
+	}
+	if key != "" && cfg.Jira.BaseURL != "" {
+		urls = append(urls, strings.TrimRight(cfg.Jira.BaseURL, "/")+"/browse/"+key)
+	}
+	return urls
+}
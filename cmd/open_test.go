@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseOpenTarget(t *testing.T) {
+	tests := []struct {
+		arg     string
+		number  int
+		key     string
+		wantErr bool
+	}{
+		{arg: "123", number: 123},
+		{arg: "#42", number: 42},
+		{arg: "PROJ-456", key: "PROJ-456"},
+		{arg: "proj-7", key: "PROJ-7"},
+		{arg: "0", wantErr: true},
+		{arg: "PROJ", wantErr: true},
+		{arg: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.arg, func(t *testing.T) {
+			number, key, err := parseOpenTarget(tt.arg)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.number, number)
+			assert.Equal(t, tt.key, key)
+		})
+	}
+}
+
+func TestMappingURLs(t *testing.T) {
+	cfg := &config.Config{
+		GitHub: config.GitHubConfig{Domain: "github.com"},
+		Jira:   config.JiraConfig{BaseURL: "https://jira.example.com/"},
+	}
+
+	assert.Equal(t, []string{
+		"https://github.com/owner/repo/issues/12",
+		"https://jira.example.com/browse/PROJ-1",
+	}, mappingURLs(cfg, "owner/repo", 12, "PROJ-1"))
+	assert.Equal(t, []string{"https://github.com/owner/repo/issues/12"}, mappingURLs(cfg, "owner/repo", 12, ""))
+	assert.Equal(t, []string{"https://jira.example.com/browse/PROJ-1"}, mappingURLs(cfg, "owner/repo", 0, "PROJ-1"))
+}
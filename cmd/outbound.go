@@ -0,0 +1,177 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/outbound"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// outboundCmd posts events about labeled GitHub issues to an endpoint.
+var outboundCmd = &cobra.Command{
+	Use:   "outbound",
+	Short: "Post events about labeled GitHub issues to an endpoint of your own",
+	Long: `Post a create, update or close event to outbound.url in the config file for
+every GitHub issue with the --label label that changed since the last run, so
+tools glue has no client for can follow the issues.
+
+An open issue is sent as "create" the first time, and as "update" when its
+title, body, labels, assignees or type changed or it was reopened. Issues sent
+before are sent as "close" once closed. The issues sent are remembered in
+glue's cache directory, one file per repository; delete it to send every
+open issue as created again.
+
+The request body is the event as JSON, or the output of the Go template in
+outbound.template, which gets the event as its data, e.g. {{.Event}} and
+{{json .Issue.Title}}. Requests carry the event name in X-Glue-Event, the
+headers in outbound.headers and, with outbound.secret, the same signature as
+hook requests.
+
+Example:
+  glue outbound -r owner/repo
+  glue outbound -r owner/repo --label roadmap --dry-run`,
+	PreRunE: validateFlags(flagRules{Repository: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		label, err := cmd.Flags().GetString("label")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		outboundClient, err := outbound.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize outbound client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		if err := checkSafety(cmd, cfg.Safety, repository, nil); err != nil {
+			return err
+		}
+
+		state, err := outbound.Load(outbound.DefaultDir(), repository)
+		if err != nil {
+			return err
+		}
+
+		open, err := githubClient.GetIssuesWithLabel(ctx, repository, label)
+		if err != nil {
+			return fmt.Errorf("failed to get issues with label %s: %v", label, err)
+		}
+		closed, err := githubClient.GetClosedIssuesWithLabels(ctx, repository, []string{label})
+		if err != nil {
+			return fmt.Errorf("failed to get closed issues with label %s: %v", label, err)
+		}
+		applyIssueTypes(ctx, githubClient, repository, append(append([]models.GitHubIssue{}, open...), closed...))
+
+		now := time.Now().UTC()
+		events := planOutboundEvents(cfg.GitHub.Domain, repository, open, closed, state, now)
+
+		out := cmd.OutOrStdout()
+		sent, failed := 0, 0
+		for _, event := range events {
+			if dryRun {
+				if _, err := outboundClient.Render(event); err != nil {
+					return err
+				}
+				fmt.Fprintf(out, "would send %s for #%d %s\n", event.Event, event.Issue.Number, event.Issue.Title)
+				continue
+			}
+
+			if err := outboundClient.Send(ctx, event); err != nil {
+				logging.Error("failed to send outbound event",
+					"issue_number", event.Issue.Number,
+					"event", event.Event,
+					"error", err)
+				failed++
+				continue
+			}
+			state.Record(event, now)
+			fmt.Fprintf(out, "sent %s for #%d %s\n", event.Event, event.Issue.Number, event.Issue.Title)
+			sent++
+		}
+
+		if !dryRun && sent > 0 {
+			if err := state.Save(); err != nil {
+				return err
+			}
+		}
+
+		fmt.Fprintf(out, "\n%d open and %d closed issues labeled %s, %d events sent\n",
+			len(open), len(closed), label, sent)
+		if failed > 0 {
+			return fmt.Errorf("failed to send events of %d issues", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(outboundCmd)
+	outboundCmd.Flags().StringP("label", "l", "outbound", "Label of the GitHub issues events are sent for")
+	outboundCmd.Flags().Bool("dry-run", false, "Print the events that would be sent without sending them")
+	outboundCmd.Flags().Bool("force", false, "Run even if the safety config doesn't permit changing the repository")
+}
+
+// planOutboundEvents returns the events of the issues that changed since the
+// events recorded in state: "create" for open issues never sent, "update"
+// for open issues that changed or were reopened and "close" for closed
+// issues whose last event wasn't.
+func planOutboundEvents(gitHubDomain, repository string, open, closed []models.GitHubIssue, state *outbound.State, now time.Time) []outbound.Event {
+	var events []outbound.Event
+	newEvent := func(name string, issue models.GitHubIssue) outbound.Event {
+		url := fmt.Sprintf("https://%s/%s/issues/%d", gitHubDomain, repository, issue.Number)
+		return outbound.Event{Event: name, Repository: repository, Issue: outbound.NewIssue(issue, url), Time: now}
+	}
+
+	for _, issue := range open {
+		event := newEvent(outbound.EventCreate, issue)
+		last, ok := state.Sent[issue.Number]
+		switch {
+		case !ok:
+		case last.Closed || last.Digest != event.Issue.Digest():
+			event.Event = outbound.EventUpdate
+		default:
+			continue
+		}
+		events = append(events, event)
+	}
+
+	for _, issue := range closed {
+		if last, ok := state.Sent[issue.Number]; ok && !last.Closed {
+			event := newEvent(outbound.EventClose, issue)
+			event.Issue.State = "closed"
+			events = append(events, event)
+		}
+	}
+	return events
+}
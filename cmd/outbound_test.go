@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/outbound"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlanOutboundEvents(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	state, err := outbound.Load(t.TempDir(), "owner/repo")
+	require.NoError(t, err)
+
+	unchanged := models.GitHubIssue{Number: 2, Title: "Unchanged", State: "open"}
+	edited := models.GitHubIssue{Number: 3, Title: "Edited", State: "open"}
+	reopened := models.GitHubIssue{Number: 4, Title: "Reopened", State: "open"}
+	closing := models.GitHubIssue{Number: 5, Title: "Closing", State: "closed"}
+	for _, issue := range []models.GitHubIssue{unchanged, edited, reopened, closing} {
+		state.Record(outbound.Event{Event: outbound.EventCreate, Issue: outbound.NewIssue(issue, fmt.Sprintf("https://github.com/owner/repo/issues/%d", issue.Number))}, now)
+	}
+	state.Record(outbound.Event{Event: outbound.EventClose, Issue: outbound.NewIssue(reopened, "https://github.com/owner/repo/issues/4")}, now)
+	edited.Title = "Edited again"
+
+	open := []models.GitHubIssue{
+		{Number: 1, Title: "New", State: "open"},
+		unchanged, edited, reopened,
+	}
+	closed := []models.GitHubIssue{
+		closing,
+		{Number: 6, Title: "Never sent", State: "closed"},
+	}
+	events := planOutboundEvents("github.com", "owner/repo", open, closed, state, now)
+
+	var got []string
+	for _, event := range events {
+		got = append(got, event.Event+" "+event.Issue.Title)
+	}
+	assert.Equal(t, []string{"create New", "update Edited again", "update Reopened", "close Closing"}, got)
+	assert.Equal(t, "https://github.com/owner/repo/issues/1", events[0].Issue.URL)
+	assert.Equal(t, "owner/repo", events[0].Repository)
+	assert.Equal(t, now, events[0].Time)
+	assert.Equal(t, "closed", events[3].Issue.State)
+}
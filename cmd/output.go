@@ -0,0 +1,25 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// outputWriter returns where a command writes its output: the file at path,
+// created or truncated, or the command's stdout if path is empty. The
+// returned close must be called once the output is written; it reports a
+// failure to write the file.
+func outputWriter(cmd *cobra.Command, path string) (io.Writer, func() error, error) {
+	if path == "" {
+		return cmd.OutOrStdout(), func() error { return nil }, nil
+	}
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create output file: %v", err)
+	}
+	return f, f.Close, nil
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutputWriter(t *testing.T) {
+	cmd := &cobra.Command{}
+	var stdout bytes.Buffer
+	cmd.SetOut(&stdout)
+
+	w, closeOutput, err := outputWriter(cmd, "")
+	require.NoError(t, err)
+	fmt.Fprint(w, "to stdout")
+	require.NoError(t, closeOutput())
+	assert.Equal(t, "to stdout", stdout.String())
+
+	path := filepath.Join(t.TempDir(), "graph.dot")
+	w, closeOutput, err = outputWriter(cmd, path)
+	require.NoError(t, err)
+	fmt.Fprint(w, "to file")
+	require.NoError(t, closeOutput())
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "to file", string(data))
+	assert.Equal(t, "to stdout", stdout.String())
+
+	_, _, err = outputWriter(cmd, filepath.Join(t.TempDir(), "missing", "graph.dot"))
+	assert.ErrorContains(t, err, "failed to create output file")
+}
@@ -0,0 +1,115 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// featureChildren returns the stories each feature is linked to under the
+// multiple-parents policy, by feature number, for the features whose
+// '## Issues' section lists any; a feature whose stories all belong
+// elsewhere maps to an empty list, so its links to them are removed. It also
+// returns the stories listed by several of the features, mapped to the
+// sorted numbers of those features.
+func featureChildren(features []models.GitHubIssue, gitHubDomain, policy string) (map[int][]int, map[int][]int) {
+	listed := make(map[int][]int)
+	parents := make(map[int][]int)
+	for _, feature := range features {
+		if _, seen := listed[feature.Number]; seen {
+			continue
+		}
+		children := parseChildIssues(feature.Description, gitHubDomain)
+		if len(children) == 0 {
+			continue
+		}
+		listed[feature.Number] = children
+		for _, child := range children {
+			if !containsInt(parents[child], feature.Number) {
+				parents[child] = append(parents[child], feature.Number)
+			}
+		}
+	}
+
+	multiple := make(map[int][]int)
+	for child, numbers := range parents {
+		if len(numbers) > 1 {
+			sort.Ints(numbers)
+			multiple[child] = numbers
+		}
+	}
+
+	linked := make(map[int][]int, len(listed))
+	for feature, children := range listed {
+		kept := []int{}
+		for _, child := range children {
+			if numbers, ok := multiple[child]; ok {
+				switch policy {
+				case config.MultipleParentsFirstWins:
+					if numbers[0] != feature {
+						continue
+					}
+				case config.MultipleParentsWarnAndSkip:
+					continue
+				}
+			}
+			kept = append(kept, child)
+		}
+		linked[feature] = kept
+	}
+	return linked, multiple
+}
+
+// reportMultipleParents logs a warning for each story listed by several
+// features of a board, naming the features and what the policy does.
+func reportMultipleParents(board string, multiple map[int][]int, policy string) {
+	stories := make([]int, 0, len(multiple))
+	for story := range multiple {
+		stories = append(stories, story)
+	}
+	sort.Ints(stories)
+	for _, story := range stories {
+		logging.Warn("story is listed by several features",
+			"board", board,
+			"issue_number", story,
+			"features", multiple[story],
+			"outcome", describeMultipleParents(multiple[story], policy))
+	}
+}
+
+// describeMultipleParents describes what the policy does with a story listed
+// by the features, for logs and 'glue explain issue'.
+func describeMultipleParents(features []int, policy string) string {
+	switch policy {
+	case config.MultipleParentsFirstWins:
+		return fmt.Sprintf("linked to #%d only (first-wins)", features[0])
+	case config.MultipleParentsWarnAndSkip:
+		return "linked to none of them (warn-and-skip)"
+	default:
+		return "linked to all of them (link-to-all)"
+	}
+}
+
+// containsInt reports whether values contains v.
+func containsInt(values []int, v int) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+// containsString reports whether values contains v.
+func containsString(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureChildren(t *testing.T) {
+	features := []models.GitHubIssue{
+		{Number: 5, Description: "## Issues\n- https://github.com/org/repo/issues/2\n- https://github.com/org/repo/issues/3"},
+		{Number: 1, Description: "## Issues\n- https://github.com/org/repo/issues/3"},
+		{Number: 7, Description: "## Issues\n- https://github.com/org/repo/issues/3"},
+		{Number: 9, Description: "No children"},
+	}
+
+	tests := []struct {
+		policy string
+		want   map[int][]int
+	}{
+		{config.MultipleParentsLinkAll, map[int][]int{5: {2, 3}, 1: {3}, 7: {3}}},
+		{config.MultipleParentsFirstWins, map[int][]int{5: {2}, 1: {3}, 7: {}}},
+		{config.MultipleParentsWarnAndSkip, map[int][]int{5: {2}, 1: {}, 7: {}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			linked, multiple := featureChildren(features, "github.com", tt.policy)
+			assert.Equal(t, tt.want, linked)
+			assert.Equal(t, map[int][]int{3: {1, 5, 7}}, multiple)
+		})
+	}
+}
+
+func TestDescribeMultipleParents(t *testing.T) {
+	assert.Equal(t, "linked to #1 only (first-wins)", describeMultipleParents([]int{1, 5}, config.MultipleParentsFirstWins))
+	assert.Equal(t, "linked to none of them (warn-and-skip)", describeMultipleParents([]int{1, 5}, config.MultipleParentsWarnAndSkip))
+	assert.Equal(t, "linked to all of them (link-to-all)", describeMultipleParents([]int{1, 5}, config.MultipleParentsLinkAll))
+}
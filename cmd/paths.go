@@ -0,0 +1,144 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+
+	"github.com/danielolaszy/glue/internal/checkpoint"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/lock"
+	"github.com/danielolaszy/glue/internal/mappings"
+	"github.com/danielolaszy/glue/internal/paths"
+	"github.com/danielolaszy/glue/internal/runs"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/internal/statuscache"
+	"github.com/spf13/cobra"
+)
+
+// pathEntry is a location glue reads or writes.
+type pathEntry struct {
+	// Name describes what the location is used for
+	Name string
+	// Path is the file, directory or address
+	Path string
+	// Note tells whether it exists or where the setting comes from
+	Note string
+}
+
+// pathsCmd prints every location glue reads or writes.
+var pathsCmd = &cobra.Command{
+	Use:   "paths",
+	Short: "Print the files and directories glue reads and writes",
+	Long: `Print every location glue reads from or writes to on this machine: the
+config file and where it is looked for, the cache directory with repository
+locks and backfill checkpoints, and any rules script, API recording directory
+or remote log sink that is configured.
+
+Locations follow the platform's conventions, e.g. ~/.cache/glue on Linux,
+~/Library/Caches/glue on macOS and %LocalAppData%\glue on Windows. Set
+GLUE_CACHE_DIR to use another cache directory.
+
+Example:
+  glue paths`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		writePaths(cmd.OutOrStdout(), collectPaths())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(pathsCmd)
+}
+
+// collectPaths returns the locations glue uses with the current environment.
+func collectPaths() []pathEntry {
+	var entries []pathEntry
+
+	configPath, err := config.FilePath()
+	switch {
+	case err != nil:
+		entries = append(entries, pathEntry{"config file", os.Getenv("GLUE_CONFIG"), err.Error()})
+	case configPath == "":
+		entries = append(entries, pathEntry{"config file", "", "none found, using environment variables only"})
+	default:
+		note := "in use"
+		if os.Getenv("GLUE_CONFIG") != "" {
+			note = "in use, from GLUE_CONFIG"
+		}
+		entries = append(entries, pathEntry{"config file", absPath(configPath), note})
+	}
+	for _, path := range config.SearchPaths() {
+		entries = append(entries, pathEntry{"config search path", absPath(path), existence(path)})
+	}
+
+	cacheNote := existence(paths.CacheDir())
+	if os.Getenv(paths.CacheDirEnv) != "" {
+		cacheNote += ", from " + paths.CacheDirEnv
+	}
+	entries = append(entries,
+		pathEntry{"cache directory", paths.CacheDir(), cacheNote},
+		pathEntry{"repository locks", lock.DefaultDir(), existence(lock.DefaultDir())},
+		pathEntry{"backfill checkpoints", checkpoint.DefaultDir(), existence(checkpoint.DefaultDir())},
+		pathEntry{"ticket status caches", statuscache.DefaultDir(), existence(statuscache.DefaultDir())},
+		pathEntry{"broken mappings", mappings.DefaultDir(), existence(mappings.DefaultDir())},
+		pathEntry{"sync state", state.DefaultDir(), existence(state.DefaultDir())},
+		pathEntry{"run summaries", runs.DefaultDir(), existence(runs.DefaultDir())},
+	)
+
+	// The remaining locations are only known if the config loads
+	if cfg, err := config.LoadConfig(); err == nil {
+		if cfg.Rules.File != "" {
+			entries = append(entries, pathEntry{"rules script", absPath(cfg.Rules.File), existence(cfg.Rules.File)})
+		}
+		if cfg.Record != "" {
+			entries = append(entries, pathEntry{"api recordings (writing)", absPath(cfg.Record), existence(cfg.Record)})
+		}
+		if cfg.Replay != "" {
+			entries = append(entries, pathEntry{"api recordings (replaying)", absPath(cfg.Replay), existence(cfg.Replay)})
+		}
+	}
+
+	entries = append(entries, pathEntry{"log output", "standard error", "LOG_LEVEL sets the level"})
+	if addr := os.Getenv("LOG_SYSLOG_ADDR"); addr != "" {
+		entries = append(entries, pathEntry{"log sink", addr, "from LOG_SYSLOG_ADDR"})
+	}
+	if url := os.Getenv("LOG_HTTP_URL"); url != "" {
+		entries = append(entries, pathEntry{"log sink", url, "from LOG_HTTP_URL"})
+	}
+	return entries
+}
+
+// writePaths prints the locations as a table.
+func writePaths(w io.Writer, entries []pathEntry) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "USE\tPATH\tNOTE")
+	for _, e := range entries {
+		path := e.Path
+		if path == "" {
+			path = "-"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", e.Name, path, e.Note)
+	}
+	tw.Flush()
+}
+
+// absPath returns path made absolute, or unchanged if that fails.
+func absPath(path string) string {
+	if abs, err := filepath.Abs(path); err == nil {
+		return abs
+	}
+	return path
+}
+
+// existence describes whether path exists.
+func existence(path string) string {
+	if _, err := os.Stat(path); err != nil {
+		return "not created yet"
+	}
+	return "exists"
+}
@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/paths"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCollectPaths(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "glue.yaml")
+	require.NoError(t, os.WriteFile(configPath, []byte("rules:\n  file: rules.star\n"), 0o600))
+	t.Setenv("GLUE_CONFIG", configPath)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv(paths.CacheDirEnv, filepath.Join(dir, "cache"))
+	t.Setenv("GLUE_RECORD", "")
+	t.Setenv("GLUE_REPLAY", "")
+	t.Setenv("LOG_SYSLOG_ADDR", "udp://logs:514")
+	t.Setenv("LOG_HTTP_URL", "")
+
+	entries := make(map[string]pathEntry)
+	for _, e := range collectPaths() {
+		entries[e.Name] = e
+	}
+
+	assert.Equal(t, pathEntry{"config file", configPath, "in use, from GLUE_CONFIG"}, entries["config file"])
+	assert.Equal(t, pathEntry{"cache directory", filepath.Join(dir, "cache"), "not created yet, from GLUE_CACHE_DIR"}, entries["cache directory"])
+	assert.Equal(t, filepath.Join(dir, "cache", "locks"), entries["repository locks"].Path)
+	assert.Equal(t, filepath.Join(dir, "cache", "checkpoints"), entries["backfill checkpoints"].Path)
+	assert.Equal(t, filepath.Join(dir, "cache", "mappings"), entries["broken mappings"].Path)
+	assert.Equal(t, filepath.Join(dir, "cache", "statuses"), entries["ticket status caches"].Path)
+	assert.Equal(t, filepath.Join(dir, "cache", "runs"), entries["run summaries"].Path)
+	assert.Equal(t, filepath.Join(dir, "cache", "state"), entries["sync state"].Path)
+	assert.Equal(t, "not created yet", entries["rules script"].Note)
+	assert.Equal(t, pathEntry{"log sink", "udp://logs:514", "from LOG_SYSLOG_ADDR"}, entries["log sink"])
+	assert.NotContains(t, entries, "api recordings (writing)")
+}
+
+func TestWritePaths(t *testing.T) {
+	var out bytes.Buffer
+	writePaths(&out, []pathEntry{
+		{"config file", "", "none found"},
+		{"cache directory", "/home/me/.cache/glue", "exists"},
+	})
+
+	assert.Equal(t, "USE              PATH                  NOTE\n"+
+		"config file      -                     none found\n"+
+		"cache directory  /home/me/.cache/glue  exists\n", out.String())
+}
@@ -0,0 +1,111 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// initPreset is a starting point for the config file of a common way of
+// working, selected with 'glue init --preset'.
+type initPreset struct {
+	// Description is shown in the help of --preset
+	Description string
+	// Flags are command-line flag defaults, as "name: value" lines under flags
+	Flags []string
+	// Settings is YAML appended to the config file
+	Settings string
+}
+
+// initPresets are the presets by name.
+var initPresets = map[string]initPreset{
+	"safe": {
+		Description: "SAFe PI planning: epics become features, descriptions, assignees and pull requests are synced",
+		Flags: []string{
+			"sync-descriptions: true",
+			"sync-assignees: true",
+			"link-pull-requests: true",
+		},
+		Settings: `# Issues labeled 'epic' are planned as features; their '## Issues'
+# sections become the child stories of the PI
+rules:
+  script: |
+    def decide(issue):
+        if "epic" in issue["labels"]:
+            return {"type": "feature"}
+        return None
+
+synced_labels:
+  - add: ["jira-id: {key}"]
+
+# Mirror the acceptance criteria checklist to a JIRA field
+# acceptance_criteria:
+#   field: customfield_10040
+`,
+	},
+	"kanban": {
+		Description: "Simple kanban: every issue is a story, tickets close once issues stay closed for 15 minutes",
+		Flags: []string{
+			"sync-descriptions: true",
+			"close-grace-period: 15m",
+		},
+		Settings: `rules:
+  script: |
+    def decide(issue):
+        return {"type": "story"}
+
+synced_labels:
+  - add: [glued]
+`,
+	},
+	"bug-triage": {
+		Description: "Bug triage: issues get tickets once triaged, the needs-jira label is replaced with the ticket key",
+		Flags: []string{
+			"close-grace-period: 1h",
+		},
+		Settings: `# Issues still labeled needs-triage wait until they are triaged
+rules:
+  script: |
+    def decide(issue):
+        if "needs-triage" in issue["labels"]:
+            return {"skip": True}
+        return {"type": "story"}
+
+synced_labels:
+  - remove: [needs-jira]
+    add: ["jira-id: {key}"]
+
+security:
+  issue_type: Bug
+`,
+	},
+}
+
+// presetNames returns the sorted names of the presets.
+func presetNames() []string {
+	names := make([]string, 0, len(initPresets))
+	for name := range initPresets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// lookupPreset returns the preset with the name, compared case-insensitively.
+func lookupPreset(name string) (initPreset, error) {
+	preset, ok := initPresets[strings.ToLower(name)]
+	if !ok {
+		return initPreset{}, fmt.Errorf("unknown preset %q, available: %s", name, strings.Join(presetNames(), ", "))
+	}
+	return preset, nil
+}
+
+// presetHelp describes the presets for the help of --preset.
+func presetHelp() string {
+	var b strings.Builder
+	for _, name := range presetNames() {
+		fmt.Fprintf(&b, "\n  %-11s %s", name, initPresets[name].Description)
+	}
+	return b.String()
+}
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitPresetsLoad(t *testing.T) {
+	for _, env := range []string{"GITHUB_DOMAIN", "GITHUB_TOKEN", "JIRA_URL", "JIRA_USERNAME", "JIRA_TOKEN", "GLUE_PROFILE"} {
+		t.Setenv(env, "")
+	}
+
+	for _, name := range presetNames() {
+		t.Run(name, func(t *testing.T) {
+			preset, err := lookupPreset(name)
+			require.NoError(t, err)
+
+			answers := initAnswers{
+				GitHubDomain: "github.com",
+				GitHubToken:  "ghp_token",
+				JiraURL:      "https://example.atlassian.net",
+				JiraUsername: "bot@example.com",
+				Repository:   "owner/repo",
+				Boards:       []string{"PROJ"},
+				StoreTokens:  true,
+				Preset:       &preset,
+			}
+			path := filepath.Join(t.TempDir(), "glue.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(renderInitConfig(answers)), 0o600))
+			t.Setenv("GLUE_CONFIG", path)
+
+			cfg, err := config.LoadConfig()
+			require.NoError(t, err)
+			assert.Equal(t, "owner/repo", cfg.Flags["repository"])
+			assert.Len(t, cfg.Flags, len(preset.Flags)+1)
+			assert.NotEmpty(t, cfg.SyncedLabels)
+
+			// Every flag default names a flag of glue jira
+			for flag := range cfg.Flags {
+				assert.NotNil(t, jiraCmd.Flag(flag), flag)
+			}
+
+			engine, err := loadRules(cfg.Rules)
+			require.NoError(t, err)
+			require.NotNil(t, engine)
+			_, err = engine.Decide(models.GitHubIssue{Number: 1, Title: "Login", Labels: []string{"PROJ"}})
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestLookupPreset(t *testing.T) {
+	preset, err := lookupPreset("SAFe")
+	require.NoError(t, err)
+	assert.Equal(t, initPresets["safe"].Description, preset.Description)
+
+	_, err = lookupPreset("scrum")
+	assert.EqualError(t, err, `unknown preset "scrum", available: bug-triage, kanban, safe`)
+}
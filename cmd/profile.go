@@ -0,0 +1,68 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// applyConfigDefaults fills in flags the user didn't give from the config
+// file and selected profile: --board from boards, and any flag listed under
+// flags. Board aliases given with --board are then resolved. Commands load
+// the config again and report its errors themselves, so they are only logged
+// here.
+func applyConfigDefaults(cmd *cobra.Command) error {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logging.Debug("not applying flag defaults from config", "error", err)
+		return nil
+	}
+	if cfg.Profile != "" {
+		logging.Info("using config profile", "profile", cfg.Profile)
+	}
+	if err := setFlagDefaults(cmd, cfg); err != nil {
+		return err
+	}
+	return resolveBoardFlag(cmd, cfg.BoardAliases)
+}
+
+// setFlagDefaults sets the flags of cmd that weren't given on the command
+// line to their values in cfg. Flags the command doesn't have are skipped,
+// since a profile usually serves several commands.
+func setFlagDefaults(cmd *cobra.Command, cfg *config.Config) error {
+	defaults := make(map[string]interface{}, len(cfg.Flags)+1)
+	for name, value := range cfg.Flags {
+		defaults[name] = value
+	}
+	if len(cfg.Boards) > 0 {
+		defaults["board"] = cfg.Boards
+	}
+
+	for name, value := range defaults {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil || flag.Changed {
+			continue
+		}
+
+		values := []interface{}{value}
+		switch v := value.(type) {
+		case []interface{}:
+			values = v
+		case []string:
+			values = nil
+			for _, s := range v {
+				values = append(values, s)
+			}
+		}
+		for _, v := range values {
+			if err := cmd.Flags().Set(name, fmt.Sprint(v)); err != nil {
+				return fmt.Errorf("invalid value %v for flag --%s in config file: %v", v, name, err)
+			}
+		}
+		logging.Debug("flag set from config", "flag", name, "value", value)
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetFlagDefaults(t *testing.T) {
+	cmd := &cobra.Command{}
+	cmd.Flags().StringArrayP("board", "b", []string{}, "")
+	cmd.Flags().StringP("repository", "r", "", "")
+	cmd.Flags().Bool("sync-descriptions", false, "")
+	cmd.Flags().Duration("max-duration", 0, "")
+	require.NoError(t, cmd.Flags().Set("repository", "owner/cli"))
+
+	cfg := &config.Config{
+		Boards: []string{"STAGE", "QA"},
+		Flags: map[string]interface{}{
+			"repository":        "owner/config",
+			"sync-descriptions": true,
+			"max-duration":      "10m",
+			"sync-assignees":    true,
+		},
+	}
+	require.NoError(t, setFlagDefaults(cmd, cfg))
+
+	boards, _ := cmd.Flags().GetStringArray("board")
+	assert.Equal(t, []string{"STAGE", "QA"}, boards)
+	// Flags given on the command line win
+	repository, _ := cmd.Flags().GetString("repository")
+	assert.Equal(t, "owner/cli", repository)
+	syncDescriptions, _ := cmd.Flags().GetBool("sync-descriptions")
+	assert.True(t, syncDescriptions)
+	maxDuration, _ := cmd.Flags().GetDuration("max-duration")
+	assert.Equal(t, 10*time.Minute, maxDuration)
+
+	cmd = &cobra.Command{}
+	cmd.Flags().Bool("sync-descriptions", false, "")
+	err := setFlagDefaults(cmd, &config.Config{Flags: map[string]interface{}{"sync-descriptions": "sometimes"}})
+	assert.ErrorContains(t, err, "invalid value sometimes for flag --sync-descriptions in config file")
+}
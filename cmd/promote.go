@@ -0,0 +1,192 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// promoteCmd groups the commands turning other GitHub content into synced issues.
+var promoteCmd = &cobra.Command{
+	Use:   "promote",
+	Short: "Turn GitHub content into issues synced to JIRA",
+	Long:  `Turn other GitHub content, like discussions, into GitHub issues with a JIRA ticket.`,
+}
+
+// promoteDiscussionCmd converts a GitHub Discussion into a synced issue.
+var promoteDiscussionCmd = &cobra.Command{
+	Use:   "discussion <number>",
+	Short: "Convert a GitHub Discussion into an issue with a JIRA ticket",
+	Long: `Convert a GitHub Discussion into a GitHub issue routed to a JIRA board, and
+create its JIRA ticket right away, for intake workflows where requests start
+as discussions.
+
+The issue gets the discussion's title, its opening post with a link back to
+the discussion, and the 'jira-project: KEY' and 'story' or 'feature' labels.
+Its ticket is created as 'glue jira' would, including the post_issue hooks,
+and a comment linking the issue is added to the discussion. The discussion
+itself is left open.
+
+Example:
+  glue promote discussion 17 -r owner/repo -b PROJ
+  glue promote discussion 17 -r owner/repo -b PROJ --type feature`,
+	Args:    cobra.ExactArgs(1),
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.Atoi(args[0])
+		if err != nil || number <= 0 {
+			return fmt.Errorf("invalid discussion number %q", args[0])
+		}
+
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		board, err := cmd.Flags().GetString("board")
+		if err != nil {
+			return err
+		}
+
+		issueType, err := cmd.Flags().GetString("type")
+		if err != nil {
+			return err
+		}
+		issueType = strings.ToLower(issueType)
+		if issueType != "story" && issueType != "feature" {
+			return fmt.Errorf("invalid type %q, expected story or feature", issueType)
+		}
+
+		noLock, err := cmd.Flags().GetBool("no-lock")
+		if err != nil {
+			return err
+		}
+
+		ctx := context.Background()
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+		if err := configureReporter(ctx, cmd, cfg.Users, githubClient, jiraClient); err != nil {
+			return err
+		}
+
+		boards, err := resolveBoards(jiraClient, []string{board})
+		if err != nil {
+			return err
+		}
+		board = boards[0]
+
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := lockRepository(repository, noLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := checkSafety(cmd, cfg.Safety, repository, boards); err != nil {
+			return err
+		}
+
+		// Look the type up before anything is created, so a board without it
+		// doesn't leave an issue behind
+		featureTypeID, storyTypeID, err := issueTypeIDs(jiraClient, board)
+		if err != nil {
+			return err
+		}
+		typeID := storyTypeID
+		if issueType == "feature" {
+			typeID = featureTypeID
+		}
+
+		discussion, err := githubClient.GetDiscussion(ctx, repository, number)
+		if err != nil {
+			return err
+		}
+
+		issue, err := githubClient.CreateIssue(ctx, repository, discussion.Title, promotedIssueBody(discussion), promotionLabels(board, issueType))
+		if err != nil {
+			return err
+		}
+
+		out := cmd.OutOrStdout()
+		fmt.Fprintf(out, "promoted discussion #%d to issue #%d\n", discussion.Number, issue.Number)
+
+		comment := fmt.Sprintf("Promoted to #%d for tracking.", issue.Number)
+		if err := githubClient.CommentOnDiscussion(ctx, discussion.ID, comment); err != nil {
+			logging.Warn("failed to link the issue from the discussion",
+				"discussion_number", discussion.Number,
+				"error", err)
+		}
+
+		// The issue is opened by glue's account; the discussion's author is
+		// the one to attribute the ticket to
+		issue.Author = discussion.Author
+		hooks := &syncHooks{repository: repository, config: cfg.Hooks}
+		updated, err := createTicketForIssue(ctx, issue, typeID, board, repository, githubClient, jiraClient, hooks, nil)
+		if err != nil {
+			return fmt.Errorf("issue #%d was created but its ticket wasn't, the next 'glue jira' run retries: %v", issue.Number, err)
+		}
+		key := marker.GitHub.Key(updated.Title)
+		hooks.postCreate(jiraClient, []string{key})
+
+		fmt.Fprintf(out, "created %s for issue #%d\n", key, issue.Number)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(promoteCmd)
+	promoteCmd.AddCommand(promoteDiscussionCmd)
+	promoteDiscussionCmd.Flags().StringP("board", "b", "", "JIRA project board to route the issue to")
+	promoteDiscussionCmd.Flags().String("type", "story", "Issue type to create: story or feature")
+	promoteDiscussionCmd.Flags().Bool("attribute-reporter", false, "Report the JIRA ticket as the JIRA user of the discussion's author, where permissions allow")
+	promoteDiscussionCmd.Flags().Bool("no-lock", false, "Skip the per-repository lock that prevents concurrent runs")
+}
+
+// promotedIssueBody returns the body of the issue a discussion is promoted
+// to: the discussion's opening post followed by a link back to it.
+func promotedIssueBody(discussion models.GitHubDiscussion) string {
+	var b strings.Builder
+	if body := strings.TrimSpace(discussion.Body); body != "" {
+		b.WriteString(body)
+		b.WriteString("\n\n---\n")
+	}
+	fmt.Fprintf(&b, "Promoted from discussion #%d", discussion.Number)
+	if discussion.Author != "" {
+		fmt.Fprintf(&b, " by @%s", discussion.Author)
+	}
+	fmt.Fprintf(&b, ": %s\n", discussion.URL)
+	return b.String()
+}
+
+// promotionLabels returns the labels routing a promoted issue to a board as
+// the given issue type.
+func promotionLabels(board, issueType string) []string {
+	return []string{fmt.Sprintf("%s %s", boardLabelPrefix, board), issueType}
+}
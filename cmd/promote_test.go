@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPromotedIssueBody(t *testing.T) {
+	discussion := models.GitHubDiscussion{
+		Number: 17,
+		Body:   "Please add a dark mode.\n",
+		URL:    "https://github.com/owner/repo/discussions/17",
+		Author: "octocat",
+	}
+	assert.Equal(t, "Please add a dark mode.\n\n---\nPromoted from discussion #17 by @octocat: https://github.com/owner/repo/discussions/17\n",
+		promotedIssueBody(discussion))
+
+	discussion.Body, discussion.Author = "", ""
+	assert.Equal(t, "Promoted from discussion #17: https://github.com/owner/repo/discussions/17\n", promotedIssueBody(discussion))
+}
+
+func TestPromotionLabels(t *testing.T) {
+	labels := promotionLabels("PROJ", "feature")
+	assert.Equal(t, []string{"jira-project: PROJ", "feature"}, labels)
+	assert.True(t, hasBoardLabel(labels, "PROJ", nil))
+	assert.Equal(t, "feature", issueTypeForLabels(labels))
+}
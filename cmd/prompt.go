@@ -0,0 +1,103 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// fieldPrompter asks the user for values of required JIRA fields. Answers are
+// reused for later issues on the same board.
+type fieldPrompter struct {
+	mu      sync.Mutex
+	in      *bufio.Reader
+	out     io.Writer
+	answers map[string]interface{} // board + "/" + field ID -> value
+}
+
+// newFieldPrompter returns a prompter reading answers from in.
+func newFieldPrompter(in io.Reader, out io.Writer) *fieldPrompter {
+	return &fieldPrompter{in: bufio.NewReader(in), out: out, answers: make(map[string]interface{})}
+}
+
+// values implements jira.RequiredFieldsFunc. Fields left empty are not supplied.
+func (p *fieldPrompter) values(projectKey string, issue models.GitHubIssue, missing map[string]string) map[string]interface{} {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := make([]string, 0, len(missing))
+	for id := range missing {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	values := make(map[string]interface{})
+	for _, id := range ids {
+		key := projectKey + "/" + id
+		if value, ok := p.answers[key]; ok {
+			values[id] = value
+			continue
+		}
+
+		fmt.Fprintf(p.out, "#%d %s: %s requires %s (%s)\nValue (text or JSON such as {\"value\": \"Team A\"}, empty to skip): ",
+			issue.Number, issue.Title, projectKey, id, missing[id])
+		line, err := p.in.ReadString('\n')
+		line = strings.TrimSpace(line)
+		if line == "" {
+			if err != nil {
+				// No more input, stop asking
+				return values
+			}
+			continue
+		}
+
+		value := parseFieldValue(line)
+		p.answers[key] = value
+		values[id] = value
+		fmt.Fprintf(p.out, "Using this value for later issues on %s.\n", projectKey)
+	}
+	return values
+}
+
+// parseFieldValue returns a JSON object or array answer decoded, and any other
+// answer as text.
+func parseFieldValue(answer string) interface{} {
+	if strings.HasPrefix(answer, "{") || strings.HasPrefix(answer, "[") {
+		var value interface{}
+		if err := json.Unmarshal([]byte(answer), &value); err == nil {
+			return value
+		}
+	}
+	return answer
+}
+
+// configureRequiredFieldPrompt makes jiraClient prompt for required fields
+// without a configured default if --prompt-required-fields is set. Prompting
+// needs an interactive terminal.
+func configureRequiredFieldPrompt(cmd *cobra.Command, jiraClient *jira.Client) error {
+	prompt, err := cmd.Flags().GetBool("prompt-required-fields")
+	if err != nil {
+		return fmt.Errorf("failed to get prompt-required-fields flag: %v", err)
+	}
+	if !prompt {
+		return nil
+	}
+
+	info, err := os.Stdin.Stat()
+	if err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("--prompt-required-fields needs an interactive terminal; set defaults under required_fields in the config file instead")
+	}
+
+	jiraClient.SetRequiredFieldsFunc(newFieldPrompter(cmd.InOrStdin(), cmd.ErrOrStderr()).values)
+	return nil
+}
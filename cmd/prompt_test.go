@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldPrompter(t *testing.T) {
+	var out bytes.Buffer
+	prompter := newFieldPrompter(strings.NewReader("Unplanned\n\n{\"value\": \"Team A\"}\n"), &out)
+	missing := map[string]string{
+		"customfield_10050": "Team is required.",
+		"customfield_10011": "Epic Name is required.",
+	}
+
+	// Fields are asked in ID order; the second is skipped
+	values := prompter.values("PROJ", models.GitHubIssue{Number: 1, Title: "Add login"}, missing)
+	assert.Equal(t, map[string]interface{}{"customfield_10011": "Unplanned"}, values)
+	assert.Contains(t, out.String(), "#1 Add login: PROJ requires customfield_10011 (Epic Name is required.)")
+
+	// The earlier answer is reused; only the skipped field is asked again
+	out.Reset()
+	values = prompter.values("PROJ", models.GitHubIssue{Number: 2, Title: "Add logout"}, missing)
+	assert.Equal(t, map[string]interface{}{
+		"customfield_10011": "Unplanned",
+		"customfield_10050": map[string]interface{}{"value": "Team A"},
+	}, values)
+	assert.NotContains(t, out.String(), "customfield_10011")
+
+	// Without more input nothing is supplied for other boards
+	assert.Empty(t, prompter.values("OPS", models.GitHubIssue{Number: 3}, missing))
+}
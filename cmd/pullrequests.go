@@ -0,0 +1,93 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// closingReferencePattern matches GitHub's closing keywords in pull request
+// descriptions, e.g. "Fixes #42", and captures the issue number.
+var closingReferencePattern = regexp.MustCompile(`(?i)\b(?:close[sd]?|fix(?:e[sd])?|resolve[sd]?)\s*:?\s+#([0-9]+)\b`)
+
+// linkPullRequests links the merged pull requests of a repository to the
+// JIRA tickets of the given issues they belong to, see pullRequestTickets.
+// Pull requests already linked to a ticket are skipped. Returns the number of
+// links added.
+func linkPullRequests(ctx context.Context, repository string, issues []models.GitHubIssue, githubClient *github.Client, jiraClient *jira.Client) (int, error) {
+	pulls, err := githubClient.GetPullRequests(ctx, repository)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch github pull requests: %v", err)
+	}
+
+	keysByIssue := make(map[int]string)
+	for _, issue := range issues {
+		if key := marker.GitHub.Key(issue.Title); key != "" {
+			keysByIssue[issue.Number] = key
+		}
+	}
+
+	linkCount := 0
+	for _, pull := range pulls {
+		if !pull.Merged {
+			continue
+		}
+		for _, key := range pullRequestTickets(pull, keysByIssue) {
+			if stopStarting(ctx, jiraClient) {
+				return linkCount, nil
+			}
+
+			linked, err := jiraClient.LinkPullRequest(key, pull)
+			if err != nil {
+				logging.Error("failed to link pull request",
+					"pull_request", pull.Number,
+					"jira_ticket", key,
+					"error", err)
+				continue
+			}
+			if linked {
+				linkCount++
+			}
+		}
+	}
+	return linkCount, nil
+}
+
+// pullRequestTickets returns the sorted keys of the tickets, among those
+// mapped to issues in keysByIssue, a pull request belongs to: tickets whose
+// key is in its title or branch name, and tickets of the issues its
+// description closes, e.g. with "Fixes #42".
+func pullRequestTickets(pull models.GitHubPullRequest, keysByIssue map[int]string) []string {
+	found := make(map[string]bool)
+	for _, key := range keysByIssue {
+		pattern := ticketKeyPattern(key)
+		if pattern.MatchString(pull.Title) || pattern.MatchString(pull.HeadBranch) {
+			found[key] = true
+		}
+	}
+	for _, match := range closingReferencePattern.FindAllStringSubmatch(pull.Body, -1) {
+		number, err := strconv.Atoi(match[1])
+		if err != nil {
+			continue
+		}
+		if key, ok := keysByIssue[number]; ok {
+			found[key] = true
+		}
+	}
+
+	keys := make([]string, 0, len(found))
+	for key := range found {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
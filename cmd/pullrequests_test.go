@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPullRequestTickets(t *testing.T) {
+	keysByIssue := map[int]string{1: "PROJ-1", 2: "PROJ-12", 42: "OPS-3"}
+
+	tests := []struct {
+		name string
+		pull models.GitHubPullRequest
+		want []string
+	}{
+		{
+			name: "key in title",
+			pull: models.GitHubPullRequest{Title: "PROJ-1: Add login"},
+			want: []string{"PROJ-1"},
+		},
+		{
+			name: "key in branch, ignoring case",
+			pull: models.GitHubPullRequest{Title: "Add login", HeadBranch: "feature/proj-12-login"},
+			want: []string{"PROJ-12"},
+		},
+		{
+			name: "closing keyword",
+			pull: models.GitHubPullRequest{Title: "Cleanup", Body: "Some text.\n\nFixes #42, closes: #1 and refs #2"},
+			want: []string{"OPS-3", "PROJ-1"},
+		},
+		{
+			name: "unmapped keys and issues",
+			pull: models.GitHubPullRequest{Title: "PROJ-123 and OTHER-1", Body: "Resolves #7"},
+			want: []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, pullRequestTickets(tt.pull, keysByIssue))
+		})
+	}
+}
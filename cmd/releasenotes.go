@@ -0,0 +1,263 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// releaseNoteOrder lists the issue types shown first, in this order; other
+// types follow alphabetically.
+var releaseNoteOrder = []string{"Feature", "Story", "Bug"}
+
+// releaseNote is a ticket of a release with the GitHub items it maps to.
+type releaseNote struct {
+	Ticket models.JiraTicket
+	// Issues are the GitHub issues synced to the ticket
+	Issues []models.GitHubIssue
+	// PullRequests are the merged pull requests mentioning the ticket key in
+	// their title or branch
+	PullRequests []models.GitHubPullRequest
+}
+
+// releaseNoteGroup holds the notes of one issue type.
+type releaseNoteGroup struct {
+	Type  string
+	Notes []releaseNote
+}
+
+// releaseNotesCmd generates Markdown release notes for a JIRA fix version.
+var releaseNotesCmd = &cobra.Command{
+	Use:   "release-notes",
+	Short: "Generate release notes for a JIRA fix version",
+	Long: `Generate Markdown release notes for the JIRA tickets in a fix version.
+
+Each ticket is cross-referenced with the GitHub issues synced to it and the
+merged pull requests mentioning its key in their title or branch name (e.g.
+'PROJ-123: Add login' or 'feature/proj-123-login'). Notes are grouped by issue
+type; tickets that are not Done yet are marked with their status.
+
+Example:
+  glue release-notes -r owner/repo -b PROJ --fix-version "PI 25.2" > RELEASE_NOTES.md
+  glue release-notes -r owner/repo -b PROJ --fix-version "PI 25.2" -o RELEASE_NOTES.md`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true, Required: []string{"fix-version"}}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		fixVersion, err := cmd.Flags().GetString("fix-version")
+		if err != nil {
+			return err
+		}
+
+		output, err := cmd.Flags().GetString("output")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		tickets, err := jiraClient.SearchTickets(fixVersionJQL(boards, fixVersion))
+		if err != nil {
+			return fmt.Errorf("failed to get tickets of fix version %s: %v", fixVersion, err)
+		}
+
+		openIssues, err := githubClient.GetAllIssues(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch github issues: %v", err)
+		}
+		closedIssues, err := githubClient.GetClosedIssues(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch closed github issues: %v", err)
+		}
+
+		pulls, err := githubClient.GetPullRequests(cmd.Context(), repository)
+		if err != nil {
+			return fmt.Errorf("failed to fetch github pull requests: %v", err)
+		}
+
+		groups := buildReleaseNotes(tickets, append(openIssues, closedIssues...), pulls)
+		links := releaseNoteLinks{
+			JiraBaseURL: jiraClient.BaseURL,
+			IssueURL:    fmt.Sprintf("https://%s/%s/issues/", cfg.GitHub.Domain, repository),
+		}
+		w, closeOutput, err := outputWriter(cmd, output)
+		if err != nil {
+			return err
+		}
+		err = writeReleaseNotes(w, fixVersion, groups, links)
+		if closeErr := closeOutput(); err == nil {
+			err = closeErr
+		}
+		return err
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(releaseNotesCmd)
+	releaseNotesCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) the fix version belongs to (can be specified multiple times)")
+	releaseNotesCmd.Flags().String("fix-version", "", "JIRA fix version to generate release notes for (e.g. \"PI 25.2\")")
+	releaseNotesCmd.Flags().StringP("output", "o", "", "File to write the release notes to instead of stdout")
+}
+
+// fixVersionJQL builds the JQL query selecting the tickets of a fix version on
+// the given boards.
+func fixVersionJQL(boards []string, fixVersion string) string {
+	quoted := make([]string, 0, len(boards))
+	for _, board := range boards {
+		quoted = append(quoted, fmt.Sprintf("'%s'", board))
+	}
+	return fmt.Sprintf("project in (%s) AND fixVersion = %q ORDER BY key ASC",
+		strings.Join(quoted, ", "), fixVersion)
+}
+
+// buildReleaseNotes cross-references tickets with the GitHub issues synced to
+// them and the merged pull requests mentioning their key, grouped by issue type.
+func buildReleaseNotes(tickets []models.JiraTicket, issues []models.GitHubIssue, pulls []models.GitHubPullRequest) []releaseNoteGroup {
+	issuesByKey := make(map[string][]models.GitHubIssue)
+	for _, issue := range issues {
+		if key := marker.GitHub.Key(issue.Title); key != "" {
+			issuesByKey[key] = append(issuesByKey[key], issue)
+		}
+	}
+
+	byType := make(map[string][]releaseNote)
+	for _, ticket := range tickets {
+		note := releaseNote{Ticket: ticket, Issues: issuesByKey[ticket.Key]}
+		pattern := ticketKeyPattern(ticket.Key)
+		for _, pull := range pulls {
+			if pull.Merged && (pattern.MatchString(pull.Title) || pattern.MatchString(pull.HeadBranch)) {
+				note.PullRequests = append(note.PullRequests, pull)
+			}
+		}
+		byType[ticket.Type] = append(byType[ticket.Type], note)
+	}
+
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		ri, rj := typeRank(types[i]), typeRank(types[j])
+		if ri != rj {
+			return ri < rj
+		}
+		return types[i] < types[j]
+	})
+
+	groups := make([]releaseNoteGroup, 0, len(types))
+	for _, t := range types {
+		groups = append(groups, releaseNoteGroup{Type: t, Notes: byType[t]})
+	}
+	return groups
+}
+
+// ticketKeyPattern matches a ticket key as a whole word, ignoring case, so
+// PROJ-1 matches "proj-1-login" but not "PROJ-12".
+func ticketKeyPattern(key string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i)(^|[^a-z0-9])` + regexp.QuoteMeta(key) + `([^0-9]|$)`)
+}
+
+// typeRank returns the position of an issue type in releaseNoteOrder, or
+// len(releaseNoteOrder) for other types.
+func typeRank(issueType string) int {
+	for i, t := range releaseNoteOrder {
+		if strings.EqualFold(t, issueType) {
+			return i
+		}
+	}
+	return len(releaseNoteOrder)
+}
+
+// releaseNoteLinks holds the URL prefixes used to link tickets and issues.
+type releaseNoteLinks struct {
+	// JiraBaseURL is the JIRA instance URL; tickets link to its /browse page
+	JiraBaseURL string
+	// IssueURL is prefixed to GitHub issue numbers
+	IssueURL string
+}
+
+// writeReleaseNotes renders release notes as Markdown.
+func writeReleaseNotes(w io.Writer, fixVersion string, groups []releaseNoteGroup, links releaseNoteLinks) error {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Release notes: %s\n", fixVersion)
+
+	if len(groups) == 0 {
+		b.WriteString("\nNo tickets in this fix version.\n")
+	}
+
+	for _, group := range groups {
+		fmt.Fprintf(&b, "\n## %s\n\n", pluralType(group.Type))
+		for _, note := range group.Notes {
+			ticketURL := strings.TrimRight(links.JiraBaseURL, "/") + "/browse/" + note.Ticket.Key
+			fmt.Fprintf(&b, "- [%s](%s) %s", note.Ticket.Key, ticketURL, note.Ticket.Title)
+
+			var refs []string
+			for _, issue := range note.Issues {
+				refs = append(refs, fmt.Sprintf("[#%d](%s%d)", issue.Number, links.IssueURL, issue.Number))
+			}
+			for _, pull := range note.PullRequests {
+				refs = append(refs, fmt.Sprintf("PR [#%d](%s)", pull.Number, pull.URL))
+			}
+			if len(refs) > 0 {
+				fmt.Fprintf(&b, " (%s)", strings.Join(refs, ", "))
+			}
+			if note.Ticket.Status != "" && note.Ticket.StatusCategory != jira.StatusCategoryDone {
+				fmt.Fprintf(&b, " _%s_", note.Ticket.Status)
+			}
+			b.WriteString("\n")
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// pluralType returns the section heading of an issue type, e.g. "Stories".
+func pluralType(issueType string) string {
+	switch {
+	case issueType == "":
+		return "Other"
+	case strings.HasSuffix(issueType, "y") && !strings.HasSuffix(issueType, "ay") && !strings.HasSuffix(issueType, "ey"):
+		return strings.TrimSuffix(issueType, "y") + "ies"
+	case strings.HasSuffix(issueType, "s"):
+		return issueType
+	default:
+		return issueType + "s"
+	}
+}
@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixVersionJQL(t *testing.T) {
+	assert.Equal(t, `project in ('PROJ', 'OPS') AND fixVersion = "PI 25.2" ORDER BY key ASC`,
+		fixVersionJQL([]string{"PROJ", "OPS"}, "PI 25.2"))
+}
+
+func TestBuildReleaseNotes(t *testing.T) {
+	tickets := []models.JiraTicket{
+		{Key: "PROJ-2", Title: "Fix crash", Type: "Bug", Status: "Done", StatusCategory: "done"},
+		{Key: "PROJ-1", Title: "Add login", Type: "Story", Status: "Done", StatusCategory: "done"},
+		{Key: "PROJ-3", Title: "Tidy docs", Type: "Task", Status: "In Progress"},
+		{Key: "PROJ-4", Title: "SSO", Type: "Feature", Status: "Done", StatusCategory: "done"},
+	}
+	issues := []models.GitHubIssue{
+		{Number: 10, Title: "[PROJ-1] Add login"},
+		{Number: 11, Title: "[PROJ-12] Other"},
+		{Number: 12, Title: "Unsynced"},
+	}
+	pulls := []models.GitHubPullRequest{
+		{Number: 20, Title: "PROJ-1: Add login form", Merged: true, URL: "https://github.com/o/r/pull/20"},
+		{Number: 21, Title: "Crash fix", HeadBranch: "fix/proj-2-crash", Merged: true, URL: "https://github.com/o/r/pull/21"},
+		{Number: 22, Title: "PROJ-1 follow-up", Merged: false},
+		{Number: 23, Title: "PROJ-12 unrelated", Merged: true},
+	}
+
+	groups := buildReleaseNotes(tickets, issues, pulls)
+
+	var types []string
+	for _, g := range groups {
+		types = append(types, g.Type)
+	}
+	assert.Equal(t, []string{"Feature", "Story", "Bug", "Task"}, types)
+
+	story := groups[1].Notes[0]
+	assert.Equal(t, []models.GitHubIssue{issues[0]}, story.Issues)
+	assert.Equal(t, []models.GitHubPullRequest{pulls[0]}, story.PullRequests)
+	assert.Equal(t, []models.GitHubPullRequest{pulls[1]}, groups[2].Notes[0].PullRequests)
+
+	var buf bytes.Buffer
+	require.NoError(t, writeReleaseNotes(&buf, "PI 25.2", groups, releaseNoteLinks{
+		JiraBaseURL: "https://jira.example.com/",
+		IssueURL:    "https://github.com/o/r/issues/",
+	}))
+	assert.Equal(t, `# Release notes: PI 25.2
+
+## Features
+
+- [PROJ-4](https://jira.example.com/browse/PROJ-4) SSO
+
+## Stories
+
+- [PROJ-1](https://jira.example.com/browse/PROJ-1) Add login ([#10](https://github.com/o/r/issues/10), PR [#20](https://github.com/o/r/pull/20))
+
+## Bugs
+
+- [PROJ-2](https://jira.example.com/browse/PROJ-2) Fix crash (PR [#21](https://github.com/o/r/pull/21))
+
+## Tasks
+
+- [PROJ-3](https://jira.example.com/browse/PROJ-3) Tidy docs _In Progress_
+`, buf.String())
+}
+
+func TestWriteReleaseNotesEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeReleaseNotes(&buf, "PI 25.2", nil, releaseNoteLinks{}))
+	assert.Equal(t, "# Release notes: PI 25.2\n\nNo tickets in this fix version.\n", buf.String())
+}
@@ -0,0 +1,104 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/spf13/cobra"
+)
+
+// inferRepository sets --repository from the origin remote of the git
+// checkout in the working directory, if it wasn't given and the remote is a
+// repository on the configured GitHub domain. The inferred repository is
+// printed, so it's clear which repository a run changes.
+func inferRepository(cmd *cobra.Command) error {
+	flag := cmd.Flags().Lookup("repository")
+	if flag == nil || flag.Value.String() != "" {
+		return nil
+	}
+
+	remote, err := originURL()
+	if err != nil {
+		logging.Debug("not inferring repository from git remote", "error", err)
+		return nil
+	}
+
+	domain := githubDomain()
+	repository, ok := parseRemoteRepository(remote, domain)
+	if !ok {
+		logging.Debug("git remote origin is not a repository on the github domain", "remote", remote, "domain", domain)
+		return nil
+	}
+
+	if err := cmd.Flags().Set("repository", repository); err != nil {
+		return err
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "Using repository %s from git remote origin (set --repository to override)\n", repository)
+	return nil
+}
+
+// originURL returns the URL of the origin remote of the git checkout in the
+// working directory.
+func originURL() (string, error) {
+	return gitOutput("remote", "get-url", "origin")
+}
+
+// gitOutput runs git with args in the working directory and returns its
+// trimmed output.
+func gitOutput(args ...string) (string, error) {
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return "", fmt.Errorf("git %s failed: %v", strings.Join(args, " "), err)
+	}
+	return strings.TrimSpace(string(out)), nil
+}
+
+// githubDomain returns the configured GitHub domain, or GITHUB_DOMAIN if the
+// config can't be loaded.
+func githubDomain() string {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return os.Getenv("GITHUB_DOMAIN")
+	}
+	return cfg.GitHub.Domain
+}
+
+// parseRemoteRepository returns the owner/repo of a git remote URL, in URL
+// (https://host/owner/repo.git, ssh://git@host/owner/repo.git) or scp-like
+// (git@host:owner/repo.git) form. It reports false if the remote isn't on
+// domain.
+func parseRemoteRepository(remote, domain string) (string, bool) {
+	var host, path string
+	if strings.Contains(remote, "://") {
+		u, err := url.Parse(remote)
+		if err != nil {
+			return "", false
+		}
+		host, path = u.Hostname(), u.Path
+	} else {
+		var found bool
+		host, path, found = strings.Cut(remote, ":")
+		if !found {
+			return "", false
+		}
+		if _, h, ok := strings.Cut(host, "@"); ok {
+			host = h
+		}
+	}
+
+	if domain == "" || !strings.EqualFold(host, domain) {
+		return "", false
+	}
+
+	repository := strings.TrimSuffix(strings.Trim(path, "/"), ".git")
+	if !repositoryPattern.MatchString(repository) {
+		return "", false
+	}
+	return repository, true
+}
@@ -0,0 +1,403 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// boardMetrics holds the throughput and completion figures of a single board.
+type boardMetrics struct {
+	Board string `json:"board"`
+	// Created is the number of issues opened in the period
+	Created int `json:"created"`
+	// Closed is the number of issues closed in the period
+	Closed int `json:"closed"`
+	// MeanLeadTimeHours is the mean time from GitHub open to JIRA done, for
+	// tickets resolved in the period
+	MeanLeadTimeHours float64 `json:"mean_lead_time_hours"`
+	// LeadTimeSamples is the number of tickets the mean lead time is based on
+	LeadTimeSamples int `json:"lead_time_samples"`
+	// Features is the number of features listing child issues
+	Features int `json:"features"`
+	// ChildIssues is the number of child issues listed by those features
+	ChildIssues int `json:"child_issues"`
+	// ChildIssuesClosed is the number of those child issues that are closed
+	ChildIssuesClosed int `json:"child_issues_closed"`
+	// CompletionPercent is the share of closed child issues
+	CompletionPercent float64 `json:"completion_percent"`
+}
+
+// reportCmd groups commands that report on synchronized issues.
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Report on synchronized GitHub issues and JIRA tickets",
+	Long:  `Report on GitHub issues and their JIRA tickets without changing anything.`,
+}
+
+// reportMetricsCmd prints burn-down and throughput metrics per board.
+var reportMetricsCmd = &cobra.Command{
+	Use:   "metrics",
+	Short: "Show throughput and completion metrics per board",
+	Long: `Compute throughput and completion metrics for each board over a period.
+
+For every board, the following metrics are reported:
+- created: GitHub issues opened in the period
+- closed: GitHub issues closed in the period
+- mean lead time: mean time from GitHub issue open to JIRA ticket resolution,
+  for tickets resolved in the period
+- hierarchy completion: the share of closed child issues listed in the
+  '## Issues' section of the board's features
+
+The period is given with --since as days (30d), weeks (2w) or a duration (72h).
+
+Example:
+  glue report metrics -r owner/repo -b PROJ --since 30d --format csv`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		sinceFlag, err := cmd.Flags().GetString("since")
+		if err != nil {
+			return err
+		}
+		period, err := parseSince(sinceFlag)
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "table" && format != "json" && format != "csv" {
+			return fmt.Errorf("invalid format %q, expected table, json or csv", format)
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		issuesByBoard, err := fetchIssuesByBoard(cmd.Context(), githubClient, repository, boards, cfg.Routes, cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+
+		// Fetch each synced ticket once for the lead time
+		tickets := make(map[string]models.JiraTicket)
+		for _, board := range boards {
+			for _, issue := range issuesByBoard[board] {
+				jiraKey := marker.GitHub.Key(issue.Title)
+				if jiraKey == "" {
+					continue
+				}
+				if _, ok := tickets[jiraKey]; ok {
+					continue
+				}
+				ticket, err := jiraClient.GetTicket(jiraKey)
+				if err != nil {
+					logging.Warn("failed to get jira ticket, excluding it from lead time",
+						"issue_number", issue.Number,
+						"jira_ticket", jiraKey,
+						"error", err)
+					continue
+				}
+				tickets[jiraKey] = ticket
+			}
+		}
+
+		since := time.Now().Add(-period)
+		metrics := make([]boardMetrics, 0, len(boards))
+		for _, board := range boards {
+			metrics = append(metrics, computeBoardMetrics(board, issuesByBoard[board], tickets, since, cfg.GitHub.Domain))
+		}
+
+		return writeMetrics(cmd.OutOrStdout(), metrics, format)
+	},
+}
+
+// reportStatsCmd prints the ticket counts of each board.
+var reportStatsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show ticket counts per board",
+	Long: `Count the JIRA tickets of each board: in total, created by glue, and open
+versus done by status category.
+
+Tickets created by glue carry the 'glue' label. Tickets created by earlier
+versions of glue don't, and are not counted as created by glue.
+
+Example:
+  glue report stats -b PROJ --format json`,
+	PreRunE: validateFlags(flagRules{Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		format, err := cmd.Flags().GetString("format")
+		if err != nil {
+			return err
+		}
+		if format != "table" && format != "json" && format != "csv" {
+			return fmt.Errorf("invalid format %q, expected table, json or csv", format)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		boards, err = resolveBoards(jiraClient, boards)
+		if err != nil {
+			return err
+		}
+
+		stats := make([]models.BoardStats, 0, len(boards))
+		for _, board := range boards {
+			boardStats, err := jiraClient.GetBoardStats(board)
+			if err != nil {
+				return err
+			}
+			stats = append(stats, boardStats)
+		}
+
+		return writeBoardStats(cmd.OutOrStdout(), stats, format)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(reportMetricsCmd)
+	reportCmd.AddCommand(reportStatsCmd)
+	reportStatsCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to report on (can be specified multiple times)")
+	reportStatsCmd.Flags().String("format", "table", "Output format: table, json or csv")
+	reportMetricsCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to report on (can be specified multiple times)")
+	reportMetricsCmd.Flags().String("since", "30d", "Period to report on, e.g. 30d, 2w or 72h")
+	reportMetricsCmd.Flags().String("format", "table", "Output format: table, json or csv")
+}
+
+// parseSince parses a reporting period. Besides Go durations (e.g. "72h") it
+// accepts whole days ("30d") and weeks ("2w").
+func parseSince(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, fmt.Errorf("invalid period: empty value")
+	}
+
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[value[len(value)-1]]
+	if unit != 0 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid period: %s", value)
+		}
+		return time.Duration(n) * unit, nil
+	}
+
+	period, err := time.ParseDuration(value)
+	if err != nil || period <= 0 {
+		return 0, fmt.Errorf("invalid period: %s", value)
+	}
+	return period, nil
+}
+
+// computeBoardMetrics computes the metrics of a board from its GitHub issues and
+// their JIRA tickets, keyed by ticket key. Only activity after since is counted,
+// except hierarchy completion which reflects the current state.
+func computeBoardMetrics(board string, issues []models.GitHubIssue, tickets map[string]models.JiraTicket, since time.Time, gitHubDomain string) boardMetrics {
+	metrics := boardMetrics{Board: board}
+
+	byNumber := make(map[int]models.GitHubIssue, len(issues))
+	var leadTime time.Duration
+	for _, issue := range issues {
+		if _, ok := byNumber[issue.Number]; ok {
+			continue
+		}
+		byNumber[issue.Number] = issue
+
+		if !issue.CreatedAt.IsZero() && !issue.CreatedAt.Before(since) {
+			metrics.Created++
+		}
+		if issue.ClosedAt != nil && !issue.ClosedAt.Before(since) {
+			metrics.Closed++
+		}
+
+		ticket, ok := tickets[marker.GitHub.Key(issue.Title)]
+		if !ok || ticket.ResolvedAt == nil || ticket.ResolvedAt.Before(since) || issue.CreatedAt.IsZero() {
+			continue
+		}
+		if lead := ticket.ResolvedAt.Sub(issue.CreatedAt); lead > 0 {
+			leadTime += lead
+			metrics.LeadTimeSamples++
+		}
+	}
+
+	if metrics.LeadTimeSamples > 0 {
+		metrics.MeanLeadTimeHours = roundTo(leadTime.Hours()/float64(metrics.LeadTimeSamples), 1)
+	}
+
+	for _, issue := range byNumber {
+		if issueTypeOf(issue) != "feature" {
+			continue
+		}
+		children := parseChildIssues(issue.Description, gitHubDomain)
+		if len(children) == 0 {
+			continue
+		}
+		metrics.Features++
+		for _, number := range children {
+			metrics.ChildIssues++
+			if child, ok := byNumber[number]; ok && child.State == "closed" {
+				metrics.ChildIssuesClosed++
+			}
+		}
+	}
+
+	if metrics.ChildIssues > 0 {
+		metrics.CompletionPercent = roundTo(100*float64(metrics.ChildIssuesClosed)/float64(metrics.ChildIssues), 1)
+	}
+
+	return metrics
+}
+
+// roundTo rounds value to the given number of decimal places.
+func roundTo(value float64, places int) float64 {
+	scale := math.Pow10(places)
+	return math.Round(value*scale) / scale
+}
+
+// writeMetrics writes the metrics in the given format: table, json or csv.
+func writeMetrics(w io.Writer, metrics []boardMetrics, format string) error {
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(metrics)
+	case "csv":
+		writer := csv.NewWriter(w)
+		header := []string{"board", "created", "closed", "mean_lead_time_hours", "lead_time_samples",
+			"features", "child_issues", "child_issues_closed", "completion_percent"}
+		if err := writer.Write(header); err != nil {
+			return err
+		}
+		for _, m := range metrics {
+			record := []string{
+				m.Board,
+				strconv.Itoa(m.Created),
+				strconv.Itoa(m.Closed),
+				strconv.FormatFloat(m.MeanLeadTimeHours, 'f', 1, 64),
+				strconv.Itoa(m.LeadTimeSamples),
+				strconv.Itoa(m.Features),
+				strconv.Itoa(m.ChildIssues),
+				strconv.Itoa(m.ChildIssuesClosed),
+				strconv.FormatFloat(m.CompletionPercent, 'f', 1, 64),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BOARD\tCREATED\tCLOSED\tMEAN LEAD TIME\tFEATURES\tHIERARCHY COMPLETION")
+		for _, m := range metrics {
+			lead := "-"
+			if m.LeadTimeSamples > 0 {
+				lead = fmt.Sprintf("%.1fd (n=%d)", m.MeanLeadTimeHours/24, m.LeadTimeSamples)
+			}
+			completion := "-"
+			if m.ChildIssues > 0 {
+				completion = fmt.Sprintf("%.1f%% (%d/%d)", m.CompletionPercent, m.ChildIssuesClosed, m.ChildIssues)
+			}
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%s\t%d\t%s\n", m.Board, m.Created, m.Closed, lead, m.Features, completion)
+		}
+		return tw.Flush()
+	}
+}
+
+// writeBoardStats writes board ticket counts in the given format: table, json or csv.
+func writeBoardStats(w io.Writer, stats []models.BoardStats, format string) error {
+	switch format {
+	case "json":
+		type boardStatsJSON struct {
+			Board         string `json:"board"`
+			Total         int    `json:"total"`
+			CreatedByGlue int    `json:"created_by_glue"`
+			Open          int    `json:"open"`
+			Done          int    `json:"done"`
+		}
+		out := make([]boardStatsJSON, 0, len(stats))
+		for _, s := range stats {
+			out = append(out, boardStatsJSON{s.ProjectKey, s.Total, s.CreatedByGlue, s.Open, s.Done})
+		}
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(out)
+	case "csv":
+		writer := csv.NewWriter(w)
+		if err := writer.Write([]string{"board", "total", "created_by_glue", "open", "done"}); err != nil {
+			return err
+		}
+		for _, s := range stats {
+			record := []string{
+				s.ProjectKey,
+				strconv.Itoa(s.Total),
+				strconv.Itoa(s.CreatedByGlue),
+				strconv.Itoa(s.Open),
+				strconv.Itoa(s.Done),
+			}
+			if err := writer.Write(record); err != nil {
+				return err
+			}
+		}
+		writer.Flush()
+		return writer.Error()
+	default:
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(tw, "BOARD\tTOTAL\tCREATED BY GLUE\tOPEN\tDONE")
+		for _, s := range stats {
+			fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\n", s.ProjectKey, s.Total, s.CreatedByGlue, s.Open, s.Done)
+		}
+		return tw.Flush()
+	}
+}
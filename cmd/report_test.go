@@ -0,0 +1,167 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSince(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{"30d", 30 * 24 * time.Hour, false},
+		{"2w", 14 * 24 * time.Hour, false},
+		{"72h", 72 * time.Hour, false},
+		{" 1d ", 24 * time.Hour, false},
+		{"", 0, true},
+		{"0d", 0, true},
+		{"-3d", 0, true},
+		{"xd", 0, true},
+		{"soon", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			period, err := parseSince(tt.value)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, period)
+		})
+	}
+}
+
+func TestComputeBoardMetrics(t *testing.T) {
+	now := time.Date(2025, 6, 30, 12, 0, 0, 0, time.UTC)
+	since := now.AddDate(0, 0, -30)
+	at := func(daysAgo int) time.Time { return now.AddDate(0, 0, -daysAgo) }
+	ptr := func(t time.Time) *time.Time { return &t }
+
+	issues := []models.GitHubIssue{
+		{
+			Number:      1,
+			Title:       "[PROJ-1] Feature",
+			Description: "## Issues\n- https://github.com/org/repo/issues/2\n- https://github.com/org/repo/issues/3",
+			Labels:      []string{"feature", "PROJ"},
+			State:       "open",
+			CreatedAt:   at(60),
+		},
+		{
+			Number:    2,
+			Title:     "[PROJ-2] Closed story",
+			Labels:    []string{"story", "PROJ"},
+			State:     "closed",
+			CreatedAt: at(10),
+			ClosedAt:  ptr(at(2)),
+		},
+		{
+			Number:    3,
+			Title:     "[PROJ-3] Open story",
+			Labels:    []string{"story", "PROJ"},
+			State:     "open",
+			CreatedAt: at(5),
+		},
+		{
+			Number:    4,
+			Title:     "[PROJ-4] Old story",
+			Labels:    []string{"story", "PROJ"},
+			State:     "closed",
+			CreatedAt: at(90),
+			ClosedAt:  ptr(at(45)),
+		},
+		// Duplicate entries are counted once
+		{
+			Number:    3,
+			Title:     "[PROJ-3] Open story",
+			Labels:    []string{"story", "PROJ"},
+			State:     "open",
+			CreatedAt: at(5),
+		},
+	}
+	tickets := map[string]models.JiraTicket{
+		"PROJ-2": {Key: "PROJ-2", ResolvedAt: ptr(at(1))},
+		"PROJ-4": {Key: "PROJ-4", ResolvedAt: ptr(at(44))},
+	}
+
+	metrics := computeBoardMetrics("PROJ", issues, tickets, since, "github.com")
+
+	assert.Equal(t, boardMetrics{
+		Board:             "PROJ",
+		Created:           2,
+		Closed:            1,
+		MeanLeadTimeHours: 216,
+		LeadTimeSamples:   1,
+		Features:          1,
+		ChildIssues:       2,
+		ChildIssuesClosed: 1,
+		CompletionPercent: 50,
+	}, metrics)
+}
+
+func TestComputeBoardMetricsEmpty(t *testing.T) {
+	metrics := computeBoardMetrics("PROJ", nil, nil, time.Now(), "github.com")
+	assert.Equal(t, boardMetrics{Board: "PROJ"}, metrics)
+}
+
+func TestWriteMetrics(t *testing.T) {
+	metrics := []boardMetrics{
+		{Board: "PROJ", Created: 3, Closed: 2, MeanLeadTimeHours: 36, LeadTimeSamples: 2, Features: 1, ChildIssues: 4, ChildIssuesClosed: 1, CompletionPercent: 25},
+		{Board: "OPS"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeMetrics(&buf, metrics, "table"))
+		assert.Contains(t, buf.String(), "BOARD")
+		assert.Contains(t, buf.String(), "1.5d (n=2)")
+		assert.Contains(t, buf.String(), "25.0% (1/4)")
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeMetrics(&buf, metrics, "json"))
+		var decoded []boardMetrics
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+		assert.Equal(t, metrics, decoded)
+	})
+
+	t.Run("csv", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, writeMetrics(&buf, metrics, "csv"))
+		assert.Equal(t,
+			"board,created,closed,mean_lead_time_hours,lead_time_samples,features,child_issues,child_issues_closed,completion_percent\n"+
+				"PROJ,3,2,36.0,2,1,4,1,25.0\n"+
+				"OPS,0,0,0.0,0,0,0,0,0.0\n",
+			buf.String())
+	})
+}
+
+func TestWriteBoardStats(t *testing.T) {
+	stats := []models.BoardStats{{ProjectKey: "PROJ", Total: 120, CreatedByGlue: 80, Open: 45, Done: 75}}
+
+	var buf bytes.Buffer
+	require.NoError(t, writeBoardStats(&buf, stats, "csv"))
+	assert.Equal(t, "board,total,created_by_glue,open,done\nPROJ,120,80,45,75\n", buf.String())
+
+	buf.Reset()
+	require.NoError(t, writeBoardStats(&buf, stats, "json"))
+	var decoded []map[string]interface{}
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &decoded))
+	assert.Equal(t, "PROJ", decoded[0]["board"])
+	assert.Equal(t, float64(80), decoded[0]["created_by_glue"])
+
+	buf.Reset()
+	require.NoError(t, writeBoardStats(&buf, stats, "table"))
+	assert.Contains(t, buf.String(), "CREATED BY GLUE")
+	assert.Contains(t, buf.String(), "PROJ")
+}
@@ -0,0 +1,39 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/identity"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// configureReporter makes the JIRA client report new tickets as the JIRA
+// user of their GitHub issue's author if --attribute-reporter is set. Authors
+// are resolved like assignees: through the users mappings of the config file,
+// then by searching JIRA users.
+func configureReporter(ctx context.Context, cmd *cobra.Command, users config.UserMappings, githubClient *github.Client, jiraClient *jira.Client) error {
+	attribute, err := cmd.Flags().GetBool("attribute-reporter")
+	if err != nil {
+		return fmt.Errorf("failed to get attribute-reporter flag: %v", err)
+	}
+	if !attribute {
+		return nil
+	}
+
+	resolver := identity.NewResolver(users, githubClient, jiraClient)
+	jiraClient.SetReporterFunc(func(login string) *models.JiraUser {
+		user, err := resolver.JiraUser(ctx, login)
+		if err != nil {
+			logging.Warn("failed to resolve github user", "login", login, "error", err)
+		}
+		return user
+	})
+	return nil
+}
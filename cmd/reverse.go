@@ -0,0 +1,245 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// Markers fencing the part of a feature's '## Issues' section that is
+// regenerated from JIRA, so the rest of the description is left alone.
+const (
+	issuesStartMarker = "<!-- glue:issues:start -->"
+	issuesEndMarker   = "<!-- glue:issues:end -->"
+)
+
+// reverseSyncOptions selects what the reverse-sync pass copies from JIRA
+// tickets back to their GitHub issues.
+type reverseSyncOptions struct {
+	// MirrorLabels lists the JIRA labels and components mirrored as GitHub labels
+	MirrorLabels []string
+	// Users maps JIRA assignees to GitHub logins; assignees are synced if set
+	Users config.UserMappings
+}
+
+// enabled reports whether the reverse-sync pass has anything to do.
+func (o reverseSyncOptions) enabled() bool {
+	return len(o.MirrorLabels) > 0 || len(o.Users) > 0
+}
+
+// reverseSync copies triage decisions made in JIRA back to the mapped GitHub
+// issues. Issues already present in seen are skipped. Returns the number of
+// GitHub issues that were updated.
+func reverseSync(ctx context.Context, repository string, issues []models.GitHubIssue, seen map[int]bool, githubClient *github.Client, jiraClient *jira.Client, opts reverseSyncOptions) int {
+	updateCount := 0
+	for _, issue := range issues {
+		if stopStarting(ctx, jiraClient) {
+			break
+		}
+		jiraID := marker.GitHub.Key(issue.Title)
+		if jiraID == "" || seen[issue.Number] {
+			continue
+		}
+		seen[issue.Number] = true
+
+		log := logging.FromContext(logging.WithTraceID(ctx, "issue_number", issue.Number, "jira_ticket", jiraID))
+		issueJira := jiraClient.WithLogger(log)
+		issueGitHub := githubClient.WithLogger(log)
+
+		ticket, err := issueJira.GetTicket(jiraID)
+		if err != nil {
+			log.Error("failed to get jira ticket", "error", err)
+			continue
+		}
+
+		updated := false
+
+		if labels := labelsToMirror(ticket, issue.Labels, opts.MirrorLabels); len(labels) > 0 {
+			if err := issueGitHub.AddLabels(ctx, repository, issue.Number, labels...); err != nil {
+				log.Error("failed to mirror jira labels", "labels", labels, "error", err)
+			} else {
+				log.Info("mirrored jira labels to github", "labels", labels)
+				updated = true
+			}
+		}
+
+		if add, remove := assigneeChanges(ticket.Assignee, issue.Assignees, opts.Users); add != "" {
+			if err := issueGitHub.AddAssignees(ctx, repository, issue.Number, add); err != nil {
+				log.Error("failed to assign github issue", "assignee", add, "error", err)
+			} else {
+				log.Info("assigned github issue from jira", "assignee", add)
+				updated = true
+
+				if len(remove) > 0 {
+					if err := issueGitHub.RemoveAssignees(ctx, repository, issue.Number, remove...); err != nil {
+						log.Error("failed to remove previous github assignees", "assignees", remove, "error", err)
+					}
+				}
+			}
+		}
+
+		if updated {
+			updateCount++
+		}
+	}
+	return updateCount
+}
+
+// labelsToMirror returns the mirrored labels present on the JIRA ticket, as a
+// label or a component, that the GitHub issue doesn't have yet. Matching is
+// case-insensitive; the spelling from the mirror list is used for GitHub.
+func labelsToMirror(ticket models.JiraTicket, issueLabels []string, mirror []string) []string {
+	var labels []string
+	for _, name := range mirror {
+		name = strings.TrimSpace(name)
+		if name == "" || hasLabel(issueLabels, name) || hasLabel(labels, name) {
+			continue
+		}
+		if hasLabel(ticket.Labels, name) || hasLabel(ticket.Components, name) {
+			labels = append(labels, name)
+		}
+	}
+	return labels
+}
+
+// assigneeChanges determines how to mirror a JIRA assignee to GitHub. It returns
+// the GitHub login to assign, or an empty string if the assignee isn't mapped or
+// is already assigned. Previous assignees that are themselves mapped users are
+// returned for removal, so ownership moves with the JIRA ticket; unmapped
+// GitHub assignees are left alone. Unassigned tickets change nothing.
+func assigneeChanges(assignee *models.JiraUser, issueAssignees []string, users config.UserMappings) (string, []string) {
+	if assignee == nil || len(users) == 0 {
+		return "", nil
+	}
+
+	login := users.GitHubLogin(assignee.AccountID, assignee.Name, assignee.Email, assignee.DisplayName)
+	if login == "" || hasLabel(issueAssignees, login) {
+		return "", nil
+	}
+
+	var remove []string
+	for _, current := range issueAssignees {
+		for _, user := range users {
+			if strings.EqualFold(user.GitHub, current) {
+				remove = append(remove, current)
+				break
+			}
+		}
+	}
+	return login, remove
+}
+
+// syncIssuesSectionsFromJira regenerates the '## Issues' section of synced
+// feature issues from the links of their JIRA tickets, for teams that
+// restructure hierarchies in JIRA. Linked tickets are listed by the GitHub
+// issue among known synced to them, by their keys in syncState, which may be
+// nil, or their titles; tickets without one are left out. The
+// descriptions in issues are updated in place so the rest of the run sees
+// them. Issues already present in seen are skipped. Returns the number of
+// GitHub issues that were updated.
+func syncIssuesSectionsFromJira(ctx context.Context, repository, gitHubDomain string, issues, known []models.GitHubIssue, seen map[int]bool, syncState *state.Store, decisions map[int]rules.Decision, githubClient *github.Client, jiraClient *jira.Client) int {
+	jiraToGitHub := make(map[string]int)
+	for number, key := range buildGitHubToJiraMap(known, syncState) {
+		jiraToGitHub[key] = number
+	}
+
+	updateCount := 0
+	for i, issue := range issues {
+		if stopStarting(ctx, jiraClient) {
+			break
+		}
+		jiraID := marker.GitHub.Key(issue.Title)
+		if jiraID == "" || seen[issue.Number] || issueTypeFor(issue, decisions) != "feature" {
+			continue
+		}
+		seen[issue.Number] = true
+
+		log := logging.FromContext(logging.WithTraceID(ctx, "issue_number", issue.Number, "jira_ticket", jiraID))
+
+		links, err := jiraClient.WithLogger(log).GetIssueLinks(jiraID)
+		if err != nil {
+			log.Error("failed to get jira links", "error", err)
+			continue
+		}
+
+		var children []int
+		for key := range links {
+			number, ok := jiraToGitHub[key]
+			if !ok {
+				log.Debug("no github issue for linked jira ticket", "linked", key)
+				continue
+			}
+			if number != issue.Number {
+				children = append(children, number)
+			}
+		}
+		sort.Ints(children)
+
+		urls := make([]string, len(children))
+		for j, number := range children {
+			urls[j] = fmt.Sprintf("https://%s/%s/issues/%d", gitHubDomain, repository, number)
+		}
+
+		description := issuesSectionFromJira(issue.Description, urls)
+		if description == issue.Description {
+			continue
+		}
+		if err := githubClient.WithLogger(log).UpdateIssueBody(ctx, repository, issue.Number, description); err != nil {
+			log.Error("failed to update issues section", "error", err)
+			continue
+		}
+		log.Info("updated issues section from jira", "children", children)
+		issues[i].Description = description
+		updateCount++
+	}
+	return updateCount
+}
+
+// issuesSectionFromJira returns the description with its '## Issues' section
+// listing urls between the markers. Only the fenced part is replaced if the
+// description has one; otherwise an unfenced '## Issues' section is replaced
+// up to the next heading, and the section is appended to a description
+// without one, unless there is nothing to list.
+func issuesSectionFromJira(description string, urls []string) string {
+	var fenced strings.Builder
+	fenced.WriteString(issuesStartMarker + "\n")
+	for _, url := range urls {
+		fenced.WriteString("- " + url + "\n")
+	}
+	fenced.WriteString(issuesEndMarker)
+
+	start := strings.Index(description, issuesStartMarker)
+	end := strings.Index(description, issuesEndMarker)
+	if start != -1 && end > start {
+		return description[:start] + fenced.String() + description[end+len(issuesEndMarker):]
+	}
+
+	heading := strings.Index(description, "## Issues")
+	if heading == -1 {
+		if len(urls) == 0 {
+			return description
+		}
+		prefix := strings.TrimRight(description, "\n")
+		if prefix != "" {
+			prefix += "\n\n"
+		}
+		return prefix + "## Issues\n" + fenced.String() + "\n"
+	}
+
+	rest := description[heading+len("## Issues"):]
+	if next := strings.Index(rest, "## "); next != -1 {
+		return description[:heading] + "## Issues\n" + fenced.String() + "\n\n" + rest[next:]
+	}
+	return description[:heading] + "## Issues\n" + fenced.String() + "\n"
+}
@@ -0,0 +1,185 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLabelsToMirror(t *testing.T) {
+	ticket := models.JiraTicket{
+		Key:        "PROJ-1",
+		Labels:     []string{"needs-design", "backend"},
+		Components: []string{"Security"},
+	}
+
+	tests := []struct {
+		name        string
+		issueLabels []string
+		mirror      []string
+		expected    []string
+	}{
+		{
+			name:     "nothing configured",
+			expected: nil,
+		},
+		{
+			name:     "jira label is mirrored",
+			mirror:   []string{"needs-design", "blocked"},
+			expected: []string{"needs-design"},
+		},
+		{
+			name:     "component is mirrored",
+			mirror:   []string{"security"},
+			expected: []string{"security"},
+		},
+		{
+			name:        "label already on github issue",
+			issueLabels: []string{"story", "Needs-Design"},
+			mirror:      []string{"needs-design", "backend"},
+			expected:    []string{"backend"},
+		},
+		{
+			name:     "duplicates and blanks in mirror list",
+			mirror:   []string{" ", "backend", "BACKEND"},
+			expected: []string{"backend"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, labelsToMirror(ticket, tt.issueLabels, tt.mirror))
+		})
+	}
+}
+
+func TestReverseSyncOptionsEnabled(t *testing.T) {
+	assert.False(t, reverseSyncOptions{}.enabled())
+	assert.True(t, reverseSyncOptions{MirrorLabels: []string{"needs-design"}}.enabled())
+	assert.True(t, reverseSyncOptions{Users: config.UserMappings{{Jira: "jsmith", GitHub: "johnsmith"}}}.enabled())
+}
+
+func TestAssigneeChanges(t *testing.T) {
+	users := config.UserMappings{
+		{Jira: "jane.doe@example.com", GitHub: "janedoe"},
+		{Jira: "557058:f58131cb", GitHub: "octocat"},
+	}
+	jane := &models.JiraUser{Email: "Jane.Doe@example.com", DisplayName: "Jane Doe"}
+
+	tests := []struct {
+		name           string
+		assignee       *models.JiraUser
+		issueAssignees []string
+		users          config.UserMappings
+		expectedAdd    string
+		expectedRemove []string
+	}{
+		{
+			name:        "mapped assignee by email",
+			assignee:    jane,
+			users:       users,
+			expectedAdd: "janedoe",
+		},
+		{
+			name:        "mapped assignee by account id",
+			assignee:    &models.JiraUser{AccountID: "557058:f58131cb"},
+			users:       users,
+			expectedAdd: "octocat",
+		},
+		{
+			name:           "already assigned",
+			assignee:       jane,
+			issueAssignees: []string{"JaneDoe"},
+			users:          users,
+		},
+		{
+			name:           "previous mapped owner is replaced",
+			assignee:       jane,
+			issueAssignees: []string{"octocat", "contractor"},
+			users:          users,
+			expectedAdd:    "janedoe",
+			expectedRemove: []string{"octocat"},
+		},
+		{
+			name:     "unmapped assignee",
+			assignee: &models.JiraUser{Name: "jsmith"},
+			users:    users,
+		},
+		{
+			name:           "unassigned ticket",
+			issueAssignees: []string{"octocat"},
+			users:          users,
+		},
+		{
+			name:     "no mappings configured",
+			assignee: jane,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			add, remove := assigneeChanges(tt.assignee, tt.issueAssignees, tt.users)
+			assert.Equal(t, tt.expectedAdd, add)
+			assert.Equal(t, tt.expectedRemove, remove)
+		})
+	}
+}
+
+func TestIssuesSectionFromJira(t *testing.T) {
+	urls := []string{"https://github.com/org/repo/issues/2", "https://github.com/org/repo/issues/3"}
+	fenced := "<!-- glue:issues:start -->\n- https://github.com/org/repo/issues/2\n- https://github.com/org/repo/issues/3\n<!-- glue:issues:end -->"
+
+	tests := []struct {
+		name        string
+		description string
+		urls        []string
+		expected    string
+	}{
+		{
+			name:        "fenced part is replaced",
+			description: "Intro\n\n## Issues\nHand-written note\n<!-- glue:issues:start -->\n- https://github.com/org/repo/issues/9\n<!-- glue:issues:end -->\n\n## Notes\nKeep",
+			urls:        urls,
+			expected:    "Intro\n\n## Issues\nHand-written note\n" + fenced + "\n\n## Notes\nKeep",
+		},
+		{
+			name:        "unfenced section is replaced up to the next heading",
+			description: "Intro\n\n## Issues\n- https://github.com/org/repo/issues/9\n\n## Notes\nKeep",
+			urls:        urls,
+			expected:    "Intro\n\n## Issues\n" + fenced + "\n\n## Notes\nKeep",
+		},
+		{
+			name:        "unfenced section at the end",
+			description: "Intro\n\n## Issues\n- https://github.com/org/repo/issues/9\n",
+			urls:        urls,
+			expected:    "Intro\n\n## Issues\n" + fenced + "\n",
+		},
+		{
+			name:        "section is appended",
+			description: "Intro\n",
+			urls:        urls,
+			expected:    "Intro\n\n## Issues\n" + fenced + "\n",
+		},
+		{
+			name:        "nothing to list and no section",
+			description: "Intro",
+			expected:    "Intro",
+		},
+		{
+			name:        "fenced part is emptied",
+			description: "## Issues\n<!-- glue:issues:start -->\n- https://github.com/org/repo/issues/9\n<!-- glue:issues:end -->\n",
+			expected:    "## Issues\n<!-- glue:issues:start -->\n<!-- glue:issues:end -->\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			description := issuesSectionFromJira(tt.description, tt.urls)
+			assert.Equal(t, tt.expected, description)
+			assert.Equal(t, description, issuesSectionFromJira(description, tt.urls))
+		})
+	}
+
+	assert.Equal(t, []int{2, 3}, parseChildIssues(issuesSectionFromJira("Intro", urls), "github.com"))
+}
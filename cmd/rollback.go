@@ -0,0 +1,52 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/spf13/cobra"
+)
+
+// jiraRollbackCmd restores a JIRA ticket description from its latest snapshot.
+var jiraRollbackCmd = &cobra.Command{
+	Use:   "rollback <ticket>",
+	Short: "Restore the JIRA description that glue last overwrote",
+	Long: `Restore the description of a JIRA ticket from the snapshot glue saved before
+it last overwrote the description (see 'glue jira --sync-descriptions').
+
+Snapshots are stored as comments on the ticket. The current description is
+snapshotted again before it is replaced, so a rollback can itself be undone.
+
+Example:
+  glue jira rollback PROJ-123`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		project, _, _ := strings.Cut(args[0], "-")
+		if err := checkSafety(cmd, cfg.Safety, "", []string{project}); err != nil {
+			return err
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		takenAt, err := jiraClient.RestoreTicketDescription(args[0])
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(cmd.OutOrStdout(), "restored description of %s from snapshot taken at %s\n",
+			args[0], takenAt.Format(time.RFC3339))
+		return nil
+	},
+}
@@ -2,6 +2,10 @@
 package cmd
 
 import (
+	"os"
+
+	"github.com/danielolaszy/glue/internal/httpdebug"
+	"github.com/danielolaszy/glue/internal/logging"
 	"github.com/spf13/cobra"
 )
 
@@ -13,12 +17,33 @@ var rootCmd = &cobra.Command{
 	Long: `Glue is a CLI tool that synchronizes GitHub issues with project management tools
 like JIRA. It enables seamless integration between your GitHub repository
 and your preferred project management platform.`,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// The clients read these through the config, like GLUE_READ_ONLY
+		for flag, env := range map[string]string{"record": "GLUE_RECORD", "replay": "GLUE_REPLAY", "profile": "GLUE_PROFILE"} {
+			if value, _ := cmd.Flags().GetString(flag); value != "" {
+				if err := os.Setenv(env, value); err != nil {
+					return err
+				}
+			}
+		}
+		if debugHTTP, _ := cmd.Flags().GetBool("debug-http"); debugHTTP {
+			if err := os.Setenv(httpdebug.Env, "1"); err != nil {
+				return err
+			}
+		}
+		return applyConfigDefaults(cmd)
+	},
 }
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once.
 func Execute() error {
-	return rootCmd.Execute()
+	err := rootCmd.Execute()
+	// Failed runs are the ones the call statistics are most wanted for
+	if summaryErr := httpdebug.DefaultStats.WriteSummary(os.Stderr); summaryErr != nil {
+		logging.Warn("failed to write api call summary", "error", summaryErr)
+	}
+	return err
 }
 
 // init is called when the package is initialized. It sets up the command structure
@@ -26,6 +51,11 @@ func Execute() error {
 func init() {
 	// Add persistent flags that will be available to all commands
 	rootCmd.PersistentFlags().StringP("repository", "r", "", "GitHub repository name (e.g., 'username/repo')")
+	rootCmd.PersistentFlags().String("profile", "", "Config file profile to use, e.g. staging or prod (overrides GLUE_PROFILE)")
+	rootCmd.PersistentFlags().String("record", "", "Save sanitized copies of all GitHub and JIRA API requests and responses to this directory")
+	rootCmd.PersistentFlags().String("replay", "", "Answer GitHub and JIRA API requests from a directory written by --record instead of calling the APIs")
+	rootCmd.PersistentFlags().Bool("debug-http", false, "Log every GitHub and JIRA API call with its status, duration and rate limit headers, and summarize the calls per endpoint at the end")
+	rootCmd.PersistentFlags().Bool("detect-board", false, "Without --board, use the project of the ticket key the git branch starts with (e.g. PROJ-123-fix) or the repository's jira-KEY topics")
 
 	// Add the JIRA command
 	rootCmd.AddCommand(jiraCmd)
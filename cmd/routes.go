@@ -0,0 +1,85 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// fileURLPattern matches links to files or directories of a repository, e.g.
+// https://github.com/owner/repo/blob/main/services/api/main.go, and captures
+// the path.
+var fileURLPattern = regexp.MustCompile(`https?://[^/\s]+/[^/\s]+/[^/\s]+/(?:blob|tree)/[^/\s]+/([^\s#?)\]>"']+)`)
+
+// codeSpanPattern matches inline code without spaces, which is how issue
+// bodies usually mention files, e.g. `services/api/main.go`.
+var codeSpanPattern = regexp.MustCompile("`([^`\\s]+)`")
+
+// lineSuffixPattern matches line references after a path, e.g. ":42".
+var lineSuffixPattern = regexp.MustCompile(`:\d+(?::\d+)?$`)
+
+// referencedPaths returns the repository paths an issue body references, as
+// links to files or directories of a repository and as inline code
+// containing a slash.
+func referencedPaths(body string) []string {
+	seen := make(map[string]bool)
+	var paths []string
+	add := func(p string) {
+		p = lineSuffixPattern.ReplaceAllString(strings.TrimSuffix(p, "/"), "")
+		if p != "" && !seen[p] {
+			seen[p] = true
+			paths = append(paths, p)
+		}
+	}
+
+	for _, match := range fileURLPattern.FindAllStringSubmatch(body, -1) {
+		add(match[1])
+	}
+	for _, match := range codeSpanPattern.FindAllStringSubmatch(body, -1) {
+		if strings.Contains(match[1], "/") && !strings.Contains(match[1], "://") {
+			add(match[1])
+		}
+	}
+	return paths
+}
+
+// routeLabels returns the labels of the routes to any of the boards, which
+// glue fetches issues by besides the board labels.
+func routeLabels(routes []config.Route, boards []string) []string {
+	var labels []string
+	for _, route := range routes {
+		if hasLabel(boards, route.Board) {
+			labels = append(labels, route.Labels...)
+		}
+	}
+	return labels
+}
+
+// routedBoard returns the board of the first route matching an issue, or an
+// empty string if none does.
+func routedBoard(issue models.GitHubIssue, routes []config.Route) string {
+	if len(routes) == 0 {
+		return ""
+	}
+	paths := referencedPaths(issue.Description)
+	for _, route := range routes {
+		if route.Matches(issue.Labels, paths) {
+			return route.Board
+		}
+	}
+	return ""
+}
+
+// routesTo reports whether an issue is synced to board without a rules
+// decision: by the first route matching it or, if none does, by its board
+// labels. An issue matching a route to a board that isn't part of the run
+// isn't synced.
+func routesTo(issue models.GitHubIssue, board string, routes []config.Route, aliases config.BoardAliases) bool {
+	if routed := routedBoard(issue, routes); routed != "" {
+		return strings.EqualFold(routed, board)
+	}
+	return hasBoardLabel(issue.Labels, board, aliases)
+}
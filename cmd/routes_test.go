@@ -0,0 +1,78 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+var testRoutes = []config.Route{
+	{Board: "PAY", Labels: []string{"area:payments"}, Paths: []string{"services/payments"}},
+	{Board: "WEB", Paths: []string{"apps/*"}},
+}
+
+func TestReferencedPaths(t *testing.T) {
+	body := "Refunds fail in `services/payments/refund.go:42` and `make test`.\n" +
+		"See https://github.com/org/mono/blob/main/apps/web/src/checkout.ts#L10 and " +
+		"[the docs](https://github.com/org/mono/tree/main/docs/payments/).\n" +
+		"Not a path: `https://example.com/a/b`, `services/payments/refund.go` again."
+
+	assert.Equal(t, []string{
+		"apps/web/src/checkout.ts",
+		"docs/payments",
+		"services/payments/refund.go",
+	}, referencedPaths(body))
+	assert.Empty(t, referencedPaths("No files here"))
+}
+
+func TestRoutedBoard(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue models.GitHubIssue
+		want  string
+	}{
+		{"label", models.GitHubIssue{Labels: []string{"Area:Payments"}}, "PAY"},
+		{"path prefix", models.GitHubIssue{Description: "Broken: `services/payments/api.go`"}, "PAY"},
+		{"glob", models.GitHubIssue{Description: "Broken: `apps/web/index.ts`"}, "WEB"},
+		{"first route wins", models.GitHubIssue{Labels: []string{"area:payments"}, Description: "`apps/web/index.ts`"}, "PAY"},
+		{"sibling directory", models.GitHubIssue{Description: "`services/payments-v2/api.go`"}, ""},
+		{"none", models.GitHubIssue{Labels: []string{"PROJ"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, routedBoard(tt.issue, testRoutes))
+		})
+	}
+}
+
+func TestRoutesTo(t *testing.T) {
+	routed := models.GitHubIssue{Labels: []string{"PROJ", "area:payments"}}
+	assert.True(t, routesTo(routed, "PAY", testRoutes, nil))
+	assert.False(t, routesTo(routed, "PROJ", testRoutes, nil), "routes take precedence over board labels")
+	assert.True(t, routesTo(routed, "PROJ", nil, nil))
+
+	labeled := models.GitHubIssue{Labels: []string{"jira-project: PROJ"}}
+	assert.True(t, routesTo(labeled, "PROJ", testRoutes, nil))
+}
+
+func TestRouteLabels(t *testing.T) {
+	routes := append(testRoutes, config.Route{Board: "OPS", Labels: []string{"area:infra"}})
+	assert.Equal(t, []string{"area:payments", "area:infra"}, routeLabels(routes, []string{"pay", "OPS"}))
+	assert.Empty(t, routeLabels(routes, []string{"WEB"}))
+}
+
+func TestRouteByRulesWithRoutes(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{Number: 1, Labels: []string{"story", "PROJ", "area:payments"}},
+		{Number: 2, Labels: []string{"story", "PROJ"}},
+	}
+	issuesByBoard := map[string][]models.GitHubIssue{"PROJ": issues}
+	decisions := map[int]rules.Decision{2: {Boards: []string{"PAY"}}}
+
+	routed := routeByRules(issuesByBoard, []string{"PROJ", "PAY"}, decisions, testRoutes, nil)
+	assert.Empty(t, routed["PROJ"])
+	assert.Equal(t, issues, routed["PAY"])
+}
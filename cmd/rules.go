@@ -0,0 +1,100 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// loadRules loads the configured rules script. It returns nil if no script is
+// configured, or an error if the script cannot be read or loaded.
+func loadRules(cfg config.RulesConfig) (*rules.Engine, error) {
+	filename, src := "rules", cfg.Script
+	if src == "" {
+		if cfg.File == "" {
+			return nil, nil
+		}
+		content, err := os.ReadFile(cfg.File)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read rules script: %v", err)
+		}
+		filename, src = cfg.File, string(content)
+	}
+	return rules.Load(filename, src)
+}
+
+// evaluateRules runs the rules engine for every issue. An issue whose rules
+// evaluation fails is skipped, so it isn't synced with a mapping the script
+// didn't intend. Returns nil if engine is nil.
+func evaluateRules(engine *rules.Engine, issuesByBoard map[string][]models.GitHubIssue) map[int]rules.Decision {
+	if engine == nil {
+		return nil
+	}
+
+	decisions := make(map[int]rules.Decision)
+	for _, issues := range issuesByBoard {
+		for _, issue := range issues {
+			if _, ok := decisions[issue.Number]; ok {
+				continue
+			}
+			decision, err := engine.Decide(issue)
+			if err != nil {
+				logging.Error("rules evaluation failed, skipping issue",
+					"issue_number", issue.Number,
+					"error", err)
+				decision = rules.Decision{Skip: true}
+			}
+			decisions[issue.Number] = decision
+		}
+	}
+	return decisions
+}
+
+// routeByRules regroups issues by board. Issues whose decision lists boards are
+// routed to those of them that are part of this run; other issues keep their
+// routing by routes and labels.
+func routeByRules(issuesByBoard map[string][]models.GitHubIssue, boards []string, decisions map[int]rules.Decision, routes []config.Route, aliases config.BoardAliases) map[string][]models.GitHubIssue {
+	if decisions == nil {
+		return issuesByBoard
+	}
+
+	var all []models.GitHubIssue
+	seen := make(map[int]bool)
+	for _, board := range boards {
+		for _, issue := range issuesByBoard[board] {
+			if !seen[issue.Number] {
+				seen[issue.Number] = true
+				all = append(all, issue)
+			}
+		}
+	}
+
+	routed := make(map[string][]models.GitHubIssue)
+	for _, issue := range all {
+		decision := decisions[issue.Number]
+		for _, board := range boards {
+			match := routesTo(issue, board, routes, aliases)
+			if decision.Boards != nil {
+				match = hasLabel(decision.Boards, board)
+			}
+			if match {
+				routed[board] = append(routed[board], issue)
+			}
+		}
+	}
+	return routed
+}
+
+// issueTypeFor returns the issue type of an issue, preferring the rules decision
+// over the native type and labels.
+func issueTypeFor(issue models.GitHubIssue, decisions map[int]rules.Decision) string {
+	if decision, ok := decisions[issue.Number]; ok && decision.Type != "" {
+		return decision.Type
+	}
+	return issueTypeOf(issue)
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testRulesScript = `
+def decide(issue):
+    if issue["number"] == 2:
+        return {"boards": ["OPS"], "type": "feature"}
+    if issue["number"] == 3:
+        return {"skip": True}
+    if issue["number"] == 4:
+        fail("broken issue")
+    return None
+`
+
+func TestLoadRules(t *testing.T) {
+	engine, err := loadRules(config.RulesConfig{})
+	assert.NoError(t, err)
+	assert.Nil(t, engine)
+
+	engine, err = loadRules(config.RulesConfig{Script: testRulesScript})
+	assert.NoError(t, err)
+	assert.NotNil(t, engine)
+
+	path := filepath.Join(t.TempDir(), "rules.star")
+	require.NoError(t, os.WriteFile(path, []byte(testRulesScript), 0o600))
+	engine, err = loadRules(config.RulesConfig{File: path})
+	assert.NoError(t, err)
+	assert.NotNil(t, engine)
+
+	_, err = loadRules(config.RulesConfig{File: filepath.Join(t.TempDir(), "missing.star")})
+	assert.Error(t, err)
+}
+
+func TestEvaluateAndRouteRules(t *testing.T) {
+	engine, err := loadRules(config.RulesConfig{Script: testRulesScript})
+	require.NoError(t, err)
+
+	issues := []models.GitHubIssue{
+		{Number: 1, Labels: []string{"story", "PROJ"}},
+		{Number: 2, Labels: []string{"story", "PROJ"}},
+		{Number: 3, Labels: []string{"story", "PROJ", "OPS"}},
+		{Number: 4, Labels: []string{"story", "OPS"}},
+	}
+	issuesByBoard := map[string][]models.GitHubIssue{
+		"PROJ": {issues[0], issues[1], issues[2]},
+		"OPS":  {issues[2], issues[3]},
+	}
+
+	decisions := evaluateRules(engine, issuesByBoard)
+	assert.Equal(t, rules.Decision{}, decisions[1])
+	assert.Equal(t, rules.Decision{Boards: []string{"OPS"}, Type: "feature"}, decisions[2])
+	assert.True(t, decisions[3].Skip)
+	assert.True(t, decisions[4].Skip, "failed evaluations skip the issue")
+
+	routed := routeByRules(issuesByBoard, []string{"PROJ", "OPS"}, decisions, nil, nil)
+	numbers := func(issues []models.GitHubIssue) []int {
+		var n []int
+		for _, issue := range issues {
+			n = append(n, issue.Number)
+		}
+		return n
+	}
+	assert.Equal(t, []int{1, 3}, numbers(routed["PROJ"]))
+	assert.Equal(t, []int{2, 3, 4}, numbers(routed["OPS"]))
+
+	assert.Equal(t, "story", issueTypeFor(issues[0], decisions))
+	assert.Equal(t, "feature", issueTypeFor(issues[1], decisions))
+}
+
+func TestRulesDisabled(t *testing.T) {
+	issuesByBoard := map[string][]models.GitHubIssue{"PROJ": {{Number: 1, Labels: []string{"feature"}}}}
+
+	decisions := evaluateRules(nil, issuesByBoard)
+	assert.Nil(t, decisions)
+	assert.Equal(t, issuesByBoard, routeByRules(issuesByBoard, []string{"PROJ"}, decisions, nil, nil))
+	assert.Equal(t, "feature", issueTypeFor(issuesByBoard["PROJ"][0], decisions))
+}
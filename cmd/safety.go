@@ -0,0 +1,30 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/spf13/cobra"
+)
+
+// checkSafety refuses to proceed when the safety config doesn't permit
+// changing the repository or one of the boards, unless --force is set.
+func checkSafety(cmd *cobra.Command, safety config.SafetyConfig, repository string, boards []string) error {
+	err := safety.Check(repository, boards)
+	if err == nil {
+		return nil
+	}
+
+	force, flagErr := cmd.Flags().GetBool("force")
+	if flagErr != nil {
+		return fmt.Errorf("failed to get force flag: %v", flagErr)
+	}
+	if force {
+		slog.Warn("ignoring safety config because --force is set", "error", err)
+		return nil
+	}
+
+	return fmt.Errorf("%v; use --force to run anyway", err)
+}
@@ -0,0 +1,28 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckSafety(t *testing.T) {
+	safety := config.SafetyConfig{AllowRepositories: []string{"myorg/*"}, DenyProjects: []string{"PROD"}}
+
+	newCmd := func(force bool) *cobra.Command {
+		cmd := &cobra.Command{}
+		cmd.Flags().Bool("force", false, "")
+		if force {
+			require.NoError(t, cmd.Flags().Set("force", "true"))
+		}
+		return cmd
+	}
+
+	assert.NoError(t, checkSafety(newCmd(false), safety, "myorg/app", []string{"PROJ"}))
+	assert.EqualError(t, checkSafety(newCmd(false), safety, "other/app", []string{"PROD"}),
+		"safety config doesn't permit changing repository other/app, jira project PROD; use --force to run anyway")
+	assert.NoError(t, checkSafety(newCmd(true), safety, "other/app", []string{"PROD"}))
+}
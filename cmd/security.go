@@ -0,0 +1,216 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// severityOrder ranks alert severities, most severe first.
+var severityOrder = map[string]int{"critical": 0, "high": 1, "medium": 2, "low": 3}
+
+// jiraSecurityCmd creates JIRA tickets for open GitHub security alerts.
+var jiraSecurityCmd = &cobra.Command{
+	Use:   "security",
+	Short: "Create JIRA tickets for open Dependabot and code scanning alerts",
+	Long: `Create a JIRA ticket for every open Dependabot and code scanning alert of a
+repository that has none yet, for teams tracking vulnerabilities in JIRA.
+
+Tickets carry the 'glue-security' label and a label identifying their alert,
+e.g. 'github-alert:owner/repo:dependabot:12', so each alert gets one ticket
+however often the command runs. The alert severity sets the ticket priority:
+critical, high, medium and low map to Highest, High, Medium and Low unless
+security.priorities in the config file says otherwise. Tickets are Stories
+unless security.issue_type names another issue type.
+
+The token needs access to the repository's security alerts. A kind of alert
+that can't be listed, e.g. because code scanning isn't set up, is skipped
+with a warning.
+
+Example:
+  glue jira security -r owner/repo -b SEC
+  glue jira security -r owner/repo -b SEC --code-scanning=false --dry-run`,
+	PreRunE: validateFlags(flagRules{Repository: true, Boards: true}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		board, err := cmd.Flags().GetString("board")
+		if err != nil {
+			return err
+		}
+
+		dependabot, err := cmd.Flags().GetBool("dependabot")
+		if err != nil {
+			return err
+		}
+
+		codeScanning, err := cmd.Flags().GetBool("code-scanning")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		boards, err := resolveBoards(jiraClient, []string{board})
+		if err != nil {
+			return err
+		}
+		board = boards[0]
+
+		if err := checkSafety(cmd, cfg.Safety, repository, boards); err != nil {
+			return err
+		}
+
+		var alerts []models.SecurityAlert
+		if dependabot {
+			found, err := githubClient.GetDependabotAlerts(ctx, repository)
+			if err != nil {
+				logging.Warn("skipping dependabot alerts", "error", err)
+			}
+			alerts = append(alerts, found...)
+		}
+		if codeScanning {
+			found, err := githubClient.GetCodeScanningAlerts(ctx, repository)
+			if err != nil {
+				logging.Warn("skipping code scanning alerts", "error", err)
+			}
+			alerts = append(alerts, found...)
+		}
+		sortAlerts(alerts)
+
+		existing, err := jiraClient.GetAlertTickets(board)
+		if err != nil {
+			return fmt.Errorf("failed to find existing alert tickets: %v", err)
+		}
+		pending := pendingAlerts(repository, alerts, existing)
+
+		issueType := cfg.Security.IssueType
+		if issueType == "" {
+			issueType = "Story"
+		}
+		issueTypeID := ""
+		if !dryRun && len(pending) > 0 {
+			issueTypeID, err = jiraClient.GetIssueTypeID(board, issueType)
+			if err != nil {
+				return fmt.Errorf("failed to get issue type %s of %s: %v", issueType, board, err)
+			}
+		}
+
+		out := cmd.OutOrStdout()
+		created, failed := 0, 0
+		for _, alert := range pending {
+			priority := cfg.Security.Priority(alert.Severity)
+			if dryRun {
+				fmt.Fprintf(out, "would create ticket for %s\n", describeAlert(alert, priority))
+				continue
+			}
+
+			key, err := jiraClient.CreateAlertTicket(board, repository, alert, issueTypeID, priority)
+			if err != nil {
+				logging.Error("failed to create ticket for security alert",
+					"alert", alert.Kind,
+					"alert_number", alert.Number,
+					"error", err)
+				failed++
+				continue
+			}
+			fmt.Fprintf(out, "created %s for %s\n", key, describeAlert(alert, priority))
+			created++
+		}
+
+		fmt.Fprintf(out, "\n%d open alerts, %d already tracked, %d tickets created\n",
+			len(alerts), len(alerts)-len(pending), created)
+		if failed > 0 {
+			return fmt.Errorf("failed to create tickets for %d alerts", failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraSecurityCmd)
+	jiraSecurityCmd.Flags().StringP("board", "b", "", "JIRA project board to create the tickets on")
+	jiraSecurityCmd.Flags().Bool("dependabot", true, "Create tickets for Dependabot alerts")
+	jiraSecurityCmd.Flags().Bool("code-scanning", true, "Create tickets for code scanning alerts")
+	jiraSecurityCmd.Flags().Bool("dry-run", false, "Print the tickets that would be created without creating them")
+}
+
+// sortAlerts orders alerts by severity, most severe first, then by kind and
+// number, so the most pressing tickets are created first.
+func sortAlerts(alerts []models.SecurityAlert) {
+	rank := func(severity string) int {
+		if r, ok := severityOrder[severity]; ok {
+			return r
+		}
+		return len(severityOrder)
+	}
+	sort.SliceStable(alerts, func(i, j int) bool {
+		a, b := alerts[i], alerts[j]
+		if rank(a.Severity) != rank(b.Severity) {
+			return rank(a.Severity) < rank(b.Severity)
+		}
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		return a.Number < b.Number
+	})
+}
+
+// pendingAlerts returns the alerts without a ticket among existing, which
+// maps alert labels to ticket keys.
+func pendingAlerts(repository string, alerts []models.SecurityAlert, existing map[string]string) []models.SecurityAlert {
+	var pending []models.SecurityAlert
+	for _, alert := range alerts {
+		if key, ok := existing[jira.AlertLabel(repository, alert)]; ok {
+			logging.Debug("security alert already tracked",
+				"alert", alert.Kind,
+				"alert_number", alert.Number,
+				"jira_ticket", key)
+			continue
+		}
+		pending = append(pending, alert)
+	}
+	return pending
+}
+
+// describeAlert names an alert and the priority of its ticket.
+func describeAlert(alert models.SecurityAlert, priority string) string {
+	if priority == "" {
+		priority = "default"
+	}
+	return fmt.Sprintf("%s alert #%d (%s, priority %s): %s", alert.Kind, alert.Number, alert.Severity, priority, alert.Summary)
+}
@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPendingAlerts(t *testing.T) {
+	alerts := []models.SecurityAlert{
+		{Kind: models.AlertCodeScanning, Number: 3, Severity: "medium"},
+		{Kind: models.AlertDependabot, Number: 12, Severity: "high"},
+		{Kind: models.AlertDependabot, Number: 4, Severity: "critical"},
+		{Kind: models.AlertDependabot, Number: 7, Severity: "high"},
+	}
+	sortAlerts(alerts)
+	assert.Equal(t, []int{4, 7, 12, 3}, alertNumbers(alerts))
+
+	existing := map[string]string{"github-alert:owner/repo:dependabot:7": "SEC-2"}
+	pending := pendingAlerts("Owner/Repo", alerts, existing)
+	assert.Equal(t, []int{4, 12, 3}, alertNumbers(pending))
+}
+
+func TestDescribeAlert(t *testing.T) {
+	alert := models.SecurityAlert{Kind: models.AlertDependabot, Number: 12, Severity: "high", Summary: "Prototype pollution"}
+	assert.Equal(t, "dependabot alert #12 (high, priority High): Prototype pollution", describeAlert(alert, "High"))
+	assert.Equal(t, "dependabot alert #12 (high, priority default): Prototype pollution", describeAlert(alert, ""))
+}
+
+func alertNumbers(alerts []models.SecurityAlert) []int {
+	numbers := make([]int, len(alerts))
+	for i, alert := range alerts {
+		numbers[i] = alert.Number
+	}
+	return numbers
+}
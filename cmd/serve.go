@@ -0,0 +1,556 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/internal/lock"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/rules"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/internal/statuscache"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// webhookQueueSize is the number of deliveries waiting to be synced before
+// further ones are refused with 503 Service Unavailable, which GitHub shows
+// as failed deliveries that can be redelivered.
+const webhookQueueSize = 100
+
+// shutdownTimeout bounds how long 'glue serve' waits for requests in flight
+// when it is stopped.
+const shutdownTimeout = 10 * time.Second
+
+// drainTimeout bounds how long 'glue serve' keeps syncing the queued
+// deliveries once it is stopped. Deliveries not synced by then are left for
+// the next 'glue jira' run.
+const drainTimeout = time.Minute
+
+// webhookActions are the actions of issues events 'glue serve' syncs.
+var webhookActions = map[string]bool{
+	"opened":    true,
+	"edited":    true,
+	"reopened":  true,
+	"labeled":   true,
+	"unlabeled": true,
+	"closed":    true,
+}
+
+// serveCmd syncs issues to JIRA as GitHub reports their changes by webhook.
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Sync issues to JIRA as GitHub reports their changes by webhook",
+	Long: `Start an HTTP server receiving GitHub webhooks, and sync each changed issue
+right away instead of waiting for the next 'glue jira' run.
+
+Point a repository or organization webhook at http://HOST:8080/webhook with
+content type application/json, the secret in GITHUB_WEBHOOK_SECRET and the
+Issues event. Deliveries not signed with the secret are refused.
+
+When an issue is opened, edited, reopened, labeled or unlabeled, its ticket is
+created on each board it is routed to, and the parent-child links of the issue
+and of the features listing it are updated. When an issue is closed, its
+ticket is closed, unless it was closed within --close-grace-period. Deliveries
+are synced one at a time; if JIRA is unavailable or in a maintenance window,
+or another glue run holds the repository lock, the change is left for the
+next 'glue jira' run, so keep running it on a schedule.
+
+Without --board, the boards are discovered from each repository's
+'jira-project: KEY' labels. With --repository, deliveries for other
+repositories are ignored. /healthz answers 200 OK for health checks.
+
+Example:
+  GITHUB_WEBHOOK_SECRET=... glue serve -b PROJ --addr :8080`,
+	PreRunE: validateFlags(flagRules{}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		secret := os.Getenv(github.WebhookSecretEnv)
+		if secret == "" {
+			return fmt.Errorf("%s is required to verify webhook deliveries", github.WebhookSecretEnv)
+		}
+
+		addr, err := cmd.Flags().GetString("addr")
+		if err != nil {
+			return err
+		}
+
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		boards, err := cmd.Flags().GetStringArray("board")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		if len(boards) > 0 {
+			boards, err = resolveBoards(jiraClient, boards)
+			if err != nil {
+				return err
+			}
+		}
+
+		closeGracePeriod, err := cmd.Flags().GetDuration("close-grace-period")
+		if err != nil {
+			return err
+		}
+
+		noStatusCache, err := cmd.Flags().GetBool("no-status-cache")
+		if err != nil {
+			return err
+		}
+
+		noSyncState, err := cmd.Flags().GetBool("no-sync-state")
+		if err != nil {
+			return err
+		}
+
+		engine, err := loadRules(cfg.Rules)
+		if err != nil {
+			return err
+		}
+
+		syncer := &webhookSyncer{
+			cfg:              cfg,
+			githubClient:     githubClient,
+			jiraClient:       jiraClient,
+			engine:           engine,
+			boards:           boards,
+			closeGracePeriod: closeGracePeriod,
+			statusCache:      !noStatusCache,
+			syncState:        !noSyncState,
+		}
+		return serveWebhooks(cmd.Context(), addr, webhookHandler(secret, repository, syncer))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+	serveCmd.Flags().String("addr", ":8080", "Address to listen on for webhook deliveries")
+	serveCmd.Flags().StringArrayP("board", "b", []string{}, "JIRA project board(s) to sync with (can be specified multiple times)")
+	serveCmd.Flags().Duration("close-grace-period", 0, "Leave the JIRA tickets of issues closed less than this long ago to the next 'glue jira' run (e.g. 15m), so issues reopened quickly don't close their tickets")
+	serveCmd.Flags().Bool("no-status-cache", false, "Check the JIRA status of every closed issue's ticket, including those recorded as done by earlier runs")
+	serveCmd.Flags().Bool("no-sync-state", false, "Rely on issue title prefixes alone, ignoring the sync state recorded by earlier runs")
+}
+
+// serveWebhooks serves deliveries with handler on addr until ctx is done or
+// the process is interrupted, then waits up to drainTimeout for the queued
+// deliveries to be synced.
+func serveWebhooks(ctx context.Context, addr string, handler *webhookQueue) error {
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Stopping the server doesn't cancel the syncs, so the queued deliveries
+	// are drained; only running out of drainTimeout does
+	syncCtx, cancelSync := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelSync()
+
+	mux := http.NewServeMux()
+	mux.Handle("/webhook", handler)
+	mux.HandleFunc("/healthz", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	server := &http.Server{Addr: addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler.run(syncCtx)
+	}()
+	shutDown := make(chan struct{})
+	go func() {
+		defer close(shutDown)
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := server.Shutdown(shutdownCtx); err != nil {
+			logging.Warn("failed to shut down webhook server", "error", err)
+		}
+	}()
+
+	logging.Info("listening for github webhooks", "addr", addr)
+	err := server.ListenAndServe()
+	stop()
+	// ListenAndServe returns as soon as shutting down starts; requests in
+	// flight may still be queueing deliveries until Shutdown returns
+	<-shutDown
+	handler.close()
+	drain := time.AfterFunc(drainTimeout, cancelSync)
+	<-done
+	drain.Stop()
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// webhookQueue accepts webhook deliveries and syncs their issues one at a
+// time, so that deliveries aren't held up by the sync and don't race each
+// other.
+type webhookQueue struct {
+	secret     string
+	repository string
+	events     chan github.IssueEvent
+	sync       func(ctx context.Context, event github.IssueEvent)
+
+	// mu guards closed, so no delivery is queued once events is closed
+	mu     sync.Mutex
+	closed bool
+}
+
+// webhookHandler returns the handler of deliveries signed with secret,
+// syncing them with syncer. A non-empty repository ignores the deliveries
+// for other repositories.
+func webhookHandler(secret, repository string, syncer *webhookSyncer) *webhookQueue {
+	return &webhookQueue{
+		secret:     secret,
+		repository: repository,
+		events:     make(chan github.IssueEvent, webhookQueueSize),
+		sync:       syncer.sync,
+	}
+}
+
+// ServeHTTP implements http.Handler. Deliveries are answered 202 Accepted
+// once queued, and 204 No Content if they are ignored.
+func (q *webhookQueue) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	event, err := github.ParseIssueEvent(r, q.secret)
+	switch {
+	case errors.Is(err, github.ErrInvalidSignature):
+		logging.Warn("refusing webhook delivery", "remote_addr", r.RemoteAddr, "error", err)
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+		return
+	case errors.Is(err, github.ErrNotIssueEvent):
+		w.WriteHeader(http.StatusNoContent)
+		return
+	case err != nil:
+		logging.Warn("refusing webhook delivery", "error", err)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	if !webhookActions[event.Action] || q.repository != "" && !strings.EqualFold(event.Repository, q.repository) {
+		logging.Debug("ignoring webhook delivery",
+			"delivery", event.Delivery,
+			"repository", event.Repository,
+			"action", event.Action)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if q.closed {
+		http.Error(w, "shutting down", http.StatusServiceUnavailable)
+		return
+	}
+	select {
+	case q.events <- event:
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		logging.Warn("webhook queue is full, refusing delivery",
+			"delivery", event.Delivery,
+			"queue_size", cap(q.events))
+		http.Error(w, "too many deliveries waiting", http.StatusServiceUnavailable)
+	}
+}
+
+// run syncs the queued deliveries until the queue is closed.
+func (q *webhookQueue) run(ctx context.Context) {
+	for event := range q.events {
+		q.sync(ctx, event)
+	}
+}
+
+// close stops accepting deliveries, which are refused with 503 Service
+// Unavailable from then on; run returns once the queued ones are synced.
+func (q *webhookQueue) close() {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if !q.closed {
+		q.closed = true
+		close(q.events)
+	}
+}
+
+// webhookSyncer syncs the issues of webhook deliveries to JIRA with the same
+// steps as 'glue jira', limited to the changed issue.
+type webhookSyncer struct {
+	cfg          *config.Config
+	githubClient *github.Client
+	jiraClient   *jira.Client
+	engine       *rules.Engine
+	// boards are the boards to sync with; empty discovers them from each
+	// repository's labels
+	boards []string
+	// closeGracePeriod leaves the tickets of issues closed less than this long
+	// ago to the next 'glue jira' run
+	closeGracePeriod time.Duration
+	// statusCache skips closing tickets recorded as done in the repository's
+	// status cache, and records those it closes
+	statusCache bool
+	// syncState consults and updates the repository's sync state, like
+	// 'glue jira' does
+	syncState bool
+}
+
+// sync applies an issues event to JIRA. Failures are logged, since the next
+// 'glue jira' run catches up on them.
+func (s *webhookSyncer) sync(ctx context.Context, event github.IssueEvent) {
+	ctx = logging.WithTraceID(ctx, "delivery", event.Delivery, "repository", event.Repository, "issue_number", event.Issue.Number)
+	log := logging.FromContext(ctx)
+	log.Info("syncing webhook delivery", "action", event.Action)
+
+	if stopStarting(ctx, s.jiraClient) {
+		log.Warn("not syncing issue, jira is unavailable or serve is stopping; the next 'glue jira' run syncs it")
+		return
+	}
+
+	repoLock, err := lock.Acquire(lock.DefaultDir(), event.Repository, lock.DefaultStaleAfter)
+	if err != nil {
+		log.Warn("not syncing issue, another run holds the repository lock; the next 'glue jira' run syncs it", "error", err)
+		return
+	}
+	defer func() {
+		if err := repoLock.Release(); err != nil {
+			log.Warn("failed to release repository lock", "error", err)
+		}
+	}()
+
+	// Each delivery is a run of its own; what earlier ones fetched may have
+	// changed since
+	s.githubClient.ForgetResponses()
+	s.jiraClient.ForgetResponses()
+
+	var syncState *state.Store
+	if s.syncState {
+		syncState = trackSyncState(s.githubClient, event.Repository)
+		defer saveSyncState(syncState)
+		// The delivered issue isn't fetched, so it's restored here
+		event.Issue = restoreIssueKey(syncState, event.Issue)
+	}
+
+	if err := isolateIssue(ctx, event.Issue.Number, func() error {
+		if event.Action == "closed" {
+			return s.closeTicket(ctx, event)
+		}
+		return s.syncIssue(ctx, event, syncState)
+	}); err != nil {
+		log.Error("failed to sync webhook delivery", "action", event.Action, "error", err)
+	}
+}
+
+// closeTicket closes the ticket of a closed issue, like 'glue jira' does:
+// issues in the close grace period are left for a later run, and tickets
+// recorded as done in the status cache aren't checked again.
+func (s *webhookSyncer) closeTicket(ctx context.Context, event github.IssueEvent) error {
+	log := logging.FromContext(ctx)
+	key := marker.GitHub.Key(event.Issue.Title)
+	if key == "" {
+		return nil
+	}
+	if err := s.cfg.Safety.Check(event.Repository, []string{projectOfKey(key)}); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if inCloseGracePeriod(event.Issue, s.closeGracePeriod, now) {
+		log.Info("leaving recently closed issue for the next 'glue jira' run",
+			"jira_ticket", key,
+			"grace_period", s.closeGracePeriod)
+		return nil
+	}
+
+	var cache *statuscache.Cache
+	if s.statusCache {
+		var err error
+		cache, err = statuscache.Load(statuscache.DefaultDir(), event.Repository)
+		if err != nil {
+			log.Warn("replacing unreadable status cache", "error", err)
+			cache = statuscache.New(statuscache.DefaultDir(), event.Repository)
+		}
+		if cache.IsDone(key) {
+			log.Debug("skipped ticket recorded as done", "jira_ticket", key)
+			return nil
+		}
+	}
+
+	closed, err := closeIssueTicket(ctx, event.Repository, event.Issue, key, s.githubClient, s.jiraClient)
+	if err != nil {
+		return err
+	}
+	if closed {
+		log.Info("closed jira ticket", "jira_ticket", key)
+	}
+	if cache != nil {
+		cache.MarkDone(key, now)
+		if err := cache.Save(); err != nil {
+			log.Warn("failed to save status cache", "error", err)
+		}
+	}
+	return nil
+}
+
+// syncIssue creates the tickets of an open issue on the boards it is routed
+// to, and updates the parent-child links of the issue and of the features
+// listing it. Created tickets are recorded in syncState, which may be nil.
+func (s *webhookSyncer) syncIssue(ctx context.Context, event github.IssueEvent, syncState *state.Store) error {
+	if event.Issue.State != "open" {
+		return nil
+	}
+
+	boards := s.boards
+	if len(boards) == 0 {
+		var err error
+		boards, err = discoverBoards(ctx, s.githubClient, s.jiraClient, event.Repository, s.cfg.BoardAliases)
+		if err != nil {
+			return err
+		}
+	}
+
+	var targets []string
+	for _, board := range boards {
+		if routesTo(event.Issue, board, s.cfg.Routes, s.cfg.BoardAliases) {
+			targets = append(targets, board)
+		}
+	}
+	if len(targets) == 0 {
+		logging.FromContext(ctx).Debug("issue isn't routed to any board", "boards", boards)
+		return nil
+	}
+	if err := s.cfg.Safety.Check(event.Repository, targets); err != nil {
+		return err
+	}
+
+	related, err := s.relatedIssues(ctx, event.Repository, event.Issue)
+	if err != nil {
+		return err
+	}
+	applyIssueTypes(ctx, s.githubClient, event.Repository, related)
+	issuesByBoard := skipLockedIssues(groupIssuesByBoard(related, targets, s.cfg.Routes, s.cfg.BoardAliases))
+	decisions := applyFrontMatter(evaluateRules(s.engine, issuesByBoard), parseFrontMatter(issuesByBoard), targets)
+	issuesByBoard = routeByRules(issuesByBoard, targets, decisions, s.cfg.Routes, s.cfg.BoardAliases)
+
+	hooks := &syncHooks{repository: event.Repository, config: s.cfg.Hooks, syncState: syncState}
+	budget := newLinkBudget(s.cfg.Jira)
+	for _, board := range targets {
+		issues := issuesByBoard[board]
+		i := indexOfIssue(issues, event.Issue.Number)
+		if i == -1 {
+			continue
+		}
+
+		if !issueSynced(syncState, issues[i]) {
+			created, err := processBoard(ctx, event.Repository, board, []models.GitHubIssue{issues[i]}, s.githubClient, s.jiraClient, hooks, syncState, decisions, budget)
+			if err != nil {
+				return err
+			}
+			if created == 0 {
+				continue
+			}
+			// The title now carries the key, which links to the ticket
+			if issues[i], err = s.githubClient.GetIssue(ctx, event.Repository, event.Issue.Number); err != nil {
+				return fmt.Errorf("failed to fetch github issue: %v", err)
+			}
+		}
+
+		scope := hierarchyScope(issues, event.Issue.Number, s.cfg.GitHub.Domain, decisions)
+		linkFeatures(ctx, s.jiraClient, s.cfg, board, scope, buildGitHubToJiraMap(issues, syncState), decisions, budget)
+	}
+	return nil
+}
+
+// relatedIssues fetches the issues whose parent-child links a change to issue
+// can affect: the issue, the features listing it in their '## Issues'
+// section, found by their references to it, and the issues listed by any of
+// them. A child that can't be fetched fails the sync, since its link would
+// otherwise be taken for obsolete and removed.
+func (s *webhookSyncer) relatedIssues(ctx context.Context, repository string, issue models.GitHubIssue) ([]models.GitHubIssue, error) {
+	referencing, err := s.githubClient.GetReferencingIssues(ctx, repository, issue.Number)
+	if err != nil {
+		return nil, fmt.Errorf("failed to find the features listing the github issue: %v", err)
+	}
+
+	related := []models.GitHubIssue{issue}
+	seen := map[int]bool{issue.Number: true}
+	for _, candidate := range referencing {
+		if !seen[candidate.Number] && containsInt(parseChildIssues(candidate.Description, s.cfg.GitHub.Domain), issue.Number) {
+			seen[candidate.Number] = true
+			related = append(related, candidate)
+		}
+	}
+
+	parents := len(related)
+	for _, parent := range related[:parents] {
+		for _, number := range parseChildIssues(parent.Description, s.cfg.GitHub.Domain) {
+			if seen[number] {
+				continue
+			}
+			seen[number] = true
+			child, err := s.githubClient.GetIssue(ctx, repository, number)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch github issue #%d: %v", number, err)
+			}
+			related = append(related, child)
+		}
+	}
+	return related, nil
+}
+
+// indexOfIssue returns the index of the issue with number in issues, or -1.
+func indexOfIssue(issues []models.GitHubIssue, number int) int {
+	for i, issue := range issues {
+		if issue.Number == number {
+			return i
+		}
+	}
+	return -1
+}
+
+// hierarchyScope returns the issues whose parent-child links a change to the
+// issue with number can affect: the features that are the issue or list it in
+// their '## Issues' section, and every other issue, which may be their child.
+func hierarchyScope(issues []models.GitHubIssue, number int, gitHubDomain string, decisions map[int]rules.Decision) []models.GitHubIssue {
+	scope := make([]models.GitHubIssue, 0, len(issues))
+	for _, issue := range issues {
+		if issueTypeFor(issue, decisions) == "feature" && issue.Number != number &&
+			!containsInt(parseChildIssues(issue.Description, gitHubDomain), number) {
+			continue
+		}
+		scope = append(scope, issue)
+	}
+	return scope
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// signedDelivery returns an issues delivery of action for repository, signed
+// with secret.
+func signedDelivery(action, repository, secret string) *http.Request {
+	payload := fmt.Sprintf(`{"action": %q, "issue": {"number": 7, "state": "open"}, "repository": {"full_name": %q}}`, action, repository)
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-GitHub-Event", "issues")
+	r.Header.Set("X-GitHub-Delivery", "delivery-1")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestWebhookQueue(t *testing.T) {
+	newQueue := func(size int) *webhookQueue {
+		return &webhookQueue{
+			secret:     "s3cret",
+			repository: "owner/repo",
+			events:     make(chan github.IssueEvent, size),
+		}
+	}
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		want    int
+		queued  bool
+	}{
+		{"labeled", signedDelivery("labeled", "owner/repo", "s3cret"), http.StatusAccepted, true},
+		{"closed", signedDelivery("closed", "Owner/Repo", "s3cret"), http.StatusAccepted, true},
+		{"ignored action", signedDelivery("assigned", "owner/repo", "s3cret"), http.StatusNoContent, false},
+		{"other repository", signedDelivery("opened", "owner/other", "s3cret"), http.StatusNoContent, false},
+		{"wrong secret", signedDelivery("opened", "owner/repo", "guess"), http.StatusUnauthorized, false},
+		{"get", httptest.NewRequest(http.MethodGet, "/webhook", nil), http.StatusMethodNotAllowed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := newQueue(1)
+			w := httptest.NewRecorder()
+			q.ServeHTTP(w, tt.request)
+			assert.Equal(t, tt.want, w.Code)
+			assert.Equal(t, tt.queued, len(q.events) == 1)
+		})
+	}
+
+	t.Run("full queue", func(t *testing.T) {
+		q := newQueue(1)
+		q.ServeHTTP(httptest.NewRecorder(), signedDelivery("opened", "owner/repo", "s3cret"))
+		w := httptest.NewRecorder()
+		q.ServeHTTP(w, signedDelivery("edited", "owner/repo", "s3cret"))
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+}
+
+func TestWebhookQueueRun(t *testing.T) {
+	var synced []string
+	q := &webhookQueue{
+		events: make(chan github.IssueEvent, 2),
+		sync: func(ctx context.Context, event github.IssueEvent) {
+			synced = append(synced, event.Action)
+		},
+	}
+	q.events <- github.IssueEvent{Action: "opened"}
+	q.events <- github.IssueEvent{Action: "closed"}
+	q.close()
+
+	q.run(context.Background())
+	assert.Equal(t, []string{"opened", "closed"}, synced)
+}
+
+func TestWebhookQueueClosed(t *testing.T) {
+	q := &webhookQueue{
+		secret:     "s3cret",
+		repository: "owner/repo",
+		events:     make(chan github.IssueEvent, 1),
+	}
+	q.close()
+	q.close()
+
+	w := httptest.NewRecorder()
+	q.ServeHTTP(w, signedDelivery("opened", "owner/repo", "s3cret"))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+}
+
+func TestServeWebhooksDrainsQueue(t *testing.T) {
+	var errs []error
+	q := &webhookQueue{
+		events: make(chan github.IssueEvent, 2),
+		sync: func(ctx context.Context, event github.IssueEvent) {
+			errs = append(errs, ctx.Err())
+		},
+	}
+	q.events <- github.IssueEvent{Action: "opened"}
+	q.events <- github.IssueEvent{Action: "closed"}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NoError(t, serveWebhooks(ctx, "127.0.0.1:0", q))
+	assert.Equal(t, []error{nil, nil}, errs)
+}
+
+func TestHierarchyScope(t *testing.T) {
+	issues := []models.GitHubIssue{
+		{Number: 1, Labels: []string{"feature"}, Description: "## Issues\n- https://github.com/owner/repo/issues/7\n"},
+		{Number: 2, Labels: []string{"feature"}, Description: "## Issues\n- https://github.com/owner/repo/issues/8\n"},
+		{Number: 7, Labels: []string{"story"}},
+		{Number: 8, Labels: []string{"story"}},
+		{Number: 9, Labels: []string{"feature"}},
+	}
+
+	var numbers []int
+	for _, issue := range hierarchyScope(issues, 7, "github.com", nil) {
+		numbers = append(numbers, issue.Number)
+	}
+	assert.Equal(t, []int{1, 7, 8}, numbers)
+
+	numbers = nil
+	for _, issue := range hierarchyScope(issues, 9, "github.com", nil) {
+		numbers = append(numbers, issue.Number)
+	}
+	require.Equal(t, []int{7, 8, 9}, numbers)
+}
+
+func TestIndexOfIssue(t *testing.T) {
+	issues := []models.GitHubIssue{{Number: 3}, {Number: 5}}
+	assert.Equal(t, 1, indexOfIssue(issues, 5))
+	assert.Equal(t, -1, indexOfIssue(issues, 4))
+}
@@ -0,0 +1,189 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/checkpoint"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/mappings"
+	"github.com/danielolaszy/glue/internal/runs"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/internal/statuscache"
+	"github.com/spf13/cobra"
+)
+
+// stateCmd groups the commands managing the state glue keeps between runs.
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Manage the state glue keeps between runs",
+	Long: `Manage the state glue keeps between runs: the ticket status caches, broken
+mapping records, sync state, backfill checkpoints and run summaries of each
+repository. 'glue paths' shows where they are stored.`,
+}
+
+// stateRenameRepoCmd moves the state and config of a renamed repository.
+var stateRenameRepoCmd = &cobra.Command{
+	Use:   "rename-repo <old> <new>",
+	Short: "Move glue's state and config to a renamed GitHub repository",
+	Long: `Move the state glue keeps for a GitHub repository to its new name after it
+was renamed or transferred, and replace the old name in the config file, e.g.
+in the safety allow and deny lists.
+
+'glue jira' notices a renamed repository by itself, since GitHub redirects
+requests for the old name, and moves the state; the config file is only
+changed by this command.
+
+Example:
+  glue state rename-repo myorg/old-name myorg/new-name`,
+	Args: cobra.ExactArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from, to := args[0], args[1]
+		for _, repository := range args {
+			if !repositoryPattern.MatchString(repository) {
+				return fmt.Errorf("repository %q is not in owner/repo form", repository)
+			}
+		}
+		if from == to {
+			return fmt.Errorf("repository %s is given twice", from)
+		}
+
+		out := cmd.OutOrStdout()
+		moved, err := moveRepositoryState(from, to)
+		for _, state := range moved {
+			fmt.Fprintf(out, "moved %s\n", state)
+		}
+		if err != nil {
+			return err
+		}
+
+		configPath, err := config.FilePath()
+		if err != nil {
+			return err
+		}
+		if configPath != "" {
+			replaced, err := renameInConfigFile(configPath, from, to)
+			if err != nil {
+				return err
+			}
+			if replaced > 0 {
+				fmt.Fprintf(out, "replaced %d mentions of %s in %s\n", replaced, from, configPath)
+			}
+		}
+
+		if len(moved) == 0 {
+			fmt.Fprintf(out, "no state stored for %s\n", from)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateRenameRepoCmd)
+}
+
+// moveRepositoryState moves the state stored for repository from to
+// repository to, and describes what was moved. State moved before an error
+// stays moved.
+func moveRepositoryState(from, to string) ([]string, error) {
+	var moved []string
+
+	ok, err := statuscache.Move(statuscache.DefaultDir(), from, to)
+	if err != nil {
+		return moved, err
+	}
+	if ok {
+		moved = append(moved, "ticket status cache")
+	}
+
+	ok, err = mappings.Move(mappings.DefaultDir(), from, to)
+	if err != nil {
+		return moved, err
+	}
+	if ok {
+		moved = append(moved, "broken mappings")
+	}
+
+	ok, err = state.Move(state.DefaultDir(), from, to)
+	if err != nil {
+		return moved, err
+	}
+	if ok {
+		moved = append(moved, "sync state")
+	}
+
+	ok, err = runs.Move(runs.DefaultDir(), from, to)
+	if err != nil {
+		return moved, err
+	}
+	if ok {
+		moved = append(moved, "run summaries")
+	}
+
+	checkpoints, err := checkpoint.Move(checkpoint.DefaultDir(), from, to)
+	if checkpoints > 0 {
+		moved = append(moved, fmt.Sprintf("%d backfill checkpoints", checkpoints))
+	}
+	return moved, err
+}
+
+// renameInConfigFile replaces the mentions of repository from in a config
+// file with repository to, keeping the rest of the file as it is, and returns
+// the number replaced. Names merely containing from, like "owner/repo-api"
+// for "owner/repo", are left alone.
+func renameInConfigFile(path, from, to string) (int, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	pattern := regexp.MustCompile(`(?i)(^|[^\w./-])` + regexp.QuoteMeta(from) + `($|[^\w./-])`)
+	replaced := len(pattern.FindAllIndex(data, -1))
+	if replaced == 0 {
+		return 0, nil
+	}
+	data = pattern.ReplaceAll(data, []byte("${1}"+strings.ReplaceAll(to, "$", "$$")+"${2}"))
+
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read config file: %v", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, info.Mode().Perm()); err != nil {
+		return 0, fmt.Errorf("failed to write config file: %v", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		os.Remove(tmp)
+		return 0, fmt.Errorf("failed to write config file: %v", err)
+	}
+	return replaced, nil
+}
+
+// followRename moves the state of a repository GitHub reports under another
+// name, since it was renamed or transferred, and returns the current name.
+// Failing to move the state isn't fatal: the run then starts from scratch
+// under the new name.
+func followRename(cmd *cobra.Command, repository, current string) string {
+	if current == "" || strings.EqualFold(current, repository) {
+		return repository
+	}
+
+	moved, err := moveRepositoryState(repository, current)
+	if err != nil {
+		if errors.Is(err, os.ErrExist) {
+			err = fmt.Errorf("%v; remove it to keep the state of %s", err, repository)
+		}
+		fmt.Fprintf(cmd.ErrOrStderr(), "failed to move the state of %s: %v\n", repository, err)
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "repository %s was renamed to %s, syncing %s", repository, current, current)
+	if len(moved) > 0 {
+		fmt.Fprintf(cmd.ErrOrStderr(), " with its %s", strings.Join(moved, ", "))
+	}
+	fmt.Fprintf(cmd.ErrOrStderr(), "; run 'glue state rename-repo %s %s' to update the config file\n", repository, current)
+	return current
+}
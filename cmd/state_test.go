@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/paths"
+	"github.com/danielolaszy/glue/internal/statuscache"
+	"github.com/spf13/cobra"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenameInConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	config := `# sync of myorg/app
+flags:
+  repository: MyOrg/App
+safety:
+  allow_repositories: ["myorg/app", "myorg/app-api", "other/myorg/app"]
+`
+	require.NoError(t, os.WriteFile(path, []byte(config), 0o600))
+
+	replaced, err := renameInConfigFile(path, "myorg/app", "myorg/web")
+	require.NoError(t, err)
+	assert.Equal(t, 3, replaced)
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, `# sync of myorg/web
+flags:
+  repository: myorg/web
+safety:
+  allow_repositories: ["myorg/web", "myorg/app-api", "other/myorg/app"]
+`, string(data))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	replaced, err = renameInConfigFile(path, "myorg/app", "myorg/web")
+	require.NoError(t, err)
+	assert.Zero(t, replaced)
+}
+
+func TestFollowRename(t *testing.T) {
+	t.Setenv(paths.CacheDirEnv, t.TempDir())
+	cache := statuscache.New(statuscache.DefaultDir(), "owner/old")
+	cache.MarkDone("PROJ-1", time.Now())
+	require.NoError(t, cache.Save())
+
+	var stderr bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetErr(&stderr)
+
+	assert.Equal(t, "owner/old", followRename(cmd, "owner/old", "Owner/Old"))
+	assert.Empty(t, stderr.String())
+
+	assert.Equal(t, "owner/new", followRename(cmd, "owner/old", "owner/new"))
+	assert.Contains(t, stderr.String(), "repository owner/old was renamed to owner/new, syncing owner/new with its ticket status cache")
+
+	moved, err := statuscache.Load(statuscache.DefaultDir(), "owner/new")
+	require.NoError(t, err)
+	assert.True(t, moved.IsDone("PROJ-1"))
+}
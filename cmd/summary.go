@@ -0,0 +1,93 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/runs"
+)
+
+// recordRun adds the summary of a run to the run history of repository and
+// prints how it differs from the last run with the same boards. Failing to read or save the
+// history is logged; the run itself succeeded.
+func recordRun(out io.Writer, dir, repository string, summary runs.Summary) {
+	history, err := runs.Load(dir, repository)
+	if err != nil {
+		logging.Warn("replacing unreadable run history", "error", err)
+		history = runs.New(dir, repository)
+	}
+
+	if previous, ok := history.LastWithBoards(summary.Boards); ok {
+		fmt.Fprintln(out, describeDelta(runs.Compare(previous, summary), previous.StartedAt))
+	}
+
+	history.Add(summary)
+	if err := history.Save(); err != nil {
+		logging.Warn("failed to save run history", "error", err)
+	}
+}
+
+// describeDelta describes how a run differs from the run before, started at
+// since, e.g. "Since the last run at 2024-05-01 12:00 UTC: +12 tickets
+// created, -3 links created, 2 new failures (#4, #9)".
+func describeDelta(d runs.Delta, since time.Time) string {
+	var parts []string
+	counts := []struct {
+		change int
+		what   string
+	}{
+		{d.Created, "tickets created"},
+		{d.Closed, "tickets closed"},
+		{d.LinksCreated, "links created"},
+		{d.LinksRemoved, "links removed"},
+	}
+	for _, c := range counts {
+		if c.change != 0 {
+			parts = append(parts, fmt.Sprintf("%+d %s", c.change, c.what))
+		}
+	}
+	if n := len(d.NewFailures); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d new %s (%s)", n, plural(n, "failure", "failures"), issueList(d.NewFailures)))
+	}
+	if n := len(d.Recovered); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s no longer failing (%s)", n, plural(n, "issue", "issues"), issueList(d.Recovered)))
+	}
+
+	prefix := "Since the last run at " + since.UTC().Format("2006-01-02 15:04 UTC")
+	if len(parts) == 0 {
+		return prefix + ": no change"
+	}
+	return prefix + ": " + strings.Join(parts, ", ")
+}
+
+// failedIssues returns the issue numbers in failed in ascending order.
+func failedIssues(failed map[int]bool) []int {
+	numbers := make([]int, 0, len(failed))
+	for number := range failed {
+		numbers = append(numbers, number)
+	}
+	sort.Ints(numbers)
+	return numbers
+}
+
+// issueList formats issue numbers as "#4, #9".
+func issueList(numbers []int) string {
+	refs := make([]string, len(numbers))
+	for i, number := range numbers {
+		refs[i] = fmt.Sprintf("#%d", number)
+	}
+	return strings.Join(refs, ", ")
+}
+
+// plural returns singular if n is 1, and plural otherwise.
+func plural(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/runs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDescribeDelta(t *testing.T) {
+	since := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	assert.Equal(t,
+		"Since the last run at 2024-05-01 12:00 UTC: +12 tickets created, -3 links created, 2 new failures (#4, #9), 1 issue no longer failing (#2)",
+		describeDelta(runs.Delta{Created: 12, LinksCreated: -3, NewFailures: []int{4, 9}, Recovered: []int{2}}, since))
+	assert.Equal(t,
+		"Since the last run at 2024-05-01 12:00 UTC: +1 tickets closed, 1 new failure (#7)",
+		describeDelta(runs.Delta{Closed: 1, NewFailures: []int{7}}, since))
+	assert.Equal(t, "Since the last run at 2024-05-01 12:00 UTC: no change", describeDelta(runs.Delta{}, since))
+}
+
+func TestRecordRun(t *testing.T) {
+	dir := t.TempDir()
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	var first bytes.Buffer
+	recordRun(&first, dir, "owner/repo", runs.Summary{StartedAt: started, Created: 2, Failed: []int{3}})
+	assert.Empty(t, first.String(), "the first run has nothing to compare with")
+
+	var other bytes.Buffer
+	recordRun(&other, dir, "owner/repo", runs.Summary{StartedAt: started.Add(time.Minute), Boards: []string{"OPS"}, Created: 9})
+	assert.Empty(t, other.String(), "runs of other boards aren't compared")
+
+	var second bytes.Buffer
+	recordRun(&second, dir, "owner/repo", runs.Summary{StartedAt: started.Add(time.Hour), Created: 5, LinksRemoved: 1})
+	assert.Equal(t, "Since the last run at 2024-05-01 12:00 UTC: +3 tickets created, +1 links removed, 1 issue no longer failing (#3)\n", second.String())
+
+	history, err := runs.Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Len(t, history.Runs, 3)
+}
+
+func TestFailedIssues(t *testing.T) {
+	assert.Equal(t, []int{2, 5, 11}, failedIssues(map[int]bool{11: true, 2: true, 5: true}))
+	assert.Empty(t, failedIssues(nil))
+}
@@ -0,0 +1,90 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// trackSyncState loads the sync state of repository and makes the GitHub
+// client consult it for every issue it fetches from the repository. The sync
+// looks keys up in the store with issueKey; the store's keys are also put in
+// the titles, for commands that read keys from titles, and keys only found in
+// titles are recorded, so repositories synced before the store existed
+// migrate to it.
+func trackSyncState(githubClient *github.Client, repository string) *state.Store {
+	store, err := state.Load(state.DefaultDir(), repository)
+	if err != nil {
+		logging.Warn("replacing unreadable sync state", "error", err)
+		store = state.New(state.DefaultDir(), repository)
+	}
+	githubClient.SetIssueFunc(func(fetchedFrom string, issue models.GitHubIssue) models.GitHubIssue {
+		if !strings.EqualFold(fetchedFrom, store.Repository) {
+			return issue
+		}
+		return restoreIssueKey(store, issue)
+	})
+	return store
+}
+
+// saveSyncState saves the sync state, if there is one.
+func saveSyncState(store *state.Store) {
+	if store == nil {
+		return
+	}
+	if err := store.Save(); err != nil {
+		logging.Warn("failed to save sync state", "error", err)
+	}
+}
+
+// issueKey returns the key of the ticket an issue is synced to: the key
+// recorded in store, or, for issues it has no entry for and with a nil store,
+// the key in the title.
+func issueKey(store *state.Store, issue models.GitHubIssue) string {
+	if store != nil {
+		if key := store.Key(issue.Number); key != "" {
+			return key
+		}
+	}
+	return marker.GitHub.Key(issue.Title)
+}
+
+// issueSynced reports whether an issue is synced to a ticket, by its key in
+// store or its marker.
+func issueSynced(store *state.Store, issue models.GitHubIssue) bool {
+	return issueKey(store, issue) != "" || marker.GitHub.Marked(issue.Title, issue.Labels)
+}
+
+// restoreIssueKey returns the issue with the key of its ticket in the title.
+// The recorded key takes precedence: it fills in for a title without one and
+// replaces a different one in the title, with a warning, since titles are
+// edited by hand while the record is only changed by glue, e.g. by 'glue jira
+// migrate'. A key in the title is recorded for issues the store has no entry
+// for.
+func restoreIssueKey(store *state.Store, issue models.GitHubIssue) models.GitHubIssue {
+	titleKey := marker.GitHub.Key(issue.Title)
+	key := store.Key(issue.Number)
+	switch {
+	case key == "":
+		if titleKey != "" {
+			store.SetKey(issue.Number, titleKey)
+		}
+	case titleKey == "":
+		logging.Debug("issue title has no jira key, using the recorded one",
+			"issue_number", issue.Number,
+			"jira_ticket", key)
+		issue.Title = marker.GitHub.Apply(issue.Title, key)
+	case !strings.EqualFold(titleKey, key):
+		logging.Warn("issue title names another jira ticket than the recorded one, using the recorded one",
+			"issue_number", issue.Number,
+			"title_key", titleKey,
+			"jira_ticket", key)
+		issue.Title = marker.GitHub.Apply(marker.GitHub.Strip(issue.Title), key)
+	}
+	return issue
+}
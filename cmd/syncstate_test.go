@@ -0,0 +1,103 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/paths"
+	"github.com/danielolaszy/glue/internal/state"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRestoreIssueKey(t *testing.T) {
+	store := state.New(t.TempDir(), "owner/repo")
+	store.SetKey(1, "PROJ-1")
+	store.SetKey(2, "PROJ-2")
+
+	// The recorded key fills in for a title that lost it
+	issue := restoreIssueKey(store, models.GitHubIssue{Number: 1, Title: "Add login"})
+	assert.Equal(t, "[PROJ-1] Add login", issue.Title)
+
+	// The recorded key wins over another one in the title
+	issue = restoreIssueKey(store, models.GitHubIssue{Number: 2, Title: "[OPS-7] Add logout"})
+	assert.Equal(t, "[PROJ-2] Add logout", issue.Title)
+	assert.Equal(t, "PROJ-2", store.Key(2))
+
+	issue = restoreIssueKey(store, models.GitHubIssue{Number: 2, Title: "[PROJ-2] Add logout"})
+	assert.Equal(t, "[PROJ-2] Add logout", issue.Title)
+
+	// Titled issues unknown to the store are recorded
+	restoreIssueKey(store, models.GitHubIssue{Number: 3, Title: "[PROJ-3] Search"})
+	assert.Equal(t, "PROJ-3", store.Key(3))
+
+	issue = restoreIssueKey(store, models.GitHubIssue{Number: 4, Title: "Profile"})
+	assert.Equal(t, "Profile", issue.Title)
+	assert.Equal(t, "", store.Key(4))
+}
+
+func TestIssueKey(t *testing.T) {
+	store := state.New(t.TempDir(), "owner/repo")
+	store.SetKey(1, "PROJ-1")
+	store.SetKey(2, "PROJ-2")
+
+	// The recorded key is used whatever the title says
+	assert.Equal(t, "PROJ-1", issueKey(store, models.GitHubIssue{Number: 1, Title: "Add login"}))
+	assert.Equal(t, "PROJ-2", issueKey(store, models.GitHubIssue{Number: 2, Title: "[OPS-7] Add logout"}))
+
+	// Issues the store has no entry for fall back to the title
+	assert.Equal(t, "PROJ-3", issueKey(store, models.GitHubIssue{Number: 3, Title: "[PROJ-3] Search"}))
+	assert.Equal(t, "", issueKey(store, models.GitHubIssue{Number: 4, Title: "Profile"}))
+	assert.Equal(t, "PROJ-3", issueKey(nil, models.GitHubIssue{Number: 3, Title: "[PROJ-3] Search"}))
+
+	assert.True(t, issueSynced(store, models.GitHubIssue{Number: 1, Title: "Add login"}))
+	assert.False(t, issueSynced(store, models.GitHubIssue{Number: 4, Title: "Profile"}))
+}
+
+func TestBuildGitHubToJiraMapUsesSyncState(t *testing.T) {
+	store := state.New(t.TempDir(), "owner/repo")
+	store.SetKey(1, "PROJ-1")
+
+	issues := []models.GitHubIssue{
+		{Number: 1, Title: "Parent feature"},
+		{Number: 2, Title: "[PROJ-2] Child story"},
+		{Number: 3, Title: "Unsynced story"},
+	}
+	assert.Equal(t, map[int]string{1: "PROJ-1", 2: "PROJ-2"}, buildGitHubToJiraMap(issues, store))
+	assert.Equal(t, map[int]string{2: "PROJ-2"}, buildGitHubToJiraMap(issues, nil))
+}
+
+func TestTrackSyncState(t *testing.T) {
+	dir := t.TempDir()
+	t.Setenv(paths.CacheDirEnv, dir)
+
+	saved := state.New(state.DefaultDir(), "owner/repo")
+	saved.SetKey(5, "PROJ-5")
+	require.NoError(t, saved.Save())
+
+	store := trackSyncState(&github.Client{}, "owner/repo")
+	assert.Equal(t, "PROJ-5", store.Key(5))
+
+	store.SetKey(6, "PROJ-6")
+	saveSyncState(store)
+	saveSyncState(nil)
+
+	loaded, err := state.Load(filepath.Join(dir, "state"), "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-6", loaded.Key(6))
+}
+
+func TestSyncHooksRecordsCreatedTickets(t *testing.T) {
+	store := state.New(t.TempDir(), "owner/repo")
+	h := &syncHooks{repository: "owner/repo", syncState: store}
+
+	h.postIssue(context.Background(), "PROJ", models.GitHubIssue{Number: 1}, "PROJ-1", nil)
+	h.postIssue(context.Background(), "PROJ", models.GitHubIssue{Number: 2}, "", assert.AnError)
+
+	assert.Equal(t, "PROJ-1", store.Key(1))
+	assert.False(t, store.Issues[1].SyncedAt.IsZero())
+	assert.Equal(t, "", store.Key(2))
+}
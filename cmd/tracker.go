@@ -0,0 +1,238 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/danielolaszy/glue/internal/lock"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// Tracker is an issue tracker glue keeps an item in for each synced GitHub
+// issue: JIRA tickets, Asana tasks, YouTrack issues or ClickUp tasks. The
+// commands syncing a tracker implement it and leave the run to syncTracker.
+type Tracker interface {
+	// Noun names the tracker's items in output and logs, e.g. "asana task"
+	Noun() string
+	// Item returns the item of an issue, if it has one
+	Item(issue models.GitHubIssue) (trackerItem, bool)
+	// Where describes where the item of an issue is created for the output,
+	// e.g. " in Backlog", or returns "" to say nothing
+	Where(issue models.GitHubIssue) string
+	// Create creates the item of an open issue
+	Create(ctx context.Context, issue models.GitHubIssue) (trackerItem, error)
+	// Close closes the item of a closed issue. It returns false if the item
+	// turned out to be closed already.
+	Close(ctx context.Context, issue models.GitHubIssue, item trackerItem) (bool, error)
+}
+
+// trackerReopener is a Tracker that reopens the closed items of reopened
+// issues.
+type trackerReopener interface {
+	Reopen(ctx context.Context, issue models.GitHubIssue, item trackerItem) error
+}
+
+// trackerStopper is a Tracker that stops a run from starting more issues,
+// e.g. while the tracker is unavailable.
+type trackerStopper interface {
+	Stopped(ctx context.Context) bool
+}
+
+// trackerItem is the item of a GitHub issue in a Tracker.
+type trackerItem struct {
+	// ID identifies the item in output, e.g. a JIRA key
+	ID string
+	// Closed reports whether the item is closed, completed or resolved
+	Closed bool
+}
+
+// trackerAction is a change syncTracker makes for an issue.
+type trackerAction struct {
+	// Kind is "create", "reopen" or "close"
+	Kind  string
+	Issue models.GitHubIssue
+	// Item is the issue's item, for "reopen" and "close"
+	Item trackerItem
+}
+
+// trackerSync configures a syncTracker run.
+type trackerSync struct {
+	// out receives a line per change, or per planned change in a dry run;
+	// nil prints nothing
+	out io.Writer
+	// dryRun plans the changes without making them
+	dryRun bool
+}
+
+// trackerCounts counts the changes a syncTracker run made, and the issues it
+// failed to change.
+type trackerCounts struct {
+	Created  int
+	Reopened int
+	Closed   int
+	Failed   int
+}
+
+// syncTracker brings the items of a tracker in line with the issues: it
+// creates items for open issues without one, features first, reopens the
+// closed items of open issues if the tracker can, and closes the items of
+// closed issues. Locked open issues are left alone, as by 'glue jira'.
+// Failures are logged and counted, and a panic fails only its issue. The run
+// stops starting issues once ctx is done or the tracker says so.
+func syncTracker(ctx context.Context, t Tracker, open, closed []models.GitHubIssue, run trackerSync) trackerCounts {
+	stopper, _ := t.(trackerStopper)
+	actions := planTrackerActions(t, open, closed)
+
+	var counts trackerCounts
+	for i, action := range actions {
+		if ctx.Err() != nil || stopper != nil && stopper.Stopped(ctx) {
+			logging.Warn("stopping early, not starting remaining issues",
+				"tracker", t.Noun(),
+				"remaining", len(actions)-i)
+			break
+		}
+
+		issue := action.Issue
+		if run.dryRun {
+			fmt.Fprintln(outOrDiscard(run.out), describeTrackerAction(t, action, "would "+action.Kind))
+			continue
+		}
+
+		issueCtx := logging.WithTraceID(ctx, "issue_number", issue.Number)
+		var changed bool
+		err := isolateIssue(issueCtx, issue.Number, func() error {
+			var err error
+			changed, err = applyTrackerAction(issueCtx, t, &action)
+			return err
+		})
+		if err != nil {
+			logging.FromContext(issueCtx).Error(fmt.Sprintf("failed to %s %s", action.Kind, t.Noun()),
+				"issue_number", issue.Number,
+				"tracker_item", action.Item.ID,
+				"error", err)
+			counts.Failed++
+			continue
+		}
+		if !changed {
+			continue
+		}
+
+		switch action.Kind {
+		case "create":
+			counts.Created++
+		case "reopen":
+			counts.Reopened++
+		case "close":
+			counts.Closed++
+		}
+		fmt.Fprintln(outOrDiscard(run.out), describeTrackerAction(t, action, trackerPastTense[action.Kind]))
+	}
+	return counts
+}
+
+// trackerPastTense describes the actions once done.
+var trackerPastTense = map[string]string{
+	"create": "created",
+	"reopen": "reopened",
+	"close":  "closed",
+}
+
+// planTrackerActions returns the changes syncTracker makes, in order: items
+// to create, features first so their stories can refer to them, then items
+// to reopen and to close.
+func planTrackerActions(t Tracker, open, closed []models.GitHubIssue) []trackerAction {
+	_, reopens := t.(trackerReopener)
+
+	var creates, statuses []trackerAction
+	for _, issue := range open {
+		if skippedAsLocked(issue) {
+			logging.Debug("skipping locked issue",
+				"issue_number", issue.Number,
+				"tracker", t.Noun())
+			continue
+		}
+		item, ok := t.Item(issue)
+		switch {
+		case !ok:
+			creates = append(creates, trackerAction{Kind: "create", Issue: issue})
+		case item.Closed && reopens:
+			statuses = append(statuses, trackerAction{Kind: "reopen", Issue: issue, Item: item})
+		}
+	}
+	for _, issue := range closed {
+		if item, ok := t.Item(issue); ok && !item.Closed {
+			statuses = append(statuses, trackerAction{Kind: "close", Issue: issue, Item: item})
+		}
+	}
+
+	sort.SliceStable(creates, func(i, j int) bool {
+		return issueTypeOf(creates[i].Issue) == "feature" && issueTypeOf(creates[j].Issue) != "feature"
+	})
+	return append(creates, statuses...)
+}
+
+// applyTrackerAction makes a change, recording a created item in action. It
+// returns false if there was nothing to change after all.
+func applyTrackerAction(ctx context.Context, t Tracker, action *trackerAction) (bool, error) {
+	switch action.Kind {
+	case "create":
+		item, err := t.Create(ctx, action.Issue)
+		if err != nil {
+			return false, err
+		}
+		action.Item = item
+		return true, nil
+	case "reopen":
+		return true, t.(trackerReopener).Reopen(ctx, action.Issue, action.Item)
+	case "close":
+		return t.Close(ctx, action.Issue, action.Item)
+	}
+	return false, fmt.Errorf("unknown tracker action %q", action.Kind)
+}
+
+// describeTrackerAction describes a change for the output, led by verb, e.g.
+// "created asana task 1204 for #12 Add login in Backlog".
+func describeTrackerAction(t Tracker, action trackerAction, verb string) string {
+	issue := action.Issue
+	switch action.Kind {
+	case "create":
+		item := t.Noun()
+		if action.Item.ID != "" {
+			item += " " + action.Item.ID
+		}
+		return fmt.Sprintf("%s %s for #%d %s%s", verb, item, issue.Number, issue.Title, t.Where(issue))
+	case "reopen":
+		return fmt.Sprintf("%s %s %s of reopened #%d %s", verb, t.Noun(), action.Item.ID, issue.Number, issue.Title)
+	default:
+		return fmt.Sprintf("%s %s %s of closed #%d %s", verb, t.Noun(), action.Item.ID, issue.Number, issue.Title)
+	}
+}
+
+// outOrDiscard returns out, or a writer discarding everything if out is nil.
+func outOrDiscard(out io.Writer) io.Writer {
+	if out == nil {
+		return io.Discard
+	}
+	return out
+}
+
+// lockRepository takes the per-repository lock that keeps runs changing
+// repository from overlapping, unless noLock is set, and returns its release.
+func lockRepository(repository string, noLock bool) (func(), error) {
+	if noLock {
+		return func() {}, nil
+	}
+	repoLock, err := lock.Acquire(lock.DefaultDir(), repository, lock.DefaultStaleAfter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to acquire repository lock: %v", err)
+	}
+	return func() {
+		if err := repoLock.Release(); err != nil {
+			logging.Warn("failed to release repository lock", "error", err)
+		}
+	}, nil
+}
@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/paths"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeTracker is a Tracker keeping its items in memory.
+type fakeTracker struct {
+	items map[int]trackerItem
+	// fail fails the changes of these issues; a panic if the value is true
+	fail    map[int]bool
+	changes []string
+}
+
+func (t *fakeTracker) Noun() string { return "task" }
+
+func (t *fakeTracker) Item(issue models.GitHubIssue) (trackerItem, bool) {
+	item, ok := t.items[issue.Number]
+	return item, ok
+}
+
+func (t *fakeTracker) Where(issue models.GitHubIssue) string { return " in Inbox" }
+
+func (t *fakeTracker) change(kind string, issue models.GitHubIssue) error {
+	if panics, ok := t.fail[issue.Number]; ok {
+		if panics {
+			panic("boom")
+		}
+		return errors.New("unavailable")
+	}
+	t.changes = append(t.changes, fmt.Sprintf("%s #%d", kind, issue.Number))
+	return nil
+}
+
+func (t *fakeTracker) Create(ctx context.Context, issue models.GitHubIssue) (trackerItem, error) {
+	if err := t.change("create", issue); err != nil {
+		return trackerItem{}, err
+	}
+	return trackerItem{ID: fmt.Sprintf("T-%d", issue.Number)}, nil
+}
+
+func (t *fakeTracker) Close(ctx context.Context, issue models.GitHubIssue, item trackerItem) (bool, error) {
+	return true, t.change("close", issue)
+}
+
+func (t *fakeTracker) Reopen(ctx context.Context, issue models.GitHubIssue, item trackerItem) error {
+	return t.change("reopen", issue)
+}
+
+func TestSyncTracker(t *testing.T) {
+	open := []models.GitHubIssue{
+		{Number: 1, Title: "Story"},
+		{Number: 2, Title: "Feature", Labels: []string{"feature"}},
+		{Number: 3, Title: "Reopened"},
+		{Number: 4, Title: "Locked", Locked: true},
+		{Number: 5, Title: "Synced"},
+	}
+	closed := []models.GitHubIssue{
+		{Number: 6, Title: "Done"},
+		{Number: 7, Title: "Closed already"},
+	}
+	items := func() map[int]trackerItem {
+		return map[int]trackerItem{
+			3: {ID: "T-3", Closed: true},
+			5: {ID: "T-5"},
+			6: {ID: "T-6"},
+			7: {ID: "T-7", Closed: true},
+		}
+	}
+
+	t.Run("dry run", func(t *testing.T) {
+		tracker := &fakeTracker{items: items()}
+		var out bytes.Buffer
+		counts := syncTracker(context.Background(), tracker, open, closed, trackerSync{out: &out, dryRun: true})
+
+		assert.Empty(t, tracker.changes)
+		assert.Equal(t, trackerCounts{}, counts)
+		assert.Equal(t, "would create task for #2 Feature in Inbox\n"+
+			"would create task for #1 Story in Inbox\n"+
+			"would reopen task T-3 of reopened #3 Reopened\n"+
+			"would close task T-6 of closed #6 Done\n", out.String())
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		tracker := &fakeTracker{items: items()}
+		var out bytes.Buffer
+		counts := syncTracker(context.Background(), tracker, open, closed, trackerSync{out: &out})
+
+		// Features first, locked issues left alone
+		assert.Equal(t, []string{"create #2", "create #1", "reopen #3", "close #6"}, tracker.changes)
+		assert.Equal(t, trackerCounts{Created: 2, Reopened: 1, Closed: 1}, counts)
+		assert.Contains(t, out.String(), "created task T-2 for #2 Feature in Inbox\n")
+		assert.Contains(t, out.String(), "closed task T-6 of closed #6 Done\n")
+	})
+
+	t.Run("failures", func(t *testing.T) {
+		tracker := &fakeTracker{items: items(), fail: map[int]bool{1: false, 2: true}}
+		counts := syncTracker(context.Background(), tracker, open, closed, trackerSync{})
+
+		assert.Equal(t, []string{"reopen #3", "close #6"}, tracker.changes)
+		assert.Equal(t, trackerCounts{Reopened: 1, Closed: 1, Failed: 2}, counts)
+	})
+
+	t.Run("stopped", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		tracker := &fakeTracker{items: items()}
+		counts := syncTracker(ctx, tracker, open, closed, trackerSync{})
+
+		assert.Empty(t, tracker.changes)
+		assert.Equal(t, trackerCounts{}, counts)
+	})
+}
+
+func TestLockRepository(t *testing.T) {
+	t.Setenv(paths.CacheDirEnv, t.TempDir())
+
+	unlock, err := lockRepository("owner/repo", false)
+	assert.NoError(t, err)
+
+	// Another run can't take the lock until it is released
+	_, err = lockRepository("owner/repo", false)
+	assert.Error(t, err)
+
+	unlockAgain, err := lockRepository("owner/repo", true)
+	assert.NoError(t, err)
+	unlockAgain()
+
+	unlock()
+	unlock, err = lockRepository("owner/repo", false)
+	assert.NoError(t, err)
+	unlock()
+}
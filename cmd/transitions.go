@@ -0,0 +1,101 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/danielolaszy/glue/internal/jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// jiraTransitionsCmd lists the workflow transitions of a ticket, or the
+// workflow statuses of a project.
+var jiraTransitionsCmd = &cobra.Command{
+	Use:   "transitions <ticket|project>",
+	Short: "List the workflow transitions of a JIRA ticket or the statuses of a project",
+	Long: `List the exact names and IDs of the transitions currently available for a
+JIRA ticket, to put into jira.close_transitions or jira.board_close_transitions
+in the config file instead of guessing. The transition glue would use to close the ticket is marked with *.
+
+Given a project key instead, the workflow statuses of each of the project's
+issue types are listed. Transitions depend on a ticket's current status, so
+list them for a ticket in the status glue closes tickets from.
+
+Example:
+  glue jira transitions PROJ-123
+  glue jira transitions PROJ`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		jiraClient, err := jira.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize jira client: %v", err)
+		}
+
+		out := cmd.OutOrStdout()
+		target := strings.ToUpper(args[0])
+		if !ticketKeyArgPattern.MatchString(target) {
+			statuses, err := jiraClient.GetProjectStatuses(target)
+			if err != nil {
+				return err
+			}
+			writeProjectStatuses(out, target, statuses)
+			return nil
+		}
+
+		transitions, err := jiraClient.GetTransitions(target)
+		if err != nil {
+			return err
+		}
+		closing, _ := jiraClient.CloseTransition(target, transitions)
+		writeTransitions(out, target, transitions, closing.ID)
+		return nil
+	},
+}
+
+func init() {
+	jiraCmd.AddCommand(jiraTransitionsCmd)
+}
+
+// writeTransitions prints a ticket's transitions as a table, marking the one
+// with closingID.
+func writeTransitions(w io.Writer, key string, transitions []models.JiraTransition, closingID string) {
+	if len(transitions) == 0 {
+		fmt.Fprintf(w, "no transitions available for %s\n", key)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "\tID\tNAME\tTO STATUS")
+	for _, t := range transitions {
+		marker := ""
+		if closingID != "" && t.ID == closingID {
+			marker = "*"
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", marker, t.ID, t.Name, t.ToStatus)
+	}
+	tw.Flush()
+
+	if closingID == "" {
+		fmt.Fprintf(w, "\nnone of these closes %s; add the name or ID of the one that should to jira.close_transitions\n", key)
+	}
+}
+
+// writeProjectStatuses prints the workflow statuses of each issue type.
+func writeProjectStatuses(w io.Writer, project string, statuses []models.JiraIssueTypeStatuses) {
+	if len(statuses) == 0 {
+		fmt.Fprintf(w, "no issue types found in %s\n", project)
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "ISSUE TYPE\tSTATUSES")
+	for _, s := range statuses {
+		fmt.Fprintf(tw, "%s\t%s\n", s.IssueType, strings.Join(s.Statuses, " | "))
+	}
+	tw.Flush()
+	fmt.Fprintf(w, "\nrun 'glue jira transitions %s-<number>' to see the transitions of a ticket\n", project)
+}
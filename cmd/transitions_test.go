@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteTransitions(t *testing.T) {
+	transitions := []models.JiraTransition{
+		{ID: "11", Name: "Start", ToStatus: "In Progress"},
+		{ID: "31", Name: "Done", ToStatus: "Done"},
+	}
+
+	var out bytes.Buffer
+	writeTransitions(&out, "PROJ-1", transitions, "31")
+	assert.Equal(t, "   ID  NAME   TO STATUS\n   11  Start  In Progress\n*  31  Done   Done\n", out.String())
+
+	out.Reset()
+	writeTransitions(&out, "PROJ-1", transitions[:1], "")
+	assert.Contains(t, out.String(), "none of these closes PROJ-1; add the name or ID of the one that should to jira.close_transitions")
+
+	out.Reset()
+	writeTransitions(&out, "PROJ-1", nil, "")
+	assert.Equal(t, "no transitions available for PROJ-1\n", out.String())
+}
+
+func TestWriteProjectStatuses(t *testing.T) {
+	var out bytes.Buffer
+	writeProjectStatuses(&out, "PROJ", []models.JiraIssueTypeStatuses{
+		{IssueType: "Story", Statuses: []string{"To Do", "Done"}},
+		{IssueType: "Feature", Statuses: []string{"Funnel", "Closed"}},
+	})
+
+	assert.Equal(t, "ISSUE TYPE  STATUSES\nStory       To Do | Done\nFeature     Funnel | Closed\n\n"+
+		"run 'glue jira transitions PROJ-<number>' to see the transitions of a ticket\n", out.String())
+}
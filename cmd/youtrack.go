@@ -0,0 +1,228 @@
+// Package cmd provides the command-line interface for the Glue CLI tool.
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/github"
+	"github.com/danielolaszy/glue/internal/issueline"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/youtrack"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/spf13/cobra"
+)
+
+// youtrackCmd creates YouTrack issues for labeled GitHub issues.
+var youtrackCmd = &cobra.Command{
+	Use:   "youtrack",
+	Short: "Synchronize labeled GitHub issues with YouTrack issues",
+	Long: `Create an issue in a YouTrack project for every open GitHub issue with the
+--label label that has none yet, and keep the state of the YouTrack issues in
+line with the GitHub issues.
+
+New YouTrack issues get the custom field values youtrack.fields in the config
+file maps the GitHub issue's labels to; fields set later in YouTrack are left
+alone. YouTrack issues of closed GitHub issues move to youtrack.resolved_state
+(default "Fixed"), and resolved YouTrack issues of reopened GitHub issues to
+youtrack.open_state (default "Open").
+
+The first line of a YouTrack issue's description links its GitHub issue,
+which is how the two find each other on later runs; GitHub issues aren't
+changed.
+
+The project is given by its short name, the prefix of its issue keys. The
+address and token are read from YOUTRACK_URL and YOUTRACK_TOKEN, or
+youtrack.baseurl and youtrack.token in the config file.
+
+Example:
+  glue youtrack -r owner/repo --project PLAT
+  glue youtrack -r owner/repo --project PLAT --label roadmap --dry-run`,
+	PreRunE: validateFlags(flagRules{Repository: true, Required: []string{"project"}}),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		repository, err := cmd.Flags().GetString("repository")
+		if err != nil {
+			return err
+		}
+
+		projectName, err := cmd.Flags().GetString("project")
+		if err != nil {
+			return err
+		}
+
+		label, err := cmd.Flags().GetString("label")
+		if err != nil {
+			return err
+		}
+
+		dryRun, err := cmd.Flags().GetBool("dry-run")
+		if err != nil {
+			return err
+		}
+
+		noLock, err := cmd.Flags().GetBool("no-lock")
+		if err != nil {
+			return err
+		}
+
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return fmt.Errorf("failed to load config: %v", err)
+		}
+
+		githubClient, err := github.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize github client: %v", err)
+		}
+
+		youtrackClient, err := youtrack.NewClient()
+		if err != nil {
+			return fmt.Errorf("failed to initialize youtrack client: %v", err)
+		}
+
+		ctx := cmd.Context()
+		repository, err = checkRepository(ctx, cmd, githubClient, repository)
+		if err != nil {
+			return err
+		}
+
+		unlock, err := lockRepository(repository, noLock)
+		if err != nil {
+			return err
+		}
+		defer unlock()
+
+		if err := checkSafety(cmd, cfg.Safety, repository, nil); err != nil {
+			return err
+		}
+
+		project, err := youtrackClient.FindProject(ctx, projectName)
+		if err != nil {
+			return err
+		}
+
+		existing, err := youtrackClient.Issues(ctx, project)
+		if err != nil {
+			return err
+		}
+
+		open, err := githubClient.GetIssuesWithLabel(ctx, repository, label)
+		if err != nil {
+			return fmt.Errorf("failed to get issues with label %s: %v", label, err)
+		}
+
+		closed, err := githubClient.GetClosedIssuesWithLabels(ctx, repository, []string{label})
+		if err != nil {
+			return fmt.Errorf("failed to get closed issues with label %s: %v", label, err)
+		}
+
+		tracker := &youTrackTracker{
+			client:     youtrackClient,
+			cfg:        cfg.YouTrack,
+			domain:     cfg.GitHub.Domain,
+			repository: repository,
+			project:    project,
+			byIssue:    youTrackIssuesByURL(existing),
+		}
+		out := cmd.OutOrStdout()
+		counts := syncTracker(ctx, tracker, open, closed, trackerSync{out: out, dryRun: dryRun})
+
+		fmt.Fprintf(out, "\n%d open issues labeled %s, %d YouTrack issues created, %d moved in %s\n",
+			len(open), label, counts.Created, counts.Reopened+counts.Closed, project.ShortName)
+		if counts.Failed > 0 {
+			return fmt.Errorf("failed to update youtrack issues of %d issues", counts.Failed)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(youtrackCmd)
+	youtrackCmd.Flags().String("project", "", "Short name of the YouTrack project to create the issues in")
+	youtrackCmd.Flags().StringP("label", "l", "youtrack", "Label of the GitHub issues that get a YouTrack issue")
+	youtrackCmd.Flags().Bool("dry-run", false, "Print the YouTrack issues that would be created or moved without changing them")
+	youtrackCmd.Flags().Bool("no-lock", false, "Skip the per-repository lock that prevents concurrent runs")
+	youtrackCmd.Flags().Bool("force", false, "Run even if the safety config doesn't permit changing the repository")
+}
+
+// youTrackTracker is the Tracker of the issues in a YouTrack project. Closing
+// and reopening an issue moves it to the resolved or the open state.
+type youTrackTracker struct {
+	client     *youtrack.Client
+	cfg        config.YouTrackConfig
+	domain     string
+	repository string
+	project    youtrack.Project
+	// byIssue are the project's issues by the lower-cased address of their
+	// GitHub issue
+	byIssue map[string]youtrack.Issue
+}
+
+// Noun implements Tracker.
+func (t *youTrackTracker) Noun() string {
+	return "youtrack issue"
+}
+
+// Item implements Tracker.
+func (t *youTrackTracker) Item(issue models.GitHubIssue) (trackerItem, bool) {
+	yt, ok := t.byIssue[strings.ToLower(issueline.URL(t.domain, t.repository, issue.Number))]
+	return trackerItem{ID: yt.IDReadable, Closed: yt.Resolved()}, ok
+}
+
+// Where implements Tracker.
+func (t *youTrackTracker) Where(issue models.GitHubIssue) string {
+	return withFields(t.cfg.FieldValues(issue.Labels))
+}
+
+// Create implements Tracker, and sets the custom fields the issue's labels
+// map to.
+func (t *youTrackTracker) Create(ctx context.Context, issue models.GitHubIssue) (trackerItem, error) {
+	url := issueline.URL(t.domain, t.repository, issue.Number)
+	yt, err := t.client.CreateIssue(ctx, t.project, issue.Title, issueline.Description(url, issue.Description))
+	if err != nil {
+		return trackerItem{}, err
+	}
+	if err := t.client.SetFields(ctx, yt.IDReadable, t.cfg.FieldValues(issue.Labels)); err != nil {
+		// The issue exists, so it isn't created again; the fields can be
+		// set by hand
+		logging.FromContext(ctx).Warn("failed to set fields of youtrack issue",
+			"issue_number", issue.Number,
+			"youtrack_issue", yt.IDReadable,
+			"error", err)
+	}
+	return trackerItem{ID: yt.IDReadable}, nil
+}
+
+// Close implements Tracker.
+func (t *youTrackTracker) Close(ctx context.Context, issue models.GitHubIssue, item trackerItem) (bool, error) {
+	return true, t.client.SetState(ctx, item.ID, t.cfg.ResolvedState)
+}
+
+// Reopen implements trackerReopener.
+func (t *youTrackTracker) Reopen(ctx context.Context, issue models.GitHubIssue, item trackerItem) error {
+	return t.client.SetState(ctx, item.ID, t.cfg.OpenState)
+}
+
+// youTrackIssuesByURL returns the YouTrack issues created by glue by the
+// lower-cased address of their GitHub issue. Issues created otherwise are
+// left out.
+func youTrackIssuesByURL(issues []youtrack.Issue) map[string]youtrack.Issue {
+	byIssue := make(map[string]youtrack.Issue)
+	for _, issue := range issues {
+		if url := issueline.IssueURL(issue.Description); url != "" {
+			byIssue[strings.ToLower(url)] = issue
+		}
+	}
+	return byIssue
+}
+
+// withFields describes the custom field values of a new issue for the
+// output, if any.
+func withFields(values map[string]string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return " with " + youtrack.FieldCommand(values)
+}
@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/issueline"
+	"github.com/danielolaszy/glue/internal/youtrack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestYouTrackIssuesByURL(t *testing.T) {
+	issues := []youtrack.Issue{
+		{IDReadable: "PLAT-1", Description: issueline.Description("https://github.com/Owner/Repo/issues/7", "Body")},
+		{IDReadable: "PLAT-2", Description: "Filed by hand"},
+	}
+	assert.Equal(t, map[string]youtrack.Issue{
+		"https://github.com/owner/repo/issues/7": issues[0],
+	}, youTrackIssuesByURL(issues))
+}
+
+func TestWithFields(t *testing.T) {
+	assert.Equal(t, " with priority {Critical}", withFields(map[string]string{"priority": "Critical"}))
+	assert.Empty(t, withFields(map[string]string{}))
+}
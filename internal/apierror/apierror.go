@@ -0,0 +1,88 @@
+// Package apierror defines the kinds of errors returned by the GitHub and JIRA
+// clients, so callers can branch on them with errors.Is and errors.As instead of
+// matching messages.
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+var (
+	// ErrNotFound means the requested resource doesn't exist or isn't visible.
+	ErrNotFound = errors.New("not found")
+	// ErrUnauthorized means the credentials were rejected or lack permission.
+	ErrUnauthorized = errors.New("unauthorized")
+	// ErrRateLimited means the API refused the request due to rate limiting.
+	ErrRateLimited = errors.New("rate limited")
+	// ErrReadOnly means the request was refused because glue runs in read-only mode.
+	ErrReadOnly = errors.New("glue is in read-only mode (read_only is set in the config)")
+	// ErrMaintenance means the request was refused because a maintenance window is active.
+	ErrMaintenance = errors.New("jira is in a maintenance window (maintenance_windows in the config)")
+)
+
+// StatusError is a failed API call. Its message is that of the underlying
+// error; it matches the error kind derived from the HTTP status code.
+type StatusError struct {
+	// Kind is ErrNotFound, ErrUnauthorized, ErrRateLimited or nil
+	Kind error
+	// StatusCode is the HTTP status code, 0 if no response was received
+	StatusCode int
+	// Err is the error returned by the API library
+	Err error
+}
+
+// Error implements error.
+func (e *StatusError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the error kind and the underlying error.
+func (e *StatusError) Unwrap() []error {
+	if e.Kind == nil {
+		return []error{e.Err}
+	}
+	return []error{e.Kind, e.Err}
+}
+
+// FromStatus returns the error kind of an HTTP status code, or nil if it has none.
+func FromStatus(statusCode int) error {
+	switch statusCode {
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	default:
+		return nil
+	}
+}
+
+// Wrap annotates an API error with the kind of its HTTP status code. It
+// returns nil if err is nil.
+func Wrap(err error, statusCode int) error {
+	if err == nil {
+		return nil
+	}
+	return &StatusError{Kind: FromStatus(statusCode), StatusCode: statusCode, Err: err}
+}
+
+// ValidationError is an invalid argument, detected before any API call is made.
+type ValidationError struct {
+	// Field names the invalid argument, e.g. "repository"
+	Field string
+	// Message describes the problem
+	Message string
+}
+
+// Error implements error.
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Invalid returns a ValidationError for field with a formatted message.
+func Invalid(field, format string, args ...interface{}) error {
+	return &ValidationError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
@@ -0,0 +1,54 @@
+package apierror
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	cause := errors.New("request failed: 404 Not Found")
+
+	tests := []struct {
+		name       string
+		statusCode int
+		kind       error
+	}{
+		{"Not found", http.StatusNotFound, ErrNotFound},
+		{"Unauthorized", http.StatusUnauthorized, ErrUnauthorized},
+		{"Forbidden", http.StatusForbidden, ErrUnauthorized},
+		{"Rate limited", http.StatusTooManyRequests, ErrRateLimited},
+		{"Server error", http.StatusInternalServerError, nil},
+		{"No response", 0, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to get ticket PROJ-1: %w (status: %d)", Wrap(cause, tt.statusCode), tt.statusCode)
+
+			assert.ErrorIs(t, err, cause)
+			for _, kind := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited} {
+				assert.Equal(t, kind == tt.kind, errors.Is(err, kind), kind.Error())
+			}
+
+			var statusErr *StatusError
+			assert.True(t, errors.As(err, &statusErr))
+			assert.Equal(t, tt.statusCode, statusErr.StatusCode)
+			assert.Equal(t, fmt.Sprintf("failed to get ticket PROJ-1: %v (status: %d)", cause, tt.statusCode), err.Error())
+		})
+	}
+
+	assert.Nil(t, Wrap(nil, http.StatusNotFound))
+}
+
+func TestValidationError(t *testing.T) {
+	err := fmt.Errorf("sync failed: %w", Invalid("repository", "invalid repository format: %s", "owner"))
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "repository", validationErr.Field)
+	assert.Equal(t, "sync failed: invalid repository format: owner", err.Error())
+}
@@ -0,0 +1,364 @@
+// Package asana provides functionality for interacting with the Asana API.
+package asana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/httpdebug"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/recorder"
+)
+
+// DefaultBaseURL is the address of the Asana API.
+const DefaultBaseURL = "https://app.asana.com/api/1.0"
+
+// DefaultRequestTimeout bounds a single Asana request.
+const DefaultRequestTimeout = 30 * time.Second
+
+// pageSize is the number of objects fetched per request.
+const pageSize = 100
+
+// Client handles interactions with the Asana API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	// Logger used for this client's output; nil means the default logger
+	logger *slog.Logger
+}
+
+// Project is an Asana project.
+type Project struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+// Section is a section of an Asana project, such as a board column.
+type Section struct {
+	GID  string `json:"gid"`
+	Name string `json:"name"`
+}
+
+// Task is an Asana task.
+type Task struct {
+	GID       string `json:"gid"`
+	Name      string `json:"name"`
+	Notes     string `json:"notes"`
+	Completed bool   `json:"completed"`
+}
+
+// NewClient creates a new Asana client with the token from the configuration.
+func NewClient() (*Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Asana.Token == "" {
+		return nil, errors.New("missing required Asana configuration (ASANA_TOKEN)")
+	}
+
+	base, err := recorder.Wrap(http.DefaultTransport, "asana", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	base, err = faults.Wrap(base, "asana")
+	if err != nil {
+		return nil, err
+	}
+	base = httpdebug.Wrap(base, "asana")
+	if cfg.ReadOnly {
+		logging.Info("asana client is read-only, changes will be refused")
+		base = &readonly.Transport{Base: base}
+	}
+
+	return &Client{
+		baseURL: DefaultBaseURL,
+		http: &http.Client{
+			Timeout:   DefaultRequestTimeout,
+			Transport: &tokenTransport{base: base, token: cfg.Asana.Token},
+		},
+	}, nil
+}
+
+// WithLogger returns a shallow copy of the client that writes its log output
+// to logger.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	copied := *c
+	copied.logger = logger
+	return &copied
+}
+
+// log returns the logger of the client.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
+}
+
+// FindProject returns the project with the GID or, compared
+// case-insensitively, the name, looking through all workspaces of the token.
+// A name shared by projects of several workspaces is an error.
+func (c *Client) FindProject(ctx context.Context, nameOrGID string) (Project, error) {
+	if isGID(nameOrGID) {
+		var project Project
+		if err := c.do(ctx, http.MethodGet, "projects/"+nameOrGID+"?opt_fields=name", nil, &project); err != nil {
+			return Project{}, fmt.Errorf("failed to get project %s: %w", nameOrGID, err)
+		}
+		return project, nil
+	}
+
+	var workspaces []Project
+	if err := c.list(ctx, "workspaces", func(raw json.RawMessage) error {
+		var page []Project
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		workspaces = append(workspaces, page...)
+		return nil
+	}); err != nil {
+		return Project{}, fmt.Errorf("failed to get workspaces: %w", err)
+	}
+
+	var found []Project
+	for _, workspace := range workspaces {
+		path := "projects?archived=false&opt_fields=name&workspace=" + url.QueryEscape(workspace.GID)
+		err := c.list(ctx, path, func(raw json.RawMessage) error {
+			var projects []Project
+			if err := json.Unmarshal(raw, &projects); err != nil {
+				return err
+			}
+			for _, project := range projects {
+				if strings.EqualFold(project.Name, nameOrGID) {
+					found = append(found, project)
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return Project{}, fmt.Errorf("failed to get projects of workspace %s: %w", workspace.Name, err)
+		}
+	}
+
+	switch len(found) {
+	case 0:
+		return Project{}, apierror.Invalid("project", "asana project %q not found", nameOrGID)
+	case 1:
+		return found[0], nil
+	default:
+		gids := make([]string, len(found))
+		for i, project := range found {
+			gids[i] = project.GID
+		}
+		return Project{}, apierror.Invalid("project", "%d asana projects are named %q, use one of their IDs: %s", len(found), nameOrGID, strings.Join(gids, ", "))
+	}
+}
+
+// Sections returns the sections of a project.
+func (c *Client) Sections(ctx context.Context, projectGID string) ([]Section, error) {
+	var sections []Section
+	err := c.list(ctx, fmt.Sprintf("projects/%s/sections?opt_fields=name", projectGID), func(raw json.RawMessage) error {
+		var page []Section
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		sections = append(sections, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get sections of project %s: %w", projectGID, err)
+	}
+	return sections, nil
+}
+
+// Tasks returns the tasks of a project, including completed ones.
+func (c *Client) Tasks(ctx context.Context, projectGID string) ([]Task, error) {
+	var tasks []Task
+	err := c.list(ctx, fmt.Sprintf("projects/%s/tasks?opt_fields=name,notes,completed", projectGID), func(raw json.RawMessage) error {
+		var page []Task
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return err
+		}
+		tasks = append(tasks, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tasks of project %s: %w", projectGID, err)
+	}
+	return tasks, nil
+}
+
+// CreateTask creates a task in a project, in the section with sectionGID
+// unless it is empty.
+func (c *Client) CreateTask(ctx context.Context, projectGID, sectionGID, name, notes string) (Task, error) {
+	data := map[string]interface{}{
+		"name":     name,
+		"notes":    notes,
+		"projects": []string{projectGID},
+	}
+	if sectionGID != "" {
+		data["memberships"] = []map[string]string{{"project": projectGID, "section": sectionGID}}
+	}
+
+	var task Task
+	if err := c.do(ctx, http.MethodPost, "tasks", data, &task); err != nil {
+		return Task{}, fmt.Errorf("failed to create task: %w", err)
+	}
+	c.log().Info("created asana task", "task", task.GID, "name", name)
+	return task, nil
+}
+
+// CompleteTask marks a task as completed.
+func (c *Client) CompleteTask(ctx context.Context, taskGID string) error {
+	if err := c.do(ctx, http.MethodPut, "tasks/"+taskGID, map[string]interface{}{"completed": true}, nil); err != nil {
+		return fmt.Errorf("failed to complete task %s: %w", taskGID, err)
+	}
+	c.log().Info("completed asana task", "task", taskGID)
+	return nil
+}
+
+// list calls fn with the data of each page of a listing.
+func (c *Client) list(ctx context.Context, path string, fn func(json.RawMessage) error) error {
+	separator := "?"
+	if strings.Contains(path, "?") {
+		separator = "&"
+	}
+	offset := ""
+	for {
+		pagePath := fmt.Sprintf("%s%slimit=%d", path, separator, pageSize)
+		if offset != "" {
+			pagePath += "&offset=" + url.QueryEscape(offset)
+		}
+
+		var page struct {
+			Data     json.RawMessage `json:"data"`
+			NextPage *struct {
+				Offset string `json:"offset"`
+			} `json:"next_page"`
+		}
+		if err := c.send(ctx, http.MethodGet, pagePath, nil, &page); err != nil {
+			return err
+		}
+		if err := fn(page.Data); err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if page.NextPage == nil || page.NextPage.Offset == "" {
+			return nil
+		}
+		offset = page.NextPage.Offset
+	}
+}
+
+// do sends a request with data, if any, wrapped in the API's data envelope,
+// and decodes the data of the response into out, unless it is nil.
+func (c *Client) do(ctx context.Context, method, path string, data interface{}, out interface{}) error {
+	var body interface{}
+	if data != nil {
+		body = map[string]interface{}{"data": data}
+	}
+	var envelope struct {
+		Data json.RawMessage `json:"data"`
+	}
+	if err := c.send(ctx, method, path, body, &envelope); err != nil {
+		return err
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Data, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// send sends a request with body encoded as JSON and decodes the response
+// into out. Error responses return the messages Asana gives.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.baseURL, "/")+"/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apierror.Wrap(err, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		var failure struct {
+			Errors []struct {
+				Message string `json:"message"`
+			} `json:"errors"`
+		}
+		raw, _ := io.ReadAll(resp.Body)
+		var messages []string
+		if json.Unmarshal(raw, &failure) == nil {
+			for _, e := range failure.Errors {
+				messages = append(messages, e.Message)
+			}
+		}
+		if len(messages) == 0 {
+			messages = append(messages, strings.TrimSpace(string(raw)))
+		}
+		err := fmt.Errorf("%s %s: %s", method, req.URL.Path, strings.Join(messages, "; "))
+		return fmt.Errorf("%w (status: %d)", apierror.Wrap(err, resp.StatusCode), resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// isGID reports whether s is an Asana object ID, which is all digits.
+func isGID(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenTransport authenticates requests with a bearer token.
+type tokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,131 @@
+package asana
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeAsana serves two workspaces with a project each, a project whose tasks
+// span two pages, and records the created and updated tasks.
+type fakeAsana struct {
+	created   map[string]interface{}
+	completed string
+}
+
+func (f *fakeAsana) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/workspaces":
+		fmt.Fprint(w, `{"data":[{"gid":"1","name":"Acme"},{"gid":"2","name":"Labs"}],"next_page":null}`)
+	case r.URL.Path == "/projects" && r.URL.Query().Get("workspace") == "1":
+		fmt.Fprint(w, `{"data":[{"gid":"11","name":"Roadmap"},{"gid":"12","name":"Hiring"}],"next_page":null}`)
+	case r.URL.Path == "/projects" && r.URL.Query().Get("workspace") == "2":
+		fmt.Fprint(w, `{"data":[{"gid":"21","name":"Hiring"}],"next_page":null}`)
+	case r.URL.Path == "/projects/11":
+		fmt.Fprint(w, `{"data":{"gid":"11","name":"Roadmap"}}`)
+	case r.URL.Path == "/projects/11/sections":
+		fmt.Fprint(w, `{"data":[{"gid":"101","name":"Bugs"},{"gid":"102","name":"Ideas"}],"next_page":null}`)
+	case r.URL.Path == "/projects/11/tasks" && r.URL.Query().Get("offset") == "":
+		fmt.Fprint(w, `{"data":[{"gid":"1001","name":"First","notes":"","completed":false}],"next_page":{"offset":"abc"}}`)
+	case r.URL.Path == "/projects/11/tasks":
+		fmt.Fprint(w, `{"data":[{"gid":"1002","name":"Second","notes":"","completed":true}],"next_page":null}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/tasks":
+		var body struct {
+			Data map[string]interface{} `json:"data"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body.Data["name"] == "" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errors":[{"message":"name: Missing input"}]}`)
+			return
+		}
+		f.created = body.Data
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"data":{"gid":"1003","name":%q}}`, body.Data["name"])
+	case r.Method == http.MethodPut && r.URL.Path == "/tasks/1001":
+		f.completed = "1001"
+		fmt.Fprint(w, `{"data":{"gid":"1001","completed":true}}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"errors":[{"message":"Not Found"}]}`)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{baseURL: server.URL, http: server.Client()}
+}
+
+func TestFindProject(t *testing.T) {
+	client := newTestClient(t, &fakeAsana{})
+	ctx := context.Background()
+
+	project, err := client.FindProject(ctx, "roadmap")
+	require.NoError(t, err)
+	assert.Equal(t, Project{GID: "11", Name: "Roadmap"}, project)
+
+	project, err = client.FindProject(ctx, "11")
+	require.NoError(t, err)
+	assert.Equal(t, "Roadmap", project.Name)
+
+	_, err = client.FindProject(ctx, "Hiring")
+	var validation *apierror.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Contains(t, err.Error(), "use one of their IDs: 12, 21")
+
+	_, err = client.FindProject(ctx, "Marketing")
+	assert.ErrorContains(t, err, `asana project "Marketing" not found`)
+}
+
+func TestSectionsAndTasks(t *testing.T) {
+	client := newTestClient(t, &fakeAsana{})
+	ctx := context.Background()
+
+	sections, err := client.Sections(ctx, "11")
+	require.NoError(t, err)
+	assert.Equal(t, []Section{{GID: "101", Name: "Bugs"}, {GID: "102", Name: "Ideas"}}, sections)
+
+	tasks, err := client.Tasks(ctx, "11")
+	require.NoError(t, err)
+	assert.Equal(t, []Task{{GID: "1001", Name: "First"}, {GID: "1002", Name: "Second", Completed: true}}, tasks)
+}
+
+func TestCreateAndCompleteTask(t *testing.T) {
+	asana := &fakeAsana{}
+	client := newTestClient(t, asana)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, "11", "101", "Fix login", "GitHub issue: https://github.com/owner/repo/issues/1")
+	require.NoError(t, err)
+	assert.Equal(t, "1003", task.GID)
+	assert.Equal(t, []interface{}{"11"}, asana.created["projects"])
+	assert.Equal(t, []interface{}{map[string]interface{}{"project": "11", "section": "101"}}, asana.created["memberships"])
+
+	_, err = client.CreateTask(ctx, "11", "", "Unsorted", "")
+	require.NoError(t, err)
+	assert.NotContains(t, asana.created, "memberships")
+
+	require.NoError(t, client.CompleteTask(ctx, "1001"))
+	assert.Equal(t, "1001", asana.completed)
+}
+
+func TestClientErrors(t *testing.T) {
+	client := newTestClient(t, &fakeAsana{})
+	ctx := context.Background()
+
+	_, err := client.CreateTask(ctx, "11", "", "", "")
+	assert.ErrorContains(t, err, "name: Missing input (status: 400)")
+
+	err = client.CompleteTask(ctx, "404")
+	assert.ErrorContains(t, err, "Not Found (status: 404)")
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
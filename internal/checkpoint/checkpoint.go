@@ -0,0 +1,160 @@
+// Package checkpoint persists the progress of long-running glue operations so
+// an interrupted run can resume where it stopped.
+package checkpoint
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// Checkpoint records the progress of an operation on a repository and board.
+type Checkpoint struct {
+	// Operation names the operation, e.g. "backfill"
+	Operation string `json:"operation"`
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	// Board is the JIRA project key
+	Board string `json:"board"`
+	// LastIssue is the highest GitHub issue number processed so far
+	LastIssue int `json:"last_issue"`
+	// Created is the number of tickets created across all runs
+	Created int `json:"created"`
+	// Failed lists the issues whose processing failed and should be retried
+	Failed []int `json:"failed,omitempty"`
+	// Runs is the number of runs that contributed to this checkpoint
+	Runs int `json:"runs"`
+	// UpdatedAt is when the checkpoint was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+
+	path string
+}
+
+// DefaultDir returns the directory where checkpoints are stored, in glue's
+// cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "checkpoints")
+}
+
+// Load reads the checkpoint of an operation from dir. If none exists, a new
+// empty checkpoint is returned. It returns an error if the file exists but
+// cannot be read or parsed.
+func Load(dir, operation, repository, board string) (*Checkpoint, error) {
+	path := filepath.Join(dir, fileName(operation, repository, board))
+	cp := &Checkpoint{Operation: operation, Repository: repository, Board: board, path: path}
+
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return cp, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint: %v", err)
+	}
+
+	if err := json.Unmarshal(data, cp); err != nil {
+		return nil, fmt.Errorf("failed to parse checkpoint %s: %v", path, err)
+	}
+	return cp, nil
+}
+
+// Path returns the file the checkpoint is stored in.
+func (c *Checkpoint) Path() string {
+	return c.path
+}
+
+// Save writes the checkpoint. The file is replaced atomically so a crash
+// during the write never leaves a truncated checkpoint behind.
+func (c *Checkpoint) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create checkpoint directory: %v", err)
+	}
+
+	c.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode checkpoint: %v", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write checkpoint: %v", err)
+	}
+	return nil
+}
+
+// Remove deletes the checkpoint file. A missing file is not an error.
+func (c *Checkpoint) Remove() error {
+	if err := os.Remove(c.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove checkpoint: %v", err)
+	}
+	return nil
+}
+
+// Move moves the checkpoints of repository from, of every operation and
+// board, to repository to, e.g. after the repository was renamed on GitHub.
+// It returns the number of checkpoints moved. A checkpoint that exists under
+// the new name already is an error.
+func Move(dir, from, to string) (int, error) {
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read checkpoint directory: %v", err)
+	}
+
+	moved := 0
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return moved, fmt.Errorf("failed to read checkpoint: %v", err)
+		}
+		var cp Checkpoint
+		if err := json.Unmarshal(data, &cp); err != nil || !strings.EqualFold(cp.Repository, from) {
+			continue
+		}
+
+		old, err := Load(dir, cp.Operation, from, cp.Board)
+		if err != nil {
+			return moved, err
+		}
+		target := filepath.Join(dir, fileName(cp.Operation, to, cp.Board))
+		if target != old.path {
+			if _, err := os.Stat(target); err == nil {
+				return moved, fmt.Errorf("%s checkpoint of %s and %s already exists: %w", cp.Operation, to, cp.Board, os.ErrExist)
+			}
+		}
+
+		oldPath := old.path
+		old.Repository, old.path = to, target
+		if err := old.Save(); err != nil {
+			return moved, err
+		}
+		if target != oldPath {
+			if err := os.Remove(oldPath); err != nil {
+				return moved, fmt.Errorf("failed to remove checkpoint: %v", err)
+			}
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// fileName converts an operation, repository and board into a checkpoint file
+// name, e.g. "backfill_owner_repo_proj.json".
+func fileName(operation, repository, board string) string {
+	return paths.FileName(operation, repository, board) + ".json"
+}
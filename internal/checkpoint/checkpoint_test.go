@@ -0,0 +1,96 @@
+package checkpoint
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	cp, err := Load(dir, "backfill", "owner/repo", "PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, "backfill", cp.Operation)
+	assert.Equal(t, "owner/repo", cp.Repository)
+	assert.Equal(t, "PROJ", cp.Board)
+	assert.Zero(t, cp.LastIssue)
+	assert.Equal(t, filepath.Join(dir, "backfill_owner_repo_proj.json"), cp.Path())
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+
+	cp, err := Load(dir, "backfill", "owner/repo", "PROJ")
+	require.NoError(t, err)
+	cp.LastIssue = 120
+	cp.Created = 95
+	cp.Failed = []int{17, 42}
+	cp.Runs = 2
+	require.NoError(t, cp.Save())
+
+	loaded, err := Load(dir, "backfill", "owner/repo", "PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, 120, loaded.LastIssue)
+	assert.Equal(t, 95, loaded.Created)
+	assert.Equal(t, []int{17, 42}, loaded.Failed)
+	assert.Equal(t, 2, loaded.Runs)
+	assert.False(t, loaded.UpdatedAt.IsZero())
+
+	_, err = os.Stat(cp.Path() + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, loaded.Remove())
+	require.NoError(t, loaded.Remove())
+	_, err = os.Stat(cp.Path())
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "backfill_owner_repo_proj.json"), []byte("{"), 0o644))
+
+	_, err := Load(dir, "backfill", "owner/repo", "PROJ")
+	assert.Error(t, err)
+}
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+
+	moved, err := Move(filepath.Join(dir, "missing"), "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+
+	for _, board := range []string{"PROJ", "OPS"} {
+		cp, err := Load(dir, "backfill", "owner/old", board)
+		require.NoError(t, err)
+		cp.LastIssue = 7
+		require.NoError(t, cp.Save())
+	}
+	other, err := Load(dir, "backfill", "owner/other", "PROJ")
+	require.NoError(t, err)
+	require.NoError(t, other.Save())
+
+	moved, err = Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	cp, err := Load(dir, "backfill", "owner/new", "OPS")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/new", cp.Repository)
+	assert.Equal(t, 7, cp.LastIssue)
+	_, err = os.Stat(filepath.Join(dir, "backfill_owner_old_proj.json"))
+	assert.True(t, os.IsNotExist(err))
+	_, err = os.Stat(other.Path())
+	assert.NoError(t, err)
+
+	// Checkpoints of both names are never merged
+	stale, err := Load(dir, "backfill", "owner/old", "PROJ")
+	require.NoError(t, err)
+	require.NoError(t, stale.Save())
+	_, err = Move(dir, "owner/old", "owner/new")
+	assert.ErrorIs(t, err, os.ErrExist)
+}
@@ -0,0 +1,230 @@
+// Package clickup provides functionality for interacting with the ClickUp API.
+package clickup
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/httpdebug"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/recorder"
+)
+
+// DefaultBaseURL is the address of the ClickUp API.
+const DefaultBaseURL = "https://api.clickup.com/api/v2"
+
+// DefaultRequestTimeout bounds a single ClickUp request.
+const DefaultRequestTimeout = 30 * time.Second
+
+// Client handles interactions with the ClickUp API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	// Logger used for this client's output; nil means the default logger
+	logger *slog.Logger
+}
+
+// Task is a ClickUp task.
+type Task struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	// Parent is the ID of the task this is a subtask of, if any
+	Parent string     `json:"parent"`
+	Status TaskStatus `json:"status"`
+}
+
+// TaskStatus is the status of a task in its list's workflow.
+type TaskStatus struct {
+	// Status is the name of the status, e.g. "in progress"
+	Status string `json:"status"`
+	// Type is "open", "custom", "done" or "closed"
+	Type string `json:"type"`
+}
+
+// Closed reports whether the task is done or closed.
+func (t Task) Closed() bool {
+	return t.Status.Type == "closed" || t.Status.Type == "done"
+}
+
+// NewTask holds the fields of a task to create.
+type NewTask struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	// Parent makes the task a subtask of the task with this ID
+	Parent string `json:"parent,omitempty"`
+}
+
+// NewClient creates a new ClickUp client with the token from the
+// configuration.
+func NewClient() (*Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.ClickUp.Token == "" {
+		return nil, errors.New("missing required ClickUp configuration (CLICKUP_TOKEN)")
+	}
+
+	base, err := recorder.Wrap(http.DefaultTransport, "clickup", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	base, err = faults.Wrap(base, "clickup")
+	if err != nil {
+		return nil, err
+	}
+	base = httpdebug.Wrap(base, "clickup")
+	if cfg.ReadOnly {
+		logging.Info("clickup client is read-only, changes will be refused")
+		base = &readonly.Transport{Base: base}
+	}
+
+	return &Client{
+		baseURL: DefaultBaseURL,
+		http: &http.Client{
+			Timeout:   DefaultRequestTimeout,
+			Transport: &tokenTransport{base: base, token: cfg.ClickUp.Token},
+		},
+	}, nil
+}
+
+// WithLogger returns a shallow copy of the client that writes its log output
+// to logger.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	copied := *c
+	copied.logger = logger
+	return &copied
+}
+
+// log returns the logger of the client.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
+}
+
+// Tasks returns the tasks and subtasks of a list, including closed ones.
+func (c *Client) Tasks(ctx context.Context, listID string) ([]Task, error) {
+	var tasks []Task
+	for page := 0; ; page++ {
+		var result struct {
+			Tasks    []Task `json:"tasks"`
+			LastPage bool   `json:"last_page"`
+		}
+		path := fmt.Sprintf("list/%s/task?include_closed=true&subtasks=true&page=%d", listID, page)
+		if err := c.send(ctx, http.MethodGet, path, nil, &result); err != nil {
+			return nil, fmt.Errorf("failed to get tasks of list %s: %w", listID, err)
+		}
+		tasks = append(tasks, result.Tasks...)
+		if result.LastPage || len(result.Tasks) == 0 {
+			return tasks, nil
+		}
+	}
+}
+
+// CreateTask creates a task in a list.
+func (c *Client) CreateTask(ctx context.Context, listID string, task NewTask) (Task, error) {
+	var created Task
+	if err := c.send(ctx, http.MethodPost, fmt.Sprintf("list/%s/task", listID), task, &created); err != nil {
+		return Task{}, fmt.Errorf("failed to create task in list %s: %w", listID, err)
+	}
+	c.log().Info("created clickup task", "task", created.ID, "list", listID, "name", task.Name)
+	return created, nil
+}
+
+// SetStatus moves a task to the status with the name, which must exist in
+// the workflow of its list.
+func (c *Client) SetStatus(ctx context.Context, taskID, status string) error {
+	if err := c.send(ctx, http.MethodPut, "task/"+taskID, map[string]string{"status": status}, &Task{}); err != nil {
+		return fmt.Errorf("failed to set status of task %s to %q: %w", taskID, status, err)
+	}
+	c.log().Info("set clickup task status", "task", taskID, "status", status)
+	return nil
+}
+
+// SetParent makes a task a subtask of the task with parentID.
+func (c *Client) SetParent(ctx context.Context, taskID, parentID string) error {
+	if err := c.send(ctx, http.MethodPut, "task/"+taskID, map[string]string{"parent": parentID}, &Task{}); err != nil {
+		return fmt.Errorf("failed to make task %s a subtask of %s: %w", taskID, parentID, err)
+	}
+	c.log().Info("linked clickup subtask", "task", taskID, "parent", parentID)
+	return nil
+}
+
+// send sends a request with body encoded as JSON and decodes the response
+// into out. Error responses return the message ClickUp gives.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.baseURL, "/")+"/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apierror.Wrap(err, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(resp.Body)
+		var failure struct {
+			Err  string `json:"err"`
+			Code string `json:"ECODE"`
+		}
+		message := strings.TrimSpace(string(raw))
+		if json.Unmarshal(raw, &failure) == nil && failure.Err != "" {
+			message = failure.Err
+			if failure.Code != "" {
+				message += " (" + failure.Code + ")"
+			}
+		}
+		err := fmt.Errorf("%s %s: %s", method, req.URL.Path, message)
+		return fmt.Errorf("%w (status: %d)", apierror.Wrap(err, resp.StatusCode), resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// tokenTransport authenticates requests with a personal API token, which
+// ClickUp expects without a scheme.
+type tokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", t.token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,117 @@
+package clickup
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeClickUp serves a list whose tasks span two pages and records the
+// created and updated tasks.
+type fakeClickUp struct {
+	created map[string]interface{}
+	updated map[string]map[string]interface{}
+}
+
+func (f *fakeClickUp) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/list/901/task" && r.URL.Query().Get("page") == "0":
+		if r.URL.Query().Get("include_closed") != "true" || r.URL.Query().Get("subtasks") != "true" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		fmt.Fprint(w, `{"tasks":[{"id":"a1","name":"First","status":{"status":"to do","type":"open"}}],"last_page":false}`)
+	case r.Method == http.MethodGet && r.URL.Path == "/list/901/task":
+		fmt.Fprint(w, `{"tasks":[{"id":"a2","name":"Second","parent":"a1","status":{"status":"complete","type":"closed"}}],"last_page":true}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/list/901/task":
+		json.NewDecoder(r.Body).Decode(&f.created)
+		fmt.Fprintf(w, `{"id":"a3","name":%q,"status":{"status":"to do","type":"open"}}`, f.created["name"])
+	case r.Method == http.MethodPut && r.URL.Path == "/task/a1":
+		var body map[string]interface{}
+		json.NewDecoder(r.Body).Decode(&body)
+		if body["status"] == "shipped" {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"err":"Status does not exist","ECODE":"ITEM_114"}`)
+			return
+		}
+		if f.updated == nil {
+			f.updated = make(map[string]map[string]interface{})
+		}
+		f.updated["a1"] = body
+		fmt.Fprint(w, `{"id":"a1"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"err":"Task not found","ECODE":"ITEM_013"}`)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{baseURL: server.URL, http: server.Client()}
+}
+
+func TestTasks(t *testing.T) {
+	client := newTestClient(t, &fakeClickUp{})
+
+	tasks, err := client.Tasks(context.Background(), "901")
+	require.NoError(t, err)
+	assert.Equal(t, []Task{
+		{ID: "a1", Name: "First", Status: TaskStatus{Status: "to do", Type: "open"}},
+		{ID: "a2", Name: "Second", Parent: "a1", Status: TaskStatus{Status: "complete", Type: "closed"}},
+	}, tasks)
+	assert.False(t, tasks[0].Closed())
+	assert.True(t, tasks[1].Closed())
+	assert.True(t, Task{Status: TaskStatus{Type: "done"}}.Closed())
+}
+
+func TestCreateTaskAndUpdates(t *testing.T) {
+	clickup := &fakeClickUp{}
+	client := newTestClient(t, clickup)
+	ctx := context.Background()
+
+	task, err := client.CreateTask(ctx, "901", NewTask{Name: "Fix login", Parent: "a1"})
+	require.NoError(t, err)
+	assert.Equal(t, "a3", task.ID)
+	assert.Equal(t, map[string]interface{}{"name": "Fix login", "parent": "a1"}, clickup.created)
+
+	require.NoError(t, client.SetStatus(ctx, "a1", "complete"))
+	assert.Equal(t, map[string]interface{}{"status": "complete"}, clickup.updated["a1"])
+
+	require.NoError(t, client.SetParent(ctx, "a1", "a0"))
+	assert.Equal(t, map[string]interface{}{"parent": "a0"}, clickup.updated["a1"])
+}
+
+func TestClientErrors(t *testing.T) {
+	client := newTestClient(t, &fakeClickUp{})
+	ctx := context.Background()
+
+	err := client.SetStatus(ctx, "a1", "shipped")
+	assert.ErrorContains(t, err, "Status does not exist (ITEM_114) (status: 400)")
+
+	err = client.SetParent(ctx, "a9", "a1")
+	assert.ErrorContains(t, err, "Task not found (ITEM_013) (status: 404)")
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+func TestTokenTransport(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `{"tasks":[],"last_page":true}`)
+	}))
+	t.Cleanup(server.Close)
+	client := &Client{baseURL: server.URL, http: &http.Client{Transport: &tokenTransport{base: http.DefaultTransport, token: "pk_1"}}}
+
+	_, err := client.Tasks(context.Background(), "901")
+	require.NoError(t, err)
+	assert.Equal(t, "pk_1", authorization)
+}
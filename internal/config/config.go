@@ -3,31 +3,663 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
 	"strings"
+	"time"
 
+	"github.com/danielolaszy/glue/internal/maintenance"
+	"github.com/danielolaszy/glue/internal/paths"
 	"github.com/spf13/viper"
 )
 
-// Config holds all configuration parameters for the application.
+// ConfigFileName is the name of the optional configuration file.
+const ConfigFileName = "glue.yaml"
+
+// Config holds all configuration parameters for the application. The
+// mapstructure tags name the matching config file keys.
 type Config struct {
-	GitHub GitHubConfig
-	Jira   JiraConfig
+	GitHub GitHubConfig `mapstructure:"github"`
+	Jira   JiraConfig   `mapstructure:"jira"`
+	Users  UserMappings `mapstructure:"users"`
+	Hooks  HooksConfig  `mapstructure:"hooks"`
+	Rules  RulesConfig  `mapstructure:"rules"`
+	Safety SafetyConfig `mapstructure:"safety"`
+	// Routes send issues to boards by label or referenced path, before the
+	// board labels are considered
+	Routes []Route `mapstructure:"routes"`
+	// Asana configures 'glue asana', which creates Asana tasks for issues
+	Asana AsanaConfig `mapstructure:"asana"`
+	// Notion configures 'glue notion', which mirrors issues to a Notion database
+	Notion NotionConfig `mapstructure:"notion"`
+	// ClickUp configures 'glue clickup', which creates ClickUp tasks for issues
+	ClickUp ClickUpConfig `mapstructure:"clickup"`
+	// YouTrack configures 'glue youtrack', which creates YouTrack issues
+	YouTrack YouTrackConfig `mapstructure:"youtrack"`
+	// Outbound configures 'glue outbound', which posts issue events to an
+	// endpoint of your own
+	Outbound OutboundConfig `mapstructure:"outbound"`
+	// Security configures the tickets created for GitHub security alerts
+	Security SecurityConfig `mapstructure:"security"`
+	// SyncedLabels changes the labels of GitHub issues once they have a ticket
+	SyncedLabels SyncedLabels `mapstructure:"synced_labels"`
+	// FormFields maps issue form sections to JIRA fields
+	FormFields []FormField `mapstructure:"form_fields"`
+	// AcceptanceCriteria mirrors the acceptance criteria checklist to a JIRA field
+	AcceptanceCriteria ChecklistConfig `mapstructure:"acceptance_criteria"`
+	// Description selects the sections JIRA descriptions are composed of
+	Description DescriptionConfig `mapstructure:"description"`
+	// RequiredFields holds default values, by field ID, for fields a JIRA
+	// project requires on creation that glue doesn't set otherwise
+	RequiredFields map[string]interface{} `mapstructure:"required_fields"`
+	// MaintenanceWindows are recurring periods during which glue makes no
+	// changes to JIRA
+	MaintenanceWindows []MaintenanceWindow `mapstructure:"maintenance_windows"`
+	// ReadOnly makes the clients refuse every request that would change
+	// GitHub or JIRA
+	ReadOnly bool `mapstructure:"read_only"`
+	// Record is a directory to save sanitized copies of all API requests and
+	// responses to
+	Record string `mapstructure:"record"`
+	// Replay is a directory of recorded responses to serve instead of calling
+	// the APIs
+	Replay string `mapstructure:"replay"`
+	// Profile is the name of the selected profile, if any
+	Profile string `mapstructure:"profile"`
+	// Boards are the JIRA project keys to use when no --board is given
+	Boards []string `mapstructure:"boards"`
+	// BoardAliases are friendly names usable instead of JIRA project keys in
+	// --board and board labels
+	BoardAliases BoardAliases `mapstructure:"board_aliases"`
+	// Flags holds default values for command-line flags, by flag name
+	Flags map[string]interface{} `mapstructure:"flags"`
 }
 
 // GitHubConfig holds GitHub specific configuration.
 type GitHubConfig struct {
-	Domain string // Just the domain name (e.g., "github.com" or "git.example.com")
-	Token  string
+	Domain string `mapstructure:"domain"` // Just the domain name (e.g., "github.com" or "git.example.com")
+	Token  string `mapstructure:"token"`
 }
 
 // JiraConfig holds JIRA specific configuration.
 type JiraConfig struct {
-	BaseURL  string
-	Username string
-	Token    string
+	BaseURL  string `mapstructure:"baseurl"`
+	Username string `mapstructure:"username"`
+	Token    string `mapstructure:"token"`
+	// Timeout bounds a single JIRA request; zero uses the client default
+	Timeout time.Duration `mapstructure:"timeout"`
+	// MaxConsecutiveFailures trips the circuit breaker; zero uses the client
+	// default and a negative value disables it
+	MaxConsecutiveFailures int `mapstructure:"max_consecutive_failures"`
+	// CloseTransitions are the names or IDs of transitions that close
+	// tickets, in order of preference; empty uses Done, Close, Closed,
+	// Resolve and Resolved
+	CloseTransitions []string `mapstructure:"close_transitions"`
+	// BoardCloseTransitions overrides CloseTransitions for some boards, by
+	// project key
+	BoardCloseTransitions map[string][]string `mapstructure:"board_close_transitions"`
+	// RequestTypes are the names or IDs of the request types tickets are
+	// created with in JIRA Service Management projects, by project key
+	RequestTypes map[string]string `mapstructure:"request_types"`
+	// MultipleParents is the policy for stories listed by several features,
+	// one of the MultipleParents constants; empty means link-to-all
+	MultipleParents string `mapstructure:"multiple_parents"`
+	// LinkBatchSize is the number of parent-child link changes made between
+	// progress reports and pauses; zero uses DefaultLinkBatchSize
+	LinkBatchSize int `mapstructure:"link_batch_size"`
+	// LinkBatchPause is waited between batches of link changes
+	LinkBatchPause time.Duration `mapstructure:"link_batch_pause"`
+	// MaxLinkChanges caps the parent-child links created and removed per
+	// run; zero means no limit
+	MaxLinkChanges int `mapstructure:"max_link_changes"`
+}
+
+// DefaultLinkBatchSize is the number of link changes per batch when none is
+// configured.
+const DefaultLinkBatchSize = 50
+
+// Policies for stories listed in the '## Issues' section of several features.
+const (
+	// MultipleParentsLinkAll links the story to every feature listing it
+	MultipleParentsLinkAll = "link-to-all"
+	// MultipleParentsFirstWins links the story to the feature with the
+	// lowest issue number only
+	MultipleParentsFirstWins = "first-wins"
+	// MultipleParentsWarnAndSkip links the story to none of the features and
+	// logs a warning, until only one lists it
+	MultipleParentsWarnAndSkip = "warn-and-skip"
+)
+
+// AsanaConfig holds Asana specific configuration.
+type AsanaConfig struct {
+	// Token is a personal access token or service account token
+	Token string `mapstructure:"token"`
+	// Sections maps GitHub labels to the names of the project sections their
+	// tasks are created in
+	Sections map[string]string `mapstructure:"sections"`
+}
+
+// Section returns the name of the section configured for the first of labels
+// that has one, or an empty string. Labels are compared case-insensitively.
+func (a AsanaConfig) Section(labels []string) string {
+	for _, label := range labels {
+		for configured, section := range a.Sections {
+			if strings.EqualFold(label, configured) {
+				return section
+			}
+		}
+	}
+	return ""
+}
+
+// DefaultClickUpClosedStatus and DefaultClickUpOpenStatus are the ClickUp
+// statuses of the tasks of closed and reopened issues when the config file
+// doesn't name others.
+const (
+	DefaultClickUpClosedStatus = "complete"
+	DefaultClickUpOpenStatus   = "to do"
+)
+
+// ClickUpConfig holds ClickUp specific configuration.
+type ClickUpConfig struct {
+	// Token is a personal API token
+	Token string `mapstructure:"token"`
+	// Lists maps GitHub labels to the IDs of the lists their tasks are
+	// created in
+	Lists map[string]string `mapstructure:"lists"`
+	// ClosedStatus is the status tasks are moved to when their issue is closed
+	ClosedStatus string `mapstructure:"closed_status"`
+	// OpenStatus is the status closed tasks are moved to when their issue is
+	// reopened
+	OpenStatus string `mapstructure:"open_status"`
+}
+
+// List returns the ID of the list configured for the first of labels that
+// has one, or an empty string. Labels are compared case-insensitively.
+func (c ClickUpConfig) List(labels []string) string {
+	for _, label := range labels {
+		for configured, list := range c.Lists {
+			if strings.EqualFold(label, configured) {
+				return list
+			}
+		}
+	}
+	return ""
+}
+
+// OutboundConfig holds the endpoint 'glue outbound' posts issue events to.
+type OutboundConfig struct {
+	// URL receives the events in POST requests
+	URL string `mapstructure:"url"`
+	// Secret signs the requests with an HMAC, like hook requests, if set
+	Secret string `mapstructure:"secret"`
+	// Headers are added to every request, e.g. for authentication
+	Headers map[string]string `mapstructure:"headers"`
+	// Template is a Go template rendering the request body from the event;
+	// empty sends the event as JSON
+	Template string `mapstructure:"template"`
+	// ContentType is the Content-Type of the rendered body; empty means
+	// application/json
+	ContentType string `mapstructure:"content_type"`
+	// Timeout bounds a single request; zero uses the client default
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// DefaultYouTrackResolvedState and DefaultYouTrackOpenState are the states of
+// the YouTrack issues of closed and reopened GitHub issues when the config
+// file doesn't name others.
+const (
+	DefaultYouTrackResolvedState = "Fixed"
+	DefaultYouTrackOpenState     = "Open"
+)
+
+// YouTrackConfig holds YouTrack specific configuration.
+type YouTrackConfig struct {
+	// BaseURL is the address of the YouTrack instance, e.g.
+	// https://example.youtrack.cloud
+	BaseURL string `mapstructure:"baseurl"`
+	// Token is a permanent token
+	Token string `mapstructure:"token"`
+	// Fields maps GitHub labels to the custom field values, by field name,
+	// the YouTrack issues of issues with the label get
+	Fields map[string]map[string]string `mapstructure:"fields"`
+	// ResolvedState is the state issues are moved to when their GitHub issue
+	// is closed
+	ResolvedState string `mapstructure:"resolved_state"`
+	// OpenState is the state resolved issues are moved to when their GitHub
+	// issue is reopened
+	OpenState string `mapstructure:"open_state"`
+}
+
+// FieldValues returns the custom field values for an issue with labels. When
+// several labels set a field, the first of them wins. Labels are compared
+// case-insensitively.
+func (c YouTrackConfig) FieldValues(labels []string) map[string]string {
+	values := make(map[string]string)
+	for _, label := range labels {
+		for configured, fields := range c.Fields {
+			if !strings.EqualFold(label, configured) {
+				continue
+			}
+			for field, value := range fields {
+				if _, ok := values[field]; !ok {
+					values[field] = value
+				}
+			}
+		}
+	}
+	return values
+}
+
+// NotionConfig holds Notion specific configuration.
+type NotionConfig struct {
+	// Token is the secret of the Notion integration the database is shared with
+	Token string `mapstructure:"token"`
+	// Database is the ID of the database rows are written to
+	Database string `mapstructure:"database"`
+	// Properties names the database properties the values are written to
+	Properties NotionProperties `mapstructure:"properties"`
+}
+
+// NotionProperties are the names of the Notion database properties glue
+// writes. Empty names use the defaults.
+type NotionProperties struct {
+	// Title holds the issue title; the database's title property by default
+	Title string `mapstructure:"title"`
+	// Status holds the JIRA status, or the GitHub state for issues without a
+	// ticket; "Status" by default
+	Status string `mapstructure:"status"`
+	// URL holds the address of the issue, which identifies its row; "GitHub"
+	// by default
+	URL string `mapstructure:"url"`
+	// Key holds the JIRA key; "JIRA Key" by default
+	Key string `mapstructure:"key"`
+}
+
+// HooksConfig holds the hooks run around synchronization.
+type HooksConfig struct {
+	// PostCreate runs after tickets are created on a board
+	PostCreate []PostCreateHook `mapstructure:"post_create"`
+	// PreSync runs before any issue is processed; a failure aborts the run
+	PreSync []ExecHook `mapstructure:"pre_sync"`
+	// PostIssue runs after each issue a ticket creation was attempted for
+	PostIssue []ExecHook `mapstructure:"post_issue"`
+	// PostRun runs once the synchronization has finished
+	PostRun []ExecHook `mapstructure:"post_run"`
+}
+
+// ExecHook is a command run with a JSON payload describing the event on
+// stdin, or a URL the payload is posted to.
+type ExecHook struct {
+	// Name identifies the hook in logs
+	Name string `mapstructure:"name"`
+	// Command is the program and its arguments; it is not run through a shell
+	Command []string `mapstructure:"command"`
+	// URL receives the payload in a POST request instead of a command
+	URL string `mapstructure:"url"`
+	// Secret signs the requests to URL with an HMAC, if set
+	Secret string `mapstructure:"secret"`
+	// Timeout bounds the command's run time or the request (e.g. "30s")
+	Timeout time.Duration `mapstructure:"timeout"`
+}
+
+// PostCreateHook updates fields of the tickets created by a run that match a
+// JQL query.
+type PostCreateHook struct {
+	// Name identifies the hook in logs
+	Name string `mapstructure:"name"`
+	// JQL restricts the hook to matching tickets; empty matches every new ticket
+	JQL string `mapstructure:"jql"`
+	// Fields maps JIRA field IDs to values in their REST representation
+	Fields map[string]interface{} `mapstructure:"fields"`
+}
+
+// RulesConfig holds the Starlark rules script deciding how issues are mapped.
+type RulesConfig struct {
+	// Script is the inline source of the rules script
+	Script string `mapstructure:"script"`
+	// File is the path of the rules script, used if Script is empty
+	File string `mapstructure:"file"`
+}
+
+// DefaultSecurityPriorities maps alert severities to the JIRA priorities of
+// their tickets when the config file doesn't.
+var DefaultSecurityPriorities = map[string]string{
+	"critical": "Highest",
+	"high":     "High",
+	"medium":   "Medium",
+	"low":      "Low",
+}
+
+// SecurityConfig holds the settings of 'glue jira security', which creates
+// tickets for open Dependabot and code scanning alerts.
+type SecurityConfig struct {
+	// IssueType is the JIRA issue type of the tickets; empty uses Story
+	IssueType string `mapstructure:"issue_type"`
+	// Priorities maps alert severities (critical, high, medium, low) to JIRA
+	// priority names, overriding DefaultSecurityPriorities; an empty name
+	// leaves the priority to JIRA's default
+	Priorities map[string]string `mapstructure:"priorities"`
+}
+
+// Priority returns the JIRA priority name for an alert severity, or "" to
+// use JIRA's default.
+func (s SecurityConfig) Priority(severity string) string {
+	severity = strings.ToLower(severity)
+	if priority, ok := s.Priorities[severity]; ok {
+		return priority
+	}
+	return DefaultSecurityPriorities[severity]
+}
+
+// FormField maps a section of GitHub issues written with issue forms or
+// templates, i.e. a "### Heading" and the text below it, to a JIRA field.
+type FormField struct {
+	// Heading is the section heading, compared case-insensitively
+	Heading string `mapstructure:"heading"`
+	// Field is the JIRA field ID, e.g. "customfield_10020" or "environment"
+	Field string `mapstructure:"field"`
+	// Option sets the value as a select option instead of text
+	Option bool `mapstructure:"option"`
+}
+
+// Formats of the JIRA field holding the acceptance criteria checklist.
+const (
+	// ChecklistFormatText writes "[x] item" lines to a text field
+	ChecklistFormatText = "text"
+	// ChecklistFormatItems writes a list of {name, checked, rank} items, as
+	// taken by checklist plugin fields
+	ChecklistFormatItems = "checklist"
+)
+
+// DefaultChecklistHeading is the heading of the acceptance criteria section
+// when none is configured.
+const DefaultChecklistHeading = "Acceptance Criteria"
+
+// ChecklistConfig mirrors a checklist section of GitHub issues, such as
+// "- [x] Error message is shown" entries under "### Acceptance Criteria", to a
+// JIRA field.
+type ChecklistConfig struct {
+	// Heading is the section heading, compared case-insensitively
+	Heading string `mapstructure:"heading"`
+	// Field is the JIRA field ID; empty disables the sync
+	Field string `mapstructure:"field"`
+	// Format is ChecklistFormatText (the default) or ChecklistFormatItems
+	Format string `mapstructure:"format"`
+}
+
+// Sections JIRA descriptions can be composed of.
+const (
+	// DescriptionBody is the GitHub issue body, without the sections mapped
+	// to JIRA fields
+	DescriptionBody = "body"
+	// DescriptionMetadata is a table of the issue's number, author, labels
+	// and state
+	DescriptionMetadata = "metadata"
+	// DescriptionChildren lists the issues of a feature's '## Issues' section
+	DescriptionChildren = "children"
+	// DescriptionFooter notes that the ticket is synced from its GitHub issue
+	DescriptionFooter = "footer"
+)
+
+// DescriptionConfig selects the sections JIRA descriptions are composed of.
+type DescriptionConfig struct {
+	// Sections are the sections in the order they are written; sections
+	// left out aren't written. Defaults to the body alone.
+	Sections []string `mapstructure:"sections"`
+}
+
+// BoardAliases maps friendly names to the JIRA project keys they stand for,
+// e.g. platform to PLTENG. Names are compared case-insensitively.
+type BoardAliases map[string]string
+
+// Resolve returns the project key name is an alias of, or name itself if it
+// isn't an alias.
+func (a BoardAliases) Resolve(name string) string {
+	if key, ok := a[strings.ToLower(strings.TrimSpace(name))]; ok {
+		return key
+	}
+	return name
+}
+
+// Of returns the sorted aliases of a project key.
+func (a BoardAliases) Of(key string) []string {
+	var aliases []string
+	for alias, aliased := range a {
+		if strings.EqualFold(aliased, key) {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// Route sends the issues with any of its labels, or referencing a file under
+// any of its paths, to a board, e.g. the issues of one service of a monorepo.
+type Route struct {
+	// Board is the key of the JIRA project the issues are synced to
+	Board string `mapstructure:"board"`
+	// Labels are compared case-insensitively, e.g. "area:payments"
+	Labels []string `mapstructure:"labels"`
+	// Paths are repository paths, matching the files below them, or glob
+	// patterns such as "services/*/api"
+	Paths []string `mapstructure:"paths"`
+}
+
+// Matches reports whether an issue with labels whose body references the
+// files at paths is sent to the route's board.
+func (r Route) Matches(labels, paths []string) bool {
+	for _, label := range labels {
+		for _, routed := range r.Labels {
+			if strings.EqualFold(label, routed) {
+				return true
+			}
+		}
+	}
+	for _, file := range paths {
+		for _, pattern := range r.Paths {
+			if pathMatches(pattern, file) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// pathMatches reports whether file is pattern, below it or, if pattern is a
+// glob pattern, in a directory matching it.
+func pathMatches(pattern, file string) bool {
+	pattern = strings.Trim(strings.TrimPrefix(pattern, "./"), "/")
+	file = strings.Trim(strings.TrimPrefix(file, "./"), "/")
+	if pattern == "" || file == "" {
+		return false
+	}
+	if !strings.ContainsAny(pattern, "*?[") {
+		return file == pattern || strings.HasPrefix(file, pattern+"/")
+	}
+	for dir := file; dir != "." && dir != "/"; dir = path.Dir(dir) {
+		if ok, err := path.Match(pattern, dir); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SafetyConfig restricts the repositories and JIRA projects glue may change.
+// Entries are case-insensitive and may use glob patterns, e.g. "myorg/*".
+type SafetyConfig struct {
+	// AllowRepositories lists the repositories that may be changed; empty allows all
+	AllowRepositories []string `mapstructure:"allow_repositories"`
+	// AllowProjects lists the JIRA projects that may be changed; empty allows all
+	AllowProjects []string `mapstructure:"allow_projects"`
+	// DenyRepositories lists repositories that may never be changed
+	DenyRepositories []string `mapstructure:"deny_repositories"`
+	// DenyProjects lists JIRA projects that may never be changed
+	DenyProjects []string `mapstructure:"deny_projects"`
+}
+
+// Check returns an error naming every repository or project the safety
+// config doesn't permit changing. An empty repository is not checked.
+func (s SafetyConfig) Check(repository string, projects []string) error {
+	var refused []string
+	if repository != "" && !permitted(repository, s.AllowRepositories, s.DenyRepositories) {
+		refused = append(refused, fmt.Sprintf("repository %s", repository))
+	}
+	for _, project := range projects {
+		if !permitted(project, s.AllowProjects, s.DenyProjects) {
+			refused = append(refused, fmt.Sprintf("jira project %s", project))
+		}
+	}
+
+	if len(refused) > 0 {
+		return fmt.Errorf("safety config doesn't permit changing %s", strings.Join(refused, ", "))
+	}
+	return nil
+}
+
+// permitted reports whether name matches none of the deny patterns and, if
+// there are allow patterns, at least one of them.
+func permitted(name string, allow, deny []string) bool {
+	if matchesAny(name, deny) {
+		return false
+	}
+	return len(allow) == 0 || matchesAny(name, allow)
+}
+
+// matchesAny reports whether name matches any of the glob patterns, ignoring case.
+func matchesAny(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		if ok, err := path.Match(strings.ToLower(pattern), name); err == nil && ok {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncedLabelRule changes the labels of the GitHub issues of some
+// repositories once their JIRA ticket is created, e.g. removing a needs-jira
+// routing label and adding a glued label.
+type SyncedLabelRule struct {
+	// Repositories are the repositories the rule applies to, as glob
+	// patterns; empty applies it to all
+	Repositories []string `mapstructure:"repositories"`
+	// Remove are labels to remove, compared case-insensitively
+	Remove []string `mapstructure:"remove"`
+	// Add are labels to add; "{key}" and "{board}" are replaced with the
+	// ticket key and its project key, e.g. "jira-id: {key}"
+	Add []string `mapstructure:"add"`
+}
+
+// Apply returns the labels of an issue synced to the ticket key on board
+// after the rule's changes, and whether they differ from labels.
+func (r SyncedLabelRule) Apply(labels []string, key, board string) ([]string, bool) {
+	var result []string
+	changed := false
+	for _, label := range labels {
+		if containsFold(r.Remove, label) {
+			changed = true
+			continue
+		}
+		result = append(result, label)
+	}
+
+	replacer := strings.NewReplacer("{key}", key, "{board}", board)
+	for _, label := range r.Add {
+		label = replacer.Replace(label)
+		if !containsFold(result, label) {
+			result = append(result, label)
+			changed = true
+		}
+	}
+	return result, changed
+}
+
+// containsFold reports whether values contains value, ignoring case.
+func containsFold(values []string, value string) bool {
+	for _, v := range values {
+		if strings.EqualFold(v, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// SyncedLabels is the list of configured synced label rules.
+type SyncedLabels []SyncedLabelRule
+
+// For returns the first rule applying to repository, and false if there is
+// none.
+func (s SyncedLabels) For(repository string) (SyncedLabelRule, bool) {
+	for _, rule := range s {
+		if len(rule.Repositories) == 0 || matchesAny(repository, rule.Repositories) {
+			return rule, true
+		}
+	}
+	return SyncedLabelRule{}, false
+}
+
+// MaintenanceWindow is a recurring period, e.g. for JIRA upgrades, during
+// which glue makes no changes to JIRA.
+type MaintenanceWindow struct {
+	// Name identifies the window in logs; empty uses the schedule
+	Name string `mapstructure:"name"`
+	// Schedule is a cron expression for the start of the window, e.g.
+	// "0 2 * * SUN" for 2am every Sunday
+	Schedule string `mapstructure:"schedule"`
+	// Duration is how long the window lasts (e.g. "3h")
+	Duration time.Duration `mapstructure:"duration"`
+	// Timezone is the IANA time zone of the schedule, e.g. "Europe/London";
+	// empty uses the local time zone
+	Timezone string `mapstructure:"timezone"`
+}
+
+// Maintenance returns the parsed maintenance windows.
+func (c *Config) Maintenance() (maintenance.Windows, error) {
+	var windows maintenance.Windows
+	for i, w := range c.MaintenanceWindows {
+		name := w.Name
+		if name == "" {
+			name = w.Schedule
+		}
+		window, err := maintenance.NewWindow(name, w.Schedule, w.Duration, w.Timezone)
+		if err != nil {
+			return nil, fmt.Errorf("invalid maintenance_windows entry %d: %v", i+1, err)
+		}
+		windows = append(windows, window)
+	}
+	return windows, nil
+}
+
+// UserMapping maps a JIRA user to a GitHub login.
+type UserMapping struct {
+	// Jira is the JIRA account ID, username, email address or display name
+	Jira string `mapstructure:"jira"`
+	// GitHub is the GitHub login
+	GitHub string `mapstructure:"github"`
+}
+
+// UserMappings is the list of configured JIRA to GitHub user mappings.
+type UserMappings []UserMapping
+
+// GitHubLogin returns the GitHub login mapped to any of the given JIRA user
+// identifiers, compared case-insensitively. It returns an empty string if
+// none of them is mapped.
+func (m UserMappings) GitHubLogin(jiraIdentifiers ...string) string {
+	for _, user := range m {
+		for _, id := range jiraIdentifiers {
+			if id != "" && strings.EqualFold(user.Jira, id) {
+				return user.GitHub
+			}
+		}
+	}
+	return ""
 }
 
-// LoadConfig initializes and loads configuration from environment variables.
+// LoadConfig initializes and loads configuration from the optional config
+// file and environment variables. Environment variables take precedence.
 func LoadConfig() (*Config, error) {
 	// Initialize Viper for environment variables
 	v := viper.New()
@@ -35,12 +667,47 @@ func LoadConfig() (*Config, error) {
 	v.AutomaticEnv()
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
+	path, err := FilePath()
+	if err != nil {
+		return nil, err
+	}
+	if path != "" {
+		settings, err := readConfigFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := v.MergeConfigMap(settings); err != nil {
+			return nil, fmt.Errorf("failed to load config file %s: %v", path, err)
+		}
+	}
+
 	// Map specific environment variables
 	v.BindEnv("github.domain", "GITHUB_DOMAIN")
 	v.BindEnv("github.token", "GITHUB_TOKEN")
 	v.BindEnv("jira.baseurl", "JIRA_URL")
 	v.BindEnv("jira.username", "JIRA_USERNAME")
 	v.BindEnv("jira.token", "JIRA_TOKEN")
+	v.BindEnv("jira.timeout", "JIRA_TIMEOUT")
+	v.BindEnv("jira.max_consecutive_failures", "JIRA_MAX_CONSECUTIVE_FAILURES")
+	v.BindEnv("asana.token", "ASANA_TOKEN")
+	v.BindEnv("notion.token", "NOTION_TOKEN")
+	v.BindEnv("notion.database", "NOTION_DATABASE")
+	v.BindEnv("clickup.token", "CLICKUP_TOKEN")
+	v.BindEnv("youtrack.baseurl", "YOUTRACK_URL")
+	v.BindEnv("youtrack.token", "YOUTRACK_TOKEN")
+	v.BindEnv("outbound.url", "OUTBOUND_URL")
+	v.BindEnv("outbound.secret", "OUTBOUND_SECRET")
+	v.BindEnv("read_only", "GLUE_READ_ONLY")
+	v.BindEnv("record", "GLUE_RECORD")
+	v.BindEnv("replay", "GLUE_REPLAY")
+	v.BindEnv("profile", "GLUE_PROFILE")
+
+	profile := v.GetString("profile")
+	if profile != "" {
+		if err := applyProfile(v, profile); err != nil {
+			return nil, err
+		}
+	}
 
 	// Create config structure
 	config := &Config{
@@ -52,7 +719,196 @@ func LoadConfig() (*Config, error) {
 			BaseURL:  v.GetString("jira.baseurl"),
 			Username: v.GetString("jira.username"),
 			Token:    v.GetString("jira.token"),
+
+			Timeout:                v.GetDuration("jira.timeout"),
+			MaxConsecutiveFailures: v.GetInt("jira.max_consecutive_failures"),
+			CloseTransitions:       v.GetStringSlice("jira.close_transitions"),
+			BoardCloseTransitions:  v.GetStringMapStringSlice("jira.board_close_transitions"),
+			RequestTypes:           v.GetStringMapString("jira.request_types"),
+			MultipleParents:        v.GetString("jira.multiple_parents"),
+			LinkBatchSize:          v.GetInt("jira.link_batch_size"),
+			LinkBatchPause:         v.GetDuration("jira.link_batch_pause"),
+			MaxLinkChanges:         v.GetInt("jira.max_link_changes"),
+		},
+		Asana: AsanaConfig{
+			Token:    v.GetString("asana.token"),
+			Sections: v.GetStringMapString("asana.sections"),
+		},
+		Notion: NotionConfig{
+			Token:    v.GetString("notion.token"),
+			Database: v.GetString("notion.database"),
+			Properties: NotionProperties{
+				Title:  v.GetString("notion.properties.title"),
+				Status: v.GetString("notion.properties.status"),
+				URL:    v.GetString("notion.properties.url"),
+				Key:    v.GetString("notion.properties.key"),
+			},
+		},
+		ClickUp: ClickUpConfig{
+			Token:        v.GetString("clickup.token"),
+			Lists:        v.GetStringMapString("clickup.lists"),
+			ClosedStatus: v.GetString("clickup.closed_status"),
+			OpenStatus:   v.GetString("clickup.open_status"),
+		},
+		YouTrack: YouTrackConfig{
+			BaseURL:       v.GetString("youtrack.baseurl"),
+			Token:         v.GetString("youtrack.token"),
+			ResolvedState: v.GetString("youtrack.resolved_state"),
+			OpenState:     v.GetString("youtrack.open_state"),
 		},
+		Outbound: OutboundConfig{
+			URL:         v.GetString("outbound.url"),
+			Secret:      v.GetString("outbound.secret"),
+			Headers:     v.GetStringMapString("outbound.headers"),
+			Template:    v.GetString("outbound.template"),
+			ContentType: v.GetString("outbound.content_type"),
+			Timeout:     v.GetDuration("outbound.timeout"),
+		},
+		ReadOnly: v.GetBool("read_only"),
+		Record:   v.GetString("record"),
+		Replay:   v.GetString("replay"),
+		Profile:  profile,
+		Boards:   v.GetStringSlice("boards"),
+		Flags:    v.GetStringMap("flags"),
+	}
+
+	config.BoardAliases = make(BoardAliases)
+	for alias, key := range v.GetStringMapString("board_aliases") {
+		key = strings.ToUpper(strings.TrimSpace(key))
+		if key == "" {
+			return nil, fmt.Errorf("board_aliases.%s needs a jira project key", alias)
+		}
+		config.BoardAliases[strings.ToLower(alias)] = key
+	}
+	for alias, key := range config.BoardAliases {
+		if _, ok := config.BoardAliases[strings.ToLower(key)]; ok && alias != strings.ToLower(key) {
+			return nil, fmt.Errorf("board_aliases.%s points to another alias, %s", alias, key)
+		}
+	}
+
+	if config.ClickUp.ClosedStatus == "" {
+		config.ClickUp.ClosedStatus = DefaultClickUpClosedStatus
+	}
+	if config.ClickUp.OpenStatus == "" {
+		config.ClickUp.OpenStatus = DefaultClickUpOpenStatus
+	}
+	if config.YouTrack.ResolvedState == "" {
+		config.YouTrack.ResolvedState = DefaultYouTrackResolvedState
+	}
+	if config.YouTrack.OpenState == "" {
+		config.YouTrack.OpenState = DefaultYouTrackOpenState
+	}
+	if err := v.UnmarshalKey("youtrack.fields", &config.YouTrack.Fields); err != nil {
+		return nil, fmt.Errorf("invalid youtrack.fields in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("users", &config.Users); err != nil {
+		return nil, fmt.Errorf("invalid users in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("hooks", &config.Hooks); err != nil {
+		return nil, fmt.Errorf("invalid hooks in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("rules", &config.Rules); err != nil {
+		return nil, fmt.Errorf("invalid rules in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("safety", &config.Safety); err != nil {
+		return nil, fmt.Errorf("invalid safety in config file: %v", err)
+	}
+
+	switch config.Jira.MultipleParents {
+	case "":
+		config.Jira.MultipleParents = MultipleParentsLinkAll
+	case MultipleParentsLinkAll, MultipleParentsFirstWins, MultipleParentsWarnAndSkip:
+	default:
+		return nil, fmt.Errorf("invalid jira.multiple_parents %q, expected %q, %q or %q",
+			config.Jira.MultipleParents, MultipleParentsLinkAll, MultipleParentsFirstWins, MultipleParentsWarnAndSkip)
+	}
+
+	if config.Jira.LinkBatchSize == 0 {
+		config.Jira.LinkBatchSize = DefaultLinkBatchSize
+	}
+	if config.Jira.LinkBatchSize < 0 || config.Jira.LinkBatchPause < 0 || config.Jira.MaxLinkChanges < 0 {
+		return nil, fmt.Errorf("jira.link_batch_size, jira.link_batch_pause and jira.max_link_changes can't be negative")
+	}
+
+	if err := v.UnmarshalKey("routes", &config.Routes); err != nil {
+		return nil, fmt.Errorf("invalid routes in config file: %v", err)
+	}
+	for i, r := range config.Routes {
+		if r.Board == "" || (len(r.Labels) == 0 && len(r.Paths) == 0) {
+			return nil, fmt.Errorf("routes entry %d needs a board and labels or paths", i+1)
+		}
+		config.Routes[i].Board = config.BoardAliases.Resolve(r.Board)
+	}
+
+	if err := v.UnmarshalKey("security", &config.Security); err != nil {
+		return nil, fmt.Errorf("invalid security in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("form_fields", &config.FormFields); err != nil {
+		return nil, fmt.Errorf("invalid form_fields in config file: %v", err)
+	}
+	for i, f := range config.FormFields {
+		if f.Heading == "" || f.Field == "" {
+			return nil, fmt.Errorf("form_fields entry %d needs both heading and field", i+1)
+		}
+	}
+
+	if err := v.UnmarshalKey("required_fields", &config.RequiredFields); err != nil {
+		return nil, fmt.Errorf("invalid required_fields in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("acceptance_criteria", &config.AcceptanceCriteria); err != nil {
+		return nil, fmt.Errorf("invalid acceptance_criteria in config file: %v", err)
+	}
+	if config.AcceptanceCriteria.Heading == "" {
+		config.AcceptanceCriteria.Heading = DefaultChecklistHeading
+	}
+	switch config.AcceptanceCriteria.Format {
+	case "":
+		config.AcceptanceCriteria.Format = ChecklistFormatText
+	case ChecklistFormatText, ChecklistFormatItems:
+	default:
+		return nil, fmt.Errorf("invalid acceptance_criteria format %q, expected %q or %q",
+			config.AcceptanceCriteria.Format, ChecklistFormatText, ChecklistFormatItems)
+	}
+
+	if err := v.UnmarshalKey("description", &config.Description); err != nil {
+		return nil, fmt.Errorf("invalid description in config file: %v", err)
+	}
+	if len(config.Description.Sections) == 0 {
+		config.Description.Sections = []string{DescriptionBody}
+	}
+	seenSections := make(map[string]bool)
+	for _, section := range config.Description.Sections {
+		switch section {
+		case DescriptionBody, DescriptionMetadata, DescriptionChildren, DescriptionFooter:
+		default:
+			return nil, fmt.Errorf("invalid description section %q, expected %q, %q, %q or %q",
+				section, DescriptionBody, DescriptionMetadata, DescriptionChildren, DescriptionFooter)
+		}
+		if seenSections[section] {
+			return nil, fmt.Errorf("description section %q is listed twice", section)
+		}
+		seenSections[section] = true
+	}
+
+	if err := v.UnmarshalKey("synced_labels", &config.SyncedLabels); err != nil {
+		return nil, fmt.Errorf("invalid synced_labels in config file: %v", err)
+	}
+
+	if err := v.UnmarshalKey("maintenance_windows", &config.MaintenanceWindows); err != nil {
+		return nil, fmt.Errorf("invalid maintenance_windows in config file: %v", err)
+	}
+	if _, err := config.Maintenance(); err != nil {
+		return nil, err
+	}
+
+	if config.Record != "" && config.Replay != "" {
+		return nil, fmt.Errorf("record and replay can't be used together")
 	}
 
 	// Set default values if not provided
@@ -68,6 +924,52 @@ func LoadConfig() (*Config, error) {
 	return config, nil
 }
 
+// applyProfile merges the settings of the named entry under "profiles" in
+// the config file over the top-level settings. Environment variables still
+// take precedence.
+func applyProfile(v *viper.Viper, name string) error {
+	key := "profiles." + name
+	if !v.IsSet(key) {
+		return fmt.Errorf("profile %q not found in config file", name)
+	}
+
+	settings := v.GetStringMap(key)
+	if err := v.MergeConfigMap(settings); err != nil {
+		return fmt.Errorf("failed to apply profile %q: %v", name, err)
+	}
+	return nil
+}
+
+// FilePath returns the path of the config file to load, or an empty string
+// if there is none. GLUE_CONFIG takes precedence and must point to an
+// existing file; otherwise the SearchPaths are tried in order.
+func FilePath() (string, error) {
+	if path := os.Getenv("GLUE_CONFIG"); path != "" {
+		if _, err := os.Stat(path); err != nil {
+			return "", fmt.Errorf("config file from GLUE_CONFIG not found: %v", err)
+		}
+		return path, nil
+	}
+
+	for _, path := range SearchPaths() {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+	return "", nil
+}
+
+// SearchPaths returns the locations the config file is looked for when
+// GLUE_CONFIG isn't set: glue.yaml in the current directory, then in glue's
+// user config directory (e.g. ~/.config/glue/glue.yaml).
+func SearchPaths() []string {
+	candidates := []string{ConfigFileName}
+	if dir, err := paths.ConfigDir(); err == nil {
+		candidates = append(candidates, filepath.Join(dir, ConfigFileName))
+	}
+	return candidates
+}
+
 // validateConfig ensures that all required configuration values are provided.
 func validateConfig(config *Config) error {
 	var missingVars []string
@@ -104,4 +1006,4 @@ func ValidateJiraConfig(config *Config) error {
 	}
 
 	return nil
-} 
\ No newline at end of file
+}
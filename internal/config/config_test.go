@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -130,4 +132,491 @@ func TestValidateJiraConfig(t *testing.T) {
 			}
 		})
 	}
-} 
\ No newline at end of file
+} 
+func TestLoadConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "glue.yaml")
+	content := `github:
+  domain: git.example.com
+  token: file-token
+jira:
+  close_transitions: [Done, "41"]
+  board_close_transitions:
+    DE: [Fertig]
+  request_types:
+    HELP: Report a bug
+  multiple_parents: first-wins
+  link_batch_size: 20
+  link_batch_pause: 2s
+  max_link_changes: 500
+asana:
+  token: asana-token
+  sections:
+    Bug: Bugs
+notion:
+  database: db1
+  properties:
+    status: Stage
+clickup:
+  lists:
+    Bug: "901"
+  closed_status: shipped
+routes:
+  - board: payments
+    labels: [area:payments]
+    paths: [services/payments]
+board_aliases:
+  Payments: pay
+  platform: PLTENG
+youtrack:
+  baseurl: https://example.youtrack.cloud
+  fields:
+    Bug:
+      Type: Bug
+      Priority: Major
+users:
+  - jira: Jane.Doe@example.com
+    github: janedoe
+  - jira: 5b10ac8d82e05b22cc7d4ef5
+    github: octocat
+hooks:
+  post_create:
+    - name: platform team
+      jql: labels = backend
+      fields:
+        customfield_10010:
+          value: Platform
+  post_run:
+    - name: notify
+      command: ["notify.sh", "--channel", "sync"]
+      timeout: 10s
+safety:
+  allow_repositories: ["myorg/*"]
+  deny_projects: [PROD]
+read_only: true
+synced_labels:
+  - repositories: ["myorg/*"]
+    remove: [needs-jira]
+    add: [glued, "jira-id: {key}"]
+maintenance_windows:
+  - name: jira upgrade
+    schedule: "0 2 * * SUN"
+    duration: 3h
+    timezone: Europe/London
+form_fields:
+  - heading: Acceptance Criteria
+    field: customfield_10020
+  - heading: Severity
+    field: customfield_10030
+    option: true
+acceptance_criteria:
+  field: customfield_10040
+  format: checklist
+description:
+  sections: [metadata, body, children]
+required_fields:
+  customfield_10011: Unplanned
+  customfield_10050:
+    value: Team A
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	t.Setenv("GLUE_CONFIG", path)
+	t.Setenv("GITHUB_DOMAIN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("GLUE_READ_ONLY", "")
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "git.example.com", config.GitHub.Domain)
+	assert.Equal(t, "file-token", config.GitHub.Token)
+	assert.Equal(t, UserMappings{
+		{Jira: "Jane.Doe@example.com", GitHub: "janedoe"},
+		{Jira: "5b10ac8d82e05b22cc7d4ef5", GitHub: "octocat"},
+	}, config.Users)
+	assert.True(t, config.ReadOnly)
+	assert.Equal(t, []string{"Done", "41"}, config.Jira.CloseTransitions)
+	// Keys are lower-cased by the config parser
+	assert.Equal(t, map[string][]string{"de": {"Fertig"}}, config.Jira.BoardCloseTransitions)
+	assert.Equal(t, map[string]string{"help": "Report a bug"}, config.Jira.RequestTypes)
+	assert.Equal(t, MultipleParentsFirstWins, config.Jira.MultipleParents)
+	assert.Equal(t, 20, config.Jira.LinkBatchSize)
+	assert.Equal(t, 2*time.Second, config.Jira.LinkBatchPause)
+	assert.Equal(t, 500, config.Jira.MaxLinkChanges)
+	assert.Equal(t, AsanaConfig{Token: "asana-token", Sections: map[string]string{"bug": "Bugs"}}, config.Asana)
+	assert.Equal(t, NotionConfig{Database: "db1", Properties: NotionProperties{Status: "Stage"}}, config.Notion)
+	assert.Equal(t, ClickUpConfig{Lists: map[string]string{"bug": "901"}, ClosedStatus: "shipped", OpenStatus: DefaultClickUpOpenStatus}, config.ClickUp)
+	assert.Equal(t, BoardAliases{"payments": "PAY", "platform": "PLTENG"}, config.BoardAliases)
+	// Routes may name their board by an alias
+	assert.Equal(t, []Route{{Board: "PAY", Labels: []string{"area:payments"}, Paths: []string{"services/payments"}}}, config.Routes)
+	assert.Equal(t, YouTrackConfig{
+		BaseURL:       "https://example.youtrack.cloud",
+		Fields:        map[string]map[string]string{"bug": {"type": "Bug", "priority": "Major"}},
+		ResolvedState: DefaultYouTrackResolvedState,
+		OpenState:     DefaultYouTrackOpenState,
+	}, config.YouTrack)
+	assert.Equal(t, map[string]interface{}{
+		"customfield_10011": "Unplanned",
+		"customfield_10050": map[string]interface{}{"value": "Team A"},
+	}, config.RequiredFields)
+	assert.Equal(t, ChecklistConfig{Heading: DefaultChecklistHeading, Field: "customfield_10040", Format: ChecklistFormatItems}, config.AcceptanceCriteria)
+	assert.Equal(t, DescriptionConfig{Sections: []string{DescriptionMetadata, DescriptionBody, DescriptionChildren}}, config.Description)
+	assert.Equal(t, []FormField{
+		{Heading: "Acceptance Criteria", Field: "customfield_10020"},
+		{Heading: "Severity", Field: "customfield_10030", Option: true},
+	}, config.FormFields)
+	assert.Equal(t, SafetyConfig{AllowRepositories: []string{"myorg/*"}, DenyProjects: []string{"PROD"}}, config.Safety)
+	require.Len(t, config.Hooks.PostCreate, 1)
+	assert.Equal(t, "platform team", config.Hooks.PostCreate[0].Name)
+	assert.Equal(t, "labels = backend", config.Hooks.PostCreate[0].JQL)
+	assert.Equal(t, map[string]interface{}{"value": "Platform"}, config.Hooks.PostCreate[0].Fields["customfield_10010"])
+	assert.Equal(t, []ExecHook{
+		{Name: "notify", Command: []string{"notify.sh", "--channel", "sync"}, Timeout: 10 * time.Second},
+	}, config.Hooks.PostRun)
+	assert.Equal(t, SyncedLabels{
+		{Repositories: []string{"myorg/*"}, Remove: []string{"needs-jira"}, Add: []string{"glued", "jira-id: {key}"}},
+	}, config.SyncedLabels)
+	assert.Equal(t, []MaintenanceWindow{
+		{Name: "jira upgrade", Schedule: "0 2 * * SUN", Duration: 3 * time.Hour, Timezone: "Europe/London"},
+	}, config.MaintenanceWindows)
+
+	// Environment variables override the file
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	config, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", config.GitHub.Token)
+
+	t.Setenv("GLUE_READ_ONLY", "false")
+	config, err = LoadConfig()
+	require.NoError(t, err)
+	assert.False(t, config.ReadOnly)
+}
+
+func TestLoadConfigFileMissing(t *testing.T) {
+	t.Setenv("GLUE_CONFIG", filepath.Join(t.TempDir(), "missing.yaml"))
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	config, err := LoadConfig()
+	assert.Error(t, err)
+	assert.Nil(t, config)
+}
+
+func TestLoadConfigInvalidMaintenanceWindow(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	content := `maintenance_windows:
+  - schedule: "0 25 * * *"
+    duration: 1h
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	_, err := LoadConfig()
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "invalid maintenance_windows entry 1")
+	assert.Contains(t, err.Error(), `invalid hour "25"`)
+}
+
+func TestSyncedLabels(t *testing.T) {
+	labels := SyncedLabels{
+		{Repositories: []string{"myorg/legacy"}, Add: []string{"jira"}},
+		{Remove: []string{"Needs-Jira"}, Add: []string{"glued", "jira-id: {key}", "{board}"}},
+	}
+
+	rule, ok := labels.For("MyOrg/Legacy")
+	require.True(t, ok)
+	assert.Equal(t, []string{"jira"}, rule.Add)
+
+	rule, ok = labels.For("myorg/app")
+	require.True(t, ok)
+	result, changed := rule.Apply([]string{"story", "needs-jira", "PROJ"}, "PROJ-12", "PROJ")
+	assert.True(t, changed)
+	assert.Equal(t, []string{"story", "PROJ", "glued", "jira-id: PROJ-12"}, result)
+
+	_, changed = rule.Apply(result, "PROJ-12", "PROJ")
+	assert.False(t, changed)
+
+	_, ok = SyncedLabels{{Repositories: []string{"other/*"}}}.For("myorg/app")
+	assert.False(t, ok)
+}
+
+func TestMaintenance(t *testing.T) {
+	config := &Config{MaintenanceWindows: []MaintenanceWindow{
+		{Name: "upgrade", Schedule: "0 2 * * SUN", Duration: time.Hour, Timezone: "UTC"},
+		{Schedule: "30 22 * * *", Duration: 30 * time.Minute, Timezone: "UTC"},
+	}}
+
+	windows, err := config.Maintenance()
+	require.NoError(t, err)
+	require.Len(t, windows, 2)
+	assert.Equal(t, "upgrade", windows[0].Name)
+	assert.Equal(t, "30 22 * * *", windows[1].Name)
+
+	config.MaintenanceWindows[1].Duration = 0
+	_, err = config.Maintenance()
+	assert.ErrorContains(t, err, "invalid maintenance_windows entry 2: duration must be positive")
+}
+
+func TestGitHubLogin(t *testing.T) {
+	users := UserMappings{
+		{Jira: "jane.doe@example.com", GitHub: "janedoe"},
+		{Jira: "jsmith", GitHub: "johnsmith"},
+	}
+
+	assert.Equal(t, "janedoe", users.GitHubLogin("acc-1", "Jane.Doe@example.com"))
+	assert.Equal(t, "johnsmith", users.GitHubLogin("", "JSMITH"))
+	assert.Equal(t, "", users.GitHubLogin("unknown", ""))
+	assert.Equal(t, "", users.GitHubLogin())
+}
+
+func TestSecurityPriority(t *testing.T) {
+	security := SecurityConfig{Priorities: map[string]string{"critical": "Blocker", "low": ""}}
+
+	assert.Equal(t, "Blocker", security.Priority("CRITICAL"))
+	assert.Equal(t, "High", security.Priority("high"))
+	assert.Equal(t, "", security.Priority("low"))
+	assert.Equal(t, "", security.Priority("unknown"))
+}
+
+func TestAsanaSection(t *testing.T) {
+	asana := AsanaConfig{Sections: map[string]string{"bug": "Bugs", "enhancement": "Ideas"}}
+
+	assert.Equal(t, "Ideas", asana.Section([]string{"asana", "Enhancement", "bug"}))
+	assert.Equal(t, "", asana.Section([]string{"asana"}))
+	assert.Equal(t, "", AsanaConfig{}.Section([]string{"bug"}))
+}
+
+func TestClickUpList(t *testing.T) {
+	clickup := ClickUpConfig{Lists: map[string]string{"bug": "901", "enhancement": "902"}}
+
+	assert.Equal(t, "902", clickup.List([]string{"clickup", "Enhancement", "bug"}))
+	assert.Equal(t, "", clickup.List([]string{"clickup"}))
+	assert.Equal(t, "", ClickUpConfig{}.List([]string{"bug"}))
+}
+
+func TestYouTrackFieldValues(t *testing.T) {
+	youtrack := YouTrackConfig{Fields: map[string]map[string]string{
+		"bug":      {"type": "Bug"},
+		"critical": {"priority": "Critical", "type": "Incident"},
+	}}
+
+	// The first label setting a field wins
+	assert.Equal(t, map[string]string{"type": "Bug", "priority": "Critical"}, youtrack.FieldValues([]string{"youtrack", "Bug", "critical"}))
+	assert.Empty(t, youtrack.FieldValues([]string{"youtrack"}))
+	assert.Empty(t, YouTrackConfig{}.FieldValues([]string{"bug"}))
+}
+
+func TestRouteMatches(t *testing.T) {
+	route := Route{Board: "PAY", Labels: []string{"area:payments"}, Paths: []string{"./services/payments/", "libs/*/billing"}}
+
+	assert.True(t, route.Matches([]string{"Area:Payments"}, nil))
+	assert.True(t, route.Matches(nil, []string{"services/payments"}))
+	assert.True(t, route.Matches(nil, []string{"/services/payments/refund/api.go"}))
+	assert.True(t, route.Matches(nil, []string{"libs/go/billing/invoice.go"}))
+	assert.False(t, route.Matches(nil, []string{"services/payments-v2/api.go"}))
+	assert.False(t, route.Matches(nil, []string{"libs/go/shipping/label.go"}))
+	assert.False(t, route.Matches([]string{"area:web"}, []string{"apps/web"}))
+}
+
+func TestSafetyCheck(t *testing.T) {
+	safety := SafetyConfig{
+		AllowRepositories: []string{"myorg/*"},
+		AllowProjects:     []string{"PROJ", "TEAM*"},
+		DenyRepositories:  []string{"myorg/prod-*"},
+		DenyProjects:      []string{"TEAMPROD"},
+	}
+
+	tests := []struct {
+		name       string
+		repository string
+		projects   []string
+		wantErr    string
+	}{
+		{name: "Allowed", repository: "myorg/app", projects: []string{"PROJ", "team1"}},
+		{name: "Repository not checked when empty", repository: "", projects: []string{"PROJ"}},
+		{name: "Repository outside allowlist", repository: "other/app", projects: []string{"PROJ"},
+			wantErr: "safety config doesn't permit changing repository other/app"},
+		{name: "Denied repository", repository: "MyOrg/prod-api", projects: nil,
+			wantErr: "safety config doesn't permit changing repository MyOrg/prod-api"},
+		{name: "Denied and unknown projects", repository: "myorg/app", projects: []string{"TEAMPROD", "OPS"},
+			wantErr: "safety config doesn't permit changing jira project TEAMPROD, jira project OPS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := safety.Check(tt.repository, tt.projects)
+			if tt.wantErr == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.wantErr)
+			}
+		})
+	}
+
+	assert.NoError(t, SafetyConfig{}.Check("any/repo", []string{"ANY"}))
+}
+
+func TestLoadConfigInvalidChecklistFormat(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("acceptance_criteria:\n  field: customfield_1\n  format: table\n"), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, `invalid acceptance_criteria format "table"`)
+}
+
+func TestLoadConfigDescriptionSections(t *testing.T) {
+	t.Setenv("GLUE_CONFIG", "")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, []string{DescriptionBody}, config.Description.Sections)
+
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("description:\n  sections: [body, summary]\n"), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, `invalid description section "summary"`)
+
+	require.NoError(t, os.WriteFile(path, []byte("description:\n  sections: [body, footer, body]\n"), 0o600))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, `description section "body" is listed twice`)
+}
+
+func TestLoadConfigInvalidRoute(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("routes:\n  - board: PAY\n    labels: [area:payments]\n  - board: WEB\n"), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "routes entry 2 needs a board and labels or paths")
+}
+
+func TestLoadConfigInvalidBoardAlias(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    string
+	}{
+		{"empty key", "board_aliases:\n  platform: \"\"\n", "board_aliases.platform needs a jira project key"},
+		{"chained", "board_aliases:\n  platform: eng\n  eng: PLTENG\n", "board_aliases.platform points to another alias, ENG"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "glue.yaml")
+			require.NoError(t, os.WriteFile(path, []byte(tt.content), 0o600))
+			t.Setenv("GLUE_CONFIG", path)
+			t.Setenv("GITHUB_TOKEN", "test-token")
+
+			_, err := LoadConfig()
+			assert.ErrorContains(t, err, tt.want)
+		})
+	}
+}
+
+func TestBoardAliases(t *testing.T) {
+	aliases := BoardAliases{"platform": "PLTENG", "infra": "PLTENG", "pay": "PAY"}
+
+	assert.Equal(t, "PLTENG", aliases.Resolve("Platform"))
+	assert.Equal(t, "PAY", aliases.Resolve(" pay "))
+	assert.Equal(t, "PROJ", aliases.Resolve("PROJ"))
+	assert.Equal(t, "PROJ", BoardAliases(nil).Resolve("PROJ"))
+
+	assert.Equal(t, []string{"infra", "platform"}, aliases.Of("plteng"))
+	assert.Empty(t, aliases.Of("PROJ"))
+}
+
+func TestLoadConfigMultipleParents(t *testing.T) {
+	t.Setenv("GLUE_CONFIG", "")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, MultipleParentsLinkAll, config.Jira.MultipleParents)
+	assert.Equal(t, DefaultLinkBatchSize, config.Jira.LinkBatchSize)
+	assert.Zero(t, config.Jira.MaxLinkChanges)
+
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("jira:\n  multiple_parents: newest\n"), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, `invalid jira.multiple_parents "newest"`)
+}
+
+func TestLoadConfigRecordAndReplay(t *testing.T) {
+	t.Setenv("GLUE_CONFIG", "")
+	t.Setenv("GITHUB_TOKEN", "test-token")
+	t.Setenv("GLUE_RECORD", "fixtures")
+	t.Setenv("GLUE_REPLAY", "")
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "fixtures", config.Record)
+
+	t.Setenv("GLUE_REPLAY", "fixtures")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, "record and replay can't be used together")
+}
+
+func TestLoadConfigProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	content := `github:
+  domain: github.com
+  token: default-token
+jira:
+  baseurl: https://jira.example.com
+read_only: false
+boards: [PROJ]
+profiles:
+  staging:
+    github:
+      token: staging-token
+    jira:
+      baseurl: https://jira-staging.example.com
+    boards: [STAGE, QA]
+    read_only: true
+    flags:
+      sync-descriptions: true
+`
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	t.Setenv("GLUE_CONFIG", path)
+	t.Setenv("GITHUB_DOMAIN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("JIRA_URL", "")
+	t.Setenv("GLUE_READ_ONLY", "")
+
+	t.Setenv("GLUE_PROFILE", "")
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "", config.Profile)
+	assert.Equal(t, "default-token", config.GitHub.Token)
+	assert.Equal(t, []string{"PROJ"}, config.Boards)
+	assert.False(t, config.ReadOnly)
+
+	t.Setenv("GLUE_PROFILE", "staging")
+	config, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "staging", config.Profile)
+	// Keys the profile doesn't set are kept
+	assert.Equal(t, "github.com", config.GitHub.Domain)
+	assert.Equal(t, "staging-token", config.GitHub.Token)
+	assert.Equal(t, "https://jira-staging.example.com", config.Jira.BaseURL)
+	assert.Equal(t, []string{"STAGE", "QA"}, config.Boards)
+	assert.True(t, config.ReadOnly)
+	assert.Equal(t, map[string]interface{}{"sync-descriptions": true}, config.Flags)
+
+	// Environment variables still take precedence
+	t.Setenv("GITHUB_TOKEN", "env-token")
+	config, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "env-token", config.GitHub.Token)
+
+	t.Setenv("GLUE_PROFILE", "prod")
+	_, err = LoadConfig()
+	assert.EqualError(t, err, `profile "prod" not found in config file`)
+}
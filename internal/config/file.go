@@ -0,0 +1,167 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/spf13/viper"
+)
+
+// envReference matches ${VAR} and ${VAR:-default} in config file values.
+var envReference = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(?::-([^}]*))?\}`)
+
+// readConfigFile reads the config file at path, with the files listed under
+// its include key merged in first and ${VAR} references in string values
+// expanded. Settings in the including file override included ones, except
+// that users mappings are combined, with the including file's taking
+// precedence.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	return readConfigFileFrom(path, nil)
+}
+
+// readConfigFileFrom reads path as part of the chain of including files, to
+// detect include cycles.
+func readConfigFileFrom(path string, chain []string) (map[string]interface{}, error) {
+	abs, err := filepath.Abs(path)
+	if err != nil {
+		return nil, err
+	}
+	for _, including := range chain {
+		if including == abs {
+			return nil, fmt.Errorf("config file %s includes itself via %s", path, strings.Join(chain, " -> "))
+		}
+	}
+	chain = append(chain, abs)
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to read config file %s: %v", path, err)
+	}
+	settings := v.AllSettings()
+
+	expanded, err := expandEnv(settings)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	settings = expanded.(map[string]interface{})
+
+	includes, err := includePaths(settings["include"], filepath.Dir(path))
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	delete(settings, "include")
+
+	merged := make(map[string]interface{})
+	for _, include := range includes {
+		included, err := readConfigFileFrom(include, chain)
+		if err != nil {
+			return nil, err
+		}
+		mergeSettings(merged, included)
+	}
+	mergeSettings(merged, settings)
+	return merged, nil
+}
+
+// includePaths returns the files named by an include value, either a single
+// path or a list, resolved relative to dir.
+func includePaths(value interface{}, dir string) ([]string, error) {
+	var paths []string
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case string:
+		paths = []string{v}
+	case []interface{}:
+		for _, item := range v {
+			path, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("invalid include %v, expected a file path", item)
+			}
+			paths = append(paths, path)
+		}
+	default:
+		return nil, fmt.Errorf("invalid include %v, expected a file path or a list of them", value)
+	}
+
+	for i, path := range paths {
+		if !filepath.IsAbs(path) {
+			paths[i] = filepath.Join(dir, path)
+		}
+	}
+	return paths, nil
+}
+
+// mergeSettings merges src into dst. Maps are merged key by key and users
+// lists are combined with src's entries first; other values in src replace
+// those in dst.
+func mergeSettings(dst, src map[string]interface{}) {
+	for key, value := range src {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			if existing, ok := dst[key].(map[string]interface{}); ok {
+				mergeSettings(existing, v)
+				continue
+			}
+		case []interface{}:
+			if existing, ok := dst[key].([]interface{}); ok && key == "users" {
+				dst[key] = append(append([]interface{}{}, v...), existing...)
+				continue
+			}
+		}
+		dst[key] = value
+	}
+}
+
+// expandEnv replaces ${VAR} references in the strings of a config value with
+// the environment variable's value. ${VAR:-default} uses default if VAR is
+// unset or empty; a plain ${VAR} that is unset is an error, so a missing
+// secret isn't silently replaced with an empty string.
+func expandEnv(value interface{}) (interface{}, error) {
+	switch v := value.(type) {
+	case string:
+		var missing []string
+		expanded := envReference.ReplaceAllStringFunc(v, func(ref string) string {
+			match := envReference.FindStringSubmatch(ref)
+			name, hasDefault := match[1], strings.Contains(ref, ":-")
+			if value := os.Getenv(name); value != "" {
+				return value
+			}
+			if hasDefault {
+				return match[2]
+			}
+			if _, ok := os.LookupEnv(name); !ok {
+				missing = append(missing, name)
+			}
+			return ""
+		})
+		if len(missing) > 0 {
+			return nil, fmt.Errorf("environment variable %s is not set", strings.Join(missing, ", "))
+		}
+		return expanded, nil
+	case map[string]interface{}:
+		for key, item := range v {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			v[key] = expanded
+		}
+		return v, nil
+	case []interface{}:
+		for i, item := range v {
+			expanded, err := expandEnv(item)
+			if err != nil {
+				return nil, err
+			}
+			v[i] = expanded
+		}
+		return v, nil
+	default:
+		return value, nil
+	}
+}
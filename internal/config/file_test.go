@@ -0,0 +1,94 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadConfigIncludesAndEnv(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "org"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "org", "shared.yaml"), []byte(`github:
+  domain: git.example.com
+  token: ${ORG_GITHUB_TOKEN}
+jira:
+  baseurl: ${JIRA_BASE:-https://jira.example.com}
+users:
+  - jira: jane.doe@example.com
+    github: janedoe
+  - jira: jsmith
+    github: johnsmith
+`), 0o600))
+	path := filepath.Join(dir, "glue.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`include: org/shared.yaml
+github:
+  domain: github.com
+users:
+  - jira: jsmith
+    github: jsmith-work
+`), 0o600))
+
+	t.Setenv("GLUE_CONFIG", path)
+	t.Setenv("GITHUB_DOMAIN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	t.Setenv("JIRA_URL", "")
+	t.Setenv("JIRA_BASE", "")
+	t.Setenv("ORG_GITHUB_TOKEN", "org-token")
+
+	config, err := LoadConfig()
+	require.NoError(t, err)
+	// The including file overrides the included one
+	assert.Equal(t, "github.com", config.GitHub.Domain)
+	assert.Equal(t, "org-token", config.GitHub.Token)
+	assert.Equal(t, "https://jira.example.com", config.Jira.BaseURL)
+	// User mappings are combined, the including file's first
+	assert.Equal(t, "jsmith-work", config.Users.GitHubLogin("jsmith"))
+	assert.Equal(t, "janedoe", config.Users.GitHubLogin("jane.doe@example.com"))
+
+	t.Setenv("JIRA_BASE", "https://jira.internal")
+	config, err = LoadConfig()
+	require.NoError(t, err)
+	assert.Equal(t, "https://jira.internal", config.Jira.BaseURL)
+
+	require.NoError(t, os.Unsetenv("ORG_GITHUB_TOKEN"))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "environment variable ORG_GITHUB_TOKEN is not set")
+}
+
+func TestLoadConfigIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "a.yaml"), []byte("include: [b.yaml]\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("include: a.yaml\n"), 0o600))
+	t.Setenv("GLUE_CONFIG", filepath.Join(dir, "a.yaml"))
+	t.Setenv("GITHUB_TOKEN", "test-token")
+
+	_, err := LoadConfig()
+	assert.ErrorContains(t, err, "includes itself")
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "b.yaml"), []byte("include: missing.yaml\n"), 0o600))
+	_, err = LoadConfig()
+	assert.ErrorContains(t, err, "missing.yaml")
+}
+
+func TestMergeSettings(t *testing.T) {
+	dst := map[string]interface{}{
+		"jira":   map[string]interface{}{"baseurl": "https://a", "username": "bot"},
+		"users":  []interface{}{"org"},
+		"boards": []interface{}{"ORG"},
+	}
+	mergeSettings(dst, map[string]interface{}{
+		"jira":   map[string]interface{}{"baseurl": "https://b"},
+		"users":  []interface{}{"repo"},
+		"boards": []interface{}{"REPO"},
+	})
+
+	assert.Equal(t, map[string]interface{}{
+		"jira":   map[string]interface{}{"baseurl": "https://b", "username": "bot"},
+		"users":  []interface{}{"repo", "org"},
+		"boards": []interface{}{"REPO"},
+	}, dst)
+}
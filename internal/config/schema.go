@@ -0,0 +1,100 @@
+package config
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// SchemaID identifies the JSON Schema of the config file.
+const SchemaID = "https://github.com/danielolaszy/glue/glue.schema.json"
+
+// durationType is the type of durations such as jira.timeout.
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// Schema returns a JSON Schema of the config file, generated from the Config
+// struct. Unknown keys are rejected, so editors using it flag typos. Number
+// and boolean values may also be strings, as they can be set with ${VAR}
+// references.
+func Schema() map[string]interface{} {
+	settings := structSchema(reflect.TypeOf(Config{}))
+
+	// A profile holds the same settings as the top level of the file
+	profile := structSchema(reflect.TypeOf(Config{}))
+	delete(profile["properties"].(map[string]interface{}), "profile")
+
+	properties := settings["properties"].(map[string]interface{})
+	properties["include"] = map[string]interface{}{
+		"description": "Config files to merge in first, relative to this file",
+		"oneOf": []interface{}{
+			map[string]interface{}{"type": "string"},
+			map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}},
+		},
+	}
+	properties["profiles"] = map[string]interface{}{
+		"description":          "Named sets of settings selected with --profile or GLUE_PROFILE",
+		"type":                 "object",
+		"additionalProperties": profile,
+	}
+
+	settings["$schema"] = "https://json-schema.org/draft/2020-12/schema"
+	settings["$id"] = SchemaID
+	settings["title"] = "glue configuration"
+	return settings
+}
+
+// typeSchema returns the JSON Schema of values of type t.
+func typeSchema(t reflect.Type) map[string]interface{} {
+	if t == durationType {
+		return map[string]interface{}{"type": "string", "description": `A duration such as "30s" or "2m"`}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]interface{}{"type": "string"}
+	case reflect.Bool:
+		return map[string]interface{}{"type": []string{"boolean", "string"}}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": []string{"integer", "string"}}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": []string{"number", "string"}}
+	case reflect.Slice, reflect.Array:
+		return map[string]interface{}{"type": "array", "items": typeSchema(t.Elem())}
+	case reflect.Map:
+		return map[string]interface{}{"type": "object", "additionalProperties": typeSchema(t.Elem())}
+	case reflect.Struct:
+		return structSchema(t)
+	case reflect.Ptr:
+		return typeSchema(t.Elem())
+	default:
+		// interface{} values, such as JIRA field values, can be anything
+		return map[string]interface{}{}
+	}
+}
+
+// structSchema returns the JSON Schema of an object with the exported fields
+// of struct type t, keyed by their config file keys.
+func structSchema(t reflect.Type) map[string]interface{} {
+	properties := make(map[string]interface{})
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+		key := strings.ToLower(field.Name)
+		if tag, ok := field.Tag.Lookup("mapstructure"); ok {
+			key = strings.Split(tag, ",")[0]
+		}
+		if key == "-" {
+			continue
+		}
+		properties[key] = typeSchema(field.Type)
+	}
+
+	return map[string]interface{}{
+		"type":                 "object",
+		"properties":           properties,
+		"additionalProperties": false,
+	}
+}
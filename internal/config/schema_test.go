@@ -0,0 +1,203 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// unknownKeys returns the keys of settings, as dotted paths, that schema
+// doesn't allow.
+func unknownKeys(prefix string, settings map[string]interface{}, schema map[string]interface{}) []string {
+	var unknown []string
+	properties, _ := schema["properties"].(map[string]interface{})
+	for key, value := range settings {
+		property, ok := properties[key].(map[string]interface{})
+		if !ok {
+			switch additional := schema["additionalProperties"].(type) {
+			case map[string]interface{}:
+				property = additional
+			case bool:
+				unknown = append(unknown, prefix+key)
+				continue
+			default:
+				// Not an object schema, so any value is allowed
+				continue
+			}
+		}
+
+		switch v := value.(type) {
+		case map[string]interface{}:
+			unknown = append(unknown, unknownKeys(prefix+key+".", v, property)...)
+		case []interface{}:
+			items, _ := property["items"].(map[string]interface{})
+			for _, item := range v {
+				if m, ok := item.(map[string]interface{}); ok && items != nil {
+					unknown = append(unknown, unknownKeys(prefix+key+"[].", m, items)...)
+				}
+			}
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func TestSchema(t *testing.T) {
+	schema := Schema()
+	assert.Equal(t, SchemaID, schema["$id"])
+	assert.Equal(t, false, schema["additionalProperties"])
+
+	properties := schema["properties"].(map[string]interface{})
+	jira := properties["jira"].(map[string]interface{})["properties"].(map[string]interface{})
+
+	tests := []struct {
+		name   string
+		schema interface{}
+		want   interface{}
+	}{
+		{"string", jira["baseurl"], map[string]interface{}{"type": "string"}},
+		{"integer", jira["max_consecutive_failures"], map[string]interface{}{"type": []string{"integer", "string"}}},
+		{"boolean", properties["read_only"], map[string]interface{}{"type": []string{"boolean", "string"}}},
+		{"duration", jira["timeout"], map[string]interface{}{"type": "string", "description": `A duration such as "30s" or "2m"`}},
+		{"list", properties["boards"], map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}},
+		{"any value", properties["required_fields"], map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, tt.schema)
+		})
+	}
+
+	profile := properties["profiles"].(map[string]interface{})["additionalProperties"].(map[string]interface{})
+	assert.Contains(t, profile["properties"], "jira")
+	assert.NotContains(t, profile["properties"], "profile")
+}
+
+func TestSchemaCoversConfigFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "glue.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(`
+github:
+  domain: github.com
+  token: ${GITHUB_TOKEN:-secret}
+jira:
+  baseurl: https://example.atlassian.net
+  username: bot@example.com
+  timeout: 30s
+  max_consecutive_failures: 5
+  close_transitions: [Done]
+  board_close_transitions:
+    OPS: [Resolve]
+  request_types:
+    HELP: "12"
+  multiple_parents: warn-and-skip
+  link_batch_size: 25
+  link_batch_pause: 1s
+  max_link_changes: 1000
+asana:
+  token: ${ASANA_TOKEN:-secret}
+  sections:
+    bug: Bugs
+notion:
+  token: ${NOTION_TOKEN:-secret}
+  database: db1
+  properties:
+    title: Name
+    status: Stage
+    url: Issue
+    key: Ticket
+clickup:
+  token: ${CLICKUP_TOKEN:-secret}
+  lists:
+    bug: "901"
+  closed_status: complete
+  open_status: to do
+youtrack:
+  baseurl: https://example.youtrack.cloud
+  token: ${YOUTRACK_TOKEN:-secret}
+  fields:
+    bug:
+      Type: Bug
+  resolved_state: Fixed
+  open_state: Open
+routes:
+  - board: PAY
+    labels: [area:payments]
+    paths: [services/payments]
+outbound:
+  url: https://tools.example.com/glue
+  secret: ${OUTBOUND_SECRET:-secret}
+  headers:
+    Authorization: Bearer token
+  template: '{"event":"{{.Event}}"}'
+  content_type: application/json
+  timeout: 10s
+users:
+  - jira: jdoe
+    github: jdoe
+hooks:
+  pre_sync:
+    - name: check
+      command: [true]
+      timeout: 10s
+  post_run:
+    - name: notify
+      url: https://hooks.example.com/glue
+      secret: ${HOOK_SECRET:-secret}
+  post_create:
+    - name: label
+      jql: type = Bug
+      fields:
+        labels: [glue]
+rules:
+  file: rules.star
+safety:
+  allow_repositories: ["myorg/*"]
+security:
+  issue_type: Bug
+  priorities:
+    critical: Blocker
+form_fields:
+  - heading: Environment
+    field: environment
+    option: false
+acceptance_criteria:
+  field: customfield_10100
+  format: checklist
+description:
+  sections: [metadata, body, footer]
+required_fields:
+  customfield_10010: {value: High}
+read_only: true
+synced_labels:
+  - remove: [needs-jira]
+    add: [glued]
+maintenance_windows:
+  - name: upgrade
+    schedule: "0 2 * * SUN"
+    duration: 3h
+    timezone: UTC
+boards: [FOO]
+board_aliases:
+  platform: PLTENG
+flags:
+  sync-descriptions: true
+profile: prod
+profiles:
+  prod:
+    jira:
+      baseurl: https://prod.atlassian.net
+    boards: [PROD]
+`), 0o644))
+
+	settings, err := readConfigFile(path)
+	require.NoError(t, err)
+	assert.Empty(t, unknownKeys("", settings, Schema()))
+
+	settings["jira"].(map[string]interface{})["close_transition"] = []interface{}{"Done"}
+	settings["profiles"].(map[string]interface{})["prod"].(map[string]interface{})["bords"] = []interface{}{"PROD"}
+	assert.Equal(t, []string{"jira.close_transition", "profiles.prod.bords"}, unknownKeys("", settings, Schema()))
+}
@@ -0,0 +1,185 @@
+// Package faults injects API failures to test how glue copes with unreliable
+// GitHub and JIRA servers. It is off unless GLUE_FAULT_RATE is set, e.g. to
+// 0.2 to fail a fifth of all requests with a rate limit, a server error or a
+// timeout. Failed requests are never sent, so the servers don't see them.
+package faults
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+const (
+	// RateEnv is the environment variable holding the fraction of requests to fail
+	RateEnv = "GLUE_FAULT_RATE"
+	// SeedEnv is the environment variable holding the random seed, for repeatable runs
+	SeedEnv = "GLUE_FAULT_SEED"
+)
+
+// Fault is a kind of injected failure.
+type Fault int
+
+const (
+	// RateLimited answers with 429 Too Many Requests
+	RateLimited Fault = iota
+	// ServerError answers with 500 Internal Server Error
+	ServerError
+	// Timeout fails the request with context.DeadlineExceeded
+	Timeout
+)
+
+// Transport fails a share of the requests passing through it and sends the
+// others to Base.
+type Transport struct {
+	// Base performs the requests that aren't failed; nil means http.DefaultTransport
+	Base http.RoundTripper
+	// Rate is the fraction of requests to fail, between 0 and 1
+	Rate float64
+
+	mu     sync.Mutex
+	random *rand.Rand
+	counts map[Fault]int
+}
+
+// New returns a transport failing rate of the requests, choosing them with
+// the given seed.
+func New(base http.RoundTripper, rate float64, seed int64) *Transport {
+	return &Transport{
+		Base:   base,
+		Rate:   rate,
+		random: rand.New(rand.NewSource(seed)),
+		counts: make(map[Fault]int),
+	}
+}
+
+// Wrap returns base wrapped in a fault-injecting transport if GLUE_FAULT_RATE
+// is set, or base unchanged otherwise. name identifies the client in logs.
+func Wrap(base http.RoundTripper, name string) (http.RoundTripper, error) {
+	value := os.Getenv(RateEnv)
+	if value == "" {
+		return base, nil
+	}
+
+	rate, err := strconv.ParseFloat(value, 64)
+	if err != nil || rate < 0 || rate > 1 {
+		return nil, fmt.Errorf("invalid %s %q, expected a number between 0 and 1", RateEnv, value)
+	}
+
+	seed := time.Now().UnixNano()
+	if value := os.Getenv(SeedEnv); value != "" {
+		seed, err = strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %v", SeedEnv, value, err)
+		}
+	}
+
+	logging.Warn("injecting faults into api requests",
+		"client", name,
+		"rate", rate,
+		"seed", seed)
+	return New(base, rate, seed), nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fault, inject := t.next()
+	if !inject {
+		base := t.Base
+		if base == nil {
+			base = http.DefaultTransport
+		}
+		return base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	logging.Debug("injecting fault",
+		"method", req.Method,
+		"path", req.URL.Path,
+		"fault", fault.String())
+
+	switch fault {
+	case RateLimited:
+		resp := response(req, http.StatusTooManyRequests)
+		resp.Header.Set("Retry-After", "1")
+		return resp, nil
+	case ServerError:
+		return response(req, http.StatusInternalServerError), nil
+	default:
+		return nil, fmt.Errorf("injected fault: %w", context.DeadlineExceeded)
+	}
+}
+
+// Counts returns how many faults of each kind were injected.
+func (t *Transport) Counts() map[Fault]int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	counts := make(map[Fault]int, len(t.counts))
+	for fault, n := range t.counts {
+		counts[fault] = n
+	}
+	return counts
+}
+
+// next decides whether to fail the next request, and how.
+func (t *Transport) next() (Fault, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.random == nil {
+		t.random = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	if t.counts == nil {
+		t.counts = make(map[Fault]int)
+	}
+
+	if t.random.Float64() >= t.Rate {
+		return 0, false
+	}
+	fault := Fault(t.random.Intn(3))
+	t.counts[fault]++
+	return fault, true
+}
+
+// String returns the name of the fault.
+func (f Fault) String() string {
+	switch f {
+	case RateLimited:
+		return "rate_limited"
+	case ServerError:
+		return "server_error"
+	case Timeout:
+		return "timeout"
+	default:
+		return "unknown"
+	}
+}
+
+// response builds an injected error response with a JSON body both APIs'
+// clients can decode.
+func response(req *http.Request, status int) *http.Response {
+	body := `{"message":"injected fault","errorMessages":["injected fault"]}`
+	header := make(http.Header)
+	header.Set("Content-Type", "application/json")
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
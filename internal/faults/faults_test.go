@@ -0,0 +1,74 @@
+package faults
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	// No faults at rate 0
+	client := &http.Client{Transport: New(nil, 0, 1)}
+	for i := 0; i < 10; i++ {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+	}
+	assert.Equal(t, 10, calls)
+
+	// Every request fails at rate 1 and none reaches the server
+	transport := New(nil, 1, 1)
+	client = &http.Client{Transport: transport}
+	for i := 0; i < 30; i++ {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			continue
+		}
+		resp.Body.Close()
+		assert.Contains(t, []int{http.StatusTooManyRequests, http.StatusInternalServerError}, resp.StatusCode)
+	}
+	assert.Equal(t, 10, calls)
+
+	counts := transport.Counts()
+	assert.Equal(t, 30, counts[RateLimited]+counts[ServerError]+counts[Timeout])
+	for _, fault := range []Fault{RateLimited, ServerError, Timeout} {
+		assert.Positive(t, counts[fault], fault.String())
+	}
+}
+
+func TestWrap(t *testing.T) {
+	base := http.DefaultTransport
+
+	t.Setenv(RateEnv, "")
+	transport, err := Wrap(base, "jira")
+	require.NoError(t, err)
+	assert.Equal(t, base, transport)
+
+	t.Setenv(RateEnv, "0.25")
+	t.Setenv(SeedEnv, "42")
+	transport, err = Wrap(base, "jira")
+	require.NoError(t, err)
+	require.IsType(t, &Transport{}, transport)
+	assert.Equal(t, 0.25, transport.(*Transport).Rate)
+
+	t.Setenv(RateEnv, "25%")
+	_, err = Wrap(base, "jira")
+	assert.ErrorContains(t, err, `invalid GLUE_FAULT_RATE "25%"`)
+
+	t.Setenv(RateEnv, "0.1")
+	t.Setenv(SeedEnv, "abc")
+	_, err = Wrap(base, "jira")
+	assert.ErrorContains(t, err, `invalid GLUE_FAULT_SEED "abc"`)
+}
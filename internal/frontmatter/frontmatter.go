@@ -0,0 +1,97 @@
+// Package frontmatter parses the sync settings of a single GitHub issue from a
+// fenced YAML block with a top-level "glue" key at the top of its body:
+//
+//	```yaml
+//	glue:
+//	  board: PROJ
+//	  type: story
+//	  fixVersion: PI 24.3
+//	```
+//
+// The settings override the label routing of that one issue.
+package frontmatter
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Overrides are the sync settings of an issue. Zero values keep the default
+// behaviour.
+type Overrides struct {
+	// Board is the JIRA project key the issue is synced to instead of its
+	// board labels
+	Board string `yaml:"board"`
+	// Type is "feature" or "story", instead of the native type or labels
+	Type string `yaml:"type"`
+	// FixVersion is the name of the fix version of the ticket, instead of the
+	// project's current PI version
+	FixVersion string `yaml:"fixVersion"`
+	// Skip leaves the issue out of the sync
+	Skip bool `yaml:"skip"`
+}
+
+// blockPattern matches a fenced code block at the start of a body, with an
+// optional yaml or yml info string.
+var blockPattern = regexp.MustCompile("(?s)^\\s*```(?:ya?ml)?[ \\t]*\\r?\\n(.*?)\\r?\\n```[ \\t]*(?:\\r?\\n|$)")
+
+// boardPattern matches a JIRA project key.
+var boardPattern = regexp.MustCompile(`^[A-Za-z][A-Za-z0-9_]*$`)
+
+// Parse returns the overrides of an issue body and the body without them. It
+// returns false if the body doesn't start with a fenced block holding a
+// "glue" key; other blocks are left alone. Invalid overrides, such as unknown
+// settings or an unknown type, are an error.
+func Parse(body string) (Overrides, string, bool, error) {
+	match := blockPattern.FindStringSubmatchIndex(body)
+	if match == nil {
+		return Overrides{}, body, false, nil
+	}
+	block := body[match[2]:match[3]]
+
+	var probe map[string]interface{}
+	if err := yaml.Unmarshal([]byte(block), &probe); err != nil {
+		return Overrides{}, body, false, nil
+	}
+	if _, ok := probe["glue"]; !ok {
+		return Overrides{}, body, false, nil
+	}
+
+	var doc struct {
+		Glue Overrides `yaml:"glue"`
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader([]byte(block)))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil && !errors.Is(err, io.EOF) {
+		return Overrides{}, body, true, fmt.Errorf("invalid glue front matter: %v", err)
+	}
+
+	overrides := doc.Glue
+	overrides.Board = strings.ToUpper(strings.TrimSpace(overrides.Board))
+	overrides.Type = strings.ToLower(strings.TrimSpace(overrides.Type))
+	overrides.FixVersion = strings.TrimSpace(overrides.FixVersion)
+	if err := overrides.validate(); err != nil {
+		return Overrides{}, body, true, fmt.Errorf("invalid glue front matter: %v", err)
+	}
+
+	return overrides, strings.TrimLeft(body[match[1]:], "\r\n"), true, nil
+}
+
+// validate returns an error describing the first invalid setting.
+func (o Overrides) validate() error {
+	if o.Board != "" && !boardPattern.MatchString(o.Board) {
+		return fmt.Errorf("board %q is not a JIRA project key", o.Board)
+	}
+	switch o.Type {
+	case "", "feature", "story":
+	default:
+		return fmt.Errorf("type %q must be feature or story", o.Type)
+	}
+	return nil
+}
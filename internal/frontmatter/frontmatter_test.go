@@ -0,0 +1,55 @@
+package frontmatter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParse(t *testing.T) {
+	body := "```yaml\nglue:\n  board: proj\n  type: Story\n  fixVersion: PI 24.3\n```\n\nThe actual description"
+
+	overrides, rest, ok, err := Parse(body)
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, Overrides{Board: "PROJ", Type: "story", FixVersion: "PI 24.3"}, overrides)
+	assert.Equal(t, "The actual description", rest)
+}
+
+func TestParseSkip(t *testing.T) {
+	overrides, rest, ok, err := Parse("```\nglue:\n  skip: true\n```")
+	assert.NoError(t, err)
+	assert.True(t, ok)
+	assert.True(t, overrides.Skip)
+	assert.Empty(t, rest)
+}
+
+func TestParseWithoutFrontMatter(t *testing.T) {
+	for _, body := range []string{
+		"",
+		"Just a description",
+		"```go\nfmt.Println(1)\n```",
+		"```yaml\nname: build\n```\nA workflow",
+		"Text first\n```yaml\nglue:\n  skip: true\n```",
+	} {
+		overrides, rest, ok, err := Parse(body)
+		assert.NoError(t, err, body)
+		assert.False(t, ok, body)
+		assert.Equal(t, Overrides{}, overrides)
+		assert.Equal(t, body, rest)
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for body, message := range map[string]string{
+		"```yaml\nglue:\n  type: epic\n```":      `type "epic" must be feature or story`,
+		"```yaml\nglue:\n  board: my-proj\n```":  `board "MY-PROJ" is not a JIRA project key`,
+		"```yaml\nglue:\n  boards: [PROJ]\n```":  "field boards not found",
+		"```yaml\nglue:\n  skip: sometimes\n```": "invalid glue front matter",
+	} {
+		_, rest, ok, err := Parse(body)
+		assert.True(t, ok, body)
+		assert.ErrorContains(t, err, message)
+		assert.Equal(t, body, rest)
+	}
+}
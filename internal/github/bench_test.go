@@ -0,0 +1,242 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// callBudget is the most GitHub requests per issue each sync phase may make.
+// Lower a budget after an optimization like batching so it can't regress;
+// raising one needs a good reason.
+var callBudget = map[string]float64{
+	"list":   0.02,
+	"closed": 0.02,
+	"title":  1,
+	"fetch":  1,
+}
+
+// timeBudget bounds the wall time per issue of each phase against the local
+// fake server. It is only checked by the benchmarks and is generous, to
+// catch gross regressions like quadratic loops rather than noise.
+const timeBudget = 5 * time.Millisecond
+
+// fakeGitHub is a minimal GitHub Enterprise server keeping the issues of
+// owner/repo.
+type fakeGitHub struct {
+	mu     sync.Mutex
+	issues []map[string]interface{}
+	// calls counts the requests served
+	calls int
+}
+
+func (f *fakeGitHub) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	w.Header().Set("Content-Type", "application/json")
+
+	path := strings.TrimPrefix(r.URL.Path, "/api/v3")
+	switch {
+	case path == "/user":
+		fmt.Fprint(w, `{"login":"glue"}`)
+	case path == "/repos/owner/repo/issues":
+		var matching []map[string]interface{}
+		for _, issue := range f.issues {
+			if issue["state"] == r.URL.Query().Get("state") {
+				matching = append(matching, issue)
+			}
+		}
+		perPage, _ := strconv.Atoi(r.URL.Query().Get("per_page"))
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page < 1 {
+			page = 1
+		}
+		start, end := min((page-1)*perPage, len(matching)), min(page*perPage, len(matching))
+		if end < len(matching) {
+			next := *r.URL
+			query := next.Query()
+			query.Set("page", strconv.Itoa(page+1))
+			next.RawQuery = query.Encode()
+			w.Header().Set("Link", fmt.Sprintf(`<https://%s%s>; rel="next"`, r.Host, next.RequestURI()))
+		}
+		json.NewEncoder(w).Encode(matching[start:end])
+	case strings.HasPrefix(path, "/repos/owner/repo/issues/"):
+		number, _ := strconv.Atoi(strings.TrimPrefix(path, "/repos/owner/repo/issues/"))
+		if number < 1 || number > len(f.issues) {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		issue := f.issues[number-1]
+		if r.Method == http.MethodPatch {
+			var update map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&update); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			for field, value := range update {
+				issue[field] = value
+			}
+		}
+		json.NewEncoder(w).Encode(issue)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// resetCalls returns the number of requests served and starts counting anew.
+func (f *fakeGitHub) resetCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := f.calls
+	f.calls = 0
+	return calls
+}
+
+// phaseResult measures one sync phase.
+type phaseResult struct {
+	name    string
+	issues  int
+	calls   int
+	elapsed time.Duration
+}
+
+// callsPerIssue returns the average number of requests per issue.
+func (p phaseResult) callsPerIssue() float64 {
+	return float64(p.calls) / float64(p.issues)
+}
+
+// newBenchClient returns a client of the fake server built by NewClient, so
+// requests go through the same transports as in a sync. The server is
+// reached as a GitHub Enterprise domain, trusting its certificate for the
+// rest of the test.
+func newBenchClient(tb testing.TB, server *httptest.Server) *Client {
+	defaultTransport := http.DefaultTransport
+	http.DefaultTransport = server.Client().Transport
+	tb.Cleanup(func() { http.DefaultTransport = defaultTransport })
+
+	configFile := filepath.Join(tb.TempDir(), "glue.yaml")
+	require.NoError(tb, os.WriteFile(configFile, nil, 0o644))
+	tb.Setenv("GLUE_CONFIG", configFile)
+	tb.Setenv("GITHUB_TOKEN", "github-token")
+	tb.Setenv("GITHUB_DOMAIN", server.Listener.Addr().String())
+
+	client, err := NewClient()
+	require.NoError(tb, err)
+	return client.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// runSyncPhases measures each phase of syncing count GitHub issues, every
+// third of them closed, from a fresh fake GitHub: listing the open issues,
+// listing the closed ones, prefixing open issues' titles with their new
+// ticket keys, and fetching every issue again as the hierarchy pass does.
+func runSyncPhases(tb testing.TB, count int) []phaseResult {
+	fake := &fakeGitHub{}
+	for number := 1; number <= count; number++ {
+		state := "open"
+		if number%3 == 0 {
+			state = "closed"
+		}
+		fake.issues = append(fake.issues, map[string]interface{}{
+			"number": number,
+			"title":  fmt.Sprintf("Issue %d", number),
+			"state":  state,
+		})
+	}
+	server := httptest.NewTLSServer(fake)
+	defer server.Close()
+
+	client := newBenchClient(tb, server)
+	ctx := context.Background()
+
+	var results []phaseResult
+	phase := func(name string, run func() int) {
+		fake.resetCalls()
+		start := time.Now()
+		n := run()
+		results = append(results, phaseResult{name: name, issues: n, calls: fake.resetCalls(), elapsed: time.Since(start)})
+	}
+
+	var open []int
+	phase("list", func() int {
+		issues, err := client.GetAllIssues(ctx, "owner/repo")
+		require.NoError(tb, err)
+		require.Len(tb, issues, count-count/3)
+		for _, issue := range issues {
+			open = append(open, issue.Number)
+		}
+		return len(issues)
+	})
+	phase("closed", func() int {
+		issues, err := client.GetClosedIssues(ctx, "owner/repo")
+		require.NoError(tb, err)
+		require.Len(tb, issues, count/3)
+		return len(issues)
+	})
+	phase("title", func() int {
+		for _, number := range open {
+			require.NoError(tb, client.UpdateIssueTitle(ctx, "owner/repo", number, fmt.Sprintf("[PROJ-%d] Issue %d", number, number)))
+		}
+		return len(open)
+	})
+	phase("fetch", func() int {
+		for number := 1; number <= count; number++ {
+			_, err := client.GetIssue(ctx, "owner/repo", number)
+			require.NoError(tb, err)
+		}
+		return count
+	})
+
+	return results
+}
+
+// checkCallBudget fails if a phase made more requests per issue than its budget.
+func checkCallBudget(tb testing.TB, results []phaseResult) {
+	for _, result := range results {
+		if got := result.callsPerIssue(); got > callBudget[result.name] {
+			tb.Errorf("%s phase made %.2f github requests per issue, budget is %.2f", result.name, got, callBudget[result.name])
+		}
+	}
+}
+
+func TestSyncCallBudget(t *testing.T) {
+	checkCallBudget(t, runSyncPhases(t, 300))
+}
+
+// BenchmarkSync reports the requests and wall time per issue of each sync
+// phase for large repositories. Run with:
+//
+//	go test ./internal/github -run '^$' -bench Sync -benchtime 1x
+func BenchmarkSync(b *testing.B) {
+	for _, count := range []int{1000, 5000} {
+		b.Run(fmt.Sprintf("%dk", count/1000), func(b *testing.B) {
+			var results []phaseResult
+			for i := 0; i < b.N; i++ {
+				results = runSyncPhases(b, count)
+			}
+
+			checkCallBudget(b, results)
+			for _, result := range results {
+				perIssue := result.elapsed / time.Duration(result.issues)
+				b.ReportMetric(result.callsPerIssue(), result.name+"-calls/issue")
+				b.ReportMetric(float64(perIssue.Microseconds()), result.name+"-us/issue")
+				if perIssue > timeBudget {
+					b.Errorf("%s phase took %v per issue, budget is %v", result.name, perIssue, timeBudget)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// apiVersion is the GitHub REST API version requested from servers that
+// support versioning.
+const apiVersion = "2022-11-28"
+
+// enterpriseVersionHeader is the response header in which GitHub Enterprise
+// Server reports its release, e.g. "3.9.2".
+const enterpriseVersionHeader = "X-GitHub-Enterprise-Version"
+
+// ErrUnsupported means the GitHub Enterprise Server release glue talks to
+// lacks an API feature. Use errors.Is to check for it.
+var ErrUnsupported = errors.New("unsupported by GitHub Enterprise Server")
+
+// Feature is a GitHub API feature that older GitHub Enterprise Server
+// releases lack. github.com supports all features.
+type Feature struct {
+	// Name describes the feature in messages
+	Name string
+	// Since is the first GitHub Enterprise Server release supporting it
+	Since string
+}
+
+var (
+	// FeatureAPIVersions is the X-GitHub-Api-Version request header.
+	FeatureAPIVersions = Feature{Name: "REST API versions", Since: "3.9"}
+	// FeatureCommitPullRequests lists the pull requests containing a commit,
+	// used to find the pull request that closed an issue.
+	FeatureCommitPullRequests = Feature{Name: "pull requests of a commit", Since: "3.3"}
+)
+
+// EnterpriseVersion returns the GitHub Enterprise Server release the client
+// talks to, or "" for github.com.
+func (c *Client) EnterpriseVersion() string {
+	return c.enterpriseVersion
+}
+
+// Supports reports whether the GitHub server the client talks to has a feature.
+func (c *Client) Supports(feature Feature) bool {
+	return versionAtLeast(c.enterpriseVersion, feature.Since)
+}
+
+// Require returns an error matching ErrUnsupported that names the release
+// needed if the GitHub server the client talks to lacks a feature.
+func (c *Client) Require(feature Feature) error {
+	if c.Supports(feature) {
+		return nil
+	}
+	return fmt.Errorf("%s: %w %s (needs %s or later)",
+		feature.Name, ErrUnsupported, c.enterpriseVersion, feature.Since)
+}
+
+// versionAtLeast reports whether a GitHub Enterprise Server release is at
+// least since. An empty version means github.com, which is always current;
+// so is a version that can't be parsed, to avoid disabling features on a
+// server glue doesn't understand.
+func versionAtLeast(version, since string) bool {
+	have, ok := parseVersion(version)
+	if !ok {
+		return true
+	}
+	want, ok := parseVersion(since)
+	if !ok {
+		return true
+	}
+	if have[0] != want[0] {
+		return have[0] > want[0]
+	}
+	return have[1] >= want[1]
+}
+
+// parseVersion returns the major and minor numbers of a release like "3.9.2".
+func parseVersion(version string) ([2]int, bool) {
+	parts := strings.SplitN(strings.TrimSpace(version), ".", 3)
+	if len(parts) < 2 {
+		return [2]int{}, false
+	}
+	major, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return [2]int{}, false
+	}
+	minor, err := strconv.Atoi(parts[1])
+	if err != nil {
+		return [2]int{}, false
+	}
+	return [2]int{major, minor}, true
+}
+
+// versionTransport sets the X-GitHub-Api-Version header on requests once the
+// server is known to support it, so responses keep their shape when GitHub
+// releases a new API version.
+type versionTransport struct {
+	// Base is the transport requests are sent with
+	Base http.RoundTripper
+	// Version is the API version to request; empty sends no header
+	Version string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *versionTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if t.Version == "" {
+		return t.Base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("X-GitHub-Api-Version", t.Version)
+	return t.Base.RoundTrip(req)
+}
@@ -0,0 +1,66 @@
+package github
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionAtLeast(t *testing.T) {
+	tests := []struct {
+		name    string
+		version string
+		since   string
+		want    bool
+	}{
+		{"github.com", "", "3.9", true},
+		{"same release", "3.9.0", "3.9", true},
+		{"newer patch", "3.9.4", "3.9", true},
+		{"newer minor", "3.12.1", "3.9", true},
+		{"newer major", "4.0.0", "3.9", true},
+		{"older minor", "3.8.2", "3.9", false},
+		{"older major", "2.22.10", "3.3", false},
+		{"unparseable", "enterprise", "3.9", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, versionAtLeast(tt.version, tt.since))
+		})
+	}
+}
+
+func TestRequire(t *testing.T) {
+	assert.NoError(t, (&Client{}).Require(FeatureAPIVersions))
+	assert.NoError(t, (&Client{enterpriseVersion: "3.10.0"}).Require(FeatureAPIVersions))
+
+	err := (&Client{enterpriseVersion: "3.8.1"}).Require(FeatureAPIVersions)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.Equal(t, "REST API versions: unsupported by GitHub Enterprise Server 3.8.1 (needs 3.9 or later)", err.Error())
+}
+
+func TestVersionTransport(t *testing.T) {
+	var got []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.Header.Get("X-GitHub-Api-Version"))
+	}))
+	defer server.Close()
+
+	transport := &versionTransport{Base: http.DefaultTransport}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	transport.Version = apiVersion
+	resp, err = client.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{"", apiVersion}, got)
+}
@@ -5,13 +5,22 @@ import (
 	"context"
 
 	"fmt"
+	"log/slog"
 	"net/http"
 	"regexp"
 	"strings"
 	"time"
 	"net/url"
 
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/httpdebug"
 	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/memo"
+	"github.com/danielolaszy/glue/internal/ratelimit"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/reauth"
+	"github.com/danielolaszy/glue/internal/recorder"
 	"github.com/danielolaszy/glue/pkg/models"
 	"github.com/google/go-github/v41/github"
 	"golang.org/x/oauth2"
@@ -23,10 +32,20 @@ import (
 // retries, and error handling.
 type Client struct {
 	client *github.Client
-	ctx    context.Context
-	cancel context.CancelFunc
+	logger *slog.Logger
+	// enterpriseVersion is the GitHub Enterprise Server release, "" for github.com
+	enterpriseVersion string
+	// syncedLabels are the label changes applied to issues once they have a ticket
+	syncedLabels config.SyncedLabels
+	// issueFunc adjusts fetched issues; may be nil
+	issueFunc IssueFunc
+	// responses remembers fetched issues; may be nil
+	responses *memo.Transport
 }
 
+// requestTimeout bounds each attempt of a GitHub request.
+const requestTimeout = 30 * time.Second
+
 // NewClient creates a new GitHub client with authentication, retries, and an extended timeout.
 // It uses the provided configuration, tests the authentication by retrieving the current
 // user, and returns a configured client or an error if authentication fails.
@@ -37,25 +56,56 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("failed to load configuration: %v", err)
 	}
 
-	// Increase timeout to 30 seconds
-	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
-
-	// Create an HTTP client with longer timeouts
-	httpClient := &http.Client{
-		Timeout: 30 * time.Second,
-	}
-
 	logging.Debug("initializing github client",
 		"domain", cfg.GitHub.Domain,
 		"token_length", len(cfg.GitHub.Token),
 		"token_prefix", cfg.GitHub.Token[:5]+"...") // Only log first 5 chars for security
 
-	ts := oauth2.StaticTokenSource(
-		&oauth2.Token{AccessToken: cfg.GitHub.Token},
-	)
+	// The token is reloaded from the config when GitHub rejects it, so a
+	// rotated token doesn't end a long run
+	credentials := reauth.NewCredentials("", cfg.GitHub.Token, func() (string, string, error) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return "", "", err
+		}
+		return "", cfg.GitHub.Token, nil
+	})
 	// Use our custom httpClient as the base client
-	tc := oauth2.NewClient(ctx, ts)
-	tc.Timeout = httpClient.Timeout
+	tc := &http.Client{
+		Transport: &reauth.Transport{
+			Base:        &oauth2.Transport{Source: tokenSource{credentials: credentials}},
+			Credentials: credentials,
+			Service:     "github",
+		},
+	}
+	// Recording sits above the token transport so the token is never seen
+	tc.Transport, err = recorder.Wrap(tc.Transport, "github", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	tc.Transport, err = faults.Wrap(tc.Transport, "github")
+	if err != nil {
+		return nil, err
+	}
+	tc.Transport = httpdebug.Wrap(tc.Transport, "github")
+	// The timeout bounds each attempt, so waiting out throttling doesn't
+	// count against it
+	limited := ratelimit.Wrap(tc.Transport, "github")
+	limited.Timeout = requestTimeout
+	tc.Transport = limited
+	responses := memo.New(tc.Transport)
+	tc.Transport = responses
+	if cfg.ReadOnly {
+		logging.Info("github client is read-only, changes will be refused")
+		tc.Transport = &readonly.Transport{Base: tc.Transport, Reads: isGraphQLQuery}
+	}
+	// github.com always supports API versions; for GitHub Enterprise the header
+	// is only sent once the server's release is known to support it
+	versions := &versionTransport{Base: tc.Transport}
+	if cfg.GitHub.Domain == "github.com" {
+		versions.Version = apiVersion
+	}
+	tc.Transport = versions
 
 	client := github.NewClient(tc)
 
@@ -64,64 +114,121 @@ func NewClient() (*Client, error) {
 		enterpriseAPIURL := fmt.Sprintf("https://%s/api/v3/", cfg.GitHub.Domain)
 		baseURL, err := url.Parse(enterpriseAPIURL)
 		if err != nil {
-			cancel()
 			return nil, fmt.Errorf("invalid GitHub Enterprise URL: %v", err)
 		}
 		client.BaseURL = baseURL
 		logging.Debug("using GitHub Enterprise API URL", "url", enterpriseAPIURL)
 	}
 
-	// Test authentication
+	// Test authentication; only this check is bounded, API calls take the
+	// caller's context so long-running commands keep working
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
 	maxRetries := 3
 	var user *github.User
+	var resp *github.Response
 
 	for attempt := 1; attempt <= maxRetries; attempt++ {
 		logging.Debug("testing github authentication",
 			"attempt", attempt,
 			"max_retries", maxRetries)
 
-		user, _, err = client.Users.Get(ctx, "")
+		user, resp, err = client.Users.Get(ctx, "")
 		if err == nil {
 			break
 		}
 
 		if attempt < maxRetries {
+			var httpResp *http.Response
+			if resp != nil {
+				httpResp = resp.Response
+			}
+			wait := ratelimit.Delay(httpResp, attempt, time.Now())
 			logging.Warn("github authentication attempt failed, retrying...",
 				"attempt", attempt,
+				"wait", wait,
 				"error", err)
-			time.Sleep(time.Second * time.Duration(attempt))
+			time.Sleep(wait)
 		}
 	}
 
 	if err != nil {
-		cancel()
-		return nil, fmt.Errorf("failed to authenticate with github: %v", err)
+		return nil, fmt.Errorf("failed to authenticate with github: %w", apiError(err))
 	}
 
 	logging.Info("github authentication successful",
 		"username", user.GetLogin())
 
-	return &Client{
-		client: client,
-		ctx:    ctx,
-		cancel: cancel,
-	}, nil
+	c := &Client{
+		client:       client,
+		syncedLabels: cfg.SyncedLabels,
+		responses:    responses,
+	}
+	if resp != nil {
+		c.enterpriseVersion = resp.Header.Get(enterpriseVersionHeader)
+	}
+	if c.enterpriseVersion != "" {
+		logging.Info("detected github enterprise server", "version", c.enterpriseVersion)
+		if c.Supports(FeatureAPIVersions) {
+			versions.Version = apiVersion
+		} else {
+			logging.Debug("github enterprise server predates api versions, sending none",
+				"version", c.enterpriseVersion)
+		}
+	}
+
+	return c, nil
+}
+
+// tokenSource supplies the current, possibly reloaded, GitHub token.
+type tokenSource struct {
+	credentials *reauth.Credentials
+}
+
+// Token implements oauth2.TokenSource.
+func (s tokenSource) Token() (*oauth2.Token, error) {
+	_, token := s.credentials.Get()
+	return &oauth2.Token{AccessToken: token}, nil
+}
+
+// WithLogger returns a shallow copy of the client that writes its log output to
+// logger, e.g. one carrying a per-issue trace ID.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	scoped := *c
+	scoped.logger = logger
+	return &scoped
+}
+
+// ForgetResponses forgets the issues fetched so far, so they are fetched
+// anew, e.g. before a long-running process starts another run.
+func (c *Client) ForgetResponses() {
+	if c.responses != nil {
+		c.responses.Reset()
+	}
+}
+
+// log returns the client's logger, falling back to the default logger.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
 }
 
 // GetAllIssues retrieves all open issues from a GitHub repository.
 // It filters out pull requests and converts the GitHub API objects to our internal model.
 // The repository should be in the format "owner/repo". It returns a slice of issues
 // or an error if the retrieval fails.
-func (c *Client) GetAllIssues(repository string) ([]models.GitHubIssue, error) {
+func (c *Client) GetAllIssues(ctx context.Context, repository string) ([]models.GitHubIssue, error) {
 	// Parse repository owner and name
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid repository format: %s, expected format: owner/repo", repository)
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
 	}
 	owner, repo := parts[0], parts[1]
 
 	// Context for API requests
-	ctx := context.Background()
 
 	// Get all open issues
 	opts := &github.IssueListByRepoOptions{
@@ -135,8 +242,8 @@ func (c *Client) GetAllIssues(repository string) ([]models.GitHubIssue, error) {
 	for {
 		issues, resp, err := c.client.Issues.ListByRepo(ctx, owner, repo, opts)
 		if err != nil {
-			logging.Error("failed to fetch github issues", "error", err)
-			return nil, fmt.Errorf("failed to fetch GitHub issues: %v", err)
+			c.log().Error("failed to fetch github issues", "error", err)
+			return nil, fmt.Errorf("failed to fetch GitHub issues: %w", apiError(err))
 		}
 
 		allIssues = append(allIssues, issues...)
@@ -151,27 +258,11 @@ func (c *Client) GetAllIssues(repository string) ([]models.GitHubIssue, error) {
 	var result []models.GitHubIssue
 	for _, issue := range allIssues {
 		// Skip pull requests (they're also returned by the Issues API)
-		if issue.PullRequestLinks != nil {
+		if issue == nil || issue.PullRequestLinks != nil {
 			continue
 		}
 
-		// Convert to our internal model
-		labelNames := make([]string, 0, len(issue.Labels))
-		for _, label := range issue.Labels {
-			labelNames = append(labelNames, *label.Name)
-		}
-
-		description := ""
-		if issue.Body != nil {
-			description = *issue.Body
-		}
-
-		result = append(result, models.GitHubIssue{
-			Number:      *issue.Number,
-			Title:       *issue.Title,
-			Description: description,
-			Labels:      labelNames,
-		})
+		result = append(result, c.convertIssueOf(repository, issue))
 	}
 
 	return result, nil
@@ -180,19 +271,18 @@ func (c *Client) GetAllIssues(repository string) ([]models.GitHubIssue, error) {
 // AddLabels adds one or more labels to a GitHub issue. If the labels don't exist
 // in the repository, GitHub will automatically create them. The repository should be
 // in the format "owner/repo". It returns an error if the operation fails.
-func (c *Client) AddLabels(repository string, issueNumber int, labels ...string) error {
+func (c *Client) AddLabels(ctx context.Context, repository string, issueNumber int, labels ...string) error {
 	// Parse repository owner and name
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid repository format: %s, expected format: owner/repo", repository)
+		return apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
 	}
 	owner, repo := parts[0], parts[1]
 
 	// Context for API requests
-	ctx := context.Background()
 
 	// Log the operation
-	logging.Debug("adding labels", "labels", labels, "issue_number", issueNumber)
+	c.log().Debug("adding labels", "labels", labels, "issue_number", issueNumber)
 
 	// Add the labels to the issue
 	// GitHub will automatically create labels that don't exist
@@ -200,30 +290,151 @@ func (c *Client) AddLabels(repository string, issueNumber int, labels ...string)
 
 	// Check for errors
 	if err != nil {
-		logging.Error("error adding labels to issue", "repository", repository, "issue_number", issueNumber, "error", err)
-		return fmt.Errorf("failed to add labels to issue %s#%d: %v", repo, issueNumber, err)
+		c.log().Error("error adding labels to issue", "repository", repository, "issue_number", issueNumber, "error", err)
+		return fmt.Errorf("failed to add labels to issue %s#%d: %w", repo, issueNumber, apiError(err))
+	}
+
+	c.log().Debug("successfully added labels", "labels", labels, "repository", repository, "issue_number", issueNumber)
+	return nil
+}
+
+// RemoveLabel removes a label from a GitHub issue. The repository should be
+// in the format "owner/repo". It returns an error if the operation fails.
+func (c *Client) RemoveLabel(ctx context.Context, repository string, issueNumber int, label string) error {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+	c.log().Debug("removing label", "label", label, "issue_number", issueNumber)
+
+	_, err := c.client.Issues.RemoveLabelForIssue(ctx, owner, repo, issueNumber, label)
+	if err != nil {
+		return fmt.Errorf("failed to remove label from issue %s#%d: %w", repo, issueNumber, apiError(err))
+	}
+	return nil
+}
+
+// AddAssignees assigns one or more users to a GitHub issue. The repository should
+// be in the format "owner/repo". It returns an error if the operation fails.
+func (c *Client) AddAssignees(ctx context.Context, repository string, issueNumber int, logins ...string) error {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
 	}
+	owner, repo := parts[0], parts[1]
+
+	c.log().Debug("adding assignees", "assignees", logins, "issue_number", issueNumber)
 
-	logging.Debug("successfully added labels", "labels", labels, "repository", repository, "issue_number", issueNumber)
+	_, _, err := c.client.Issues.AddAssignees(ctx, owner, repo, issueNumber, logins)
+	if err != nil {
+		return fmt.Errorf("failed to add assignees to issue %s#%d: %w", repo, issueNumber, apiError(err))
+	}
 	return nil
 }
 
+// RemoveAssignees unassigns one or more users from a GitHub issue. The repository
+// should be in the format "owner/repo". It returns an error if the operation fails.
+func (c *Client) RemoveAssignees(ctx context.Context, repository string, issueNumber int, logins ...string) error {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+	c.log().Debug("removing assignees", "assignees", logins, "issue_number", issueNumber)
+
+	_, _, err := c.client.Issues.RemoveAssignees(ctx, owner, repo, issueNumber, logins)
+	if err != nil {
+		return fmt.Errorf("failed to remove assignees from issue %s#%d: %w", repo, issueNumber, apiError(err))
+	}
+	return nil
+}
+
+// ListLabels retrieves the names of all labels defined in a GitHub repository.
+// The repository should be in the format "owner/repo". It returns a slice of
+// label names or an error if the retrieval fails.
+func (c *Client) ListLabels(ctx context.Context, repository string) ([]string, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+	c.log().Debug("listing repository labels", "repository", repository)
+
+	opts := &github.ListOptions{PerPage: 100}
+
+	var names []string
+	for {
+		labels, resp, err := c.client.Issues.ListLabels(ctx, owner, repo, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list labels for %s: %w", repository, apiError(err))
+		}
+
+		for _, label := range labels {
+			names = append(names, label.GetName())
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	c.log().Debug("found repository labels", "repository", repository, "count", len(names))
+	return names, nil
+}
+
+// GetRepository retrieves a GitHub repository. The repository should be in
+// the format "owner/repo". GitHub redirects requests for a renamed repository,
+// so the returned FullName is its current name.
+func (c *Client) GetRepository(ctx context.Context, repository string) (models.GitHubRepository, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return models.GitHubRepository{}, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	repo, _, err := c.client.Repositories.Get(ctx, parts[0], parts[1])
+	if err != nil {
+		return models.GitHubRepository{}, fmt.Errorf("failed to get repository %s: %w", repository, apiError(err))
+	}
+	return models.GitHubRepository{FullName: repo.GetFullName(), Archived: repo.GetArchived()}, nil
+}
+
+// ListTopics retrieves the topics of a GitHub repository. The repository
+// should be in the format "owner/repo".
+func (c *Client) ListTopics(ctx context.Context, repository string) ([]string, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	c.log().Debug("listing repository topics", "repository", repository)
+
+	topics, _, err := c.client.Repositories.ListAllTopics(ctx, parts[0], parts[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to list topics for %s: %w", repository, apiError(err))
+	}
+	return topics, nil
+}
+
 // GetLabelsForIssue retrieves all labels for a specific GitHub issue and returns
 // them as string names. The repository should be in the format "owner/repo".
 // It returns a slice of label names or an error if the retrieval fails.
-func (c *Client) GetLabelsForIssue(repository string, issueNumber int) ([]string, error) {
+func (c *Client) GetLabelsForIssue(ctx context.Context, repository string, issueNumber int) ([]string, error) {
 	// Parse repository owner and name
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid repository format: %s, expected format: owner/repo", repository)
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
 	}
 	owner, repo := parts[0], parts[1]
 
 	// Context for API requests
-	ctx := context.Background()
 
 	// Log the operation
-	logging.Debug("retrieving labels", "repository", repository, "issue_number", issueNumber)
+	c.log().Debug("retrieving labels", "repository", repository, "issue_number", issueNumber)
 
 	// Get the labels for the issue
 	// The GitHub API returns an array of label objects
@@ -231,8 +442,8 @@ func (c *Client) GetLabelsForIssue(repository string, issueNumber int) ([]string
 
 	// Check for errors
 	if err != nil {
-		logging.Error("error retrieving labels", "repository", repository, "issue_number", issueNumber, "error", err)
-		return nil, fmt.Errorf("failed to retrieve labels for issue %s#%d: %v", repo, issueNumber, err)
+		c.log().Error("error retrieving labels", "repository", repository, "issue_number", issueNumber, "error", err)
+		return nil, fmt.Errorf("failed to retrieve labels for issue %s#%d: %w", repo, issueNumber, apiError(err))
 	}
 
 	// Convert the GitHub label objects to an array of strings
@@ -242,16 +453,16 @@ func (c *Client) GetLabelsForIssue(repository string, issueNumber int) ([]string
 		labelNames[i] = label.GetName()
 	}
 
-	logging.Debug("successfully retrieved labels", "repository", repository, "issue_number", issueNumber, "number_of_labels", len(labelNames))
+	c.log().Debug("successfully retrieved labels", "repository", repository, "issue_number", issueNumber, "number_of_labels", len(labelNames))
 	return labelNames, nil
 }
 
 // HasLabel checks if a GitHub issue has a specific label using exact matching.
 // The repository should be in the format "owner/repo". It returns true if the
 // label is found, false otherwise, and any error encountered during checking.
-func (c *Client) HasLabel(repository string, issueNumber int, labelName string) (bool, error) {
+func (c *Client) HasLabel(ctx context.Context, repository string, issueNumber int, labelName string) (bool, error) {
 	// Get all labels for the issue
-	labels, err := c.GetLabelsForIssue(repository, issueNumber)
+	labels, err := c.GetLabelsForIssue(ctx, repository, issueNumber)
 	if err != nil {
 		return false, err
 	}
@@ -269,9 +480,9 @@ func (c *Client) HasLabel(repository string, issueNumber int, labelName string)
 // HasLabelMatching checks if a GitHub issue has any label matching a regular expression pattern.
 // The repository should be in the format "owner/repo". It returns true if any label
 // matches the pattern, false otherwise, and any error encountered during checking.
-func (c *Client) HasLabelMatching(repository string, issueNumber int, pattern *regexp.Regexp) (bool, error) {
+func (c *Client) HasLabelMatching(ctx context.Context, repository string, issueNumber int, pattern *regexp.Regexp) (bool, error) {
 	// Get all labels for the issue
-	labels, err := c.GetLabelsForIssue(repository, issueNumber)
+	labels, err := c.GetLabelsForIssue(ctx, repository, issueNumber)
 	if err != nil {
 		return false, err
 	}
@@ -289,26 +500,25 @@ func (c *Client) HasLabelMatching(repository string, issueNumber int, pattern *r
 // IsIssueClosed checks if a GitHub issue is closed.
 // The repository should be in the format "owner/repo". It returns true if the issue
 // is closed, false if it's open, and any error encountered during checking.
-func (c *Client) IsIssueClosed(repository string, issueNumber int) (bool, error) {
+func (c *Client) IsIssueClosed(ctx context.Context, repository string, issueNumber int) (bool, error) {
 	// Parse repository owner and name
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return false, fmt.Errorf("invalid repository format: %s, expected format: owner/repo", repository)
+		return false, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
 	}
 	owner, repo := parts[0], parts[1]
 
 	// Context for API requests
-	ctx := context.Background()
 
 	// Get the issue
 	issue, resp, err := c.client.Issues.Get(ctx, owner, repo, issueNumber)
 	if err != nil {
-		logging.Error("failed to get github issue",
+		c.log().Error("failed to get github issue",
 			"repository", repository,
 			"issue_number", issueNumber,
 			"error", err,
 			"status_code", resp.StatusCode)
-		return false, fmt.Errorf("failed to get GitHub issue: %v", err)
+		return false, fmt.Errorf("failed to get GitHub issue: %w", apiError(err))
 	}
 
 	// Check the state of the issue
@@ -319,16 +529,15 @@ func (c *Client) IsIssueClosed(repository string, issueNumber int) (bool, error)
 // It filters out pull requests and converts the GitHub API objects to our internal model.
 // The repository should be in the format "owner/repo". It returns a slice of issues
 // or an error if the retrieval fails.
-func (c *Client) GetClosedIssues(repository string) ([]models.GitHubIssue, error) {
+func (c *Client) GetClosedIssues(ctx context.Context, repository string) ([]models.GitHubIssue, error) {
 	// Parse repository owner and name
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return nil, fmt.Errorf("invalid repository format: %s, expected format: owner/repo", repository)
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
 	}
 	owner, repo := parts[0], parts[1]
 
 	// Context for API requests
-	ctx := context.Background()
 
 	// Get all closed issues
 	opts := &github.IssueListByRepoOptions{
@@ -342,8 +551,8 @@ func (c *Client) GetClosedIssues(repository string) ([]models.GitHubIssue, error
 	for {
 		issues, resp, err := c.client.Issues.ListByRepo(ctx, owner, repo, opts)
 		if err != nil {
-			logging.Error("failed to fetch closed github issues", "error", err)
-			return nil, fmt.Errorf("failed to fetch GitHub closed issues: %v", err)
+			c.log().Error("failed to fetch closed github issues", "error", err)
+			return nil, fmt.Errorf("failed to fetch GitHub closed issues: %w", apiError(err))
 		}
 
 		allIssues = append(allIssues, issues...)
@@ -358,35 +567,19 @@ func (c *Client) GetClosedIssues(repository string) ([]models.GitHubIssue, error
 	var result []models.GitHubIssue
 	for _, issue := range allIssues {
 		// Skip pull requests (they're also returned by the Issues API)
-		if issue.PullRequestLinks != nil {
+		if issue == nil || issue.PullRequestLinks != nil {
 			continue
 		}
 
-		// Convert to our internal model
-		labelNames := make([]string, 0, len(issue.Labels))
-		for _, label := range issue.Labels {
-			labelNames = append(labelNames, *label.Name)
-		}
-
-		description := ""
-		if issue.Body != nil {
-			description = *issue.Body
-		}
-
-		result = append(result, models.GitHubIssue{
-			Number:      *issue.Number,
-			Title:       *issue.Title,
-			Description: description,
-			Labels:      labelNames,
-		})
+		result = append(result, c.convertIssueOf(repository, issue))
 	}
 
 	return result, nil
 }
 
 // GetIssuesWithLabel retrieves all open issues that have a specific label
-func (c *Client) GetIssuesWithLabel(repository, label string) ([]models.GitHubIssue, error) {
-	logging.Debug("fetching github issues with label",
+func (c *Client) GetIssuesWithLabel(ctx context.Context, repository, label string) ([]models.GitHubIssue, error) {
+	c.log().Debug("fetching github issues with label",
 		"repository", repository,
 		"label", label)
 
@@ -399,29 +592,16 @@ func (c *Client) GetIssuesWithLabel(repository, label string) ([]models.GitHubIs
 
 	var allIssues []models.GitHubIssue
 	for {
-		result, resp, err := c.client.Search.Issues(context.Background(), query, opts)
+		result, resp, err := c.client.Search.Issues(ctx, query, opts)
 		if err != nil {
-			return nil, fmt.Errorf("failed to search issues: %v", err)
+			return nil, fmt.Errorf("failed to search issues: %w", apiError(err))
 		}
 
 		for _, issue := range result.Issues {
-			labels := make([]string, 0, len(issue.Labels))
-			for _, label := range issue.Labels {
-				if label.Name != nil {
-					labels = append(labels, *label.Name)
-				}
+			if issue == nil {
+				continue
 			}
-
-			allIssues = append(allIssues, models.GitHubIssue{
-				Number:      *issue.Number,
-				Title:       *issue.Title,
-				Description: *issue.Body,
-				State:       *issue.State,
-				CreatedAt:   *issue.CreatedAt,
-				UpdatedAt:   *issue.UpdatedAt,
-				ClosedAt:    issue.ClosedAt,
-				Labels:      labels,
-			})
+			allIssues = append(allIssues, c.convertIssueOf(repository, issue))
 		}
 
 		if resp.NextPage == 0 {
@@ -433,60 +613,137 @@ func (c *Client) GetIssuesWithLabel(repository, label string) ([]models.GitHubIs
 	return allIssues, nil
 }
 
+// CreateIssue opens a GitHub issue with the given title, body and labels.
+// Labels that don't exist in the repository are created by GitHub. The
+// repository should be in the format "owner/repo". It returns the new issue.
+func (c *Client) CreateIssue(ctx context.Context, repository, title, body string, labels []string) (models.GitHubIssue, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return models.GitHubIssue{}, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	c.log().Debug("creating github issue", "repository", repository, "title", title, "labels", labels)
+
+	issue, _, err := c.client.Issues.Create(ctx, parts[0], parts[1], &github.IssueRequest{
+		Title:  &title,
+		Body:   &body,
+		Labels: &labels,
+	})
+	if err != nil {
+		return models.GitHubIssue{}, fmt.Errorf("failed to create issue in %s: %w", repository, apiError(err))
+	}
+	if issue == nil {
+		return models.GitHubIssue{}, fmt.Errorf("failed to create issue: empty response for %s", repository)
+	}
+
+	return convertIssue(issue), nil
+}
+
 // UpdateIssueTitle updates the title of a GitHub issue
-func (c *Client) UpdateIssueTitle(repository string, issueNumber int, newTitle string) error {
+func (c *Client) UpdateIssueTitle(ctx context.Context, repository string, issueNumber int, newTitle string) error {
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return fmt.Errorf("invalid repository format: %s", repository)
+		return apierror.Invalid("repository", "invalid repository format: %s", repository)
 	}
 
 	issue := &github.IssueRequest{
 		Title: &newTitle,
 	}
 
-	_, _, err := c.client.Issues.Edit(context.Background(), parts[0], parts[1], issueNumber, issue)
+	_, _, err := c.client.Issues.Edit(ctx, parts[0], parts[1], issueNumber, issue)
+	if err != nil {
+		return fmt.Errorf("failed to update issue title: %w", apiError(err))
+	}
+
+	return nil
+}
+
+// UpdateIssueBody replaces the body of a GitHub issue
+func (c *Client) UpdateIssueBody(ctx context.Context, repository string, issueNumber int, body string) error {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return apierror.Invalid("repository", "invalid repository format: %s", repository)
+	}
+
+	c.log().Debug("updating github issue body", "repository", repository, "issue_number", issueNumber)
+
+	_, _, err := c.client.Issues.Edit(ctx, parts[0], parts[1], issueNumber, &github.IssueRequest{
+		Body: &body,
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update issue title: %v", err)
+		return fmt.Errorf("failed to update issue body: %w", apiError(err))
 	}
 
 	return nil
 }
 
 // GetIssue retrieves a specific GitHub issue by number
-func (c *Client) GetIssue(repository string, issueNumber int) (models.GitHubIssue, error) {
+func (c *Client) GetIssue(ctx context.Context, repository string, issueNumber int) (models.GitHubIssue, error) {
 	parts := strings.Split(repository, "/")
 	if len(parts) != 2 {
-		return models.GitHubIssue{}, fmt.Errorf("invalid repository format: %s", repository)
+		return models.GitHubIssue{}, apierror.Invalid("repository", "invalid repository format: %s", repository)
 	}
 
-	issue, _, err := c.client.Issues.Get(context.Background(), parts[0], parts[1], issueNumber)
+	issue, _, err := c.client.Issues.Get(ctx, parts[0], parts[1], issueNumber)
 	if err != nil {
-		return models.GitHubIssue{}, fmt.Errorf("failed to get issue: %v", err)
+		return models.GitHubIssue{}, fmt.Errorf("failed to get issue: %w", apiError(err))
+	}
+	if issue == nil {
+		return models.GitHubIssue{}, fmt.Errorf("failed to get issue: empty response for %s#%d", repository, issueNumber)
 	}
 
-	labels := make([]string, 0, len(issue.Labels))
-	for _, label := range issue.Labels {
-		if label.Name != nil {
-			labels = append(labels, *label.Name)
+	return c.convertIssueOf(repository, issue), nil
+}
+
+// GetReferencingIssues returns the issues of the repository that mention the
+// issue with issueNumber, found by the cross-referenced events of its
+// timeline. Pull requests and issues of other repositories are left out. The
+// repository should be in the format "owner/repo".
+func (c *Client) GetReferencingIssues(ctx context.Context, repository string, issueNumber int) ([]models.GitHubIssue, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	opts := &github.ListOptions{PerPage: 100}
+	var issues []models.GitHubIssue
+	seen := make(map[int]bool)
+	for {
+		events, resp, err := c.client.Issues.ListIssueTimeline(ctx, parts[0], parts[1], issueNumber, opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list timeline of %s#%d: %w", repository, issueNumber, apiError(err))
+		}
+
+		for _, event := range events {
+			if event == nil || event.GetEvent() != "cross-referenced" || event.Source == nil {
+				continue
+			}
+			source := event.Source.Issue
+			if source == nil || source.IsPullRequest() || seen[source.GetNumber()] ||
+				!strings.HasSuffix(strings.ToLower(source.GetRepositoryURL()), "/repos/"+strings.ToLower(repository)) {
+				continue
+			}
+			seen[source.GetNumber()] = true
+			issues = append(issues, c.convertIssueOf(repository, source))
+		}
+
+		if resp.NextPage == 0 {
+			break
 		}
+		opts.Page = resp.NextPage
 	}
 
-	return models.GitHubIssue{
-		Number:      *issue.Number,
-		Title:       *issue.Title,
-		Description: *issue.Body,
-		Labels:      labels,
-	}, nil
+	return issues, nil
 }
 
 // GetIssuesWithLabels retrieves all open issues with any of the specified labels
-func (c *Client) GetIssuesWithLabels(repository string, labels []string) ([]models.GitHubIssue, error) {
+func (c *Client) GetIssuesWithLabels(ctx context.Context, repository string, labels []string) ([]models.GitHubIssue, error) {
 	var allIssues []models.GitHubIssue
 
 	// Start with just getting all open issues
 	query := fmt.Sprintf("repo:%s is:issue is:open", repository)
 
-	logging.Debug("searching for github issues",
+	c.log().Debug("searching for github issues",
 		"query", query)
 
 	opts := &github.SearchOptions{
@@ -495,51 +752,248 @@ func (c *Client) GetIssuesWithLabels(repository string, labels []string) ([]mode
 		},
 	}
 
-	result, _, err := c.client.Search.Issues(c.ctx, query, opts)
+	result, _, err := c.client.Search.Issues(ctx, query, opts)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search issues: %v", err)
+		return nil, fmt.Errorf("failed to search issues: %w", apiError(err))
 	}
 
-	logging.Debug("found issues without label filter",
+	c.log().Debug("found issues without label filter",
 		"total_count", result.GetTotal())
 
 	// Now filter by labels in memory
 	for _, issue := range result.Issues {
+		if issue == nil {
+			continue
+		}
 		issueLabels := extractLabelsFromIssue(issue)
 		for _, targetLabel := range labels {
 			if hasLabel(issueLabels, targetLabel) {
-				ghIssue := models.GitHubIssue{
-					Number:      issue.GetNumber(),
-					Title:       issue.GetTitle(),
-					Description: issue.GetBody(),
-					Labels:      issueLabels,
-					State:       issue.GetState(),
-					CreatedAt:   issue.GetCreatedAt(),
-					UpdatedAt:   issue.GetUpdatedAt(),
-				}
-				allIssues = append(allIssues, ghIssue)
+				allIssues = append(allIssues, c.convertIssueOf(repository, issue))
 				break // Found one matching label, no need to check others
 			}
 		}
 	}
 
-	logging.Debug("filtered issues by labels",
+	c.log().Debug("filtered issues by labels",
 		"total_matching", len(allIssues),
 		"labels", labels)
 
 	return allIssues, nil
 }
 
+// GetUser retrieves the public profile of a GitHub user, or an error if the
+// retrieval fails.
+func (c *Client) GetUser(ctx context.Context, login string) (models.GitHubUser, error) {
+	if login == "" {
+		return models.GitHubUser{}, apierror.Invalid("login", "login is required")
+	}
+
+	user, _, err := c.client.Users.Get(ctx, login)
+	if err != nil {
+		return models.GitHubUser{}, fmt.Errorf("failed to get GitHub user %s: %w", login, apiError(err))
+	}
+
+	return models.GitHubUser{
+		Login: user.GetLogin(),
+		Name:  user.GetName(),
+		Email: user.GetEmail(),
+	}, nil
+}
+
+// GetPullRequests retrieves all pull requests of a GitHub repository, open and
+// closed. The repository should be in the format "owner/repo". It returns the
+// pull requests or an error if the retrieval fails.
+func (c *Client) GetPullRequests(ctx context.Context, repository string) ([]models.GitHubPullRequest, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+
+	opts := &github.PullRequestListOptions{
+		State: "all",
+		ListOptions: github.ListOptions{
+			PerPage: 100,
+		},
+	}
+
+	var result []models.GitHubPullRequest
+	for {
+		pulls, resp, err := c.client.PullRequests.List(ctx, owner, repo, opts)
+		if err != nil {
+			c.log().Error("failed to fetch github pull requests", "error", err)
+			return nil, fmt.Errorf("failed to fetch GitHub pull requests: %w", apiError(err))
+		}
+
+		for _, pull := range pulls {
+			if pull != nil {
+				result = append(result, convertPullRequest(pull))
+			}
+		}
+
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+
+	return result, nil
+}
+
+// issueEvent is an issue event as returned by the events API, including the
+// state reason go-github doesn't decode.
+type issueEvent struct {
+	Event       string       `json:"event"`
+	Actor       *github.User `json:"actor"`
+	CommitID    string       `json:"commit_id"`
+	StateReason string       `json:"state_reason"`
+	CreatedAt   time.Time    `json:"created_at"`
+}
+
+// GetIssueClosure describes how a GitHub issue was last closed: by whom, when,
+// with which state reason, and by which commit and pull request, if any. The
+// repository should be in the format "owner/repo". It returns an empty
+// closure if the issue was never closed.
+func (c *Client) GetIssueClosure(ctx context.Context, repository string, issueNumber int) (models.GitHubIssueClosure, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return models.GitHubIssueClosure{}, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+
+	var closed *issueEvent
+	for page := 1; page != 0; {
+		req, err := c.client.NewRequest("GET", fmt.Sprintf("repos/%s/%s/issues/%d/events?per_page=100&page=%d", owner, repo, issueNumber, page), nil)
+		if err != nil {
+			return models.GitHubIssueClosure{}, err
+		}
+
+		var events []issueEvent
+		resp, err := c.client.Do(ctx, req, &events)
+		if err != nil {
+			return models.GitHubIssueClosure{}, fmt.Errorf("failed to list events of %s#%d: %w", repository, issueNumber, apiError(err))
+		}
+
+		for i := range events {
+			if events[i].Event == "closed" {
+				closed = &events[i]
+			}
+		}
+		page = resp.NextPage
+	}
+
+	if closed == nil {
+		return models.GitHubIssueClosure{}, nil
+	}
+
+	closure := models.GitHubIssueClosure{
+		ClosedBy: closed.Actor.GetLogin(),
+		ClosedAt: closed.CreatedAt,
+		Reason:   closed.StateReason,
+		CommitID: closed.CommitID,
+	}
+	if closure.CommitID == "" {
+		return closure, nil
+	}
+	closure.CommitURL = fmt.Sprintf("%s/%s/commit/%s", c.webURL(), repository, closure.CommitID)
+
+	if err := c.Require(FeatureCommitPullRequests); err != nil {
+		c.log().Debug("not looking up pull request of closing commit", "commit", closure.CommitID, "reason", err)
+		return closure, nil
+	}
+
+	pulls, _, err := c.client.PullRequests.ListPullRequestsWithCommit(ctx, owner, repo, closure.CommitID, nil)
+	if err != nil {
+		c.log().Warn("failed to find pull request of closing commit", "commit", closure.CommitID, "error", apiError(err))
+		return closure, nil
+	}
+	if len(pulls) > 0 && pulls[0] != nil {
+		pull := convertPullRequest(pulls[0])
+		closure.PullRequest = &pull
+	}
+	return closure, nil
+}
+
+// webURL returns the address of the GitHub web interface the client's API
+// belongs to, e.g. https://github.com or https://git.example.com.
+func (c *Client) webURL() string {
+	if c.client.BaseURL.Host == "api.github.com" {
+		return "https://github.com"
+	}
+	return fmt.Sprintf("%s://%s", c.client.BaseURL.Scheme, c.client.BaseURL.Host)
+}
+
+// convertPullRequest converts a GitHub API pull request to our internal model.
+// The list API doesn't report the merged flag, so a merge time counts as merged.
+func convertPullRequest(pull *github.PullRequest) models.GitHubPullRequest {
+	return models.GitHubPullRequest{
+		Number:     pull.GetNumber(),
+		Title:      pull.GetTitle(),
+		State:      pull.GetState(),
+		Merged:     pull.GetMerged() || pull.MergedAt != nil,
+		HeadBranch: pull.GetHead().GetRef(),
+		Body:       pull.GetBody(),
+		URL:        pull.GetHTMLURL(),
+	}
+}
+
+// convertIssue converts a GitHub API issue to our internal model. It is safe
+// for issues lacking any optional field: a missing body, state or timestamp
+// becomes the zero value, and labels or assignees without a name are left out.
+func convertIssue(issue *github.Issue) models.GitHubIssue {
+	var closedAt *time.Time
+	if issue.GetClosedAt() != (time.Time{}) {
+		t := issue.GetClosedAt()
+		closedAt = &t
+	}
+
+	return models.GitHubIssue{
+		Number:      issue.GetNumber(),
+		Title:       issue.GetTitle(),
+		Description: issue.GetBody(),
+		State:       issue.GetState(),
+		CreatedAt:   issue.GetCreatedAt(),
+		UpdatedAt:   issue.GetUpdatedAt(),
+		ClosedAt:    closedAt,
+		Labels:      extractLabelsFromIssue(issue),
+		Assignees:   extractAssigneesFromIssue(issue),
+		Locked:      issue.GetLocked(),
+		Author:      issue.GetUser().GetLogin(),
+		URL:         issue.GetHTMLURL(),
+	}
+}
+
 // extractLabelsFromIssue extracts label names from a GitHub issue and returns them as a string slice.
-// It processes each label in the issue's Labels field and retrieves its name.
+// It processes each label in the issue's Labels field and retrieves its name; unnamed labels are skipped.
 func extractLabelsFromIssue(issue *github.Issue) []string {
-	labels := make([]string, len(issue.Labels))
-	for i, label := range issue.Labels {
-		labels[i] = label.GetName()
+	if issue == nil {
+		return []string{}
+	}
+	labels := make([]string, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		if name := label.GetName(); name != "" {
+			labels = append(labels, name)
+		}
 	}
 	return labels
 }
 
+// extractAssigneesFromIssue extracts the logins of the users assigned to a GitHub issue.
+func extractAssigneesFromIssue(issue *github.Issue) []string {
+	if issue == nil {
+		return []string{}
+	}
+	assignees := make([]string, 0, len(issue.Assignees))
+	for _, user := range issue.Assignees {
+		if login := user.GetLogin(); login != "" {
+			assignees = append(assignees, login)
+		}
+	}
+	return assignees
+}
+
 // hasLabel checks if a specific label exists in a slice of labels using case-insensitive comparison.
 // It returns true if the target label is found, false otherwise.
 func hasLabel(labels []string, targetLabel string) bool {
@@ -552,47 +1006,37 @@ func hasLabel(labels []string, targetLabel string) bool {
 }
 
 // GetClosedIssuesWithLabels retrieves all closed issues with specified labels from a repository
-func (c *Client) GetClosedIssuesWithLabels(repository string, labels []string) ([]models.GitHubIssue, error) {
-	logging.Debug("searching for closed github issues with labels",
+func (c *Client) GetClosedIssuesWithLabels(ctx context.Context, repository string, labels []string) ([]models.GitHubIssue, error) {
+	c.log().Debug("searching for closed github issues with labels",
 		"repository", repository,
 		"labels", labels)
 
 	// Build the query for closed issues with labels
 	query := fmt.Sprintf("repo:%s is:issue is:closed", repository)
 	for _, label := range labels {
-		query += fmt.Sprintf(" label:%s", label)
+		query += fmt.Sprintf(" label:%q", label)
 	}
 
 	// Get closed issues using the search API
-	issues, _, err := c.client.Search.Issues(context.Background(), query, &github.SearchOptions{
+	issues, _, err := c.client.Search.Issues(ctx, query, &github.SearchOptions{
 		ListOptions: github.ListOptions{
 			PerPage: 100,
 		},
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to search closed issues: %v", err)
+		return nil, fmt.Errorf("failed to search closed issues: %w", apiError(err))
 	}
 
 	// Convert GitHub issues to our models
 	var filteredIssues []models.GitHubIssue
 	for _, issue := range issues.Issues {
-		// Extract labels
-		var labels []string
-		for _, label := range issue.Labels {
-			labels = append(labels, label.GetName())
+		if issue == nil {
+			continue
 		}
-
-		// Convert to our model
-		filteredIssues = append(filteredIssues, models.GitHubIssue{
-			Number:      issue.GetNumber(),
-			Title:       issue.GetTitle(),
-			Description: issue.GetBody(),
-			Labels:      labels,
-			State:       issue.GetState(),
-		})
+		filteredIssues = append(filteredIssues, c.convertIssueOf(repository, issue))
 	}
 
-	logging.Debug("filtered closed issues by labels",
+	c.log().Debug("filtered closed issues by labels",
 		"total_matching", len(filteredIssues),
 		"labels", labels)
 
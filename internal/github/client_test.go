@@ -1,12 +1,18 @@
 package github
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"net/url"
 	"os"
 	"regexp"
 	"strings"
 	"testing"
+	"time"
+	"github.com/danielolaszy/glue/pkg/models"
 	"github.com/google/go-github/v41/github"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -124,7 +130,7 @@ func TestIsIssueClosedValidation(t *testing.T) {
 	client := &Client{}
 
 	// Test with invalid repository format
-	_, err := client.IsIssueClosed("invalid-repo-format", 123)
+	_, err := client.IsIssueClosed(context.Background(), "invalid-repo-format", 123)
 	if err == nil {
 		t.Error("Expected error with invalid repository format, got nil")
 	}
@@ -139,7 +145,7 @@ func TestGetClosedIssuesValidation(t *testing.T) {
 	client := &Client{}
 
 	// Test with invalid repository format
-	_, err := client.GetClosedIssues("invalid-repo-format")
+	_, err := client.GetClosedIssues(context.Background(), "invalid-repo-format")
 	if err == nil {
 		t.Error("Expected error with invalid repository format, got nil")
 	}
@@ -234,7 +240,7 @@ func TestGetIssuesWithLabels(t *testing.T) {
 				client: github.NewClient(nil),
 			}
 			
-			issues, err := client.GetIssuesWithLabels(tt.repo, tt.labels)
+			issues, err := client.GetIssuesWithLabels(context.Background(), tt.repo, tt.labels)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, issues)
@@ -270,7 +276,7 @@ func TestHasLabelMatching(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			result, err := tt.client.HasLabelMatching(tt.repo, tt.issueNum, tt.pattern)
+			result, err := tt.client.HasLabelMatching(context.Background(), tt.repo, tt.issueNum, tt.pattern)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.False(t, result)
@@ -320,7 +326,7 @@ func TestGetClosedIssuesWithLabels(t *testing.T) {
 				client: github.NewClient(nil),
 			}
 			
-			issues, err := client.GetClosedIssuesWithLabels(tt.repo, tt.labels)
+			issues, err := client.GetClosedIssuesWithLabels(context.Background(), tt.repo, tt.labels)
 			if tt.wantErr {
 				assert.Error(t, err)
 				assert.Nil(t, issues)
@@ -352,3 +358,262 @@ func stringSlicesEqual(a, b []string) bool {
 	}
 	return true
 }
+
+func TestConvertIssue(t *testing.T) {
+	created := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	closed := created.Add(48 * time.Hour)
+
+	tests := []struct {
+		name  string
+		issue *github.Issue
+		want  func(t *testing.T, got models.GitHubIssue)
+	}{
+		{
+			name: "All fields",
+			issue: &github.Issue{
+				Number:    github.Int(42),
+				Title:     github.String("Add login"),
+				Body:      github.String("Body"),
+				State:     github.String("closed"),
+				CreatedAt: &created,
+				UpdatedAt: &closed,
+				ClosedAt:  &closed,
+				Labels:    []*github.Label{{Name: github.String("story")}, {Name: github.String("PROJ")}},
+				Assignees: []*github.User{{Login: github.String("octocat")}},
+				Locked:    github.Bool(true),
+				HTMLURL:   github.String("https://github.com/owner/repo/issues/42"),
+			},
+			want: func(t *testing.T, got models.GitHubIssue) {
+				assert.Equal(t, 42, got.Number)
+				assert.Equal(t, "Add login", got.Title)
+				assert.Equal(t, "Body", got.Description)
+				assert.Equal(t, "closed", got.State)
+				assert.Equal(t, created, got.CreatedAt)
+				require.NotNil(t, got.ClosedAt)
+				assert.Equal(t, closed, *got.ClosedAt)
+				assert.Equal(t, []string{"story", "PROJ"}, got.Labels)
+				assert.Equal(t, []string{"octocat"}, got.Assignees)
+				assert.True(t, got.Locked)
+				assert.Equal(t, "https://github.com/owner/repo/issues/42", got.URL)
+			},
+		},
+		{
+			name:  "Missing body, state and timestamps",
+			issue: &github.Issue{Number: github.Int(7), Title: github.String("Bare")},
+			want: func(t *testing.T, got models.GitHubIssue) {
+				assert.Equal(t, 7, got.Number)
+				assert.Empty(t, got.Description)
+				assert.Empty(t, got.State)
+				assert.True(t, got.CreatedAt.IsZero())
+				assert.Nil(t, got.ClosedAt)
+				assert.Empty(t, got.Labels)
+				assert.Empty(t, got.Assignees)
+				assert.False(t, got.Locked)
+			},
+		},
+		{
+			name: "Labels and assignees without names",
+			issue: &github.Issue{
+				Number:    github.Int(8),
+				Labels:    []*github.Label{nil, {}, {Name: github.String("feature")}},
+				Assignees: []*github.User{nil, {}, {Login: github.String("hubot")}},
+			},
+			want: func(t *testing.T, got models.GitHubIssue) {
+				assert.Equal(t, []string{"feature"}, got.Labels)
+				assert.Equal(t, []string{"hubot"}, got.Assignees)
+			},
+		},
+		{
+			name:  "Nil issue",
+			issue: nil,
+			want: func(t *testing.T, got models.GitHubIssue) {
+				assert.Equal(t, 0, got.Number)
+				assert.Empty(t, got.Labels)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.want(t, convertIssue(tt.issue))
+		})
+	}
+}
+
+func TestConvertPullRequest(t *testing.T) {
+	merged := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	got := convertPullRequest(&github.PullRequest{
+		Number:   github.Int(43),
+		Title:    github.String("PROJ-1: Add login"),
+		State:    github.String("closed"),
+		MergedAt: &merged,
+		Head:     &github.PullRequestBranch{Ref: github.String("feature/proj-1-login")},
+		Body:     github.String("Fixes #12"),
+		HTMLURL:  github.String("https://github.com/owner/repo/pull/43"),
+	})
+	assert.Equal(t, models.GitHubPullRequest{
+		Number:     43,
+		Title:      "PROJ-1: Add login",
+		State:      "closed",
+		Merged:     true,
+		HeadBranch: "feature/proj-1-login",
+		Body:       "Fixes #12",
+		URL:        "https://github.com/owner/repo/pull/43",
+	}, got)
+
+	assert.Equal(t, models.GitHubPullRequest{Number: 44}, convertPullRequest(&github.PullRequest{Number: github.Int(44)}))
+}
+
+func TestListTopics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/topics", r.URL.Path)
+		fmt.Fprint(w, `{"names": ["go", "jira-proj"]}`)
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	topics, err := client.ListTopics(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "jira-proj"}, topics)
+
+	_, err = client.ListTopics(context.Background(), "invalid-repo-format")
+	assert.ErrorContains(t, err, "invalid repository format")
+}
+
+func TestUpdateIssueBody(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/repos/owner/repo/issues/7", r.URL.Path)
+		var request github.IssueRequest
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		assert.Equal(t, "New body", request.GetBody())
+		assert.Nil(t, request.Title)
+		fmt.Fprint(w, `{"number": 7}`)
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	require.NoError(t, client.UpdateIssueBody(context.Background(), "owner/repo", 7, "New body"))
+
+	err = client.UpdateIssueBody(context.Background(), "invalid-repo-format", 7, "New body")
+	assert.ErrorContains(t, err, "invalid repository format")
+}
+
+func TestGetIssueClosure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/42/events":
+			fmt.Fprint(w, `[
+				{"event": "closed", "actor": {"login": "someone"}, "state_reason": "not_planned", "created_at": "2023-05-01T10:00:00Z"},
+				{"event": "reopened", "actor": {"login": "octocat"}, "created_at": "2023-05-02T10:00:00Z"},
+				{"event": "closed", "actor": {"login": "octocat"}, "commit_id": "0123456789abcdef", "state_reason": "completed", "created_at": "2023-06-01T10:30:00Z"}
+			]`)
+		case "/repos/owner/repo/commits/0123456789abcdef/pulls":
+			fmt.Fprint(w, `[{"number": 7, "title": "Fix login", "state": "closed", "merged_at": "2023-06-01T10:29:00Z", "html_url": "https://github.com/owner/repo/pull/7"}]`)
+		case "/repos/owner/repo/issues/43/events":
+			fmt.Fprint(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	closure, err := client.GetIssueClosure(context.Background(), "owner/repo", 42)
+	require.NoError(t, err)
+	assert.Equal(t, "octocat", closure.ClosedBy)
+	assert.Equal(t, time.Date(2023, 6, 1, 10, 30, 0, 0, time.UTC), closure.ClosedAt)
+	assert.Equal(t, "completed", closure.Reason)
+	assert.Equal(t, server.URL+"/owner/repo/commit/0123456789abcdef", closure.CommitURL)
+	require.NotNil(t, closure.PullRequest)
+	assert.Equal(t, 7, closure.PullRequest.Number)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7", closure.PullRequest.URL)
+
+	closure, err = client.GetIssueClosure(context.Background(), "owner/repo", 43)
+	require.NoError(t, err)
+	assert.Equal(t, models.GitHubIssueClosure{}, closure)
+
+	_, err = client.GetIssueClosure(context.Background(), "invalid-repo-format", 42)
+	assert.ErrorContains(t, err, "invalid repository format")
+}
+
+func TestGetReferencingIssues(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/repo/issues/7/timeline":
+			fmt.Fprint(w, `[
+				{"event": "labeled"},
+				{"event": "cross-referenced", "source": {"type": "issue", "issue": {"number": 1, "title": "Feature", "state": "open", "repository_url": "https://api.github.com/repos/Owner/Repo"}}},
+				{"event": "cross-referenced", "source": {"type": "issue", "issue": {"number": 1, "title": "Feature", "state": "open", "repository_url": "https://api.github.com/repos/owner/repo"}}},
+				{"event": "cross-referenced", "source": {"type": "issue", "issue": {"number": 2, "title": "Fix", "state": "open", "repository_url": "https://api.github.com/repos/owner/repo", "pull_request": {"url": "https://api.github.com/repos/owner/repo/pulls/2"}}}},
+				{"event": "cross-referenced", "source": {"type": "issue", "issue": {"number": 3, "title": "Elsewhere", "state": "open", "repository_url": "https://api.github.com/repos/owner/other"}}}
+			]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	issues, err := client.GetReferencingIssues(context.Background(), "owner/repo", 7)
+	require.NoError(t, err)
+	require.Len(t, issues, 1)
+	assert.Equal(t, 1, issues[0].Number)
+	assert.Equal(t, "Feature", issues[0].Title)
+
+	_, err = client.GetReferencingIssues(context.Background(), "invalid-repo-format", 7)
+	assert.ErrorContains(t, err, "invalid repository format")
+}
+
+func TestGetRepository(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/repos/owner/old":
+			fmt.Fprint(w, `{"full_name": "owner/old", "archived": true}`)
+		case "/repos/owner/repo":
+			fmt.Fprint(w, `{"full_name": "owner/repo", "archived": false}`)
+		case "/repos/owner/renamed":
+			http.Redirect(w, r, "/repos/owner/repo", http.StatusMovedPermanently)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	repo, err := client.GetRepository(context.Background(), "owner/old")
+	require.NoError(t, err)
+	assert.True(t, repo.Archived)
+
+	repo, err = client.GetRepository(context.Background(), "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, models.GitHubRepository{FullName: "owner/repo"}, repo)
+
+	repo, err = client.GetRepository(context.Background(), "owner/renamed")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/repo", repo.FullName)
+
+	_, err = client.GetRepository(context.Background(), "owner/missing")
+	assert.Error(t, err)
+}
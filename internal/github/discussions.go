@@ -0,0 +1,117 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// GetDiscussion retrieves a GitHub Discussion by number through the GraphQL
+// API, which is the only API serving discussions. The repository should be
+// in the format "owner/repo".
+func (c *Client) GetDiscussion(ctx context.Context, repository string, number int) (models.GitHubDiscussion, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return models.GitHubDiscussion{}, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	c.log().Debug("fetching github discussion", "repository", repository, "discussion_number", number)
+
+	var data struct {
+		Repository struct {
+			Discussion *struct {
+				ID     string `json:"id"`
+				Number int    `json:"number"`
+				Title  string `json:"title"`
+				Body   string `json:"body"`
+				URL    string `json:"url"`
+				Author *struct {
+					Login string `json:"login"`
+				} `json:"author"`
+				Category *struct {
+					Name string `json:"name"`
+				} `json:"category"`
+			} `json:"discussion"`
+		} `json:"repository"`
+	}
+	query := `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    discussion(number: $number) { id number title body url author { login } category { name } }
+  }
+}`
+	err := c.graphQL(ctx, query, map[string]any{"owner": parts[0], "name": parts[1], "number": number}, &data)
+	if err != nil {
+		return models.GitHubDiscussion{}, fmt.Errorf("failed to get discussion %s#%d: %w", repository, number, err)
+	}
+
+	d := data.Repository.Discussion
+	if d == nil {
+		return models.GitHubDiscussion{}, fmt.Errorf("failed to get discussion %s#%d: %w", repository, number, apierror.ErrNotFound)
+	}
+	discussion := models.GitHubDiscussion{
+		ID:     d.ID,
+		Number: d.Number,
+		Title:  d.Title,
+		Body:   d.Body,
+		URL:    d.URL,
+	}
+	if d.Author != nil {
+		discussion.Author = d.Author.Login
+	}
+	if d.Category != nil {
+		discussion.Category = d.Category.Name
+	}
+	return discussion, nil
+}
+
+// CommentOnDiscussion adds a comment to a GitHub Discussion, identified by
+// its GraphQL node ID.
+func (c *Client) CommentOnDiscussion(ctx context.Context, discussionID, body string) error {
+	c.log().Debug("commenting on github discussion", "discussion_id", discussionID)
+
+	query := `mutation($id: ID!, $body: String!) {
+  addDiscussionComment(input: {discussionId: $id, body: $body}) { comment { id } }
+}`
+	if err := c.graphQL(ctx, query, map[string]any{"id": discussionID, "body": body}, nil); err != nil {
+		return fmt.Errorf("failed to comment on discussion: %w", err)
+	}
+	return nil
+}
+
+// graphQL runs a GraphQL query or mutation and decodes its data into data,
+// unless data is nil. Any error in the response fails the request.
+func (c *Client) graphQL(ctx context.Context, query string, variables map[string]any, data any) error {
+	req, err := c.client.NewRequest(http.MethodPost, c.graphQLPath(), map[string]any{
+		"query":     query,
+		"variables": variables,
+	})
+	if err != nil {
+		return err
+	}
+
+	var resp struct {
+		Data   json.RawMessage `json:"data"`
+		Errors []struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if _, err := c.client.Do(ctx, req, &resp); err != nil {
+		return apiError(err)
+	}
+	if len(resp.Errors) > 0 {
+		if resp.Errors[0].Type == "NOT_FOUND" {
+			return fmt.Errorf("%s: %w", resp.Errors[0].Message, apierror.ErrNotFound)
+		}
+		return fmt.Errorf("%s", resp.Errors[0].Message)
+	}
+	if data == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	return json.Unmarshal(resp.Data, data)
+}
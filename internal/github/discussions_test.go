@@ -0,0 +1,67 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscussions(t *testing.T) {
+	var comment map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		assert.Equal(t, "/graphql", r.URL.Path)
+
+		switch {
+		case strings.Contains(body.Query, "addDiscussionComment"):
+			comment = body.Variables
+			fmt.Fprint(w, `{"data": {"addDiscussionComment": {"comment": {"id": "DC_1"}}}}`)
+		case body.Variables["number"] == float64(17):
+			fmt.Fprint(w, `{"data": {"repository": {"discussion": {"id": "D_17", "number": 17, "title": "Dark mode",
+				"body": "Please add it.", "url": "https://github.com/owner/repo/discussions/17",
+				"author": {"login": "octocat"}, "category": {"name": "Ideas"}}}}}`)
+		default:
+			fmt.Fprint(w, `{"data": {"repository": {"discussion": null}},
+				"errors": [{"type": "NOT_FOUND", "message": "Could not resolve to a Discussion with the number of 18."}]}`)
+		}
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	discussion, err := client.GetDiscussion(context.Background(), "owner/repo", 17)
+	require.NoError(t, err)
+	assert.Equal(t, models.GitHubDiscussion{
+		ID:       "D_17",
+		Number:   17,
+		Title:    "Dark mode",
+		Body:     "Please add it.",
+		URL:      "https://github.com/owner/repo/discussions/17",
+		Author:   "octocat",
+		Category: "Ideas",
+	}, discussion)
+
+	_, err = client.GetDiscussion(context.Background(), "owner/repo", 18)
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+
+	require.NoError(t, client.CommentOnDiscussion(context.Background(), "D_17", "Promoted to #42"))
+	assert.Equal(t, map[string]any{"id": "D_17", "body": "Promoted to #42"}, comment)
+}
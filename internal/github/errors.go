@@ -0,0 +1,51 @@
+package github
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/google/go-github/v41/github"
+)
+
+// Error kinds returned by the client, shared with the JIRA client. Use
+// errors.Is to check for them.
+var (
+	ErrNotFound     = apierror.ErrNotFound
+	ErrUnauthorized = apierror.ErrUnauthorized
+	ErrRateLimited  = apierror.ErrRateLimited
+	ErrReadOnly     = apierror.ErrReadOnly
+)
+
+// ValidationError is returned for invalid arguments, before any request is made.
+type ValidationError = apierror.ValidationError
+
+// apiError annotates an error from the GitHub API with its error kind. GitHub
+// reports rate limiting with 403 responses, so rate limit errors are detected
+// by type before the status code is considered.
+func apiError(err error) error {
+	var rateLimitErr *github.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		return &apierror.StatusError{Kind: ErrRateLimited, StatusCode: statusCode(rateLimitErr.Response), Err: err}
+	}
+
+	var abuseErr *github.AbuseRateLimitError
+	if errors.As(err, &abuseErr) {
+		return &apierror.StatusError{Kind: ErrRateLimited, StatusCode: statusCode(abuseErr.Response), Err: err}
+	}
+
+	var responseErr *github.ErrorResponse
+	if errors.As(err, &responseErr) {
+		return apierror.Wrap(err, statusCode(responseErr.Response))
+	}
+
+	return apierror.Wrap(err, 0)
+}
+
+// statusCode returns the status code of a response, or 0 if there is none.
+func statusCode(resp *http.Response) int {
+	if resp == nil {
+		return 0
+	}
+	return resp.StatusCode
+}
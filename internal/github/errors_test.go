@@ -0,0 +1,52 @@
+package github
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAPIError(t *testing.T) {
+	response := func(status int) *http.Response {
+		return &http.Response{StatusCode: status, Request: &http.Request{Method: "GET"}}
+	}
+
+	tests := []struct {
+		name string
+		err  error
+		kind error
+	}{
+		{"Not found", &github.ErrorResponse{Response: response(http.StatusNotFound)}, ErrNotFound},
+		{"Unauthorized", &github.ErrorResponse{Response: response(http.StatusUnauthorized)}, ErrUnauthorized},
+		{"Rate limited", &github.RateLimitError{Response: response(http.StatusForbidden)}, ErrRateLimited},
+		{"Secondary rate limit", &github.AbuseRateLimitError{Response: response(http.StatusForbidden)}, ErrRateLimited},
+		{"Server error", &github.ErrorResponse{Response: response(http.StatusBadGateway)}, nil},
+		{"Network error", errors.New("connection reset by peer"), nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := fmt.Errorf("failed to get issue: %w", apiError(tt.err))
+
+			assert.ErrorIs(t, err, tt.err)
+			for _, kind := range []error{ErrNotFound, ErrUnauthorized, ErrRateLimited} {
+				assert.Equal(t, kind == tt.kind, errors.Is(err, kind), kind.Error())
+			}
+		})
+	}
+}
+
+func TestInvalidRepositoryIsValidationError(t *testing.T) {
+	client := &Client{}
+
+	_, err := client.GetIssue(context.Background(), "invalid", 1)
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "repository", validationErr.Field)
+}
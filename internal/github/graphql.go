@@ -0,0 +1,41 @@
+package github
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// graphQLOperation matches the keywords of GraphQL operations that change
+// data, wherever an operation can start.
+var graphQLOperation = regexp.MustCompile(`(^|[\s}])(mutation|subscription)\b`)
+
+// isGraphQLQuery reports whether a request is a GraphQL query, which is sent
+// as a POST but changes nothing, unlike a mutation. Requests whose body can't
+// be read again are not.
+func isGraphQLQuery(req *http.Request) bool {
+	if req.Method != http.MethodPost || !strings.HasSuffix(req.URL.Path, "/graphql") || req.GetBody == nil {
+		return false
+	}
+
+	body, err := req.GetBody()
+	if err != nil {
+		return false
+	}
+	defer body.Close()
+	var payload struct {
+		Query string `json:"query"`
+	}
+	data, err := io.ReadAll(body)
+	if err != nil || json.Unmarshal(data, &payload) != nil {
+		return false
+	}
+
+	query := strings.TrimSpace(payload.Query)
+	if !strings.HasPrefix(query, "{") && !strings.HasPrefix(query, "query") {
+		return false
+	}
+	return !graphQLOperation.MatchString(query)
+}
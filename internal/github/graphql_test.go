@@ -0,0 +1,35 @@
+package github
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsGraphQLQuery(t *testing.T) {
+	tests := []struct {
+		name   string
+		method string
+		path   string
+		body   string
+		want   bool
+	}{
+		{"query", http.MethodPost, "/graphql", `{"query": "query($owner: String!) { repository(owner: $owner) { id } }"}`, true},
+		{"shorthand query", http.MethodPost, "/api/graphql", `{"query": "{ viewer { login } }"}`, true},
+		{"mutation", http.MethodPost, "/graphql", `{"query": "mutation { addComment(input: {}) { clientMutationId } }"}`, false},
+		{"query followed by mutation", http.MethodPost, "/graphql", `{"query": "query A { viewer { login } } mutation B { x }"}`, false},
+		{"rest request", http.MethodPost, "/repos/owner/repo/issues", `{"title": "query"}`, false},
+		{"invalid body", http.MethodPost, "/graphql", `query`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req, err := http.NewRequest(tt.method, "https://api.github.com"+tt.path, strings.NewReader(tt.body))
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, isGraphQLQuery(req))
+		})
+	}
+}
@@ -0,0 +1,27 @@
+package github
+
+import (
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+)
+
+// IssueFunc adjusts the issues fetched from a repository before they are
+// returned, e.g. to fill in the ticket key of an issue whose title lost it.
+type IssueFunc func(repository string, issue models.GitHubIssue) models.GitHubIssue
+
+// SetIssueFunc sets the function adjusting the issues returned by GetIssue,
+// GetAllIssues, GetClosedIssues and the label searches. Copies made with
+// WithLogger afterwards share it.
+func (c *Client) SetIssueFunc(fn IssueFunc) {
+	c.issueFunc = fn
+}
+
+// convertIssueOf converts an issue fetched from repository and passes it to
+// the issue function, if there is one.
+func (c *Client) convertIssueOf(repository string, issue *github.Issue) models.GitHubIssue {
+	converted := convertIssue(issue)
+	if c.issueFunc == nil {
+		return converted
+	}
+	return c.issueFunc(repository, converted)
+}
@@ -0,0 +1,41 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetIssueFunc(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/owner/repo/issues/7", r.URL.Path)
+		fmt.Fprint(w, `{"number": 7, "title": "Add login"}`)
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	issue, err := client.GetIssue(context.Background(), "owner/repo", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "Add login", issue.Title)
+
+	client.SetIssueFunc(func(repository string, issue models.GitHubIssue) models.GitHubIssue {
+		assert.Equal(t, "owner/repo", repository)
+		issue.Title = "[PROJ-7] " + issue.Title
+		return issue
+	})
+	issue, err = client.WithLogger(nil).GetIssue(context.Background(), "owner/repo", 7)
+	require.NoError(t, err)
+	assert.Equal(t, "[PROJ-7] Add login", issue.Title)
+}
@@ -0,0 +1,88 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+)
+
+// issueTypesBatch is the number of issues whose type is queried at once.
+const issueTypesBatch = 100
+
+// graphQLResponse is the response to a GraphQL query for issue types.
+type graphQLResponse struct {
+	Data struct {
+		Repository map[string]*struct {
+			IssueType *struct {
+				Name string `json:"name"`
+			} `json:"issueType"`
+		} `json:"repository"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// GetIssueTypes retrieves the native issue types, such as "Bug" or "Feature",
+// of issues in a GitHub repository through the GraphQL API. The repository
+// should be in the format "owner/repo". Issues without a type are left out of
+// the returned map. It returns an error if the server doesn't know issue
+// types, as older GitHub Enterprise Server releases don't.
+func (c *Client) GetIssueTypes(ctx context.Context, repository string, numbers []int) (map[int]string, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	types := make(map[int]string)
+	for start := 0; start < len(numbers); start += issueTypesBatch {
+		end := min(start+issueTypesBatch, len(numbers))
+
+		var query strings.Builder
+		query.WriteString("query($owner: String!, $name: String!) { repository(owner: $owner, name: $name) {")
+		for _, number := range numbers[start:end] {
+			fmt.Fprintf(&query, " i%d: issue(number: %d) { issueType { name } }", number, number)
+		}
+		query.WriteString(" } }")
+
+		c.log().Debug("querying issue types", "repository", repository, "count", end-start)
+
+		req, err := c.client.NewRequest(http.MethodPost, c.graphQLPath(), map[string]any{
+			"query":     query.String(),
+			"variables": map[string]string{"owner": parts[0], "name": parts[1]},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		var resp graphQLResponse
+		if _, err := c.client.Do(ctx, req, &resp); err != nil {
+			return nil, fmt.Errorf("failed to query issue types for %s: %w", repository, apiError(err))
+		}
+		if len(resp.Errors) > 0 && resp.Data.Repository == nil {
+			return nil, fmt.Errorf("failed to query issue types for %s: %s", repository, resp.Errors[0].Message)
+		}
+
+		for _, number := range numbers[start:end] {
+			// Numbers of pull requests or deleted issues come back null
+			issue := resp.Data.Repository[fmt.Sprintf("i%d", number)]
+			if issue != nil && issue.IssueType != nil && issue.IssueType.Name != "" {
+				types[number] = issue.IssueType.Name
+			}
+		}
+	}
+	return types, nil
+}
+
+// graphQLPath returns the path of the GraphQL endpoint relative to the REST
+// API URL: "graphql" on github.com, and "../graphql" on GitHub Enterprise
+// Server, whose REST API is served under /api/v3/ and GraphQL under /api/.
+func (c *Client) graphQLPath() string {
+	if strings.HasSuffix(c.client.BaseURL.Path, "/v3/") {
+		return "../graphql"
+	}
+	return "graphql"
+}
@@ -0,0 +1,59 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetIssueTypes(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Query     string            `json:"query"`
+			Variables map[string]string `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		queries = append(queries, r.URL.Path)
+		assert.Equal(t, map[string]string{"owner": "owner", "name": "repo"}, body.Variables)
+
+		switch {
+		case strings.Contains(body.Query, "i1:"):
+			fmt.Fprint(w, `{"data": {"repository": {"i1": {"issueType": {"name": "Bug"}}, "i2": {"issueType": null}, "i3": null}},
+				"errors": [{"message": "Could not resolve to an Issue with the number of 3."}]}`)
+		default:
+			fmt.Fprint(w, `{"data": null, "errors": [{"message": "Field 'issueType' doesn't exist on type 'Issue'"}]}`)
+		}
+	}))
+	defer server.Close()
+
+	for _, tt := range []struct {
+		base, path string
+	}{
+		{"/", "/graphql"},
+		{"/api/v3/", "/api/graphql"},
+	} {
+		queries = nil
+		baseURL, err := url.Parse(server.URL + tt.base)
+		require.NoError(t, err)
+		client := &Client{client: github.NewClient(nil)}
+		client.client.BaseURL = baseURL
+
+		types, err := client.GetIssueTypes(context.Background(), "owner/repo", []int{1, 2, 3})
+		require.NoError(t, err)
+		assert.Equal(t, map[int]string{1: "Bug"}, types)
+		assert.Equal(t, []string{tt.path}, queries)
+
+		_, err = client.GetIssueTypes(context.Background(), "owner/repo", []int{4})
+		assert.ErrorContains(t, err, "doesn't exist")
+	}
+}
@@ -0,0 +1,163 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+)
+
+// dependabotAlert is a Dependabot alert as returned by the REST API, which
+// the client library predates.
+type dependabotAlert struct {
+	Number     int       `json:"number"`
+	HTMLURL    string    `json:"html_url"`
+	CreatedAt  time.Time `json:"created_at"`
+	Dependency struct {
+		Package struct {
+			Ecosystem string `json:"ecosystem"`
+			Name      string `json:"name"`
+		} `json:"package"`
+		ManifestPath string `json:"manifest_path"`
+	} `json:"dependency"`
+	SecurityAdvisory struct {
+		GHSAID      string `json:"ghsa_id"`
+		Summary     string `json:"summary"`
+		Description string `json:"description"`
+		Severity    string `json:"severity"`
+	} `json:"security_advisory"`
+}
+
+// codeScanningSeverities maps the severities of code scanning rules without a
+// security severity level to alert severities.
+var codeScanningSeverities = map[string]string{
+	"error":   "high",
+	"warning": "medium",
+	"note":    "low",
+	"none":    "low",
+}
+
+// GetDependabotAlerts retrieves the open Dependabot alerts of a GitHub
+// repository. The repository should be in the format "owner/repo". It
+// returns an error if Dependabot alerts are disabled or not visible with the
+// token.
+func (c *Client) GetDependabotAlerts(ctx context.Context, repository string) ([]models.SecurityAlert, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	c.log().Debug("fetching dependabot alerts", "repository", repository)
+
+	var alerts []models.SecurityAlert
+	for page := 1; page != 0; {
+		path := fmt.Sprintf("repos/%s/%s/dependabot/alerts?state=open&per_page=100&page=%d", parts[0], parts[1], page)
+		req, err := c.client.NewRequest(http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var batch []dependabotAlert
+		resp, err := c.client.Do(ctx, req, &batch)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list dependabot alerts for %s: %w", repository, apiError(err))
+		}
+		for _, alert := range batch {
+			alerts = append(alerts, convertDependabotAlert(alert))
+		}
+		page = resp.NextPage
+	}
+	return alerts, nil
+}
+
+// GetCodeScanningAlerts retrieves the open code scanning alerts of a GitHub
+// repository. The repository should be in the format "owner/repo". It
+// returns an error if code scanning isn't set up or not visible with the
+// token.
+func (c *Client) GetCodeScanningAlerts(ctx context.Context, repository string) ([]models.SecurityAlert, error) {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return nil, apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+
+	c.log().Debug("fetching code scanning alerts", "repository", repository)
+
+	var alerts []models.SecurityAlert
+	opts := &github.AlertListOptions{State: "open", ListOptions: github.ListOptions{PerPage: 100}}
+	for {
+		batch, resp, err := c.client.CodeScanning.ListAlertsForRepo(ctx, parts[0], parts[1], opts)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list code scanning alerts for %s: %w", repository, apiError(err))
+		}
+		for _, alert := range batch {
+			alerts = append(alerts, convertCodeScanningAlert(alert))
+		}
+		if resp.NextPage == 0 {
+			break
+		}
+		opts.Page = resp.NextPage
+	}
+	return alerts, nil
+}
+
+// convertDependabotAlert converts a Dependabot alert to our model.
+func convertDependabotAlert(alert dependabotAlert) models.SecurityAlert {
+	pkg := alert.Dependency.Package
+	location := pkg.Name
+	if pkg.Ecosystem != "" {
+		location = fmt.Sprintf("%s (%s)", pkg.Name, pkg.Ecosystem)
+	}
+	if alert.Dependency.ManifestPath != "" {
+		location += " in " + alert.Dependency.ManifestPath
+	}
+
+	return models.SecurityAlert{
+		Kind:        models.AlertDependabot,
+		Number:      alert.Number,
+		Severity:    strings.ToLower(alert.SecurityAdvisory.Severity),
+		Summary:     alert.SecurityAdvisory.Summary,
+		Description: alert.SecurityAdvisory.Description,
+		Location:    location,
+		URL:         alert.HTMLURL,
+		CreatedAt:   alert.CreatedAt,
+	}
+}
+
+// convertCodeScanningAlert converts a code scanning alert to our model. The
+// severity is the rule's security severity level if it has one.
+func convertCodeScanningAlert(alert *github.Alert) models.SecurityAlert {
+	rule := alert.GetRule()
+	severity := strings.ToLower(rule.GetSecuritySeverityLevel())
+	if severity == "" {
+		severity = codeScanningSeverities[strings.ToLower(rule.GetSeverity())]
+	}
+
+	summary := rule.GetDescription()
+	if summary == "" {
+		summary = alert.GetRuleDescription()
+	}
+
+	var location string
+	if instance := alert.GetMostRecentInstance(); instance != nil && instance.GetLocation() != nil {
+		location = instance.GetLocation().GetPath()
+		if line := instance.GetLocation().GetStartLine(); line > 0 {
+			location = fmt.Sprintf("%s:%d", location, line)
+		}
+	}
+
+	return models.SecurityAlert{
+		Kind:        models.AlertCodeScanning,
+		Number:      int(alert.ID()),
+		Severity:    severity,
+		Summary:     summary,
+		Description: rule.GetFullDescription(),
+		Location:    location,
+		URL:         alert.GetHTMLURL(),
+		CreatedAt:   alert.GetCreatedAt().Time,
+	}
+}
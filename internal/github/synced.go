@@ -0,0 +1,45 @@
+package github
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+)
+
+// MarkSynced records on an issue that it is synced to the JIRA ticket key on
+// board. The title is prefixed with the key and, if a synced_labels rule
+// applies to the repository, the issue's labels are changed in the same
+// edit. The labels are computed from issue.Labels, so labels added since the
+// issue was fetched are dropped.
+func (c *Client) MarkSynced(ctx context.Context, repository string, issue models.GitHubIssue, key, board string) error {
+	parts := strings.Split(repository, "/")
+	if len(parts) != 2 {
+		return apierror.Invalid("repository", "invalid repository format: %s, expected format: owner/repo", repository)
+	}
+	owner, repo := parts[0], parts[1]
+
+	title := marker.GitHub.Apply(issue.Title, key)
+	request := &github.IssueRequest{Title: &title}
+	if rule, ok := c.syncedLabels.For(repository); ok {
+		if labels, changed := rule.Apply(issue.Labels, key, board); changed {
+			if labels == nil {
+				labels = []string{}
+			}
+			request.Labels = &labels
+			c.log().Debug("updating labels of synced issue",
+				"issue_number", issue.Number,
+				"labels", labels)
+		}
+	}
+
+	_, _, err := c.client.Issues.Edit(ctx, owner, repo, issue.Number, request)
+	if err != nil {
+		return fmt.Errorf("failed to mark issue %s#%d as synced: %w", repo, issue.Number, apiError(err))
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarkSynced(t *testing.T) {
+	var edits []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPatch, r.Method)
+		assert.Equal(t, "/repos/owner/repo/issues/42", r.URL.Path)
+		var edit map[string]interface{}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&edit))
+		edits = append(edits, edit)
+		w.Write([]byte(`{"number": 42}`))
+	}))
+	defer server.Close()
+
+	baseURL, err := url.Parse(server.URL + "/")
+	require.NoError(t, err)
+	client := &Client{client: github.NewClient(nil)}
+	client.client.BaseURL = baseURL
+
+	issue := models.GitHubIssue{Number: 42, Title: "Add login", Labels: []string{"story", "needs-jira"}}
+
+	// Without a rule only the title changes
+	require.NoError(t, client.MarkSynced(context.Background(), "owner/repo", issue, "PROJ-7", "PROJ"))
+
+	client.syncedLabels = config.SyncedLabels{{Remove: []string{"needs-jira"}, Add: []string{"jira-id: {key}"}}}
+	require.NoError(t, client.MarkSynced(context.Background(), "owner/repo", issue, "PROJ-7", "PROJ"))
+
+	// Removing the last label sends an empty list rather than none
+	client.syncedLabels = config.SyncedLabels{{Remove: []string{"story", "needs-jira"}}}
+	require.NoError(t, client.MarkSynced(context.Background(), "owner/repo", issue, "PROJ-7", "PROJ"))
+
+	assert.Equal(t, []map[string]interface{}{
+		{"title": "[PROJ-7] Add login"},
+		{"title": "[PROJ-7] Add login", "labels": []interface{}{"story", "jira-id: PROJ-7"}},
+		{"title": "[PROJ-7] Add login", "labels": []interface{}{}},
+	}, edits)
+
+	err = client.MarkSynced(context.Background(), "invalid-repo-format", issue, "PROJ-7", "PROJ")
+	assert.ErrorContains(t, err, "invalid repository format")
+}
@@ -0,0 +1,70 @@
+package github
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/google/go-github/v41/github"
+)
+
+// WebhookSecretEnv names the environment variable holding the secret GitHub
+// signs webhook deliveries with.
+const WebhookSecretEnv = "GITHUB_WEBHOOK_SECRET"
+
+// maxWebhookPayload is the largest delivery GitHub sends, 25 MB.
+const maxWebhookPayload = 25 << 20
+
+var (
+	// ErrInvalidSignature is returned for webhook deliveries that aren't
+	// signed with the secret, or can't be read.
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	// ErrNotIssueEvent is returned for webhook deliveries of other events than
+	// issues, such as the ping sent when a webhook is created.
+	ErrNotIssueEvent = errors.New("not an issues event")
+)
+
+// IssueEvent is an issues webhook delivery.
+type IssueEvent struct {
+	// Delivery is the GUID GitHub identifies the delivery by
+	Delivery string
+	// Action is what happened to the issue, e.g. "opened" or "labeled"
+	Action string
+	// Repository is the repository of the issue in owner/repo form
+	Repository string
+	// Issue is the issue as it was after the change
+	Issue models.GitHubIssue
+}
+
+// ParseIssueEvent verifies that a webhook delivery is signed with secret and
+// returns the issues event it carries. It returns an error wrapping
+// ErrInvalidSignature if the signature doesn't match, and ErrNotIssueEvent
+// for deliveries of other events.
+func ParseIssueEvent(r *http.Request, secret string) (IssueEvent, error) {
+	r.Body = http.MaxBytesReader(nil, r.Body, maxWebhookPayload)
+	payload, err := github.ValidatePayload(r, []byte(secret))
+	if err != nil {
+		return IssueEvent{}, fmt.Errorf("%w: %v", ErrInvalidSignature, err)
+	}
+
+	eventType := github.WebHookType(r)
+	if eventType != "issues" {
+		return IssueEvent{}, fmt.Errorf("%w: %s", ErrNotIssueEvent, eventType)
+	}
+	parsed, err := github.ParseWebHook(eventType, payload)
+	if err != nil {
+		return IssueEvent{}, fmt.Errorf("failed to parse issues event: %w", err)
+	}
+	event, ok := parsed.(*github.IssuesEvent)
+	if !ok || event.Issue == nil || event.GetRepo().GetFullName() == "" {
+		return IssueEvent{}, fmt.Errorf("failed to parse issues event: no issue or repository in delivery %s", github.DeliveryID(r))
+	}
+
+	return IssueEvent{
+		Delivery:   github.DeliveryID(r),
+		Action:     event.GetAction(),
+		Repository: event.GetRepo().GetFullName(),
+		Issue:      convertIssue(event.Issue),
+	}, nil
+}
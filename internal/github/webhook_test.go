@@ -0,0 +1,79 @@
+package github
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// webhookRequest returns a delivery of payload as eventType, signed with secret.
+func webhookRequest(eventType, payload, secret string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/webhook", strings.NewReader(payload))
+	r.Header.Set("Content-Type", "application/json")
+	r.Header.Set("X-GitHub-Event", eventType)
+	r.Header.Set("X-GitHub-Delivery", "72d3162e-cc78-11e3-81ab-4c9367dc0958")
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(payload))
+	r.Header.Set("X-Hub-Signature-256", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	return r
+}
+
+func TestParseIssueEvent(t *testing.T) {
+	payload := `{
+		"action": "labeled",
+		"issue": {
+			"number": 42,
+			"title": "Add login",
+			"body": "As a user...",
+			"state": "open",
+			"labels": [{"name": "story"}, {"name": "PROJ"}],
+			"user": {"login": "octocat"},
+			"html_url": "https://github.com/owner/repo/issues/42"
+		},
+		"repository": {"full_name": "owner/repo"}
+	}`
+
+	event, err := ParseIssueEvent(webhookRequest("issues", payload, "s3cret"), "s3cret")
+	require.NoError(t, err)
+	assert.Equal(t, "72d3162e-cc78-11e3-81ab-4c9367dc0958", event.Delivery)
+	assert.Equal(t, "labeled", event.Action)
+	assert.Equal(t, "owner/repo", event.Repository)
+	assert.Equal(t, 42, event.Issue.Number)
+	assert.Equal(t, "Add login", event.Issue.Title)
+	assert.Equal(t, []string{"story", "PROJ"}, event.Issue.Labels)
+	assert.Equal(t, "octocat", event.Issue.Author)
+	assert.Equal(t, "https://github.com/owner/repo/issues/42", event.Issue.URL)
+}
+
+func TestParseIssueEventRejectsDeliveries(t *testing.T) {
+	issues := `{"action": "opened", "issue": {"number": 1}, "repository": {"full_name": "owner/repo"}}`
+	unsigned := webhookRequest("issues", issues, "s3cret")
+	unsigned.Header.Del("X-Hub-Signature-256")
+
+	tests := []struct {
+		name    string
+		request *http.Request
+		want    error
+	}{
+		{"wrong secret", webhookRequest("issues", issues, "guess"), ErrInvalidSignature},
+		{"unsigned", unsigned, ErrInvalidSignature},
+		{"ping", webhookRequest("ping", `{"zen": "Keep it simple."}`, "s3cret"), ErrNotIssueEvent},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseIssueEvent(tt.request, "s3cret")
+			assert.ErrorIs(t, err, tt.want)
+		})
+	}
+
+	_, err := ParseIssueEvent(webhookRequest("issues", `{"action": "opened"}`, "s3cret"), "s3cret")
+	assert.ErrorContains(t, err, "no issue or repository")
+}
@@ -0,0 +1,65 @@
+// Package hooks runs user-provided commands, or posts to user-provided URLs,
+// around synchronization events.
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+	"time"
+)
+
+// DefaultTimeout bounds a hook command when no timeout is configured.
+const DefaultTimeout = 30 * time.Second
+
+// maxOutput is the number of bytes of hook output included in errors.
+const maxOutput = 512
+
+// Run executes command with the JSON-encoded payload on stdin. The event name
+// is exported as GLUE_HOOK_EVENT. The command is killed after timeout, or
+// DefaultTimeout if timeout is zero. It returns an error including the
+// command's output if it cannot be started or exits with a non-zero status.
+func Run(ctx context.Context, command []string, timeout time.Duration, event string, payload interface{}) error {
+	if len(command) == 0 || command[0] == "" {
+		return fmt.Errorf("hook command is empty")
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	input, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook payload: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdin = bytes.NewReader(input)
+	cmd.Env = append(os.Environ(), "GLUE_HOOK_EVENT="+event)
+
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	if err := cmd.Run(); err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("hook %s timed out after %s", command[0], timeout)
+		}
+		return fmt.Errorf("hook %s failed: %v: %s", command[0], err, truncate(strings.TrimSpace(output.String())))
+	}
+	return nil
+}
+
+// truncate shortens hook output for error messages.
+func truncate(output string) string {
+	if len(output) <= maxOutput {
+		return output
+	}
+	return output[:maxOutput] + "..."
+}
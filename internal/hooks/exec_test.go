@@ -0,0 +1,70 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireShell(t *testing.T) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+}
+
+func TestRunPassesPayloadAndEvent(t *testing.T) {
+	requireShell(t)
+
+	out := filepath.Join(t.TempDir(), "out")
+	command := []string{"sh", "-c", `cat > "$1"; echo "$GLUE_HOOK_EVENT" >> "$1"`, "hook", out}
+	payload := map[string]interface{}{"repository": "owner/repo", "issue_number": 42}
+
+	require.NoError(t, Run(context.Background(), command, 0, "post_issue", payload))
+
+	content, err := os.ReadFile(out)
+	require.NoError(t, err)
+
+	// The payload is followed by the event name written by the script
+	var decoded map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	require.NoError(t, decoder.Decode(&decoded))
+	assert.Equal(t, "owner/repo", decoded["repository"])
+	assert.Equal(t, float64(42), decoded["issue_number"])
+	assert.Contains(t, string(content), "}post_issue\n")
+}
+
+func TestRunFailure(t *testing.T) {
+	requireShell(t)
+
+	err := Run(context.Background(), []string{"sh", "-c", "echo boom >&2; exit 3"}, 0, "pre_sync", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "boom")
+}
+
+func TestRunTimeout(t *testing.T) {
+	requireShell(t)
+
+	err := Run(context.Background(), []string{"sh", "-c", "sleep 5"}, 50*time.Millisecond, "post_run", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "timed out")
+}
+
+func TestRunEmptyCommand(t *testing.T) {
+	assert.Error(t, Run(context.Background(), nil, 0, "pre_sync", nil))
+	assert.Error(t, Run(context.Background(), []string{""}, 0, "pre_sync", nil))
+}
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", truncate("short"))
+	long := string(make([]byte, maxOutput+10))
+	assert.Len(t, truncate(long), maxOutput+3)
+}
@@ -0,0 +1,86 @@
+package hooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Headers of the requests posting payloads to hook URLs.
+const (
+	// EventHeader carries the event name
+	EventHeader = "X-Glue-Event"
+	// TimestampHeader carries the time the request was signed, in Unix seconds
+	TimestampHeader = "X-Glue-Timestamp"
+	// SignatureHeader carries "sha256=" and the hex-encoded HMAC-SHA256 of the
+	// timestamp, a period and the body, keyed with the hook's secret
+	SignatureHeader = "X-Glue-Signature"
+)
+
+// Post sends the JSON-encoded payload to url. With a secret, the request is
+// signed (see Sign) so the receiver can verify it came from glue and reject
+// replays by the timestamp. The request is abandoned after timeout, or
+// DefaultTimeout if timeout is zero. It returns an error including the start
+// of the response body unless the receiver answers with a 2xx status.
+func Post(ctx context.Context, url, secret string, timeout time.Duration, event string, payload interface{}) error {
+	if url == "" {
+		return fmt.Errorf("hook url is empty")
+	}
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook payload: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("invalid hook url %s: %v", url, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, timestamp)
+		req.Header.Set(SignatureHeader, Sign(secret, timestamp, body))
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return fmt.Errorf("hook %s timed out after %s", url, timeout)
+		}
+		return fmt.Errorf("hook %s failed: %v", url, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		output, _ := io.ReadAll(io.LimitReader(resp.Body, maxOutput+1))
+		return fmt.Errorf("hook %s failed: %s: %s", url, resp.Status, truncate(strings.TrimSpace(string(output))))
+	}
+	return nil
+}
+
+// Sign returns the signature of a hook request body sent at timestamp, in
+// the form of the SignatureHeader.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,75 @@
+package hooks
+
+import (
+	"context"
+	"crypto/hmac"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostSignsPayload(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	payload := map[string]interface{}{"repository": "owner/repo", "issue_number": 42}
+	require.NoError(t, Post(context.Background(), server.URL, "s3cret", 0, "post_issue", payload))
+
+	assert.Equal(t, "application/json", received.Header.Get("Content-Type"))
+	assert.Equal(t, "post_issue", received.Header.Get(EventHeader))
+	var decoded map[string]interface{}
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, "owner/repo", decoded["repository"])
+
+	// The receiver recomputes the signature from the timestamp and raw body
+	timestamp := received.Header.Get(TimestampHeader)
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), time.Unix(sent, 0), time.Minute)
+	signature := received.Header.Get(SignatureHeader)
+	assert.True(t, hmac.Equal([]byte(Sign("s3cret", timestamp, body)), []byte(signature)))
+	assert.NotEqual(t, Sign("other", timestamp, body), signature)
+}
+
+func TestPostUnsigned(t *testing.T) {
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header
+	}))
+	defer server.Close()
+
+	require.NoError(t, Post(context.Background(), server.URL, "", 0, "post_run", nil))
+	assert.Empty(t, headers.Get(SignatureHeader))
+	assert.Empty(t, headers.Get(TimestampHeader))
+}
+
+func TestPostFailure(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "invalid signature", http.StatusUnauthorized)
+	}))
+	defer server.Close()
+
+	err := Post(context.Background(), server.URL, "s3cret", 0, "pre_sync", nil)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "401 Unauthorized: invalid signature")
+
+	assert.Error(t, Post(context.Background(), "", "", 0, "pre_sync", nil))
+}
+
+func TestSign(t *testing.T) {
+	// printf '1700000000.{}' | openssl dgst -sha256 -hmac s3cret
+	assert.Equal(t, "sha256=97926816e98fbb41ccb1673225ff29a2f35369099990e1b1561651e7bd097ebf",
+		Sign("s3cret", "1700000000", []byte("{}")))
+}
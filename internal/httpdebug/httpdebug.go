@@ -0,0 +1,184 @@
+// Package httpdebug logs every GitHub and JIRA API call when GLUE_DEBUG_HTTP
+// is set, e.g. by --debug-http: method, URL, status, duration and rate limit
+// headers, with secrets redacted. It also counts the calls per endpoint, so
+// a summary can be printed at the end of the run.
+package httpdebug
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/recorder"
+)
+
+// Env is the environment variable enabling the transport.
+const Env = "GLUE_DEBUG_HTTP"
+
+// rateLimitHeaders are the response headers logged with each call.
+var rateLimitHeaders = []string{"X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Reset", "Retry-After"}
+
+var (
+	numberSegment = regexp.MustCompile(`^[0-9]+$`)
+	keySegment    = regexp.MustCompile(`^[A-Z][A-Z0-9_]*-[0-9]+$`)
+	shaSegment    = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
+
+// Endpoint is an API endpoint: a client name, method and path in which
+// issue numbers, ticket keys and commit SHAs are replaced by placeholders.
+type Endpoint struct {
+	Client string
+	Method string
+	Path   string
+}
+
+// EndpointStats are the calls made to one endpoint.
+type EndpointStats struct {
+	Calls    int
+	Errors   int
+	Duration time.Duration
+}
+
+// Stats collects the calls of all transports sharing it. It is safe for
+// concurrent use.
+type Stats struct {
+	mu        sync.Mutex
+	endpoints map[Endpoint]*EndpointStats
+}
+
+// DefaultStats collects the calls of the transports returned by Wrap.
+var DefaultStats = &Stats{}
+
+// add records a call to an endpoint.
+func (s *Stats) add(endpoint Endpoint, duration time.Duration, failed bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.endpoints == nil {
+		s.endpoints = make(map[Endpoint]*EndpointStats)
+	}
+	stats, ok := s.endpoints[endpoint]
+	if !ok {
+		stats = &EndpointStats{}
+		s.endpoints[endpoint] = stats
+	}
+	stats.Calls++
+	stats.Duration += duration
+	if failed {
+		stats.Errors++
+	}
+}
+
+// WriteSummary writes a table of the calls per endpoint, most called first.
+// It writes nothing if no calls were recorded.
+func (s *Stats) WriteSummary(w io.Writer) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.endpoints) == 0 {
+		return nil
+	}
+
+	endpoints := make([]Endpoint, 0, len(s.endpoints))
+	total := 0
+	for endpoint, stats := range s.endpoints {
+		endpoints = append(endpoints, endpoint)
+		total += stats.Calls
+	}
+	sort.Slice(endpoints, func(i, j int) bool {
+		a, b := s.endpoints[endpoints[i]], s.endpoints[endpoints[j]]
+		if a.Calls != b.Calls {
+			return a.Calls > b.Calls
+		}
+		if endpoints[i].Client != endpoints[j].Client {
+			return endpoints[i].Client < endpoints[j].Client
+		}
+		if endpoints[i].Path != endpoints[j].Path {
+			return endpoints[i].Path < endpoints[j].Path
+		}
+		return endpoints[i].Method < endpoints[j].Method
+	})
+
+	fmt.Fprintf(w, "API calls: %d\n", total)
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CLIENT\tMETHOD\tENDPOINT\tCALLS\tERRORS\tAVG")
+	for _, endpoint := range endpoints {
+		stats := s.endpoints[endpoint]
+		average := stats.Duration / time.Duration(stats.Calls)
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%d\t%d\t%s\n", endpoint.Client, endpoint.Method, endpoint.Path,
+			stats.Calls, stats.Errors, average.Round(time.Millisecond))
+	}
+	return tw.Flush()
+}
+
+// Transport logs and counts the calls it passes to Base.
+type Transport struct {
+	// Base sends the requests
+	Base http.RoundTripper
+	// Client names the API in logs and the summary, e.g. "github"
+	Client string
+	// Stats collects the calls
+	Stats *Stats
+}
+
+// Wrap returns base logging and counting its calls into DefaultStats if
+// GLUE_DEBUG_HTTP is set, and base unchanged otherwise.
+func Wrap(base http.RoundTripper, client string) http.RoundTripper {
+	if os.Getenv(Env) == "" {
+		return base
+	}
+	return &Transport{Base: base, Client: client, Stats: DefaultStats}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	resp, err := t.Base.RoundTrip(req)
+	duration := time.Since(start)
+
+	endpoint := Endpoint{Client: t.Client, Method: req.Method, Path: EndpointPath(req.URL.Path)}
+	t.Stats.add(endpoint, duration, err != nil || resp.StatusCode >= 400)
+
+	args := []any{
+		"client", t.Client,
+		"method", req.Method,
+		"url", recorder.SanitizeURL(req.URL),
+		"duration", duration.Round(time.Millisecond),
+	}
+	if err != nil {
+		logging.Info("http request failed", append(args, "error", err)...)
+		return resp, err
+	}
+	args = append(args, "status", resp.StatusCode)
+	for _, header := range rateLimitHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			args = append(args, strings.ToLower(header), value)
+		}
+	}
+	logging.Info("http request", args...)
+	return resp, nil
+}
+
+// EndpointPath replaces the issue numbers, ticket keys and commit SHAs in a
+// URL path with placeholders, so calls for different issues add up.
+func EndpointPath(path string) string {
+	segments := strings.Split(path, "/")
+	for i, segment := range segments {
+		switch {
+		case numberSegment.MatchString(segment) && (i == 0 || segments[i-1] != "api"):
+			// JIRA's API version, as in /rest/api/2, is kept
+			segments[i] = "{number}"
+		case keySegment.MatchString(segment):
+			segments[i] = "{key}"
+		case shaSegment.MatchString(segment):
+			segments[i] = "{sha}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
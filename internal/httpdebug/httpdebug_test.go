@@ -0,0 +1,63 @@
+package httpdebug
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEndpointPath(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/repos/owner/repo/issues/42", "/repos/owner/repo/issues/{number}"},
+		{"/api/v3/repos/owner/repo/issues/42/labels", "/api/v3/repos/owner/repo/issues/{number}/labels"},
+		{"/rest/api/2/issue/PROJ-123/remotelink", "/rest/api/2/issue/{key}/remotelink"},
+		{"/repos/owner/repo/commits/0123456789abcdef0123456789abcdef01234567/pulls", "/repos/owner/repo/commits/{sha}/pulls"},
+		{"/rest/api/2/search", "/rest/api/2/search"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, EndpointPath(tt.path))
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Remaining", "4999")
+		if strings.HasSuffix(r.URL.Path, "/2") {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	stats := &Stats{}
+	client := &http.Client{Transport: &Transport{Base: http.DefaultTransport, Client: "github", Stats: stats}}
+	for _, path := range []string{"/repos/o/r/issues/1", "/repos/o/r/issues/2", "/repos/o/r/labels?access_token=secret"} {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	var out bytes.Buffer
+	require.NoError(t, stats.WriteSummary(&out))
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	require.Len(t, lines, 4)
+	assert.Equal(t, "API calls: 3", lines[0])
+	assert.Regexp(t, `^github\s+GET\s+/repos/o/r/issues/\{number\}\s+2\s+1\s`, lines[2])
+	assert.Regexp(t, `^github\s+GET\s+/repos/o/r/labels\s+1\s+0\s`, lines[3])
+}
+
+func TestWriteSummaryEmpty(t *testing.T) {
+	var out bytes.Buffer
+	require.NoError(t, (&Stats{}).WriteSummary(&out))
+	assert.Empty(t, out.String())
+}
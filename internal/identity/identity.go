@@ -0,0 +1,124 @@
+// Package identity resolves GitHub users to JIRA users, so that work assigned
+// or attributed on GitHub can be attributed to the same person in JIRA.
+//
+// A login is resolved through the user mappings of the config file first, then
+// by searching JIRA users for the email address and name of the GitHub user.
+// Results, including failed lookups, are cached for the lifetime of a Resolver.
+package identity
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// GitHubUsers looks up GitHub users.
+type GitHubUsers interface {
+	GetUser(ctx context.Context, login string) (models.GitHubUser, error)
+}
+
+// JiraUsers searches JIRA users by email address, name or username.
+type JiraUsers interface {
+	FindUsers(query string) ([]models.JiraUser, error)
+}
+
+// Resolver resolves GitHub logins to JIRA users. It is safe for concurrent use.
+type Resolver struct {
+	overrides config.UserMappings
+	github    GitHubUsers
+	jira      JiraUsers
+
+	mu    sync.Mutex
+	cache map[string]*models.JiraUser
+}
+
+// NewResolver returns a Resolver using the given user mappings as overrides.
+func NewResolver(overrides config.UserMappings, github GitHubUsers, jira JiraUsers) *Resolver {
+	return &Resolver{
+		overrides: overrides,
+		github:    github,
+		jira:      jira,
+		cache:     make(map[string]*models.JiraUser),
+	}
+}
+
+// JiraUser returns the JIRA user of a GitHub login, or nil if it can't be
+// resolved unambiguously. Errors are returned for failed lookups only; they
+// are not cached, so the next call retries.
+func (r *Resolver) JiraUser(ctx context.Context, login string) (*models.JiraUser, error) {
+	key := strings.ToLower(login)
+
+	r.mu.Lock()
+	user, ok := r.cache[key]
+	r.mu.Unlock()
+	if ok {
+		return user, nil
+	}
+
+	user, err := r.resolve(ctx, login)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cache[key] = user
+	r.mu.Unlock()
+	return user, nil
+}
+
+// resolve looks a login up without the cache.
+func (r *Resolver) resolve(ctx context.Context, login string) (*models.JiraUser, error) {
+	if override := r.override(login); override != "" {
+		user, err := r.find(override)
+		if err != nil {
+			return nil, err
+		}
+		if user == nil {
+			// Account IDs aren't searchable; use the mapped identifier as is
+			user = &models.JiraUser{AccountID: override}
+		}
+		return user, nil
+	}
+
+	ghUser, err := r.github.GetUser(ctx, login)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get github user %s: %w", login, err)
+	}
+
+	for _, query := range []string{ghUser.Email, ghUser.Name} {
+		if query == "" {
+			continue
+		}
+		user, err := r.find(query)
+		if err != nil || user != nil {
+			return user, err
+		}
+	}
+	return nil, nil
+}
+
+// override returns the JIRA identifier mapped to a login in the config file.
+func (r *Resolver) override(login string) string {
+	for _, user := range r.overrides {
+		if strings.EqualFold(user.GitHub, login) {
+			return user.Jira
+		}
+	}
+	return ""
+}
+
+// find returns the only JIRA user matching query, or nil if none or several match.
+func (r *Resolver) find(query string) (*models.JiraUser, error) {
+	users, err := r.jira.FindUsers(query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jira users for %q: %w", query, err)
+	}
+	if len(users) != 1 {
+		return nil, nil
+	}
+	return &users[0], nil
+}
@@ -0,0 +1,86 @@
+package identity
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeGitHub struct {
+	users map[string]models.GitHubUser
+	calls int
+}
+
+func (f *fakeGitHub) GetUser(_ context.Context, login string) (models.GitHubUser, error) {
+	f.calls++
+	user, ok := f.users[login]
+	if !ok {
+		return models.GitHubUser{}, errors.New("not found")
+	}
+	return user, nil
+}
+
+type fakeJira struct {
+	users   map[string][]models.JiraUser
+	queries []string
+}
+
+func (f *fakeJira) FindUsers(query string) ([]models.JiraUser, error) {
+	f.queries = append(f.queries, query)
+	return f.users[query], nil
+}
+
+func TestResolver(t *testing.T) {
+	ctx := context.Background()
+	jane := models.JiraUser{AccountID: "acc-jane", DisplayName: "Jane Doe"}
+	gh := &fakeGitHub{users: map[string]models.GitHubUser{
+		"janedoe":   {Login: "janedoe", Name: "Jane Doe", Email: "jane@example.com"},
+		"byname":    {Login: "byname", Name: "Jane Doe"},
+		"ambiguous": {Login: "ambiguous", Name: "John Smith"},
+	}}
+	jira := &fakeJira{users: map[string][]models.JiraUser{
+		"jane@example.com": {jane},
+		"Jane Doe":         {jane},
+		"John Smith":       {{AccountID: "a"}, {AccountID: "b"}},
+		"ops@example.com":  {{AccountID: "acc-ops"}},
+	}}
+	resolver := NewResolver(config.UserMappings{
+		{Jira: "ops@example.com", GitHub: "OpsBot"},
+		{Jira: "557058:f58131cb", GitHub: "octocat"},
+	}, gh, jira)
+
+	tests := []struct {
+		login string
+		want  *models.JiraUser
+	}{
+		{login: "janedoe", want: &jane},
+		{login: "byname", want: &jane},
+		{login: "ambiguous", want: nil},
+		{login: "opsbot", want: &models.JiraUser{AccountID: "acc-ops"}},
+		{login: "octocat", want: &models.JiraUser{AccountID: "557058:f58131cb"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.login, func(t *testing.T) {
+			user, err := resolver.JiraUser(ctx, tt.login)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, user)
+		})
+	}
+
+	// Results are cached, including unresolved logins
+	githubCalls, jiraCalls := gh.calls, len(jira.queries)
+	for _, tt := range tests {
+		_, err := resolver.JiraUser(ctx, tt.login)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, githubCalls, gh.calls)
+	assert.Equal(t, jiraCalls, len(jira.queries))
+
+	_, err := resolver.JiraUser(ctx, "unknown")
+	assert.Error(t, err)
+}
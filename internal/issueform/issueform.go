@@ -0,0 +1,132 @@
+// Package issueform parses GitHub issue bodies written with issue forms or
+// issue templates, where each field is rendered as a "### Heading" followed by
+// its value, so that individual sections can be mapped to JIRA fields.
+package issueform
+
+import (
+	"regexp"
+	"strings"
+)
+
+// noResponse is the value GitHub renders for an optional form field left empty.
+const noResponse = "_No response_"
+
+// headingPattern matches a level three heading line.
+var headingPattern = regexp.MustCompile(`^###\s+(.+?)\s*#*\s*$`)
+
+// Section is a part of an issue body under a level three heading.
+type Section struct {
+	// Heading is the heading text, without the leading hashes
+	Heading string
+	// Value is the text below the heading, trimmed; empty if the form field
+	// was left empty
+	Value string
+}
+
+// Parse returns the sections of an issue body in order. Text before the first
+// heading is not part of any section.
+func Parse(body string) []Section {
+	var sections []Section
+	for _, s := range split(body) {
+		if s.heading != "" {
+			sections = append(sections, Section{Heading: s.heading, Value: value(s.lines[1:])})
+		}
+	}
+	return sections
+}
+
+// Extract removes the sections with the given headings, compared
+// case-insensitively, from an issue body. It returns the remaining body and the
+// values of the removed sections keyed by the heading as given; sections left
+// empty in the form are removed but have no value.
+func Extract(body string, headings []string) (string, map[string]string) {
+	values := make(map[string]string)
+	var kept []string
+	for _, s := range split(body) {
+		heading := matchHeading(s.heading, headings)
+		if heading == "" {
+			kept = append(kept, s.lines...)
+			continue
+		}
+		if v := value(s.lines[1:]); v != "" {
+			values[heading] = v
+		}
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n")), values
+}
+
+// rawSection is a heading line and the lines up to the next heading. The
+// preamble before the first heading has an empty heading.
+type rawSection struct {
+	heading string
+	lines   []string
+}
+
+// split divides a body into raw sections at level three headings. Headings
+// inside fenced code blocks are ignored.
+func split(body string) []rawSection {
+	lines := strings.Split(strings.ReplaceAll(body, "\r\n", "\n"), "\n")
+	sections := []rawSection{{}}
+	inFence := false
+	for _, line := range lines {
+		if strings.HasPrefix(strings.TrimSpace(line), "```") {
+			inFence = !inFence
+		}
+		if !inFence {
+			if m := headingPattern.FindStringSubmatch(line); m != nil {
+				sections = append(sections, rawSection{heading: m[1], lines: []string{line}})
+				continue
+			}
+		}
+		last := &sections[len(sections)-1]
+		last.lines = append(last.lines, line)
+	}
+	return sections
+}
+
+// matchHeading returns the entry of headings equal to heading ignoring case,
+// or an empty string if there is none.
+func matchHeading(heading string, headings []string) string {
+	if heading == "" {
+		return ""
+	}
+	for _, h := range headings {
+		if strings.EqualFold(strings.TrimSpace(h), heading) {
+			return h
+		}
+	}
+	return ""
+}
+
+// value returns the trimmed text of a section, or an empty string if the form
+// field was left empty.
+func value(lines []string) string {
+	v := strings.TrimSpace(strings.Join(lines, "\n"))
+	if v == noResponse {
+		return ""
+	}
+	return v
+}
+
+// ChecklistItem is a task list entry such as "- [x] Error message is shown".
+type ChecklistItem struct {
+	// Text is the entry without the checkbox
+	Text string
+	// Checked reports whether the box is ticked
+	Checked bool
+}
+
+// checklistPattern matches a Markdown task list entry.
+var checklistPattern = regexp.MustCompile(`^\s*[-*+]\s+\[([ xX])\]\s+(.+?)\s*$`)
+
+// ParseChecklist returns the task list entries of a section value in order.
+// Other lines are ignored.
+func ParseChecklist(value string) []ChecklistItem {
+	var items []ChecklistItem
+	for _, line := range strings.Split(value, "\n") {
+		if m := checklistPattern.FindStringSubmatch(line); m != nil {
+			items = append(items, ChecklistItem{Text: m[2], Checked: m[1] != " "})
+		}
+	}
+	return items
+}
@@ -0,0 +1,64 @@
+package issueform
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const formBody = `Intro written above the form.
+
+### Description
+
+Users can't log in with SSO.
+
+### Acceptance Criteria
+
+- [ ] SSO login works
+- [x] Error message is shown
+
+### Environment
+
+_No response_
+
+### Logs
+
+` + "```" + `
+### not a heading
+` + "```"
+
+func TestParse(t *testing.T) {
+	assert.Equal(t, []Section{
+		{Heading: "Description", Value: "Users can't log in with SSO."},
+		{Heading: "Acceptance Criteria", Value: "- [ ] SSO login works\n- [x] Error message is shown"},
+		{Heading: "Environment", Value: ""},
+		{Heading: "Logs", Value: "```\n### not a heading\n```"},
+	}, Parse(formBody))
+
+	assert.Empty(t, Parse("No headings here\n## Level two"))
+}
+
+func TestExtract(t *testing.T) {
+	remaining, values := Extract(formBody, []string{"acceptance criteria", "Environment", "Missing"})
+
+	assert.Equal(t, map[string]string{
+		"acceptance criteria": "- [ ] SSO login works\n- [x] Error message is shown",
+	}, values)
+	assert.Equal(t, "Intro written above the form.\n\n### Description\n\nUsers can't log in with SSO.\n\n### Logs\n\n```\n### not a heading\n```", remaining)
+
+	remaining, values = Extract("Plain body", []string{"Environment"})
+	assert.Equal(t, "Plain body", remaining)
+	assert.Empty(t, values)
+}
+
+func TestParseChecklist(t *testing.T) {
+	value := "Must hold:\n- [ ] SSO login works\n* [X] Error message is shown \n+ [x] Audit log entry\n- plain bullet\n- [] malformed"
+
+	assert.Equal(t, []ChecklistItem{
+		{Text: "SSO login works", Checked: false},
+		{Text: "Error message is shown", Checked: true},
+		{Text: "Audit log entry", Checked: true},
+	}, ParseChecklist(value))
+
+	assert.Empty(t, ParseChecklist("No tasks"))
+}
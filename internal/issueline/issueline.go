@@ -0,0 +1,40 @@
+// Package issueline links the tasks glue creates in other trackers, like
+// Asana, ClickUp and YouTrack, to their GitHub issues. The description of such
+// a task starts with a line with the address of its issue, e.g.
+// "GitHub issue: https://github.com/owner/repo/issues/7", which is how later
+// runs find the task of an issue again.
+package issueline
+
+import (
+	"fmt"
+	"strings"
+)
+
+// prefix starts the line linking a task to its issue.
+const prefix = "GitHub issue: "
+
+// URL returns the address of a GitHub issue on domain, which links it to its
+// task.
+func URL(domain, repository string, number int) string {
+	return fmt.Sprintf("https://%s/%s/issues/%d", domain, repository, number)
+}
+
+// Description returns the description of the task of an issue: the line with
+// the address of the issue, followed by its body.
+func Description(issueURL, body string) string {
+	description := prefix + issueURL
+	if body = strings.TrimSpace(body); body != "" {
+		description += "\n\n" + body
+	}
+	return description
+}
+
+// IssueURL returns the address of the GitHub issue a task with description
+// was created for, or an empty string if glue didn't create it.
+func IssueURL(description string) string {
+	line, _, _ := strings.Cut(strings.TrimLeft(description, "\n"), "\n")
+	if !strings.HasPrefix(line, prefix) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+}
@@ -0,0 +1,26 @@
+package issueline
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestURL(t *testing.T) {
+	assert.Equal(t, "https://github.com/Owner/Repo/issues/7", URL("github.com", "Owner/Repo", 7))
+	assert.Equal(t, "https://git.example.com/owner/repo/issues/12", URL("git.example.com", "owner/repo", 12))
+}
+
+func TestDescription(t *testing.T) {
+	url := "https://github.com/owner/repo/issues/7"
+	assert.Equal(t, "GitHub issue: "+url+"\n\nSteps to reproduce", Description(url, "Steps to reproduce\n"))
+	assert.Equal(t, "GitHub issue: "+url, Description(url, " "))
+}
+
+func TestIssueURL(t *testing.T) {
+	url := "https://github.com/owner/repo/issues/7"
+	assert.Equal(t, url, IssueURL(Description(url, "Body")))
+	assert.Equal(t, url, IssueURL("\n"+Description(url, "")))
+	assert.Empty(t, IssueURL("Created by hand\nGitHub issue: "+url))
+	assert.Empty(t, IssueURL(""))
+}
@@ -0,0 +1,22 @@
+package jira
+
+import (
+	"net/http"
+
+	"github.com/danielolaszy/glue/internal/reauth"
+)
+
+// basicAuthTransport authenticates requests with the current, possibly
+// reloaded, JIRA username and token.
+type basicAuthTransport struct {
+	base        http.RoundTripper
+	credentials *reauth.Credentials
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *basicAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	username, token := t.credentials.Get()
+	req = req.Clone(req.Context())
+	req.SetBasicAuth(username, token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,180 @@
+package jira
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/require"
+)
+
+// callBudget is the most JIRA requests per issue each sync phase may make.
+// Lower a budget after an optimization like batching so it can't regress;
+// raising one needs a good reason.
+var callBudget = map[string]float64{
+	"create": 1,
+	"status": 1,
+	"close":  2,
+	"link":   2,
+	"resync": 1,
+}
+
+// timeBudget bounds the wall time per issue of each phase against the local
+// fake server. It is only checked by the benchmarks and is generous, to
+// catch gross regressions like quadratic loops rather than noise.
+const timeBudget = 5 * time.Millisecond
+
+// phaseResult measures one sync phase.
+type phaseResult struct {
+	name    string
+	issues  int
+	calls   int
+	elapsed time.Duration
+}
+
+// callsPerIssue returns the average number of requests per issue.
+func (p phaseResult) callsPerIssue() float64 {
+	return float64(p.calls) / float64(p.issues)
+}
+
+// newBenchClient returns a client of the fake server built by NewClient, so
+// requests go through the same transports as in a sync. The issue type and
+// fix version of the project are cached up front, as they are looked up once
+// per run rather than per issue.
+func newBenchClient(tb testing.TB, url string) *Client {
+	configFile := filepath.Join(tb.TempDir(), "glue.yaml")
+	require.NoError(tb, os.WriteFile(configFile, nil, 0o644))
+	tb.Setenv("GLUE_CONFIG", configFile)
+	tb.Setenv("GITHUB_TOKEN", "github-token")
+	tb.Setenv("JIRA_URL", url)
+	tb.Setenv("JIRA_USERNAME", "glue")
+	tb.Setenv("JIRA_TOKEN", "jira-token")
+
+	client, err := NewClient()
+	require.NoError(tb, err)
+	client.issueTypeCache["PROJ"] = map[string]string{"story": "10001"}
+	client.fixVersionCache["PROJ"] = nil
+	return client.WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+// runSyncPhases syncs count GitHub issues, every third of them closed, to a
+// fresh fake JIRA and measures each phase: creating the tickets, checking the
+// status of closed issues' tickets, closing them, linking every other ticket
+// to the one before as its parent, and fetching every ticket again as the
+// description and diff passes do.
+func runSyncPhases(tb testing.TB, count int) []phaseResult {
+	fake := newFakeJira()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	client := newBenchClient(tb, server.URL)
+
+	issues := make([]models.GitHubIssue, count)
+	for i := range issues {
+		issues[i] = models.GitHubIssue{Number: i + 1, Title: fmt.Sprintf("Issue %d", i+1), State: "open"}
+		if (i+1)%3 == 0 {
+			issues[i].State = "closed"
+		}
+	}
+	keys := make([]string, count)
+
+	var results []phaseResult
+	phase := func(name string, run func(i int, issue models.GitHubIssue) bool) {
+		fake.resetCalls()
+		start := time.Now()
+		n := 0
+		for i, issue := range issues {
+			if run(i, issue) {
+				n++
+			}
+		}
+		results = append(results, phaseResult{name: name, issues: n, calls: fake.resetCalls(), elapsed: time.Since(start)})
+	}
+
+	phase("create", func(i int, issue models.GitHubIssue) bool {
+		key, err := client.CreateTicketWithTypeID("PROJ", issue, "10001")
+		require.NoError(tb, err)
+		keys[i] = key
+		return true
+	})
+	phase("status", func(i int, issue models.GitHubIssue) bool {
+		if issue.State != "closed" {
+			return false
+		}
+		status, err := client.GetTicketStatus(keys[i])
+		require.NoError(tb, err)
+		require.Equal(tb, "To Do", status)
+		return true
+	})
+	phase("close", func(i int, issue models.GitHubIssue) bool {
+		if issue.State != "closed" {
+			return false
+		}
+		require.NoError(tb, client.CloseTicket(keys[i]))
+		return true
+	})
+	phase("link", func(i int, issue models.GitHubIssue) bool {
+		if i%2 == 0 {
+			return false
+		}
+		exists, err := client.CheckParentChildLinkExists(keys[i-1], keys[i])
+		require.NoError(tb, err)
+		require.False(tb, exists)
+		require.NoError(tb, client.CreateParentChildLink(keys[i-1], keys[i]))
+		return true
+	})
+	children, err := client.GetIssueLinks(keys[0])
+	require.NoError(tb, err)
+	require.Equal(tb, map[string]bool{keys[1]: true}, children)
+	phase("resync", func(i int, issue models.GitHubIssue) bool {
+		_, err := client.GetTicket(keys[i])
+		require.NoError(tb, err)
+		return true
+	})
+
+	return results
+}
+
+// checkCallBudget fails if a phase made more requests per issue than its budget.
+func checkCallBudget(tb testing.TB, results []phaseResult) {
+	for _, result := range results {
+		if got := result.callsPerIssue(); got > callBudget[result.name] {
+			tb.Errorf("%s phase made %.2f jira requests per issue, budget is %.2f", result.name, got, callBudget[result.name])
+		}
+	}
+}
+
+func TestSyncCallBudget(t *testing.T) {
+	checkCallBudget(t, runSyncPhases(t, 300))
+}
+
+// BenchmarkSync reports the requests and wall time per issue of each sync
+// phase for large repositories. Run with:
+//
+//	go test ./internal/jira -run '^$' -bench Sync -benchtime 1x
+func BenchmarkSync(b *testing.B) {
+	for _, count := range []int{1000, 5000} {
+		b.Run(fmt.Sprintf("%dk", count/1000), func(b *testing.B) {
+			var results []phaseResult
+			for i := 0; i < b.N; i++ {
+				results = runSyncPhases(b, count)
+			}
+
+			checkCallBudget(b, results)
+			for _, result := range results {
+				perIssue := result.elapsed / time.Duration(result.issues)
+				b.ReportMetric(result.callsPerIssue(), result.name+"-calls/issue")
+				b.ReportMetric(float64(perIssue.Microseconds()), result.name+"-us/issue")
+				if perIssue > timeBudget {
+					b.Errorf("%s phase took %v per issue, budget is %v", result.name, perIssue, timeBudget)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,113 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// DefaultRequestTimeout bounds each attempt of a JIRA request when no timeout
+// is configured. Waits for throttling to end between attempts don't count.
+const DefaultRequestTimeout = 30 * time.Second
+
+// DefaultMaxConsecutiveFailures is the number of consecutive failed JIRA
+// requests after which the circuit breaker trips, when none is configured.
+const DefaultMaxConsecutiveFailures = 5
+
+// ErrCircuitOpen is returned for JIRA requests made after the circuit breaker tripped.
+var ErrCircuitOpen = errors.New("jira circuit breaker open")
+
+// breaker counts consecutive failed requests. Once the threshold is reached it
+// stays open for the rest of the run, so every further request fails fast.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	failures  int
+	open      bool
+	lastErr   error
+}
+
+// newBreaker returns a breaker tripping after threshold consecutive failures.
+// A threshold of zero or less disables it.
+func newBreaker(threshold int) *breaker {
+	return &breaker{threshold: threshold}
+}
+
+// allow returns an error if the breaker is open.
+func (b *breaker) allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.open {
+		return b.openErr()
+	}
+	return nil
+}
+
+// success resets the consecutive failure count.
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+}
+
+// failure records a failed request and trips the breaker at the threshold.
+func (b *breaker) failure(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.lastErr = err
+	if b.threshold > 0 && b.failures >= b.threshold {
+		b.open = true
+	}
+}
+
+// state returns the error describing why the breaker is open, or nil.
+func (b *breaker) state() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.open {
+		return nil
+	}
+	return b.openErr()
+}
+
+// openErr describes the open breaker; the caller must hold mu.
+func (b *breaker) openErr() error {
+	return fmt.Errorf("%w after %d consecutive failed requests, last error: %v", ErrCircuitOpen, b.failures, b.lastErr)
+}
+
+// breakerTransport passes requests through a breaker. Transport errors, 5xx
+// responses and rate limiting count as failures.
+type breakerTransport struct {
+	base    http.RoundTripper
+	breaker *breaker
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *breakerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.breaker.allow(); err != nil {
+		return nil, err
+	}
+
+	resp, err := t.base.RoundTrip(req)
+	switch {
+	case err != nil:
+		t.breaker.failure(err)
+	case resp.StatusCode >= http.StatusInternalServerError || resp.StatusCode == http.StatusTooManyRequests:
+		t.breaker.failure(fmt.Errorf("%s %s: %s", req.Method, req.URL.Path, resp.Status))
+	default:
+		t.breaker.success()
+	}
+	return resp, err
+}
+
+// CircuitOpen returns an error wrapping ErrCircuitOpen if JIRA has failed
+// consistently and further requests are refused, or nil otherwise.
+func (c *Client) CircuitOpen() error {
+	if c == nil || c.breaker == nil {
+		return nil
+	}
+	return c.breaker.state()
+}
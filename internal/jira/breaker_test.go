@@ -0,0 +1,71 @@
+package jira
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBreakerTripsAfterConsecutiveFailures(t *testing.T) {
+	b := newBreaker(3)
+
+	b.failure(errors.New("timeout"))
+	b.failure(errors.New("timeout"))
+	b.success()
+	b.failure(errors.New("timeout"))
+	b.failure(errors.New("timeout"))
+	assert.NoError(t, b.allow())
+
+	b.failure(errors.New("connection refused"))
+	err := b.allow()
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Contains(t, err.Error(), "3 consecutive failed requests")
+	assert.Contains(t, err.Error(), "connection refused")
+
+	// Stays open for the rest of the run
+	b.success()
+	assert.ErrorIs(t, b.state(), ErrCircuitOpen)
+}
+
+func TestBreakerDisabled(t *testing.T) {
+	b := newBreaker(0)
+	for i := 0; i < 10; i++ {
+		b.failure(errors.New("timeout"))
+	}
+	assert.NoError(t, b.allow())
+}
+
+func TestBreakerTransport(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	b := newBreaker(2)
+	httpClient := &http.Client{Transport: &breakerTransport{base: http.DefaultTransport, breaker: b}}
+
+	// Client errors don't count as failures
+	status = http.StatusNotFound
+	resp, err := httpClient.Get(server.URL)
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	status = http.StatusServiceUnavailable
+	for i := 0; i < 2; i++ {
+		resp, err := httpClient.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+	}
+
+	_, err = httpClient.Get(server.URL)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	client := &Client{breaker: b}
+	assert.ErrorIs(t, client.CircuitOpen(), ErrCircuitOpen)
+	assert.NoError(t, (&Client{}).CircuitOpen())
+}
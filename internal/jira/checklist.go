@@ -0,0 +1,141 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/issueform"
+)
+
+// SyncAcceptanceCriteria mirrors the acceptance criteria checklist of a GitHub
+// issue body to the configured JIRA field, if its items or their completion
+// state differ from the field's current value. It reports whether the field was
+// updated. Nothing is done if no field is configured or the body has no
+// acceptance criteria section.
+func (c *Client) SyncAcceptanceCriteria(key, body string) (bool, error) {
+	if c.checklist.Field == "" {
+		return false, nil
+	}
+	if c.client == nil {
+		return false, fmt.Errorf("jira client not initialized")
+	}
+
+	_, fields := extractChecklist(body, c.checklist)
+	want, ok := fields[c.checklist.Field]
+	if !ok {
+		return false, nil
+	}
+
+	changed := false
+	err := c.editWithRetry(key, func() error {
+		issue, resp, err := c.client.Issue.Get(key, &jira.GetQueryOptions{Fields: c.checklist.Field + ",updated"})
+		if err != nil {
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			return fmt.Errorf("failed to get acceptance criteria of %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+		}
+
+		var current interface{}
+		var read time.Time
+		if issue != nil && issue.Fields != nil {
+			current = issue.Fields.Unknowns[c.checklist.Field]
+			read = time.Time(issue.Fields.Updated)
+		}
+		if checklistEqual(current, want) {
+			return nil
+		}
+		if err := c.checkUnchanged(key, read); err != nil {
+			return err
+		}
+
+		changed = true
+		return c.UpdateFields(key, map[string]interface{}{c.checklist.Field: want})
+	})
+	if err != nil || !changed {
+		return false, err
+	}
+	c.log().Info("updated acceptance criteria", "ticket", key, "field", c.checklist.Field)
+	return true, nil
+}
+
+// extractChecklist removes the acceptance criteria section from an issue body.
+// It returns the remaining body and the checklist as the value of the
+// configured field, in the configured format. Without a configured field, or
+// if the body has no such section, the body is returned unchanged.
+func extractChecklist(body string, checklist config.ChecklistConfig) (string, map[string]interface{}) {
+	if checklist.Field == "" {
+		return body, nil
+	}
+
+	var section *issueform.Section
+	for _, s := range issueform.Parse(body) {
+		if strings.EqualFold(s.Heading, checklist.Heading) {
+			section = &s
+			break
+		}
+	}
+	if section == nil {
+		return body, nil
+	}
+
+	remaining, _ := issueform.Extract(body, []string{checklist.Heading})
+	items := issueform.ParseChecklist(section.Value)
+	return remaining, map[string]interface{}{checklist.Field: renderChecklist(items, checklist.Format)}
+}
+
+// renderChecklist returns the field value of a checklist: "[x] item" lines for
+// text fields, or a list of items for checklist plugin fields.
+func renderChecklist(items []issueform.ChecklistItem, format string) interface{} {
+	if format == config.ChecklistFormatItems {
+		list := make([]interface{}, 0, len(items))
+		for i, item := range items {
+			list = append(list, map[string]interface{}{
+				"name":    item.Text,
+				"checked": item.Checked,
+				"rank":    i,
+			})
+		}
+		return list
+	}
+
+	lines := make([]string, 0, len(items))
+	for _, item := range items {
+		box := "[ ]"
+		if item.Checked {
+			box = "[x]"
+		}
+		lines = append(lines, box+" "+item.Text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// checklistEqual reports whether the current value of a field, as decoded from
+// JSON, holds the same checklist as want.
+func checklistEqual(current, want interface{}) bool {
+	switch want := want.(type) {
+	case string:
+		text, _ := current.(string)
+		return strings.TrimSpace(strings.ReplaceAll(text, "\r\n", "\n")) == want
+	case []interface{}:
+		list, _ := current.([]interface{})
+		if len(list) != len(want) {
+			return false
+		}
+		for i := range want {
+			have, _ := list[i].(map[string]interface{})
+			item := want[i].(map[string]interface{})
+			if have["name"] != item["name"] || have["checked"] != item["checked"] {
+				return false
+			}
+		}
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,97 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/issueform"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const checklistBody = "Login is broken\n\n### Acceptance Criteria\n\n- [x] SSO login works\n- [ ] Error message is shown"
+
+func TestExtractChecklist(t *testing.T) {
+	text := config.ChecklistConfig{Heading: "Acceptance Criteria", Field: "customfield_10040", Format: config.ChecklistFormatText}
+
+	remaining, fields := extractChecklist(checklistBody, text)
+	assert.Equal(t, "Login is broken", remaining)
+	assert.Equal(t, map[string]interface{}{
+		"customfield_10040": "[x] SSO login works\n[ ] Error message is shown",
+	}, fields)
+
+	items := text
+	items.Format = config.ChecklistFormatItems
+	_, fields = extractChecklist(checklistBody, items)
+	assert.Equal(t, []interface{}{
+		map[string]interface{}{"name": "SSO login works", "checked": true, "rank": 0},
+		map[string]interface{}{"name": "Error message is shown", "checked": false, "rank": 1},
+	}, fields["customfield_10040"])
+
+	remaining, fields = extractChecklist("No criteria", text)
+	assert.Equal(t, "No criteria", remaining)
+	assert.Nil(t, fields)
+
+	remaining, fields = extractChecklist(checklistBody, config.ChecklistConfig{})
+	assert.Equal(t, checklistBody, remaining)
+	assert.Nil(t, fields)
+}
+
+func TestChecklistEqual(t *testing.T) {
+	var decoded interface{}
+	require.NoError(t, json.Unmarshal([]byte(`[{"name":"SSO login works","checked":true,"rank":0,"id":7}]`), &decoded))
+
+	want := renderChecklist([]issueform.ChecklistItem{{Text: "SSO login works", Checked: true}}, config.ChecklistFormatItems)
+	assert.True(t, checklistEqual(decoded, want))
+
+	unchecked := renderChecklist([]issueform.ChecklistItem{{Text: "SSO login works"}}, config.ChecklistFormatItems)
+	assert.False(t, checklistEqual(decoded, unchecked))
+
+	assert.True(t, checklistEqual("[x] Done\r\n", "[x] Done"))
+	assert.False(t, checklistEqual(nil, "[x] Done"))
+}
+
+func TestSyncAcceptanceCriteria(t *testing.T) {
+	current := `"[x] SSO login works\n[x] Error message is shown"`
+	var updated map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprintf(w, `{"key":"TEST-1","fields":{"customfield_10040":%s}}`, current)
+		case http.MethodPut:
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&updated))
+			w.WriteHeader(http.StatusNoContent)
+		}
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient, checklist: config.ChecklistConfig{
+		Heading: "Acceptance Criteria", Field: "customfield_10040", Format: config.ChecklistFormatText,
+	}}
+
+	changed, err := client.SyncAcceptanceCriteria("TEST-1", checklistBody)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]interface{}{
+		"fields": map[string]interface{}{"customfield_10040": "[x] SSO login works\n[ ] Error message is shown"},
+	}, updated)
+
+	current = `"[x] SSO login works\n[ ] Error message is shown"`
+	updated = nil
+	changed, err = client.SyncAcceptanceCriteria("TEST-1", checklistBody)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.Nil(t, updated)
+
+	changed, err = (&Client{}).SyncAcceptanceCriteria("TEST-1", checklistBody)
+	assert.NoError(t, err)
+	assert.False(t, changed)
+}
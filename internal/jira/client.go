@@ -4,14 +4,26 @@ package jira
 import (
 	"fmt"
 	"net/http"
+	"net/url"
 	"errors"
+	"log/slog"
 	"strings"
 	"time"
 	"sort"
 	"regexp"
 
 	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/httpdebug"
 	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/maintenance"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/internal/memo"
+	"github.com/danielolaszy/glue/internal/ratelimit"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/reauth"
+	"github.com/danielolaszy/glue/internal/recorder"
 	"github.com/danielolaszy/glue/pkg/models"
 	"github.com/danielolaszy/glue/internal/config"
 )
@@ -26,6 +38,39 @@ type Client struct {
 	issueTypeCache map[string]map[string]string // projectKey -> typeName -> typeID
 	// Cache for fix versions by project key
 	fixVersionCache map[string]*jira.FixVersion // projectKey -> fixVersion
+	// Logger used for this client's output; nil means the default logger
+	logger *slog.Logger
+	// Circuit breaker shared by all requests of this client
+	breaker *breaker
+	// Remembers fetched tickets; may be nil
+	responses *memo.Transport
+	// Issue form sections mapped to JIRA fields
+	formFields []config.FormField
+	// Acceptance criteria checklist mirrored to a JIRA field
+	checklist config.ChecklistConfig
+	// Sections descriptions are composed of; empty means the body alone
+	descriptionSections []string
+	// Default values of fields JIRA requires on creation
+	requiredDefaults map[string]interface{}
+	// Supplies values for required fields without a default; may be nil
+	requiredFields RequiredFieldsFunc
+	// Observes the fetches of tickets by key; may be nil
+	ticketFunc TicketFunc
+	// Resolves the reporters of new tickets; nil leaves them to the service account
+	reporterFunc ReporterFunc
+	// Projects that rejected a reporter, by upper-case project key
+	reporterDenied map[string]bool
+	// Names or IDs of transitions closing tickets; empty uses the defaults
+	closeTransitions []string
+	// Per-board overrides of closeTransitions, by upper-case project key
+	boardCloseTransitions map[string][]string
+	// Windows during which changes to JIRA are refused
+	maintenance maintenance.Windows
+	// Request types of JIRA Service Management projects, by upper-case
+	// project key
+	requestTypes map[string]string
+	// Cache for resolved request types by upper-case project key
+	requestTypeCache map[string]requestType
 }
 
 // NewClient creates a new JIRA client with the provided configuration.
@@ -47,14 +92,65 @@ func NewClient() (*Client, error) {
 		return nil, errors.New("missing required JIRA configuration (JIRA_URL, JIRA_USERNAME, JIRA_TOKEN)")
 	}
 
-	// Create transport for authentication
-	tp := jira.BasicAuthTransport{
-		Username: cfg.Jira.Username,
-		Password: cfg.Jira.Token,
+	timeout := cfg.Jira.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	maxFailures := cfg.Jira.MaxConsecutiveFailures
+	if maxFailures == 0 {
+		maxFailures = DefaultMaxConsecutiveFailures
+	}
+	circuitBreaker := newBreaker(maxFailures)
+
+	base, err := recorder.Wrap(http.DefaultTransport, "jira", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	base, err = faults.Wrap(base, "jira")
+	if err != nil {
+		return nil, err
+	}
+	base = httpdebug.Wrap(base, "jira")
+	// The timeout bounds each attempt, so waiting out throttling doesn't
+	// count against it
+	limited := ratelimit.Wrap(base, "jira")
+	limited.Timeout = timeout
+	responses := memo.New(limited)
+	base = responses
+	if cfg.ReadOnly {
+		logging.Info("jira client is read-only, changes will be refused")
+		base = &readonly.Transport{Base: base}
+	}
+	windows, err := cfg.Maintenance()
+	if err != nil {
+		return nil, err
+	}
+	if len(windows) > 0 {
+		base = &maintenance.Transport{Base: base, Windows: windows}
+	}
+
+	// Create transport for authentication; the credentials are reloaded from
+	// the config when JIRA rejects them, so a rotated token doesn't end a long run
+	credentials := reauth.NewCredentials(cfg.Jira.Username, cfg.Jira.Token, func() (string, string, error) {
+		cfg, err := config.LoadConfig()
+		if err != nil {
+			return "", "", err
+		}
+		return cfg.Jira.Username, cfg.Jira.Token, nil
+	})
+	httpClient := &http.Client{
+		Transport: &reauth.Transport{
+			Base: &basicAuthTransport{
+				base:        &breakerTransport{base: base, breaker: circuitBreaker},
+				credentials: credentials,
+			},
+			Credentials: credentials,
+			Service:     "jira",
+		},
 	}
 
 	// Create JIRA client
-	jiraClient, err := jira.NewClient(tp.Client(), cfg.Jira.BaseURL)
+	jiraClient, err := jira.NewClient(httpClient, cfg.Jira.BaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create JIRA client: %w", err)
 	}
@@ -67,6 +163,17 @@ func NewClient() (*Client, error) {
 		client: jiraClient,
 		issueTypeCache: make(map[string]map[string]string),
 		fixVersionCache: make(map[string]*jira.FixVersion),
+		breaker: circuitBreaker,
+		responses: responses,
+		formFields: cfg.FormFields,
+		checklist: cfg.AcceptanceCriteria,
+		descriptionSections: cfg.Description.Sections,
+		requiredDefaults: cfg.RequiredFields,
+		closeTransitions: cfg.Jira.CloseTransitions,
+		boardCloseTransitions: upperKeys(cfg.Jira.BoardCloseTransitions),
+		maintenance: windows,
+		requestTypes: upperKeys(cfg.Jira.RequestTypes),
+		requestTypeCache: make(map[string]requestType),
 	}
 
 	// Test authentication with retries
@@ -74,22 +181,34 @@ func NewClient() (*Client, error) {
 	var authError error
 	
 	for attempt := 1; attempt <= maxRetries; attempt++ {
-		_, _, err := jiraClient.User.GetSelf()
+		_, resp, err := jiraClient.User.GetSelf()
 		if err == nil {
 			// Authentication successful
 			logging.Info("jira authentication successful")
 			return client, nil
 		}
 		
-		authError = err  // Store the last error
+		authError = responseError(resp, err)  // Store the last error
+
+		// Rejected credentials won't succeed on retry
+		if errors.Is(authError, ErrUnauthorized) {
+			logging.Error("jira rejected the credentials", "error", err)
+			break
+		}
 		
+		var httpResp *http.Response
+		if resp != nil {
+			httpResp = resp.Response
+		}
+		wait := ratelimit.Delay(httpResp, attempt, time.Now())
 		logging.Warn("jira authentication attempt failed, retrying...", 
 			"attempt", attempt, 
+			"wait", wait,
 			"error", err)
 		
 		// Only retry if this is not the last attempt
 		if attempt < maxRetries {
-			time.Sleep(time.Duration(attempt) * time.Second)
+			time.Sleep(wait)
 		} else {
 			// Log final error
 			logging.Error("all jira authentication attempts failed", 
@@ -102,28 +221,196 @@ func NewClient() (*Client, error) {
 	return nil, fmt.Errorf("failed to authenticate with JIRA: %w", authError)
 }
 
+// WithLogger returns a shallow copy of the client that writes its log output to
+// logger, e.g. one carrying a per-issue trace ID. Caches are shared with the original.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	scoped := *c
+	scoped.logger = logger
+	return &scoped
+}
+
+// ForgetResponses forgets the tickets fetched so far, so they are fetched
+// anew, e.g. before a long-running process starts another run.
+func (c *Client) ForgetResponses() {
+	if c.responses != nil {
+		c.responses.Reset()
+	}
+}
+
+// log returns the client's logger, falling back to the default logger.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
+}
+
+// TicketCountOptions narrows the tickets counted by GetTotalTicketsWithOptions.
+type TicketCountOptions struct {
+	// JQL is an additional filter combined with the project, e.g. "status = Done"
+	JQL string
+}
+
 // GetTotalTickets returns the total number of tickets in a JIRA project by executing
 // a JQL search. It returns the count or an error if the query fails.
 func (c *Client) GetTotalTickets(projectKey string) (int, error) {
+	return c.GetTotalTicketsWithOptions(projectKey, TicketCountOptions{})
+}
+
+// GetTotalTicketsWithOptions returns the number of tickets in a JIRA project
+// matching the options. The count is read from the search metadata, so only a
+// single ticket is transferred regardless of the total.
+func (c *Client) GetTotalTicketsWithOptions(projectKey string, opts TicketCountOptions) (int, error) {
 	if c.client == nil {
 		return 0, fmt.Errorf("jira client not initialized")
 	}
 
-	// Search for all issues in the project
-	jql := fmt.Sprintf("project = '%s'", projectKey)
+	jql := ticketCountJQL(projectKey, opts)
 
-	// We're only interested in the count, not the actual issues
+	// We're only interested in the total, not the actual issues. A maxResults of
+	// 0 would be omitted from the request and fall back to the server default.
 	options := &jira.SearchOptions{
-		MaxResults: 0,              // We don't need actual results, just the count
+		MaxResults: 1,
 		Fields:     []string{"id"}, // Minimize data returned
 	}
 
-	result, resp, err := c.client.Issue.Search(jql, options)
+	_, resp, err := c.client.Issue.Search(jql, options)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return 0, fmt.Errorf("failed to search jira issues: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
+	}
+	if resp == nil {
+		return 0, fmt.Errorf("failed to search jira issues: empty response")
+	}
+
+	c.log().Debug("counted jira tickets", "jql", jql, "total", resp.Total)
+	return resp.Total, nil
+}
+
+// ticketCountJQL builds the JQL query counting the tickets of a project.
+func ticketCountJQL(projectKey string, opts TicketCountOptions) string {
+	jql := fmt.Sprintf("project = '%s'", projectKey)
+	if filter := strings.TrimSpace(opts.JQL); filter != "" {
+		jql += fmt.Sprintf(" AND (%s)", filter)
+	}
+	return jql
+}
+
+// SearchKeys returns the keys of all tickets matching a JQL query, or an error
+// if the search fails.
+func (c *Client) SearchKeys(jql string) ([]string, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+	if jql == "" {
+		return nil, apierror.Invalid("jql", "jql query is required")
+	}
+
+	c.log().Debug("searching tickets", "jql", jql)
+
+	var keys []string
+	options := &jira.SearchOptions{
+		MaxResults: 100,
+		Fields:     []string{"key"},
+	}
+	err := c.client.Issue.SearchPages(jql, options, func(issue jira.Issue) error {
+		keys = append(keys, issue.Key)
+		return nil
+	})
 	if err != nil {
-		return 0, fmt.Errorf("failed to search jira issues: %v (status: %d)", err, resp.StatusCode)
+		return nil, fmt.Errorf("failed to search jira issues: %w", err)
 	}
 
-	return len(result), nil
+	return keys, nil
+}
+
+// SearchTickets returns all tickets matching a JQL query, with the same fields
+// as GetTicket, or an error if the search fails.
+func (c *Client) SearchTickets(jql string) ([]models.JiraTicket, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+	if jql == "" {
+		return nil, apierror.Invalid("jql", "jql query is required")
+	}
+
+	c.log().Debug("searching tickets", "jql", jql)
+
+	var tickets []models.JiraTicket
+	options := &jira.SearchOptions{
+		MaxResults: 100,
+		Fields:     strings.Split(ticketFields, ","),
+	}
+	err := c.client.Issue.SearchPages(jql, options, func(issue jira.Issue) error {
+		if issue.Fields != nil {
+			tickets = append(tickets, convertTicket(&issue))
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jira issues: %w", err)
+	}
+
+	return tickets, nil
+}
+
+// UpdateFields sets the given fields of a ticket. Field values are sent as-is,
+// so they must use the JIRA REST representation (e.g. {"value": "Team A"} for
+// a select list). It returns an error if the update fails.
+func (c *Client) UpdateFields(key string, fields map[string]interface{}) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+	if key == "" {
+		return apierror.Invalid("key", "ticket key is required")
+	}
+	if len(fields) == 0 {
+		return apierror.Invalid("fields", "no fields to update")
+	}
+
+	resp, err := c.client.Issue.UpdateIssue(key, map[string]interface{}{
+		"fields": fields,
+	})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return fmt.Errorf("failed to update fields of %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	c.log().Info("updated jira ticket fields", "ticket", key)
+	return nil
+}
+
+// ListProjectKeys returns the keys of all JIRA projects visible to the
+// authenticated user, or an error if the retrieval fails.
+func (c *Client) ListProjectKeys() ([]string, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	c.log().Debug("listing jira projects")
+
+	projects, resp, err := c.client.Project.GetList()
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return nil, fmt.Errorf("failed to list jira projects: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	keys := make([]string, 0, len(*projects))
+	for _, project := range *projects {
+		keys = append(keys, project.Key)
+	}
+
+	c.log().Debug("found jira projects", "count", len(keys))
+	return keys, nil
 }
 
 // IssueTypeExists checks if an issue type exists in the JIRA project. It returns
@@ -133,22 +420,22 @@ func (c *Client) IssueTypeExists(projectKey, typeName string) (bool, string, err
 		return false, "", fmt.Errorf("jira client not initialized")
 	}
 
-	logging.Debug("checking if issue type exists", "project", projectKey, "type", typeName)
+	c.log().Debug("checking if issue type exists", "project", projectKey, "type", typeName)
 
 	// Get the project to see available issue types
 	project, resp, err := c.client.Project.Get(projectKey)
 	if err != nil {
-		logging.Error("failed to get jira project",
+		err = responseError(resp, err)
+		c.log().Error("failed to get jira project",
 			"project", projectKey,
-			"error", err,
-			"status_code", resp.StatusCode)
-		return false, "", fmt.Errorf("failed to get jira project '%s': %v", projectKey, err)
+			"error", err)
+		return false, "", fmt.Errorf("failed to get jira project '%s': %w", projectKey, err)
 	}
 
 	// Check if the issue type already exists
 	for _, issueType := range project.IssueTypes {
 		if strings.EqualFold(issueType.Name, typeName) {
-			logging.Debug("issue type found",
+			c.log().Debug("issue type found",
 				"project", projectKey,
 				"type", typeName,
 				"type_id", issueType.ID)
@@ -156,7 +443,7 @@ func (c *Client) IssueTypeExists(projectKey, typeName string) (bool, string, err
 		}
 	}
 
-	logging.Debug("issue type not found", "project", projectKey, "type", typeName)
+	c.log().Debug("issue type not found", "project", projectKey, "type", typeName)
 	return false, "", nil
 }
 
@@ -165,13 +452,13 @@ func (c *Client) IssueTypeExists(projectKey, typeName string) (bool, string, err
 // It returns the type ID or an error if the type cannot be found.
 func (c *Client) GetIssueTypeID(projectKey, typeName string) (string, error) {
 	typeName = strings.ToLower(typeName)
-	logging.Debug("retrieving issue type id", "project", projectKey, "type", typeName)
+	c.log().Debug("retrieving issue type id", "project", projectKey, "type", typeName)
 
 	// Check if we have cached issue types for this project
 	if projectTypes, exists := c.issueTypeCache[projectKey]; exists {
 		// Check if the requested type exists in the cache
 		if typeID, exists := projectTypes[typeName]; exists {
-			logging.Info("found issue type in cache", "name", typeName, "id", typeID)
+			c.log().Info("found issue type in cache", "name", typeName, "id", typeID)
 			return typeID, nil
 		}
 	} else {
@@ -183,7 +470,7 @@ func (c *Client) GetIssueTypeID(projectKey, typeName string) (string, error) {
 
 		// Now check the cache again
 		if typeID, exists := c.issueTypeCache[projectKey][typeName]; exists {
-			logging.Info("found issue type", "name", typeName, "id", typeID)
+			c.log().Info("found issue type", "name", typeName, "id", typeID)
 			return typeID, nil
 		}
 	}
@@ -192,6 +479,33 @@ func (c *Client) GetIssueTypeID(projectKey, typeName string) (string, error) {
 	return "", fmt.Errorf("issue type '%s' not found in project '%s'", typeName, projectKey)
 }
 
+// LoadIssueTypes fetches the issue types of a JIRA project and caches their
+// IDs by lower-case name, for GetIssueTypeID.
+func (c *Client) LoadIssueTypes(projectKey string) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	c.log().Debug("loading issue types", "project", projectKey)
+
+	project, resp, err := c.client.Project.Get(projectKey)
+	if err != nil {
+		return fmt.Errorf("failed to get jira project '%s': %w", projectKey, responseError(resp, err))
+	}
+
+	types := make(map[string]string, len(project.IssueTypes))
+	for _, issueType := range project.IssueTypes {
+		types[strings.ToLower(issueType.Name)] = issueType.ID
+	}
+	if c.issueTypeCache == nil {
+		c.issueTypeCache = make(map[string]map[string]string)
+	}
+	c.issueTypeCache[projectKey] = types
+
+	c.log().Debug("loaded issue types", "project", projectKey, "count", len(types))
+	return nil
+}
+
 // getCustomField retrieves the custom field ID by its name.
 // It returns the field ID, field type, and any error that occurred.
 func (c *Client) getCustomField(name string) (string, string, error) {
@@ -199,7 +513,7 @@ func (c *Client) getCustomField(name string) (string, string, error) {
 		return "", "", fmt.Errorf("jira client not initialized")
 	}
 
-	logging.Debug("getting custom field ID", "name", name)
+	c.log().Debug("getting custom field ID", "name", name)
 
 	// Get all fields
 	req, err := c.client.NewRequest("GET", "rest/api/2/field", nil)
@@ -222,13 +536,13 @@ func (c *Client) getCustomField(name string) (string, string, error) {
 		if resp != nil {
 			statusCode = resp.StatusCode
 		}
-		return "", "", fmt.Errorf("failed to get fields: %v (status: %d)", err, statusCode)
+		return "", "", fmt.Errorf("failed to get fields: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
 	}
 
 	// Find the field with matching name
 	for _, field := range fields {
 		if field.Name == name {
-			logging.Debug("found custom field",
+			c.log().Debug("found custom field",
 				"name", name,
 				"id", field.ID,
 				"type", field.Schema.Type,
@@ -247,53 +561,71 @@ func (c *Client) CreateTicketWithTypeID(projectKey string, issue models.GitHubIs
        return "", fmt.Errorf("jira client not initialized")
     }
 
+    // Service management projects get requests of their request type
+    if c.UsesRequestType(projectKey) {
+       return c.createRequest(projectKey, issue)
+    }
+
     // Get the default fix version for the project
     fixVersion, err := c.GetDefaultFixVersion(projectKey)
     if err != nil {
-       logging.Error("failed to get default fix version", "error", err)
+       c.log().Error("failed to get default fix version", "error", err)
        // Continue without fix version
     }
 
-    logging.Info("creating jira ticket",
+    c.log().Info("creating jira ticket",
        "project", projectKey,
        "title", issue.Title,
        "type_id", issueTypeID)
 
+    body, formValues := c.extractFields(issue.Description)
+    description := c.composeDescription(issue, body)
+
     issueFields := &jira.IssueFields{
        Project: jira.Project{
           Key: projectKey,
        },
        Summary:     issue.Title,
-       Description: issue.Description,
+       Description: description,
        Type: jira.IssueType{
           ID: issueTypeID, // Use issue type ID
        },
+       Labels: []string{marker.Jira.Name},
     }
 
     // Add fix version if available
     if fixVersion != nil {
        issueFields.FixVersions = []*jira.FixVersion{fixVersion}
-       logging.Info("adding fix version to ticket",
+       c.log().Info("adding fix version to ticket",
           "version_name", fixVersion.Name,
           "version_id", fixVersion.ID)
     }
 
+    // Set the fields mapped from issue form sections
+    if len(formValues) > 0 {
+       issueFields.Unknowns = make(map[string]interface{})
+       for id, value := range formValues {
+          issueFields.Unknowns[id] = value
+       }
+       c.log().Debug("added fields from issue form", "count", len(formValues))
+    }
+
     // Check if this is a feature type and add required custom fields
     featureTypeID, err := c.GetIssueTypeID(projectKey, "Feature")
     if err == nil && featureTypeID == issueTypeID {
-       logging.Debug("adding custom fields for feature type")
+       c.log().Debug("adding custom fields for feature type")
 
        // Get Feature Name field ID
        featureNameFieldID, featureNameType, err := c.getCustomField("Feature Name")
        if err != nil {
-          logging.Error("failed to get Feature Name field ID", "error", err)
+          c.log().Error("failed to get Feature Name field ID", "error", err)
           return "", fmt.Errorf("failed to get Feature Name field ID: %v", err)
        }
 
        // Get Primary Feature Work Type field ID
        workTypeFieldID, workTypeFieldType, err := c.getCustomField("Primary Feature Work Type ")
        if err != nil {
-          logging.Error("failed to get Primary Feature Work Type field ID", "error", err)
+          c.log().Error("failed to get Primary Feature Work Type field ID", "error", err)
           return "", fmt.Errorf("failed to get Primary Feature Work Type field ID: %v", err)
        }
 
@@ -319,7 +651,7 @@ func (c *Client) CreateTicketWithTypeID(projectKey string, issue models.GitHubIs
           issueFields.Unknowns[id] = value
        }
 
-       logging.Debug("added custom fields",
+       c.log().Debug("added custom fields",
           "feature_name_id", featureNameFieldID,
           "feature_name_type", featureNameType,
           "work_type_id", workTypeFieldID,
@@ -331,42 +663,69 @@ func (c *Client) CreateTicketWithTypeID(projectKey string, issue models.GitHubIs
        Fields: issueFields,
     }
 
-    logging.Debug("sending request to jira api")
+    c.log().Debug("sending request to jira api")
 
-    newIssue, resp, err := c.client.Issue.Create(jiraIssue)
-    if err != nil {
-       statusCode := 0
-       if resp != nil {
-          statusCode = resp.StatusCode
-
-          // Try to get more details about the error
-          body, readErr := io.ReadAll(resp.Body)
-          if readErr == nil {
-             logging.Error("failed to create jira ticket",
-                "error", err,
-                "status_code", statusCode,
-                "response", string(body))
-             return "", fmt.Errorf("failed to create jira ticket: %v (status: %d, response: %s)",
-                err, statusCode, string(body))
+    newIssue, err := c.createAttributedIssue(jiraIssue, issue, description)
+
+    // Retry once with values for required fields the mappings didn't cover
+    var required *RequiredFieldsError
+    if errors.As(err, &required) {
+       if values := c.requiredFieldValues(projectKey, issue, required.Fields); len(values) > 0 {
+          if issueFields.Unknowns == nil {
+             issueFields.Unknowns = make(map[string]interface{})
           }
+          for id, value := range values {
+             issueFields.Unknowns[id] = value
+          }
+          c.log().Info("retrying ticket creation with required field values", "fields", len(values))
+          newIssue, err = c.createAttributedIssue(jiraIssue, issue, description)
        }
-       logging.Error("failed to create jira ticket", "error", err, "status_code", statusCode)
-       return "", fmt.Errorf("failed to create jira ticket: %v (status: %d)", err, statusCode)
+    }
+    if err != nil {
+       return "", err
     }
 
     if newIssue == nil {
-       logging.Error("jira api returned nil issue")
+       c.log().Error("jira api returned nil issue")
        return "", fmt.Errorf("jira api returned nil issue")
     }
 
-    logging.Info("created jira ticket", "key", newIssue.Key)
+    c.log().Info("created jira ticket", "key", newIssue.Key)
     return newIssue.Key, nil
 }
 
+// ParentChildLinkType is the JIRA link type linking a feature's ticket to
+// the tickets of its stories. The feature is the inward issue of the link,
+// so its children are the outward issues of its links.
+const ParentChildLinkType = "Relates"
+
+// CreateParentChildLink links the ticket childKey to its parent parentKey
+// with a ParentChildLinkType link.
+func (c *Client) CreateParentChildLink(parentKey, childKey string) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	c.log().Info("creating parent-child relationship in JIRA",
+		"parent", parentKey,
+		"child", childKey)
+
+	link := &jira.IssueLink{
+		Type:         jira.IssueLinkType{Name: ParentChildLinkType},
+		InwardIssue:  &jira.Issue{Key: parentKey},
+		OutwardIssue: &jira.Issue{Key: childKey},
+	}
+	resp, err := c.client.Issue.AddLink(link)
+	if err != nil {
+		return fmt.Errorf("failed to link %s to %s: %w", childKey, parentKey, responseError(resp, err))
+	}
+	return nil
+}
+
 // CheckParentChildLinkExists checks if a parent-child link already exists in JIRA.
 // It returns true if the link exists, false if it doesn't, and an error if the check fails.
 func (c *Client) CheckParentChildLinkExists(parentKey, childKey string) (bool, error) {
-	logging.Debug("checking if parent-child link exists in JIRA",
+	c.log().Debug("checking if parent-child link exists in JIRA",
 		"parent", parentKey,
 		"child", childKey)
 
@@ -382,7 +741,7 @@ func (c *Client) CheckParentChildLinkExists(parentKey, childKey string) (bool, e
 		if resp != nil {
 			statusCode = resp.StatusCode
 		}
-		return false, fmt.Errorf("failed to get child issue: %v (status: %d)", err, statusCode)
+		return false, fmt.Errorf("failed to get child issue: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
 	}
 
 	// Check if there are any links
@@ -410,16 +769,16 @@ func (c *Client) CheckParentChildLinkExists(parentKey, childKey string) (bool, e
 // It checks both the parent and child issues for links connecting them,
 // and returns the link ID if found or an error if the retrieval fails.
 func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
-	logging.Debug("finding issue link ID in JIRA",
+	c.log().Debug("finding issue link ID in JIRA",
 		"parent", parentKey,
 		"child", childKey)
 
 	// Get both issues to check links from both sides
-	parentIssue, _, err := c.client.Issue.Get(parentKey, &jira.GetQueryOptions{
+	parentIssue, resp, err := c.client.Issue.Get(parentKey, &jira.GetQueryOptions{
 		Expand: "issuelinks",
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get parent issue: %v", err)
+		return "", fmt.Errorf("failed to get parent issue: %w", responseError(resp, err))
 	}
 
 	// Log all links on parent issue
@@ -433,7 +792,7 @@ func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
 			inwardKey = link.InwardIssue.Key
 		}
 		
-		logging.Debug("examining parent link",
+		c.log().Debug("examining parent link",
 			"link_id", link.ID,
 			"type", link.Type.Name,
 			"outward_issue", link.OutwardIssue != nil,
@@ -443,11 +802,11 @@ func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
 	}
 
 	// Get child issue as well
-	childIssue, _, err := c.client.Issue.Get(childKey, &jira.GetQueryOptions{
+	childIssue, resp, err := c.client.Issue.Get(childKey, &jira.GetQueryOptions{
 		Expand: "issuelinks",
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get child issue: %v", err)
+		return "", fmt.Errorf("failed to get child issue: %w", responseError(resp, err))
 	}
 
 	// Log all links on child issue
@@ -461,7 +820,7 @@ func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
 			inwardKey = link.InwardIssue.Key
 		}
 
-		logging.Debug("examining child link",
+		c.log().Debug("examining child link",
 			"link_id", link.ID,
 			"type", link.Type.Name,
 			"outward_issue", link.OutwardIssue != nil,
@@ -469,11 +828,11 @@ func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
 			"outward_key", outwardKey,
 			"inward_key", inwardKey)
 
-		// For "Relates" type links, check both directions
-		if link.Type.Name == "Relates" {
+		// For parent-child links, check both directions
+		if link.Type.Name == ParentChildLinkType {
 			if (link.OutwardIssue != nil && link.OutwardIssue.Key == parentKey) ||
 			   (link.InwardIssue != nil && link.InwardIssue.Key == parentKey) {
-				logging.Debug("found matching link to remove",
+				c.log().Debug("found matching link to remove",
 					"link_id", link.ID,
 					"parent", parentKey,
 					"child", childKey)
@@ -482,7 +841,7 @@ func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
 		}
 	}
 
-	logging.Debug("no matching link found",
+	c.log().Debug("no matching link found",
 		"parent", parentKey,
 		"child", childKey)
 	return "", nil
@@ -490,7 +849,7 @@ func (c *Client) GetIssueLinkID(parentKey, childKey string) (string, error) {
 
 // DeleteIssueLink removes a link between two JIRA issues.
 func (c *Client) DeleteIssueLink(parentKey, childKey string) error {
-	logging.Info("removing parent-child relationship in JIRA",
+	c.log().Info("removing parent-child relationship in JIRA",
 		"parent", parentKey,
 		"child", childKey)
 
@@ -506,7 +865,7 @@ func (c *Client) DeleteIssueLink(parentKey, childKey string) error {
 	}
 
 	if linkID == "" {
-		logging.Debug("no link found to delete",
+		c.log().Debug("no link found to delete",
 			"parent", parentKey,
 			"child", childKey)
 		return nil
@@ -526,14 +885,14 @@ func (c *Client) DeleteIssueLink(parentKey, childKey string) error {
 		if resp != nil {
 			statusCode = resp.StatusCode
 		}
-		logging.Error("failed to delete issue link",
+		c.log().Error("failed to delete issue link",
 			"error", err,
 			"status_code", statusCode,
 			"link_id", linkID)
-		return fmt.Errorf("failed to delete issue link: %v (status: %d)", err, statusCode)
+		return fmt.Errorf("failed to delete issue link: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
 	}
 
-	logging.Info("successfully removed issue link",
+	c.log().Info("successfully removed issue link",
 		"parent", parentKey,
 		"child", childKey,
 		"link_id", linkID)
@@ -544,7 +903,7 @@ func (c *Client) DeleteIssueLink(parentKey, childKey string) error {
 // GetLinkedIssues retrieves all issue keys that are linked to the specified parent issue.
 // It returns a slice of child issue keys or an error if retrieval fails.
 func (c *Client) GetLinkedIssues(parentKey string) ([]string, error) {
-	logging.Debug("retrieving linked issues in JIRA",
+	c.log().Debug("retrieving linked issues in JIRA",
 		"parent", parentKey)
 
 	// Check if the client is initialized
@@ -559,7 +918,7 @@ func (c *Client) GetLinkedIssues(parentKey string) ([]string, error) {
 		if resp != nil {
 			statusCode = resp.StatusCode
 		}
-		return nil, fmt.Errorf("failed to get parent issue: %v (status: %d)", err, statusCode)
+		return nil, fmt.Errorf("failed to get parent issue: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
 	}
 
 	// Check if there are any links
@@ -587,7 +946,7 @@ func (c *Client) GetLinkedIssues(parentKey string) ([]string, error) {
 // CloseTicket transitions a JIRA ticket to the "Done" status.
 // It returns an error if the operation fails.
 func (c *Client) CloseTicket(ticketKey string) error {
-	logging.Info("closing jira ticket", "ticket", ticketKey)
+	c.log().Info("closing jira ticket", "ticket", ticketKey)
 
 	// Check if the client is initialized
 	if c.client == nil {
@@ -595,42 +954,29 @@ func (c *Client) CloseTicket(ticketKey string) error {
 	}
 
 	// Get available transitions for the ticket
-	transitions, resp, err := c.client.Issue.GetTransitions(ticketKey)
+	transitions, err := c.GetTransitions(ticketKey)
 	if err != nil {
-		statusCode := 0
-		if resp != nil {
-			statusCode = resp.StatusCode
-		}
-		return fmt.Errorf("failed to get transitions for ticket %s: %v (status: %d)",
-			ticketKey, err, statusCode)
+		return err
 	}
 
-	// Look for a "Done" or "Closed" transition
-	var transitionID string
-	for _, t := range transitions {
-		name := strings.ToLower(t.Name)
-		if name == "done" || name == "close" || name == "closed" || name == "resolve" || name == "resolved" {
-			transitionID = t.ID
-			break
-		}
-	}
-
-	if transitionID == "" {
-		return fmt.Errorf("no 'done' or 'close' transition found for ticket %s", ticketKey)
+	transition, ok := c.CloseTransition(ticketKey, transitions)
+	if !ok {
+		return fmt.Errorf("none of the close transitions %s is available for ticket %s; list them with 'glue jira transitions %s'",
+			strings.Join(c.closeTransitionsFor(ticketKey), ", "), ticketKey, ticketKey)
 	}
 
 	// Execute the transition
-	resp, err = c.client.Issue.DoTransition(ticketKey, transitionID)
+	resp, err := c.client.Issue.DoTransition(ticketKey, transition.ID)
 	if err != nil {
 		statusCode := 0
 		if resp != nil {
 			statusCode = resp.StatusCode
 		}
-		return fmt.Errorf("failed to close ticket %s: %v (status: %d)",
-			ticketKey, err, statusCode)
+		return fmt.Errorf("failed to close ticket %s: %w (status: %d)",
+			ticketKey, apierror.Wrap(err, statusCode), statusCode)
 	}
 
-	logging.Info("successfully closed jira ticket", "ticket", ticketKey)
+	c.log().Info("successfully closed jira ticket", "ticket", ticketKey)
 	return nil
 }
 
@@ -641,7 +987,7 @@ func (c *Client) GetProjectVersions(projectKey string) ([]jira.Version, error) {
 		return nil, fmt.Errorf("jira client not initialized")
 	}
 
-	logging.Debug("retrieving project versions", "project", projectKey)
+	c.log().Debug("retrieving project versions", "project", projectKey)
 
 	// Get project to access versions
 	project, resp, err := c.client.Project.Get(projectKey)
@@ -650,11 +996,11 @@ func (c *Client) GetProjectVersions(projectKey string) ([]jira.Version, error) {
 		if resp != nil {
 			statusCode = resp.StatusCode
 		}
-		logging.Error("failed to get project versions",
+		c.log().Error("failed to get project versions",
 			"project", projectKey,
 			"error", err,
 			"status_code", statusCode)
-		return nil, fmt.Errorf("failed to get project versions: %v (status: %d)", err, statusCode)
+		return nil, fmt.Errorf("failed to get project versions: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
 	}
 
 	return project.Versions, nil
@@ -666,30 +1012,30 @@ func (c *Client) GetProjectVersions(projectKey string) ([]jira.Version, error) {
 // 2. Not archived
 // 3. Has the closest PI number to current (e.g., PI 25.1 instead of PI 25.5)
 func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, error) {
-	logging.Debug("getting default fix version", "project", projectKey)
+	c.log().Debug("getting default fix version", "project", projectKey)
 
 	// Check if we already have this project's fix version in cache
 	if fixVersion, exists := c.fixVersionCache[projectKey]; exists {
 		if fixVersion == nil {
-			logging.Info("no suitable fix version found in cache for project", "project", projectKey)
+			c.log().Info("no suitable fix version found in cache for project", "project", projectKey)
 		} else {
-			logging.Info("found fix version in cache", "project", projectKey, "version", fixVersion.Name, "id", fixVersion.ID)
+			c.log().Info("found fix version in cache", "project", projectKey, "version", fixVersion.Name, "id", fixVersion.ID)
 		}
 		return fixVersion, nil
 	}
 
 	versions, err := c.GetProjectVersions(projectKey)
 	if err != nil {
-		logging.Error("failed to get project versions", "error", err)
+		c.log().Error("failed to get project versions", "error", err)
 		return nil, err
 	}
 
-	logging.Debug("found project versions", "count", len(versions))
+	c.log().Debug("found project versions", "count", len(versions))
 
 	// Get current year's last two digits to use as major version
 	currentYear := time.Now().Year()
 	targetMajor := currentYear % 100
-	logging.Debug("looking for current PI version", "year", currentYear, "target_major", targetMajor)
+	c.log().Debug("looking for current PI version", "year", currentYear, "target_major", targetMajor)
 
 	type piVersion struct {
 		major    int
@@ -708,7 +1054,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		version := &versions[i]
 		
 		// Log all versions for visibility
-		logging.Debug("examining version", 
+		c.log().Debug("examining version", 
 			"name", version.Name, 
 			"id", version.ID,
 			"released", version.Released != nil && *version.Released,
@@ -720,7 +1066,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		
 		// Skip archived versions
 		if archived {
-			logging.Debug("skipping archived version", "name", version.Name, "archived", archived)
+			c.log().Debug("skipping archived version", "name", version.Name, "archived", archived)
 			continue
 		}
 		
@@ -728,7 +1074,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		var major, minor int
 		_, err := fmt.Sscanf(version.Name, "PI %d.%d", &major, &minor)
 		if err != nil {
-			logging.Debug("skipping non-PI version", "name", version.Name, "error", err)
+			c.log().Debug("skipping non-PI version", "name", version.Name, "error", err)
 			continue
 		}
 
@@ -742,14 +1088,14 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		
 		// Categorize by whether it matches the current year
 		if major == targetMajor {
-			logging.Debug("found current year PI version", 
+			c.log().Debug("found current year PI version", 
 				"name", version.Name, 
 				"major", major, 
 				"minor", minor, 
 				"released", released)
 			currentYearVersions = append(currentYearVersions, pv)
 		} else {
-			logging.Debug("found other year PI version", 
+			c.log().Debug("found other year PI version", 
 				"name", version.Name, 
 				"major", major, 
 				"minor", minor, 
@@ -758,7 +1104,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		}
 	}
 	
-	logging.Debug("version summary",
+	c.log().Debug("version summary",
 		"current_year_versions_count", len(currentYearVersions),
 		"other_year_versions_count", len(otherPIVersions))
 	
@@ -769,7 +1115,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 	if len(currentYearVersions) > 0 {
 		// Log all current year versions for clarity
 		for i, v := range currentYearVersions {
-			logging.Debug("current year PI version", 
+			c.log().Debug("current year PI version", 
 				"index", i,
 				"name", v.version.Name,
 				"major", v.major,
@@ -788,9 +1134,9 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		})
 		
 		// Log the sorted versions
-		logging.Debug("sorted current year PI versions (unreleased first, then by lowest minor)")
+		c.log().Debug("sorted current year PI versions (unreleased first, then by lowest minor)")
 		for i, v := range currentYearVersions {
-			logging.Debug("sorted current year PI version", 
+			c.log().Debug("sorted current year PI version", 
 				"index", i,
 				"name", v.version.Name,
 				"major", v.major,
@@ -799,7 +1145,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		}
 		
 		selectedPI = currentYearVersions[0]
-		logging.Debug("selected current year PI version", 
+		c.log().Debug("selected current year PI version", 
 			"name", selectedPI.version.Name,
 			"major", selectedPI.major,
 			"minor", selectedPI.minor,
@@ -808,7 +1154,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		// If no current year PI found, use the most recent from other years
 		// Log all other year versions for clarity
 		for i, v := range otherPIVersions {
-			logging.Debug("other year PI version", 
+			c.log().Debug("other year PI version", 
 				"index", i,
 				"name", v.version.Name,
 				"major", v.major,
@@ -831,9 +1177,9 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		})
 		
 		// Log the sorted versions
-		logging.Debug("sorted other year PI versions (highest major first, unreleased first, then by lowest minor)")
+		c.log().Debug("sorted other year PI versions (highest major first, unreleased first, then by lowest minor)")
 		for i, v := range otherPIVersions {
-			logging.Debug("sorted other year PI version", 
+			c.log().Debug("sorted other year PI version", 
 				"index", i,
 				"name", v.version.Name,
 				"major", v.major,
@@ -842,13 +1188,13 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		}
 		
 		selectedPI = otherPIVersions[0]
-		logging.Debug("selected other year PI version as fallback", 
+		c.log().Debug("selected other year PI version as fallback", 
 			"name", selectedPI.version.Name,
 			"major", selectedPI.major,
 			"minor", selectedPI.minor,
 			"released", selectedPI.released)
 	} else {
-		logging.Debug("no PI versions found at all")
+		c.log().Debug("no PI versions found at all")
 	}
 
 	// Convert Version to FixVersion
@@ -864,7 +1210,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		releasedPtr := &released
 		archivedPtr := &archived
 
-		logging.Info("selected fix version",
+		c.log().Info("selected fix version",
 			"name", selectedPI.version.Name,
 			"id", selectedPI.version.ID,
 			"major", selectedPI.major,
@@ -884,7 +1230,7 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 		return fixVersion, nil
 	}
 
-	logging.Info("no suitable fix version found")
+	c.log().Info("no suitable fix version found")
 	// Cache the nil result to avoid repeated lookups
 	c.fixVersionCache[projectKey] = nil
 	return nil, nil
@@ -894,9 +1240,9 @@ func (c *Client) GetDefaultFixVersion(projectKey string) (*jira.FixVersion, erro
 // It takes a parentID string representing the JIRA issue key (e.g., "PROJECT-123") and returns
 // a slice of child issue keys or an error if the retrieval fails.
 func (c *Client) GetChildIssues(parentID string) ([]string, error) {
-	issue, _, err := c.client.Issue.Get(parentID, nil)
+	issue, resp, err := c.client.Issue.Get(parentID, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %v", err)
+		return nil, fmt.Errorf("failed to get issue: %w", responseError(resp, err))
 	}
 
 	var children []string
@@ -908,71 +1254,238 @@ func (c *Client) GetChildIssues(parentID string) ([]string, error) {
 	return children, nil
 }
 
-// GetIssueLinks retrieves all issues linked to the specified JIRA issue, regardless of link type.
-// It takes an issueID string representing the JIRA issue key (e.g., "PROJECT-123") and returns
-// a map where keys are the linked issue keys and values are always true, or an error if the 
-// retrieval fails. The map acts as a set of unique linked issue keys.
+// GetIssueLinks retrieves the children of the specified JIRA issue: the outward
+// issues of its parent-child links. Links of other types, like "Blocks", and
+// the links to its own parents are left out. It takes an issueID string
+// representing the JIRA issue key (e.g., "PROJECT-123") and returns a map
+// where keys are the child issue keys and values are always true, or an error
+// if the retrieval fails. The map acts as a set of unique child issue keys.
 func (c *Client) GetIssueLinks(issueID string) (map[string]bool, error) {
-	logging.Debug("getting issue links", "issue", issueID)
-	
-	issue, _, err := c.client.Issue.Get(issueID, &jira.GetQueryOptions{
+	c.log().Debug("getting issue links", "issue", issueID)
+
+	issue, resp, err := c.client.Issue.Get(issueID, &jira.GetQueryOptions{
 		Expand: "issuelinks",
 	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get issue: %v", err)
+		return nil, fmt.Errorf("failed to get issue: %w", responseError(resp, err))
 	}
 
 	children := make(map[string]bool)
 	for _, link := range issue.Fields.IssueLinks {
-		// Log the link type for debugging
-		logging.Debug("found link",
+		c.log().Debug("found link",
 			"issue", issueID,
 			"type", link.Type.Name,
 			"outward", link.OutwardIssue != nil,
 			"inward", link.InwardIssue != nil)
 
-		// Check both inward and outward links
-		if link.OutwardIssue != nil {
+		if link.Type.Name == ParentChildLinkType && link.OutwardIssue != nil {
 			children[link.OutwardIssue.Key] = true
 		}
-		if link.InwardIssue != nil {
-			children[link.InwardIssue.Key] = true
-		}
 	}
 
-	logging.Debug("found linked issues",
+	c.log().Debug("found child issues",
 		"issue", issueID,
-		"links", children)
+		"children", children)
 
 	return children, nil
 }
 
+// FindUsers returns the active JIRA users matching a query on their email
+// address, name or username.
+func (c *Client) FindUsers(query string) ([]models.JiraUser, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+	if strings.TrimSpace(query) == "" {
+		return nil, apierror.Invalid("query", "user search query is required")
+	}
+
+	users, resp, err := c.client.User.Find(url.QueryEscape(query))
+	if err != nil {
+		return nil, fmt.Errorf("failed to search jira users: %w", responseError(resp, err))
+	}
+
+	var result []models.JiraUser
+	for _, user := range users {
+		if !user.Active {
+			continue
+		}
+		result = append(result, models.JiraUser{
+			AccountID:   user.AccountID,
+			Name:        user.Name,
+			Email:       user.EmailAddress,
+			DisplayName: user.DisplayName,
+		})
+	}
+	return result, nil
+}
+
+// AssignTicket assigns a ticket to a JIRA user, identified by account ID on
+// JIRA Cloud or by username on JIRA Server.
+func (c *Client) AssignTicket(key string, user models.JiraUser) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	resp, err := c.client.Issue.UpdateAssignee(key, &jira.User{AccountID: user.AccountID, Name: user.Name})
+	if err != nil {
+		return fmt.Errorf("failed to assign %s: %w", key, responseError(resp, err))
+	}
+
+	c.log().Info("assigned jira ticket", "ticket", key, "account_id", user.AccountID, "name", user.Name)
+	return nil
+}
+
+// GetBlockedTickets returns the keys of the tickets a ticket blocks, i.e. the
+// targets of its outward "Blocks" links.
+func (c *Client) GetBlockedTickets(key string) ([]string, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	issue, resp, err := c.client.Issue.Get(key, &jira.GetQueryOptions{Fields: "issuelinks"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get links of %s: %w", key, responseError(resp, err))
+	}
+	if issue == nil || issue.Fields == nil {
+		return nil, nil
+	}
+
+	var blocked []string
+	for _, link := range issue.Fields.IssueLinks {
+		if link == nil || link.OutwardIssue == nil || !strings.EqualFold(link.Type.Name, "Blocks") {
+			continue
+		}
+		blocked = append(blocked, link.OutwardIssue.Key)
+	}
+	return blocked, nil
+}
+
+// StatusCategoryDone is the key of the status category of finished tickets.
+// Workflows name their final statuses freely, e.g. "Done", "Closed" or
+// "Released", but they are all in this category.
+const StatusCategoryDone = "done"
+
 // GetTicketStatus retrieves the current status of a JIRA ticket.
 // It takes an issueID string representing the JIRA issue key (e.g., "PROJECT-123") and returns
 // the status name as a string (e.g., "In Progress", "Done") or an error if the retrieval fails.
 func (c *Client) GetTicketStatus(issueID string) (string, error) {
+	status, err := c.ticketStatus(issueID)
+	if err != nil {
+		return "", err
+	}
+	return status.Name, nil
+}
+
+// IsTicketDone reports whether a JIRA ticket is in a status of the done
+// category, whatever the status is called.
+func (c *Client) IsTicketDone(issueID string) (bool, error) {
+	status, err := c.ticketStatus(issueID)
+	if err != nil {
+		return false, err
+	}
+	return status.StatusCategory.Key == StatusCategoryDone, nil
+}
+
+// ticketStatus retrieves the current status of a JIRA ticket with its category.
+func (c *Client) ticketStatus(issueID string) (*jira.Status, error) {
 	if c.client == nil {
-		return "", fmt.Errorf("jira client not initialized")
+		return nil, fmt.Errorf("jira client not initialized")
 	}
 
-	logging.Debug("getting ticket status", "ticket", issueID)
+	c.log().Debug("getting ticket status", "ticket", issueID)
 
-	issue, _, err := c.client.Issue.Get(issueID, &jira.GetQueryOptions{
+	issue, resp, err := c.client.Issue.Get(issueID, &jira.GetQueryOptions{
 		Fields: "status",
 	})
 	if err != nil {
-		return "", fmt.Errorf("failed to get issue status: %v", err)
+		err = responseError(resp, err)
+		c.observeTicket(issueID, nil, err)
+		return nil, fmt.Errorf("failed to get issue status: %w", err)
 	}
+	c.observeTicket(issueID, issue, nil)
 
 	if issue == nil || issue.Fields == nil || issue.Fields.Status == nil {
-		return "", fmt.Errorf("invalid issue response")
+		return nil, fmt.Errorf("invalid issue response")
 	}
 
-	logging.Debug("got ticket status",
+	c.log().Debug("got ticket status",
 		"ticket", issueID,
-		"status", issue.Fields.Status.Name)
+		"status", issue.Fields.Status.Name,
+		"category", issue.Fields.Status.StatusCategory.Key)
+
+	return issue.Fields.Status, nil
+}
+
+// GetTicket retrieves a JIRA ticket with its summary, description, type, status,
+// labels, components, assignee, resolution date and last update time.
+// It takes a ticket key (e.g., "PROJECT-123") and returns the ticket or an error
+// if the retrieval fails.
+func (c *Client) GetTicket(key string) (models.JiraTicket, error) {
+	if c.client == nil {
+		return models.JiraTicket{}, fmt.Errorf("jira client not initialized")
+	}
+
+	c.log().Debug("getting ticket", "ticket", key)
+
+	issue, resp, err := c.client.Issue.Get(key, &jira.GetQueryOptions{
+		Fields: ticketFields,
+	})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		err = apierror.Wrap(err, statusCode)
+		c.observeTicket(key, nil, err)
+		return models.JiraTicket{}, fmt.Errorf("failed to get ticket %s: %w (status: %d)", key, err, statusCode)
+	}
+	c.observeTicket(key, issue, nil)
+
+	if issue == nil || issue.Fields == nil {
+		return models.JiraTicket{}, fmt.Errorf("invalid issue response")
+	}
+
+	return convertTicket(issue), nil
+}
+
+// ticketFields are the fields requested to build a models.JiraTicket.
+const ticketFields = "summary,description,issuetype,status,labels,components,assignee,resolutiondate,updated"
+
+// convertTicket converts a JIRA API issue with its fields to a models.JiraTicket.
+func convertTicket(issue *jira.Issue) models.JiraTicket {
+	ticket := models.JiraTicket{
+		ID:          issue.ID,
+		Key:         issue.Key,
+		Title:       issue.Fields.Summary,
+		Description: issue.Fields.Description,
+		Type:        issue.Fields.Type.Name,
+	}
+	if issue.Fields.Status != nil {
+		ticket.Status = issue.Fields.Status.Name
+		ticket.StatusCategory = issue.Fields.Status.StatusCategory.Key
+	}
+	ticket.Labels = issue.Fields.Labels
+	ticket.CreatedByGlue = marker.Jira.Marked(ticket.Title, ticket.Labels)
+	for _, component := range issue.Fields.Components {
+		if component != nil {
+			ticket.Components = append(ticket.Components, component.Name)
+		}
+	}
+	if assignee := issue.Fields.Assignee; assignee != nil {
+		ticket.Assignee = &models.JiraUser{
+			AccountID:   assignee.AccountID,
+			Name:        assignee.Name,
+			Email:       assignee.EmailAddress,
+			DisplayName: assignee.DisplayName,
+		}
+	}
+	if resolved := time.Time(issue.Fields.Resolutiondate); !resolved.IsZero() {
+		ticket.ResolvedAt = &resolved
+	}
+	ticket.UpdatedAt = time.Time(issue.Fields.Updated)
 
-	return issue.Fields.Status.Name, nil
+	return ticket
 }
 
 // cleanMarkdownHeadings processes a GitHub markdown string to clean up heading syntax
@@ -2,6 +2,8 @@ package jira
 
 import (
 	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"sort"
 	"strings"
@@ -605,6 +607,32 @@ func TestGetTotalTickets(t *testing.T) {
 	}
 }
 
+func TestGetTotalTicketsReadsSearchTotal(t *testing.T) {
+	var gotJQL, gotMaxResults string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotJQL = r.URL.Query().Get("jql")
+		gotMaxResults = r.URL.Query().Get("maxResults")
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"startAt":0,"maxResults":1,"total":137,"issues":[{"id":"10001","key":"TEST-1"}]}`)
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	count, err := client.GetTotalTickets("TEST")
+	require.NoError(t, err)
+	assert.Equal(t, 137, count)
+	assert.Equal(t, "project = 'TEST'", gotJQL)
+	assert.Equal(t, "1", gotMaxResults)
+
+	count, err = client.GetTotalTicketsWithOptions("TEST", TicketCountOptions{JQL: "status = Done"})
+	require.NoError(t, err)
+	assert.Equal(t, 137, count)
+	assert.Equal(t, "project = 'TEST' AND (status = Done)", gotJQL)
+}
+
 func TestIssueTypeExists(t *testing.T) {
 	tests := []struct {
 		name       string
@@ -854,3 +882,71 @@ func createTestVersions() []jira.Version {
 		},
 	}
 }
+
+func TestSearchKeysValidation(t *testing.T) {
+	_, err := (&Client{}).SearchKeys("project = TEST")
+	assert.Error(t, err)
+
+	_, err = (&Client{client: &jira.Client{}}).SearchKeys("")
+	assert.EqualError(t, err, "jql query is required")
+}
+
+func TestUpdateFieldsValidation(t *testing.T) {
+	fields := map[string]interface{}{"customfield_10010": "Team A"}
+
+	assert.Error(t, (&Client{}).UpdateFields("TEST-1", fields))
+
+	client := &Client{client: &jira.Client{}}
+	assert.EqualError(t, client.UpdateFields("", fields), "ticket key is required")
+	assert.EqualError(t, client.UpdateFields("TEST-1", nil), "no fields to update")
+}
+
+func TestIsTicketDone(t *testing.T) {
+	fake := newFakeJira()
+	fake.tickets["PROJ-1"] = &fakeTicket{summary: "Login", status: "In Progress"}
+	fake.tickets["PROJ-2"] = &fakeTicket{summary: "Logout", status: "Done"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	done, err := client.IsTicketDone("PROJ-1")
+	require.NoError(t, err)
+	assert.False(t, done)
+
+	done, err = client.IsTicketDone("PROJ-2")
+	require.NoError(t, err)
+	assert.True(t, done)
+
+	ticket, err := client.GetTicket("PROJ-2")
+	require.NoError(t, err)
+	assert.Equal(t, StatusCategoryDone, ticket.StatusCategory)
+}
+
+func TestGetIssueLinksOnlyChildren(t *testing.T) {
+	fake := newFakeJira()
+	for _, key := range []string{"PROJ-1", "PROJ-2", "PROJ-3", "PROJ-4", "PROJ-5"} {
+		fake.tickets[key] = &fakeTicket{summary: key, status: "To Do"}
+	}
+	link := func(typ, inward, outward string) jira.IssueLink {
+		return jira.IssueLink{Type: jira.IssueLinkType{Name: typ}, InwardIssue: &jira.Issue{Key: inward}, OutwardIssue: &jira.Issue{Key: outward}}
+	}
+	fake.links = []jira.IssueLink{
+		link(ParentChildLinkType, "PROJ-1", "PROJ-2"),
+		link(ParentChildLinkType, "PROJ-5", "PROJ-1"),
+		link("Blocks", "PROJ-1", "PROJ-3"),
+		link(ParentChildLinkType, "PROJ-1", "PROJ-4"),
+	}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	children, err := client.GetIssueLinks("PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]bool{"PROJ-2": true, "PROJ-4": true}, children)
+}
@@ -0,0 +1,78 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// closureHeader starts the comment explaining why glue closed a ticket.
+const closureHeader = "[glue] Closed on GitHub"
+
+// CloseTicketForIssue closes a ticket like CloseTicket because its GitHub
+// issue was closed, then comments who closed the issue, why, and the pull
+// request or commit that closed it, so the context isn't lost in JIRA. A
+// failure to comment is only logged.
+func (c *Client) CloseTicketForIssue(key string, issueNumber int, closure models.GitHubIssueClosure) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	if err := c.CloseTicket(key); err != nil {
+		return err
+	}
+
+	// The ticket is closed either way, so a failed comment doesn't fail the close
+	_, resp, err := c.client.Issue.AddComment(key, &jira.Comment{
+		Body: formatClosure(issueNumber, closure),
+	})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		c.log().Warn("failed to comment close reason", "ticket", key, "error", apierror.Wrap(err, statusCode), "status", statusCode)
+	}
+	return nil
+}
+
+// formatClosure renders the comment body explaining how the GitHub issue of a
+// ticket was closed, in JIRA wiki markup.
+func formatClosure(issueNumber int, closure models.GitHubIssueClosure) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: GitHub issue #%d was closed", closureHeader, issueNumber)
+	if closure.ClosedBy != "" {
+		fmt.Fprintf(&b, " by %s", closure.ClosedBy)
+	}
+	if !closure.ClosedAt.IsZero() {
+		fmt.Fprintf(&b, " at %s", closure.ClosedAt.UTC().Format(time.RFC3339))
+	}
+	switch closure.Reason {
+	case "":
+	case "not_planned":
+		b.WriteString(" as not planned")
+	default:
+		fmt.Fprintf(&b, " as %s", strings.ReplaceAll(closure.Reason, "_", " "))
+	}
+	b.WriteString(".")
+
+	switch {
+	case closure.PullRequest != nil:
+		fmt.Fprintf(&b, "\nClosed by pull request [#%d %s|%s].", closure.PullRequest.Number, closure.PullRequest.Title, closure.PullRequest.URL)
+	case closure.CommitID != "":
+		fmt.Fprintf(&b, "\nClosed by commit [%s|%s].", shortSHA(closure.CommitID), closure.CommitURL)
+	}
+	return b.String()
+}
+
+// shortSHA abbreviates a commit SHA like git does.
+func shortSHA(sha string) string {
+	if len(sha) > 7 {
+		return sha[:7]
+	}
+	return sha
+}
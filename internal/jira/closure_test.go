@@ -0,0 +1,62 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatClosure(t *testing.T) {
+	closedAt := time.Date(2023, 6, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	tests := []struct {
+		name    string
+		closure models.GitHubIssueClosure
+		want    string
+	}{
+		{
+			name: "closed by pull request",
+			closure: models.GitHubIssueClosure{
+				ClosedBy:    "octocat",
+				ClosedAt:    closedAt,
+				Reason:      "completed",
+				CommitID:    "0123456789abcdef",
+				CommitURL:   "https://github.com/owner/repo/commit/0123456789abcdef",
+				PullRequest: &models.GitHubPullRequest{Number: 7, Title: "Fix login", URL: "https://github.com/owner/repo/pull/7"},
+			},
+			want: "[glue] Closed on GitHub: GitHub issue #42 was closed by octocat at 2023-06-01T10:30:00Z as completed.\n" +
+				"Closed by pull request [#7 Fix login|https://github.com/owner/repo/pull/7].",
+		},
+		{
+			name: "closed by commit",
+			closure: models.GitHubIssueClosure{
+				ClosedBy:  "octocat",
+				CommitID:  "0123456789abcdef",
+				CommitURL: "https://github.com/owner/repo/commit/0123456789abcdef",
+			},
+			want: "[glue] Closed on GitHub: GitHub issue #42 was closed by octocat.\n" +
+				"Closed by commit [0123456|https://github.com/owner/repo/commit/0123456789abcdef].",
+		},
+		{
+			name:    "not planned",
+			closure: models.GitHubIssueClosure{ClosedBy: "octocat", Reason: "not_planned"},
+			want:    "[glue] Closed on GitHub: GitHub issue #42 was closed by octocat as not planned.",
+		},
+		{
+			name: "nothing known",
+			want: "[glue] Closed on GitHub: GitHub issue #42 was closed.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, formatClosure(42, tt.closure))
+		})
+	}
+}
+
+func TestCloseTicketForIssueValidation(t *testing.T) {
+	assert.EqualError(t, (&Client{}).CloseTicketForIssue("TEST-1", 42, models.GitHubIssueClosure{}), "jira client not initialized")
+}
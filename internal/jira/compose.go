@@ -0,0 +1,154 @@
+package jira
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// DescriptionSection renders one section of a JIRA description for issue.
+// body is the issue body without the sections mapped to JIRA fields. An
+// empty result leaves the section out.
+type DescriptionSection func(issue models.GitHubIssue, body string) string
+
+// descriptionSections are the sections descriptions can be composed of, by
+// the name config.DescriptionConfig lists them by.
+var descriptionSections = map[string]DescriptionSection{
+	config.DescriptionBody:     bodySection,
+	config.DescriptionMetadata: metadataSection,
+	config.DescriptionChildren: childrenSection,
+	config.DescriptionFooter:   footerSection,
+}
+
+// childIssuePattern matches the links to GitHub issues in a '## Issues' section.
+var childIssuePattern = regexp.MustCompile(`https?://[^\s/]+/[^\s/]+/[^\s/]+/issues/\d+`)
+
+// backlinkPatterns match the link to the GitHub issue in the metadata table
+// and in the footer of a description, as "[#12|url]" or, without a known
+// URL, as "#12".
+var backlinkPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`\|\|GitHub issue\|\|[^\n]*\n\|(?:\[#(\d+)\|([^\]]+)\]|#(\d+))\|`),
+	regexp.MustCompile(`Synced from GitHub issue (?:\[#(\d+)\|([^\]]+)\]|#(\d+)) by glue`),
+}
+
+// Backlink is the link from a ticket's description to its GitHub issue.
+type Backlink struct {
+	// Number is the number of the issue
+	Number int
+	// URL is the web page of the issue, empty if it wasn't known when the
+	// description was written
+	URL string
+}
+
+// DescriptionBacklink returns the link to its GitHub issue written in a
+// description by the metadata or footer section. It returns false if the
+// description has neither.
+func DescriptionBacklink(description string) (Backlink, bool) {
+	for _, pattern := range backlinkPatterns {
+		match := pattern.FindStringSubmatch(description)
+		if match == nil {
+			continue
+		}
+		number := match[1]
+		if number == "" {
+			number = match[3]
+		}
+		n, err := strconv.Atoi(number)
+		if err != nil {
+			continue
+		}
+		return Backlink{Number: n, URL: match[2]}, true
+	}
+	return Backlink{}, false
+}
+
+// ComposeDescription returns the description of the ticket of issue made of
+// the named sections, in order and separated by blank lines. body is the
+// issue body without the sections mapped to JIRA fields. Without sections,
+// the description is the body alone.
+func ComposeDescription(issue models.GitHubIssue, body string, sections []string) string {
+	if len(sections) == 0 {
+		return strings.TrimSpace(body)
+	}
+
+	var parts []string
+	for _, name := range sections {
+		render, ok := descriptionSections[name]
+		if !ok {
+			continue
+		}
+		if part := strings.TrimSpace(render(issue, body)); part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+// composeDescription composes the description of issue from the sections
+// configured for the client. A JIRA notes section in the body is left out, so
+// it can't swallow the sections after the body.
+func (c *Client) composeDescription(issue models.GitHubIssue, body string) string {
+	return ComposeDescription(issue, SyncedDescription(body), c.descriptionSections)
+}
+
+// bodySection is the issue body.
+func bodySection(_ models.GitHubIssue, body string) string {
+	return body
+}
+
+// metadataSection is a table of the issue's number, author, labels and state.
+func metadataSection(issue models.GitHubIssue, _ string) string {
+	cell := func(value string) string {
+		if value == "" {
+			return " "
+		}
+		return value
+	}
+	author := ""
+	if issue.Author != "" {
+		author = "@" + issue.Author
+	}
+	return fmt.Sprintf("||GitHub issue||Author||Labels||State||\n|%s|%s|%s|%s|",
+		issueLink(issue), cell(author), cell(strings.Join(issue.Labels, ", ")), cell(issue.State))
+}
+
+// childrenSection lists the issues linked in the '## Issues' section of a
+// feature's body.
+func childrenSection(_ models.GitHubIssue, body string) string {
+	start := strings.Index(body, "## Issues")
+	if start == -1 {
+		return ""
+	}
+	section := body[start+len("## Issues"):]
+	if next := strings.Index(section, "## "); next != -1 {
+		section = section[:next]
+	}
+
+	links := childIssuePattern.FindAllString(section, -1)
+	if len(links) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("h3. Child issues")
+	for _, link := range links {
+		b.WriteString("\n* " + link)
+	}
+	return b.String()
+}
+
+// footerSection notes that the ticket is synced from its GitHub issue.
+func footerSection(issue models.GitHubIssue, _ string) string {
+	return fmt.Sprintf("----\n_Synced from GitHub issue %s by glue; edit the issue to change this description._", issueLink(issue))
+}
+
+// issueLink links the issue's number to its web page, if known.
+func issueLink(issue models.GitHubIssue) string {
+	if issue.URL == "" {
+		return fmt.Sprintf("#%d", issue.Number)
+	}
+	return fmt.Sprintf("[#%d|%s]", issue.Number, issue.URL)
+}
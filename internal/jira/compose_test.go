@@ -0,0 +1,92 @@
+package jira
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// updateGolden rewrites the golden files with the rendered output, e.g.
+// go test ./internal/jira -run TestComposeDescription -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata")
+
+func TestComposeDescription(t *testing.T) {
+	feature := models.GitHubIssue{
+		Number:      42,
+		Title:       "Checkout",
+		Description: "Rework checkout.\n\n## Issues\n- https://github.com/owner/repo/issues/43\n- https://github.com/owner/repo/issues/44\n\n## Notes\nSee https://github.com/owner/repo/issues/1",
+		State:       "open",
+		Labels:      []string{"feature", "PROJ"},
+		Author:      "octocat",
+		URL:         "https://github.com/owner/repo/issues/42",
+	}
+	story := models.GitHubIssue{Number: 7, Description: "Broken login"}
+
+	tests := []struct {
+		name     string
+		issue    models.GitHubIssue
+		sections []string
+	}{
+		{"default", feature, nil},
+		{"body", feature, []string{config.DescriptionBody}},
+		{"all", feature, []string{config.DescriptionMetadata, config.DescriptionBody, config.DescriptionChildren, config.DescriptionFooter}},
+		{"reordered", feature, []string{config.DescriptionFooter, config.DescriptionChildren, config.DescriptionBody}},
+		{"story", story, []string{config.DescriptionMetadata, config.DescriptionBody, config.DescriptionChildren, config.DescriptionFooter}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ComposeDescription(tt.issue, tt.issue.Description, tt.sections)
+
+			path := filepath.Join("testdata", "description", tt.name+".golden")
+			if *updateGolden {
+				require.NoError(t, os.WriteFile(path, []byte(got), 0o644))
+			}
+			want, err := os.ReadFile(path)
+			require.NoError(t, err)
+			assert.Equal(t, string(want), got)
+		})
+	}
+}
+
+func TestComposeDescriptionOnUpdate(t *testing.T) {
+	client := &Client{descriptionSections: []string{config.DescriptionBody, config.DescriptionFooter}}
+	issue := models.GitHubIssue{Number: 7, Description: "Broken login\n\n" + jiraNotesStart + "\nnote"}
+
+	assert.Equal(t, "Broken login\n\n----\n_Synced from GitHub issue #7 by glue; edit the issue to change this description._",
+		client.DescriptionFor(issue))
+}
+
+func TestDescriptionBacklink(t *testing.T) {
+	issue := models.GitHubIssue{Number: 42, Description: "Rework checkout.", URL: "https://github.com/owner/repo/issues/42"}
+	sections := func(names ...string) string {
+		return ComposeDescription(issue, issue.Description, names)
+	}
+
+	tests := []struct {
+		name        string
+		description string
+		want        Backlink
+		wantOK      bool
+	}{
+		{"metadata", sections(config.DescriptionMetadata, config.DescriptionBody), Backlink{Number: 42, URL: issue.URL}, true},
+		{"footer", sections(config.DescriptionBody, config.DescriptionFooter), Backlink{Number: 42, URL: issue.URL}, true},
+		{"without url", ComposeDescription(models.GitHubIssue{Number: 7}, "Broken login", []string{config.DescriptionFooter}), Backlink{Number: 7}, true},
+		{"body only", sections(config.DescriptionBody), Backlink{}, false},
+		{"link in body", "See [#9|https://github.com/owner/repo/issues/9]", Backlink{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := DescriptionBacklink(tt.description)
+			assert.Equal(t, tt.wantOK, ok)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
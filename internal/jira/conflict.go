@@ -0,0 +1,75 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+)
+
+// maxEditAttempts bounds how often an update computed from a ticket's
+// current value is recomputed when the ticket is edited concurrently.
+const maxEditAttempts = 3
+
+// errEditConflict means a ticket was updated after it was read.
+var errEditConflict = errors.New("ticket was edited concurrently")
+
+// currentUpdated returns the time a ticket was last updated, bypassing
+// responses remembered earlier in the run.
+func (c *Client) currentUpdated(key string) (time.Time, error) {
+	req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf("rest/api/2/issue/%s?fields=updated", key), nil)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Cache-Control", "no-cache")
+
+	var issue jira.Issue
+	resp, err := c.client.Do(req, &issue)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return time.Time{}, fmt.Errorf("failed to get last update of %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+	}
+	if issue.Fields == nil {
+		return time.Time{}, nil
+	}
+	return time.Time(issue.Fields.Updated), nil
+}
+
+// checkUnchanged returns errEditConflict if the ticket was updated after the
+// time it had when it was read, which is logged with both times.
+func (c *Client) checkUnchanged(key string, read time.Time) error {
+	updated, err := c.currentUpdated(key)
+	if err != nil {
+		return err
+	}
+	if !updated.Equal(read) {
+		c.log().Warn("jira ticket was edited concurrently, reading it again",
+			"ticket", key,
+			"read_updated", read,
+			"current_updated", updated)
+		return errEditConflict
+	}
+	return nil
+}
+
+// editWithRetry runs edit, which reads a ticket and updates it from what it
+// read, until it succeeds without an errEditConflict, at most
+// maxEditAttempts times.
+func (c *Client) editWithRetry(key string, edit func() error) error {
+	var err error
+	for attempt := 1; attempt <= maxEditAttempts; attempt++ {
+		if err = edit(); !errors.Is(err, errEditConflict) {
+			return err
+		}
+	}
+	c.log().Error("giving up updating jira ticket edited concurrently",
+		"ticket", key,
+		"attempts", maxEditAttempts)
+	return fmt.Errorf("not updating %s, it was edited concurrently %d times: %w", key, maxEditAttempts, err)
+}
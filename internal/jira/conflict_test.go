@@ -0,0 +1,99 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// editedTicket serves a ticket that a JIRA user edits concurrently: the first
+// staleReads reads of the whole ticket return its state before the edit,
+// while the update checks already see the edit.
+type editedTicket struct {
+	staleReads  int
+	description string
+	updated     string
+	// checks counts the update checks; with moving set, every check sees
+	// another edit
+	checks int
+	moving bool
+	// written is the description written by glue
+	written  string
+	comments int
+}
+
+func (e *editedTicket) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.Method == http.MethodGet && r.URL.Query().Get("fields") == "updated":
+		e.checks++
+		updated := e.updated
+		if e.moving {
+			updated = fmt.Sprintf("2024-03-01T10:%02d:00.000+0000", e.checks)
+		}
+		fmt.Fprintf(w, `{"key":"TEST-1","fields":{"updated":%q}}`, updated)
+	case r.Method == http.MethodGet:
+		description, updated := e.description, e.updated
+		if e.staleReads > 0 {
+			e.staleReads--
+			description, updated = "Old body", "2024-03-01T09:00:00.000+0000"
+		}
+		fmt.Fprintf(w, `{"key":"TEST-1","fields":{"description":%q,"updated":%q}}`, description, updated)
+	case r.Method == http.MethodPost:
+		e.comments++
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"1"}`)
+	case r.Method == http.MethodPut:
+		var body struct {
+			Fields map[string]string `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		e.written = body.Fields["description"]
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func TestUpdateTicketDescriptionRereadsAfterConcurrentEdit(t *testing.T) {
+	notes := jiraNotesStart + "\nAdded while glue was syncing\n" + jiraNotesEnd
+	ticket := &editedTicket{
+		staleReads:  1,
+		description: "Old body\n\n" + notes,
+		updated:     "2024-03-01T10:00:00.000+0000",
+	}
+	server := httptest.NewServer(ticket)
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	require.NoError(t, client.UpdateTicketDescription("TEST-1", models.GitHubIssue{Description: "New body"}))
+	assert.Equal(t, 2, ticket.checks)
+	assert.Equal(t, 1, ticket.comments)
+	// The notes added concurrently survive
+	assert.Equal(t, "New body\n\n"+notes, ticket.written)
+}
+
+func TestUpdateTicketDescriptionGivesUp(t *testing.T) {
+	ticket := &editedTicket{description: "Old body", updated: "2024-03-01T10:00:00.000+0000", moving: true}
+	server := httptest.NewServer(ticket)
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	err = client.UpdateTicketDescription("TEST-1", models.GitHubIssue{Description: "New body"})
+	assert.ErrorIs(t, err, errEditConflict)
+	assert.Contains(t, err.Error(), "edited concurrently 3 times")
+	assert.Equal(t, maxEditAttempts, ticket.checks)
+	assert.Zero(t, ticket.comments)
+	assert.Empty(t, ticket.written)
+}
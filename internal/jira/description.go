@@ -0,0 +1,223 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+const (
+	// snapshotHeader starts every comment that stores a previous description.
+	snapshotHeader = "[glue] Description snapshot"
+	// snapshotFence wraps the stored description so JIRA renders it verbatim.
+	snapshotFence = "{noformat}"
+	// jiraNotesStart and jiraNotesEnd delimit the part of a description that is
+	// maintained in JIRA and kept when the description is synced from GitHub.
+	jiraNotesStart = "=== JIRA notes (kept when glue updates the description) ==="
+	jiraNotesEnd   = "=== End of JIRA notes ==="
+)
+
+// UpdateTicketDescription replaces the description of a JIRA ticket with one
+// composed for its GitHub issue. Issue form sections mapped to JIRA fields are
+// set on their fields instead, and a JIRA notes section of the current description is
+// kept below it (see MergeDescription). The previous description is first saved as
+// a comment on the ticket, so it can be restored with RestoreTicketDescription.
+// If the ticket is edited between reading and replacing its description, it is
+// read and merged again. It returns an error if either step fails; the
+// description is not changed if the snapshot cannot be saved.
+func (c *Client) UpdateTicketDescription(key string, issue models.GitHubIssue) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	body, formValues := c.extractFields(issue.Description)
+	description := c.composeDescription(issue, body)
+	err := c.editWithRetry(key, func() error {
+		ticket, err := c.GetTicket(key)
+		if err != nil {
+			return err
+		}
+		return c.replaceDescription(key, ticket.Description, MergeDescription(description, ticket.Description), ticket.UpdatedAt)
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(formValues) > 0 {
+		return c.UpdateFields(key, formValues)
+	}
+	return nil
+}
+
+// replaceDescription snapshots the current description of a ticket and
+// replaces it, unless it is unchanged. read is the time the ticket was last
+// updated when current was read; if it has been updated since, the
+// description is left alone and errEditConflict returned.
+func (c *Client) replaceDescription(key, current, description string, read time.Time) error {
+	if current == description {
+		c.log().Debug("description unchanged, skipping update", "ticket", key)
+		return nil
+	}
+	if err := c.checkUnchanged(key, read); err != nil {
+		return err
+	}
+
+	_, resp, err := c.client.Issue.AddComment(key, &jira.Comment{
+		Body: formatDescriptionSnapshot(current, time.Now()),
+	})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return fmt.Errorf("failed to save description snapshot for %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	return c.setDescription(key, description)
+}
+
+// RestoreTicketDescription restores the description saved in the most recent
+// snapshot comment of a JIRA ticket. The current description is snapshotted
+// before it is replaced, so a restore can itself be undone. It returns the time
+// the restored snapshot was taken.
+func (c *Client) RestoreTicketDescription(key string) (time.Time, error) {
+	if c.client == nil {
+		return time.Time{}, fmt.Errorf("jira client not initialized")
+	}
+
+	var restored time.Time
+	err := c.editWithRetry(key, func() error {
+		issue, resp, err := c.client.Issue.Get(key, &jira.GetQueryOptions{Fields: "description,comment,updated"})
+		if err != nil {
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			return fmt.Errorf("failed to get comments for %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+		}
+
+		if issue == nil || issue.Fields == nil || issue.Fields.Comments == nil {
+			return fmt.Errorf("no description snapshot found for %s", key)
+		}
+
+		// Comments are returned oldest first; the latest snapshot wins
+		comments := issue.Fields.Comments.Comments
+		for i := len(comments) - 1; i >= 0; i-- {
+			description, takenAt, ok := parseDescriptionSnapshot(comments[i].Body)
+			if !ok {
+				continue
+			}
+
+			c.log().Info("restoring description snapshot",
+				"ticket", key,
+				"comment_id", comments[i].ID,
+				"taken_at", takenAt)
+
+			// The snapshot is restored verbatim, including its JIRA notes
+			if err := c.replaceDescription(key, issue.Fields.Description, description, time.Time(issue.Fields.Updated)); err != nil {
+				return err
+			}
+			restored = takenAt
+			return nil
+		}
+
+		return fmt.Errorf("no description snapshot found for %s", key)
+	})
+	return restored, err
+}
+
+// setDescription writes the description field of a ticket.
+func (c *Client) setDescription(key, description string) error {
+	resp, err := c.client.Issue.UpdateIssue(key, map[string]interface{}{
+		"fields": map[string]interface{}{
+			"description": description,
+		},
+	})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return fmt.Errorf("failed to update description of %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	c.log().Info("updated jira ticket description", "ticket", key)
+	return nil
+}
+
+// MergeDescription returns the GitHub issue body followed by the JIRA notes
+// section of the current JIRA description, if it has one. The section starts
+// at a line reading jiraNotesStart and ends after a line reading jiraNotesEnd,
+// or at the end of the description, so notes added by JIRA users survive syncs.
+func MergeDescription(githubBody, current string) string {
+	synced := SyncedDescription(githubBody)
+	_, notes := splitJiraNotes(current)
+	if notes == "" {
+		return synced
+	}
+	if synced == "" {
+		return notes
+	}
+	return synced + "\n\n" + notes
+}
+
+// SyncedDescription returns a description without its JIRA notes section,
+// i.e. the part that is synced from GitHub.
+func SyncedDescription(description string) string {
+	synced, _ := splitJiraNotes(description)
+	return synced
+}
+
+// splitJiraNotes splits a description into the text outside the JIRA notes
+// section and the section itself, both trimmed of surrounding blank lines.
+func splitJiraNotes(description string) (string, string) {
+	lines := strings.Split(description, "\n")
+	start, end := -1, len(lines)
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if start == -1 && trimmed == jiraNotesStart {
+			start = i
+		} else if start != -1 && trimmed == jiraNotesEnd {
+			end = i + 1
+			break
+		}
+	}
+	if start == -1 {
+		return strings.TrimSpace(description), ""
+	}
+
+	outside := append(append([]string{}, lines[:start]...), lines[end:]...)
+	return strings.TrimSpace(strings.Join(outside, "\n")), strings.TrimSpace(strings.Join(lines[start:end], "\n"))
+}
+
+// formatDescriptionSnapshot renders the comment body that stores a description.
+func formatDescriptionSnapshot(description string, takenAt time.Time) string {
+	return fmt.Sprintf("%s taken at %s before glue updated the description.\n%s\n%s\n%s",
+		snapshotHeader, takenAt.UTC().Format(time.RFC3339), snapshotFence, description, snapshotFence)
+}
+
+// parseDescriptionSnapshot extracts the stored description and snapshot time
+// from a comment body. It returns false if the comment is not a snapshot.
+func parseDescriptionSnapshot(body string) (string, time.Time, bool) {
+	if !strings.HasPrefix(body, snapshotHeader) {
+		return "", time.Time{}, false
+	}
+
+	start := strings.Index(body, snapshotFence+"\n")
+	end := strings.LastIndex(body, "\n"+snapshotFence)
+	if start == -1 || end == -1 || end < start+len(snapshotFence) {
+		return "", time.Time{}, false
+	}
+
+	var takenAt time.Time
+	header := strings.TrimPrefix(body[:start], snapshotHeader+" taken at ")
+	if fields := strings.Fields(header); len(fields) > 0 {
+		takenAt, _ = time.Parse(time.RFC3339, fields[0])
+	}
+
+	return body[start+len(snapshotFence)+1 : end], takenAt, true
+}
@@ -0,0 +1,87 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDescriptionSnapshotRoundTrip(t *testing.T) {
+	takenAt := time.Date(2025, 3, 14, 9, 26, 53, 0, time.UTC)
+
+	tests := []struct {
+		name        string
+		description string
+	}{
+		{name: "Plain text", description: "Original description"},
+		{name: "Empty description", description: ""},
+		{name: "Multiline with markdown", description: "## Issues\n- https://github.com/org/repo/issues/1\n\nMore text"},
+		{name: "Contains fence", description: "before\n{noformat}\ncode\n{noformat}\nafter"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := formatDescriptionSnapshot(tt.description, takenAt)
+
+			description, gotTime, ok := parseDescriptionSnapshot(body)
+			assert.True(t, ok)
+			assert.Equal(t, tt.description, description)
+			assert.True(t, takenAt.Equal(gotTime))
+		})
+	}
+}
+
+func TestParseDescriptionSnapshotIgnoresOtherComments(t *testing.T) {
+	for _, body := range []string{
+		"",
+		"Looks good to me",
+		"[glue] Description snapshot without fences",
+	} {
+		_, _, ok := parseDescriptionSnapshot(body)
+		assert.False(t, ok, body)
+	}
+}
+
+func TestUpdateTicketDescriptionValidation(t *testing.T) {
+	client := &Client{}
+
+	assert.Error(t, client.UpdateTicketDescription("TEST-1", models.GitHubIssue{Description: "new"}))
+
+	_, err := client.RestoreTicketDescription("TEST-1")
+	assert.Error(t, err)
+}
+
+func TestMergeDescription(t *testing.T) {
+	notes := jiraNotesStart + "\nAgreed with ops: deploy on Tuesdays\n" + jiraNotesEnd
+
+	tests := []struct {
+		name       string
+		githubBody string
+		current    string
+		want       string
+	}{
+		{name: "No notes", githubBody: "New body", current: "Old body", want: "New body"},
+		{name: "Notes kept below body", githubBody: "New body\n", current: "Old body\n\n" + notes, want: "New body\n\n" + notes},
+		{name: "Notes in the middle", githubBody: "New body", current: "Old body\n" + notes + "\nTrailing text", want: "New body\n\n" + notes},
+		{name: "Unterminated notes run to the end", githubBody: "New body", current: "Old\n" + jiraNotesStart + "\nnote", want: "New body\n\n" + jiraNotesStart + "\nnote"},
+		{name: "Notes in the GitHub body are replaced", githubBody: "New body\n" + jiraNotesStart + "\nstale\n" + jiraNotesEnd, current: notes, want: "New body\n\n" + notes},
+		{name: "Empty body", githubBody: "", current: notes, want: notes},
+		{name: "CRLF markers", githubBody: "New body", current: "Old\r\n" + jiraNotesStart + "\r\nnote\r\n" + jiraNotesEnd + "\r\n", want: "New body\n\n" + jiraNotesStart + "\r\nnote\r\n" + jiraNotesEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			merged := MergeDescription(tt.githubBody, tt.current)
+			assert.Equal(t, tt.want, merged)
+			assert.Equal(t, merged, MergeDescription(tt.githubBody, merged), "merging again is a no-op")
+		})
+	}
+}
+
+func TestSyncedDescription(t *testing.T) {
+	description := "Body\n\n" + jiraNotesStart + "\nnote\n" + jiraNotesEnd
+	assert.Equal(t, "Body", SyncedDescription(description))
+	assert.Equal(t, "Body", SyncedDescription("Body\n"))
+}
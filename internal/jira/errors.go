@@ -0,0 +1,29 @@
+package jira
+
+import (
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+)
+
+// Error kinds returned by the client, shared with the GitHub client. Use
+// errors.Is to check for them.
+var (
+	ErrNotFound     = apierror.ErrNotFound
+	ErrUnauthorized = apierror.ErrUnauthorized
+	ErrRateLimited  = apierror.ErrRateLimited
+	ErrReadOnly     = apierror.ErrReadOnly
+	ErrMaintenance  = apierror.ErrMaintenance
+)
+
+// ValidationError is returned for invalid arguments, before any request is made.
+type ValidationError = apierror.ValidationError
+
+// responseError annotates an error from the JIRA API with the error kind of
+// the response status.
+func responseError(resp *jira.Response, err error) error {
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+	}
+	return apierror.Wrap(err, statusCode)
+}
@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseError(t *testing.T) {
+	cause := errors.New("request failed")
+
+	notFound := responseError(&jira.Response{Response: &http.Response{StatusCode: http.StatusNotFound}}, cause)
+	assert.ErrorIs(t, notFound, ErrNotFound)
+	assert.ErrorIs(t, notFound, cause)
+	assert.Equal(t, "request failed", notFound.Error())
+
+	noResponse := responseError(nil, cause)
+	assert.ErrorIs(t, noResponse, cause)
+	assert.False(t, errors.Is(noResponse, ErrNotFound))
+}
+
+func TestValidationErrors(t *testing.T) {
+	client := &Client{client: &jira.Client{}}
+
+	var validationErr *ValidationError
+	assert.True(t, errors.As(client.UpdateFields("", map[string]interface{}{"labels": nil}), &validationErr))
+	assert.Equal(t, "key", validationErr.Field)
+
+	_, err := client.SearchKeys("")
+	assert.True(t, errors.As(err, &validationErr))
+	assert.Equal(t, "jql", validationErr.Field)
+}
@@ -0,0 +1,157 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// fakeJira is a minimal JIRA server keeping created tickets with their
+// status, remote links, comments and issue links.
+type fakeJira struct {
+	mu      sync.Mutex
+	tickets map[string]*fakeTicket
+	// links holds the issue links between tickets, by link ID
+	links []jira.IssueLink
+	// moved maps the former keys of moved tickets to their current key
+	moved map[string]string
+	// calls counts the requests served
+	calls int
+}
+
+type fakeTicket struct {
+	summary     string
+	status      string
+	remoteLinks []jira.RemoteLink
+	comments    []string
+}
+
+func (f *fakeJira) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	w.Header().Set("Content-Type", "application/json")
+
+	switch r.URL.Path {
+	case "/rest/api/2/myself":
+		fmt.Fprint(w, `{"name":"glue"}`)
+		return
+	case "/rest/api/2/issueLink":
+		var link jira.IssueLink
+		if err := json.NewDecoder(r.Body).Decode(&link); err != nil || link.InwardIssue == nil || link.OutwardIssue == nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		link.ID = fmt.Sprint(len(f.links) + 1)
+		f.links = append(f.links, link)
+		w.WriteHeader(http.StatusCreated)
+		return
+	}
+
+	path := strings.TrimPrefix(r.URL.Path, "/rest/api/2/issue")
+	if current, ok := f.moved[strings.TrimPrefix(path, "/")]; ok {
+		path = "/" + current
+	}
+	switch {
+	case r.Method == http.MethodPost && path == "":
+		var issue jira.Issue
+		if err := json.NewDecoder(r.Body).Decode(&issue); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		key := fmt.Sprintf("PROJ-%d", len(f.tickets)+1)
+		f.tickets[key] = &fakeTicket{summary: issue.Fields.Summary, status: "To Do"}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":"%d","key":"%s"}`, len(f.tickets), key)
+	case strings.HasSuffix(path, "/transitions"):
+		ticket := f.tickets[strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/transitions")]
+		if ticket == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			fmt.Fprint(w, `{"transitions":[{"id":"11","name":"In Progress"},{"id":"31","name":"Done"}]}`)
+			return
+		}
+		ticket.status = "Done"
+		w.WriteHeader(http.StatusNoContent)
+	case strings.HasSuffix(path, "/remotelink"):
+		ticket := f.tickets[strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/remotelink")]
+		if ticket == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		if r.Method == http.MethodGet {
+			json.NewEncoder(w).Encode(ticket.remoteLinks)
+			return
+		}
+		var link jira.RemoteLink
+		if err := json.NewDecoder(r.Body).Decode(&link); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		link.ID = len(ticket.remoteLinks) + 1
+		ticket.remoteLinks = append(ticket.remoteLinks, link)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"id":%d}`, link.ID)
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/comment"):
+		ticket := f.tickets[strings.TrimSuffix(strings.TrimPrefix(path, "/"), "/comment")]
+		if ticket == nil {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		var comment jira.Comment
+		if err := json.NewDecoder(r.Body).Decode(&comment); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		ticket.comments = append(ticket.comments, comment.Body)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"1"}`)
+	case r.Method == http.MethodGet && f.tickets[strings.TrimPrefix(path, "/")] != nil:
+		key := strings.TrimPrefix(path, "/")
+		ticket := f.tickets[key]
+		links, _ := json.Marshal(f.linksOf(key))
+		category := "new"
+		if ticket.status == "Done" {
+			category = "done"
+		}
+		fmt.Fprintf(w, `{"key":"%s","fields":{"summary":%q,"status":{"name":"%s","statusCategory":{"key":"%s"}},"issuelinks":%s}}`,
+			key, ticket.summary, ticket.status, category, links)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// linksOf returns the issue links of a ticket as JIRA shows them on it: a
+// link shows the ticket at its other end.
+func (f *fakeJira) linksOf(key string) []jira.IssueLink {
+	links := []jira.IssueLink{}
+	for _, link := range f.links {
+		switch key {
+		case link.InwardIssue.Key:
+			links = append(links, jira.IssueLink{ID: link.ID, Type: link.Type, OutwardIssue: &jira.Issue{Key: link.OutwardIssue.Key}})
+		case link.OutwardIssue.Key:
+			links = append(links, jira.IssueLink{ID: link.ID, Type: link.Type, InwardIssue: &jira.Issue{Key: link.InwardIssue.Key}})
+		}
+	}
+	return links
+}
+
+// newFakeJira returns an empty fake server.
+func newFakeJira() *fakeJira {
+	return &fakeJira{tickets: make(map[string]*fakeTicket), moved: make(map[string]string)}
+}
+
+// resetCalls returns the number of requests served and starts counting anew.
+func (f *fakeJira) resetCalls() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	calls := f.calls
+	f.calls = 0
+	return calls
+}
@@ -0,0 +1,95 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestSyncConvergesDespiteFaults runs the create and close passes of a sync
+// repeatedly against a JIRA failing a third of all requests, the way glue is
+// rerun by cron. Since an issue only counts as synced once JIRA returned its
+// key, every issue must end up with exactly one ticket in the right status.
+func TestSyncConvergesDespiteFaults(t *testing.T) {
+	fake := newFakeJira()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	var issues []models.GitHubIssue
+	for i := 1; i <= 20; i++ {
+		state := "open"
+		if i%3 == 0 {
+			state = "closed"
+		}
+		issues = append(issues, models.GitHubIssue{Number: i, Title: fmt.Sprintf("Issue %d", i), State: state})
+	}
+
+	keys := make(map[int]string)
+	closed := make(map[int]bool)
+	injected := 0
+	runs := 0
+	for ; runs < 50; runs++ {
+		// Each run is a new process with a fresh client and circuit breaker
+		transport := faults.New(http.DefaultTransport, 0.3, int64(runs))
+		b := newBreaker(DefaultMaxConsecutiveFailures)
+		jiraClient, err := jira.NewClient(&http.Client{Transport: &breakerTransport{base: transport, breaker: b}}, server.URL)
+		require.NoError(t, err)
+		client := &Client{
+			client:          jiraClient,
+			issueTypeCache:  map[string]map[string]string{"PROJ": {"story": "10001"}},
+			fixVersionCache: map[string]*jira.FixVersion{"PROJ": nil},
+			breaker:         b,
+		}
+
+		pending := 0
+		for _, issue := range issues {
+			if client.CircuitOpen() != nil {
+				pending++
+				continue
+			}
+			if keys[issue.Number] == "" {
+				key, err := client.CreateTicketWithTypeID("PROJ", issue, "10001")
+				if err != nil {
+					pending++
+					continue
+				}
+				keys[issue.Number] = key
+			}
+			if issue.State == "closed" && !closed[issue.Number] {
+				if err := client.CloseTicket(keys[issue.Number]); err != nil {
+					pending++
+					continue
+				}
+				closed[issue.Number] = true
+			}
+		}
+
+		for _, n := range transport.Counts() {
+			injected += n
+		}
+		if pending == 0 {
+			break
+		}
+	}
+
+	require.Less(t, runs, 50, "sync didn't converge")
+	assert.Positive(t, injected)
+	assert.Len(t, fake.tickets, len(issues))
+	for _, issue := range issues {
+		ticket := fake.tickets[keys[issue.Number]]
+		require.NotNil(t, ticket, "issue #%d", issue.Number)
+		assert.Equal(t, issue.Title, ticket.summary)
+		if issue.State == "closed" {
+			assert.Equal(t, "Done", ticket.status, "issue #%d", issue.Number)
+		} else {
+			assert.Equal(t, "To Do", ticket.status, "issue #%d", issue.Number)
+		}
+	}
+}
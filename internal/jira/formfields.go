@@ -0,0 +1,59 @@
+package jira
+
+import (
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/issueform"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// DescriptionFor returns the description glue writes for a GitHub issue:
+// composed of the configured sections, with the body without the issue form
+// sections mapped to JIRA fields, and without a JIRA notes section.
+func (c *Client) DescriptionFor(issue models.GitHubIssue) string {
+	body, _ := c.extractFields(issue.Description)
+	return c.composeDescription(issue, body)
+}
+
+// extractFields removes the sections mapped to JIRA fields, by form_fields and
+// acceptance_criteria, from an issue body. It returns the remaining body and
+// the field values.
+func (c *Client) extractFields(body string) (string, map[string]interface{}) {
+	body, fields := extractFormFields(body, c.formFields)
+	body, checklist := extractChecklist(body, c.checklist)
+	for id, value := range checklist {
+		if fields == nil {
+			fields = make(map[string]interface{})
+		}
+		fields[id] = value
+	}
+	return body, fields
+}
+
+// extractFormFields removes the issue form sections mapped to JIRA fields from
+// an issue body. It returns the remaining body and the field values in their
+// REST representation. Without mappings the body is returned unchanged.
+func extractFormFields(body string, mappings []config.FormField) (string, map[string]interface{}) {
+	if len(mappings) == 0 {
+		return body, nil
+	}
+
+	headings := make([]string, 0, len(mappings))
+	for _, m := range mappings {
+		headings = append(headings, m.Heading)
+	}
+	remaining, values := issueform.Extract(body, headings)
+
+	fields := make(map[string]interface{})
+	for _, m := range mappings {
+		value, ok := values[m.Heading]
+		if !ok {
+			continue
+		}
+		if m.Option {
+			fields[m.Field] = map[string]interface{}{"value": value}
+		} else {
+			fields[m.Field] = value
+		}
+	}
+	return remaining, fields
+}
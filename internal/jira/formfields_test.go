@@ -0,0 +1,37 @@
+package jira
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractFormFields(t *testing.T) {
+	body := "Summary\n\n### Acceptance Criteria\n\n- [ ] Works\n\n### Severity\n\nHigh\n\n### Environment\n\n_No response_"
+	mappings := []config.FormField{
+		{Heading: "Acceptance Criteria", Field: "customfield_10020"},
+		{Heading: "severity", Field: "customfield_10030", Option: true},
+		{Heading: "Environment", Field: "environment"},
+	}
+
+	remaining, fields := extractFormFields(body, mappings)
+	assert.Equal(t, "Summary", remaining)
+	assert.Equal(t, map[string]interface{}{
+		"customfield_10020": "- [ ] Works",
+		"customfield_10030": map[string]interface{}{"value": "High"},
+	}, fields)
+
+	remaining, fields = extractFormFields(body, nil)
+	assert.Equal(t, body, remaining)
+	assert.Nil(t, fields)
+}
+
+func TestDescriptionFor(t *testing.T) {
+	client := &Client{formFields: []config.FormField{{Heading: "Environment", Field: "environment"}}}
+
+	body := "Broken login\n\n### Environment\n\nProduction\n\n" + jiraNotesStart + "\nnote"
+	assert.Equal(t, "Broken login", client.DescriptionFor(models.GitHubIssue{Description: body}))
+	assert.Equal(t, "Broken login", (&Client{}).DescriptionFor(models.GitHubIssue{Description: "Broken login\n"}))
+}
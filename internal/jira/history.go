@@ -0,0 +1,54 @@
+package jira
+
+import (
+	"fmt"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+)
+
+// historicalCloseHeader starts the comment recording when an imported GitHub issue was closed.
+const historicalCloseHeader = "[glue] Historical import"
+
+// jiraTimeLayout is the layout of JIRA datetime field values.
+const jiraTimeLayout = "2006-01-02T15:04:05.000-0700"
+
+// CloseHistoricalTicket marks a ticket created for an already closed GitHub
+// issue as done. The original close time is recorded in a comment and, if
+// closedAtField is set, in that datetime custom field. It then transitions the
+// ticket like CloseTicket.
+func (c *Client) CloseHistoricalTicket(key string, closedAt time.Time, closedAtField string) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+	if closedAt.IsZero() {
+		return fmt.Errorf("close time of %s is unknown", key)
+	}
+
+	_, resp, err := c.client.Issue.AddComment(key, &jira.Comment{
+		Body: formatHistoricalClose(closedAt),
+	})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return fmt.Errorf("failed to record close time on %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	if closedAtField != "" {
+		fields := map[string]interface{}{closedAtField: closedAt.UTC().Format(jiraTimeLayout)}
+		if err := c.UpdateFields(key, fields); err != nil {
+			return err
+		}
+	}
+
+	return c.CloseTicket(key)
+}
+
+// formatHistoricalClose renders the comment body that records a close time.
+func formatHistoricalClose(closedAt time.Time) string {
+	return fmt.Sprintf("%s: the GitHub issue was closed at %s, before this ticket was created.",
+		historicalCloseHeader, closedAt.UTC().Format(time.RFC3339))
+}
@@ -0,0 +1,26 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatHistoricalClose(t *testing.T) {
+	closedAt := time.Date(2023, 6, 1, 12, 30, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+	assert.Equal(t,
+		"[glue] Historical import: the GitHub issue was closed at 2023-06-01T10:30:00Z, before this ticket was created.",
+		formatHistoricalClose(closedAt))
+}
+
+func TestCloseHistoricalTicketValidation(t *testing.T) {
+	closedAt := time.Date(2023, 6, 1, 12, 30, 0, 0, time.UTC)
+
+	assert.EqualError(t, (&Client{}).CloseHistoricalTicket("TEST-1", closedAt, ""), "jira client not initialized")
+
+	client := &Client{client: &jira.Client{}}
+	assert.EqualError(t, client.CloseHistoricalTicket("TEST-1", time.Time{}, ""), "close time of TEST-1 is unknown")
+}
@@ -0,0 +1,17 @@
+package jira
+
+import (
+	"time"
+
+	"github.com/danielolaszy/glue/internal/maintenance"
+)
+
+// Maintenance returns the maintenance window JIRA is in at now and when it
+// ends, or false outside all windows. Requests that would change JIRA fail
+// with ErrMaintenance during a window.
+func (c *Client) Maintenance(now time.Time) (maintenance.Window, time.Time, bool) {
+	if c == nil {
+		return maintenance.Window{}, time.Time{}, false
+	}
+	return c.maintenance.Active(now)
+}
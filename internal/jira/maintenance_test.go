@@ -0,0 +1,27 @@
+package jira
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/maintenance"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientMaintenance(t *testing.T) {
+	window, err := maintenance.NewWindow("upgrade", "0 2 * * *", time.Hour, "UTC")
+	require.NoError(t, err)
+	client := &Client{maintenance: maintenance.Windows{window}}
+
+	active, until, ok := client.Maintenance(time.Date(2024, 3, 1, 2, 30, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "upgrade", active.Name)
+	assert.True(t, until.Equal(time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC)))
+
+	_, _, ok = client.Maintenance(time.Date(2024, 3, 1, 3, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+
+	_, _, ok = (&Client{}).Maintenance(time.Now())
+	assert.False(t, ok)
+}
@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"errors"
+
+	jira "github.com/andygrunwald/go-jira"
+)
+
+// TicketFunc observes the fetches of tickets by key, e.g. to notice mappings
+// that broke: current is the key the ticket answered under, which differs
+// from key if the ticket was moved to another project, or "" if it doesn't
+// exist anymore. Fetches failing for other reasons aren't observed.
+type TicketFunc func(key, current string)
+
+// SetTicketFunc sets the function observing the fetches of tickets by
+// GetTicket and GetTicketStatus. Copies made with WithLogger afterwards share it.
+func (c *Client) SetTicketFunc(fn TicketFunc) {
+	c.ticketFunc = fn
+}
+
+// observeTicket passes the outcome of fetching a ticket by key to the ticket
+// function, if there is one.
+func (c *Client) observeTicket(key string, issue *jira.Issue, err error) {
+	if c.ticketFunc == nil {
+		return
+	}
+	switch {
+	case err != nil:
+		if errors.Is(err, ErrNotFound) {
+			c.ticketFunc(key, "")
+		}
+	case issue != nil && issue.Key != "":
+		c.ticketFunc(key, issue.Key)
+	}
+}
@@ -0,0 +1,40 @@
+package jira
+
+import (
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketFunc(t *testing.T) {
+	fake := newFakeJira()
+	fake.tickets["PROJ-1"] = &fakeTicket{summary: "Login", status: "To Do"}
+	fake.tickets["NEW-7"] = &fakeTicket{summary: "Logout", status: "Done"}
+	fake.moved["PROJ-2"] = "NEW-7"
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	observed := make(map[string]string)
+	client.SetTicketFunc(func(key, current string) {
+		observed[key] = current
+	})
+
+	_, err = client.GetTicketStatus("PROJ-1")
+	require.NoError(t, err)
+	status, err := client.WithLogger(client.log()).GetTicketStatus("PROJ-2")
+	require.NoError(t, err)
+	assert.Equal(t, "Done", status)
+	_, err = client.GetTicket("PROJ-3")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	assert.Equal(t, map[string]string{"PROJ-1": "PROJ-1", "PROJ-2": "NEW-7", "PROJ-3": ""}, observed)
+}
@@ -0,0 +1,85 @@
+package jira
+
+import (
+	"fmt"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// pullRequestHeader starts the comment announcing a merged pull request.
+const pullRequestHeader = "[glue] Pull request merged"
+
+// LinkPullRequest adds a remote link to a merged GitHub pull request to a
+// ticket and comments on it, so the work shows up next to the ticket. It
+// reports false without changing anything if the ticket already links to the
+// pull request.
+func (c *Client) LinkPullRequest(key string, pull models.GitHubPullRequest) (bool, error) {
+	if c.client == nil {
+		return false, fmt.Errorf("jira client not initialized")
+	}
+
+	links, resp, err := c.client.Issue.GetRemoteLinks(key)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return false, fmt.Errorf("failed to get remote links of %s: %w (status: %d)", key, apierror.Wrap(err, statusCode), statusCode)
+	}
+	if links != nil {
+		for _, link := range *links {
+			if link.GlobalID == pull.URL || link.Object != nil && link.Object.URL == pull.URL {
+				return false, nil
+			}
+		}
+	}
+
+	_, resp, err = c.client.Issue.AddRemoteLink(key, pullRequestLink(pull))
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return false, fmt.Errorf("failed to link pull request #%d to %s: %w (status: %d)", pull.Number, key, apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	_, resp, err = c.client.Issue.AddComment(key, &jira.Comment{Body: formatPullRequestComment(pull)})
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return true, fmt.Errorf("failed to comment pull request #%d on %s: %w (status: %d)", pull.Number, key, apierror.Wrap(err, statusCode), statusCode)
+	}
+
+	c.log().Info("linked pull request", "ticket", key, "pull_request", pull.Number)
+	return true, nil
+}
+
+// pullRequestLink returns the remote link to a pull request. Its global ID is
+// the pull request URL, so JIRA keeps a single link per pull request.
+func pullRequestLink(pull models.GitHubPullRequest) *jira.RemoteLink {
+	return &jira.RemoteLink{
+		GlobalID:     pull.URL,
+		Application:  &jira.RemoteLinkApplication{Type: "com.github", Name: "GitHub"},
+		Relationship: "pull request",
+		Object: &jira.RemoteLinkObject{
+			URL:     pull.URL,
+			Title:   fmt.Sprintf("#%d %s", pull.Number, pull.Title),
+			Summary: fmt.Sprintf("Merged from %s", pull.HeadBranch),
+			Status:  &jira.RemoteLinkStatus{Resolved: pull.Merged},
+		},
+	}
+}
+
+// formatPullRequestComment renders the comment announcing a merged pull
+// request, in JIRA wiki markup.
+func formatPullRequestComment(pull models.GitHubPullRequest) string {
+	comment := fmt.Sprintf("%s: [#%d %s|%s]", pullRequestHeader, pull.Number, pull.Title, pull.URL)
+	if pull.HeadBranch != "" {
+		comment += fmt.Sprintf(" from branch {{%s}}", pull.HeadBranch)
+	}
+	return comment + "."
+}
@@ -0,0 +1,65 @@
+package jira
+
+import (
+	"io"
+	"log/slog"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPullRequestLink(t *testing.T) {
+	pull := models.GitHubPullRequest{Number: 7, Title: "Fix login", Merged: true, HeadBranch: "PROJ-1-login", URL: "https://github.com/owner/repo/pull/7"}
+
+	link := pullRequestLink(pull)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7", link.GlobalID)
+	assert.Equal(t, "GitHub", link.Application.Name)
+	assert.Equal(t, "#7 Fix login", link.Object.Title)
+	assert.Equal(t, "https://github.com/owner/repo/pull/7", link.Object.URL)
+	assert.True(t, link.Object.Status.Resolved)
+
+	assert.Equal(t, "[glue] Pull request merged: [#7 Fix login|https://github.com/owner/repo/pull/7] from branch {{PROJ-1-login}}.",
+		formatPullRequestComment(pull))
+	pull.HeadBranch = ""
+	assert.Equal(t, "[glue] Pull request merged: [#7 Fix login|https://github.com/owner/repo/pull/7].",
+		formatPullRequestComment(pull))
+}
+
+func TestLinkPullRequestValidation(t *testing.T) {
+	_, err := (&Client{}).LinkPullRequest("TEST-1", models.GitHubPullRequest{})
+	assert.EqualError(t, err, "jira client not initialized")
+}
+
+func TestLinkPullRequest(t *testing.T) {
+	fake := newFakeJira()
+	fake.tickets["PROJ-1"] = &fakeTicket{summary: "Login", status: "To Do"}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	pull := models.GitHubPullRequest{Number: 7, Title: "Fix login", Merged: true, URL: "https://github.com/owner/repo/pull/7"}
+
+	linked, err := client.LinkPullRequest("PROJ-1", pull)
+	require.NoError(t, err)
+	assert.True(t, linked)
+
+	// Linking again changes nothing
+	linked, err = client.LinkPullRequest("PROJ-1", pull)
+	require.NoError(t, err)
+	assert.False(t, linked)
+
+	ticket := fake.tickets["PROJ-1"]
+	require.Len(t, ticket.remoteLinks, 1)
+	assert.Equal(t, pull.URL, ticket.remoteLinks[0].GlobalID)
+	assert.Equal(t, []string{"[glue] Pull request merged: [#7 Fix login|https://github.com/owner/repo/pull/7]."}, ticket.comments)
+
+	_, err = client.LinkPullRequest("PROJ-2", pull)
+	assert.Error(t, err)
+}
@@ -0,0 +1,97 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// errReporterRejected marks ticket creations JIRA rejected for the reporter,
+// typically because the service account lacks the Modify Reporter permission.
+var errReporterRejected = errors.New("reporter rejected")
+
+// ReporterFunc returns the JIRA user of the GitHub login that opened an issue,
+// or nil if it has none.
+type ReporterFunc func(login string) *models.JiraUser
+
+// SetReporterFunc sets the function resolving the reporters of new tickets.
+// Once set, tickets are reported by the JIRA user of their issue's author;
+// where that user isn't found or can't be set, the service account reports
+// them and a JIRA notes section names the author instead. Copies made with
+// WithLogger afterwards share it.
+func (c *Client) SetReporterFunc(fn ReporterFunc) {
+	c.reporterFunc = fn
+	c.reporterDenied = make(map[string]bool)
+}
+
+// reporterFor returns the reporter to set on a new ticket of a project for
+// issue, or nil if the service account reports it.
+func (c *Client) reporterFor(projectKey string, issue models.GitHubIssue) *jira.User {
+	if c.reporterFunc == nil || issue.Author == "" || c.reporterDenied[strings.ToUpper(projectKey)] {
+		return nil
+	}
+	user := c.reporterFunc(issue.Author)
+	if user == nil {
+		c.log().Debug("no jira user for issue author, reporting on their behalf",
+			"issue_number", issue.Number,
+			"author", issue.Author)
+		return nil
+	}
+	return &jira.User{AccountID: user.AccountID, Name: user.Name}
+}
+
+// onBehalfOf returns description with a JIRA notes section naming the GitHub
+// author of issue, for tickets reported by the service account. Being JIRA
+// notes, it is kept when the description is synced from GitHub. The
+// description is returned unchanged if reporters aren't attributed.
+func (c *Client) onBehalfOf(description string, issue models.GitHubIssue) string {
+	if c.reporterFunc == nil || issue.Author == "" {
+		return description
+	}
+	footer := fmt.Sprintf("%s\nReported on behalf of GitHub user @%s\n%s", jiraNotesStart, issue.Author, jiraNotesEnd)
+	if strings.TrimSpace(description) == "" {
+		return footer
+	}
+	return strings.TrimRight(description, "\n") + "\n\n" + footer
+}
+
+// createAttributedIssue creates a ticket with the given description for
+// issue, reported by the JIRA user of the issue's author where possible (see
+// SetReporterFunc). If JIRA rejects the reporter, the ticket is created again
+// as the service account and the project isn't sent a reporter anymore.
+func (c *Client) createAttributedIssue(jiraIssue *jira.Issue, issue models.GitHubIssue, description string) (*jira.Issue, error) {
+	reporter := c.reporterFor(jiraIssue.Fields.Project.Key, issue)
+	if reporter == nil {
+		jiraIssue.Fields.Reporter = nil
+		jiraIssue.Fields.Description = c.onBehalfOf(description, issue)
+		return c.createIssue(jiraIssue)
+	}
+
+	jiraIssue.Fields.Reporter = reporter
+	jiraIssue.Fields.Description = description
+	newIssue, err := c.createIssue(jiraIssue)
+	if !errors.Is(err, errReporterRejected) {
+		return newIssue, err
+	}
+
+	projectKey := strings.ToUpper(jiraIssue.Fields.Project.Key)
+	c.log().Warn("jira rejected the reporter, reporting as the service account",
+		"project", projectKey,
+		"author", issue.Author)
+	c.reporterDenied[projectKey] = true
+	jiraIssue.Fields.Reporter = nil
+	jiraIssue.Fields.Description = c.onBehalfOf(description, issue)
+	return c.createIssue(jiraIssue)
+}
+
+// rejectsReporter reports whether a 400 response rejects the reporter field.
+func rejectsReporter(statusCode int, body []byte) bool {
+	if statusCode != http.StatusBadRequest {
+		return false
+	}
+	return fieldErrors(body)["reporter"] != ""
+}
@@ -0,0 +1,88 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateAttributedIssue(t *testing.T) {
+	var requests []jira.IssueFields
+	allowReporter := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var issue jira.Issue
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&issue))
+		requests = append(requests, *issue.Fields)
+
+		w.Header().Set("Content-Type", "application/json")
+		if issue.Fields.Reporter != nil && !allowReporter {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"errorMessages":[],"errors":{"reporter":"Field 'reporter' cannot be set. It is not on the appropriate screen, or unknown."}}`)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"id":"1","key":"PROJ-1"}`)
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+	client.SetReporterFunc(func(login string) *models.JiraUser {
+		if login == "octocat" {
+			return &models.JiraUser{AccountID: "5b10a2844c20165700ede21g"}
+		}
+		return nil
+	})
+	newIssue := func(author string) (*jira.Issue, error) {
+		fields := &jira.IssueFields{Project: jira.Project{Key: "proj"}, Summary: "Add login"}
+		return client.createAttributedIssue(&jira.Issue{Fields: fields}, models.GitHubIssue{Number: 7, Author: author}, "Body")
+	}
+	footer := "Body\n\n" + jiraNotesStart + "\nReported on behalf of GitHub user @%s\n" + jiraNotesEnd
+
+	// A mapped author reports the ticket
+	_, err = newIssue("octocat")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "5b10a2844c20165700ede21g", requests[0].Reporter.AccountID)
+	assert.Equal(t, "Body", requests[0].Description)
+
+	// An unmapped author is named in the description
+	requests = nil
+	_, err = newIssue("hubot")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Nil(t, requests[0].Reporter)
+	assert.Equal(t, fmt.Sprintf(footer, "hubot"), requests[0].Description)
+
+	// A rejected reporter falls back to the service account, and isn't sent again
+	allowReporter = false
+	requests = nil
+	_, err = newIssue("octocat")
+	require.NoError(t, err)
+	require.Len(t, requests, 2)
+	assert.Nil(t, requests[1].Reporter)
+	assert.Equal(t, fmt.Sprintf(footer, "octocat"), requests[1].Description)
+
+	requests = nil
+	_, err = newIssue("octocat")
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	assert.Nil(t, requests[0].Reporter)
+}
+
+func TestRejectsReporter(t *testing.T) {
+	body := []byte(`{"errorMessages":[],"errors":{"reporter":"You do not have permission to modify the reporter."}}`)
+	assert.True(t, rejectsReporter(http.StatusBadRequest, body))
+	assert.False(t, rejectsReporter(http.StatusForbidden, body))
+	assert.False(t, rejectsReporter(http.StatusBadRequest, []byte(`{"errors":{"summary":"Summary is required."}}`)))
+}
@@ -0,0 +1,140 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// RequiredFieldsError is returned when JIRA rejects a ticket because fields
+// required by the project are missing.
+type RequiredFieldsError struct {
+	// Fields maps the IDs of the missing fields to JIRA's error messages
+	Fields map[string]string
+	// Err is the error of the API call
+	Err error
+}
+
+// Error implements error.
+func (e *RequiredFieldsError) Error() string {
+	ids := make([]string, 0, len(e.Fields))
+	for id := range e.Fields {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	missing := make([]string, 0, len(ids))
+	for _, id := range ids {
+		missing = append(missing, fmt.Sprintf("%s (%s)", id, e.Fields[id]))
+	}
+	return "missing required fields: " + strings.Join(missing, ", ")
+}
+
+// Unwrap returns the error of the API call.
+func (e *RequiredFieldsError) Unwrap() error {
+	return e.Err
+}
+
+// RequiredFieldsFunc supplies values, in their REST representation, for the
+// required fields a ticket for issue was rejected for. missing maps field IDs
+// to JIRA's error messages. It returns the values it could supply.
+type RequiredFieldsFunc func(projectKey string, issue models.GitHubIssue, missing map[string]string) map[string]interface{}
+
+// SetRequiredFieldsFunc sets the function asked for required fields that have
+// no default in the config file, e.g. to prompt the user. Copies made with
+// WithLogger afterwards share it.
+func (c *Client) SetRequiredFieldsFunc(fn RequiredFieldsFunc) {
+	c.requiredFields = fn
+}
+
+// requiredFieldValues returns values for missing required fields: the
+// configured defaults, then those supplied by the required fields function.
+func (c *Client) requiredFieldValues(projectKey string, issue models.GitHubIssue, missing map[string]string) map[string]interface{} {
+	values := make(map[string]interface{})
+	rest := make(map[string]string)
+	for id, message := range missing {
+		if value, ok := c.requiredDefaults[strings.ToLower(id)]; ok {
+			values[id] = value
+		} else {
+			rest[id] = message
+		}
+	}
+
+	if len(rest) > 0 && c.requiredFields != nil {
+		for id, value := range c.requiredFields(projectKey, issue, rest) {
+			values[id] = value
+		}
+	}
+	return values
+}
+
+// createIssue creates a ticket and returns it, or an error including JIRA's
+// response. Rejections for missing required fields return a *RequiredFieldsError.
+func (c *Client) createIssue(jiraIssue *jira.Issue) (*jira.Issue, error) {
+	c.log().Debug("sending request to jira api")
+
+	newIssue, resp, err := c.client.Issue.Create(jiraIssue)
+	if err == nil {
+		return newIssue, nil
+	}
+
+	statusCode := 0
+	if resp != nil {
+		statusCode = resp.StatusCode
+
+		// Try to get more details about the error
+		body, readErr := io.ReadAll(resp.Body)
+		if readErr == nil {
+			c.log().Error("failed to create jira ticket",
+				"error", err,
+				"status_code", statusCode,
+				"response", string(body))
+
+			cause := apierror.Wrap(err, statusCode)
+			if missing := parseRequiredFields(statusCode, body); len(missing) > 0 {
+				cause = &RequiredFieldsError{Fields: missing, Err: cause}
+			} else if rejectsReporter(statusCode, body) {
+				cause = fmt.Errorf("%w: %w", errReporterRejected, cause)
+			}
+			return nil, fmt.Errorf("failed to create jira ticket: %w (status: %d, response: %s)",
+				cause, statusCode, string(body))
+		}
+	}
+	c.log().Error("failed to create jira ticket", "error", err, "status_code", statusCode)
+	return nil, fmt.Errorf("failed to create jira ticket: %w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
+}
+
+// parseRequiredFields returns the fields a 400 response reports as required,
+// mapped to their error messages, e.g. {"customfield_10011": "Epic Name is required."}.
+func parseRequiredFields(statusCode int, body []byte) map[string]string {
+	if statusCode != http.StatusBadRequest {
+		return nil
+	}
+
+	missing := make(map[string]string)
+	for field, message := range fieldErrors(body) {
+		if strings.Contains(strings.ToLower(message), "is required") {
+			missing[field] = message
+		}
+	}
+	return missing
+}
+
+// fieldErrors returns the per-field errors of a JIRA error response, mapped
+// from field IDs to messages, or nil if the body isn't one.
+func fieldErrors(body []byte) map[string]string {
+	var response struct {
+		Errors map[string]string `json:"errors"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil
+	}
+	return response.Errors
+}
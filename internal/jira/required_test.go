@@ -0,0 +1,64 @@
+package jira
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRequiredFields(t *testing.T) {
+	body := []byte(`{"errorMessages":[],"errors":{"customfield_10011":"Epic Name is required.","summary":"Summary is too long."}}`)
+
+	assert.Equal(t, map[string]string{"customfield_10011": "Epic Name is required."}, parseRequiredFields(http.StatusBadRequest, body))
+	assert.Empty(t, parseRequiredFields(http.StatusInternalServerError, body))
+	assert.Empty(t, parseRequiredFields(http.StatusBadRequest, []byte("not json")))
+}
+
+func TestRequiredFieldValues(t *testing.T) {
+	var asked map[string]string
+	client := &Client{
+		requiredDefaults: map[string]interface{}{"customfield_10011": "Unplanned"},
+	}
+	client.SetRequiredFieldsFunc(func(projectKey string, issue models.GitHubIssue, missing map[string]string) map[string]interface{} {
+		asked = missing
+		return map[string]interface{}{"customfield_10050": map[string]interface{}{"value": "Team A"}}
+	})
+
+	values := client.requiredFieldValues("PROJ", models.GitHubIssue{Number: 1}, map[string]string{
+		"customfield_10011": "Epic Name is required.",
+		"customfield_10050": "Team is required.",
+	})
+
+	assert.Equal(t, map[string]string{"customfield_10050": "Team is required."}, asked)
+	assert.Equal(t, map[string]interface{}{
+		"customfield_10011": "Unplanned",
+		"customfield_10050": map[string]interface{}{"value": "Team A"},
+	}, values)
+}
+
+func TestCreateIssueRequiredFieldsError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"errorMessages":[],"errors":{"customfield_10011":"Epic Name is required."}}`)
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	_, err = client.createIssue(&jira.Issue{Fields: &jira.IssueFields{Summary: "Add login"}})
+
+	var required *RequiredFieldsError
+	require.True(t, errors.As(err, &required))
+	assert.Equal(t, map[string]string{"customfield_10011": "Epic Name is required."}, required.Fields)
+	assert.Contains(t, err.Error(), "missing required fields: customfield_10011 (Epic Name is required.) (status: 400")
+}
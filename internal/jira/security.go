@@ -0,0 +1,107 @@
+package jira
+
+import (
+	"fmt"
+	"strings"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// SecurityLabel marks the tickets created for GitHub security alerts.
+const SecurityLabel = "glue-security"
+
+// alertLabelPrefix starts the labels identifying the alert of a ticket.
+const alertLabelPrefix = "github-alert:"
+
+// alertKinds names the kinds of alerts in ticket summaries.
+var alertKinds = map[string]string{
+	models.AlertDependabot:   "Dependabot",
+	models.AlertCodeScanning: "Code scanning",
+}
+
+// AlertLabel returns the label identifying the ticket of an alert of a
+// repository, e.g. "github-alert:owner/repo:dependabot:12".
+func AlertLabel(repository string, alert models.SecurityAlert) string {
+	return strings.ToLower(fmt.Sprintf("%s%s:%s:%d", alertLabelPrefix, repository, alert.Kind, alert.Number))
+}
+
+// GetAlertTickets returns the keys of a project's tickets for security alerts,
+// by the label identifying their alert (see AlertLabel).
+func (c *Client) GetAlertTickets(projectKey string) (map[string]string, error) {
+	tickets, err := c.SearchTickets(fmt.Sprintf("project = %q AND labels = %q", projectKey, SecurityLabel))
+	if err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]string)
+	for _, ticket := range tickets {
+		for _, label := range ticket.Labels {
+			if strings.HasPrefix(label, alertLabelPrefix) {
+				keys[label] = ticket.Key
+			}
+		}
+	}
+	return keys, nil
+}
+
+// CreateAlertTicket creates a ticket for a security alert of a repository,
+// labeled so GetAlertTickets finds it. An empty priority leaves the priority
+// to JIRA's default. It returns the key of the new ticket.
+func (c *Client) CreateAlertTicket(projectKey, repository string, alert models.SecurityAlert, issueTypeID, priority string) (string, error) {
+	if c.client == nil {
+		return "", fmt.Errorf("jira client not initialized")
+	}
+
+	fields := &jira.IssueFields{
+		Project:     jira.Project{Key: projectKey},
+		Summary:     alertSummary(alert),
+		Description: alertDescription(repository, alert),
+		Type:        jira.IssueType{ID: issueTypeID},
+		Labels:      []string{marker.Jira.Name, SecurityLabel, AlertLabel(repository, alert)},
+	}
+	if priority != "" {
+		fields.Priority = &jira.Priority{Name: priority}
+	}
+
+	c.log().Info("creating jira ticket for security alert",
+		"project", projectKey,
+		"alert", alert.Kind,
+		"alert_number", alert.Number,
+		"priority", priority)
+
+	newIssue, err := c.createIssue(&jira.Issue{Fields: fields})
+	if err != nil {
+		return "", err
+	}
+	if newIssue == nil {
+		return "", fmt.Errorf("jira api returned nil issue")
+	}
+	return newIssue.Key, nil
+}
+
+// alertSummary returns the summary of an alert's ticket, e.g. "[Dependabot]
+// Prototype pollution in lodash".
+func alertSummary(alert models.SecurityAlert) string {
+	summary := alert.Summary
+	if summary == "" {
+		summary = fmt.Sprintf("alert #%d", alert.Number)
+	}
+	return fmt.Sprintf("[%s] %s", alertKinds[alert.Kind], summary)
+}
+
+// alertDescription returns the description of an alert's ticket.
+func alertDescription(repository string, alert models.SecurityAlert) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s alert #%d in %s\n", alertKinds[alert.Kind], alert.Number, repository)
+	fmt.Fprintf(&b, "Severity: %s\n", alert.Severity)
+	if alert.Location != "" {
+		fmt.Fprintf(&b, "Location: %s\n", alert.Location)
+	}
+	fmt.Fprintf(&b, "Alert: %s\n", alert.URL)
+	if alert.Description != "" {
+		fmt.Fprintf(&b, "\n%s\n", alert.Description)
+	}
+	return b.String()
+}
@@ -0,0 +1,60 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAlertTickets(t *testing.T) {
+	var created jira.Issue
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/rest/api/2/issue":
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&created))
+			w.WriteHeader(http.StatusCreated)
+			fmt.Fprint(w, `{"id":"1","key":"SEC-1"}`)
+		case r.URL.Path == "/rest/api/2/search":
+			assert.Equal(t, `project = "SEC" AND labels = "glue-security"`, r.URL.Query().Get("jql"))
+			fmt.Fprint(w, `{"startAt":0,"maxResults":100,"total":1,"issues":[
+				{"key":"SEC-1","fields":{"summary":"[Dependabot] Prototype pollution","labels":["glue","glue-security","github-alert:owner/repo:dependabot:12"]}}]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient, logger: slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	alert := models.SecurityAlert{
+		Kind:     models.AlertDependabot,
+		Number:   12,
+		Severity: "high",
+		Summary:  "Prototype pollution",
+		Location: "lodash (npm) in package-lock.json",
+		URL:      "https://github.com/owner/repo/security/dependabot/12",
+	}
+	key, err := client.CreateAlertTicket("SEC", "Owner/Repo", alert, "10001", "High")
+	require.NoError(t, err)
+	assert.Equal(t, "SEC-1", key)
+	assert.Equal(t, "[Dependabot] Prototype pollution", created.Fields.Summary)
+	assert.Equal(t, "High", created.Fields.Priority.Name)
+	assert.Equal(t, []string{"glue", "glue-security", "github-alert:owner/repo:dependabot:12"}, created.Fields.Labels)
+	assert.Contains(t, created.Fields.Description, "Location: lodash (npm) in package-lock.json")
+
+	tickets, err := client.GetAlertTickets("SEC")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{AlertLabel("owner/repo", alert): "SEC-1"}, tickets)
+}
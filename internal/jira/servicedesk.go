@@ -0,0 +1,186 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// serviceDeskPageSize is the number of service desks or request types
+// fetched per request.
+const serviceDeskPageSize = 50
+
+// requestType is a JIRA Service Management request type resolved for a
+// project.
+type requestType struct {
+	serviceDeskID string
+	id            string
+	name          string
+}
+
+// serviceDeskPage is a page of the JIRA Service Management REST API.
+type serviceDeskPage struct {
+	IsLastPage bool `json:"isLastPage"`
+	Values     []struct {
+		ID         string `json:"id"`
+		ProjectKey string `json:"projectKey"`
+		Name       string `json:"name"`
+	} `json:"values"`
+}
+
+// UsesRequestType reports whether tickets of a project are created as JIRA
+// Service Management requests, because jira.request_types configures a
+// request type for it. The issue type of such tickets is given by the request
+// type.
+func (c *Client) UsesRequestType(projectKey string) bool {
+	_, ok := c.requestTypes[strings.ToUpper(projectKey)]
+	return ok
+}
+
+// createRequest creates the ticket of issue as a request of the project's
+// configured request type through the servicedesk API, so the portal fields
+// and notifications of the request type apply. Labels and issue form fields
+// aren't part of the request form and are set once the request exists.
+func (c *Client) createRequest(projectKey string, issue models.GitHubIssue) (string, error) {
+	rt, err := c.requestType(projectKey)
+	if err != nil {
+		return "", err
+	}
+
+	c.log().Info("creating jira service management request",
+		"project", projectKey,
+		"title", issue.Title,
+		"request_type", rt.name)
+
+	body, formValues := c.extractFields(issue.Description)
+	description := c.composeDescription(issue, body)
+	payload := map[string]interface{}{
+		"serviceDeskId": rt.serviceDeskID,
+		"requestTypeId": rt.id,
+	}
+	if reporter := c.reporterFor(projectKey, issue); reporter != nil {
+		if reporter.AccountID != "" {
+			payload["raiseOnBehalfOf"] = reporter.AccountID
+		} else {
+			payload["raiseOnBehalfOf"] = reporter.Name
+		}
+	} else {
+		description = c.onBehalfOf(description, issue)
+	}
+	payload["requestFieldValues"] = map[string]interface{}{
+		"summary":     issue.Title,
+		"description": description,
+	}
+
+	req, err := c.client.NewRequest(http.MethodPost, "rest/servicedeskapi/request", payload)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	var created struct {
+		IssueKey string `json:"issueKey"`
+	}
+	resp, err := c.client.Do(req, &created)
+	if err != nil {
+		statusCode := 0
+		if resp != nil {
+			statusCode = resp.StatusCode
+		}
+		return "", fmt.Errorf("failed to create %s request in %s: %w (status: %d)", rt.name, projectKey, apierror.Wrap(err, statusCode), statusCode)
+	}
+	if created.IssueKey == "" {
+		return "", fmt.Errorf("jira api returned no key for the request")
+	}
+
+	fields := map[string]interface{}{"labels": []string{marker.Jira.Name}}
+	for id, value := range formValues {
+		fields[id] = value
+	}
+	if err := c.UpdateFields(created.IssueKey, fields); err != nil {
+		c.log().Warn("failed to set labels and form fields of request",
+			"key", created.IssueKey,
+			"error", err)
+	}
+
+	c.log().Info("created jira service management request", "key", created.IssueKey)
+	return created.IssueKey, nil
+}
+
+// requestType returns the configured request type of a project, looking up
+// the IDs of its service desk and of the request type, by name or ID, the
+// first time.
+func (c *Client) requestType(projectKey string) (requestType, error) {
+	projectKey = strings.ToUpper(projectKey)
+	if rt, ok := c.requestTypeCache[projectKey]; ok {
+		return rt, nil
+	}
+	configured := c.requestTypes[projectKey]
+
+	var serviceDeskID string
+	err := c.serviceDeskPages("rest/servicedeskapi/servicedesk", func(page serviceDeskPage) bool {
+		for _, desk := range page.Values {
+			if strings.EqualFold(desk.ProjectKey, projectKey) {
+				serviceDeskID = desk.ID
+				return false
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return requestType{}, fmt.Errorf("failed to get service desks: %w", err)
+	}
+	if serviceDeskID == "" {
+		return requestType{}, apierror.Invalid("jira.request_types", "%s is not a JIRA Service Management project", projectKey)
+	}
+
+	var available []string
+	var found requestType
+	err = c.serviceDeskPages(fmt.Sprintf("rest/servicedeskapi/servicedesk/%s/requesttype", serviceDeskID), func(page serviceDeskPage) bool {
+		for _, value := range page.Values {
+			if value.ID == configured || strings.EqualFold(value.Name, configured) {
+				found = requestType{serviceDeskID: serviceDeskID, id: value.ID, name: value.Name}
+				return false
+			}
+			available = append(available, value.Name)
+		}
+		return true
+	})
+	if err != nil {
+		return requestType{}, fmt.Errorf("failed to get request types of %s: %w", projectKey, err)
+	}
+	if found.id == "" {
+		return requestType{}, apierror.Invalid("jira.request_types", "request type %q not found in %s, available: %s", configured, projectKey, strings.Join(available, ", "))
+	}
+
+	if c.requestTypeCache == nil {
+		c.requestTypeCache = make(map[string]requestType)
+	}
+	c.requestTypeCache[projectKey] = found
+	return found, nil
+}
+
+// serviceDeskPages calls fn with each page of a servicedesk API listing until
+// fn returns false or the last page is reached.
+func (c *Client) serviceDeskPages(path string, fn func(serviceDeskPage) bool) error {
+	for start := 0; ; start += serviceDeskPageSize {
+		req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf("%s?start=%d&limit=%d", path, start, serviceDeskPageSize), nil)
+		if err != nil {
+			return fmt.Errorf("failed to create request: %w", err)
+		}
+		var page serviceDeskPage
+		resp, err := c.client.Do(req, &page)
+		if err != nil {
+			statusCode := 0
+			if resp != nil {
+				statusCode = resp.StatusCode
+			}
+			return fmt.Errorf("%w (status: %d)", apierror.Wrap(err, statusCode), statusCode)
+		}
+		if !fn(page) || page.IsLastPage || len(page.Values) == 0 {
+			return nil
+		}
+	}
+}
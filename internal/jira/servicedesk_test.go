@@ -0,0 +1,112 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServiceDesk serves the servicedesk API of a HELP project, with request
+// types split over two pages, and records the created request.
+type fakeServiceDesk struct {
+	request map[string]interface{}
+	fields  map[string]interface{}
+	lookups int
+}
+
+func (f *fakeServiceDesk) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	switch {
+	case r.URL.Path == "/rest/servicedeskapi/servicedesk":
+		f.lookups++
+		fmt.Fprint(w, `{"isLastPage":true,"values":[{"id":"3","projectKey":"OPS"},{"id":"7","projectKey":"HELP"}]}`)
+	case r.URL.Path == "/rest/servicedeskapi/servicedesk/7/requesttype" && r.URL.Query().Get("start") == "0":
+		fmt.Fprint(w, `{"isLastPage":false,"values":[{"id":"21","name":"Get IT help"}]}`)
+	case r.URL.Path == "/rest/servicedeskapi/servicedesk/7/requesttype":
+		fmt.Fprint(w, `{"isLastPage":true,"values":[{"id":"25","name":"Report a bug"}]}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/rest/servicedeskapi/request":
+		json.NewDecoder(r.Body).Decode(&f.request)
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprint(w, `{"issueId":"10001","issueKey":"HELP-1"}`)
+	case r.Method == http.MethodPut && r.URL.Path == "/rest/api/2/issue/HELP-1":
+		var body struct {
+			Fields map[string]interface{} `json:"fields"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		f.fields = body.Fields
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func newServiceDeskClient(t *testing.T, handler http.Handler, requestTypes map[string]string) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	return &Client{client: jiraClient, requestTypes: requestTypes}
+}
+
+func TestCreateTicketAsRequest(t *testing.T) {
+	desk := &fakeServiceDesk{}
+	client := newServiceDeskClient(t, desk, map[string]string{"HELP": "report a bug"})
+	assert.True(t, client.UsesRequestType("help"))
+	assert.False(t, client.UsesRequestType("PROJ"))
+
+	issue := models.GitHubIssue{Number: 1, Title: "Printer is on fire", Description: "It smells", Author: "octocat"}
+	key, err := client.CreateTicketWithTypeID("help", issue, "")
+	require.NoError(t, err)
+	assert.Equal(t, "HELP-1", key)
+
+	assert.Equal(t, "7", desk.request["serviceDeskId"])
+	assert.Equal(t, "25", desk.request["requestTypeId"])
+	assert.Equal(t, map[string]interface{}{
+		"summary":     "Printer is on fire",
+		"description": "It smells",
+	}, desk.request["requestFieldValues"])
+	assert.NotContains(t, desk.request, "raiseOnBehalfOf")
+	assert.Equal(t, []interface{}{"glue"}, desk.fields["labels"])
+
+	// The request type is looked up once
+	_, err = client.CreateTicketWithTypeID("HELP", issue, "")
+	require.NoError(t, err)
+	assert.Equal(t, 1, desk.lookups)
+}
+
+func TestCreateTicketAsRequestOnBehalfOfAuthor(t *testing.T) {
+	desk := &fakeServiceDesk{}
+	client := newServiceDeskClient(t, desk, map[string]string{"HELP": "25"})
+	client.SetReporterFunc(func(login string) *models.JiraUser {
+		return &models.JiraUser{AccountID: "5b10ac8d82e05b22cc7d4ef5"}
+	})
+
+	_, err := client.CreateTicketWithTypeID("HELP", models.GitHubIssue{Number: 1, Title: "Broken", Author: "octocat"}, "")
+	require.NoError(t, err)
+	assert.Equal(t, "5b10ac8d82e05b22cc7d4ef5", desk.request["raiseOnBehalfOf"])
+}
+
+func TestCreateTicketAsRequestUnknownType(t *testing.T) {
+	client := newServiceDeskClient(t, &fakeServiceDesk{}, map[string]string{"HELP": "Order a laptop"})
+
+	_, err := client.CreateTicketWithTypeID("HELP", models.GitHubIssue{Number: 1, Title: "Broken"}, "")
+	var validation *apierror.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Contains(t, err.Error(), `request type "Order a laptop" not found in HELP, available: Get IT help, Report a bug`)
+}
+
+func TestCreateTicketAsRequestNotServiceDesk(t *testing.T) {
+	client := newServiceDeskClient(t, &fakeServiceDesk{}, map[string]string{"PROJ": "Report a bug"})
+
+	_, err := client.CreateTicketWithTypeID("PROJ", models.GitHubIssue{Number: 1, Title: "Broken"}, "")
+	assert.ErrorContains(t, err, "PROJ is not a JIRA Service Management project")
+}
@@ -0,0 +1,35 @@
+package jira
+
+import (
+	"fmt"
+
+	"github.com/danielolaszy/glue/internal/marker"
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// GetBoardStats counts the tickets of a JIRA project: in total, created by
+// glue (see marker.Jira), and open versus done by status category. Tickets created before glue
+// labelled its tickets are not counted as created by glue.
+func (c *Client) GetBoardStats(projectKey string) (models.BoardStats, error) {
+	stats := models.BoardStats{ProjectKey: projectKey}
+
+	counts := []struct {
+		jql   string
+		count *int
+	}{
+		{"", &stats.Total},
+		{marker.Jira.JQL(), &stats.CreatedByGlue},
+		{"statusCategory != Done", &stats.Open},
+		{"statusCategory = Done", &stats.Done},
+	}
+
+	for _, q := range counts {
+		n, err := c.GetTotalTicketsWithOptions(projectKey, TicketCountOptions{JQL: q.jql})
+		if err != nil {
+			return models.BoardStats{}, fmt.Errorf("failed to get stats for %s: %w", projectKey, err)
+		}
+		*q.count = n
+	}
+
+	return stats, nil
+}
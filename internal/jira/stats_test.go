@@ -0,0 +1,43 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetBoardStats(t *testing.T) {
+	totals := map[string]int{
+		"project = 'PROJ'":                              120,
+		"project = 'PROJ' AND (labels = \"glue\")":      80,
+		"project = 'PROJ' AND (statusCategory != Done)": 45,
+		"project = 'PROJ' AND (statusCategory = Done)":  75,
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		total, ok := totals[r.URL.Query().Get("jql")]
+		if !ok {
+			http.Error(w, `{"errorMessages":["unexpected query"]}`, http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"startAt":0,"maxResults":1,"total":%d,"issues":[]}`, total)
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	stats, err := client.GetBoardStats("PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, models.BoardStats{ProjectKey: "PROJ", Total: 120, CreatedByGlue: 80, Open: 45, Done: 75}, stats)
+
+	_, err = (&Client{}).GetBoardStats("PROJ")
+	assert.Error(t, err)
+}
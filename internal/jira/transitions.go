@@ -0,0 +1,136 @@
+package jira
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// GetTransitions returns the workflow transitions currently available for a
+// ticket, in the order JIRA lists them.
+func (c *Client) GetTransitions(key string) ([]models.JiraTransition, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	transitions, resp, err := c.client.Issue.GetTransitions(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transitions for ticket %s: %w", key, responseError(resp, err))
+	}
+
+	result := make([]models.JiraTransition, 0, len(transitions))
+	for _, t := range transitions {
+		result = append(result, models.JiraTransition{ID: t.ID, Name: t.Name, ToStatus: t.To.Name})
+	}
+	return result, nil
+}
+
+// defaultCloseTransitions are the names of transitions that close a ticket
+// when none are configured, in order of preference.
+var defaultCloseTransitions = []string{"Done", "Close", "Closed", "Resolve", "Resolved"}
+
+// CloseTransition returns the transition CloseTicket uses for the ticket
+// among the available ones: the first of the close transitions configured
+// for the ticket's board, or else for all boards, or else the defaults, that
+// matches a transition's ID or name. Names are compared case-insensitively,
+// so localized workflows only need their names configured, e.g. "Fertig".
+func (c *Client) CloseTransition(key string, transitions []models.JiraTransition) (models.JiraTransition, bool) {
+	for _, candidate := range c.closeTransitionsFor(key) {
+		for _, t := range transitions {
+			if t.ID == candidate || strings.EqualFold(t.Name, candidate) {
+				return t, true
+			}
+		}
+	}
+	return models.JiraTransition{}, false
+}
+
+// FindTransition returns the transition among the available ones that target
+// names: a transition ID or name, or else the status a transition leads to.
+// Names are compared case-insensitively.
+func FindTransition(transitions []models.JiraTransition, target string) (models.JiraTransition, bool) {
+	for _, t := range transitions {
+		if t.ID == target || strings.EqualFold(t.Name, target) {
+			return t, true
+		}
+	}
+	for _, t := range transitions {
+		if strings.EqualFold(t.ToStatus, target) {
+			return t, true
+		}
+	}
+	return models.JiraTransition{}, false
+}
+
+// DoTransition moves a ticket through the workflow transition with the given ID.
+func (c *Client) DoTransition(key, transitionID string) error {
+	if c.client == nil {
+		return fmt.Errorf("jira client not initialized")
+	}
+
+	resp, err := c.client.Issue.DoTransition(key, transitionID)
+	if err != nil {
+		return fmt.Errorf("failed to transition ticket %s: %w", key, responseError(resp, err))
+	}
+
+	c.log().Info("transitioned jira ticket", "ticket", key, "transition_id", transitionID)
+	return nil
+}
+
+// closeTransitionsFor returns the close transition candidates for a ticket.
+func (c *Client) closeTransitionsFor(key string) []string {
+	project, _, _ := strings.Cut(key, "-")
+	if candidates := c.boardCloseTransitions[strings.ToUpper(project)]; len(candidates) > 0 {
+		return candidates
+	}
+	if len(c.closeTransitions) > 0 {
+		return c.closeTransitions
+	}
+	return defaultCloseTransitions
+}
+
+// upperKeys returns m with its keys upper-cased, as the config file's keys
+// are lower-cased when read.
+func upperKeys[V any](m map[string]V) map[string]V {
+	upper := make(map[string]V, len(m))
+	for key, value := range m {
+		upper[strings.ToUpper(key)] = value
+	}
+	return upper
+}
+
+// GetProjectStatuses returns the workflow statuses of each issue type in a
+// project.
+func (c *Client) GetProjectStatuses(projectKey string) ([]models.JiraIssueTypeStatuses, error) {
+	if c.client == nil {
+		return nil, fmt.Errorf("jira client not initialized")
+	}
+
+	req, err := c.client.NewRequest(http.MethodGet, fmt.Sprintf("rest/api/2/project/%s/statuses", projectKey), nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %v", err)
+	}
+
+	var issueTypes []struct {
+		Name     string `json:"name"`
+		Statuses []struct {
+			Name string `json:"name"`
+		} `json:"statuses"`
+	}
+	resp, err := c.client.Do(req, &issueTypes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get statuses of project %s: %w", projectKey, responseError(resp, err))
+	}
+
+	result := make([]models.JiraIssueTypeStatuses, 0, len(issueTypes))
+	for _, issueType := range issueTypes {
+		statuses := make([]string, 0, len(issueType.Statuses))
+		for _, status := range issueType.Statuses {
+			statuses = append(statuses, status.Name)
+		}
+		result = append(result, models.JiraIssueTypeStatuses{IssueType: issueType.Name, Statuses: statuses})
+	}
+	return result, nil
+}
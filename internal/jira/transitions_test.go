@@ -0,0 +1,147 @@
+package jira
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	jira "github.com/andygrunwald/go-jira"
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransitionsAndStatuses(t *testing.T) {
+	var transitioned string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions" && r.Method == http.MethodGet:
+			fmt.Fprint(w, `{"transitions":[
+				{"id":"11","name":"Start","to":{"name":"In Progress"}},
+				{"id":"41","name":"Won't Do","to":{"name":"Closed"}},
+				{"id":"31","name":"Done","to":{"name":"Done"}}]}`)
+		case r.URL.Path == "/rest/api/2/issue/PROJ-1/transitions":
+			var body struct {
+				Transition struct {
+					ID string `json:"id"`
+				} `json:"transition"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			transitioned = body.Transition.ID
+			w.WriteHeader(http.StatusNoContent)
+		case r.URL.Path == "/rest/api/2/project/PROJ/statuses":
+			fmt.Fprint(w, `[{"name":"Story","statuses":[{"name":"To Do"},{"name":"Done"}]},
+				{"name":"Feature","statuses":[{"name":"Funnel"},{"name":"Closed"}]}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	jiraClient, err := jira.NewClient(nil, server.URL)
+	require.NoError(t, err)
+	client := &Client{client: jiraClient}
+
+	transitions, err := client.GetTransitions("PROJ-1")
+	require.NoError(t, err)
+	assert.Equal(t, []models.JiraTransition{
+		{ID: "11", Name: "Start", ToStatus: "In Progress"},
+		{ID: "41", Name: "Won't Do", ToStatus: "Closed"},
+		{ID: "31", Name: "Done", ToStatus: "Done"},
+	}, transitions)
+
+	statuses, err := client.GetProjectStatuses("PROJ")
+	require.NoError(t, err)
+	assert.Equal(t, []models.JiraIssueTypeStatuses{
+		{IssueType: "Story", Statuses: []string{"To Do", "Done"}},
+		{IssueType: "Feature", Statuses: []string{"Funnel", "Closed"}},
+	}, statuses)
+
+	_, err = client.GetTransitions("PROJ-2")
+	assert.Error(t, err)
+
+	// The default picks Done; a configured transition is matched by name or ID
+	require.NoError(t, client.CloseTicket("PROJ-1"))
+	assert.Equal(t, "31", transitioned)
+
+	client.closeTransitions = []string{"99", "won't do"}
+	require.NoError(t, client.CloseTicket("PROJ-1"))
+	assert.Equal(t, "41", transitioned)
+
+	require.NoError(t, client.DoTransition("PROJ-1", "11"))
+	assert.Equal(t, "11", transitioned)
+
+	client.boardCloseTransitions = upperKeys(map[string][]string{"proj": {"Fertig", "Terminé"}})
+	err = client.CloseTicket("PROJ-1")
+	assert.ErrorContains(t, err, "none of the close transitions Fertig, Terminé is available for ticket PROJ-1")
+}
+
+func TestCloseTransition(t *testing.T) {
+	transitions := []models.JiraTransition{
+		{ID: "21", Name: "Erneut öffnen"},
+		{ID: "31", Name: "FERTIG"},
+		{ID: "41", Name: "Terminé"},
+		{ID: "51", Name: "Done"},
+	}
+	client := &Client{
+		closeTransitions:      []string{"terminé", "Done"},
+		boardCloseTransitions: upperKeys(map[string][]string{"de": {"Fertig"}, "ops": {"51"}}),
+	}
+
+	tests := []struct {
+		key    string
+		wantID string
+	}{
+		{key: "DE-1", wantID: "31"},
+		{key: "OPS-7", wantID: "51"},
+		{key: "FR-3", wantID: "41"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			transition, ok := client.CloseTransition(tt.key, transitions)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantID, transition.ID)
+		})
+	}
+
+	// Without configuration the English defaults apply
+	transition, ok := (&Client{}).CloseTransition("PROJ-1", transitions)
+	require.True(t, ok)
+	assert.Equal(t, "51", transition.ID)
+
+	_, ok = (&Client{}).CloseTransition("PROJ-1", transitions[:3])
+	assert.False(t, ok)
+}
+
+func TestFindTransition(t *testing.T) {
+	transitions := []models.JiraTransition{
+		{ID: "11", Name: "Start", ToStatus: "In Progress"},
+		{ID: "41", Name: "Won't Do", ToStatus: "Closed"},
+		{ID: "31", Name: "Close", ToStatus: "Done"},
+	}
+
+	tests := []struct {
+		target string
+		wantID string
+	}{
+		{target: "41", wantID: "41"},
+		{target: "start", wantID: "11"},
+		{target: "done", wantID: "31"},
+		// A transition name wins over a status of the same name
+		{target: "Close", wantID: "31"},
+		{target: "closed", wantID: "41"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			transition, ok := FindTransition(transitions, tt.target)
+			require.True(t, ok)
+			assert.Equal(t, tt.wantID, transition.ID)
+		})
+	}
+
+	_, ok := FindTransition(transitions, "Backlog")
+	assert.False(t, ok)
+}
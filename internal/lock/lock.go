@@ -0,0 +1,246 @@
+// Package lock provides per-repository lock files that prevent concurrent glue
+// invocations from processing the same repository at the same time.
+package lock
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// DefaultStaleAfter is the time after which a lock that wasn't refreshed is
+// considered abandoned, even if the process that created it appears to still
+// be running. Held locks are refreshed well within it, so long runs keep
+// their lock.
+const DefaultStaleAfter = 2 * time.Hour
+
+// unreadableGrace is how long an unreadable lock file is assumed to be still
+// being written by another process, rather than left over from a crash.
+const unreadableGrace = time.Minute
+
+// ErrLocked is returned when the repository is already locked by another run.
+var ErrLocked = errors.New("repository is locked by another glue process")
+
+// Lock represents an acquired repository lock file.
+type Lock struct {
+	path string
+	// stop ends the refreshing of the lock file; done is closed once it ended
+	stop chan struct{}
+	done chan struct{}
+}
+
+// holder describes the process that owns a lock file.
+type holder struct {
+	PID       int       `json:"pid"`
+	Hostname  string    `json:"hostname"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// DefaultDir returns the directory where lock files are stored, in glue's
+// cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "locks")
+}
+
+// Acquire creates a lock file for the repository inside dir. If a lock already
+// exists and is stale (not refreshed for staleAfter, or owned by a dead process
+// on this host), it is removed and acquisition is retried once. It returns
+// ErrLocked wrapped with holder details if another process holds the lock.
+// The lock file is refreshed until the lock is released.
+func Acquire(dir, repository string, staleAfter time.Duration) (*Lock, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create lock directory: %v", err)
+	}
+
+	path := filepath.Join(dir, fileName(repository))
+
+	for attempt := 1; attempt <= 2; attempt++ {
+		err := create(path)
+		if err == nil {
+			logging.Debug("acquired repository lock", "repository", repository, "path", path)
+			l := &Lock{path: path, stop: make(chan struct{}), done: make(chan struct{})}
+			go l.refresh(refreshInterval(staleAfter))
+			return l, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create lock file: %v", err)
+		}
+
+		info, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			// Released since we tried to create it
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read lock file: %v", err)
+		}
+
+		h, data, err := readHolder(path)
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			if time.Since(info.ModTime()) < unreadableGrace {
+				return nil, fmt.Errorf("%w (lock file %s is being written)", ErrLocked, path)
+			}
+			// An old lock file we cannot parse is most likely a leftover from a crash
+			logging.Warn("removing unreadable lock file", "path", path, "error", err)
+		} else if !isStale(h, info.ModTime(), staleAfter) {
+			return nil, fmt.Errorf("%w (pid %d on %s since %s, lock file %s)",
+				ErrLocked, h.PID, h.Hostname, h.CreatedAt.Format(time.RFC3339), path)
+		} else {
+			logging.Warn("removing stale repository lock",
+				"repository", repository,
+				"pid", h.PID,
+				"hostname", h.Hostname,
+				"created_at", h.CreatedAt,
+				"refreshed_at", info.ModTime())
+		}
+
+		if err := removeStale(path, info, data); err != nil {
+			return nil, err
+		}
+	}
+
+	return nil, fmt.Errorf("%w (lock file %s)", ErrLocked, path)
+}
+
+// Release stops refreshing the lock and removes the lock file. Releasing an
+// already released lock is a no-op.
+func (l *Lock) Release() error {
+	if l == nil || l.path == "" {
+		return nil
+	}
+	close(l.stop)
+	<-l.done
+	if err := os.Remove(l.path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to release lock: %v", err)
+	}
+	l.path = ""
+	return nil
+}
+
+// refresh updates the modification time of the lock file every interval
+// until the lock is released, so other processes don't consider it stale.
+func (l *Lock) refresh(interval time.Duration) {
+	defer close(l.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			if err := os.Chtimes(l.path, now, now); err != nil {
+				logging.Warn("failed to refresh repository lock", "path", l.path, "error", err)
+			}
+		}
+	}
+}
+
+// refreshInterval returns how often a lock that becomes stale after
+// staleAfter is refreshed.
+func refreshInterval(staleAfter time.Duration) time.Duration {
+	if staleAfter <= 0 {
+		staleAfter = DefaultStaleAfter
+	}
+	return staleAfter / 4
+}
+
+// create atomically creates the lock file and records the current process in
+// it. The holder is written to a temporary file first and linked into place,
+// so the lock file is never seen empty or half-written.
+func create(path string) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	hostname, _ := os.Hostname()
+	err = json.NewEncoder(f).Encode(holder{
+		PID:       os.Getpid(),
+		Hostname:  hostname,
+		CreatedAt: time.Now(),
+	})
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Link(f.Name(), path)
+}
+
+// removeStale removes the lock file at path if it is still the stale one
+// described by info with contents data. The file is moved aside first, so that
+// if another process replaced or refreshed the stale lock in the meantime, its
+// lock can be put back instead of being removed.
+func removeStale(path string, info os.FileInfo, data []byte) error {
+	aside := fmt.Sprintf("%s.%d.stale", path, os.Getpid())
+	if err := os.Rename(path, aside); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil
+		}
+		return fmt.Errorf("failed to remove stale lock file: %v", err)
+	}
+	defer os.Remove(aside)
+
+	moved, err := os.Stat(aside)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale lock file: %v", err)
+	}
+	movedData, err := os.ReadFile(aside)
+	if err != nil {
+		return fmt.Errorf("failed to remove stale lock file: %v", err)
+	}
+	if !os.SameFile(moved, info) || !moved.ModTime().Equal(info.ModTime()) || !bytes.Equal(movedData, data) {
+		if err := os.Link(aside, path); err != nil && !errors.Is(err, os.ErrExist) {
+			return fmt.Errorf("failed to restore lock file: %v", err)
+		}
+		return fmt.Errorf("%w (lock file %s)", ErrLocked, path)
+	}
+	return nil
+}
+
+// readHolder reads the owner information from an existing lock file. It also
+// returns the raw contents, which are returned even if they can't be parsed.
+func readHolder(path string) (holder, []byte, error) {
+	var h holder
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return h, nil, err
+	}
+	if err := json.Unmarshal(data, &h); err != nil {
+		return h, data, err
+	}
+	return h, data, nil
+}
+
+// isStale reports whether a lock held by h, last refreshed at refreshedAt, can
+// be safely taken over.
+func isStale(h holder, refreshedAt time.Time, staleAfter time.Duration) bool {
+	if staleAfter > 0 && time.Since(refreshedAt) > staleAfter {
+		return true
+	}
+
+	hostname, _ := os.Hostname()
+	if h.Hostname == hostname && !processAlive(h.PID) {
+		return true
+	}
+
+	return false
+}
+
+// fileName converts a repository name ("owner/repo") into a lock file name.
+func fileName(repository string) string {
+	return paths.FileName(repository) + ".lock"
+}
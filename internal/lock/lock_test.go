@@ -0,0 +1,173 @@
+package lock
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAcquireAndRelease(t *testing.T) {
+	dir := t.TempDir()
+
+	l, err := Acquire(dir, "owner/repo", DefaultStaleAfter)
+	require.NoError(t, err)
+
+	_, err = os.Stat(filepath.Join(dir, "owner_repo.lock"))
+	assert.NoError(t, err)
+
+	// A second acquisition must fail while the first lock is held
+	_, err = Acquire(dir, "owner/repo", DefaultStaleAfter)
+	assert.True(t, errors.Is(err, ErrLocked))
+
+	// Other repositories are not affected
+	other, err := Acquire(dir, "owner/other", DefaultStaleAfter)
+	require.NoError(t, err)
+	require.NoError(t, other.Release())
+
+	require.NoError(t, l.Release())
+	require.NoError(t, l.Release(), "releasing twice should be a no-op")
+
+	l, err = Acquire(dir, "owner/repo", DefaultStaleAfter)
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestAcquireStaleLock(t *testing.T) {
+	hostname, _ := os.Hostname()
+
+	tests := []struct {
+		name        string
+		holder      holder
+		refreshedAt time.Time
+		wantErr     bool
+	}{
+		{
+			name:        "Lock not refreshed within stale threshold",
+			holder:      holder{PID: os.Getpid(), Hostname: hostname, CreatedAt: time.Now().Add(-3 * time.Hour)},
+			refreshedAt: time.Now().Add(-3 * time.Hour),
+			wantErr:     false,
+		},
+		{
+			name:        "Old lock refreshed recently",
+			holder:      holder{PID: os.Getpid(), Hostname: hostname, CreatedAt: time.Now().Add(-3 * time.Hour)},
+			refreshedAt: time.Now(),
+			wantErr:     true,
+		},
+		{
+			name:    "Lock held by dead process on this host",
+			holder:  holder{PID: -1, Hostname: hostname, CreatedAt: time.Now()},
+			wantErr: false,
+		},
+		{
+			name:    "Fresh lock held by live process",
+			holder:  holder{PID: os.Getpid(), Hostname: hostname, CreatedAt: time.Now()},
+			wantErr: true,
+		},
+		{
+			name:    "Fresh lock held on another host",
+			holder:  holder{PID: -1, Hostname: "some-other-host", CreatedAt: time.Now()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			data, err := json.Marshal(tt.holder)
+			require.NoError(t, err)
+			path := filepath.Join(dir, "owner_repo.lock")
+			require.NoError(t, os.WriteFile(path, data, 0o644))
+			if !tt.refreshedAt.IsZero() {
+				require.NoError(t, os.Chtimes(path, tt.refreshedAt, tt.refreshedAt))
+			}
+
+			l, err := Acquire(dir, "owner/repo", DefaultStaleAfter)
+			if tt.wantErr {
+				assert.True(t, errors.Is(err, ErrLocked))
+				return
+			}
+			require.NoError(t, err)
+			require.NoError(t, l.Release())
+		})
+	}
+}
+
+func TestAcquireUnreadableLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owner_repo.lock")
+
+	// A young file may still be written by its holder
+	require.NoError(t, os.WriteFile(path, nil, 0o644))
+	_, err := Acquire(dir, "owner/repo", DefaultStaleAfter)
+	assert.True(t, errors.Is(err, ErrLocked))
+
+	require.NoError(t, os.WriteFile(path, []byte("garbage"), 0o644))
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+	l, err := Acquire(dir, "owner/repo", DefaultStaleAfter)
+	require.NoError(t, err)
+	require.NoError(t, l.Release())
+}
+
+func TestLockRefresh(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owner_repo.lock")
+
+	l, err := Acquire(dir, "owner/repo", 40*time.Millisecond)
+	require.NoError(t, err)
+	old := time.Now().Add(-time.Hour)
+	require.NoError(t, os.Chtimes(path, old, old))
+
+	assert.Eventually(t, func() bool {
+		info, err := os.Stat(path)
+		return err == nil && time.Since(info.ModTime()) < time.Minute
+	}, time.Second, 5*time.Millisecond)
+	require.NoError(t, l.Release())
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "no temporary files are left behind")
+}
+
+func TestRemoveStaleReplacedLock(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owner_repo.lock")
+
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0o644))
+	stale, err := os.Stat(path)
+	require.NoError(t, err)
+
+	// Another process took over the stale lock after it was judged stale
+	require.NoError(t, os.Remove(path))
+	require.NoError(t, create(path))
+
+	err = removeStale(path, stale, []byte("stale"))
+	assert.True(t, errors.Is(err, ErrLocked))
+	_, _, err = readHolder(path)
+	assert.NoError(t, err, "the new lock is put back")
+}
+
+func TestRemoveStale(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "owner_repo.lock")
+
+	require.NoError(t, os.WriteFile(path, []byte("stale"), 0o644))
+	stale, err := os.Stat(path)
+	require.NoError(t, err)
+
+	require.NoError(t, removeStale(path, stale, []byte("stale")))
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "owner_repo.lock", fileName("owner/repo"))
+	assert.Equal(t, "owner_repo.lock", fileName("Owner/Repo"))
+}
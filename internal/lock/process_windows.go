@@ -0,0 +1,19 @@
+//go:build windows
+
+package lock
+
+import "os"
+
+// processAlive reports whether a process with the given PID is running.
+// On Windows, FindProcess fails if the process does not exist.
+func processAlive(pid int) bool {
+	if pid <= 0 {
+		return false
+	}
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+	proc.Release()
+	return true
+}
@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+)
+
+// contextKey is the key type for values stored in a context by this package.
+type contextKey struct{}
+
+// NewTraceID returns a short random identifier used to correlate all log lines
+// produced while processing a single item.
+func NewTraceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return "00000000"
+	}
+	return hex.EncodeToString(b)
+}
+
+// NewContext returns a copy of ctx carrying logger.
+func NewContext(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, contextKey{}, logger)
+}
+
+// FromContext returns the logger stored in ctx, or the default logger if none is set.
+func FromContext(ctx context.Context) *slog.Logger {
+	if ctx != nil {
+		if logger, ok := ctx.Value(contextKey{}).(*slog.Logger); ok {
+			return logger
+		}
+	}
+	return defaultLogger
+}
+
+// WithTraceID returns a context carrying a logger derived from the one in ctx,
+// annotated with a new trace ID and the given attributes.
+func WithTraceID(ctx context.Context, args ...any) context.Context {
+	logger := FromContext(ctx).With(append([]any{"trace_id", NewTraceID()}, args...)...)
+	return NewContext(ctx, logger)
+}
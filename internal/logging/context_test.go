@@ -0,0 +1,38 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewTraceID(t *testing.T) {
+	id := NewTraceID()
+	assert.Regexp(t, regexp.MustCompile(`^[0-9a-f]{16}$`), id)
+	assert.NotEqual(t, id, NewTraceID())
+}
+
+func TestFromContext(t *testing.T) {
+	var buf bytes.Buffer
+	SetupLogger(&buf, LevelInfo)
+	defer SetupLogger(io.Discard, LevelInfo)
+
+	// Without a stored logger the default logger is returned
+	assert.Equal(t, GetLogger(), FromContext(context.Background()))
+
+	ctx := WithTraceID(context.Background(), "issue_number", 42)
+	FromContext(ctx).Info("creating ticket")
+	FromContext(ctx).Info("updating title")
+
+	output := buf.String()
+	assert.Contains(t, output, "issue_number=42")
+
+	ids := regexp.MustCompile(`trace_id=([0-9a-f]+)`).FindAllStringSubmatch(output, -1)
+	if assert.Len(t, ids, 2) {
+		assert.Equal(t, ids[0][1], ids[1][1], "all lines for one issue share a trace ID")
+	}
+}
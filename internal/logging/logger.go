@@ -35,33 +35,42 @@ func init() {
 		logLevelStr = string(LevelInfo)
 	}
 
-	// Set up the logger
-	SetupLogger(os.Stdout, LogLevel(logLevelStr))
+	// Log to stderr, so that output meant for files and pipes, like
+	// 'glue config schema > glue.schema.json', isn't mixed with log lines
+	SetupLogger(os.Stderr, LogLevel(logLevelStr))
+
+	// Attach optional remote sinks (syslog, HTTP log aggregator)
+	if err := setupSinksFromEnv(LogLevel(logLevelStr)); err != nil {
+		Warn("failed to configure log sinks", "error", err)
+	}
 }
 
 // SetupLogger configures the logger with the specified output and level.
 func SetupLogger(w io.Writer, level LogLevel) {
-	var logLevel slog.Level
+	opts := &slog.HandlerOptions{
+		Level: parseLevel(level),
+	}
+
+	handler := slog.NewTextHandler(w, opts)
+	defaultLogger = slog.New(handler)
+	slog.SetDefault(defaultLogger)
+}
+
+// parseLevel converts a LogLevel into the corresponding slog level.
+// Unknown values default to info.
+func parseLevel(level LogLevel) slog.Level {
 	switch level {
 	case LevelDebug:
-		logLevel = slog.LevelDebug
+		return slog.LevelDebug
 	case LevelInfo:
-		logLevel = slog.LevelInfo
+		return slog.LevelInfo
 	case LevelWarn:
-		logLevel = slog.LevelWarn
+		return slog.LevelWarn
 	case LevelError:
-		logLevel = slog.LevelError
+		return slog.LevelError
 	default:
-		logLevel = slog.LevelInfo
-	}
-
-	opts := &slog.HandlerOptions{
-		Level: logLevel,
+		return slog.LevelInfo
 	}
-
-	handler := slog.NewTextHandler(w, opts)
-	defaultLogger = slog.New(handler)
-	slog.SetDefault(defaultLogger)
 }
 
 // Debug logs a message at debug level.
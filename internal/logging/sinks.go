@@ -0,0 +1,277 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// httpBatchSize is the number of buffered records that triggers a push.
+	httpBatchSize = 100
+	// httpFlushInterval is how often buffered records are pushed regardless of size.
+	httpFlushInterval = 5 * time.Second
+)
+
+var (
+	// flushersMu guards flushers.
+	flushersMu sync.Mutex
+	// flushers holds the flush functions of buffered sinks.
+	flushers []func() error
+)
+
+// setupSinksFromEnv attaches remote log sinks configured through environment variables:
+//   - LOG_SYSLOG_ADDR: syslog destination ("local", "udp://host:514" or "tcp://host:514")
+//   - LOG_HTTP_URL: Loki-compatible push endpoint (e.g. "http://loki:3100/loki/api/v1/push")
+func setupSinksFromEnv(level LogLevel) error {
+	var errs []error
+
+	if addr := os.Getenv("LOG_SYSLOG_ADDR"); addr != "" {
+		h, err := NewSyslogHandler(addr, level)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("syslog: %v", err))
+		} else {
+			AddHandler(h)
+		}
+	}
+
+	if url := os.Getenv("LOG_HTTP_URL"); url != "" {
+		AddHandler(NewHTTPHandler(url, level))
+	}
+
+	return errors.Join(errs...)
+}
+
+// AddHandler attaches an additional handler to the default logger. Every record
+// is delivered to the existing handlers and to h.
+func AddHandler(h slog.Handler) {
+	current := defaultLogger.Handler()
+	if mh, ok := current.(*multiHandler); ok {
+		current = &multiHandler{handlers: append(append([]slog.Handler{}, mh.handlers...), h)}
+	} else {
+		current = &multiHandler{handlers: []slog.Handler{current, h}}
+	}
+	defaultLogger = slog.New(current)
+	slog.SetDefault(defaultLogger)
+}
+
+// Flush pushes any records buffered by remote sinks. It should be called
+// before the process exits.
+func Flush() error {
+	flushersMu.Lock()
+	fns := append([]func() error{}, flushers...)
+	flushersMu.Unlock()
+
+	var errs []error
+	for _, fn := range fns {
+		if err := fn(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// registerFlusher adds a flush function to be called by Flush.
+func registerFlusher(fn func() error) {
+	flushersMu.Lock()
+	defer flushersMu.Unlock()
+	flushers = append(flushers, fn)
+}
+
+// multiHandler fans out records to several handlers.
+type multiHandler struct {
+	handlers []slog.Handler
+}
+
+func (m *multiHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range m.handlers {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *multiHandler) Handle(ctx context.Context, r slog.Record) error {
+	var errs []error
+	for _, h := range m.handlers {
+		if !h.Enabled(ctx, r.Level) {
+			continue
+		}
+		if err := h.Handle(ctx, r.Clone()); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (m *multiHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	handlers := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithAttrs(attrs)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+func (m *multiHandler) WithGroup(name string) slog.Handler {
+	handlers := make([]slog.Handler, len(m.handlers))
+	for i, h := range m.handlers {
+		handlers[i] = h.WithGroup(name)
+	}
+	return &multiHandler{handlers: handlers}
+}
+
+// lineHandler renders each record as a single JSON line and passes it to emit.
+// It is the building block for sinks that deliver one message per record.
+type lineHandler struct {
+	level slog.Leveler
+	// wrap applies attributes and groups collected via WithAttrs/WithGroup
+	wrap []func(slog.Handler) slog.Handler
+	emit func(r slog.Record, line []byte) error
+}
+
+func (h *lineHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *lineHandler) Handle(ctx context.Context, r slog.Record) error {
+	var buf bytes.Buffer
+	var inner slog.Handler = slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: h.level})
+	for _, w := range h.wrap {
+		inner = w(inner)
+	}
+	if err := inner.Handle(ctx, r); err != nil {
+		return err
+	}
+	return h.emit(r, bytes.TrimRight(buf.Bytes(), "\n"))
+}
+
+func (h *lineHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithAttrs(attrs) })
+}
+
+func (h *lineHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(inner slog.Handler) slog.Handler { return inner.WithGroup(name) })
+}
+
+func (h *lineHandler) with(fn func(slog.Handler) slog.Handler) *lineHandler {
+	wrap := append(append([]func(slog.Handler) slog.Handler{}, h.wrap...), fn)
+	return &lineHandler{level: h.level, wrap: wrap, emit: h.emit}
+}
+
+// lokiPusher buffers log lines and pushes them to a Loki-compatible endpoint.
+type lokiPusher struct {
+	url    string
+	client *http.Client
+
+	mu      sync.Mutex
+	entries map[string][][2]string // level -> [timestamp, line] pairs
+	count   int
+}
+
+// lokiPush is the request body of the Loki push API.
+type lokiPush struct {
+	Streams []lokiStream `json:"streams"`
+}
+
+// lokiStream is a set of log lines sharing the same labels.
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+// NewHTTPHandler returns a handler that ships records as JSON lines to a
+// Loki-compatible push endpoint. Records are buffered and sent in batches;
+// call Flush before exiting to deliver the remainder.
+func NewHTTPHandler(url string, level LogLevel) slog.Handler {
+	p := &lokiPusher{
+		url:     url,
+		client:  &http.Client{Timeout: 10 * time.Second},
+		entries: make(map[string][][2]string),
+	}
+	registerFlusher(p.flush)
+
+	go func() {
+		ticker := time.NewTicker(httpFlushInterval)
+		defer ticker.Stop()
+		for range ticker.C {
+			if err := p.flush(); err != nil {
+				fmt.Fprintf(os.Stderr, "failed to push logs: %v\n", err)
+			}
+		}
+	}()
+
+	return &lineHandler{level: parseLevel(level), emit: p.add}
+}
+
+// add buffers a line and triggers a push once the batch is full.
+func (p *lokiPusher) add(r slog.Record, line []byte) error {
+	p.mu.Lock()
+	lvl := levelName(r.Level)
+	p.entries[lvl] = append(p.entries[lvl], [2]string{strconv.FormatInt(r.Time.UnixNano(), 10), string(line)})
+	p.count++
+	full := p.count >= httpBatchSize
+	p.mu.Unlock()
+
+	if full {
+		return p.flush()
+	}
+	return nil
+}
+
+// flush sends all buffered lines in a single push request.
+func (p *lokiPusher) flush() error {
+	p.mu.Lock()
+	if p.count == 0 {
+		p.mu.Unlock()
+		return nil
+	}
+	body := lokiPush{}
+	for lvl, values := range p.entries {
+		body.Streams = append(body.Streams, lokiStream{
+			Stream: map[string]string{"app": "glue", "level": lvl},
+			Values: values,
+		})
+	}
+	p.entries = make(map[string][][2]string)
+	p.count = 0
+	p.mu.Unlock()
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode log batch: %v", err)
+	}
+
+	resp, err := p.client.Post(p.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("failed to push log batch: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("failed to push log batch: unexpected status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// levelName returns the lower-case name used for the level label.
+func levelName(level slog.Level) string {
+	switch {
+	case level >= slog.LevelError:
+		return string(LevelError)
+	case level >= slog.LevelWarn:
+		return string(LevelWarn)
+	case level >= slog.LevelInfo:
+		return string(LevelInfo)
+	default:
+		return string(LevelDebug)
+	}
+}
@@ -0,0 +1,105 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddHandler(t *testing.T) {
+	var primary, secondary bytes.Buffer
+	SetupLogger(&primary, LevelInfo)
+	AddHandler(slog.NewTextHandler(&secondary, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	defer SetupLogger(io.Discard, LevelInfo)
+
+	Debug("debug message")
+	Info("info message", "key", "value")
+
+	assert.NotContains(t, primary.String(), "debug message")
+	assert.Contains(t, primary.String(), "info message")
+	assert.Contains(t, secondary.String(), "debug message")
+	assert.Contains(t, secondary.String(), "key=value")
+}
+
+func TestLineHandler(t *testing.T) {
+	var lines []string
+	var h slog.Handler = &lineHandler{
+		level: slog.LevelInfo,
+		emit: func(r slog.Record, line []byte) error {
+			lines = append(lines, string(line))
+			return nil
+		},
+	}
+	h = h.WithAttrs([]slog.Attr{slog.String("repository", "owner/repo")}).WithGroup("issue")
+
+	logger := slog.New(h)
+	logger.Debug("ignored")
+	logger.Info("processing", "number", 42)
+
+	require.Len(t, lines, 1)
+
+	var decoded map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &decoded))
+	assert.Equal(t, "processing", decoded["msg"])
+	assert.Equal(t, "owner/repo", decoded["repository"])
+	assert.Equal(t, map[string]any{"number": float64(42)}, decoded["issue"])
+}
+
+func TestHTTPHandler(t *testing.T) {
+	var mu sync.Mutex
+	var pushes []lokiPush
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body lokiPush
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		mu.Lock()
+		pushes = append(pushes, body)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	logger := slog.New(NewHTTPHandler(server.URL, LevelInfo))
+	logger.Info("first")
+	logger.Error("second")
+	logger.Debug("filtered")
+
+	require.NoError(t, Flush())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, pushes, 1)
+
+	levels := make(map[string]int)
+	for _, stream := range pushes[0].Streams {
+		assert.Equal(t, "glue", stream.Stream["app"])
+		levels[stream.Stream["level"]] += len(stream.Values)
+	}
+	assert.Equal(t, map[string]int{"info": 1, "error": 1}, levels)
+}
+
+func TestHTTPHandlerPushError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	logger := slog.New(NewHTTPHandler(server.URL, LevelInfo))
+	logger.Info("message")
+
+	assert.Error(t, Flush())
+	assert.NoError(t, Flush(), "buffer should be empty after a failed push")
+}
+
+func TestNewSyslogHandlerInvalidAddress(t *testing.T) {
+	_, err := NewSyslogHandler("http://example.com", LevelInfo)
+	assert.Error(t, err)
+}
@@ -0,0 +1,13 @@
+//go:build windows || plan9
+
+package logging
+
+import (
+	"errors"
+	"log/slog"
+)
+
+// NewSyslogHandler is not supported on platforms without a syslog facility.
+func NewSyslogHandler(addr string, level LogLevel) (slog.Handler, error) {
+	return nil, errors.New("syslog is not supported on this platform")
+}
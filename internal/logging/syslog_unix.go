@@ -0,0 +1,47 @@
+//go:build !windows && !plan9
+
+package logging
+
+import (
+	"fmt"
+	"log/slog"
+	"log/syslog"
+	"strings"
+)
+
+// NewSyslogHandler returns a handler that writes records as JSON lines to syslog.
+// The addr is either "local" for the local syslog daemon or a network address
+// in the form "udp://host:port" or "tcp://host:port". Record levels are mapped
+// to the corresponding syslog severities.
+func NewSyslogHandler(addr string, level LogLevel) (slog.Handler, error) {
+	network, raddr := "", ""
+	if addr != "local" {
+		parts := strings.SplitN(addr, "://", 2)
+		if len(parts) != 2 || (parts[0] != "udp" && parts[0] != "tcp") {
+			return nil, fmt.Errorf("invalid syslog address %q, expected 'local', 'udp://host:port' or 'tcp://host:port'", addr)
+		}
+		network, raddr = parts[0], parts[1]
+	}
+
+	w, err := syslog.Dial(network, raddr, syslog.LOG_INFO|syslog.LOG_USER, "glue")
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to syslog: %v", err)
+	}
+
+	return &lineHandler{
+		level: parseLevel(level),
+		emit: func(r slog.Record, line []byte) error {
+			msg := string(line)
+			switch {
+			case r.Level >= slog.LevelError:
+				return w.Err(msg)
+			case r.Level >= slog.LevelWarn:
+				return w.Warning(msg)
+			case r.Level >= slog.LevelInfo:
+				return w.Info(msg)
+			default:
+				return w.Debug(msg)
+			}
+		},
+	}, nil
+}
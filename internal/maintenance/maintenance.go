@@ -0,0 +1,244 @@
+// Package maintenance implements the maintenance_windows config option:
+// recurring periods, such as JIRA upgrades, during which glue makes no
+// changes to JIRA. Windows start on a cron schedule and last for a fixed
+// duration.
+package maintenance
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/readonly"
+)
+
+// MaxDuration is the longest a maintenance window may last.
+const MaxDuration = 7 * 24 * time.Hour
+
+// Window is a recurring maintenance window.
+type Window struct {
+	// Name identifies the window in logs and errors
+	Name string
+	// Schedule decides when the window starts
+	Schedule *Schedule
+	// Duration is how long the window lasts once started
+	Duration time.Duration
+	// Location is the time zone the schedule is evaluated in
+	Location *time.Location
+}
+
+// NewWindow returns a window starting on the cron schedule, e.g.
+// "0 2 * * SUN", that lasts for duration. The schedule is evaluated in the
+// IANA time zone, or the local time zone if timezone is empty.
+func NewWindow(name, schedule string, duration time.Duration, timezone string) (Window, error) {
+	parsed, err := Parse(schedule)
+	if err != nil {
+		return Window{}, err
+	}
+	if duration <= 0 || duration > MaxDuration {
+		return Window{}, fmt.Errorf("duration must be positive and at most %s, got %s", MaxDuration, duration)
+	}
+	location := time.Local
+	if timezone != "" {
+		location, err = time.LoadLocation(timezone)
+		if err != nil {
+			return Window{}, fmt.Errorf("invalid timezone %q: %v", timezone, err)
+		}
+	}
+	return Window{Name: name, Schedule: parsed, Duration: duration, Location: location}, nil
+}
+
+// Ends returns the end of the occurrence of the window that now falls into,
+// and false if now is outside the window.
+func (w Window) Ends(now time.Time) (time.Time, bool) {
+	local := now.In(w.Location)
+	earliest := local.Add(-w.Duration)
+	for start := local.Truncate(time.Minute); start.After(earliest); start = start.Add(-time.Minute) {
+		if w.Schedule.Matches(start) {
+			return start.Add(w.Duration), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// Windows are the configured maintenance windows.
+type Windows []Window
+
+// Active returns the window now falls into and when it ends. If windows
+// overlap, the one ending last is returned. It returns false outside all
+// windows.
+func (ws Windows) Active(now time.Time) (Window, time.Time, bool) {
+	var active Window
+	var until time.Time
+	for _, w := range ws {
+		if ends, ok := w.Ends(now); ok && ends.After(until) {
+			active, until = w, ends
+		}
+	}
+	return active, until, !until.IsZero()
+}
+
+// Transport fails requests that could change data with an error wrapping
+// apierror.ErrMaintenance while a window is active, and passes through all
+// others.
+type Transport struct {
+	// Base performs the permitted requests; nil means http.DefaultTransport
+	Base http.RoundTripper
+	// Windows are the maintenance windows
+	Windows Windows
+	// Now returns the current time; nil means time.Now
+	Now func() time.Time
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !readonly.Safe(req.Method) {
+		now := time.Now
+		if t.Now != nil {
+			now = t.Now
+		}
+		if window, until, ok := t.Windows.Active(now()); ok {
+			if req.Body != nil {
+				req.Body.Close()
+			}
+			return nil, fmt.Errorf("refusing %s %s during maintenance window %s until %s: %w",
+				req.Method, req.URL.Path, window.Name, until.Format(time.RFC3339), apierror.ErrMaintenance)
+		}
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// Schedule is a parsed cron expression with the five standard fields:
+// minute, hour, day of month, month and day of week.
+type Schedule struct {
+	minute, hour, dom, month, dow uint64
+	// domAny and dowAny are set if the day fields start with "*"; as in cron, a
+	// time matches if either day field matches when both are restricted
+	domAny, dowAny bool
+}
+
+// field describes the range of a cron field and its names, if any.
+type field struct {
+	name     string
+	min, max int
+	names    []string
+}
+
+var fields = []field{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31},
+	{name: "month", min: 1, max: 12, names: []string{"", "jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}},
+	// 7 is accepted for Sunday and folded into 0
+	{name: "day of week", min: 0, max: 7, names: []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}},
+}
+
+// Parse parses a cron expression such as "30 1 * * SAT,SUN". Fields take
+// "*", values, ranges ("1-5"), steps ("*/15", "0-30/10") and lists of them;
+// months and days of the week may be given by their three-letter names.
+func Parse(expr string) (*Schedule, error) {
+	parts := strings.Fields(expr)
+	if len(parts) != len(fields) {
+		return nil, fmt.Errorf("invalid schedule %q: expected 5 fields (minute hour day-of-month month day-of-week), got %d", expr, len(parts))
+	}
+
+	var bits [5]uint64
+	for i, part := range parts {
+		b, err := parseField(part, fields[i])
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule %q: %v", expr, err)
+		}
+		bits[i] = b
+	}
+	if bits[4]&(1<<7) != 0 {
+		bits[4] = bits[4]&^(1<<7) | 1
+	}
+
+	return &Schedule{
+		minute: bits[0],
+		hour:   bits[1],
+		dom:    bits[2],
+		month:  bits[3],
+		dow:    bits[4],
+		domAny: strings.HasPrefix(parts[2], "*"),
+		dowAny: strings.HasPrefix(parts[4], "*"),
+	}, nil
+}
+
+// Matches reports whether the schedule fires in the minute of t, in t's
+// time zone.
+func (s *Schedule) Matches(t time.Time) bool {
+	if s.minute&(1<<t.Minute()) == 0 || s.hour&(1<<t.Hour()) == 0 || s.month&(1<<int(t.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<t.Day()) != 0
+	dowMatch := s.dow&(1<<int(t.Weekday())) != 0
+	if s.domAny || s.dowAny {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// parseField returns the bit set of the values a comma-separated cron field
+// selects.
+func parseField(expr string, f field) (uint64, error) {
+	var bits uint64
+	for _, item := range strings.Split(expr, ",") {
+		rng, stepExpr, hasStep := strings.Cut(item, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepExpr)
+			if err != nil || step <= 0 {
+				return 0, fmt.Errorf("invalid step %q in %s", stepExpr, f.name)
+			}
+		}
+
+		low, high := f.min, f.max
+		if rng != "*" {
+			lowExpr, highExpr, isRange := strings.Cut(rng, "-")
+			var err error
+			if low, err = parseValue(lowExpr, f); err != nil {
+				return 0, err
+			}
+			high = low
+			if isRange {
+				if high, err = parseValue(highExpr, f); err != nil {
+					return 0, err
+				}
+			} else if hasStep {
+				high = f.max
+			}
+			if low > high {
+				return 0, fmt.Errorf("invalid range %q in %s", rng, f.name)
+			}
+		}
+
+		for v := low; v <= high; v += step {
+			bits |= 1 << v
+		}
+	}
+	return bits, nil
+}
+
+// parseValue parses a single value of a cron field, by number or name.
+func parseValue(expr string, f field) (int, error) {
+	for i, name := range f.names {
+		if name != "" && strings.EqualFold(expr, name) {
+			return i, nil
+		}
+	}
+	v, err := strconv.Atoi(expr)
+	if err != nil || v < f.min || v > f.max {
+		return 0, fmt.Errorf("invalid %s %q, expected %d-%d", f.name, expr, f.min, f.max)
+	}
+	return v, nil
+}
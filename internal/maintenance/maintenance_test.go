@@ -0,0 +1,157 @@
+package maintenance
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	// Sunday, March 3rd 2024
+	sunday := time.Date(2024, 3, 3, 2, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		expr    string
+		matches []time.Time
+		misses  []time.Time
+	}{
+		{
+			expr:    "0 2 * * SUN",
+			matches: []time.Time{sunday, sunday.AddDate(0, 0, 7)},
+			misses:  []time.Time{sunday.Add(time.Minute), sunday.AddDate(0, 0, 1), sunday.Add(time.Hour)},
+		},
+		{
+			expr:    "*/15 9-17 * * 1-5",
+			matches: []time.Time{time.Date(2024, 3, 4, 9, 45, 0, 0, time.UTC), time.Date(2024, 3, 8, 17, 0, 0, 0, time.UTC)},
+			misses:  []time.Time{time.Date(2024, 3, 4, 9, 50, 0, 0, time.UTC), time.Date(2024, 3, 4, 18, 0, 0, 0, time.UTC), sunday},
+		},
+		{
+			// Sunday as 7, names in any case
+			expr:    "0 2 * jan,Mar 7",
+			matches: []time.Time{sunday},
+			misses:  []time.Time{sunday.AddDate(0, 1, 0)},
+		},
+		{
+			// Both day fields restricted: either may match
+			expr:    "0 0 1 * MON",
+			matches: []time.Time{time.Date(2024, 3, 1, 0, 0, 0, 0, time.UTC), time.Date(2024, 3, 4, 0, 0, 0, 0, time.UTC)},
+			misses:  []time.Time{time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)},
+		},
+		{
+			expr:    "0-30/10 0 * * *",
+			matches: []time.Time{time.Date(2024, 3, 5, 0, 20, 0, 0, time.UTC)},
+			misses:  []time.Time{time.Date(2024, 3, 5, 0, 40, 0, 0, time.UTC)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expr, func(t *testing.T) {
+			schedule, err := Parse(tt.expr)
+			require.NoError(t, err)
+			for _, m := range tt.matches {
+				assert.True(t, schedule.Matches(m), m.String())
+			}
+			for _, m := range tt.misses {
+				assert.False(t, schedule.Matches(m), m.String())
+			}
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	tests := map[string]string{
+		"0 2 * *":       "expected 5 fields",
+		"60 * * * *":    `invalid minute "60", expected 0-59`,
+		"0 2 * * FUN":   `invalid day of week "FUN"`,
+		"*/0 * * * *":   `invalid step "0" in minute`,
+		"0 5-2 * * *":   `invalid range "5-2" in hour`,
+		"0 0 0 * *":     `invalid day of month "0", expected 1-31`,
+		"0 0 * 13 *":    `invalid month "13"`,
+		"0 0 * * 1,x-3": `invalid day of week "x"`,
+	}
+
+	for expr, want := range tests {
+		_, err := Parse(expr)
+		assert.ErrorContains(t, err, want, expr)
+	}
+}
+
+func TestNewWindow(t *testing.T) {
+	_, err := NewWindow("w", "0 2 * * *", 0, "")
+	assert.ErrorContains(t, err, "duration must be positive")
+
+	_, err = NewWindow("w", "0 2 * * *", 8*24*time.Hour, "")
+	assert.ErrorContains(t, err, "at most 168h0m0s")
+
+	_, err = NewWindow("w", "0 2 * * *", time.Hour, "Mars/Olympus")
+	assert.ErrorContains(t, err, `invalid timezone "Mars/Olympus"`)
+
+	window, err := NewWindow("w", "0 2 * * *", time.Hour, "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Local, window.Location)
+}
+
+func TestWindowsActive(t *testing.T) {
+	upgrade, err := NewWindow("upgrade", "0 22 * * SAT", 6*time.Hour, "Europe/Berlin")
+	require.NoError(t, err)
+	nightly, err := NewWindow("nightly", "0 2 * * *", 2*time.Hour, "UTC")
+	require.NoError(t, err)
+	windows := Windows{upgrade, nightly}
+
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	// Saturday 22:00 in Berlin is 21:00 UTC; the window spans midnight
+	window, until, ok := windows.Active(time.Date(2024, 3, 2, 21, 0, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "upgrade", window.Name)
+	assert.True(t, until.Equal(time.Date(2024, 3, 3, 4, 0, 0, 0, berlin)))
+
+	// Overlapping windows report the one ending last
+	window, until, ok = windows.Active(time.Date(2024, 3, 3, 2, 10, 0, 0, time.UTC))
+	assert.True(t, ok)
+	assert.Equal(t, "nightly", window.Name)
+	assert.True(t, until.Equal(time.Date(2024, 3, 3, 4, 0, 0, 0, time.UTC)))
+
+	_, _, ok = windows.Active(time.Date(2024, 3, 2, 20, 59, 59, 0, time.UTC))
+	assert.False(t, ok)
+	_, _, ok = windows.Active(time.Date(2024, 3, 5, 4, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+	_, _, ok = Windows(nil).Active(time.Now())
+	assert.False(t, ok)
+}
+
+func TestTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method)
+	}))
+	defer server.Close()
+
+	window, err := NewWindow("upgrade", "0 2 * * *", time.Hour, "UTC")
+	require.NoError(t, err)
+	now := time.Date(2024, 3, 1, 2, 15, 0, 0, time.UTC)
+	client := &http.Client{Transport: &Transport{Windows: Windows{window}, Now: func() time.Time { return now }}}
+
+	resp, err := client.Get(server.URL + "/rest/api/2/issue/PROJ-1")
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = client.Post(server.URL+"/rest/api/2/issue", "application/json", strings.NewReader("{}"))
+	assert.True(t, errors.Is(err, apierror.ErrMaintenance))
+	assert.Contains(t, err.Error(), "refusing POST /rest/api/2/issue during maintenance window upgrade until 2024-03-01T03:00:00Z")
+
+	now = now.Add(time.Hour)
+	resp, err = client.Post(server.URL+"/rest/api/2/issue", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	assert.Equal(t, []string{http.MethodGet, http.MethodPost}, received)
+}
@@ -0,0 +1,197 @@
+// Package mappings records the issue-to-ticket mappings of a repository that
+// broke because their JIRA ticket was deleted or moved to another project, so
+// they can be reported with a remedy instead of failing every run anew.
+package mappings
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// Reason is why a mapping broke.
+type Reason string
+
+const (
+	// Deleted means the ticket doesn't exist anymore, or isn't visible to glue
+	Deleted Reason = "deleted"
+	// Moved means the ticket answers under another key, e.g. after it was
+	// moved to another project
+	Moved Reason = "moved"
+)
+
+// Broken is a mapping to a ticket that was deleted or moved.
+type Broken struct {
+	// Key is the ticket key in the GitHub issue title
+	Key string `json:"key"`
+	// Reason is why the mapping broke
+	Reason Reason `json:"reason"`
+	// MovedTo is the current key of a moved ticket
+	MovedTo string `json:"moved_to,omitempty"`
+	// DetectedAt is when the mapping was first seen broken
+	DetectedAt time.Time `json:"detected_at"`
+}
+
+// Store holds the broken mappings of a repository. It is safe for concurrent use.
+type Store struct {
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	// Broken maps ticket keys to their broken mapping
+	Broken map[string]Broken `json:"broken"`
+	// UpdatedAt is when the store was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+
+	mu   sync.Mutex
+	path string
+}
+
+// DefaultDir returns the directory where mapping stores are kept, in glue's
+// cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "mappings")
+}
+
+// New returns an empty mapping store of a repository, stored in dir. Saving
+// it replaces any store saved before.
+func New(dir, repository string) *Store {
+	path := filepath.Join(dir, paths.FileName(repository)+".json")
+	return &Store{Repository: repository, Broken: make(map[string]Broken), path: path}
+}
+
+// Load reads the mapping store of a repository from dir. If none exists, a
+// new empty store is returned. It returns an error if the file exists but
+// cannot be read or parsed.
+func Load(dir, repository string) (*Store, error) {
+	s := New(dir, repository)
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mapping store: %v", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse mapping store %s: %v", s.path, err)
+	}
+	if s.Broken == nil {
+		s.Broken = make(map[string]Broken)
+	}
+	return s, nil
+}
+
+// Path returns the file the store is kept in.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Observe records the outcome of fetching the ticket with key: current is
+// the key it answered under, or "" if it doesn't exist. A ticket answering
+// under its own key repairs a mapping recorded as broken before.
+func (s *Store) Observe(key, current string, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	broken := Broken{Key: key, Reason: Deleted, DetectedAt: at.UTC()}
+	switch {
+	case strings.EqualFold(key, current):
+		delete(s.Broken, key)
+		return
+	case current != "":
+		broken.Reason, broken.MovedTo = Moved, current
+	}
+
+	if previous, ok := s.Broken[key]; ok && previous.Reason == broken.Reason && previous.MovedTo == broken.MovedTo {
+		return
+	}
+	s.Broken[key] = broken
+}
+
+// Forget drops the broken mapping to the ticket with key, e.g. once the
+// mapping was rewritten to the ticket's new key.
+func (s *Store) Forget(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.Broken, key)
+}
+
+// List returns the broken mappings, sorted by key.
+func (s *Store) List() []Broken {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	list := make([]Broken, 0, len(s.Broken))
+	for _, broken := range s.Broken {
+		list = append(list, broken)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Key < list[j].Key
+	})
+	return list
+}
+
+// Move moves the mapping store of repository from to repository to, e.g.
+// after the repository was renamed on GitHub. It returns false if from has no
+// store, and an error if to has one already.
+func Move(dir, from, to string) (bool, error) {
+	s, err := Load(dir, from)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	moved := New(dir, to)
+	if moved.path != s.path {
+		if _, err := os.Stat(moved.path); err == nil {
+			return false, fmt.Errorf("mapping store of %s already exists: %w", to, os.ErrExist)
+		}
+	}
+	moved.Broken = s.Broken
+	if err := moved.Save(); err != nil {
+		return false, err
+	}
+	if moved.path != s.path {
+		if err := os.Remove(s.path); err != nil {
+			return false, fmt.Errorf("failed to remove mapping store: %v", err)
+		}
+	}
+	return true, nil
+}
+
+// Save writes the store. The file is replaced atomically so a crash during
+// the write never leaves a truncated store behind.
+func (s *Store) Save() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create mapping store directory: %v", err)
+	}
+
+	s.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode mapping store: %v", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write mapping store: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write mapping store: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,100 @@
+package mappings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/repo", s.Repository)
+	assert.Empty(t, s.List())
+	assert.Equal(t, filepath.Join(dir, "owner_repo.json"), s.Path())
+}
+
+func TestObserve(t *testing.T) {
+	first := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := New(t.TempDir(), "owner/repo")
+
+	s.Observe("PROJ-1", "", first)
+	s.Observe("PROJ-1", "", first.Add(time.Hour))
+	s.Observe("PROJ-2", "NEW-7", first)
+	s.Observe("PROJ-3", "PROJ-3", first)
+	assert.Equal(t, []Broken{
+		{Key: "PROJ-1", Reason: Deleted, DetectedAt: first},
+		{Key: "PROJ-2", Reason: Moved, MovedTo: "NEW-7", DetectedAt: first},
+	}, s.List())
+
+	// A ticket answering under its own key again repairs the mapping
+	s.Observe("PROJ-1", "PROJ-1", first.Add(2*time.Hour))
+	// A moved ticket that disappeared is recorded as deleted from then on
+	s.Observe("PROJ-2", "", first.Add(2*time.Hour))
+	assert.Equal(t, []Broken{
+		{Key: "PROJ-2", Reason: Deleted, DetectedAt: first.Add(2 * time.Hour)},
+	}, s.List())
+
+	s.Forget("PROJ-2")
+	assert.Empty(t, s.List())
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s := New(dir, "owner/repo")
+	s.Observe("PROJ-2", "NEW-7", at)
+	require.NoError(t, s.Save())
+
+	loaded, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, s.List(), loaded.List())
+	assert.False(t, loaded.UpdatedAt.IsZero())
+
+	_, err = os.Stat(s.Path() + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "owner_repo.json"), []byte("{"), 0o644))
+
+	_, err := Load(dir, "owner/repo")
+	assert.Error(t, err)
+}
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	moved, err := Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	s := New(dir, "owner/old")
+	s.Observe("PROJ-1", "", at)
+	require.NoError(t, s.Save())
+
+	// A rename changing only the case keeps the file
+	moved, err = Move(dir, "owner/old", "Owner/Old")
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	moved, err = Move(dir, "Owner/Old", "owner/new")
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	loaded, err := Load(dir, "owner/new")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/new", loaded.Repository)
+	assert.Equal(t, s.List(), loaded.List())
+	_, err = os.Stat(s.Path())
+	assert.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,84 @@
+// Package marker defines how glue marks the items it synchronizes, so that
+// synced GitHub issues and glue-created JIRA tickets are recognized the same
+// way everywhere: when selecting work, detecting duplicates and computing stats.
+//
+// Each provider has its own strategy. GitHub issues carry the key of their
+// ticket as a title prefix, e.g. "[PROJ-123] Add login"; JIRA tickets created
+// by glue carry the "glue" label.
+package marker
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Marker tells from an item's title and labels whether glue marked it.
+type Marker interface {
+	// Marked reports whether an item with the given title and labels is marked.
+	Marked(title string, labels []string) bool
+}
+
+// titlePrefixPattern matches a ticket key prefix such as "[PROJ-123]".
+var titlePrefixPattern = regexp.MustCompile(`^\[([A-Z][A-Z0-9_]*-\d+)\]`)
+
+// TitlePrefix marks an item with the ticket key it is synced to, as a
+// bracketed prefix of its title.
+type TitlePrefix struct{}
+
+// Marked implements Marker.
+func (TitlePrefix) Marked(title string, labels []string) bool {
+	return titlePrefixPattern.MatchString(title)
+}
+
+// Key returns the ticket key a title is prefixed with, or an empty string if
+// it has none.
+func (TitlePrefix) Key(title string) string {
+	matches := titlePrefixPattern.FindStringSubmatch(title)
+	if len(matches) < 2 {
+		return ""
+	}
+	return matches[1]
+}
+
+// Strip returns the title without its ticket key prefix.
+func (TitlePrefix) Strip(title string) string {
+	return strings.TrimSpace(titlePrefixPattern.ReplaceAllString(title, ""))
+}
+
+// Apply returns the title prefixed with key. A title already prefixed with a
+// key is returned unchanged.
+func (p TitlePrefix) Apply(title, key string) string {
+	if p.Marked(title, nil) {
+		return title
+	}
+	return fmt.Sprintf("[%s] %s", key, title)
+}
+
+// Label marks an item with a fixed label.
+type Label struct {
+	// Name is the label, compared case-insensitively
+	Name string
+}
+
+// Marked implements Marker.
+func (l Label) Marked(title string, labels []string) bool {
+	for _, label := range labels {
+		if strings.EqualFold(label, l.Name) {
+			return true
+		}
+	}
+	return false
+}
+
+// JQL returns a JQL clause selecting the items carrying the label.
+func (l Label) JQL() string {
+	return fmt.Sprintf("labels = %q", l.Name)
+}
+
+var (
+	// GitHub marks GitHub issues synced to a ticket.
+	GitHub = TitlePrefix{}
+	// Jira marks JIRA tickets created by glue.
+	Jira = Label{Name: "glue"}
+)
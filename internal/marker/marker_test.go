@@ -0,0 +1,56 @@
+package marker
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTitlePrefix(t *testing.T) {
+	tests := []struct {
+		title string
+		key   string
+	}{
+		{"[PROJ-123] Add login", "PROJ-123"},
+		{"[GCP2-7] Migrate bucket", "GCP2-7"},
+		{"[MY_PROJ-1] Underscore key", "MY_PROJ-1"},
+		{"Add login", ""},
+		{"[WIP] Add login", ""},
+		{"[proj-123] Lowercase key", ""},
+		{"Fix [PROJ-123] later", ""},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			assert.Equal(t, tt.key, GitHub.Key(tt.title))
+			assert.Equal(t, tt.key != "", GitHub.Marked(tt.title, nil))
+		})
+	}
+}
+
+func TestTitlePrefixApply(t *testing.T) {
+	assert.Equal(t, "[PROJ-1] Add login", GitHub.Apply("Add login", "PROJ-1"))
+	assert.Equal(t, "[PROJ-1] Add login", GitHub.Apply("[PROJ-1] Add login", "PROJ-2"))
+	assert.Equal(t, "[PROJ-1] [WIP] Add login", GitHub.Apply("[WIP] Add login", "PROJ-1"))
+}
+
+func TestTitlePrefixStrip(t *testing.T) {
+	assert.Equal(t, "Add login", GitHub.Strip("[PROJ-1] Add login"))
+	assert.Equal(t, "[WIP] Add login", GitHub.Strip("[WIP] Add login"))
+	assert.Equal(t, "Fix [PROJ-1] later", GitHub.Strip("Fix [PROJ-1] later"))
+}
+
+func TestLabel(t *testing.T) {
+	assert.True(t, Jira.Marked("", []string{"backend", "glue"}))
+	assert.True(t, Jira.Marked("", []string{"Glue"}))
+	assert.False(t, Jira.Marked("[PROJ-1] Title", []string{"gluey"}))
+	assert.False(t, Jira.Marked("", nil))
+	assert.Equal(t, `labels = "glue"`, Jira.JQL())
+}
+
+func TestStrategiesImplementMarker(t *testing.T) {
+	for _, m := range []Marker{GitHub, Jira} {
+		assert.NotNil(t, m)
+	}
+}
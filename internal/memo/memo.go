@@ -0,0 +1,215 @@
+// Package memo remembers the responses to GET requests for single GitHub
+// issues and JIRA tickets for the rest of the run, since the sync, hierarchy
+// and status checks fetch the same ones repeatedly. Any other request for an
+// issue or ticket forgets its responses; any other request for anything
+// else, like a JIRA issue link, forgets all of them. A request with
+// "Cache-Control: no-cache" is always sent and forgets the responses of its
+// issue or ticket, for callers that need the current state. Long-running
+// processes call Reset between runs, and the number of remembered responses
+// is bounded.
+package memo
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+// DefaultMaxResponses is the most responses a transport remembers unless
+// configured otherwise.
+const DefaultMaxResponses = 5000
+
+var (
+	// githubIssuePath matches the paths of a GitHub issue and its subresources
+	githubIssuePath = regexp.MustCompile(`/repos/([^/]+/[^/]+)/issues/([0-9]+)(?:/|$)`)
+	// jiraIssuePath matches the paths of a JIRA ticket and its subresources
+	jiraIssuePath = regexp.MustCompile(`/rest/api/[0-9]+/issue/([^/]+)(?:/|$)`)
+)
+
+// response is a remembered response.
+type response struct {
+	status int
+	header http.Header
+	body   []byte
+}
+
+// Transport answers repeated GET requests for single issues and tickets from
+// memory. It is safe for concurrent use.
+type Transport struct {
+	// Base sends the requests that can't be answered from memory
+	Base http.RoundTripper
+	// MaxResponses is the most responses remembered; the responses remembered
+	// first are forgotten to make room for new ones. Zero means
+	// DefaultMaxResponses.
+	MaxResponses int
+
+	mu        sync.Mutex
+	responses map[string]response
+	// urls holds the remembered URLs of each issue or ticket
+	urls map[string][]string
+	// order holds the issue or ticket and URL of remembered responses, oldest
+	// first; it may hold responses forgotten since
+	order []entry
+}
+
+// entry identifies a remembered response.
+type entry struct {
+	object, url string
+}
+
+// New returns a transport remembering the responses of base.
+func New(base http.RoundTripper) *Transport {
+	return &Transport{
+		Base:      base,
+		responses: make(map[string]response),
+		urls:      make(map[string][]string),
+	}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	object := Object(req.URL.Path)
+
+	if req.Method != "" && req.Method != http.MethodGet {
+		resp, err := t.Base.RoundTrip(req)
+		t.forget(object)
+		return resp, err
+	}
+	if object == "" {
+		return t.Base.RoundTrip(req)
+	}
+
+	url := req.URL.String()
+	if strings.Contains(req.Header.Get("Cache-Control"), "no-cache") {
+		t.forget(object)
+	} else if remembered, ok := t.lookup(url); ok {
+		logging.Debug("answering request from memory", "url", req.URL.Path)
+		return remembered.to(req), nil
+	}
+
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.remember(object, url, response{status: resp.StatusCode, header: resp.Header.Clone(), body: body})
+	return resp, nil
+}
+
+// Reset forgets all remembered responses, e.g. before a long-running process
+// starts another run.
+func (t *Transport) Reset() {
+	t.forget("")
+}
+
+// lookup returns the remembered response to a URL.
+func (t *Transport) lookup(url string) (response, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	remembered, ok := t.responses[url]
+	return remembered, ok
+}
+
+// remember stores the response to a URL of an issue or ticket.
+func (t *Transport) remember(object, url string, remembered response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if _, ok := t.responses[url]; !ok {
+		t.evict()
+		t.urls[object] = append(t.urls[object], url)
+		t.order = append(t.order, entry{object: object, url: url})
+	}
+	t.responses[url] = remembered
+}
+
+// evict forgets the oldest responses until there is room for another one.
+func (t *Transport) evict() {
+	max := t.MaxResponses
+	if max <= 0 {
+		max = DefaultMaxResponses
+	}
+	for len(t.responses) >= max && len(t.order) > 0 {
+		oldest := t.order[0]
+		t.order = t.order[1:]
+		if _, ok := t.responses[oldest.url]; !ok {
+			continue
+		}
+		delete(t.responses, oldest.url)
+		var urls []string
+		for _, url := range t.urls[oldest.object] {
+			if url != oldest.url {
+				urls = append(urls, url)
+			}
+		}
+		if len(urls) == 0 {
+			delete(t.urls, oldest.object)
+		} else {
+			t.urls[oldest.object] = urls
+		}
+	}
+	if len(t.order) > 2*max {
+		// Drop the responses forgotten since they were remembered
+		order := make([]entry, 0, len(t.responses))
+		for _, e := range t.order {
+			if _, ok := t.responses[e.url]; ok {
+				order = append(order, e)
+			}
+		}
+		t.order = order
+	}
+}
+
+// forget drops the responses of an issue or ticket, or all responses if
+// object is empty.
+func (t *Transport) forget(object string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if object == "" {
+		t.responses = make(map[string]response)
+		t.urls = make(map[string][]string)
+		t.order = nil
+		return
+	}
+	for _, url := range t.urls[object] {
+		delete(t.responses, url)
+	}
+	delete(t.urls, object)
+}
+
+// to returns the remembered response as the response to req.
+func (r response) to(req *http.Request) *http.Response {
+	return &http.Response{
+		Status:        http.StatusText(r.status),
+		StatusCode:    r.status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        r.header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader(r.body)),
+		ContentLength: int64(len(r.body)),
+		Request:       req,
+	}
+}
+
+// Object returns the GitHub issue ("owner/repo#42") or JIRA ticket
+// ("PROJ-123") a URL path belongs to, or "" if it belongs to neither.
+func Object(path string) string {
+	if match := githubIssuePath.FindStringSubmatch(path); match != nil {
+		return strings.ToLower(match[1]) + "#" + match[2]
+	}
+	if match := jiraIssuePath.FindStringSubmatch(path); match != nil {
+		return strings.ToUpper(match[1])
+	}
+	return ""
+}
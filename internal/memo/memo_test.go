@@ -0,0 +1,145 @@
+package memo
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestObject(t *testing.T) {
+	tests := []struct {
+		path string
+		want string
+	}{
+		{"/repos/Owner/Repo/issues/42", "owner/repo#42"},
+		{"/api/v3/repos/owner/repo/issues/42/labels", "owner/repo#42"},
+		{"/rest/api/2/issue/proj-123", "PROJ-123"},
+		{"/rest/api/2/issue/PROJ-123/transitions", "PROJ-123"},
+		{"/repos/owner/repo/issues", ""},
+		{"/rest/api/2/search", ""},
+		{"/rest/api/2/issueLink", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.want, Object(tt.path))
+		})
+	}
+}
+
+func TestTransport(t *testing.T) {
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := r.Method + " " + r.URL.RequestURI()
+		calls[key]++
+		fmt.Fprintf(w, "%s %d", key, calls[key])
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(http.DefaultTransport)}
+	do := func(method, path string) string {
+		req, err := http.NewRequest(method, server.URL+path, nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	// Repeated gets of a ticket are answered from memory
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-1?fields=status 1", do("GET", "/rest/api/2/issue/PROJ-1?fields=status"))
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-1?fields=status 1", do("GET", "/rest/api/2/issue/PROJ-1?fields=status"))
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-2 1", do("GET", "/rest/api/2/issue/PROJ-2"))
+
+	// Searches aren't remembered
+	do("GET", "/rest/api/2/search")
+	assert.True(t, strings.HasSuffix(do("GET", "/rest/api/2/search"), " 2"))
+
+	// Changing a ticket forgets only its responses
+	do("POST", "/rest/api/2/issue/PROJ-1/transitions")
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-1?fields=status 2", do("GET", "/rest/api/2/issue/PROJ-1?fields=status"))
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-2 1", do("GET", "/rest/api/2/issue/PROJ-2"))
+
+	// Changing anything else forgets all responses
+	do("POST", "/rest/api/2/issueLink")
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-2 2", do("GET", "/rest/api/2/issue/PROJ-2"))
+
+	// Any other method forgets the responses of its ticket too
+	do("HEAD", "/rest/api/2/issue/PROJ-2")
+	assert.Equal(t, "GET /rest/api/2/issue/PROJ-2 3", do("GET", "/rest/api/2/issue/PROJ-2"))
+}
+
+func TestTransportResetAndBound(t *testing.T) {
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.RequestURI()]++
+		fmt.Fprintf(w, "%s %d", r.URL.RequestURI(), calls[r.URL.RequestURI()])
+	}))
+	defer server.Close()
+
+	transport := New(http.DefaultTransport)
+	transport.MaxResponses = 2
+	client := &http.Client{Transport: transport}
+	get := func(path string) string {
+		resp, err := client.Get(server.URL + path)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	get("/rest/api/2/issue/PROJ-1")
+	get("/rest/api/2/issue/PROJ-2")
+	transport.Reset()
+	assert.Equal(t, "/rest/api/2/issue/PROJ-1 2", get("/rest/api/2/issue/PROJ-1"))
+	assert.Equal(t, "/rest/api/2/issue/PROJ-2 2", get("/rest/api/2/issue/PROJ-2"))
+
+	// Remembering a third response forgets the oldest
+	get("/rest/api/2/issue/PROJ-3")
+	assert.Equal(t, "/rest/api/2/issue/PROJ-2 2", get("/rest/api/2/issue/PROJ-2"))
+	assert.Equal(t, "/rest/api/2/issue/PROJ-3 1", get("/rest/api/2/issue/PROJ-3"))
+	assert.Equal(t, "/rest/api/2/issue/PROJ-1 3", get("/rest/api/2/issue/PROJ-1"))
+	assert.Len(t, transport.responses, 2)
+}
+
+func TestTransportNoCache(t *testing.T) {
+	calls := make(map[string]int)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls[r.URL.RequestURI()]++
+		fmt.Fprintf(w, "%s %d", r.URL.RequestURI(), calls[r.URL.RequestURI()])
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: New(http.DefaultTransport)}
+	do := func(path string, noCache bool) string {
+		req, err := http.NewRequest(http.MethodGet, server.URL+path, nil)
+		require.NoError(t, err)
+		if noCache {
+			req.Header.Set("Cache-Control", "no-cache")
+		}
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		return string(body)
+	}
+
+	do("/rest/api/2/issue/PROJ-1", false)
+	do("/rest/api/2/issue/PROJ-2", false)
+	assert.Equal(t, "/rest/api/2/issue/PROJ-1?fields=updated 1", do("/rest/api/2/issue/PROJ-1?fields=updated", true))
+	assert.Equal(t, "/rest/api/2/issue/PROJ-1?fields=updated 2", do("/rest/api/2/issue/PROJ-1?fields=updated", true))
+
+	// The ticket's other responses are forgotten, other tickets' kept
+	assert.Equal(t, "/rest/api/2/issue/PROJ-1 2", do("/rest/api/2/issue/PROJ-1", false))
+	assert.Equal(t, "/rest/api/2/issue/PROJ-2 1", do("/rest/api/2/issue/PROJ-2", false))
+}
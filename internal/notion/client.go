@@ -0,0 +1,235 @@
+// Package notion provides functionality for interacting with the Notion API.
+package notion
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/httpdebug"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/recorder"
+)
+
+// DefaultBaseURL is the address of the Notion API.
+const DefaultBaseURL = "https://api.notion.com/v1"
+
+// DefaultRequestTimeout bounds a single Notion request.
+const DefaultRequestTimeout = 30 * time.Second
+
+// apiVersion is the Notion API version the client is written against.
+const apiVersion = "2022-06-28"
+
+// pageSize is the number of pages fetched per query.
+const pageSize = 100
+
+// Client handles interactions with the Notion API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	// Logger used for this client's output; nil means the default logger
+	logger *slog.Logger
+}
+
+// Page is a row of a Notion database.
+type Page struct {
+	ID string `json:"id"`
+	// Properties are the raw values of the row, by property name
+	Properties map[string]json.RawMessage `json:"properties"`
+}
+
+// NewClient creates a new Notion client with the token from the
+// configuration.
+func NewClient() (*Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Notion.Token == "" {
+		return nil, errors.New("missing required Notion configuration (NOTION_TOKEN)")
+	}
+
+	base, err := recorder.Wrap(http.DefaultTransport, "notion", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	base, err = faults.Wrap(base, "notion")
+	if err != nil {
+		return nil, err
+	}
+	base = httpdebug.Wrap(base, "notion")
+	if cfg.ReadOnly {
+		logging.Info("notion client is read-only, changes will be refused")
+		base = &readonly.Transport{Base: base, Reads: isQuery}
+	}
+
+	return &Client{
+		baseURL: DefaultBaseURL,
+		http: &http.Client{
+			Timeout:   DefaultRequestTimeout,
+			Transport: &tokenTransport{base: base, token: cfg.Notion.Token},
+		},
+	}, nil
+}
+
+// WithLogger returns a shallow copy of the client that writes its log output
+// to logger.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	copied := *c
+	copied.logger = logger
+	return &copied
+}
+
+// log returns the logger of the client.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
+}
+
+// PropertyTypes returns the types of the properties of a database, such as
+// "title", "rich_text" or "select", by property name.
+func (c *Client) PropertyTypes(ctx context.Context, databaseID string) (map[string]string, error) {
+	var database struct {
+		Properties map[string]struct {
+			Type string `json:"type"`
+		} `json:"properties"`
+	}
+	if err := c.send(ctx, http.MethodGet, "databases/"+databaseID, nil, &database); err != nil {
+		return nil, fmt.Errorf("failed to get database %s: %w", databaseID, err)
+	}
+
+	types := make(map[string]string, len(database.Properties))
+	for name, property := range database.Properties {
+		types[name] = property.Type
+	}
+	return types, nil
+}
+
+// Query returns all rows of a database.
+func (c *Client) Query(ctx context.Context, databaseID string) ([]Page, error) {
+	var pages []Page
+	cursor := ""
+	for {
+		body := map[string]interface{}{"page_size": pageSize}
+		if cursor != "" {
+			body["start_cursor"] = cursor
+		}
+		var result struct {
+			Results    []Page `json:"results"`
+			HasMore    bool   `json:"has_more"`
+			NextCursor string `json:"next_cursor"`
+		}
+		if err := c.send(ctx, http.MethodPost, "databases/"+databaseID+"/query", body, &result); err != nil {
+			return nil, fmt.Errorf("failed to query database %s: %w", databaseID, err)
+		}
+		pages = append(pages, result.Results...)
+		if !result.HasMore || result.NextCursor == "" {
+			return pages, nil
+		}
+		cursor = result.NextCursor
+	}
+}
+
+// CreatePage adds a row with the properties, encoded with Properties, to a
+// database and returns its ID.
+func (c *Client) CreatePage(ctx context.Context, databaseID string, properties map[string]interface{}) (string, error) {
+	body := map[string]interface{}{
+		"parent":     map[string]string{"database_id": databaseID},
+		"properties": properties,
+	}
+	var page Page
+	if err := c.send(ctx, http.MethodPost, "pages", body, &page); err != nil {
+		return "", fmt.Errorf("failed to create page: %w", err)
+	}
+	c.log().Info("created notion page", "page", page.ID)
+	return page.ID, nil
+}
+
+// UpdatePage sets the properties, encoded with Properties, of a row.
+func (c *Client) UpdatePage(ctx context.Context, pageID string, properties map[string]interface{}) error {
+	body := map[string]interface{}{"properties": properties}
+	if err := c.send(ctx, http.MethodPatch, "pages/"+pageID, body, &Page{}); err != nil {
+		return fmt.Errorf("failed to update page %s: %w", pageID, err)
+	}
+	c.log().Info("updated notion page", "page", pageID)
+	return nil
+}
+
+// send sends a request with body encoded as JSON and decodes the response
+// into out. Error responses return the message Notion gives.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.baseURL, "/")+"/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("Notion-Version", apiVersion)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apierror.Wrap(err, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(resp.Body)
+		var failure struct {
+			Message string `json:"message"`
+		}
+		message := strings.TrimSpace(string(raw))
+		if json.Unmarshal(raw, &failure) == nil && failure.Message != "" {
+			message = failure.Message
+		}
+		err := fmt.Errorf("%s %s: %s", method, req.URL.Path, message)
+		return fmt.Errorf("%w (status: %d)", apierror.Wrap(err, resp.StatusCode), resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// isQuery reports whether a request is a database query, which is sent as a
+// POST but changes nothing.
+func isQuery(req *http.Request) bool {
+	return req.Method == http.MethodPost && strings.HasSuffix(req.URL.Path, "/query")
+}
+
+// tokenTransport authenticates requests with the integration's secret.
+type tokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
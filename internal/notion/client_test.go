@@ -0,0 +1,105 @@
+package notion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeNotion serves a database whose rows span two pages and records the
+// written pages.
+type fakeNotion struct {
+	created map[string]interface{}
+	updated map[string]interface{}
+	version string
+}
+
+func (f *fakeNotion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.version = r.Header.Get("Notion-Version")
+	w.Header().Set("Content-Type", "application/json")
+
+	var body map[string]interface{}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/databases/db1":
+		fmt.Fprint(w, `{"properties":{"Name":{"type":"title"},"Status":{"type":"select"},"GitHub":{"type":"url"}}}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/databases/db1/query" && body["start_cursor"] == nil:
+		fmt.Fprint(w, `{"results":[{"id":"p1","properties":{}}],"has_more":true,"next_cursor":"c2"}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/databases/db1/query":
+		fmt.Fprint(w, `{"results":[{"id":"p2","properties":{}}],"has_more":false,"next_cursor":null}`)
+	case r.Method == http.MethodPost && r.URL.Path == "/pages":
+		f.created = body
+		fmt.Fprint(w, `{"id":"p3"}`)
+	case r.Method == http.MethodPatch && r.URL.Path == "/pages/p1":
+		f.updated = body
+		fmt.Fprint(w, `{"id":"p1"}`)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"object":"error","status":404,"code":"object_not_found","message":"Could not find database with ID: missing."}`)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{baseURL: server.URL, http: server.Client()}
+}
+
+func TestPropertyTypes(t *testing.T) {
+	fake := &fakeNotion{}
+	client := newTestClient(t, fake)
+
+	types, err := client.PropertyTypes(context.Background(), "db1")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"Name": "title", "Status": "select", "GitHub": "url"}, types)
+	assert.Equal(t, apiVersion, fake.version)
+
+	_, err = client.PropertyTypes(context.Background(), "missing")
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+	assert.ErrorContains(t, err, "Could not find database with ID: missing. (status: 404)")
+}
+
+func TestQuery(t *testing.T) {
+	client := newTestClient(t, &fakeNotion{})
+
+	pages, err := client.Query(context.Background(), "db1")
+	require.NoError(t, err)
+	require.Len(t, pages, 2)
+	assert.Equal(t, "p1", pages[0].ID)
+	assert.Equal(t, "p2", pages[1].ID)
+}
+
+func TestCreateAndUpdatePage(t *testing.T) {
+	fake := &fakeNotion{}
+	client := newTestClient(t, fake)
+	ctx := context.Background()
+	properties := map[string]interface{}{"GitHub": map[string]interface{}{"url": "https://github.com/owner/repo/issues/1"}}
+
+	id, err := client.CreatePage(ctx, "db1", properties)
+	require.NoError(t, err)
+	assert.Equal(t, "p3", id)
+	assert.Equal(t, map[string]interface{}{"database_id": "db1"}, fake.created["parent"])
+	assert.Contains(t, fake.created, "properties")
+
+	require.NoError(t, client.UpdatePage(ctx, "p1", properties))
+	assert.Contains(t, fake.updated, "properties")
+}
+
+func TestIsQuery(t *testing.T) {
+	query, err := http.NewRequest(http.MethodPost, DefaultBaseURL+"/databases/db1/query", nil)
+	require.NoError(t, err)
+	assert.True(t, isQuery(query))
+
+	create, err := http.NewRequest(http.MethodPost, DefaultBaseURL+"/pages", nil)
+	require.NoError(t, err)
+	assert.False(t, isQuery(create))
+}
@@ -0,0 +1,118 @@
+package notion
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// propertyValue is the part of a property value glue reads. Only the field of
+// the property's type is set.
+type propertyValue struct {
+	Type     string     `json:"type"`
+	Title    []richText `json:"title"`
+	RichText []richText `json:"rich_text"`
+	URL      *string    `json:"url"`
+	Select   *option    `json:"select"`
+	Status   *option    `json:"status"`
+}
+
+type richText struct {
+	PlainText string `json:"plain_text"`
+}
+
+type option struct {
+	Name string `json:"name"`
+}
+
+// Value returns the plain text of a property of the page: the text of a title
+// or rich text, a URL, or the name of a select or status option. It returns
+// an empty string for missing properties and other types.
+func (p Page) Value(name string) string {
+	raw, ok := p.Properties[name]
+	if !ok {
+		return ""
+	}
+	var value propertyValue
+	if err := json.Unmarshal(raw, &value); err != nil {
+		return ""
+	}
+
+	var text []richText
+	switch value.Type {
+	case "title":
+		text = value.Title
+	case "rich_text":
+		text = value.RichText
+	case "url":
+		if value.URL != nil {
+			return *value.URL
+		}
+	case "select":
+		if value.Select != nil {
+			return value.Select.Name
+		}
+	case "status":
+		if value.Status != nil {
+			return value.Status.Name
+		}
+	}
+
+	var b strings.Builder
+	for _, t := range text {
+		b.WriteString(t.PlainText)
+	}
+	return b.String()
+}
+
+// TitleProperty returns the name of the title property among the property
+// types of a database, or an empty string if there is none.
+func TitleProperty(types map[string]string) string {
+	for name, propertyType := range types {
+		if propertyType == "title" {
+			return name
+		}
+	}
+	return ""
+}
+
+// Properties encodes values, by property name, as the property values of a
+// page for the property types of its database. Title, rich text, URL, select
+// and status properties are supported; a missing property or another type is
+// an error naming them all. An empty value clears the property.
+func Properties(types map[string]string, values map[string]string) (map[string]interface{}, error) {
+	properties := make(map[string]interface{}, len(values))
+	var problems []string
+	for name, value := range values {
+		switch propertyType := types[name]; propertyType {
+		case "title", "rich_text":
+			text := []interface{}{}
+			if value != "" {
+				text = append(text, map[string]interface{}{"text": map[string]string{"content": value}})
+			}
+			properties[name] = map[string]interface{}{propertyType: text}
+		case "url":
+			var url interface{}
+			if value != "" {
+				url = value
+			}
+			properties[name] = map[string]interface{}{"url": url}
+		case "select", "status":
+			var choice interface{}
+			if value != "" {
+				choice = map[string]string{"name": value}
+			}
+			properties[name] = map[string]interface{}{propertyType: choice}
+		case "":
+			problems = append(problems, fmt.Sprintf("property %q not found", name))
+		default:
+			problems = append(problems, fmt.Sprintf("property %q has unsupported type %s", name, propertyType))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return nil, fmt.Errorf("invalid notion database: %s", strings.Join(problems, ", "))
+	}
+	return properties, nil
+}
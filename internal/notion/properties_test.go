@@ -0,0 +1,57 @@
+package notion
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPageValue(t *testing.T) {
+	var page Page
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"p1","properties":{
+		"Name":{"type":"title","title":[{"plain_text":"Add "},{"plain_text":"login"}]},
+		"Key":{"type":"rich_text","rich_text":[{"plain_text":"PROJ-1"}]},
+		"GitHub":{"type":"url","url":"https://github.com/owner/repo/issues/1"},
+		"Status":{"type":"select","select":{"name":"In Progress"}},
+		"Stage":{"type":"status","status":{"name":"Done"}},
+		"Empty":{"type":"select","select":null},
+		"Points":{"type":"number","number":3}
+	}}`), &page))
+
+	assert.Equal(t, "Add login", page.Value("Name"))
+	assert.Equal(t, "PROJ-1", page.Value("Key"))
+	assert.Equal(t, "https://github.com/owner/repo/issues/1", page.Value("GitHub"))
+	assert.Equal(t, "In Progress", page.Value("Status"))
+	assert.Equal(t, "Done", page.Value("Stage"))
+	assert.Equal(t, "", page.Value("Empty"))
+	assert.Equal(t, "", page.Value("Points"))
+	assert.Equal(t, "", page.Value("Missing"))
+}
+
+func TestTitleProperty(t *testing.T) {
+	assert.Equal(t, "Name", TitleProperty(map[string]string{"Status": "select", "Name": "title"}))
+	assert.Equal(t, "", TitleProperty(map[string]string{"Status": "select"}))
+}
+
+func TestProperties(t *testing.T) {
+	types := map[string]string{"Name": "title", "Key": "rich_text", "GitHub": "url", "Status": "status"}
+
+	properties, err := Properties(types, map[string]string{
+		"Name":   "Add login",
+		"Key":    "",
+		"GitHub": "https://github.com/owner/repo/issues/1",
+		"Status": "In Progress",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"Name":   map[string]interface{}{"title": []interface{}{map[string]interface{}{"text": map[string]string{"content": "Add login"}}}},
+		"Key":    map[string]interface{}{"rich_text": []interface{}{}},
+		"GitHub": map[string]interface{}{"url": "https://github.com/owner/repo/issues/1"},
+		"Status": map[string]interface{}{"status": map[string]string{"name": "In Progress"}},
+	}, properties)
+
+	_, err = Properties(map[string]string{"Points": "number"}, map[string]string{"Points": "3", "Owner": "me"})
+	assert.EqualError(t, err, `invalid notion database: property "Owner" not found, property "Points" has unsupported type number`)
+}
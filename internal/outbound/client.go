@@ -0,0 +1,191 @@
+package outbound
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/hooks"
+	"github.com/danielolaszy/glue/internal/httpdebug"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/recorder"
+)
+
+// DefaultRequestTimeout bounds a single request when outbound.timeout isn't
+// set.
+const DefaultRequestTimeout = 30 * time.Second
+
+// maxResponse is the number of bytes of an error response included in errors.
+const maxResponse = 512
+
+// templateFuncs are the functions available in body templates besides the
+// built-in ones.
+var templateFuncs = template.FuncMap{
+	// json encodes a value as JSON, e.g. {{json .Issue.Title}} for a quoted
+	// and escaped string
+	"json": func(v interface{}) (string, error) {
+		encoded, err := json.Marshal(v)
+		return string(encoded), err
+	},
+	"join":  strings.Join,
+	"lower": strings.ToLower,
+	"upper": strings.ToUpper,
+}
+
+// Client posts events to the configured endpoint.
+type Client struct {
+	url         string
+	secret      string
+	headers     map[string]string
+	contentType string
+	// template renders the request body; nil sends the event as JSON
+	template *template.Template
+	http     *http.Client
+	// Logger used for this client's output; nil means the default logger
+	logger *slog.Logger
+}
+
+// NewClient creates a new client posting to the endpoint in the
+// configuration. It returns an error if the body template doesn't parse.
+func NewClient() (*Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Outbound.URL == "" {
+		return nil, errors.New("missing required outbound configuration (OUTBOUND_URL)")
+	}
+
+	tmpl, err := ParseTemplate(cfg.Outbound.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	base, err := recorder.Wrap(http.DefaultTransport, "outbound", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	base, err = faults.Wrap(base, "outbound")
+	if err != nil {
+		return nil, err
+	}
+	base = httpdebug.Wrap(base, "outbound")
+	if cfg.ReadOnly {
+		logging.Info("outbound client is read-only, events will be refused")
+		base = &readonly.Transport{Base: base}
+	}
+
+	timeout := cfg.Outbound.Timeout
+	if timeout <= 0 {
+		timeout = DefaultRequestTimeout
+	}
+	contentType := cfg.Outbound.ContentType
+	if contentType == "" {
+		contentType = "application/json"
+	}
+
+	return &Client{
+		url:         cfg.Outbound.URL,
+		secret:      cfg.Outbound.Secret,
+		headers:     cfg.Outbound.Headers,
+		contentType: contentType,
+		template:    tmpl,
+		http:        &http.Client{Timeout: timeout, Transport: base},
+	}, nil
+}
+
+// ParseTemplate parses a body template, returning nil for an empty one.
+func ParseTemplate(text string) (*template.Template, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tmpl, err := template.New("outbound").Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, apierror.Invalid("outbound.template", "invalid outbound.template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// WithLogger returns a shallow copy of the client that writes its log output
+// to logger.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	copied := *c
+	copied.logger = logger
+	return &copied
+}
+
+// log returns the logger of the client.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
+}
+
+// Render returns the request body of an event: the template's output, or
+// the event as JSON without a template.
+func (c *Client) Render(event Event) ([]byte, error) {
+	if c.template == nil {
+		return json.Marshal(event)
+	}
+	var body bytes.Buffer
+	if err := c.template.Execute(&body, event); err != nil {
+		return nil, fmt.Errorf("failed to render outbound.template: %w", err)
+	}
+	return body.Bytes(), nil
+}
+
+// Send posts an event. With a secret the request is signed like hook
+// requests (see hooks.Sign). It returns an error including the start of the
+// response body unless the endpoint answers with a 2xx status.
+func (c *Client) Send(ctx context.Context, event Event) error {
+	body, err := c.Render(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for name, value := range c.headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set(hooks.EventHeader, event.Event)
+	if c.secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(hooks.TimestampHeader, timestamp)
+		req.Header.Set(hooks.SignatureHeader, hooks.Sign(c.secret, timestamp, body))
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apierror.Wrap(err, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponse))
+		err := fmt.Errorf("POST %s: %s", req.URL.Path, strings.TrimSpace(string(raw)))
+		return fmt.Errorf("%w (status: %d)", apierror.Wrap(err, resp.StatusCode), resp.StatusCode)
+	}
+
+	c.log().Info("sent outbound event",
+		"event", event.Event,
+		"issue_number", event.Issue.Number)
+	return nil
+}
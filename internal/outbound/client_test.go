@@ -0,0 +1,90 @@
+package outbound
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/hooks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testEvent = Event{
+	Event:      EventCreate,
+	Repository: "owner/repo",
+	Issue:      Issue{Number: 7, Title: `Fix "login"`, State: "open", Labels: []string{"bug", "outbound"}},
+	Time:       time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+}
+
+func TestRender(t *testing.T) {
+	client := &Client{}
+	body, err := client.Render(testEvent)
+	require.NoError(t, err)
+	var decoded Event
+	require.NoError(t, json.Unmarshal(body, &decoded))
+	assert.Equal(t, testEvent, decoded)
+
+	tmpl, err := ParseTemplate(`{"kind":"{{upper .Event}}","summary":{{json .Issue.Title}},"tags":"{{join .Issue.Labels ","}}"}`)
+	require.NoError(t, err)
+	client.template = tmpl
+	body, err = client.Render(testEvent)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"kind":"CREATE","summary":"Fix \"login\"","tags":"bug,outbound"}`, string(body))
+
+	tmpl, err = ParseTemplate(`{{.Issue.Missing}}`)
+	require.NoError(t, err)
+	client.template = tmpl
+	_, err = client.Render(testEvent)
+	assert.ErrorContains(t, err, "failed to render outbound.template")
+}
+
+func TestParseTemplate(t *testing.T) {
+	tmpl, err := ParseTemplate(" \n")
+	require.NoError(t, err)
+	assert.Nil(t, tmpl)
+
+	_, err = ParseTemplate(`{{.Event`)
+	var validation *apierror.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.Equal(t, "outbound.template", validation.Field)
+}
+
+func TestSend(t *testing.T) {
+	var got *http.Request
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		body, _ = io.ReadAll(r.Body)
+		if r.Header.Get("Authorization") != "Bearer abc" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte("bad token\n"))
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := &Client{
+		url:         server.URL + "/glue",
+		secret:      "s3cret",
+		headers:     map[string]string{"authorization": "Bearer abc"},
+		contentType: "application/json",
+		http:        server.Client(),
+	}
+	require.NoError(t, client.Send(context.Background(), testEvent))
+	assert.Equal(t, http.MethodPost, got.Method)
+	assert.Equal(t, "create", got.Header.Get(hooks.EventHeader))
+	assert.Equal(t, "application/json", got.Header.Get("Content-Type"))
+	timestamp := got.Header.Get(hooks.TimestampHeader)
+	assert.Equal(t, hooks.Sign("s3cret", timestamp, body), got.Header.Get(hooks.SignatureHeader))
+
+	client.headers = nil
+	client.secret = ""
+	err := client.Send(context.Background(), testEvent)
+	assert.ErrorContains(t, err, "POST /glue: bad token (status: 401)")
+	assert.Empty(t, got.Header.Get(hooks.SignatureHeader))
+}
@@ -0,0 +1,81 @@
+// Package outbound posts normalized events about GitHub issues to an endpoint
+// of the user's own, so tools glue has no client for can follow the issues.
+package outbound
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+)
+
+// Event names, sent in the event field and the hooks.EventHeader header.
+const (
+	// EventCreate is sent the first time an issue is seen
+	EventCreate = "create"
+	// EventUpdate is sent when an issue sent before changed or was reopened
+	EventUpdate = "update"
+	// EventClose is sent when an issue sent before was closed
+	EventClose = "close"
+)
+
+// Event is the payload posted for a change of an issue, and the data of the
+// body template.
+type Event struct {
+	// Event is EventCreate, EventUpdate or EventClose
+	Event string `json:"event"`
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	Issue      Issue  `json:"issue"`
+	// Time is when glue noticed the change
+	Time time.Time `json:"time"`
+}
+
+// Issue is the tracker-neutral form of a GitHub issue in events.
+type Issue struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	Body   string `json:"body"`
+	URL    string `json:"url"`
+	// State is "open" or "closed"
+	State     string   `json:"state"`
+	Type      string   `json:"type,omitempty"`
+	Author    string   `json:"author,omitempty"`
+	Labels    []string `json:"labels"`
+	Assignees []string `json:"assignees"`
+}
+
+// NewIssue returns the event form of a GitHub issue with the address url.
+func NewIssue(issue models.GitHubIssue, url string) Issue {
+	state := "open"
+	if issue.ClosedAt != nil || issue.State == "closed" {
+		state = "closed"
+	}
+	labels := append([]string{}, issue.Labels...)
+	assignees := append([]string{}, issue.Assignees...)
+	sort.Strings(labels)
+	sort.Strings(assignees)
+	return Issue{
+		Number:    issue.Number,
+		Title:     issue.Title,
+		Body:      issue.Description,
+		URL:       url,
+		State:     state,
+		Type:      issue.Type,
+		Author:    issue.Author,
+		Labels:    labels,
+		Assignees: assignees,
+	}
+}
+
+// Digest returns a fingerprint of the content of an issue, which changes
+// when anything but its state does.
+func (i Issue) Digest() string {
+	i.State = ""
+	encoded, _ := json.Marshal(i)
+	sum := sha256.Sum256(encoded)
+	return hex.EncodeToString(sum[:])
+}
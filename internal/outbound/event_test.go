@@ -0,0 +1,52 @@
+package outbound
+
+import (
+	"testing"
+	"time"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewIssue(t *testing.T) {
+	closedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	issue := NewIssue(models.GitHubIssue{
+		Number:      7,
+		Title:       "Fix login",
+		Description: "Steps",
+		Labels:      []string{"outbound", "bug"},
+		Assignees:   []string{"octocat"},
+		Author:      "janedoe",
+		Type:        "Bug",
+		ClosedAt:    &closedAt,
+	}, "https://github.com/owner/repo/issues/7")
+
+	assert.Equal(t, Issue{
+		Number:    7,
+		Title:     "Fix login",
+		Body:      "Steps",
+		URL:       "https://github.com/owner/repo/issues/7",
+		State:     "closed",
+		Type:      "Bug",
+		Author:    "janedoe",
+		Labels:    []string{"bug", "outbound"},
+		Assignees: []string{"octocat"},
+	}, issue)
+	assert.Equal(t, "open", NewIssue(models.GitHubIssue{State: "open"}, "").State)
+}
+
+func TestDigest(t *testing.T) {
+	issue := Issue{Number: 7, Title: "Fix login", State: "open", Labels: []string{"bug"}}
+
+	closed := issue
+	closed.State = "closed"
+	assert.Equal(t, issue.Digest(), closed.Digest())
+
+	renamed := issue
+	renamed.Title = "Fix logout"
+	assert.NotEqual(t, issue.Digest(), renamed.Digest())
+
+	relabeled := issue
+	relabeled.Labels = []string{"bug", "urgent"}
+	assert.NotEqual(t, issue.Digest(), relabeled.Digest())
+}
@@ -0,0 +1,106 @@
+package outbound
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// State records the issues of a repository events were sent for, so later
+// runs only send what changed.
+type State struct {
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	// Sent maps issue numbers to the last event sent for them
+	Sent map[int]Sent `json:"sent"`
+	// UpdatedAt is when the state was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+
+	path string
+}
+
+// Sent is the last event sent for an issue.
+type Sent struct {
+	// Digest is the Issue.Digest of the issue when the event was sent
+	Digest string `json:"digest"`
+	// Closed reports whether the event was EventClose
+	Closed bool `json:"closed"`
+	// At is when the event was sent
+	At time.Time `json:"at"`
+}
+
+// DefaultDir returns the directory where outbound states are stored, in
+// glue's cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "outbound")
+}
+
+// Load reads the outbound state of a repository from dir. If none exists, an
+// empty state is returned, so every open issue is sent as created. It
+// returns an error if the file exists but cannot be read or parsed.
+func Load(dir, repository string) (*State, error) {
+	s := &State{
+		Repository: repository,
+		Sent:       make(map[int]Sent),
+		path:       filepath.Join(dir, paths.FileName(repository)+".json"),
+	}
+
+	data, err := os.ReadFile(s.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outbound state: %v", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse outbound state %s: %v", s.path, err)
+	}
+	if s.Sent == nil {
+		s.Sent = make(map[int]Sent)
+	}
+	return s, nil
+}
+
+// Path returns the file the state is stored in.
+func (s *State) Path() string {
+	return s.path
+}
+
+// Record remembers an event sent for an issue.
+func (s *State) Record(event Event, at time.Time) {
+	s.Sent[event.Issue.Number] = Sent{
+		Digest: event.Issue.Digest(),
+		Closed: event.Event == EventClose,
+		At:     at.UTC(),
+	}
+}
+
+// Save writes the state. The file is replaced atomically so a crash during
+// the write never leaves a truncated state behind.
+func (s *State) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create outbound state directory: %v", err)
+	}
+
+	s.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode outbound state: %v", err)
+	}
+
+	tmp := s.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write outbound state: %v", err)
+	}
+	if err := os.Rename(tmp, s.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write outbound state: %v", err)
+	}
+	return nil
+}
@@ -0,0 +1,44 @@
+package outbound
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStateSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Empty(t, s.Sent)
+	assert.Equal(t, filepath.Join(dir, "owner_repo.json"), s.Path())
+
+	s.Record(testEvent, at)
+	closed := testEvent
+	closed.Event = EventClose
+	closed.Issue.Number = 8
+	s.Record(closed, at)
+	require.NoError(t, s.Save())
+
+	loaded, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, map[int]Sent{
+		7: {Digest: testEvent.Issue.Digest(), At: at},
+		8: {Digest: closed.Issue.Digest(), Closed: true, At: at},
+	}, loaded.Sent)
+	assert.False(t, loaded.UpdatedAt.IsZero())
+}
+
+func TestStateLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "owner_repo.json"), []byte("{"), 0o644))
+
+	_, err := Load(dir, "owner/repo")
+	assert.ErrorContains(t, err, "failed to parse outbound state")
+}
@@ -0,0 +1,46 @@
+// Package paths locates the directories glue keeps files in, so every
+// platform gets its conventional locations: for example ~/.cache/glue on
+// Linux, ~/Library/Caches/glue on macOS and %LocalAppData%\glue on Windows.
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CacheDirEnv is the environment variable overriding the cache directory.
+const CacheDirEnv = "GLUE_CACHE_DIR"
+
+// CacheDir returns the directory for locks, checkpoints and other state that
+// can be recreated: GLUE_CACHE_DIR if set, otherwise glue in the user cache
+// directory, falling back to the system temp directory.
+func CacheDir() string {
+	if dir := os.Getenv(CacheDirEnv); dir != "" {
+		return dir
+	}
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	return filepath.Join(base, "glue")
+}
+
+// ConfigDir returns glue's directory in the user config directory, e.g.
+// ~/.config/glue on Linux or %AppData%\glue on Windows.
+func ConfigDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(base, "glue"), nil
+}
+
+// unsafeChars are the characters not allowed in file names on some platform.
+var unsafeChars = strings.NewReplacer("/", "_", "\\", "_", ":", "_", "*", "_", "?", "_", "\"", "_", "<", "_", ">", "_", "|", "_")
+
+// FileName joins parts with underscores into a lower-case file name that is
+// valid on every platform, e.g. "owner_repo" for "Owner/Repo".
+func FileName(parts ...string) string {
+	return unsafeChars.Replace(strings.ToLower(strings.Join(parts, "_")))
+}
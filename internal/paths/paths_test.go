@@ -0,0 +1,28 @@
+package paths
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCacheDir(t *testing.T) {
+	t.Setenv(CacheDirEnv, "")
+	base, err := os.UserCacheDir()
+	if err != nil {
+		base = os.TempDir()
+	}
+	assert.Equal(t, filepath.Join(base, "glue"), CacheDir())
+
+	dir := t.TempDir()
+	t.Setenv(CacheDirEnv, dir)
+	assert.Equal(t, dir, CacheDir())
+}
+
+func TestFileName(t *testing.T) {
+	assert.Equal(t, "owner_repo", FileName("Owner/Repo"))
+	assert.Equal(t, "backfill_owner_repo_proj", FileName("backfill", "owner/repo", "PROJ"))
+	assert.Equal(t, "c__a_b_c_d_e_f_g_", FileName(`C:\a*b?c"d<e>f|g:`))
+}
@@ -0,0 +1,237 @@
+// Package ratelimit retries GitHub and JIRA requests the servers throttled,
+// waiting as long as they ask to. GitHub sends Retry-After with secondary
+// rate limits and X-RateLimit-Reset, a Unix time, once the primary limit is
+// used up; JIRA Cloud sends Retry-After and X-RateLimit-Reset as a timestamp.
+// Without either header, the waits grow exponentially.
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+const (
+	// DefaultMaxRetries is the number of times a throttled request is retried
+	DefaultMaxRetries = 3
+	// DefaultMaxWait is the longest wait before a retry; a server asking for
+	// longer gets its throttled response passed on instead
+	DefaultMaxWait = time.Minute
+	// baseDelay is the first wait when the server doesn't say how long to wait
+	baseDelay = time.Second
+)
+
+// Transport retries throttled requests: 429 and 503 responses, and 403
+// responses of a used up GitHub rate limit. Requests with a body that can't be
+// sent again are not retried.
+type Transport struct {
+	// Base sends the requests; nil means http.DefaultTransport
+	Base http.RoundTripper
+	// Service names the API in log messages, e.g. "github"
+	Service string
+	// MaxRetries is the number of retries; zero uses DefaultMaxRetries
+	MaxRetries int
+	// MaxWait is the longest wait before a retry; zero uses DefaultMaxWait
+	MaxWait time.Duration
+	// Timeout bounds each attempt, from sending the request until its
+	// response body is closed; the waits between attempts don't count. Zero
+	// means no bound.
+	Timeout time.Duration
+
+	// now and sleep are replaced in tests; nil uses the clock
+	now   func() time.Time
+	sleep func(*http.Request, time.Duration) error
+}
+
+// Wrap returns a transport retrying the throttled requests of base.
+func Wrap(base http.RoundTripper, service string) *Transport {
+	return &Transport{Base: base, Service: service}
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	maxRetries := t.MaxRetries
+	if maxRetries <= 0 {
+		maxRetries = DefaultMaxRetries
+	}
+	maxWait := t.MaxWait
+	if maxWait <= 0 {
+		maxWait = DefaultMaxWait
+	}
+	replayable := req.Body == nil || req.Body == http.NoBody || req.GetBody != nil
+
+	attempt := req
+	for retry := 1; ; retry++ {
+		resp, err := t.send(base, attempt)
+		if err != nil || !Throttled(resp) || retry > maxRetries || !replayable {
+			return resp, err
+		}
+
+		wait := Delay(resp, retry, t.clock())
+		if wait > maxWait {
+			logging.Warn("throttled, but the server asks to wait too long to retry",
+				"service", t.Service,
+				"path", req.URL.Path,
+				"wait", wait,
+				"max_wait", maxWait)
+			return resp, nil
+		}
+
+		next := req.Clone(req.Context())
+		if req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return resp, nil
+			}
+			next.Body = body
+		}
+		io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+
+		logging.Warn("throttled, retrying",
+			"service", t.Service,
+			"method", req.Method,
+			"path", req.URL.Path,
+			"status", resp.StatusCode,
+			"attempt", retry,
+			"wait", wait)
+		if err := t.wait(req, wait); err != nil {
+			return nil, err
+		}
+		attempt = next
+	}
+}
+
+// send sends one attempt of a request, bounded by the timeout.
+func (t *Transport) send(base http.RoundTripper, req *http.Request) (*http.Response, error) {
+	if t.Timeout <= 0 {
+		return base.RoundTrip(req)
+	}
+	ctx, cancel := context.WithTimeout(req.Context(), t.Timeout)
+	resp, err := base.RoundTrip(req.WithContext(ctx))
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+	resp.Body = &cancelBody{ReadCloser: resp.Body, cancel: cancel}
+	return resp, nil
+}
+
+// cancelBody ends the timeout of an attempt once its response body is closed.
+type cancelBody struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer.
+func (b *cancelBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	return err
+}
+
+// clock returns the current time.
+func (t *Transport) clock() time.Time {
+	if t.now != nil {
+		return t.now()
+	}
+	return time.Now()
+}
+
+// wait sleeps for d, or until the request is canceled.
+func (t *Transport) wait(req *http.Request, d time.Duration) error {
+	if t.sleep != nil {
+		return t.sleep(req, d)
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-req.Context().Done():
+		return req.Context().Err()
+	}
+}
+
+// Throttled reports whether a response refuses the request for sending too
+// many: a 429 or 503, or a 403 of GitHub once the rate limit is used up or
+// with a secondary rate limit's Retry-After.
+func Throttled(resp *http.Response) bool {
+	if resp == nil {
+		return false
+	}
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusForbidden:
+		return resp.Header.Get("X-RateLimit-Remaining") == "0" || resp.Header.Get("Retry-After") != ""
+	default:
+		return false
+	}
+}
+
+// Delay returns how long to wait before retrying a request that got resp, on
+// the given retry starting at 1. Retry-After, in seconds or as an HTTP date,
+// takes precedence over X-RateLimit-Reset, as a Unix time or an RFC 3339
+// timestamp. Without either, or for a nil resp, the delay doubles with every
+// retry, starting at one second.
+func Delay(resp *http.Response, retry int, now time.Time) time.Duration {
+	if resp != nil {
+		if wait, ok := retryAfter(resp.Header.Get("Retry-After"), now); ok {
+			return wait
+		}
+		if wait, ok := resetAt(resp.Header.Get("X-RateLimit-Reset"), now); ok {
+			return wait
+		}
+	}
+	if retry < 1 {
+		retry = 1
+	}
+	return baseDelay << (retry - 1)
+}
+
+// retryAfter parses a Retry-After header.
+func retryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return untilOrZero(at, now), true
+	}
+	return 0, false
+}
+
+// resetAt parses an X-RateLimit-Reset header.
+func resetAt(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if epoch, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return untilOrZero(time.Unix(epoch, 0), now), true
+	}
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04Z07:00"} {
+		if at, err := time.Parse(layout, value); err == nil {
+			return untilOrZero(at, now), true
+		}
+	}
+	return 0, false
+}
+
+// untilOrZero returns the time from now until at, or zero if at has passed.
+func untilOrZero(at, now time.Time) time.Duration {
+	if wait := at.Sub(now); wait > 0 {
+		return wait
+	}
+	return 0
+}
@@ -0,0 +1,165 @@
+package ratelimit
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDelay(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	header := func(pairs ...string) *http.Response {
+		resp := &http.Response{Header: http.Header{}}
+		for i := 0; i < len(pairs); i += 2 {
+			resp.Header.Set(pairs[i], pairs[i+1])
+		}
+		return resp
+	}
+
+	tests := []struct {
+		name  string
+		resp  *http.Response
+		retry int
+		want  time.Duration
+	}{
+		{"retry-after seconds", header("Retry-After", "7"), 1, 7 * time.Second},
+		{"retry-after date", header("Retry-After", "Wed, 01 May 2024 12:00:30 GMT"), 1, 30 * time.Second},
+		{"retry-after wins over reset", header("Retry-After", "2", "X-RateLimit-Reset", "1714564800"), 1, 2 * time.Second},
+		{"github reset", header("X-RateLimit-Reset", "1714564845"), 1, 45 * time.Second},
+		{"jira reset", header("X-RateLimit-Reset", "2024-05-01T12:01Z"), 1, time.Minute},
+		{"reset passed", header("X-RateLimit-Reset", "1714564000"), 1, 0},
+		{"backoff", header(), 1, time.Second},
+		{"backoff doubles", header("Retry-After", "soon"), 3, 4 * time.Second},
+		{"no response", nil, 2, 2 * time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Delay(tt.resp, tt.retry, now))
+		})
+	}
+}
+
+func TestThrottled(t *testing.T) {
+	resp := func(status int, pairs ...string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		for i := 0; i < len(pairs); i += 2 {
+			r.Header.Set(pairs[i], pairs[i+1])
+		}
+		return r
+	}
+
+	assert.True(t, Throttled(resp(http.StatusTooManyRequests)))
+	assert.True(t, Throttled(resp(http.StatusServiceUnavailable)))
+	assert.True(t, Throttled(resp(http.StatusForbidden, "X-RateLimit-Remaining", "0")))
+	assert.True(t, Throttled(resp(http.StatusForbidden, "Retry-After", "60")))
+	assert.False(t, Throttled(resp(http.StatusForbidden, "X-RateLimit-Remaining", "10")))
+	assert.False(t, Throttled(resp(http.StatusInternalServerError)))
+	assert.False(t, Throttled(nil))
+}
+
+func TestTransport(t *testing.T) {
+	var bodies []string
+	throttled := 2
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if len(bodies) <= throttled {
+			w.Header().Set("Retry-After", "3")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	var waits []time.Duration
+	transport := &Transport{Base: http.DefaultTransport, Service: "test",
+		sleep: func(_ *http.Request, d time.Duration) error {
+			waits = append(waits, d)
+			return nil
+		}}
+	client := &http.Client{Transport: transport}
+
+	t.Run("retries until accepted", func(t *testing.T) {
+		bodies, waits, throttled = nil, nil, 2
+		resp, err := client.Post(server.URL, "text/plain", strings.NewReader("payload"))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, []string{"payload", "payload", "payload"}, bodies)
+		assert.Equal(t, []time.Duration{3 * time.Second, 3 * time.Second}, waits)
+	})
+
+	t.Run("gives up after max retries", func(t *testing.T) {
+		bodies, waits, throttled = nil, nil, 10
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Len(t, bodies, DefaultMaxRetries+1)
+	})
+
+	t.Run("wait longer than max wait", func(t *testing.T) {
+		bodies, waits, throttled = nil, nil, 10
+		short := &http.Client{Transport: &Transport{MaxWait: time.Second, sleep: transport.sleep}}
+		resp, err := short.Get(server.URL)
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Len(t, bodies, 1)
+		assert.Empty(t, waits)
+	})
+
+	t.Run("canceled request", func(t *testing.T) {
+		bodies, waits, throttled = nil, nil, 10
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		_, err = (&Transport{}).RoundTrip(req)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
+
+func TestTransportTimeout(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		attempts++
+		if r.URL.Path == "/slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		if attempts == 1 {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		io.WriteString(w, "done")
+	}))
+	defer server.Close()
+
+	transport := &Transport{Base: http.DefaultTransport, Service: "test", Timeout: 100 * time.Millisecond,
+		sleep: func(_ *http.Request, d time.Duration) error {
+			// Waiting out the throttling takes longer than the timeout
+			time.Sleep(150 * time.Millisecond)
+			return nil
+		}}
+	client := &http.Client{Transport: transport}
+
+	resp, err := client.Get(server.URL + "/fast")
+	require.NoError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "done", string(body))
+	assert.Equal(t, 2, attempts)
+
+	_, err = client.Get(server.URL + "/slow")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,47 @@
+// Package readonly enforces the read_only config option. Its transport sits
+// below the API clients and refuses every request that could change data, so
+// no code path can mutate any system in read-only mode.
+package readonly
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+)
+
+// Transport passes through requests with safe methods (GET, HEAD and OPTIONS)
+// and fails all others with an error wrapping apierror.ErrReadOnly.
+type Transport struct {
+	// Base performs the permitted requests; nil means http.DefaultTransport
+	Base http.RoundTripper
+	// Reads reports whether a request with an unsafe method only reads data,
+	// such as a search sent as a POST, and is passed through; may be nil
+	Reads func(*http.Request) bool
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !Safe(req.Method) && (t.Reads == nil || !t.Reads(req)) {
+		if req.Body != nil {
+			req.Body.Close()
+		}
+		return nil, fmt.Errorf("refusing %s %s: %w", req.Method, req.URL.Path, apierror.ErrReadOnly)
+	}
+
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// Safe reports whether requests with the HTTP method don't change data.
+func Safe(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,62 @@
+package readonly
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransport(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.Method)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{}}
+
+	for _, method := range []string{http.MethodGet, http.MethodHead} {
+		req, err := http.NewRequest(method, server.URL+"/rest/api/2/issue/PROJ-1", nil)
+		require.NoError(t, err)
+		resp, err := client.Do(req)
+		require.NoError(t, err, method)
+		resp.Body.Close()
+	}
+
+	for _, method := range []string{http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete} {
+		req, err := http.NewRequest(method, server.URL+"/rest/api/2/issue", strings.NewReader("{}"))
+		require.NoError(t, err)
+		_, err = client.Do(req)
+		assert.True(t, errors.Is(err, apierror.ErrReadOnly), method)
+		assert.Contains(t, err.Error(), "refusing "+method+" /rest/api/2/issue")
+	}
+
+	assert.Equal(t, []string{http.MethodGet, http.MethodHead}, received)
+}
+
+func TestTransportReads(t *testing.T) {
+	var received []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = append(received, r.URL.Path)
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: &Transport{Reads: func(req *http.Request) bool {
+		return strings.HasSuffix(req.URL.Path, "/query")
+	}}}
+
+	resp, err := client.Post(server.URL+"/databases/1/query", "application/json", strings.NewReader("{}"))
+	require.NoError(t, err)
+	resp.Body.Close()
+
+	_, err = client.Post(server.URL+"/pages", "application/json", strings.NewReader("{}"))
+	assert.True(t, errors.Is(err, apierror.ErrReadOnly))
+
+	assert.Equal(t, []string{"/databases/1/query"}, received)
+}
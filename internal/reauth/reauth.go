@@ -0,0 +1,113 @@
+// Package reauth lets long runs survive credential rotation. Its transport
+// sits above the authenticating transports of the GitHub and JIRA clients;
+// when a request is rejected with 401 Unauthorized it reloads the credentials
+// and, if they changed, sends the request once more.
+package reauth
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+// Credentials holds a username and token that can be reloaded while in use.
+// It is safe for concurrent use.
+type Credentials struct {
+	mu       sync.RWMutex
+	username string
+	token    string
+	load     func() (string, string, error)
+}
+
+// NewCredentials returns credentials starting out as username and token.
+// load returns the current username and token, e.g. from the config file.
+func NewCredentials(username, token string, load func() (string, string, error)) *Credentials {
+	return &Credentials{username: username, token: token, load: load}
+}
+
+// Get returns the username and token.
+func (c *Credentials) Get() (string, string) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.username, c.token
+}
+
+// Reload loads the credentials again and reports whether they changed.
+// Empty credentials are ignored, so a config file in the middle of being
+// rewritten doesn't replace working ones.
+func (c *Credentials) Reload() (bool, error) {
+	username, token, err := c.load()
+	if err != nil {
+		return false, err
+	}
+	if token == "" {
+		return false, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if username == c.username && token == c.token {
+		return false, nil
+	}
+	c.username, c.token = username, token
+	return true, nil
+}
+
+// Transport retries requests rejected with 401 Unauthorized once after the
+// credentials were reloaded, if they changed. Requests whose body can't be
+// sent again are not retried.
+type Transport struct {
+	// Base authenticates requests with Credentials and sends them
+	Base http.RoundTripper
+	// Credentials are reloaded after a 401 response
+	Credentials *Credentials
+	// Service names the API in log messages, e.g. "github"
+	Service string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	usedUsername, usedToken := t.Credentials.Get()
+	resp, err := t.Base.RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return resp, nil
+	}
+
+	// Another request may have reloaded the credentials already
+	username, token := t.Credentials.Get()
+	changed := username != usedUsername || token != usedToken
+	if !changed {
+		changed, err = t.Credentials.Reload()
+		if err != nil {
+			logging.Warn("failed to reload credentials after 401 response",
+				"service", t.Service,
+				"error", err)
+			return resp, nil
+		}
+	}
+	if !changed {
+		return resp, nil
+	}
+
+	retry := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return resp, nil
+		}
+		retry.Body = body
+	}
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	logging.Info("credentials were rejected, retrying with reloaded credentials",
+		"service", t.Service,
+		"method", req.Method,
+		"path", req.URL.Path)
+	return t.Base.RoundTrip(retry)
+}
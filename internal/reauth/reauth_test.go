@@ -0,0 +1,79 @@
+package reauth
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bearerTransport authenticates requests with the token of credentials.
+type bearerTransport struct {
+	credentials *Credentials
+}
+
+func (t *bearerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	_, token := t.credentials.Get()
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token)
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func TestTransport(t *testing.T) {
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+		if r.Header.Get("Authorization") != "Bearer new" {
+			w.WriteHeader(http.StatusUnauthorized)
+		}
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		loaded      string
+		loadErr     error
+		wantStatus  int
+		wantBodies  int
+		wantCurrent string
+	}{
+		{"rotated token", "new", nil, http.StatusOK, 2, "new"},
+		{"unchanged token", "old", nil, http.StatusUnauthorized, 1, "old"},
+		{"empty token", "", nil, http.StatusUnauthorized, 1, "old"},
+		{"failed reload", "", errors.New("no config"), http.StatusUnauthorized, 1, "old"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bodies = nil
+			credentials := NewCredentials("", "old", func() (string, string, error) {
+				return "", tt.loaded, tt.loadErr
+			})
+			client := &http.Client{Transport: &Transport{
+				Base:        &bearerTransport{credentials: credentials},
+				Credentials: credentials,
+				Service:     "test",
+			}}
+
+			req, err := http.NewRequest(http.MethodPost, server.URL, strings.NewReader("payload"))
+			require.NoError(t, err)
+			resp, err := client.Do(req)
+			require.NoError(t, err)
+			resp.Body.Close()
+
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Len(t, bodies, tt.wantBodies)
+			for _, body := range bodies {
+				assert.Equal(t, "payload", body)
+			}
+			_, current := credentials.Get()
+			assert.Equal(t, tt.wantCurrent, current)
+		})
+	}
+}
@@ -0,0 +1,267 @@
+// Package recorder saves the HTTP exchanges of the GitHub and JIRA clients to
+// fixture files and serves them back later, so a run can be reproduced
+// offline from a bug report. Credentials and email addresses are removed
+// before anything is written.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/danielolaszy/glue/internal/logging"
+)
+
+const (
+	// Redacted replaces secret values in recorded exchanges
+	Redacted = "REDACTED"
+	// RedactedEmail replaces email addresses in recorded exchanges
+	RedactedEmail = "redacted@example.com"
+)
+
+// Exchange is a recorded request and its response.
+type Exchange struct {
+	Method      string      `json:"method"`
+	URL         string      `json:"url"`
+	RequestBody string      `json:"request_body,omitempty"`
+	Status      int         `json:"status"`
+	Header      http.Header `json:"header,omitempty"`
+	Body        string      `json:"body"`
+}
+
+// keptHeaders are the response headers worth recording; all others, including
+// cookies, are dropped.
+var keptHeaders = []string{"Content-Type", "Link", "Retry-After", "X-Ratelimit-Limit", "X-Ratelimit-Remaining", "X-Ratelimit-Reset"}
+
+var (
+	secretKey    = regexp.MustCompile(`(?i)token|password|secret|api_?key|signature`)
+	secretJSON   = regexp.MustCompile(`(?i)("[a-z_]*(?:token|password|secret|api_?key|signature)[a-z_]*"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+	emailAddress = regexp.MustCompile(`[A-Za-z0-9._%+\-]+@[A-Za-z0-9.\-]+\.[A-Za-z]{2,}`)
+)
+
+// Wrap returns base recording every exchange to recordDir or, when replayDir
+// is set, a transport serving the exchanges recorded there without calling
+// base. name tells apart the recordings of different clients in one
+// directory. With neither directory set, base is returned unchanged.
+func Wrap(base http.RoundTripper, name, recordDir, replayDir string) (http.RoundTripper, error) {
+	switch {
+	case replayDir != "":
+		return NewReplayer(replayDir, name)
+	case recordDir != "":
+		if err := os.MkdirAll(recordDir, 0o755); err != nil {
+			return nil, fmt.Errorf("failed to create record directory: %w", err)
+		}
+		return &Recorder{Base: base, Dir: recordDir, Name: name}, nil
+	default:
+		return base, nil
+	}
+}
+
+// Recorder is a transport that writes a sanitized copy of every exchange to
+// a numbered file in Dir.
+type Recorder struct {
+	// Base performs the requests; nil means http.DefaultTransport
+	Base http.RoundTripper
+	// Dir receives the fixture files
+	Dir string
+	// Name prefixes the fixture files, e.g. "jira" for jira-0001.json
+	Name string
+
+	mu  sync.Mutex
+	seq int
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var reqBody []byte
+	if req.Body != nil {
+		var err error
+		reqBody, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(req.Context())
+		req.Body = io.NopCloser(bytes.NewReader(reqBody))
+	}
+
+	base := r.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	exchange := Exchange{
+		Method:      req.Method,
+		URL:         SanitizeURL(req.URL),
+		RequestBody: Sanitize(string(reqBody)),
+		Status:      resp.StatusCode,
+		Header:      keepHeaders(resp.Header),
+		Body:        Sanitize(string(body)),
+	}
+	if err := r.write(exchange); err != nil {
+		logging.Warn("failed to record http exchange",
+			"method", req.Method,
+			"url", exchange.URL,
+			"error", err)
+	}
+
+	return resp, nil
+}
+
+// write saves the exchange to the next numbered file.
+func (r *Recorder) write(exchange Exchange) error {
+	data, err := json.MarshalIndent(exchange, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.seq++
+	path := filepath.Join(r.Dir, fmt.Sprintf("%s-%04d.json", r.Name, r.seq))
+	return os.WriteFile(path, data, 0o600)
+}
+
+// Replayer is a transport answering requests from recorded exchanges. Each
+// request is answered by the next unused exchange with the same method and
+// URL; once those run out, the last one is repeated.
+type Replayer struct {
+	mu        sync.Mutex
+	exchanges map[string][]Exchange
+}
+
+// NewReplayer loads the exchanges recorded under name in dir.
+func NewReplayer(dir, name string) (*Replayer, error) {
+	if _, err := os.Stat(dir); err != nil {
+		return nil, fmt.Errorf("failed to open replay directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(dir, name+"-*.json"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(paths)
+
+	r := &Replayer{exchanges: make(map[string][]Exchange)}
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording: %w", err)
+		}
+		var exchange Exchange
+		if err := json.Unmarshal(data, &exchange); err != nil {
+			return nil, fmt.Errorf("invalid recording %s: %w", path, err)
+		}
+		key := exchangeKey(exchange.Method, exchange.URL)
+		r.exchanges[key] = append(r.exchanges[key], exchange)
+	}
+
+	logging.Info("replaying recorded http exchanges",
+		"dir", dir,
+		"client", name,
+		"count", len(paths))
+	return r, nil
+}
+
+// RoundTrip implements http.RoundTripper.
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+
+	key := exchangeKey(req.Method, SanitizeURL(req.URL))
+	r.mu.Lock()
+	queue := r.exchanges[key]
+	if len(queue) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("no recorded response for %s", key)
+	}
+	exchange := queue[0]
+	if len(queue) > 1 {
+		r.exchanges[key] = queue[1:]
+	}
+	r.mu.Unlock()
+
+	header := exchange.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", exchange.Status, http.StatusText(exchange.Status)),
+		StatusCode:    exchange.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(exchange.Body)),
+		ContentLength: int64(len(exchange.Body)),
+		Request:       req,
+	}, nil
+}
+
+// exchangeKey identifies the requests an exchange answers. The host is left
+// out so recordings replay against any base URL.
+func exchangeKey(method, rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		rawURL = u.RequestURI()
+	}
+	return method + " " + rawURL
+}
+
+// SanitizeURL returns the URL without user info and with the values of
+// secret-looking query parameters and email addresses redacted.
+func SanitizeURL(u *url.URL) string {
+	clean := *u
+	clean.User = nil
+
+	query := clean.Query()
+	for key, values := range query {
+		for i, value := range values {
+			if secretKey.MatchString(key) {
+				values[i] = Redacted
+			} else {
+				values[i] = emailAddress.ReplaceAllString(value, RedactedEmail)
+			}
+		}
+		query[key] = values
+	}
+	clean.RawQuery = query.Encode()
+	return clean.String()
+}
+
+// Sanitize redacts the values of secret-looking JSON keys and email
+// addresses in a request or response body.
+func Sanitize(body string) string {
+	body = secretJSON.ReplaceAllString(body, `$1"`+Redacted+`"`)
+	return emailAddress.ReplaceAllString(body, RedactedEmail)
+}
+
+// keepHeaders returns the recorded subset of the response headers.
+func keepHeaders(header http.Header) http.Header {
+	kept := make(http.Header)
+	for _, name := range keptHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			kept[http.CanonicalHeaderKey(name)] = values
+		}
+	}
+	return kept
+}
@@ -0,0 +1,224 @@
+// Package rules evaluates user-provided Starlark scripts that decide how GitHub
+// issues are mapped to JIRA, replacing the fixed label heuristics where needed.
+//
+// A script defines a function decide(issue) that receives the issue as a dict
+// with the keys number, title, body, state, labels and issue_type (the native
+// GitHub issue type, or "" if it has none). It returns None to keep the
+// default behaviour, or a dict with any of these keys:
+//
+//	skip:   True to leave the issue out of the sync
+//	boards: list of JIRA project keys the issue is routed to
+//	type:   "feature" or "story"
+//	fields: dict of JIRA field IDs to values set on newly created tickets
+package rules
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+)
+
+// entryPoint is the name of the function a rules script must define.
+const entryPoint = "decide"
+
+// maxSteps bounds the work of a single decision so a runaway script can't hang a sync.
+const maxSteps = 1_000_000
+
+// Decision is the outcome of a rules script for a single issue. Zero values
+// mean the default behaviour applies.
+type Decision struct {
+	// Skip leaves the issue out of the sync
+	Skip bool
+	// Boards routes the issue to these boards instead of its labels, if non-nil
+	Boards []string
+	// Type overrides the issue type derived from the native type or labels
+	// ("feature" or "story")
+	Type string
+	// Fields are set on the ticket after it is created
+	Fields map[string]interface{}
+}
+
+// Engine evaluates a loaded rules script.
+type Engine struct {
+	decide starlark.Callable
+}
+
+// Load compiles and executes a rules script. The filename is used in error
+// messages; src is the script source. It returns an error if the script fails
+// to run or doesn't define a decide function.
+func Load(filename string, src string) (*Engine, error) {
+	thread := &starlark.Thread{Name: "load " + filename}
+	thread.SetMaxExecutionSteps(maxSteps)
+
+	globals, err := starlark.ExecFileOptions(&syntax.FileOptions{}, thread, filename, src, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load rules script %s: %v", filename, err)
+	}
+
+	decide, ok := globals[entryPoint].(starlark.Callable)
+	if !ok {
+		return nil, fmt.Errorf("rules script %s must define a %s(issue) function", filename, entryPoint)
+	}
+
+	return &Engine{decide: decide}, nil
+}
+
+// Decide runs the script for an issue. It returns an error if the script fails
+// or returns a value that isn't None or a valid decision dict.
+func (e *Engine) Decide(issue models.GitHubIssue) (Decision, error) {
+	thread := &starlark.Thread{Name: fmt.Sprintf("decide #%d", issue.Number)}
+	thread.SetMaxExecutionSteps(maxSteps)
+
+	result, err := starlark.Call(thread, e.decide, starlark.Tuple{issueValue(issue)}, nil)
+	if err != nil {
+		return Decision{}, fmt.Errorf("rules script failed for issue #%d: %v", issue.Number, err)
+	}
+
+	decision, err := toDecision(result)
+	if err != nil {
+		return Decision{}, fmt.Errorf("invalid decision for issue #%d: %v", issue.Number, err)
+	}
+	return decision, nil
+}
+
+// issueValue converts an issue to the dict passed to decide.
+func issueValue(issue models.GitHubIssue) *starlark.Dict {
+	labels := make([]starlark.Value, 0, len(issue.Labels))
+	for _, label := range issue.Labels {
+		labels = append(labels, starlark.String(label))
+	}
+
+	dict := starlark.NewDict(6)
+	_ = dict.SetKey(starlark.String("number"), starlark.MakeInt(issue.Number))
+	_ = dict.SetKey(starlark.String("title"), starlark.String(issue.Title))
+	_ = dict.SetKey(starlark.String("body"), starlark.String(issue.Description))
+	_ = dict.SetKey(starlark.String("state"), starlark.String(issue.State))
+	_ = dict.SetKey(starlark.String("labels"), starlark.NewList(labels))
+	_ = dict.SetKey(starlark.String("issue_type"), starlark.String(issue.Type))
+	return dict
+}
+
+// toDecision converts the value returned by decide to a Decision.
+func toDecision(value starlark.Value) (Decision, error) {
+	var decision Decision
+	if value == starlark.None {
+		return decision, nil
+	}
+
+	dict, ok := value.(*starlark.Dict)
+	if !ok {
+		return decision, fmt.Errorf("expected None or dict, got %s", value.Type())
+	}
+
+	for _, item := range dict.Items() {
+		key, ok := starlark.AsString(item[0])
+		if !ok {
+			return decision, fmt.Errorf("decision keys must be strings, got %s", item[0].Type())
+		}
+
+		switch key {
+		case "skip":
+			decision.Skip = bool(item[1].Truth())
+		case "boards":
+			boards, err := toStrings(item[1])
+			if err != nil {
+				return decision, fmt.Errorf("boards: %v", err)
+			}
+			decision.Boards = boards
+		case "type":
+			issueType, ok := starlark.AsString(item[1])
+			issueType = strings.ToLower(issueType)
+			if !ok || (issueType != "feature" && issueType != "story") {
+				return decision, fmt.Errorf("type must be \"feature\" or \"story\", got %s", item[1])
+			}
+			decision.Type = issueType
+		case "fields":
+			fields, err := toGo(item[1])
+			if err != nil {
+				return decision, fmt.Errorf("fields: %v", err)
+			}
+			fieldMap, ok := fields.(map[string]interface{})
+			if !ok {
+				return decision, fmt.Errorf("fields must be a dict, got %s", item[1].Type())
+			}
+			decision.Fields = fieldMap
+		default:
+			return decision, fmt.Errorf("unknown key %q", key)
+		}
+	}
+
+	return decision, nil
+}
+
+// toStrings converts a Starlark list or tuple of strings.
+func toStrings(value starlark.Value) ([]string, error) {
+	var iterable starlark.Indexable
+	switch v := value.(type) {
+	case *starlark.List:
+		iterable = v
+	case starlark.Tuple:
+		iterable = v
+	default:
+		return nil, fmt.Errorf("expected list of strings, got %s", value.Type())
+	}
+
+	values := make([]string, 0, iterable.Len())
+	for i := 0; i < iterable.Len(); i++ {
+		s, ok := starlark.AsString(iterable.Index(i))
+		if !ok {
+			return nil, fmt.Errorf("expected list of strings, got %s element", iterable.Index(i).Type())
+		}
+		values = append(values, s)
+	}
+	return values, nil
+}
+
+// toGo converts a Starlark value to its JSON-compatible Go representation.
+func toGo(value starlark.Value) (interface{}, error) {
+	switch v := value.(type) {
+	case starlark.NoneType:
+		return nil, nil
+	case starlark.Bool:
+		return bool(v), nil
+	case starlark.Int:
+		n, ok := v.Int64()
+		if !ok {
+			return nil, fmt.Errorf("integer %s out of range", v)
+		}
+		return n, nil
+	case starlark.Float:
+		return float64(v), nil
+	case starlark.String:
+		return string(v), nil
+	case *starlark.List, starlark.Tuple:
+		iterable := v.(starlark.Indexable)
+		values := make([]interface{}, 0, iterable.Len())
+		for i := 0; i < iterable.Len(); i++ {
+			elem, err := toGo(iterable.Index(i))
+			if err != nil {
+				return nil, err
+			}
+			values = append(values, elem)
+		}
+		return values, nil
+	case *starlark.Dict:
+		values := make(map[string]interface{}, v.Len())
+		for _, item := range v.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("dict keys must be strings, got %s", item[0].Type())
+			}
+			elem, err := toGo(item[1])
+			if err != nil {
+				return nil, err
+			}
+			values[key] = elem
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("unsupported value of type %s", value.Type())
+	}
+}
@@ -0,0 +1,117 @@
+package rules
+
+import (
+	"testing"
+
+	"github.com/danielolaszy/glue/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const script = `
+def decide(issue):
+    if "wontfix" in issue["labels"]:
+        return {"skip": True}
+    if issue["title"].startswith("Epic:"):
+        return {"type": "feature", "boards": ["PLAN"]}
+    if "security" in issue["labels"]:
+        return {"type": "Story", "fields": {"customfield_10010": {"value": "Security"}, "priority": {"name": "High"}}}
+    return None
+`
+
+func TestDecide(t *testing.T) {
+	engine, err := Load("rules.star", script)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		issue    models.GitHubIssue
+		expected Decision
+	}{
+		{
+			name:     "default",
+			issue:    models.GitHubIssue{Number: 1, Title: "Bug", Labels: []string{"story"}},
+			expected: Decision{},
+		},
+		{
+			name:     "skip",
+			issue:    models.GitHubIssue{Number: 2, Title: "Old", Labels: []string{"wontfix"}},
+			expected: Decision{Skip: true},
+		},
+		{
+			name:     "type and boards",
+			issue:    models.GitHubIssue{Number: 3, Title: "Epic: Billing"},
+			expected: Decision{Type: "feature", Boards: []string{"PLAN"}},
+		},
+		{
+			name:  "fields",
+			issue: models.GitHubIssue{Number: 4, Title: "Leak", Labels: []string{"security"}},
+			expected: Decision{
+				Type: "story",
+				Fields: map[string]interface{}{
+					"customfield_10010": map[string]interface{}{"value": "Security"},
+					"priority":          map[string]interface{}{"name": "High"},
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decision, err := engine.Decide(tt.issue)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, decision)
+		})
+	}
+}
+
+func TestDecideIssueFields(t *testing.T) {
+	engine, err := Load("rules.star", `
+def decide(issue):
+    return {"boards": [issue["state"], issue["body"], str(issue["number"]), ",".join(issue["labels"]), issue["issue_type"]]}
+`)
+	require.NoError(t, err)
+
+	decision, err := engine.Decide(models.GitHubIssue{
+		Number:      7,
+		Description: "body",
+		State:       "open",
+		Labels:      []string{"a", "b"},
+		Type:        "Bug",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"open", "body", "7", "a,b", "Bug"}, decision.Boards)
+}
+
+func TestLoadErrors(t *testing.T) {
+	_, err := Load("rules.star", "def decide(issue)")
+	assert.Error(t, err)
+
+	_, err = Load("rules.star", "x = 1")
+	assert.ErrorContains(t, err, "must define a decide(issue) function")
+}
+
+func TestDecideErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+	}{
+		{"runtime error", "def decide(issue):\n    return issue[\"missing\"]"},
+		{"wrong return type", "def decide(issue):\n    return 1"},
+		{"unknown key", "def decide(issue):\n    return {\"board\": \"PROJ\"}"},
+		{"invalid type", "def decide(issue):\n    return {\"type\": \"bug\"}"},
+		{"invalid boards", "def decide(issue):\n    return {\"boards\": \"PROJ\"}"},
+		{"invalid fields", "def decide(issue):\n    return {\"fields\": [1]}"},
+		{"endless loop", "def decide(issue):\n    for i in range(100000000):\n        pass"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			engine, err := Load("rules.star", tt.script)
+			require.NoError(t, err)
+
+			_, err = engine.Decide(models.GitHubIssue{Number: 1})
+			assert.Error(t, err)
+		})
+	}
+}
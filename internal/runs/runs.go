@@ -0,0 +1,248 @@
+// Package runs keeps the summaries of a repository's recent synchronization
+// runs, so each run can be compared with the one before it that synced with
+// the same boards.
+package runs
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// MaxRuns is the number of summaries kept per repository; older ones are
+// dropped.
+const MaxRuns = 50
+
+// Summary records what a synchronization run did.
+type Summary struct {
+	// StartedAt is when the run started
+	StartedAt time.Time `json:"started_at"`
+	// FinishedAt is when the run finished
+	FinishedAt time.Time `json:"finished_at"`
+	// Boards are the JIRA project keys the run synced with
+	Boards []string `json:"boards"`
+	// Created is the number of tickets created
+	Created int `json:"created"`
+	// Closed is the number of tickets closed
+	Closed int `json:"closed"`
+	// LinksCreated is the number of parent-child links created
+	LinksCreated int `json:"links_created"`
+	// LinksRemoved is the number of parent-child links removed
+	LinksRemoved int `json:"links_removed"`
+	// Failed lists the issues whose ticket couldn't be created, in ascending order
+	Failed []int `json:"failed,omitempty"`
+	// Aborted is set if the run stopped early because JIRA was unavailable
+	Aborted bool `json:"aborted,omitempty"`
+}
+
+// History holds the summaries of a repository's recent runs.
+type History struct {
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	// Runs are the summaries of the recent runs, oldest first
+	Runs []Summary `json:"runs"`
+	// UpdatedAt is when the history was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+
+	path string
+}
+
+// DefaultDir returns the directory where run histories are stored, in glue's
+// cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "runs")
+}
+
+// New returns an empty run history of a repository, stored in dir. Saving it
+// replaces any history stored before.
+func New(dir, repository string) *History {
+	path := filepath.Join(dir, paths.FileName(repository)+".json")
+	return &History{Repository: repository, path: path}
+}
+
+// Load reads the run history of a repository from dir. If none exists, a new
+// empty history is returned. It returns an error if the file exists but
+// cannot be read or parsed.
+func Load(dir, repository string) (*History, error) {
+	h := New(dir, repository)
+
+	data, err := os.ReadFile(h.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return h, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read run history: %v", err)
+	}
+
+	if err := json.Unmarshal(data, h); err != nil {
+		return nil, fmt.Errorf("failed to parse run history %s: %v", h.path, err)
+	}
+	return h, nil
+}
+
+// Path returns the file the history is stored in.
+func (h *History) Path() string {
+	return h.path
+}
+
+// Last returns the summary of the most recent run, and false if no run was
+// recorded.
+func (h *History) Last() (Summary, bool) {
+	if len(h.Runs) == 0 {
+		return Summary{}, false
+	}
+	return h.Runs[len(h.Runs)-1], true
+}
+
+// LastWithBoards returns the summary of the most recent run that synced with
+// the same boards, in any order, and false if no such run was recorded. Runs
+// with other boards did other work, so comparing with them says nothing.
+func (h *History) LastWithBoards(boards []string) (Summary, bool) {
+	want := boardSet(boards)
+	for i := len(h.Runs) - 1; i >= 0; i-- {
+		if boardSet(h.Runs[i].Boards) == want {
+			return h.Runs[i], true
+		}
+	}
+	return Summary{}, false
+}
+
+// boardSet returns a key identifying boards regardless of their order, case
+// and duplicates.
+func boardSet(boards []string) string {
+	seen := make(map[string]bool, len(boards))
+	keys := make([]string, 0, len(boards))
+	for _, board := range boards {
+		key := strings.ToUpper(board)
+		if !seen[key] {
+			seen[key] = true
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// Add records the summary of a run, dropping the oldest summaries beyond
+// MaxRuns.
+func (h *History) Add(s Summary) {
+	s.Failed = append([]int(nil), s.Failed...)
+	sort.Ints(s.Failed)
+	h.Runs = append(h.Runs, s)
+	if len(h.Runs) > MaxRuns {
+		h.Runs = append([]Summary(nil), h.Runs[len(h.Runs)-MaxRuns:]...)
+	}
+}
+
+// Save writes the history. The file is replaced atomically so a crash during
+// the write never leaves a truncated history behind.
+func (h *History) Save() error {
+	if err := os.MkdirAll(filepath.Dir(h.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create run history directory: %v", err)
+	}
+
+	h.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(h, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode run history: %v", err)
+	}
+
+	tmp := h.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write run history: %v", err)
+	}
+	if err := os.Rename(tmp, h.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write run history: %v", err)
+	}
+	return nil
+}
+
+// Move moves the run history of repository from to repository to, e.g. after
+// the repository was renamed on GitHub. It returns false if from has no
+// history, and an error if to has one already.
+func Move(dir, from, to string) (bool, error) {
+	h, err := Load(dir, from)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(h.path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	moved := New(dir, to)
+	if moved.path != h.path {
+		if _, err := os.Stat(moved.path); err == nil {
+			return false, fmt.Errorf("run history of %s already exists: %w", to, os.ErrExist)
+		}
+	}
+	moved.Runs = h.Runs
+	if err := moved.Save(); err != nil {
+		return false, err
+	}
+	if moved.path != h.path {
+		if err := os.Remove(h.path); err != nil {
+			return false, fmt.Errorf("failed to remove run history: %v", err)
+		}
+	}
+	return true, nil
+}
+
+// Delta is how a run differs from the one before it.
+type Delta struct {
+	// Created is the change in the number of tickets created
+	Created int
+	// Closed is the change in the number of tickets closed
+	Closed int
+	// LinksCreated is the change in the number of links created
+	LinksCreated int
+	// LinksRemoved is the change in the number of links removed
+	LinksRemoved int
+	// NewFailures lists the issues that failed in this run but not the one before
+	NewFailures []int
+	// Recovered lists the issues that failed in the run before but not this one
+	Recovered []int
+}
+
+// Compare returns how current differs from previous.
+func Compare(previous, current Summary) Delta {
+	return Delta{
+		Created:      current.Created - previous.Created,
+		Closed:       current.Closed - previous.Closed,
+		LinksCreated: current.LinksCreated - previous.LinksCreated,
+		LinksRemoved: current.LinksRemoved - previous.LinksRemoved,
+		NewFailures:  missingFrom(current.Failed, previous.Failed),
+		Recovered:    missingFrom(previous.Failed, current.Failed),
+	}
+}
+
+// IsZero reports whether the runs did the same amount of work and failed on
+// the same issues.
+func (d Delta) IsZero() bool {
+	return d.Created == 0 && d.Closed == 0 && d.LinksCreated == 0 && d.LinksRemoved == 0 &&
+		len(d.NewFailures) == 0 && len(d.Recovered) == 0
+}
+
+// missingFrom returns the numbers of a that aren't in b, in ascending order.
+func missingFrom(a, b []int) []int {
+	in := make(map[int]bool, len(b))
+	for _, n := range b {
+		in[n] = true
+	}
+	var missing []int
+	for _, n := range a {
+		if !in[n] {
+			missing = append(missing, n)
+			in[n] = true
+		}
+	}
+	sort.Ints(missing)
+	return missing
+}
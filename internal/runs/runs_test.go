@@ -0,0 +1,127 @@
+package runs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	h, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/repo", h.Repository)
+	assert.Equal(t, filepath.Join(dir, "owner_repo.json"), h.Path())
+	_, ok := h.Last()
+	assert.False(t, ok)
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	started := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	h, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	h.Add(Summary{StartedAt: started, Boards: []string{"PROJ"}, Created: 3})
+	h.Add(Summary{StartedAt: started.Add(time.Hour), Boards: []string{"PROJ"}, Created: 1, Failed: []int{9, 4}})
+	require.NoError(t, h.Save())
+
+	loaded, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	require.Len(t, loaded.Runs, 2)
+	last, ok := loaded.Last()
+	require.True(t, ok)
+	assert.Equal(t, started.Add(time.Hour), last.StartedAt)
+	assert.Equal(t, []int{4, 9}, last.Failed)
+	assert.False(t, loaded.UpdatedAt.IsZero())
+
+	_, err = os.Stat(h.Path() + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestAddKeepsRecentRuns(t *testing.T) {
+	h := New(t.TempDir(), "owner/repo")
+	for i := 0; i < MaxRuns+5; i++ {
+		h.Add(Summary{Created: i})
+	}
+
+	require.Len(t, h.Runs, MaxRuns)
+	assert.Equal(t, 5, h.Runs[0].Created)
+	last, _ := h.Last()
+	assert.Equal(t, MaxRuns+4, last.Created)
+}
+
+func TestLastWithBoards(t *testing.T) {
+	h := New(t.TempDir(), "owner/repo")
+	h.Add(Summary{Boards: []string{"PROJ", "OPS"}, Created: 1})
+	h.Add(Summary{Boards: []string{"PROJ"}, Created: 2})
+	h.Add(Summary{Boards: []string{"OPS"}, Created: 3})
+
+	last, ok := h.LastWithBoards([]string{"PROJ"})
+	require.True(t, ok)
+	assert.Equal(t, 2, last.Created)
+
+	last, ok = h.LastWithBoards([]string{"ops", "PROJ"})
+	require.True(t, ok)
+	assert.Equal(t, 1, last.Created)
+
+	_, ok = h.LastWithBoards([]string{"OTHER"})
+	assert.False(t, ok)
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "owner_repo.json"), []byte("{"), 0o644))
+
+	_, err := Load(dir, "owner/repo")
+	assert.Error(t, err)
+}
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+
+	moved, err := Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	h := New(dir, "owner/old")
+	h.Add(Summary{Created: 2})
+	require.NoError(t, h.Save())
+
+	moved, err = Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	loaded, err := Load(dir, "owner/new")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/new", loaded.Repository)
+	require.Len(t, loaded.Runs, 1)
+	_, err = os.Stat(filepath.Join(dir, "owner_old.json"))
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, New(dir, "owner/old").Save())
+	_, err = Move(dir, "owner/old", "owner/new")
+	assert.ErrorIs(t, err, os.ErrExist)
+}
+
+func TestCompare(t *testing.T) {
+	previous := Summary{Created: 2, Closed: 1, LinksCreated: 5, Failed: []int{3, 7}}
+	current := Summary{Created: 14, Closed: 1, LinksCreated: 2, LinksRemoved: 1, Failed: []int{7, 8, 12}}
+
+	d := Compare(previous, current)
+	assert.Equal(t, Delta{
+		Created:      12,
+		LinksCreated: -3,
+		LinksRemoved: 1,
+		NewFailures:  []int{8, 12},
+		Recovered:    []int{3},
+	}, d)
+	assert.False(t, d.IsZero())
+
+	assert.True(t, Compare(current, current).IsZero())
+}
@@ -0,0 +1,305 @@
+// Package state records which JIRA ticket each GitHub issue of a repository
+// is synced to, when it was last synced and what was synced, so runs don't
+// depend on issue titles keeping their key prefix and don't re-diff unchanged
+// issues. Each repository's state is a bolt database, whose transactions keep
+// a crash during a save from leaving a partial store behind.
+package state
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// Buckets of the database: the entries by issue number, and the repository
+// and save time.
+var (
+	issuesBucket = []byte("issues")
+	metaBucket   = []byte("meta")
+)
+
+// openTimeout is how long to wait for another glue process to close the
+// database.
+const openTimeout = 10 * time.Second
+
+// Entry is the sync state of a GitHub issue.
+type Entry struct {
+	// Key is the key of the issue's JIRA ticket
+	Key string `json:"key"`
+	// SyncedAt is when glue last created or updated the ticket from the
+	// issue; zero if the mapping was only seen
+	SyncedAt time.Time `json:"synced_at,omitempty"`
+	// DescriptionHash is the hash of the description last written to the
+	// ticket, or "" if it is unknown
+	DescriptionHash string `json:"description_hash,omitempty"`
+}
+
+// Store holds the sync state of a repository's issues.
+type Store struct {
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	// Issues maps GitHub issue numbers to their sync state
+	Issues map[int]Entry `json:"issues"`
+	// UpdatedAt is when the store was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+
+	path string
+	// legacyPath is the JSON file the store was read from, removed once the
+	// store is saved to the database
+	legacyPath string
+}
+
+// DefaultDir returns the directory where sync state is stored, in glue's
+// cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "state")
+}
+
+// New returns an empty store of a repository, stored in dir. Saving it
+// replaces any store saved before.
+func New(dir, repository string) *Store {
+	path := filepath.Join(dir, paths.FileName(repository)+".db")
+	return &Store{Repository: repository, Issues: make(map[int]Entry), path: path}
+}
+
+// Load reads the store of a repository from dir. If none exists, a new empty
+// store is returned; a JSON store written by earlier versions is read instead
+// and replaced by the database on Save. It returns an error if the file exists
+// but cannot be read or parsed.
+func Load(dir, repository string) (*Store, error) {
+	s := New(dir, repository)
+
+	if _, err := os.Stat(s.path); errors.Is(err, os.ErrNotExist) {
+		return s.loadLegacy()
+	}
+
+	db, err := bolt.Open(s.path, 0o644, &bolt.Options{Timeout: openTimeout, ReadOnly: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open sync state %s: %v", s.path, err)
+	}
+	defer db.Close()
+
+	err = db.View(func(tx *bolt.Tx) error {
+		if meta := tx.Bucket(metaBucket); meta != nil {
+			if err := s.UpdatedAt.UnmarshalText(meta.Get([]byte("updated_at"))); err != nil {
+				return err
+			}
+		}
+		issues := tx.Bucket(issuesBucket)
+		if issues == nil {
+			return nil
+		}
+		return issues.ForEach(func(k, v []byte) error {
+			number, err := strconv.Atoi(string(k))
+			if err != nil {
+				return fmt.Errorf("invalid issue number %q: %v", k, err)
+			}
+			var entry Entry
+			if err := json.Unmarshal(v, &entry); err != nil {
+				return fmt.Errorf("issue %d: %v", number, err)
+			}
+			s.Issues[number] = entry
+			return nil
+		})
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse sync state %s: %v", s.path, err)
+	}
+	return s, nil
+}
+
+// loadLegacy reads the JSON store earlier versions kept next to the
+// database, if there is one.
+func (s *Store) loadLegacy() (*Store, error) {
+	legacyPath := strings.TrimSuffix(s.path, ".db") + ".json"
+	data, err := os.ReadFile(legacyPath)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sync state: %v", err)
+	}
+
+	if err := json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("failed to parse sync state %s: %v", legacyPath, err)
+	}
+	if s.Issues == nil {
+		s.Issues = make(map[int]Entry)
+	}
+	s.legacyPath = legacyPath
+	return s, nil
+}
+
+// exists reports whether the store was saved before.
+func (s *Store) exists() bool {
+	if s.legacyPath != "" {
+		return true
+	}
+	_, err := os.Stat(s.path)
+	return err == nil
+}
+
+// Path returns the file the store is saved in.
+func (s *Store) Path() string {
+	return s.path
+}
+
+// Key returns the key of the ticket the issue is synced to, or "" if none is
+// recorded.
+func (s *Store) Key(number int) string {
+	return s.Issues[number].Key
+}
+
+// SetKey records the ticket the issue is synced to. Recording another key
+// than before forgets what was synced to the old ticket.
+func (s *Store) SetKey(number int, key string) {
+	entry := s.Issues[number]
+	if entry.Key != key {
+		entry = Entry{Key: key}
+	}
+	s.Issues[number] = entry
+}
+
+// MarkSynced records that the issue's ticket key was created or updated at.
+func (s *Store) MarkSynced(number int, key string, at time.Time) {
+	s.SetKey(number, key)
+	entry := s.Issues[number]
+	entry.SyncedAt = at.UTC()
+	s.Issues[number] = entry
+}
+
+// DescriptionSynced reports whether hash is the hash of the description last
+// written to the issue's ticket key.
+func (s *Store) DescriptionSynced(number int, key, hash string) bool {
+	entry, ok := s.Issues[number]
+	return ok && entry.Key == key && entry.DescriptionHash != "" && entry.DescriptionHash == hash
+}
+
+// MarkDescriptionSynced records the hash of the description the issue's
+// ticket key has as of at.
+func (s *Store) MarkDescriptionSynced(number int, key, hash string, at time.Time) {
+	s.MarkSynced(number, key, at)
+	entry := s.Issues[number]
+	entry.DescriptionHash = hash
+	s.Issues[number] = entry
+}
+
+// Rename moves the issues synced to the ticket oldKey to newKey, e.g. after
+// its project was re-keyed. Keys are compared case-insensitively.
+func (s *Store) Rename(oldKey, newKey string) {
+	for number, entry := range s.Issues {
+		if strings.EqualFold(entry.Key, oldKey) {
+			entry.Key = newKey
+			s.Issues[number] = entry
+		}
+	}
+}
+
+// Forget drops the sync state of the issue.
+func (s *Store) Forget(number int) {
+	delete(s.Issues, number)
+}
+
+// Move moves the store of repository from to repository to, e.g. after the
+// repository was renamed on GitHub. It returns false if from has no store,
+// and an error if to has one already.
+func Move(dir, from, to string) (bool, error) {
+	s, err := Load(dir, from)
+	if err != nil {
+		return false, err
+	}
+	if !s.exists() {
+		return false, nil
+	}
+
+	moved, err := Load(dir, to)
+	if err != nil {
+		return false, err
+	}
+	if moved.path != s.path && moved.exists() {
+		return false, fmt.Errorf("sync state of %s already exists: %w", to, os.ErrExist)
+	}
+	moved.Issues = s.Issues
+	if err := moved.Save(); err != nil {
+		return false, err
+	}
+	if moved.path != s.path {
+		for _, path := range []string{s.path, s.legacyPath} {
+			if path == "" {
+				continue
+			}
+			if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return false, fmt.Errorf("failed to remove sync state: %v", err)
+			}
+		}
+	}
+	return true, nil
+}
+
+// Save writes the store to its database in a single transaction, so a crash
+// during the write never leaves a partial store behind.
+func (s *Store) Save() error {
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create sync state directory: %v", err)
+	}
+
+	db, err := bolt.Open(s.path, 0o644, &bolt.Options{Timeout: openTimeout})
+	if err != nil {
+		return fmt.Errorf("failed to open sync state %s: %v", s.path, err)
+	}
+	defer db.Close()
+
+	s.UpdatedAt = time.Now().UTC()
+	err = db.Update(func(tx *bolt.Tx) error {
+		// Forgotten issues are dropped by rewriting the bucket
+		if err := tx.DeleteBucket(issuesBucket); err != nil && !errors.Is(err, bolt.ErrBucketNotFound) {
+			return err
+		}
+		issues, err := tx.CreateBucket(issuesBucket)
+		if err != nil {
+			return err
+		}
+		for number, entry := range s.Issues {
+			data, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			if err := issues.Put([]byte(strconv.Itoa(number)), data); err != nil {
+				return err
+			}
+		}
+
+		meta, err := tx.CreateBucketIfNotExists(metaBucket)
+		if err != nil {
+			return err
+		}
+		if err := meta.Put([]byte("repository"), []byte(s.Repository)); err != nil {
+			return err
+		}
+		updatedAt, err := s.UpdatedAt.MarshalText()
+		if err != nil {
+			return err
+		}
+		return meta.Put([]byte("updated_at"), updatedAt)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write sync state: %v", err)
+	}
+
+	if s.legacyPath != "" {
+		if err := os.Remove(s.legacyPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("failed to remove old sync state: %v", err)
+		}
+		s.legacyPath = ""
+	}
+	return nil
+}
@@ -0,0 +1,139 @@
+package state
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/repo", s.Repository)
+	assert.Empty(t, s.Issues)
+	assert.Equal(t, "", s.Key(1))
+	assert.Equal(t, filepath.Join(dir, "owner_repo.db"), s.Path())
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	s, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	s.SetKey(1, "PROJ-1")
+	s.MarkDescriptionSynced(2, "PROJ-2", "abc", at)
+	require.NoError(t, s.Save())
+
+	loaded, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, Entry{Key: "PROJ-1"}, loaded.Issues[1])
+	assert.Equal(t, Entry{Key: "PROJ-2", SyncedAt: at, DescriptionHash: "abc"}, loaded.Issues[2])
+	assert.False(t, loaded.UpdatedAt.IsZero())
+
+	// Forgotten issues stay forgotten
+	loaded.Forget(1)
+	require.NoError(t, loaded.Save())
+	loaded, err = Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.NotContains(t, loaded.Issues, 1)
+	assert.Contains(t, loaded.Issues, 2)
+}
+
+func TestLoadLegacyJSON(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, "owner_repo.json")
+	require.NoError(t, os.WriteFile(legacy, []byte(`{"repository":"owner/repo","issues":{"1":{"key":"PROJ-1"}}}`), 0o644))
+
+	s, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-1", s.Key(1))
+
+	// Saving moves the store to the database
+	require.NoError(t, s.Save())
+	_, err = os.Stat(legacy)
+	assert.True(t, os.IsNotExist(err))
+
+	loaded, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "PROJ-1", loaded.Key(1))
+}
+
+func TestDescriptionSynced(t *testing.T) {
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	s := New(t.TempDir(), "owner/repo")
+	s.MarkDescriptionSynced(2, "PROJ-2", "abc", at)
+
+	assert.True(t, s.DescriptionSynced(2, "PROJ-2", "abc"))
+	assert.False(t, s.DescriptionSynced(2, "PROJ-2", "def"))
+	assert.False(t, s.DescriptionSynced(2, "PROJ-9", "abc"))
+	assert.False(t, s.DescriptionSynced(3, "PROJ-3", ""))
+
+	// Another ticket forgets what was synced to the old one
+	s.SetKey(2, "OPS-1")
+	assert.Equal(t, Entry{Key: "OPS-1"}, s.Issues[2])
+
+	s.MarkSynced(2, "OPS-1", at)
+	s.SetKey(2, "OPS-1")
+	assert.Equal(t, at, s.Issues[2].SyncedAt)
+}
+
+func TestRenameAndForget(t *testing.T) {
+	s := New(t.TempDir(), "owner/repo")
+	s.SetKey(1, "OLD-1")
+	s.SetKey(2, "OLD-2")
+
+	s.Rename("old-1", "NEW-1")
+	assert.Equal(t, "NEW-1", s.Key(1))
+	assert.Equal(t, "OLD-2", s.Key(2))
+
+	s.Forget(2)
+	assert.Equal(t, "", s.Key(2))
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "owner_repo.db"), []byte("{"), 0o644))
+
+	_, err := Load(dir, "owner/repo")
+	assert.Error(t, err)
+
+	legacyDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(legacyDir, "owner_repo.json"), []byte("{"), 0o644))
+
+	_, err = Load(legacyDir, "owner/repo")
+	assert.Error(t, err)
+}
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+
+	moved, err := Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	s := New(dir, "owner/old")
+	s.SetKey(1, "PROJ-1")
+	require.NoError(t, s.Save())
+
+	moved, err = Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	loaded, err := Load(dir, "owner/new")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/new", loaded.Repository)
+	assert.Equal(t, "PROJ-1", loaded.Key(1))
+	_, err = os.Stat(filepath.Join(dir, "owner_old.db"))
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, New(dir, "owner/old").Save())
+	_, err = Move(dir, "owner/old", "owner/new")
+	assert.ErrorIs(t, err, os.ErrExist)
+}
@@ -0,0 +1,158 @@
+// Package statuscache remembers which JIRA tickets of closed GitHub issues are
+// already done, so steady-state runs don't query their status again.
+package statuscache
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/paths"
+)
+
+// Cache records the tickets of a repository's closed issues that were seen
+// done in JIRA.
+type Cache struct {
+	// Repository is the GitHub repository in the format "owner/repo"
+	Repository string `json:"repository"`
+	// Done maps ticket keys to when they were first seen done
+	Done map[string]time.Time `json:"done"`
+	// UpdatedAt is when the cache was last saved
+	UpdatedAt time.Time `json:"updated_at"`
+
+	path string
+}
+
+// DefaultDir returns the directory where status caches are stored, in glue's
+// cache directory.
+func DefaultDir() string {
+	return filepath.Join(paths.CacheDir(), "statuses")
+}
+
+// New returns an empty status cache of a repository, stored in dir. Saving it
+// replaces any cache stored before.
+func New(dir, repository string) *Cache {
+	path := filepath.Join(dir, paths.FileName(repository)+".json")
+	return &Cache{Repository: repository, Done: make(map[string]time.Time), path: path}
+}
+
+// Load reads the status cache of a repository from dir. If none exists, a new
+// empty cache is returned. It returns an error if the file exists but cannot
+// be read or parsed.
+func Load(dir, repository string) (*Cache, error) {
+	c := New(dir, repository)
+
+	data, err := os.ReadFile(c.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return c, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read status cache: %v", err)
+	}
+
+	if err := json.Unmarshal(data, c); err != nil {
+		return nil, fmt.Errorf("failed to parse status cache %s: %v", c.path, err)
+	}
+	if c.Done == nil {
+		c.Done = make(map[string]time.Time)
+	}
+	return c, nil
+}
+
+// Path returns the file the cache is stored in.
+func (c *Cache) Path() string {
+	return c.path
+}
+
+// IsDone reports whether the ticket was recorded as done.
+func (c *Cache) IsDone(key string) bool {
+	_, ok := c.Done[key]
+	return ok
+}
+
+// MarkDone records the ticket as done, keeping the time it was first seen so.
+func (c *Cache) MarkDone(key string, at time.Time) {
+	if !c.IsDone(key) {
+		c.Done[key] = at.UTC()
+	}
+}
+
+// Rename moves the record of a ticket to its new key, e.g. after its project
+// was re-keyed.
+func (c *Cache) Rename(oldKey, newKey string) {
+	if at, ok := c.Done[oldKey]; ok {
+		delete(c.Done, oldKey)
+		c.Done[newKey] = at
+	}
+}
+
+// Retain forgets every ticket not in keys, e.g. those whose GitHub issue was
+// reopened, so they are checked again once their issue is closed again.
+func (c *Cache) Retain(keys []string) {
+	keep := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		keep[key] = true
+	}
+	for key := range c.Done {
+		if !keep[key] {
+			delete(c.Done, key)
+		}
+	}
+}
+
+// Move moves the status cache of repository from to repository to, e.g. after
+// the repository was renamed on GitHub. It returns false if from has no
+// cache, and an error if to has one already.
+func Move(dir, from, to string) (bool, error) {
+	c, err := Load(dir, from)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(c.path); errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+
+	moved := New(dir, to)
+	if moved.path != c.path {
+		if _, err := os.Stat(moved.path); err == nil {
+			return false, fmt.Errorf("status cache of %s already exists: %w", to, os.ErrExist)
+		}
+	}
+	moved.Done = c.Done
+	if err := moved.Save(); err != nil {
+		return false, err
+	}
+	if moved.path != c.path {
+		if err := os.Remove(c.path); err != nil {
+			return false, fmt.Errorf("failed to remove status cache: %v", err)
+		}
+	}
+	return true, nil
+}
+
+// Save writes the cache. The file is replaced atomically so a crash during
+// the write never leaves a truncated cache behind.
+func (c *Cache) Save() error {
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create status cache directory: %v", err)
+	}
+
+	c.UpdatedAt = time.Now().UTC()
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode status cache: %v", err)
+	}
+
+	tmp := c.path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write status cache: %v", err)
+	}
+	if err := os.Rename(tmp, c.path); err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to write status cache: %v", err)
+	}
+	return nil
+}
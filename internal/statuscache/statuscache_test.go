@@ -0,0 +1,94 @@
+package statuscache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMissing(t *testing.T) {
+	dir := t.TempDir()
+
+	c, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/repo", c.Repository)
+	assert.Empty(t, c.Done)
+	assert.False(t, c.IsDone("PROJ-1"))
+	assert.Equal(t, filepath.Join(dir, "owner_repo.json"), c.Path())
+}
+
+func TestSaveAndLoad(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "nested")
+	first := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	c, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	c.MarkDone("PROJ-1", first)
+	c.MarkDone("PROJ-1", first.Add(time.Hour))
+	c.MarkDone("PROJ-2", first)
+	require.NoError(t, c.Save())
+
+	loaded, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.True(t, loaded.IsDone("PROJ-1"))
+	assert.Equal(t, first, loaded.Done["PROJ-1"])
+	assert.False(t, loaded.UpdatedAt.IsZero())
+
+	loaded.Retain([]string{"PROJ-2", "PROJ-3"})
+	assert.False(t, loaded.IsDone("PROJ-1"))
+	assert.True(t, loaded.IsDone("PROJ-2"))
+	assert.False(t, loaded.IsDone("PROJ-3"))
+
+	loaded.Rename("PROJ-2", "NEW-2")
+	assert.False(t, loaded.IsDone("PROJ-2"))
+	assert.Equal(t, first, loaded.Done["NEW-2"])
+
+	_, err = os.Stat(c.Path() + ".tmp")
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestLoadCorrupt(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "owner_repo.json"), []byte("{"), 0o644))
+
+	_, err := Load(dir, "owner/repo")
+	assert.Error(t, err)
+
+	// A new cache replaces the corrupt one
+	require.NoError(t, New(dir, "owner/repo").Save())
+	c, err := Load(dir, "owner/repo")
+	require.NoError(t, err)
+	assert.Empty(t, c.Done)
+}
+
+func TestMove(t *testing.T) {
+	dir := t.TempDir()
+	at := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+
+	moved, err := Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.False(t, moved)
+
+	c := New(dir, "owner/old")
+	c.MarkDone("PROJ-1", at)
+	require.NoError(t, c.Save())
+
+	moved, err = Move(dir, "owner/old", "owner/new")
+	require.NoError(t, err)
+	assert.True(t, moved)
+
+	loaded, err := Load(dir, "owner/new")
+	require.NoError(t, err)
+	assert.Equal(t, "owner/new", loaded.Repository)
+	assert.Equal(t, at, loaded.Done["PROJ-1"])
+	_, err = os.Stat(c.Path())
+	assert.True(t, os.IsNotExist(err))
+
+	require.NoError(t, c.Save())
+	_, err = Move(dir, "owner/old", "owner/new")
+	assert.ErrorIs(t, err, os.ErrExist)
+}
@@ -0,0 +1,61 @@
+package xlsx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestColumnName(t *testing.T) {
+	tests := map[int]string{0: "A", 1: "B", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for index, expected := range tests {
+		assert.Equal(t, expected, ColumnName(index))
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buf bytes.Buffer
+	rows := [][]string{
+		{"number", "title"},
+		{"42", "Fix <login> & \"logout\"\x01"},
+	}
+	require.NoError(t, Write(&buf, "Mapping", rows))
+
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		rc.Close()
+		files[f.Name] = string(content)
+	}
+
+	for _, name := range []string{"[Content_Types].xml", "_rels/.rels", "xl/workbook.xml", "xl/_rels/workbook.xml.rels", "xl/worksheets/sheet1.xml"} {
+		require.Contains(t, files, name)
+		assert.NoError(t, xml.Unmarshal([]byte(files[name]), new(struct{})), name)
+	}
+
+	assert.Contains(t, files["xl/workbook.xml"], `name="Mapping"`)
+
+	var sheet struct {
+		Rows []struct {
+			Cells []struct {
+				Ref  string `xml:"r,attr"`
+				Text string `xml:"is>t"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	require.NoError(t, xml.Unmarshal([]byte(files["xl/worksheets/sheet1.xml"]), &sheet))
+	require.Len(t, sheet.Rows, 2)
+	assert.Equal(t, "B2", sheet.Rows[1].Cells[1].Ref)
+	assert.Equal(t, "Fix <login> & \"logout\"", sheet.Rows[1].Cells[1].Text)
+}
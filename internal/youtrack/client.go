@@ -0,0 +1,317 @@
+// Package youtrack provides functionality for interacting with the YouTrack
+// REST API.
+package youtrack
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/danielolaszy/glue/internal/config"
+	"github.com/danielolaszy/glue/internal/faults"
+	"github.com/danielolaszy/glue/internal/httpdebug"
+	"github.com/danielolaszy/glue/internal/logging"
+	"github.com/danielolaszy/glue/internal/readonly"
+	"github.com/danielolaszy/glue/internal/recorder"
+)
+
+// DefaultRequestTimeout bounds a single YouTrack request.
+const DefaultRequestTimeout = 30 * time.Second
+
+// pageSize is the number of objects fetched per request.
+const pageSize = 100
+
+// issueFields are the fields of issues requested from YouTrack.
+const issueFields = "id,idReadable,summary,description,resolved"
+
+// Client handles interactions with the YouTrack REST API.
+type Client struct {
+	baseURL string
+	http    *http.Client
+	// Logger used for this client's output; nil means the default logger
+	logger *slog.Logger
+}
+
+// Project is a YouTrack project.
+type Project struct {
+	ID        string `json:"id"`
+	ShortName string `json:"shortName"`
+	Name      string `json:"name"`
+}
+
+// Issue is a YouTrack issue.
+type Issue struct {
+	ID string `json:"id"`
+	// IDReadable is the key of the issue, e.g. "PLAT-12"
+	IDReadable  string `json:"idReadable"`
+	Summary     string `json:"summary"`
+	Description string `json:"description"`
+	// ResolvedAt is when the issue was resolved in milliseconds since the
+	// epoch, or nil while it is unresolved
+	ResolvedAt *int64 `json:"resolved"`
+}
+
+// Resolved reports whether the issue is in a resolved state.
+func (i Issue) Resolved() bool {
+	return i.ResolvedAt != nil
+}
+
+// NewClient creates a new YouTrack client with the address and token from the
+// configuration.
+func NewClient() (*Client, error) {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.YouTrack.BaseURL == "" || cfg.YouTrack.Token == "" {
+		return nil, errors.New("missing required YouTrack configuration (YOUTRACK_URL, YOUTRACK_TOKEN)")
+	}
+
+	base, err := recorder.Wrap(http.DefaultTransport, "youtrack", cfg.Record, cfg.Replay)
+	if err != nil {
+		return nil, err
+	}
+	base, err = faults.Wrap(base, "youtrack")
+	if err != nil {
+		return nil, err
+	}
+	base = httpdebug.Wrap(base, "youtrack")
+	if cfg.ReadOnly {
+		logging.Info("youtrack client is read-only, changes will be refused")
+		base = &readonly.Transport{Base: base}
+	}
+
+	return &Client{
+		baseURL: strings.TrimRight(cfg.YouTrack.BaseURL, "/") + "/api",
+		http: &http.Client{
+			Timeout:   DefaultRequestTimeout,
+			Transport: &tokenTransport{base: base, token: cfg.YouTrack.Token},
+		},
+	}, nil
+}
+
+// WithLogger returns a shallow copy of the client that writes its log output
+// to logger.
+func (c *Client) WithLogger(logger *slog.Logger) *Client {
+	copied := *c
+	copied.logger = logger
+	return &copied
+}
+
+// log returns the logger of the client.
+func (c *Client) log() *slog.Logger {
+	if c.logger != nil {
+		return c.logger
+	}
+	return logging.GetLogger()
+}
+
+// FindProject returns the project with the short name, the prefix of its
+// issue keys, compared case-insensitively.
+func (c *Client) FindProject(ctx context.Context, shortName string) (Project, error) {
+	var found Project
+	err := c.list(ctx, "admin/projects", url.Values{"fields": {"id,shortName,name"}}, func(raw json.RawMessage) (bool, error) {
+		var projects []Project
+		if err := json.Unmarshal(raw, &projects); err != nil {
+			return false, err
+		}
+		for _, project := range projects {
+			if strings.EqualFold(project.ShortName, shortName) {
+				found = project
+				return false, nil
+			}
+		}
+		return len(projects) == pageSize, nil
+	})
+	if err != nil {
+		return Project{}, fmt.Errorf("failed to get projects: %w", err)
+	}
+	if found.ID == "" {
+		return Project{}, apierror.Invalid("project", "youtrack project %q not found", shortName)
+	}
+	return found, nil
+}
+
+// Issues returns the issues of a project, including resolved ones.
+func (c *Client) Issues(ctx context.Context, project Project) ([]Issue, error) {
+	var issues []Issue
+	params := url.Values{
+		"query":  {"project: " + project.ShortName},
+		"fields": {issueFields},
+	}
+	err := c.list(ctx, "issues", params, func(raw json.RawMessage) (bool, error) {
+		var page []Issue
+		if err := json.Unmarshal(raw, &page); err != nil {
+			return false, err
+		}
+		issues = append(issues, page...)
+		return len(page) == pageSize, nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get issues of project %s: %w", project.ShortName, err)
+	}
+	return issues, nil
+}
+
+// CreateIssue creates an issue in a project.
+func (c *Client) CreateIssue(ctx context.Context, project Project, summary, description string) (Issue, error) {
+	body := map[string]interface{}{
+		"project":     map[string]string{"id": project.ID},
+		"summary":     summary,
+		"description": description,
+	}
+	var issue Issue
+	path := "issues?" + url.Values{"fields": {issueFields}}.Encode()
+	if err := c.send(ctx, http.MethodPost, path, body, &issue); err != nil {
+		return Issue{}, fmt.Errorf("failed to create issue in project %s: %w", project.ShortName, err)
+	}
+	c.log().Info("created youtrack issue", "issue", issue.IDReadable, "summary", summary)
+	return issue, nil
+}
+
+// SetFields sets custom fields of an issue, values by field name. The values
+// are names, as shown in YouTrack, of enum values, states, users or versions.
+func (c *Client) SetFields(ctx context.Context, issueID string, values map[string]string) error {
+	if len(values) == 0 {
+		return nil
+	}
+	if err := c.command(ctx, issueID, FieldCommand(values)); err != nil {
+		return fmt.Errorf("failed to set fields of issue %s: %w", issueID, err)
+	}
+	c.log().Info("set youtrack issue fields", "issue", issueID, "fields", len(values))
+	return nil
+}
+
+// SetState moves an issue to the state with the name, which must exist in
+// the State field of its project.
+func (c *Client) SetState(ctx context.Context, issueID, state string) error {
+	if err := c.command(ctx, issueID, FieldCommand(map[string]string{"State": state})); err != nil {
+		return fmt.Errorf("failed to set state of issue %s to %q: %w", issueID, state, err)
+	}
+	c.log().Info("set youtrack issue state", "issue", issueID, "state", state)
+	return nil
+}
+
+// FieldCommand returns the YouTrack command setting the fields to the values,
+// such as "Priority {Show-stopper} Type {Bug}". Values are wrapped in braces
+// so values with spaces are taken whole. Fields are ordered by name.
+func FieldCommand(values map[string]string) string {
+	fields := make([]string, 0, len(values))
+	for field := range values {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	parts := make([]string, len(fields))
+	for i, field := range fields {
+		parts[i] = fmt.Sprintf("%s {%s}", field, values[field])
+	}
+	return strings.Join(parts, " ")
+}
+
+// command applies a command to an issue, which is how YouTrack sets custom
+// fields without the client knowing their types.
+func (c *Client) command(ctx context.Context, issueID, query string) error {
+	body := map[string]interface{}{
+		"query":  query,
+		"issues": []map[string]string{{"idReadable": issueID}},
+	}
+	return c.send(ctx, http.MethodPost, "commands", body, nil)
+}
+
+// list calls fn with each page of a listing until fn returns false.
+func (c *Client) list(ctx context.Context, path string, params url.Values, fn func(json.RawMessage) (bool, error)) error {
+	for skip := 0; ; skip += pageSize {
+		params.Set("$top", strconv.Itoa(pageSize))
+		params.Set("$skip", strconv.Itoa(skip))
+		var page json.RawMessage
+		if err := c.send(ctx, http.MethodGet, path+"?"+params.Encode(), nil, &page); err != nil {
+			return err
+		}
+		more, err := fn(page)
+		if err != nil {
+			return fmt.Errorf("failed to decode response: %w", err)
+		}
+		if !more {
+			return nil
+		}
+	}
+}
+
+// send sends a request with body encoded as JSON and decodes the response
+// into out, unless it is nil. Error responses return the message YouTrack
+// gives.
+func (c *Client) send(ctx context.Context, method, path string, body interface{}, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		encoded, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		reader = bytes.NewReader(encoded)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, strings.TrimRight(c.baseURL, "/")+"/"+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return apierror.Wrap(err, 0)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(resp.Body)
+		var failure struct {
+			Error       string `json:"error"`
+			Description string `json:"error_description"`
+		}
+		message := strings.TrimSpace(string(raw))
+		if json.Unmarshal(raw, &failure) == nil && failure.Error != "" {
+			message = failure.Error
+			if failure.Description != "" {
+				message = failure.Description
+			}
+		}
+		err := fmt.Errorf("%s %s: %s", method, req.URL.Path, message)
+		return fmt.Errorf("%w (status: %d)", apierror.Wrap(err, resp.StatusCode), resp.StatusCode)
+	}
+
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+// tokenTransport authenticates requests with a permanent token.
+type tokenTransport struct {
+	base  http.RoundTripper
+	token string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *tokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.token)
+	return t.base.RoundTrip(req)
+}
@@ -0,0 +1,147 @@
+package youtrack
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/danielolaszy/glue/internal/apierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeYouTrack serves two projects and a project whose issues span two pages,
+// and records the created issues and applied commands.
+type fakeYouTrack struct {
+	created  map[string]interface{}
+	commands []string
+}
+
+func (f *fakeYouTrack) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	query := r.URL.Query()
+	switch {
+	case r.URL.Path == "/admin/projects":
+		fmt.Fprint(w, `[{"id":"0-1","shortName":"PLAT","name":"Platform"},{"id":"0-2","shortName":"OPS","name":"Operations"}]`)
+	case r.Method == http.MethodGet && r.URL.Path == "/issues" && query.Get("query") == "project: PLAT" && query.Get("$skip") == "0":
+		issues := make([]string, pageSize)
+		for i := range issues {
+			issues[i] = fmt.Sprintf(`{"id":"2-%d","idReadable":"PLAT-%d","summary":"Issue %d","resolved":null}`, i, i+1, i+1)
+		}
+		fmt.Fprintf(w, "[%s]", strings.Join(issues, ","))
+	case r.Method == http.MethodGet && r.URL.Path == "/issues":
+		fmt.Fprint(w, `[{"id":"2-100","idReadable":"PLAT-101","summary":"Last","resolved":1700000000000}]`)
+	case r.Method == http.MethodPost && r.URL.Path == "/issues":
+		json.NewDecoder(r.Body).Decode(&f.created)
+		fmt.Fprintf(w, `{"id":"2-200","idReadable":"PLAT-201","summary":%q,"resolved":null}`, f.created["summary"])
+	case r.Method == http.MethodPost && r.URL.Path == "/commands":
+		var body struct {
+			Query  string `json:"query"`
+			Issues []struct {
+				IDReadable string `json:"idReadable"`
+			} `json:"issues"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		if strings.Contains(body.Query, "Shipped") {
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, `{"error":"bad_request","error_description":"Unknown state: Shipped"}`)
+			return
+		}
+		if len(body.Issues) != 1 || body.Issues[0].IDReadable == "PLAT-999" {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `{"error":"Not Found"}`)
+			return
+		}
+		f.commands = append(f.commands, body.Issues[0].IDReadable+": "+body.Query)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, `{"error":"Not Found"}`)
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler) *Client {
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	return &Client{baseURL: server.URL, http: server.Client()}
+}
+
+func TestFindProject(t *testing.T) {
+	client := newTestClient(t, &fakeYouTrack{})
+	ctx := context.Background()
+
+	project, err := client.FindProject(ctx, "ops")
+	require.NoError(t, err)
+	assert.Equal(t, Project{ID: "0-2", ShortName: "OPS", Name: "Operations"}, project)
+
+	_, err = client.FindProject(ctx, "HR")
+	var validation *apierror.ValidationError
+	require.ErrorAs(t, err, &validation)
+	assert.ErrorContains(t, err, `youtrack project "HR" not found`)
+}
+
+func TestIssues(t *testing.T) {
+	client := newTestClient(t, &fakeYouTrack{})
+
+	issues, err := client.Issues(context.Background(), Project{ID: "0-1", ShortName: "PLAT"})
+	require.NoError(t, err)
+	require.Len(t, issues, pageSize+1)
+	assert.Equal(t, "PLAT-1", issues[0].IDReadable)
+	assert.False(t, issues[0].Resolved())
+	assert.Equal(t, "PLAT-101", issues[pageSize].IDReadable)
+	assert.True(t, issues[pageSize].Resolved())
+}
+
+func TestCreateIssueAndCommands(t *testing.T) {
+	youtrack := &fakeYouTrack{}
+	client := newTestClient(t, youtrack)
+	ctx := context.Background()
+
+	issue, err := client.CreateIssue(ctx, Project{ID: "0-1", ShortName: "PLAT"}, "Fix login", "GitHub issue: https://github.com/owner/repo/issues/1")
+	require.NoError(t, err)
+	assert.Equal(t, "PLAT-201", issue.IDReadable)
+	assert.Equal(t, map[string]interface{}{"id": "0-1"}, youtrack.created["project"])
+	assert.Equal(t, "Fix login", youtrack.created["summary"])
+
+	require.NoError(t, client.SetFields(ctx, "PLAT-201", map[string]string{"type": "Bug", "priority": "Show-stopper"}))
+	require.NoError(t, client.SetFields(ctx, "PLAT-201", nil))
+	require.NoError(t, client.SetState(ctx, "PLAT-201", "In Progress"))
+	assert.Equal(t, []string{
+		"PLAT-201: priority {Show-stopper} type {Bug}",
+		"PLAT-201: State {In Progress}",
+	}, youtrack.commands)
+}
+
+func TestClientErrors(t *testing.T) {
+	client := newTestClient(t, &fakeYouTrack{})
+	ctx := context.Background()
+
+	err := client.SetState(ctx, "PLAT-1", "Shipped")
+	assert.ErrorContains(t, err, "Unknown state: Shipped (status: 400)")
+
+	err = client.SetState(ctx, "PLAT-999", "Fixed")
+	assert.ErrorContains(t, err, "Not Found (status: 404)")
+	assert.ErrorIs(t, err, apierror.ErrNotFound)
+}
+
+func TestTokenTransport(t *testing.T) {
+	var authorization string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		fmt.Fprint(w, `[]`)
+	}))
+	t.Cleanup(server.Close)
+	client := &Client{baseURL: server.URL, http: &http.Client{Transport: &tokenTransport{base: http.DefaultTransport, token: "perm:abc"}}}
+
+	_, err := client.Issues(context.Background(), Project{ShortName: "PLAT"})
+	require.NoError(t, err)
+	assert.Equal(t, "Bearer perm:abc", authorization)
+}
+
+func TestFieldCommand(t *testing.T) {
+	assert.Equal(t, "Priority {Major} Type {Feature Request}", FieldCommand(map[string]string{"Type": "Feature Request", "Priority": "Major"}))
+	assert.Equal(t, "", FieldCommand(nil))
+}
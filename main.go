@@ -20,8 +20,17 @@ func main() {
 
 	logging.Info("starting glue cli", "version", "1.0.0", "log_level", logLevel)
 
-	if err := cmd.Execute(); err != nil {
+	err := cmd.Execute()
+	if err != nil {
 		logging.Error("command execution failed", "error", err)
+	}
+
+	// Deliver buffered records to remote log sinks before exiting
+	if flushErr := logging.Flush(); flushErr != nil {
+		fmt.Fprintln(os.Stderr, flushErr)
+	}
+
+	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
 	}
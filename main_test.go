@@ -0,0 +1,48 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runGlueEnv is set when the test binary is run as glue by runGlue.
+const runGlueEnv = "GLUE_TEST_RUN_MAIN"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(runGlueEnv) == "1" {
+		main()
+		os.Exit(0)
+	}
+	os.Exit(m.Run())
+}
+
+// runGlue runs glue with args in a child process, as a user would, and
+// returns what it wrote to stdout and stderr.
+func runGlue(t *testing.T, args ...string) (string, string) {
+	t.Helper()
+	configFile := filepath.Join(t.TempDir(), "glue.yaml")
+	require.NoError(t, os.WriteFile(configFile, nil, 0o644))
+
+	cmd := exec.Command(os.Args[0], args...)
+	cmd.Env = append(os.Environ(), runGlueEnv+"=1", "LOG_LEVEL=debug", "GLUE_CONFIG="+configFile)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout, cmd.Stderr = &stdout, &stderr
+	require.NoError(t, cmd.Run(), stderr.String())
+	return stdout.String(), stderr.String()
+}
+
+func TestConfigSchemaOutputIsJSON(t *testing.T) {
+	stdout, stderr := runGlue(t, "config", "schema")
+
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(stdout), &schema), "stdout must hold the schema alone")
+	assert.Equal(t, "https://json-schema.org/draft/2020-12/schema", schema["$schema"])
+	assert.Contains(t, stderr, "starting glue cli")
+}
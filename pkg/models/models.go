@@ -30,6 +30,150 @@ type GitHubIssue struct {
 
 	// Labels is a slice of label names attached to the issue
 	Labels []string
+
+	// Assignees is a slice of GitHub logins assigned to the issue
+	Assignees []string
+
+	// Locked indicates the conversation on the issue is locked
+	Locked bool
+
+	// Author is the GitHub login of the user who opened the issue
+	Author string
+
+	// URL is the issue's web page
+	URL string
+
+	// Type is the native GitHub issue type (e.g., "Bug"), or empty if the
+	// issue has none or it wasn't fetched
+	Type string
+}
+
+// GitHubRepository represents a GitHub repository.
+type GitHubRepository struct {
+	// FullName is the current name in the format "owner/repo", which differs
+	// from the name it was requested by if the repository was renamed
+	FullName string
+
+	// Archived reports whether the repository is archived and read-only
+	Archived bool
+}
+
+// GitHubDiscussion represents a GitHub Discussion with its essential fields.
+type GitHubDiscussion struct {
+	// ID is the GraphQL node ID, which identifies the discussion in mutations
+	ID string
+
+	// Number is the discussion number in GitHub (e.g., 17)
+	Number int
+
+	// Title is the discussion's title
+	Title string
+
+	// Body is the full text of the discussion's opening post
+	Body string
+
+	// URL is the discussion's web page
+	URL string
+
+	// Author is the GitHub login of the user who started the discussion
+	Author string
+
+	// Category is the name of the discussion's category (e.g., "Ideas")
+	Category string
+}
+
+// GitHubUser represents a GitHub user. Name and Email are only set if the user
+// made them public.
+type GitHubUser struct {
+	// Login is the GitHub username
+	Login string
+
+	// Name is the user's full name
+	Name string
+
+	// Email is the user's public email address
+	Email string
+}
+
+// GitHubPullRequest represents a GitHub pull request with its essential fields.
+type GitHubPullRequest struct {
+	// Number is the pull request number in GitHub (e.g., 43)
+	Number int
+
+	// Title is the pull request's title
+	Title string
+
+	// State is "open" or "closed"
+	State string
+
+	// Merged indicates whether the pull request was merged
+	Merged bool
+
+	// HeadBranch is the name of the branch the changes are on
+	HeadBranch string
+
+	// Body is the pull request's description
+	Body string
+
+	// URL is the web address of the pull request
+	URL string
+}
+
+// GitHubIssueClosure describes how a GitHub issue was closed.
+type GitHubIssueClosure struct {
+	// ClosedBy is the login of the user who closed the issue
+	ClosedBy string
+
+	// ClosedAt is the time the issue was closed
+	ClosedAt time.Time
+
+	// Reason is GitHub's state reason, e.g. "completed" or "not_planned";
+	// empty if GitHub didn't record one
+	Reason string
+
+	// CommitID is the SHA of the commit that closed the issue, if any
+	CommitID string
+
+	// CommitURL is the web address of that commit
+	CommitURL string
+
+	// PullRequest is the pull request the closing commit belongs to, if any
+	PullRequest *GitHubPullRequest
+}
+
+// Kinds of GitHub security alerts.
+const (
+	// AlertDependabot is a Dependabot alert about a vulnerable dependency
+	AlertDependabot = "dependabot"
+	// AlertCodeScanning is a code scanning alert, e.g. from CodeQL
+	AlertCodeScanning = "code-scanning"
+)
+
+// SecurityAlert represents an open GitHub security alert of a repository.
+type SecurityAlert struct {
+	// Kind is AlertDependabot or AlertCodeScanning
+	Kind string
+
+	// Number is the alert number, unique per repository and kind
+	Number int
+
+	// Severity is "critical", "high", "medium" or "low"
+	Severity string
+
+	// Summary is a one-line description, e.g. the advisory or rule summary
+	Summary string
+
+	// Description is the full description of the vulnerability or rule
+	Description string
+
+	// Location is the affected package or source file
+	Location string
+
+	// URL is the web address of the alert
+	URL string
+
+	// CreatedAt is the timestamp when the alert was raised
+	CreatedAt time.Time
 }
 
 // JiraTicket represents a JIRA ticket with its key properties.
@@ -49,6 +193,83 @@ type JiraTicket struct {
 	// Type is the JIRA issue type (e.g., "Story", "Feature", "Task")
 	Type string
 
+	// Status is the current workflow status name (e.g., "To Do", "Done")
+	Status string
+
+	// StatusCategory is the key of the status's category: "new",
+	// "indeterminate" or "done", whatever the workflow calls the status
+	StatusCategory string
+
+	// Labels is a slice of label names attached to the ticket
+	Labels []string
+
+	// Components is a slice of component names the ticket belongs to
+	Components []string
+
+	// Assignee is the user the ticket is assigned to, nil if unassigned
+	Assignee *JiraUser
+
+	// ResolvedAt is the timestamp when the ticket was resolved, nil if unresolved
+	ResolvedAt *time.Time
+
+	// UpdatedAt is the timestamp when the ticket was last updated
+	UpdatedAt time.Time
+
 	// CreatedByGlue indicates whether this ticket was created by our tool
 	CreatedByGlue bool
 }
+
+// JiraUser represents a JIRA user. Which identifiers are set depends on the
+// JIRA deployment: Cloud uses account IDs, Server and Data Center use names.
+type JiraUser struct {
+	// AccountID is the JIRA Cloud account ID
+	AccountID string
+
+	// Name is the JIRA Server username
+	Name string
+
+	// Email is the user's email address, if visible
+	Email string
+
+	// DisplayName is the user's full name
+	DisplayName string
+}
+
+// JiraTransition is a workflow transition available for a JIRA ticket.
+type JiraTransition struct {
+	// ID is the transition ID
+	ID string
+
+	// Name is the transition name shown on the ticket's workflow buttons
+	Name string
+
+	// ToStatus is the status the ticket moves to
+	ToStatus string
+}
+
+// JiraIssueTypeStatuses lists the statuses of an issue type's workflow.
+type JiraIssueTypeStatuses struct {
+	// IssueType is the issue type name (e.g., "Story")
+	IssueType string
+
+	// Statuses are the status names in the workflow
+	Statuses []string
+}
+
+// BoardStats summarizes the tickets of a JIRA project.
+type BoardStats struct {
+	// ProjectKey is the JIRA project key (e.g., "PROJ")
+	ProjectKey string
+
+	// Total is the number of tickets in the project
+	Total int
+
+	// CreatedByGlue is the number of tickets carrying glue's marker label
+	CreatedByGlue int
+
+	// Open is the number of tickets not in a done status category
+	Open int
+
+	// Done is the number of tickets in a done status category
+	Done int
+}